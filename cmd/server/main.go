@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -15,6 +14,7 @@ import (
 	v1 "github.com/mantonx/volumeviz/internal/api/v1"
 	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/server"
 	"github.com/mantonx/volumeviz/internal/services"
 	lifecycle "github.com/mantonx/volumeviz/internal/services/lifecycle"
 	"github.com/mantonx/volumeviz/internal/version"
@@ -57,6 +57,12 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Report every configuration problem at once instead of failing on the
+	// first subsystem that trips over one
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
 	// Set Gin mode
 	gin.SetMode(cfg.Server.Mode)
 
@@ -77,13 +83,23 @@ func main() {
 		Database: cfg.Database.Name,
 		SSLMode:  cfg.Database.SSLMode,
 		Path:     cfg.Database.Path,
+
+		MigrationsDir: cfg.Database.MigrationsDir,
 	}
 
+	// Mask configured secrets wherever the connection string could surface
+	database.AddSecretKeys(cfg.Security.RedactKeys...)
+
+	// Subsystems register how they stop as they start; see the shutdown phases
+	shutdown := server.NewShutdown()
+
 	db, err := database.NewDB(dbConfig)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
-	defer db.Close()
+	shutdown.Add(server.PhaseRelease, "database", func(context.Context) error {
+		return db.Close()
+	})
 
 	// Run database migrations
 	migrationManager := database.NewMigrationManager(db)
@@ -91,6 +107,15 @@ func main() {
 		log.Fatalf("Failed to run database migrations: %v", err)
 	}
 
+	// Slow query reporting reads pg_stat_statements; create it when asked to
+	if cfg.Database.EnableQueryStats && db.IsPostgreSQL() {
+		if err := db.EnableQueryStats(); err != nil {
+			log.Printf("[WARN] Failed to enable %s: %v", database.QueryStatsExtension, err)
+		} else {
+			log.Printf("[INFO] Enabled %s for slow query reporting", database.QueryStatsExtension)
+		}
+	}
+
 	// Start lifecycle retention service
 	lc := lifecycle.New(db.DB, lifecycle.Config{
 		Enabled:        cfg.Lifecycle.Enabled,
@@ -101,54 +126,69 @@ func main() {
 		InitialDelay:   cfg.Lifecycle.InitialDelay,
 	})
 	lc.Start()
-	defer lc.Stop()
+	shutdown.AddFunc(server.PhaseBackground, "retention service", lc.Stop)
 
 	// Initialize Docker service
 	dockerService, err := services.NewDockerService(cfg.Docker.Host, cfg.Docker.Timeout)
 	if err != nil {
 		log.Fatalf("Failed to initialize Docker service: %v", err)
 	}
-	defer dockerService.Close()
+	shutdown.Add(server.PhaseRelease, "Docker client", func(context.Context) error {
+		return dockerService.Close()
+	})
 
 	// Setup v1 API router
 	apiRouter := v1.NewRouter(dockerService, db, cfg)
 	router := apiRouter.Engine()
 
+	// The scheduler drains queued and running scans and commits their
+	// buffered stats before the events service stops
+	if apiRouter.Scheduler() != nil {
+		shutdown.Add(server.PhaseDrain, "scan scheduler", apiRouter.Scheduler().Stop)
+	}
+
 	// Start events service if enabled
 	if cfg.Events.Enabled && apiRouter.EventsService() != nil {
 		if err := apiRouter.EventsService().Start(context.Background()); err != nil {
 			log.Printf("[WARN] Failed to start events service: %v", err)
 		}
-		defer func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
-			if err := apiRouter.EventsService().Stop(ctx); err != nil {
-				log.Printf("[ERROR] Failed to stop events service: %v", err)
-			}
-		}()
+		shutdown.Add(server.PhaseDrain, "events service", apiRouter.EventsService().Stop)
 	}
 
-	// Create server
-	srv := &http.Server{
-		Addr:         fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
-		Handler:      router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  120 * time.Second,
+	shutdown.AddFunc(server.PhaseBackground, "database optimizer", apiRouter.Optimizer().Stop)
+	if apiRouter.RollupJob() != nil {
+		shutdown.AddFunc(server.PhaseBackground, "metrics rollup job", apiRouter.RollupJob().Stop)
+	}
+	if apiRouter.QuarantineJob() != nil {
+		shutdown.AddFunc(server.PhaseBackground, "quarantine job", apiRouter.QuarantineJob().Stop)
+	}
+	if apiRouter.DuplicateJob() != nil {
+		shutdown.AddFunc(server.PhaseBackground, "duplicate detection job", apiRouter.DuplicateJob().Stop)
+	}
+
+	// Bind before serving so an address in use fails startup right away
+	listener, err := server.Listen(cfg.Server.Host, cfg.Server.Port, cfg.Server.SocketMode)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
 	}
 
+	// Create server
+	srv := server.NewHTTPServer(router, &cfg.Server)
+	// In-flight requests finish first; nothing new is accepted while the rest drains
+	shutdown.Add(server.PhaseIntake, "HTTP server", srv.Shutdown)
+
 	// Start server in goroutine
 	go func() {
 		if cfg.TLS.Enabled {
-			log.Printf("Starting VolumeViz HTTPS server on %s:%s", cfg.Server.Host, cfg.Server.Port)
+			log.Printf("Starting VolumeViz HTTPS server on %s", listener.Addr())
 			log.Printf("Using TLS cert: %s, key: %s", cfg.TLS.CertFile, cfg.TLS.KeyFile)
-			if err := srv.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile); err != nil && err != http.ErrServerClosed {
+			if err := srv.ServeTLS(listener, cfg.TLS.CertFile, cfg.TLS.KeyFile); err != nil && err != http.ErrServerClosed {
 				log.Fatalf("Failed to start HTTPS server: %v", err)
 			}
 		} else {
-			log.Printf("Starting VolumeViz HTTP server on %s:%s", cfg.Server.Host, cfg.Server.Port)
+			log.Printf("Starting VolumeViz HTTP server on %s", listener.Addr())
 			log.Println("⚠️  Running in HTTP mode. For production, enable TLS with TLS_CERT_FILE and TLS_KEY_FILE")
-			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
 				log.Fatalf("Failed to start HTTP server: %v", err)
 			}
 		}
@@ -160,19 +200,12 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
-	// Graceful shutdown
+	// Graceful shutdown of every subsystem within one deadline
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Stop scheduler if running
-	if apiRouter.Scheduler() != nil {
-		if err := apiRouter.Scheduler().Stop(ctx); err != nil {
-			log.Printf("[ERROR] Failed to stop scan scheduler: %v", err)
-		}
-	}
-
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+	if err := shutdown.Run(ctx); err != nil {
+		log.Fatalf("Shutdown did not complete cleanly: %v", err)
 	}
 
 	log.Println("Server exited gracefully")
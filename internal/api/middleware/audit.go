@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// auditWriteTimeout bounds how long recording an audit entry may take
+const auditWriteTimeout = 5 * time.Second
+
+// redactedValue replaces sensitive values in audit summaries
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeyMarkers identify parameter names whose values are never recorded
+var sensitiveKeyMarkers = []string{"token", "secret", "password", "passwd", "key", "auth", "credential", "signature"}
+
+// AuditRecorder stores audit log entries
+type AuditRecorder interface {
+	Insert(ctx context.Context, entry *database.AuditLogEntry) error
+}
+
+// AuditConfig holds audit logging configuration
+type AuditConfig struct {
+	Enabled      bool
+	IncludeReads bool // Also record GET, HEAD and OPTIONS requests
+	SkipPaths    []string
+	Recorder     AuditRecorder
+}
+
+// AuditMiddleware records who performed each API action once the request has
+// been handled. Only the route, path parameters and query parameters go into the
+// summary, with sensitive values redacted; bodies and headers are never recorded.
+func AuditMiddleware(config *AuditConfig) gin.HandlerFunc {
+	if config == nil || !config.Enabled || config.Recorder == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		for _, skipPath := range config.SkipPaths {
+			if strings.HasPrefix(c.Request.URL.Path, skipPath) {
+				c.Next()
+				return
+			}
+		}
+
+		if !config.IncludeReads && isReadOnlyMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		entry := &database.AuditLogEntry{
+			OccurredAt: time.Now().UTC(),
+			UserID:     GetUserID(c),
+			UserRole:   string(GetUserRole(c)),
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Route:      c.FullPath(),
+			StatusCode: c.Writer.Status(),
+			Summary:    auditSummary(c),
+			RequestID:  GetRequestID(c),
+			ClientIP:   c.ClientIP(),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+		defer cancel()
+
+		if err := config.Recorder.Insert(ctx, entry); err != nil {
+			log.Printf("[WARN] Failed to record audit entry for %s %s: %v", entry.Method, entry.Path, err)
+		}
+	}
+}
+
+// isReadOnlyMethod reports whether the HTTP method does not change state
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditSummary describes the action as the matched route followed by its path
+// and query parameters, e.g. "POST /api/v1/volumes/:name/scan name=data async=true"
+func auditSummary(c *gin.Context) string {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	parts := append([]string{c.Request.Method + " " + route}, requestParams(c)...)
+	return strings.Join(parts, " ")
+}
+
+// requestParams lists the path parameters followed by the sorted query
+// parameters as key=value, with sensitive values redacted
+func requestParams(c *gin.Context) []string {
+	var params []string
+	for _, param := range c.Params {
+		params = append(params, param.Key+"="+sanitizeAuditValue(param.Key, param.Value))
+	}
+
+	query := c.Request.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range query[key] {
+			params = append(params, key+"="+sanitizeAuditValue(key, value))
+		}
+	}
+
+	return params
+}
+
+// sanitizeAuditValue redacts values of sensitive parameters
+func sanitizeAuditValue(key, value string) string {
+	lowered := strings.ToLower(key)
+	for _, marker := range sensitiveKeyMarkers {
+		if strings.Contains(lowered, marker) {
+			return redactedValue
+		}
+	}
+	return value
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -43,6 +44,13 @@ type AuthConfig struct {
 	Secret       string
 	SkipPaths    []string
 	RequiredRole UserRole // Minimum required role
+
+	// Authenticators are tried in order until one identifies the request;
+	// without any, HS256 JWTs signed with Secret are accepted
+	Authenticators []Authenticator
+
+	// Anonymous lets requests that no authenticator identified through
+	Anonymous AnonymousPolicy
 }
 
 // DefaultAuthConfig returns default authentication configuration
@@ -60,7 +68,11 @@ func DefaultAuthConfig() *AuthConfig {
 	}
 }
 
-// AuthMiddleware returns JWT authentication middleware
+// AuthMiddleware returns authentication middleware running the configured
+// authenticators in order. The first to identify a request decides who it acts
+// as; one rejecting invalid credentials ends the chain, so a bad token never
+// falls through to anonymous access. Requests no authenticator identified are
+// left to the anonymous policy.
 func AuthMiddleware(config *AuthConfig) gin.HandlerFunc {
 	if config == nil {
 		config = DefaultAuthConfig()
@@ -73,9 +85,13 @@ func AuthMiddleware(config *AuthConfig) gin.HandlerFunc {
 		}
 	}
 
-	// Validate secret is provided
-	if config.Secret == "" {
-		panic("AUTH_HS256_SECRET must be provided when AUTH_ENABLED=true")
+	authenticators := config.Authenticators
+	if len(authenticators) == 0 {
+		// Validate secret is provided
+		if config.Secret == "" {
+			panic("AUTH_HS256_SECRET must be provided when AUTH_ENABLED=true")
+		}
+		authenticators = []Authenticator{NewJWTAuthenticator(config.Secret)}
 	}
 
 	return gin.HandlerFunc(func(c *gin.Context) {
@@ -87,44 +103,46 @@ func AuthMiddleware(config *AuthConfig) gin.HandlerFunc {
 			}
 		}
 
-		// Extract token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error":     "Authorization header required",
-				"code":      "MISSING_AUTH_HEADER",
-				"requestId": GetRequestID(c),
-			})
-			return
-		}
-
-		// Check Bearer token format
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error":     "Invalid authorization header format",
-				"code":      "INVALID_AUTH_FORMAT",
-				"requestId": GetRequestID(c),
-			})
-			return
+		var identity *Identity
+		for _, authenticator := range authenticators {
+			var err error
+			identity, err = authenticator.Authenticate(c)
+			if err != nil {
+				response := gin.H{
+					"error":     err.Error(),
+					"code":      "INVALID_CREDENTIALS",
+					"requestId": GetRequestID(c),
+				}
+				var authErr *AuthError
+				if errors.As(err, &authErr) {
+					response["error"] = authErr.Message
+					response["code"] = authErr.Code
+					if authErr.Details != "" {
+						response["details"] = authErr.Details
+					}
+				}
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response)
+				return
+			}
+			if identity != nil {
+				break
+			}
 		}
 
-		tokenString := parts[1]
-
-		// Parse and validate JWT token
-		claims, err := validateJWT(tokenString, config.Secret)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error":     "Invalid token",
-				"code":      "INVALID_TOKEN",
-				"details":   err.Error(),
-				"requestId": GetRequestID(c),
-			})
-			return
+		if identity == nil {
+			if !config.Anonymous.Allows(c) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error":     "Authorization header or API key required",
+					"code":      "MISSING_AUTH_HEADER",
+					"requestId": GetRequestID(c),
+				})
+				return
+			}
+			identity = &Identity{UserID: ProviderAnonymous, Role: config.Anonymous.Role, Provider: ProviderAnonymous}
 		}
 
 		// Check if user role meets minimum requirement
-		if !hasRequiredRole(claims.Role, config.RequiredRole) {
+		if !hasRequiredRole(identity.Role, config.RequiredRole) {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 				"error":     "Insufficient permissions",
 				"code":      "INSUFFICIENT_PERMISSIONS",
@@ -134,8 +152,9 @@ func AuthMiddleware(config *AuthConfig) gin.HandlerFunc {
 		}
 
 		// Store user information in context for use by handlers
-		c.Set("userID", claims.UserID)
-		c.Set("userRole", string(claims.Role))
+		c.Set("userID", identity.UserID)
+		c.Set("userRole", string(identity.Role))
+		c.Set("authProvider", identity.Provider)
 
 		c.Next()
 	})
@@ -183,6 +202,28 @@ func ProtectMutatingOperations(config *AuthConfig) gin.HandlerFunc {
 	})
 }
 
+// RequireRoleWhenEnabled applies RequireRole only when authentication is enabled,
+// so role-gated routes remain usable in unauthenticated development setups
+func RequireRoleWhenEnabled(config *AuthConfig, requiredRole UserRole) gin.HandlerFunc {
+	if config == nil || !config.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return RequireRole(requiredRole)
+}
+
+// HasRoleWhenEnabled reports whether a request acts with at least requiredRole,
+// which is always the case when authentication is disabled. It is the check
+// RequireRoleWhenEnabled makes, for handlers that only gate part of a request.
+func HasRoleWhenEnabled(config *AuthConfig, c *gin.Context, requiredRole UserRole) bool {
+	if config == nil || !config.Enabled {
+		return true
+	}
+	return hasRequiredRole(GetUserRole(c), requiredRole)
+}
+
 // RequireRole middleware requires a specific minimum role
 func RequireRole(requiredRole UserRole) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
@@ -197,6 +238,15 @@ func RequireRole(requiredRole UserRole) gin.HandlerFunc {
 		}
 
 		if !hasRequiredRole(UserRole(userRole), requiredRole) {
+			// Anonymous callers may hold the role once they authenticate
+			if GetAuthProvider(c) == ProviderAnonymous {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error":     fmt.Sprintf("Authentication with the %s role required", requiredRole),
+					"code":      "AUTH_REQUIRED",
+					"requestId": GetRequestID(c),
+				})
+				return
+			}
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 				"error":     fmt.Sprintf("%s role required", requiredRole),
 				"code":      "INSUFFICIENT_ROLE",
@@ -243,6 +293,12 @@ func GetUserRole(c *gin.Context) UserRole {
 	return ""
 }
 
+// GetAuthProvider retrieves the provider that authenticated the request, e.g.
+// ProviderAPIKey, or "" when authentication is disabled or skipped
+func GetAuthProvider(c *gin.Context) string {
+	return c.GetString("authProvider")
+}
+
 // validateJWT validates a JWT token and returns claims
 func validateJWT(tokenString, secret string) (*AuthClaims, error) {
 	// Split JWT into parts
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAuthSecret = "test-secret-at-least-32-characters-long"
+
+func TestAuthMiddleware_AuthenticatorChain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authConfig := &AuthConfig{
+		Enabled:      true,
+		RequiredRole: RoleViewer,
+		SkipPaths:    []string{"/health"},
+		Authenticators: []Authenticator{
+			NewJWTAuthenticator(testAuthSecret),
+			NewAPIKeyAuthenticator([]APIKey{
+				{Name: "ci", Role: RoleOperator, Key: "ci-key-0123456789abcdef"},
+			}),
+		},
+		Anonymous: AnonymousPolicy{Role: RoleViewer, Paths: []string{"/api/v1/volumes"}},
+	}
+
+	engine := gin.New()
+	engine.Use(AuthMiddleware(authConfig))
+	identify := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user": GetUserID(c), "role": GetUserRole(c), "provider": GetAuthProvider(c)})
+	}
+	engine.GET("/health", identify)
+	engine.GET("/api/v1/volumes", identify)
+	engine.GET("/api/v1/scans", identify)
+	engine.POST("/api/v1/volumes/prune", RequireRole(RoleOperator), identify)
+	engine.GET("/api/v1/audit", RequireRole(RoleAdmin), identify)
+
+	send := func(method, path string, headers map[string]string) (int, map[string]string) {
+		req := httptest.NewRequest(method, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+		return w.Code, body
+	}
+
+	token, err := GenerateJWT("alice", RoleAdmin, testAuthSecret, time.Hour)
+	require.NoError(t, err)
+	bearer := map[string]string{"Authorization": "Bearer " + token}
+	apiKey := map[string]string{APIKeyHeader: "ci-key-0123456789abcdef"}
+
+	t.Run("jwt", func(t *testing.T) {
+		code, body := send("GET", "/api/v1/audit", bearer)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]string{"user": "alice", "role": "admin", "provider": ProviderJWT}, body)
+	})
+
+	t.Run("api key", func(t *testing.T) {
+		code, body := send("POST", "/api/v1/volumes/prune", apiKey)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]string{"user": "ci", "role": "operator", "provider": ProviderAPIKey}, body)
+
+		code, body = send("GET", "/api/v1/audit", apiKey)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "INSUFFICIENT_ROLE", body["code"])
+	})
+
+	t.Run("the first authenticator to identify the request wins", func(t *testing.T) {
+		code, body := send("GET", "/api/v1/volumes", map[string]string{"Authorization": "Bearer " + token, APIKeyHeader: "ci-key-0123456789abcdef"})
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, ProviderJWT, body["provider"])
+	})
+
+	t.Run("anonymous reads fall through to the policy", func(t *testing.T) {
+		code, body := send("GET", "/api/v1/volumes", nil)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]string{"user": ProviderAnonymous, "role": "viewer", "provider": ProviderAnonymous}, body)
+	})
+
+	t.Run("anonymous requests outside the policy need credentials", func(t *testing.T) {
+		code, body := send("GET", "/api/v1/scans", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "MISSING_AUTH_HEADER", body["code"])
+
+		// Anonymous requests never write, even below an open path
+		code, body = send("POST", "/api/v1/volumes/prune", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "MISSING_AUTH_HEADER", body["code"])
+	})
+
+	t.Run("invalid credentials do not fall through", func(t *testing.T) {
+		code, body := send("GET", "/api/v1/volumes", map[string]string{"Authorization": "Bearer not.a.token"})
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "INVALID_TOKEN", body["code"])
+
+		code, body = send("GET", "/api/v1/volumes", map[string]string{APIKeyHeader: "wrong-key"})
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "INVALID_API_KEY", body["code"])
+
+		code, body = send("GET", "/api/v1/volumes", map[string]string{"Authorization": "Basic YWxpY2U6cHc="})
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "INVALID_AUTH_FORMAT", body["code"])
+	})
+
+	t.Run("skip paths bypass the chain", func(t *testing.T) {
+		code, body := send("GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "", body["provider"])
+	})
+}
+
+func TestRequireRole_AnonymousIsAskedToAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(AuthMiddleware(&AuthConfig{
+		Enabled:      true,
+		Secret:       testAuthSecret,
+		RequiredRole: RoleViewer,
+		Anonymous:    AnonymousPolicy{Role: RoleViewer},
+	}))
+	engine.GET("/api/v1/audit", RequireRole(RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/audit", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "AUTH_REQUIRED")
+}
+
+func TestAnonymousPolicy_DisabledWithoutRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/volumes", nil)
+	assert.False(t, AnonymousPolicy{}.Allows(c))
+	assert.True(t, AnonymousPolicy{Role: RoleViewer}.Allows(c))
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authentication providers, as reported by GetAuthProvider
+const (
+	ProviderJWT       = "jwt"
+	ProviderAPIKey    = "api_key"
+	ProviderAnonymous = "anonymous"
+)
+
+// APIKeyHeader carries the key checked by APIKeyAuthenticator
+const APIKeyHeader = "X-API-Key"
+
+// Identity is who a request acts as
+type Identity struct {
+	UserID   string
+	Role     UserRole
+	Provider string
+}
+
+// Authenticator identifies the caller of a request. It returns nil and no error
+// when the request carries no credentials it handles, so the next
+// authenticator is tried, and an *AuthError when the credentials are invalid.
+type Authenticator interface {
+	Authenticate(c *gin.Context) (*Identity, error)
+}
+
+// AuthError rejects a request whose credentials are invalid
+type AuthError struct {
+	Code    string // Reported as the response's code, e.g. INVALID_TOKEN
+	Message string
+	Details string
+}
+
+func (e *AuthError) Error() string {
+	if e.Details == "" {
+		return e.Message
+	}
+	return e.Message + ": " + e.Details
+}
+
+// JWTAuthenticator accepts HS256 tokens sent as "Authorization: Bearer <token>"
+type JWTAuthenticator struct {
+	secret string
+}
+
+// NewJWTAuthenticator creates a JWT authenticator verifying tokens with secret
+func NewJWTAuthenticator(secret string) *JWTAuthenticator {
+	return &JWTAuthenticator{secret: secret}
+}
+
+// Authenticate implements Authenticator
+func (a *JWTAuthenticator) Authenticate(c *gin.Context) (*Identity, error) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil, &AuthError{Code: "INVALID_AUTH_FORMAT", Message: "Invalid authorization header format"}
+	}
+
+	claims, err := validateJWT(parts[1], a.secret)
+	if err != nil {
+		return nil, &AuthError{Code: "INVALID_TOKEN", Message: "Invalid token", Details: err.Error()}
+	}
+	return &Identity{UserID: claims.UserID, Role: claims.Role, Provider: ProviderJWT}, nil
+}
+
+// APIKey is a static key for automation, acting as Name with Role
+type APIKey struct {
+	Name string
+	Role UserRole
+	Key  string
+}
+
+// APIKeyAuthenticator accepts the keys it was created with in the X-API-Key header
+type APIKeyAuthenticator struct {
+	keys []apiKeyDigest
+}
+
+// apiKeyDigest keeps a key's hash, so keys of any length compare in constant time
+type apiKeyDigest struct {
+	name   string
+	role   UserRole
+	digest [sha256.Size]byte
+}
+
+// NewAPIKeyAuthenticator creates an authenticator accepting keys
+func NewAPIKeyAuthenticator(keys []APIKey) *APIKeyAuthenticator {
+	a := &APIKeyAuthenticator{keys: make([]apiKeyDigest, 0, len(keys))}
+	for _, key := range keys {
+		a.keys = append(a.keys, apiKeyDigest{name: key.Name, role: key.Role, digest: sha256.Sum256([]byte(key.Key))})
+	}
+	return a
+}
+
+// Authenticate implements Authenticator
+func (a *APIKeyAuthenticator) Authenticate(c *gin.Context) (*Identity, error) {
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+
+	digest := sha256.Sum256([]byte(key))
+	var match *apiKeyDigest
+	// Every key is compared, so the time taken does not reveal which matched
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(digest[:], a.keys[i].digest[:]) == 1 {
+			match = &a.keys[i]
+		}
+	}
+	if match == nil {
+		return nil, &AuthError{Code: "INVALID_API_KEY", Message: "Invalid API key"}
+	}
+	return &Identity{UserID: match.name, Role: match.role, Provider: ProviderAPIKey}, nil
+}
+
+// AnonymousPolicy decides which requests without credentials are let through.
+// Anonymous requests may only read, so they never reach mutating routes.
+type AnonymousPolicy struct {
+	Role  UserRole // Role anonymous requests act with; empty rejects them all
+	Paths []string // Path prefixes open to anonymous reads; empty opens every path
+}
+
+// Allows reports whether a request without credentials may proceed anonymously
+func (p AnonymousPolicy) Allows(c *gin.Context) bool {
+	if p.Role == "" {
+		return false
+	}
+	switch c.Request.Method {
+	case "GET", "HEAD", "OPTIONS":
+	default:
+		return false
+	}
+	if len(p.Paths) == 0 {
+		return true
+	}
+	for _, path := range p.Paths {
+		if strings.HasPrefix(c.Request.URL.Path, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/interfaces"
+)
+
+// DockerCallBudgetMiddleware allows each request at most calls Docker API
+// calls, see interfaces.WithDockerCallBudget. Zero disables the cap.
+func DockerCallBudgetMiddleware(calls int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if calls > 0 {
+			c.Request = c.Request.WithContext(interfaces.WithDockerCallBudget(c.Request.Context(), calls))
+		}
+		c.Next()
+	}
+}
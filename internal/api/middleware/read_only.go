@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadOnlyReadRoutes are the POST routes that only inspect state and stay
+// available in read-only mode
+var ReadOnlyReadRoutes = []string{
+	"/api/v1/volumes/probe", // Reads from volume mounts to check they respond
+}
+
+// ReadOnlyMiddleware returns middleware that, when enabled, rejects every
+// request that could change state with a 403, locking down the whole
+// instance whatever the caller's role. GET, HEAD and OPTIONS requests and
+// the POST routes in readRoutes (route patterns) pass through.
+func ReadOnlyMiddleware(enabled bool, readRoutes []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if c.Request.Method == http.MethodPost && slices.Contains(readRoutes, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":     "VolumeViz is running in read-only mode; changes are disabled",
+			"code":      "READ_ONLY",
+			"requestId": GetRequestID(c),
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/v1/volumes"},
+		{"GET", "/api/v1/volumes/:name"},
+		{"POST", "/api/v1/volumes/probe"},
+		{"POST", "/api/v1/volumes/:name/scan"},
+		{"POST", "/api/v1/scan/now"},
+		{"POST", "/api/v1/volumes/prune"},
+		{"POST", "/api/v1/database/migrations/apply"},
+		{"POST", "/api/v1/database/migrations/:version/rollback"},
+		{"PUT", "/api/v1/volume-aliases/:name"},
+		{"DELETE", "/api/v1/volume-aliases/:name"},
+		{"POST", "/api/v1/config/import"},
+	}
+	newEngine := func(enabled bool) *gin.Engine {
+		engine := gin.New()
+		engine.Use(ReadOnlyMiddleware(enabled, ReadOnlyReadRoutes))
+		for _, route := range routes {
+			engine.Handle(route.method, route.path, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+		}
+		return engine
+	}
+	send := func(engine *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("blocks changes", func(t *testing.T) {
+		engine := newEngine(true)
+		for _, req := range [][2]string{
+			{"POST", "/api/v1/volumes/app-data/scan"},
+			{"POST", "/api/v1/scan/now"},
+			{"POST", "/api/v1/volumes/prune"},
+			{"POST", "/api/v1/database/migrations/apply"},
+			{"POST", "/api/v1/database/migrations/009/rollback"},
+			{"PUT", "/api/v1/volume-aliases/app-data"},
+			{"DELETE", "/api/v1/volume-aliases/app-data"},
+			{"POST", "/api/v1/config/import"},
+		} {
+			w := send(engine, req[0], req[1])
+			assert.Equal(t, http.StatusForbidden, w.Code, req)
+			assert.Contains(t, w.Body.String(), "READ_ONLY", req)
+			assert.Contains(t, w.Body.String(), "read-only mode", req)
+		}
+	})
+
+	t.Run("serves reads", func(t *testing.T) {
+		engine := newEngine(true)
+		assert.Equal(t, http.StatusOK, send(engine, "GET", "/api/v1/volumes").Code)
+		assert.Equal(t, http.StatusOK, send(engine, "GET", "/api/v1/volumes/app-data").Code)
+		assert.Equal(t, http.StatusOK, send(engine, "POST", "/api/v1/volumes/probe").Code, "probing only reads mounts")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		engine := newEngine(false)
+		assert.Equal(t, http.StatusOK, send(engine, "POST", "/api/v1/scan/now").Code)
+		assert.Equal(t, http.StatusOK, send(engine, "DELETE", "/api/v1/volume-aliases/app-data").Code)
+	})
+}
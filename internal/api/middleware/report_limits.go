@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+)
+
+// ReportLimitsMiddleware sets the caps apiutils.RespondWithReport enforces on
+// report responses. A zero cap is disabled.
+func ReportLimitsMiddleware(maxItems, maxBytes int) gin.HandlerFunc {
+	limits := apiutils.ReportLimits{MaxItems: maxItems, MaxBytes: maxBytes}
+
+	return func(c *gin.Context) {
+		c.Set(apiutils.ReportLimitsKey, limits)
+		c.Next()
+	}
+}
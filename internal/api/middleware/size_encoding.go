@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+)
+
+const (
+	// SizeEncodingHeader lets clients choose how size_bytes values are encoded
+	SizeEncodingHeader = "X-Size-Encoding"
+	// SizeEncodingKey is the context key for the selected size encoding
+	SizeEncodingKey = "sizeEncoding"
+)
+
+// SizeEncodingMiddleware selects the JSON encoding for size values. The
+// X-Size-Encoding header ("number" or "string") overrides the configured default.
+func SizeEncodingMiddleware(defaultEncoding string) gin.HandlerFunc {
+	defaultEncoding = normalizeSizeEncoding(defaultEncoding, models.SizeEncodingNumber)
+
+	return func(c *gin.Context) {
+		encoding := normalizeSizeEncoding(c.GetHeader(SizeEncodingHeader), defaultEncoding)
+		c.Set(SizeEncodingKey, encoding)
+
+		c.Next()
+	}
+}
+
+// SizesAsStrings reports whether size values should be encoded as strings for this request
+func SizesAsStrings(c *gin.Context) bool {
+	if encoding, exists := c.Get(SizeEncodingKey); exists {
+		return encoding == models.SizeEncodingString
+	}
+	// Middleware not installed: honour the header alone
+	return normalizeSizeEncoding(c.GetHeader(SizeEncodingHeader), models.SizeEncodingNumber) == models.SizeEncodingString
+}
+
+// normalizeSizeEncoding returns a known encoding, or fallback for empty and unknown values
+func normalizeSizeEncoding(encoding, fallback string) string {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case models.SizeEncodingString:
+		return models.SizeEncodingString
+	case models.SizeEncodingNumber:
+		return models.SizeEncodingNumber
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// slowRequestsTotal counts requests that took longer than the slow request threshold
+var slowRequestsTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "volumeviz_http_slow_requests_total",
+		Help: "Total number of HTTP requests exceeding the slow request threshold",
+	},
+	[]string{"method", "route"},
+)
+
+// SlowRequestConfig holds slow request logging configuration
+type SlowRequestConfig struct {
+	Threshold time.Duration // Zero disables slow request logging
+	SkipPaths []string
+	Logger    *log.Logger // Defaults to the standard logger
+}
+
+// SlowRequestMiddleware logs requests that take longer than the threshold with
+// their route, status, duration and parameters, and counts them in
+// volumeviz_http_slow_requests_total. Parameter values are redacted as in the
+// audit log.
+func SlowRequestMiddleware(config *SlowRequestConfig) gin.HandlerFunc {
+	if config == nil || config.Threshold <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return func(c *gin.Context) {
+		for _, skipPath := range config.SkipPaths {
+			if strings.HasPrefix(c.Request.URL.Path, skipPath) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
+		if duration < config.Threshold {
+			return
+		}
+
+		// Unmatched requests are counted together so random paths cannot grow the label set
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		slowRequestsTotal.WithLabelValues(c.Request.Method, route).Inc()
+
+		fields := []string{
+			"method=" + c.Request.Method,
+			"route=" + route,
+			"status=" + strconv.Itoa(c.Writer.Status()),
+			"duration=" + duration.Round(time.Millisecond).String(),
+			"threshold=" + config.Threshold.String(),
+		}
+		if requestID := GetRequestID(c); requestID != "" {
+			fields = append(fields, "request_id="+requestID)
+		}
+		if params := requestParams(c); len(params) > 0 {
+			fields = append(fields, "params="+strconv.Quote(strings.Join(params, " ")))
+		}
+		logger.Printf("[WARN] Slow request: %s", strings.Join(fields, " "))
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowRequestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	engine := gin.New()
+	engine.Use(RequestIDMiddleware())
+	engine.Use(SlowRequestMiddleware(&SlowRequestConfig{
+		Threshold: 20 * time.Millisecond,
+		Logger:    log.New(&logs, "", 0),
+	}))
+	engine.GET("/api/v1/volumes/:name", func(c *gin.Context) {
+		if c.Query("slow") == "true" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		c.Status(http.StatusAccepted)
+	})
+
+	slowCount := func() float64 {
+		return testutil.ToFloat64(slowRequestsTotal.WithLabelValues(http.MethodGet, "/api/v1/volumes/:name"))
+	}
+	before := slowCount()
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/volumes/fast-data", nil))
+	assert.Empty(t, logs.String(), "fast requests are not logged")
+	assert.Equal(t, before, slowCount())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/volumes/app-data?slow=true&token=secret", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := logs.String()
+	assert.Contains(t, entry, "[WARN] Slow request:")
+	assert.Contains(t, entry, "method=GET route=/api/v1/volumes/:name status=202")
+	assert.Contains(t, entry, "threshold=20ms")
+	assert.Contains(t, entry, "request_id=req-123")
+	assert.Contains(t, entry, `params="name=app-data slow=true token=[REDACTED]"`)
+	assert.NotContains(t, entry, "secret")
+	assert.Equal(t, before+1, slowCount())
+}
+
+func TestSlowRequestMiddleware_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	engine := gin.New()
+	engine.Use(SlowRequestMiddleware(&SlowRequestConfig{Logger: log.New(&logs, "", 0)}))
+	engine.GET("/slow", func(c *gin.Context) {
+		time.Sleep(5 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Empty(t, logs.String())
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditEntryV1 represents a recorded API action in the v1 API format
+type AuditEntryV1 struct {
+	ID         int       `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	UserID     string    `json:"user_id,omitempty"`
+	UserRole   string    `json:"user_role,omitempty"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Route      string    `json:"route,omitempty"`
+	StatusCode int       `json:"status_code"`
+	Summary    string    `json:"summary"`
+	RequestID  string    `json:"request_id,omitempty"`
+	ClientIP   string    `json:"client_ip,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// ContainerV1 represents a synced container in the v1 API format
+type ContainerV1 struct {
+	ContainerID  string            `json:"container_id"`
+	Name         string            `json:"name"`
+	Image        string            `json:"image"`
+	State        string            `json:"state"`
+	Status       string            `json:"status,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	StartedAt    *time.Time        `json:"started_at,omitempty"`
+	FinishedAt   *time.Time        `json:"finished_at,omitempty"`
+	ActiveMounts int               `json:"active_mounts"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// MountV1 represents a volume mount in the host-wide mount inventory
+type MountV1 struct {
+	VolumeID      string    `json:"volume_id"`
+	VolumeName    string    `json:"volume_name"`
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name,omitempty"`
+	MountPath     string    `json:"mount_path"`
+	AccessMode    string    `json:"access_mode"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// MetadataDocumentVersion is the version of the metadata export format
+const MetadataDocumentVersion = 1
+
+// MetadataDocumentV1 is an export of all VolumeViz-managed volume metadata:
+// aliases, pins, retention and any other annotations. It contains no Docker
+// state, so it can be kept under version control and imported on other hosts.
+type MetadataDocumentV1 struct {
+	Version int `json:"version" yaml:"version"`
+	// Volumes maps a volume name to its annotations
+	Volumes map[string]map[string]string `json:"volumes" yaml:"volumes"`
+}
+
+// MetadataChangeV1 is one annotation an import creates, updates or deletes
+type MetadataChangeV1 struct {
+	Volume   string `json:"volume"`
+	Key      string `json:"key"`
+	Action   string `json:"action"` // "create", "update" or "delete"
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// MetadataImportResultV1 reports the changes an import made, or would make on a dry run
+type MetadataImportResultV1 struct {
+	DryRun    bool               `json:"dry_run"`
+	Replace   bool               `json:"replace"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Deleted   int                `json:"deleted"`
+	Unchanged int                `json:"unchanged"`
+	Changes   []MetadataChangeV1 `json:"changes"`
+}
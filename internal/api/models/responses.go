@@ -13,6 +13,37 @@ type ErrorResponse struct {
 	Details map[string]any `json:"details,omitempty"`
 } // @name ErrorResponse
 
+// ReportPageV1 tells whether a report response was cut short by the report
+// limits, and where to continue reading it
+type ReportPageV1 struct {
+	Truncated  bool   `json:"truncated"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Next       string `json:"next,omitempty"` // Request URL continuing at NextCursor
+}
+
+// PartialResultV1 marks a response cut short because the request reached its
+// Docker API call budget
+type PartialResultV1 struct {
+	Partial bool   `json:"partial"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// BatchItemV1 is the outcome of one item of a batch request
+type BatchItemV1 struct {
+	ID     string `json:"id"`
+	Status int    `json:"status"` // HTTP status the item would have had on its own
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchResultV1 reports a batch request item by item. Batch endpoints embed
+// it in their responses.
+type BatchResultV1 struct {
+	Items     []BatchItemV1     `json:"items"`
+	NotFound  []string          `json:"not_found"`
+	Errors    map[string]string `json:"errors"` // Items that failed for other reasons
+	Succeeded int               `json:"succeeded"`
+}
+
 // HealthResponse represents a health check response
 type HealthResponse struct {
 	Status     string                 `json:"status" example:"ok"`
@@ -41,16 +72,27 @@ type VolumeListResponse struct {
 
 // ScanResult represents the result of a volume scan
 type ScanResult struct {
-	VolumeID       string        `json:"volume_id" example:"tv-shows-readonly"`
-	TotalSize      int64         `json:"total_size" example:"70640394854400"`
-	FileCount      int           `json:"file_count" example:"12543"`
-	DirectoryCount int           `json:"directory_count" example:"1204"`
-	LargestFile    int64         `json:"largest_file" example:"8589934592"`
-	Method         string        `json:"method" example:"du"`
-	ScannedAt      time.Time     `json:"scanned_at"`
-	Duration       time.Duration `json:"duration" example:"13248000000"`
-	CacheHit       bool          `json:"cache_hit" example:"false"`
-	FilesystemType string        `json:"filesystem_type" example:"cifs"`
+	VolumeID         string        `json:"volume_id" example:"tv-shows-readonly"`
+	TotalSize        int64         `json:"total_size" example:"70640394854400"`
+	FileCount        int           `json:"file_count" example:"12543"`
+	DirectoryCount   int           `json:"directory_count" example:"1204"`
+	LargestFile      int64         `json:"largest_file" example:"8589934592"`
+	Method           string        `json:"method" example:"du"`
+	ScannedAt        time.Time     `json:"scanned_at"`
+	Duration         time.Duration `json:"duration" example:"13248000000"`
+	CacheHit         bool          `json:"cache_hit" example:"false"`
+	FilesystemType   string        `json:"filesystem_type" example:"cifs"`
+	Accuracy         string        `json:"accuracy" example:"exact" enums:"exact,approximate,estimated"`
+	Estimated        bool          `json:"estimated,omitempty" example:"false"`
+	SampleSize       int           `json:"sample_size,omitempty" example:"20"`
+	MarginOfError    int64         `json:"margin_of_error,omitempty" example:"1073741824"`
+	SkippedCount     int           `json:"skipped_count,omitempty" example:"3"`
+	SpecialCount     int           `json:"special_count,omitempty" example:"2"`
+	ErrorCount       int           `json:"error_count,omitempty" example:"1"`
+	ApparentSize     int64         `json:"apparent_size,omitempty" example:"107374182400"`
+	Sparse           bool          `json:"sparse,omitempty" example:"true"`
+	SymlinksSkipped  int           `json:"symlinks_skipped,omitempty" example:"4"`
+	SymlinksFollowed int           `json:"symlinks_followed,omitempty" example:"2"`
 } // @name ScanResult
 
 // ScanResponse represents a volume scan response
@@ -67,6 +109,21 @@ type AsyncScanResponse struct {
 	Status   string `json:"status" example:"started"`
 } // @name AsyncScanResponse
 
+// VolumeCacheResponse represents the scan result cached for a volume
+type VolumeCacheResponse struct {
+	VolumeID string      `json:"volume_id" example:"tv-shows-readonly"`
+	Cached   bool        `json:"cached" example:"true"`
+	Result   *ScanResult `json:"result,omitempty"`
+} // @name VolumeCacheResponse
+
+// VolumeCacheClearResponse represents the outcome of clearing a volume's cached scan result
+type VolumeCacheClearResponse struct {
+	VolumeID  string `json:"volume_id" example:"tv-shows-readonly"`
+	WasCached *bool  `json:"was_cached,omitempty" example:"true"` // Absent when the scanner cannot show its cache
+	ScanID    string `json:"scan_id,omitempty" example:"scan_tv-shows-readonly_1640995200"`
+	StatusURL string `json:"status_url,omitempty" example:"/api/v1/scans/scan_tv-shows-readonly_1640995200/status"`
+} // @name VolumeCacheClearResponse
+
 // ScanProgress represents the progress of an ongoing scan
 type ScanProgress struct {
 	ScanID             string        `json:"scan_id" example:"scan_tv-shows-readonly_1640995200"`
@@ -96,22 +153,37 @@ type BulkScanResponse struct {
 	Total    int               `json:"total" example:"2"`
 	Success  int               `json:"success" example:"1"`
 	Failures int               `json:"failures" example:"1"`
+	BatchResultV1
 } // @name BulkScanResponse
 
+// BulkScanStartedResponse represents the response from starting an async bulk scan
+type BulkScanStartedResponse struct {
+	Message string   `json:"message" example:"Bulk async scan started"`
+	ScanIDs []string `json:"scan_ids"` // Scans started, in request order
+	Total   int      `json:"total" example:"2"`
+	BatchResultV1
+} // @name BulkScanStartedResponse
+
 // RefreshRequest represents a request to refresh volume size
 type RefreshRequest struct {
 	Async  bool   `json:"async" example:"false"`
 	Method string `json:"method,omitempty" example:"du"`
 } // @name RefreshRequest
 
+// ScanMethodsOrderRequest sets the scan method preference order
+type ScanMethodsOrderRequest struct {
+	Order []string `json:"order" binding:"required" example:"du,diskus,native"`
+} // @name ScanMethodsOrderRequest
+
 // MethodInfo provides information about available scan methods
 type MethodInfo struct {
-	Name        string   `json:"name" example:"du"`
-	Available   bool     `json:"available" example:"true"`
-	Description string   `json:"description" example:"du-based volume scanning"`
-	Performance string   `json:"performance" example:"medium" enums:"fast,medium,slow"`
-	Accuracy    string   `json:"accuracy" example:"high" enums:"high,medium,basic"`
-	Features    []string `json:"features" example:"reliable,standard_tool"`
+	Name          string     `json:"name" example:"du"`
+	Available     bool       `json:"available" example:"true"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	Description   string     `json:"description" example:"du-based volume scanning"`
+	Performance   string     `json:"performance" example:"medium" enums:"fast,medium,slow"`
+	Accuracy      string     `json:"accuracy" example:"high" enums:"high,medium,basic"`
+	Features      []string   `json:"features" example:"reliable,standard_tool"`
 } // @name MethodInfo
 
 // SystemInfoResponse represents system information
@@ -154,16 +226,27 @@ func ConvertScanResult(result *interfaces.ScanResult) *ScanResult {
 		return nil
 	}
 	return &ScanResult{
-		VolumeID:       result.VolumeID,
-		TotalSize:      result.TotalSize,
-		FileCount:      result.FileCount,
-		DirectoryCount: result.DirectoryCount,
-		LargestFile:    result.LargestFile,
-		Method:         result.Method,
-		ScannedAt:      result.ScannedAt,
-		Duration:       result.Duration,
-		CacheHit:       result.CacheHit,
-		FilesystemType: result.FilesystemType,
+		VolumeID:         result.VolumeID,
+		TotalSize:        result.TotalSize,
+		FileCount:        result.FileCount,
+		DirectoryCount:   result.DirectoryCount,
+		LargestFile:      result.LargestFile,
+		Method:           result.Method,
+		ScannedAt:        result.ScannedAt,
+		Duration:         result.Duration,
+		CacheHit:         result.CacheHit,
+		FilesystemType:   result.FilesystemType,
+		Accuracy:         result.Accuracy,
+		Estimated:        result.Estimated,
+		SampleSize:       result.SampleSize,
+		MarginOfError:    result.MarginOfError,
+		SkippedCount:     result.SkippedCount,
+		SpecialCount:     result.SpecialCount,
+		ErrorCount:       result.ErrorCount,
+		ApparentSize:     result.ApparentSize,
+		Sparse:           result.Sparse,
+		SymlinksSkipped:  result.SymlinksSkipped,
+		SymlinksFollowed: result.SymlinksFollowed,
 	}
 }
 
@@ -189,12 +272,13 @@ func ConvertMethodInfo(methods []interfaces.MethodInfo) []MethodInfo {
 	result := make([]MethodInfo, len(methods))
 	for i, method := range methods {
 		result[i] = MethodInfo{
-			Name:        method.Name,
-			Available:   method.Available,
-			Description: method.Description,
-			Performance: method.Performance,
-			Accuracy:    method.Accuracy,
-			Features:    method.Features,
+			Name:          method.Name,
+			Available:     method.Available,
+			LastCheckedAt: method.LastCheckedAt,
+			Description:   method.Description,
+			Performance:   method.Performance,
+			Accuracy:      method.Accuracy,
+			Features:      method.Features,
 		}
 	}
 	return result
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Size encodings accepted by the X-Size-Encoding header and API_SIZE_ENCODING
+const (
+	SizeEncodingNumber = "number"
+	SizeEncodingString = "string"
+)
+
+// SizeBytes is a byte count in an API response. It encodes as a JSON number by
+// default, or as a decimal string when AsString is set so values beyond 2^53 keep
+// their precision in JavaScript clients.
+type SizeBytes struct {
+	Value    int64
+	AsString bool
+}
+
+// NewSizeBytes wraps an optional size, returning nil when the size is unknown
+func NewSizeBytes(size *int64, asString bool) *SizeBytes {
+	if size == nil {
+		return nil
+	}
+	return &SizeBytes{Value: *size, AsString: asString}
+}
+
+// MarshalJSON encodes the size as a number, or as a quoted string in string mode
+func (s SizeBytes) MarshalJSON() ([]byte, error) {
+	encoded := strconv.FormatInt(s.Value, 10)
+	if s.AsString {
+		return []byte(`"` + encoded + `"`), nil
+	}
+	return []byte(encoded), nil
+}
+
+// UnmarshalJSON accepts both the number and the string encoding
+func (s *SizeBytes) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid size: %w", err)
+	}
+
+	value, err := strconv.ParseInt(number.String(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size %q: %w", number.String(), err)
+	}
+
+	s.Value = value
+	s.AsString = len(data) > 0 && data[0] == '"'
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// beyondSafeInteger is larger than 2^53 and cannot be represented exactly as a float64
+const beyondSafeInteger int64 = 1<<53 + 1
+
+func TestSizeBytes_StringModeRoundTrip(t *testing.T) {
+	type payload struct {
+		SizeBytes *SizeBytes `json:"size_bytes"`
+	}
+
+	size := beyondSafeInteger
+	encoded, err := json.Marshal(payload{SizeBytes: NewSizeBytes(&size, true)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"size_bytes":"9007199254740993"}`, string(encoded))
+
+	var decoded payload
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.NotNil(t, decoded.SizeBytes)
+	assert.Equal(t, beyondSafeInteger, decoded.SizeBytes.Value)
+	assert.True(t, decoded.SizeBytes.AsString)
+}
+
+func TestSizeBytes_NumberModeByDefault(t *testing.T) {
+	size := beyondSafeInteger
+	encoded, err := json.Marshal(NewSizeBytes(&size, false))
+	require.NoError(t, err)
+	assert.Equal(t, "9007199254740993", string(encoded))
+
+	var decoded SizeBytes
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, beyondSafeInteger, decoded.Value)
+	assert.False(t, decoded.AsString)
+
+	assert.Nil(t, NewSizeBytes(nil, true))
+}
+
+func TestSizeBytes_UnmarshalInvalid(t *testing.T) {
+	var decoded SizeBytes
+	assert.Error(t, json.Unmarshal([]byte(`"12.5"`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`"large"`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`true`), &decoded))
+}
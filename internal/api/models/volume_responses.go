@@ -6,33 +6,120 @@ import (
 
 // VolumeV1 represents a volume in the v1 API format
 type VolumeV1 struct {
-	Name             string            `json:"name"`
-	Driver           string            `json:"driver"`
-	CreatedAt        time.Time         `json:"created_at"`
-	Labels           map[string]string `json:"labels,omitempty"`
-	Scope            string            `json:"scope"`
-	Mountpoint       string            `json:"mountpoint"`
-	SizeBytes        *int64            `json:"size_bytes,omitempty"`
-	LastScanAt       *time.Time        `json:"last_scan_at,omitempty"`
-	AttachmentsCount int               `json:"attachments_count"`
-	IsSystem         bool              `json:"is_system"`
-	IsOrphaned       bool              `json:"is_orphaned"`
+	Name              string            `json:"name"`
+	Driver            string            `json:"driver"`
+	CreatedAt         time.Time         `json:"created_at"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Scope             string            `json:"scope"`
+	Mountpoint        string            `json:"mountpoint"`
+	Node              string            `json:"node"` // Swarm node the volume lives on, or "local"
+	SizeBytes         *SizeBytes        `json:"size_bytes"`
+	SizeScannedAt     *time.Time        `json:"size_scanned_at,omitempty"` // When a size from scan stats was measured
+	SizeStale         bool              `json:"size_stale"`                // Size from scan stats older than the staleness threshold
+	SizeSupported     bool              `json:"size_supported"`
+	Scannable         bool              `json:"scannable"`
+	UnscannableReason string            `json:"unscannable_reason,omitempty"`
+	LastScanAt        *time.Time        `json:"last_scan_at,omitempty"`
+	AttachmentsCount  int               `json:"attachments_count"`
+	AllReadOnly       *bool             `json:"all_readonly"` // Whether every attachment is read-only; null without attachments
+	IsSystem          bool              `json:"is_system"`
+	IsOrphaned        bool              `json:"is_orphaned"`
+	OrphanedState     string            `json:"orphaned_state"` // One of the OrphanedState constants
 }
 
 // VolumeDetailV1 represents detailed volume information
 type VolumeDetailV1 struct {
-	Name             string                 `json:"name"`
-	Driver           string                 `json:"driver"`
-	CreatedAt        time.Time              `json:"created_at"`
-	Labels           map[string]string      `json:"labels,omitempty"`
-	Scope            string                 `json:"scope"`
-	Mountpoint       string                 `json:"mountpoint"`
-	SizeBytes        *int64                 `json:"size_bytes,omitempty"`
-	LastScanAt       *time.Time             `json:"last_scan_at,omitempty"`
-	Attachments      []AttachmentV1         `json:"attachments"`
-	IsSystem         bool                   `json:"is_system"`
-	IsOrphaned       bool                   `json:"is_orphaned"`
-	Meta             map[string]interface{} `json:"meta,omitempty"`
+	Name              string                 `json:"name"`
+	Driver            string                 `json:"driver"`
+	CreatedAt         time.Time              `json:"created_at"`
+	Labels            map[string]string      `json:"labels,omitempty"`
+	Scope             string                 `json:"scope"`
+	Mountpoint        string                 `json:"mountpoint"`
+	Node              string                 `json:"node"`
+	SizeBytes         *SizeBytes             `json:"size_bytes"`
+	SizeSupported     bool                   `json:"size_supported"`
+	Scannable         bool                   `json:"scannable"`
+	UnscannableReason string                 `json:"unscannable_reason,omitempty"`
+	ScanEnabled       bool                   `json:"scan_enabled"` // False when switched off by the scan_enabled annotation
+	LastScanAt        *time.Time             `json:"last_scan_at,omitempty"`
+	Attachments       []AttachmentV1         `json:"attachments"`
+	AttachmentsTotal  int                    `json:"attachments_total"`
+	MoreAttachments   bool                   `json:"has_more_attachments"` // Attachments is capped; AttachmentsURL pages through the rest
+	AttachmentsURL    string                 `json:"more_attachments_url,omitempty"`
+	AllReadOnly       *bool                  `json:"all_readonly"` // Whether every attachment, listed or not, is read-only; null without attachments
+	IsSystem          bool                   `json:"is_system"`
+	IsOrphaned        bool                   `json:"is_orphaned"`
+	OrphanedState     string                 `json:"orphaned_state"`
+	SizeDrift         *SizeDriftV1           `json:"size_drift,omitempty"` // Only for volumes with an expected size
+	Meta              map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Kinds of storage a local-driver volume's options describe
+const (
+	DriverConfigDefault = "default" // No options: a directory managed by Docker
+	DriverConfigBind    = "bind"    // A bind mount of a host directory
+	DriverConfigNFS     = "nfs"
+	DriverConfigCIFS    = "cifs"
+	DriverConfigTmpfs   = "tmpfs"
+	DriverConfigDevice  = "device" // A block device or other filesystem mount
+)
+
+// DriverConfigV1 is a volume's driver options parsed into typed fields, for
+// drivers whose options are understood. Options keeps the options that were
+// not parsed, and all of them for other drivers.
+type DriverConfigV1 struct {
+	Driver       string            `json:"driver"`
+	Recognized   bool              `json:"recognized"`
+	Kind         string            `json:"kind,omitempty"`
+	Type         string            `json:"type,omitempty"`          // Filesystem type passed to mount
+	MountOptions []string          `json:"mount_options,omitempty"` // The o option, split at commas
+	Device       string            `json:"device,omitempty"`
+	Server       string            `json:"server,omitempty"` // Host serving a network mount
+	Source       string            `json:"source,omitempty"` // Export, share or host path mounted
+	Options      map[string]string `json:"options,omitempty"`
+}
+
+// Orphaned states of a volume, a finer classification than is_orphaned
+const (
+	OrphanedStateAttached  = "attached"  // A mounting container is running
+	OrphanedStateDormant   = "dormant"   // Mounted only by stopped containers
+	OrphanedStateDetaching = "detaching" // Its containers are being or were just removed
+	OrphanedStateOrphaned  = "orphaned"  // No containers, and none recently
+)
+
+// Size drift statuses
+const (
+	SizeDriftWithin  = "within"
+	SizeDriftOver    = "over"
+	SizeDriftUnder   = "under"
+	SizeDriftUnknown = "unknown" // Volume has no known size yet
+)
+
+// SizeDriftV1 compares a volume's latest scanned size with its expected size
+type SizeDriftV1 struct {
+	Status            string     `json:"status"`
+	ExpectedSizeBytes *SizeBytes `json:"expected_size_bytes"`
+	MinSizeBytes      *SizeBytes `json:"min_size_bytes"`
+	MaxSizeBytes      *SizeBytes `json:"max_size_bytes"`
+	ActualSizeBytes   *SizeBytes `json:"actual_size_bytes"`
+	DeviationBytes    *SizeBytes `json:"deviation_bytes,omitempty"`   // Actual minus expected
+	DeviationPercent  *float64   `json:"deviation_percent,omitempty"` // Deviation relative to the expected size
+	ScannedAt         *time.Time `json:"scanned_at,omitempty"`
+}
+
+// SizeDriftVolumeV1 is a volume in the size drift report
+type SizeDriftVolumeV1 struct {
+	Name string `json:"name"`
+	SizeDriftV1
+}
+
+// SizeDriftReportV1 lists volumes whose size is outside their expected range
+type SizeDriftReportV1 struct {
+	Volumes     []SizeDriftVolumeV1 `json:"volumes"`
+	Expected    int                 `json:"expected"`     // Volumes with an expected size
+	OutOfRange  int                 `json:"out_of_range"` // Volumes over or under their range
+	GeneratedAt time.Time           `json:"generated_at"`
+	*ReportPageV1
 }
 
 // AttachmentV1 represents a container attachment to a volume
@@ -41,23 +128,328 @@ type AttachmentV1 struct {
 	ContainerName string    `json:"container_name,omitempty"`
 	MountPath     string    `json:"mount_path"`
 	RW            bool      `json:"rw"`
+	Propagation   string    `json:"propagation,omitempty"`
+	Consistency   string    `json:"consistency,omitempty"`
 	FirstSeen     time.Time `json:"first_seen,omitempty"`
 	LastSeen      time.Time `json:"last_seen,omitempty"`
 }
 
 // AttachmentsListV1 represents a list of volume attachments
 type AttachmentsListV1 struct {
-	Data  []AttachmentV1 `json:"data"`
-	Total int            `json:"total"`
+	Data     []AttachmentV1 `json:"data"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page,omitempty"` // Set when a page was requested
+	PageSize int            `json:"page_size,omitempty"`
 }
 
 // OrphanedVolumeV1 represents an orphaned volume in the report
 type OrphanedVolumeV1 struct {
+	Name          string     `json:"name"`
+	Driver        string     `json:"driver"`
+	SizeBytes     *SizeBytes `json:"size_bytes"`
+	SizeSupported bool       `json:"size_supported"`
+	CreatedAt     time.Time  `json:"created_at"`
+	IsSystem      bool       `json:"is_system"`
+}
+
+// OrphanedReclaimableV1 is what deleting the orphaned volumes could free.
+// Pinned and system volumes are never counted.
+type OrphanedReclaimableV1 struct {
+	ReclaimableBytes SizeBytes `json:"reclaimable_bytes"` // Sum of known sizes
+	UnsizedCount     int       `json:"unsized_count"`     // Volumes left out of the sum as their size is unknown
+}
+
+// NodeVolumeV1 is a volume in the volumes-by-node report
+type NodeVolumeV1 struct {
+	Name          string     `json:"name"`
+	Driver        string     `json:"driver"`
+	SizeBytes     *SizeBytes `json:"size_bytes"`
+	SizeSupported bool       `json:"size_supported"`
+}
+
+// NodeVolumesV1 groups the volumes that live on one node
+type NodeVolumesV1 struct {
+	Node           string         `json:"node"`
+	VolumeCount    int            `json:"volume_count"`
+	TotalSizeBytes SizeBytes      `json:"total_size_bytes"` // Sum of known sizes
+	Volumes        []NodeVolumeV1 `json:"volumes"`
+}
+
+// VolumesByNodeReportV1 represents the volumes-by-node report
+type VolumesByNodeReportV1 struct {
+	Nodes        []NodeVolumesV1 `json:"nodes"`
+	TotalVolumes int             `json:"total_volumes"`
+	GeneratedAt  time.Time       `json:"generated_at"`
+	*ReportPageV1
+}
+
+// LabelValueUnassigned groups the volumes of the by-label report that have no value for the key
+const LabelValueUnassigned = "unassigned"
+
+// Where a volume's value in the by-label report comes from
+const (
+	LabelSourceAnnotation = "annotation"
+	LabelSourceLabel      = "label"
+)
+
+// LabelVolumeV1 is a volume in the volumes-by-label report
+type LabelVolumeV1 struct {
+	Name          string     `json:"name"`
+	Driver        string     `json:"driver"`
+	SizeBytes     *SizeBytes `json:"size_bytes"`
+	SizeSupported bool       `json:"size_supported"`
+	Source        string     `json:"source,omitempty"` // annotation or label; empty when unassigned
+}
+
+// LabelVolumesV1 groups the volumes sharing one value of the report key
+type LabelVolumesV1 struct {
+	Value          string          `json:"value"`
+	VolumeCount    int             `json:"volume_count"`
+	TotalSizeBytes SizeBytes       `json:"total_size_bytes"` // Sum of known sizes
+	Volumes        []LabelVolumeV1 `json:"volumes"`
+}
+
+// VolumesByLabelReportV1 represents the volumes-by-label report
+type VolumesByLabelReportV1 struct {
+	Key            string           `json:"key"`
+	Groups         []LabelVolumesV1 `json:"groups"`
+	TotalVolumes   int              `json:"total_volumes"`
+	TotalSizeBytes SizeBytes        `json:"total_size_bytes"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+	*ReportPageV1
+}
+
+// ProjectUngrouped groups the volumes of the by-project report that belong to no Compose project
+const ProjectUngrouped = "ungrouped"
+
+// ProjectVolumeV1 is a volume in the volumes-by-project report
+type ProjectVolumeV1 struct {
+	Name          string     `json:"name"`
+	Driver        string     `json:"driver"`
+	SizeBytes     *SizeBytes `json:"size_bytes"`
+	SizeSupported bool       `json:"size_supported"`
+	Attachments   []string   `json:"attachments"` // Names of the containers using the volume
+}
+
+// ProjectVolumesV1 groups the volumes of one Compose project
+type ProjectVolumesV1 struct {
+	Project         string            `json:"project"`
+	VolumeCount     int               `json:"volume_count"`
+	AttachmentCount int               `json:"attachment_count"` // Distinct containers using the project's volumes
+	TotalSizeBytes  SizeBytes         `json:"total_size_bytes"` // Sum of known sizes
+	Volumes         []ProjectVolumeV1 `json:"volumes"`
+}
+
+// VolumesByProjectReportV1 represents the volumes-by-project report
+type VolumesByProjectReportV1 struct {
+	Projects       []ProjectVolumesV1 `json:"projects"`
+	TotalVolumes   int                `json:"total_volumes"`
+	TotalSizeBytes SizeBytes          `json:"total_size_bytes"`
+	GeneratedAt    time.Time          `json:"generated_at"`
+	*ReportPageV1
+	*PartialResultV1 // Set when the Docker API call budget ran out; totals cover the volumes checked before
+}
+
+// SizeSampleV1 is the size of a volume at one point in time
+type SizeSampleV1 struct {
+	SizeBytes *SizeBytes `json:"size_bytes"`
+	FileCount *int64     `json:"file_count,omitempty"`
+	Method    string     `json:"method,omitempty"`
+	Accuracy  string     `json:"accuracy,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// VolumeOverviewV1 combines everything the volume detail page shows. Sections
+// whose lookup failed are left empty and explained in Warnings.
+type VolumeOverviewV1 struct {
+	Volume      VolumeDetailV1    `json:"volume"`
+	LatestSize  *SizeSampleV1     `json:"latest_size"` // Most recent scan; nil if never scanned
+	History     []SizeSampleV1    `json:"history"`     // Newest first
+	Attachments []AttachmentV1    `json:"attachments"`
+	Annotations map[string]string `json:"annotations"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+// PruneRequestV1 selects orphaned volumes to delete by policy
+type PruneRequestV1 struct {
+	// Match is a set of labels or annotations a volume must all carry
+	Match map[string]string `json:"match,omitempty"`
+	// DryRun lists the volumes the policy selects without deleting them
+	DryRun bool `json:"dry_run"`
+	// ConfirmationToken is the token from a dry run of the same selection,
+	// required to delete when prune confirmation is enabled
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// PrunedVolumeV1 is a volume selected by a prune policy
+type PrunedVolumeV1 struct {
+	Name          string     `json:"name"`
+	Driver        string     `json:"driver"`
+	SizeBytes     *SizeBytes `json:"size_bytes"`
+	OrphanedSince time.Time  `json:"orphaned_since"`
+	Reason        string     `json:"reason"`          // Why the policy selected the volume
+	Error         string     `json:"error,omitempty"` // Set when the volume could not be deleted
+}
+
+// PruneResponseV1 reports the volumes a prune policy selected and deleted
+type PruneResponseV1 struct {
+	DryRun   bool             `json:"dry_run"`
+	Selected int              `json:"selected"`
+	Deleted  int              `json:"deleted"`
+	Volumes  []PrunedVolumeV1 `json:"volumes"`
+
+	// Set when the Docker API call budget ran out; volumes past it were not considered
+	*PartialResultV1
+
+	// Returned by dry runs when prune confirmation is enabled
+	ConfirmationToken     string     `json:"confirmation_token,omitempty"`
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at,omitempty"`
+}
+
+// QuarantinedVolumeV1 is a volume held in quarantine before deletion
+type QuarantinedVolumeV1 struct {
+	Name          string     `json:"name"`
+	Driver        string     `json:"driver"`
+	SizeBytes     *SizeBytes `json:"size_bytes"`
+	QuarantinedAt time.Time  `json:"quarantined_at"`
+	DeleteAfter   *time.Time `json:"delete_after,omitempty"` // Unset while automatic quarantine is disabled
+}
+
+// QuarantineReportV1 lists the volumes in quarantine, soonest deleted first
+type QuarantineReportV1 struct {
+	Volumes     []QuarantinedVolumeV1 `json:"volumes"`
+	Total       int                   `json:"total"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	*ReportPageV1
+}
+
+// QuarantineRestoreResponseV1 reports a volume restored from quarantine
+type QuarantineRestoreResponseV1 struct {
+	Name       string    `json:"name"`
+	RestoredAt time.Time `json:"restored_at"`
+}
+
+// VolumeLockRequestV1 locks a volume against scanning for a while
+type VolumeLockRequestV1 struct {
+	// TTL is how long the lock holds: a Go duration ("2h") or days ("1d")
+	TTL string `json:"ttl" binding:"required"`
+	// Holder names who takes the lock, e.g. the backup job; optional
+	Holder string `json:"holder,omitempty"`
+}
+
+// VolumeLockV1 is the scan lock held on a volume
+type VolumeLockV1 struct {
+	Name             string     `json:"name"`
+	Locked           bool       `json:"locked"`
+	Holder           string     `json:"holder,omitempty"`
+	LockedUntil      *time.Time `json:"locked_until,omitempty"`
+	ExpiresInSeconds int64      `json:"expires_in_seconds,omitempty"`
+}
+
+// Confidence that the volumes of a duplicates group hold the same content
+const (
+	DuplicateConfidenceHigh   = "high"   // Same files and sizes, and the sampled content covers much of it
+	DuplicateConfidenceMedium = "medium" // Same files and sizes, but little of the content was sampled
+	DuplicateConfidenceLow    = "low"    // A walk stopped at the file limit, so only part of the tree was compared
+)
+
+// DuplicateGroupV1 is a set of volumes whose fingerprints match
+type DuplicateGroupV1 struct {
+	Volumes          []string   `json:"volumes"`
+	Confidence       string     `json:"confidence"`
+	FileCount        int        `json:"file_count"`
+	SizeBytes        *SizeBytes `json:"size_bytes"`        // Fingerprinted bytes of each volume
+	ReclaimableBytes *SizeBytes `json:"reclaimable_bytes"` // Held by all but one of the volumes
+	SampledFraction  float64    `json:"sampled_fraction"`  // Share of the bytes whose content was hashed
+	FingerprintedAt  time.Time  `json:"fingerprinted_at"`  // Oldest fingerprint of the group
+}
+
+// DuplicatesReportV1 lists groups of volumes that likely hold the same data,
+// most reclaimable first. Matching is heuristic: fingerprints cover every
+// file path and size but only a sample of the content.
+type DuplicatesReportV1 struct {
+	Groups                []DuplicateGroupV1 `json:"groups"`
+	Total                 int                `json:"total"`
+	TotalReclaimableBytes *SizeBytes         `json:"total_reclaimable_bytes"`
+	VolumesFingerprinted  int                `json:"volumes_fingerprinted"`
+	FingerprintedAt       *time.Time         `json:"fingerprinted_at,omitempty"` // End of the last run; unset before the first
+	Heuristic             bool               `json:"heuristic"`
+	Note                  string             `json:"note"`
+	GeneratedAt           time.Time          `json:"generated_at"`
+	*ReportPageV1
+}
+
+// VolumeProbeRequestV1 narrows a probe of network volumes; an empty request
+// probes every network-backed volume
+type VolumeProbeRequestV1 struct {
+	// Volumes probes these volumes by name, network-backed or not
+	Volumes []string `json:"volumes,omitempty"`
+	// Driver only probes volumes on this driver
+	Driver string `json:"driver,omitempty"`
+}
+
+// VolumeProbeResultV1 is the outcome of probing one volume's mount
+type VolumeProbeResultV1 struct {
+	Name      string  `json:"name"`
+	Driver    string  `json:"driver"`
+	Type      string  `json:"type,omitempty"` // Filesystem type from the volume options, e.g. nfs
+	Reachable bool    `json:"reachable"`
+	LatencyMs float64 `json:"latency_ms"` // Time the probe took, up to the probe timeout
+	Error     string  `json:"error,omitempty"`
+	InFlight  bool    `json:"in_flight,omitempty"` // Not probed: an earlier probe of the mount has not returned
+}
+
+// VolumeProbeReportV1 is the result of probing network volumes
+type VolumeProbeReportV1 struct {
+	Volumes     []VolumeProbeResultV1 `json:"volumes"`
+	Total       int                   `json:"total"`
+	Reachable   int                   `json:"reachable"`
+	Unreachable int                   `json:"unreachable"`
+	InFlight    int                   `json:"in_flight"` // Unreachable volumes whose earlier probe has not returned
+	GeneratedAt time.Time             `json:"generated_at"`
+	BatchResultV1
+}
+
+// Directory entry types
+const (
+	EntryTypeFile    = "file"
+	EntryTypeDir     = "dir"
+	EntryTypeSymlink = "symlink"
+	EntryTypeOther   = "other" // Sockets, FIFOs and device files
+)
+
+// DirectoryEntryV1 is one entry of a volume directory listing
+type DirectoryEntryV1 struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	SizeBytes  *SizeBytes `json:"size_bytes,omitempty"` // Regular files only; directories are not walked
+	Mode       string     `json:"mode"`
+	ModifiedAt time.Time  `json:"modified_at"`
+}
+
+// DirectoryListingV1 is one directory level of a volume
+type DirectoryListingV1 struct {
+	Volume    string             `json:"volume"`
+	Path      string             `json:"path"` // Relative to the volume root, "" for the root
+	Entries   []DirectoryEntryV1 `json:"entries"`
+	Truncated bool               `json:"truncated"` // More entries exist than the limit returned
+}
+
+// RemovedVolumeV1 is a volume removed since the time a change listing asked about
+type RemovedVolumeV1 struct {
 	Name      string    `json:"name"`
-	Driver    string    `json:"driver"`
-	SizeBytes int64     `json:"size_bytes"`
-	CreatedAt time.Time `json:"created_at"`
-	IsSystem  bool      `json:"is_system"`
+	RemovedAt time.Time `json:"removed_at"`
+}
+
+// VolumeChangesV1 lists the volumes created, updated and removed since a
+// time. ServerTime is when the listing was taken; passing it as the next
+// since misses no change, though one may be listed twice.
+type VolumeChangesV1 struct {
+	Since      time.Time         `json:"since"`
+	ServerTime time.Time         `json:"server_time"`
+	Created    []VolumeV1        `json:"created"`
+	Updated    []VolumeV1        `json:"updated"`
+	Removed    []RemovedVolumeV1 `json:"removed"`
 }
 
 // ErrorV1 represents the uniform error response format
@@ -71,4 +463,4 @@ type ErrorDetailsV1 struct {
 	Message   string                 `json:"message"`
 	Details   map[string]interface{} `json:"details,omitempty"`
 	RequestID string                 `json:"request_id"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+)
+
+// BatchIDs returns the IDs a batch request names, without blanks and
+// duplicates. An empty list is answered with a 400 and ok false.
+func BatchIDs(c *gin.Context, field string, ids []string) (unique []string, ok bool) {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	if len(unique) == 0 {
+		RespondWithBadRequest(c, fmt.Sprintf("%s must list at least one item", field), map[string]interface{}{"field": field})
+		return nil, false
+	}
+	return unique, true
+}
+
+// Batch collects the outcome of each item of a batch request so every batch
+// endpoint reports missing items, failures and partial success the same way
+type Batch struct {
+	result models.BatchResultV1
+}
+
+// NewBatch creates an empty batch
+func NewBatch() *Batch {
+	return &Batch{result: models.BatchResultV1{
+		Items:    []models.BatchItemV1{},
+		NotFound: []string{},
+		Errors:   map[string]string{},
+	}}
+}
+
+// Succeeded records that id was processed, answering it with status
+func (b *Batch) Succeeded(id string, status int) {
+	b.result.Items = append(b.result.Items, models.BatchItemV1{ID: id, Status: status})
+	b.result.Succeeded++
+}
+
+// NotFound records that id does not exist
+func (b *Batch) NotFound(id string) {
+	b.result.Items = append(b.result.Items, models.BatchItemV1{ID: id, Status: http.StatusNotFound, Error: "not found"})
+	b.result.NotFound = append(b.result.NotFound, id)
+}
+
+// Failed records that processing id failed with err
+func (b *Batch) Failed(id string, err error) {
+	b.result.Items = append(b.result.Items, models.BatchItemV1{ID: id, Status: http.StatusInternalServerError, Error: err.Error()})
+	b.result.Errors[id] = err.Error()
+}
+
+// Result returns the per-item outcomes for the response body
+func (b *Batch) Result() models.BatchResultV1 {
+	return b.result
+}
+
+// StatusCode returns the status shared by every item, 200 for an empty
+// batch, or 207 Multi-Status when the items' statuses differ
+func (b *Batch) StatusCode() int {
+	if len(b.result.Items) == 0 {
+		return http.StatusOK
+	}
+	status := b.result.Items[0].Status
+	for _, item := range b.result.Items[1:] {
+		if item.Status != status {
+			return http.StatusMultiStatus
+		}
+	}
+	return status
+}
+
+// RespondWithBatch sends body, which embeds the batch's result, with the
+// batch's status code
+func RespondWithBatch(c *gin.Context, batch *Batch, body interface{}) {
+	c.JSON(batch.StatusCode(), body)
+}
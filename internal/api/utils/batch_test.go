@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ids, ok := BatchIDs(c, "volume_ids", []string{"media", " backups ", "media", ""})
+	require.True(t, ok)
+	assert.Equal(t, []string{"media", "backups"}, ids)
+
+	for _, input := range [][]string{nil, {}, {"", "  "}} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/batch", nil)
+		_, ok := BatchIDs(c, "volume_ids", input)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "volume_ids must list at least one item")
+	}
+}
+
+func TestBatch_StatusCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(b *Batch)
+		status int
+	}{
+		{"empty", func(b *Batch) {}, http.StatusOK},
+		{"all succeeded", func(b *Batch) {
+			b.Succeeded("a", http.StatusOK)
+			b.Succeeded("b", http.StatusOK)
+		}, http.StatusOK},
+		{"all accepted", func(b *Batch) {
+			b.Succeeded("a", http.StatusAccepted)
+		}, http.StatusAccepted},
+		{"all missing", func(b *Batch) {
+			b.NotFound("a")
+			b.NotFound("b")
+		}, http.StatusNotFound},
+		{"all failed", func(b *Batch) {
+			b.Failed("a", errors.New("boom"))
+		}, http.StatusInternalServerError},
+		{"partial success", func(b *Batch) {
+			b.Succeeded("a", http.StatusOK)
+			b.NotFound("b")
+		}, http.StatusMultiStatus},
+		{"missing and failed", func(b *Batch) {
+			b.NotFound("a")
+			b.Failed("b", errors.New("boom"))
+		}, http.StatusMultiStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := NewBatch()
+			tt.record(batch)
+			assert.Equal(t, tt.status, batch.StatusCode())
+		})
+	}
+}
+
+func TestRespondWithBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type body struct {
+		Total int `json:"total"`
+		models.BatchResultV1
+	}
+
+	batch := NewBatch()
+	batch.Succeeded("media", http.StatusOK)
+	batch.NotFound("ghost")
+	batch.Failed("backups", errors.New("mount timed out"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	RespondWithBatch(c, batch, body{Total: 3, BatchResultV1: batch.Result()})
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.EqualValues(t, 3, got["total"])
+	assert.EqualValues(t, 1, got["succeeded"])
+	assert.Equal(t, []interface{}{"ghost"}, got["not_found"])
+	assert.Equal(t, map[string]interface{}{"backups": "mount timed out"}, got["errors"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "media", "status": 200.0},
+		map[string]interface{}{"id": "ghost", "status": 404.0, "error": "not found"},
+		map[string]interface{}{"id": "backups", "status": 500.0, "error": "mount timed out"},
+	}, got["items"])
+
+	// An empty batch still lists empty sections rather than nulls
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	empty := NewBatch()
+	RespondWithBatch(c, empty, body{BatchResultV1: empty.Result()})
+	assert.JSONEq(t, `{"total":0,"items":[],"not_found":[],"errors":{},"succeeded":0}`, w.Body.String())
+}
@@ -15,6 +15,7 @@ const (
 	ErrorCodeUnauthorized ErrorCode = "unauthorized"
 	ErrorCodeForbidden    ErrorCode = "forbidden"
 	ErrorCodeNotFound     ErrorCode = "not_found"
+	ErrorCodeConflict     ErrorCode = "conflict"
 	ErrorCodeRateLimited  ErrorCode = "rate_limited"
 	ErrorCodeInternal     ErrorCode = "internal"
 )
@@ -58,6 +59,11 @@ func RespondWithNotFound(c *gin.Context, message string) {
 	RespondWithError(c, 404, ErrorCodeNotFound, message, nil)
 }
 
+// RespondWithConflict sends a 409 Conflict error
+func RespondWithConflict(c *gin.Context, message string, details map[string]interface{}) {
+	RespondWithError(c, 409, ErrorCodeConflict, message, details)
+}
+
 // RespondWithRateLimited sends a 429 Rate Limited error
 func RespondWithRateLimited(c *gin.Context, message string, retryAfter int) {
 	if retryAfter > 0 {
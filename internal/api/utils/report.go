@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+)
+
+// ReportLimitsKey is the context key for the report limits of a request
+const ReportLimitsKey = "reportLimits"
+
+// ReportLimits caps the items and encoded size of a report response so
+// pathological inputs cannot produce payloads that exhaust client or server
+// memory. A zero cap is disabled.
+type ReportLimits struct {
+	MaxItems int
+	MaxBytes int
+}
+
+// DefaultReportLimits applies when no limits were set for the request
+var DefaultReportLimits = ReportLimits{MaxItems: 5000, MaxBytes: 8 << 20}
+
+// GetReportLimits returns the report limits of the request
+func GetReportLimits(c *gin.Context) ReportLimits {
+	if limits, exists := c.Get(ReportLimitsKey); exists {
+		if limits, ok := limits.(ReportLimits); ok {
+			return limits
+		}
+	}
+	return DefaultReportLimits
+}
+
+// ParseReportCursor returns the item offset a report continues at, zero when
+// the request carries no cursor
+func ParseReportCursor(c *gin.Context) (int, error) {
+	raw := c.Query("cursor")
+	if raw == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(raw)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor parameter: must be a cursor returned as next_cursor")
+	}
+	return offset, nil
+}
+
+// RespondWithReport writes a 200 report carrying the count items that start
+// at offset in the full result. build returns the payload holding the first n
+// of those items along with page. When all count items break the request's
+// report limits, the largest n that fits is sent with page.Truncated set and
+// a cursor at the first item left out. At least one item is always sent so a
+// client following the cursor always makes progress.
+func RespondWithReport(c *gin.Context, offset, count int, build func(n int, page *models.ReportPageV1) interface{}) {
+	limits := GetReportLimits(c)
+
+	encode := func(n int) ([]byte, error) {
+		page := &models.ReportPageV1{}
+		if n < count {
+			page.Truncated = true
+			page.NextCursor = strconv.Itoa(offset + n)
+			page.Next = continuationURL(c, page.NextCursor)
+		}
+		return json.Marshal(build(n, page))
+	}
+	fits := func(body []byte) bool {
+		return limits.MaxBytes <= 0 || len(body) <= limits.MaxBytes
+	}
+
+	n := count
+	if limits.MaxItems > 0 && n > limits.MaxItems {
+		n = limits.MaxItems
+	}
+	body, err := encode(n)
+	if err == nil && !fits(body) && n > 1 {
+		// The largest item count whose encoding fits; a single item always goes out
+		n = sort.Search(n-1, func(i int) bool {
+			candidate, err := encode(i + 2)
+			return err != nil || !fits(candidate)
+		}) + 1
+		body, err = encode(n)
+	}
+	if err != nil {
+		RespondWithInternalError(c, "Failed to encode report", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// continuationURL returns the request URL with its cursor set to cursor. The
+// page parameter gives way to the cursor.
+func continuationURL(c *gin.Context, cursor string) string {
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+	return c.Request.URL.Path + "?" + query.Encode()
+}
+
+// RespondWithPagedReport writes one page of a paginated report. The page may
+// be truncated like any report; its cursor continues within the same page size.
+func RespondWithPagedReport[T any](c *gin.Context, data []T, pagination *PaginationParams, total int64, sortParams []SortParam, filters map[string]interface{}) {
+	RespondWithReport(c, pagination.Offset, len(data), func(n int, page *models.ReportPageV1) interface{} {
+		response := BuildPagedResponse(data[:n], pagination, total, sortParams, filters)
+		response.ReportPageV1 = page
+		return response
+	})
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReport is a report listing a window of items
+type testReport struct {
+	Items []string `json:"items"`
+	*models.ReportPageV1
+}
+
+func respondWithTestReport(t *testing.T, target string, limits *ReportLimits, items []string) (int, testReport) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", target, nil)
+	if limits != nil {
+		c.Set(ReportLimitsKey, *limits)
+	}
+
+	cursor, err := ParseReportCursor(c)
+	require.NoError(t, err)
+	remaining := items[min(cursor, len(items)):]
+	RespondWithReport(c, cursor, len(remaining), func(n int, page *models.ReportPageV1) interface{} {
+		return testReport{Items: remaining[:n], ReportPageV1: page}
+	})
+
+	var report testReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report), w.Body.String())
+	return w.Code, report
+}
+
+func TestRespondWithReport(t *testing.T) {
+	items := make([]string, 10)
+	for i := range items {
+		items[i] = fmt.Sprintf("item-%02d", i)
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		code, report := respondWithTestReport(t, "/reports/test", nil, items)
+		assert.Equal(t, 200, code)
+		assert.Equal(t, items, report.Items)
+		assert.False(t, report.Truncated)
+		assert.Empty(t, report.NextCursor)
+		assert.Empty(t, report.Next)
+	})
+
+	t.Run("item cap truncates with a cursor", func(t *testing.T) {
+		_, report := respondWithTestReport(t, "/reports/test?all=true", &ReportLimits{MaxItems: 4}, items)
+		assert.Equal(t, items[:4], report.Items)
+		assert.True(t, report.Truncated)
+		assert.Equal(t, "4", report.NextCursor)
+		assert.Equal(t, "/reports/test?all=true&cursor=4", report.Next)
+
+		// Following the cursors reads the rest of the report
+		_, report = respondWithTestReport(t, report.Next, &ReportLimits{MaxItems: 4}, items)
+		assert.Equal(t, items[4:8], report.Items)
+		_, report = respondWithTestReport(t, report.Next, &ReportLimits{MaxItems: 4}, items)
+		assert.Equal(t, items[8:], report.Items)
+		assert.False(t, report.Truncated)
+	})
+
+	t.Run("byte cap truncates to what fits", func(t *testing.T) {
+		limits := &ReportLimits{MaxBytes: 100}
+		code, report := respondWithTestReport(t, "/reports/test", limits, items)
+		assert.Equal(t, 200, code)
+		assert.True(t, report.Truncated)
+		require.NotEmpty(t, report.Items)
+		assert.Less(t, len(report.Items), len(items))
+
+		encoded, err := json.Marshal(report)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(encoded), limits.MaxBytes)
+
+		// One more item would not have fitted
+		bigger, err := json.Marshal(testReport{Items: items[:len(report.Items)+1], ReportPageV1: report.ReportPageV1})
+		require.NoError(t, err)
+		assert.Greater(t, len(bigger), limits.MaxBytes)
+	})
+
+	t.Run("an item larger than the byte cap is still sent", func(t *testing.T) {
+		huge := []string{strings.Repeat("x", 200), "small"}
+		_, report := respondWithTestReport(t, "/reports/test", &ReportLimits{MaxBytes: 50}, huge)
+		assert.Equal(t, huge[:1], report.Items)
+		assert.True(t, report.Truncated)
+		assert.Equal(t, "1", report.NextCursor)
+	})
+
+	t.Run("zero caps are disabled", func(t *testing.T) {
+		_, report := respondWithTestReport(t, "/reports/test", &ReportLimits{}, items)
+		assert.Equal(t, items, report.Items)
+		assert.False(t, report.Truncated)
+	})
+}
+
+func TestRespondWithPagedReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/reports/mounts?page=2&page_size=5", nil)
+	c.Set(ReportLimitsKey, ReportLimits{MaxItems: 3})
+
+	pagination, err := ParsePaginationParams(c)
+	require.NoError(t, err)
+	RespondWithPagedReport(c, []int{5, 6, 7, 8, 9}, pagination, 20, nil, nil)
+
+	var response struct {
+		Data []int `json:"data"`
+		Page int   `json:"page"`
+		models.ReportPageV1
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []int{5, 6, 7}, response.Data)
+	assert.Equal(t, 2, response.Page)
+	assert.True(t, response.Truncated)
+	assert.Equal(t, "8", response.NextCursor)
+	assert.Equal(t, "/reports/mounts?cursor=8&page_size=5", response.Next)
+
+	// The cursor replaces the page and continues inside it
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", response.Next, nil)
+	pagination, err = ParsePaginationParams(c)
+	require.NoError(t, err)
+	assert.Equal(t, 8, pagination.Offset)
+	assert.Equal(t, 5, pagination.Limit)
+	assert.Equal(t, 2, pagination.Page)
+
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/reports/mounts?cursor=-1", nil)
+	_, err = ParsePaginationParams(c)
+	assert.Error(t, err)
+}
@@ -2,11 +2,13 @@ package utils
 
 import (
 	"fmt"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
 )
 
 // PaginationParams holds pagination parameters
@@ -25,10 +27,15 @@ type SortParam struct {
 
 // VolumeFilters holds volume-specific filter parameters
 type VolumeFilters struct {
-	Query          string    // Search query (q parameter)
-	Driver         string    // Exact driver match
-	Orphaned       *bool     // Filter by orphaned status
-	System         bool      // Include system volumes
+	Query          string      // Search query (q parameter)
+	Driver         string      // Exact driver match
+	Project        string      // Docker Compose project, or "ungrouped" for volumes outside any project
+	Orphaned       *bool       // Filter by orphaned status
+	AllReadOnly    *bool       // Filter by whether every attachment is read-only; volumes without attachments never match
+	Mountpoint     *PathFilter // Filter by the mountpoint Docker reports
+	Device         *PathFilter // Filter by the device option of the volume driver; volumes without one never match
+	CountStopped   *bool       // Whether stopped containers keep a volume from being orphaned; nil uses the server default
+	System         bool        // Include system volumes
 	CreatedAfter   *time.Time
 	CreatedBefore  *time.Time
 }
@@ -48,7 +55,7 @@ func ParsePaginationParams(c *gin.Context) (*PaginationParams, error) {
 		return nil, fmt.Errorf("invalid page_size parameter: must be a positive integer")
 	}
 
-	// Enforce maximum page size
+	// Enforce maximum page size; API_INLINE_ATTACHMENTS_MAX is validated against it
 	const maxPageSize = 200
 	if pageSize > maxPageSize {
 		pageSize = maxPageSize
@@ -58,6 +65,14 @@ func ParsePaginationParams(c *gin.Context) (*PaginationParams, error) {
 	offset := (page - 1) * pageSize
 	limit := pageSize
 
+	// A report cursor continues a truncated page at the item it points to
+	if _, hasCursor := c.GetQuery("cursor"); hasCursor {
+		if offset, err = ParseReportCursor(c); err != nil {
+			return nil, err
+		}
+		page = offset/pageSize + 1
+	}
+
 	return &PaginationParams{
 		Page:     page,
 		PageSize: pageSize,
@@ -117,12 +132,66 @@ func ParseSortParams(c *gin.Context, allowedFields []string) ([]SortParam, error
 	return sortParams, nil
 }
 
+// PathFilter matches a host path exactly or, when given with a trailing *, by
+// prefix
+type PathFilter struct {
+	Path   string
+	Prefix bool
+}
+
+// Matches reports whether p matches the filter. Exact matches ignore
+// trailing slashes and redundant separators.
+func (f *PathFilter) Matches(p string) bool {
+	if p == "" {
+		return false
+	}
+	if f.Prefix {
+		return strings.HasPrefix(p, f.Path)
+	}
+	return path.Clean(p) == f.Path
+}
+
+// parsePathFilter extracts the path filter named param, returning nil when
+// the parameter is absent
+func parsePathFilter(c *gin.Context, param string) (*PathFilter, error) {
+	raw := strings.TrimSpace(c.Query(param))
+	if raw == "" {
+		return nil, nil
+	}
+	if prefix, ok := strings.CutSuffix(raw, "*"); ok {
+		if prefix == "" || strings.Contains(prefix, "*") {
+			return nil, fmt.Errorf("invalid %s parameter: a prefix needs a path before the trailing *", param)
+		}
+		return &PathFilter{Path: prefix, Prefix: true}, nil
+	}
+	if strings.Contains(raw, "*") {
+		return nil, fmt.Errorf("invalid %s parameter: * is only allowed at the end", param)
+	}
+	return &PathFilter{Path: path.Clean(raw)}, nil
+}
+
+// ParseCountStopped extracts the count_stopped parameter, which decides
+// whether containers that are not running count as attachments when telling
+// if a volume is orphaned. It returns nil when the parameter is absent.
+func ParseCountStopped(c *gin.Context) (*bool, error) {
+	raw := c.Query("count_stopped")
+	if raw == "" {
+		return nil, nil
+	}
+	countStopped, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid count_stopped parameter: must be true or false")
+	}
+	return &countStopped, nil
+}
+
 // ParseVolumeFilters extracts volume-specific filter parameters
 func ParseVolumeFilters(c *gin.Context) (*VolumeFilters, error) {
 	filters := &VolumeFilters{
-		Query:  c.Query("q"),
-		Driver: c.Query("driver"),
-		System: c.DefaultQuery("system", "false") == "true",
+		Query:   c.Query("q"),
+		Driver:  c.Query("driver"),
+		Project: strings.TrimSpace(c.Query("project")),
+		System:  c.DefaultQuery("system", "false") == "true",
 	}
 
 	// Parse orphaned filter
@@ -131,6 +200,30 @@ func ParseVolumeFilters(c *gin.Context) (*VolumeFilters, error) {
 		filters.Orphaned = &orphaned
 	}
 
+	// Parse stopped-container counting
+	countStopped, err := ParseCountStopped(c)
+	if err != nil {
+		return nil, err
+	}
+	filters.CountStopped = countStopped
+
+	// Parse read-only filter
+	if allReadOnlyStr := c.Query("all_readonly"); allReadOnlyStr != "" {
+		allReadOnly, err := strconv.ParseBool(allReadOnlyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid all_readonly parameter: must be true or false")
+		}
+		filters.AllReadOnly = &allReadOnly
+	}
+
+	// Parse host path filters
+	if filters.Mountpoint, err = parsePathFilter(c, "mountpoint"); err != nil {
+		return nil, err
+	}
+	if filters.Device, err = parsePathFilter(c, "device"); err != nil {
+		return nil, err
+	}
+
 	// Parse date filters
 	if createdAfterStr := c.Query("created_after"); createdAfterStr != "" {
 		t, err := time.Parse(time.RFC3339, createdAfterStr)
@@ -179,6 +272,9 @@ type PagedResponse struct {
 	Total      int64                  `json:"total"`
 	Sort       string                 `json:"sort,omitempty"`
 	Filters    map[string]interface{} `json:"filters,omitempty"`
+
+	// Set on reports, which RespondWithReport may truncate
+	*models.ReportPageV1
 }
 
 // BuildPagedResponse creates a standardized paged response
@@ -190,6 +190,28 @@ func TestParseVolumeFilters(t *testing.T) {
 			query:       "created_after=invalid-date",
 			expectError: true,
 		},
+		{
+			name:  "path filters",
+			query: "mountpoint=/var/lib/docker/volumes/app*&device=/mnt/data//foo/",
+			check: func(t *testing.T, filters *VolumeFilters) {
+				assert.Equal(t, &PathFilter{Path: "/var/lib/docker/volumes/app", Prefix: true}, filters.Mountpoint)
+				assert.Equal(t, &PathFilter{Path: "/mnt/data/foo"}, filters.Device)
+				assert.True(t, filters.Mountpoint.Matches("/var/lib/docker/volumes/app-data/_data"))
+				assert.True(t, filters.Device.Matches("/mnt/data/foo/"))
+				assert.False(t, filters.Device.Matches("/mnt/data/foo/bar"))
+				assert.False(t, filters.Device.Matches(""))
+			},
+		},
+		{
+			name:        "bare wildcard path filter",
+			query:       "mountpoint=*",
+			expectError: true,
+		},
+		{
+			name:        "inner wildcard path filter",
+			query:       "device=/mnt/*/foo",
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DisableWriteTimeout lifts the server write timeout for the response of c.
+// Streaming endpoints call it before they start writing, since their
+// responses take as long as the data they stream rather than a bounded time.
+// Response writers that carry no deadline are left as they are.
+func DisableWriteTimeout(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VolumeNamePattern is the character set Docker accepts for volume names:
+// an alphanumeric first character followed by alphanumerics, '_', '.' or '-'
+const VolumeNamePattern = `^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`
+
+// MaxVolumeNameLength bounds volume names to what fits in a single path segment on disk
+const MaxVolumeNameLength = 255
+
+var volumeNameRegexp = regexp.MustCompile(VolumeNamePattern)
+
+// NormalizeVolumeName validates a volume name against VolumeNamePattern. Names
+// from path parameters arrive already decoded by the router and are not
+// decoded again, so a literal '%' is simply an invalid character.
+func NormalizeVolumeName(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("volume name cannot be empty")
+	}
+
+	if len(name) > MaxVolumeNameLength {
+		return "", fmt.Errorf("volume name too long (max %d characters)", MaxVolumeNameLength)
+	}
+
+	if !volumeNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("volume name %q must match %s", name, VolumeNamePattern)
+	}
+
+	return name, nil
+}
+
+// ParseVolumeNameParam reads and validates a volume name path parameter.
+// It responds with 400 and returns false when the name is missing or invalid.
+func ParseVolumeNameParam(c *gin.Context, param string) (string, bool) {
+	raw := c.Param(param)
+	if raw == "" {
+		RespondWithBadRequest(c, "Volume name is required", nil)
+		return "", false
+	}
+
+	name, err := NormalizeVolumeName(raw)
+	if err != nil {
+		RespondWithBadRequest(c, "Invalid volume name", map[string]interface{}{
+			"name":    raw,
+			"pattern": VolumeNamePattern,
+			"error":   err.Error(),
+		})
+		return "", false
+	}
+
+	return name, true
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeVolumeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "simple", input: "postgres-data", expected: "postgres-data"},
+		{name: "compose style", input: "myproject_db.data", expected: "myproject_db.data"},
+		{name: "anonymous hash", input: strings.Repeat("a1", 32), expected: strings.Repeat("a1", 32)},
+		{name: "empty", input: "", wantErr: true},
+		{name: "single character", input: "a", wantErr: true},
+		{name: "leading dash", input: "-data", wantErr: true},
+		{name: "leading dot", input: ".hidden", wantErr: true},
+		{name: "space", input: "my volume", wantErr: true},
+		{name: "slash", input: "host/path", wantErr: true},
+		{name: "encoded slash", input: "host%2Fpath", wantErr: true},
+		{name: "encoded traversal", input: "..%2F..%2Fetc", wantErr: true},
+		{name: "literal percent", input: "bad%zz", wantErr: true},
+		{name: "not decoded again", input: "a%41", wantErr: true},
+		{name: "too long", input: strings.Repeat("v", MaxVolumeNameLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeVolumeName(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
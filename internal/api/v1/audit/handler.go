@@ -0,0 +1,135 @@
+// Package audit provides HTTP handlers for the API audit trail
+// Serves entries recorded by the audit middleware to administrators
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// Handler handles audit log HTTP requests
+type Handler struct {
+	auditRepo *database.AuditLogRepository
+}
+
+// NewHandler creates a new audit handler
+func NewHandler(db *database.DB) *Handler {
+	return &Handler{
+		auditRepo: database.NewAuditLogRepository(db),
+	}
+}
+
+// ListAuditEntries returns paginated audit entries, newest first
+// Implements GET /api/v1/audit?user_id=&role=&method=&path=&status=&since=&until=
+func (h *Handler) ListAuditEntries(c *gin.Context) {
+	pagination, err := apiutils.ParsePaginationParams(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	options, filtersMap, err := parseAuditFilters(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+	options.Limit = pagination.Limit
+	options.Offset = pagination.Offset
+
+	entries, total, err := h.auditRepo.List(c.Request.Context(), options)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list audit entries", err)
+		return
+	}
+
+	apiEntries := make([]models.AuditEntryV1, 0, len(entries))
+	for _, entry := range entries {
+		apiEntries = append(apiEntries, convertToAPIAuditEntry(entry))
+	}
+
+	response := apiutils.BuildPagedResponse(apiEntries, pagination, int64(total), nil, filtersMap)
+	c.JSON(http.StatusOK, response)
+}
+
+// parseAuditFilters reads the audit query filters, returning them along with
+// the filters map echoed in the response
+func parseAuditFilters(c *gin.Context) (*database.AuditLogListOptions, map[string]interface{}, error) {
+	options := &database.AuditLogListOptions{
+		UserID:     strings.TrimSpace(c.Query("user_id")),
+		UserRole:   strings.TrimSpace(c.Query("role")),
+		Method:     strings.ToUpper(strings.TrimSpace(c.Query("method"))),
+		PathPrefix: strings.TrimSpace(c.Query("path")),
+	}
+
+	filtersMap := make(map[string]interface{})
+	if options.UserID != "" {
+		filtersMap["user_id"] = options.UserID
+	}
+	if options.UserRole != "" {
+		filtersMap["role"] = options.UserRole
+	}
+	if options.Method != "" {
+		filtersMap["method"] = options.Method
+	}
+	if options.PathPrefix != "" {
+		filtersMap["path"] = options.PathPrefix
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status, err := strconv.Atoi(statusStr)
+		if err != nil || status < 100 || status > 599 {
+			return nil, nil, fmt.Errorf("invalid status parameter: must be an HTTP status code")
+		}
+		options.StatusCode = status
+		filtersMap["status"] = status
+	}
+
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		since, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid since parameter: must be RFC3339")
+		}
+		options.Since = since
+		filtersMap["since"] = since
+	}
+
+	if untilStr := c.Query("until"); untilStr != "" {
+		until, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid until parameter: must be RFC3339")
+		}
+		options.Until = until
+		filtersMap["until"] = until
+	}
+
+	if !options.Since.IsZero() && !options.Until.IsZero() && !options.Since.Before(options.Until) {
+		return nil, nil, fmt.Errorf("invalid time range: since must be before until")
+	}
+
+	return options, filtersMap, nil
+}
+
+// convertToAPIAuditEntry converts a stored audit entry to API format
+func convertToAPIAuditEntry(entry *database.AuditLogEntry) models.AuditEntryV1 {
+	return models.AuditEntryV1{
+		ID:         entry.ID,
+		OccurredAt: entry.OccurredAt,
+		UserID:     entry.UserID,
+		UserRole:   entry.UserRole,
+		Method:     entry.Method,
+		Path:       entry.Path,
+		Route:      entry.Route,
+		StatusCode: entry.StatusCode,
+		Summary:    entry.Summary,
+		RequestID:  entry.RequestID,
+		ClientIP:   entry.ClientIP,
+	}
+}
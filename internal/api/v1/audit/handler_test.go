@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "audit-test-secret"
+
+// setupAuditTestDB creates a SQLite database with the audit log migration applied
+func setupAuditTestDB(t *testing.T) *database.DB {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "audit.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "007" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+
+	return db
+}
+
+// setupAuditRouter wires authentication and auditing the way the v1 router does,
+// with a stand-in prune endpoint
+func setupAuditRouter(t *testing.T, db *database.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	authConfig := &middleware.AuthConfig{
+		Enabled:      true,
+		Secret:       testSecret,
+		RequiredRole: middleware.RoleViewer,
+	}
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.AuditMiddleware(&middleware.AuditConfig{
+		Enabled:  true,
+		Recorder: database.NewAuditLogRepository(db),
+	}))
+	router.Use(middleware.AuthMiddleware(authConfig))
+
+	v1 := router.Group("/api/v1")
+	v1.POST("/volumes/prune", middleware.RequireRoleWhenEnabled(authConfig, middleware.RoleOperator), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"removed": []string{"old-data"}})
+	})
+	v1.GET("/volumes", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": []string{}})
+	})
+	NewRouter(db, middleware.RequireRoleWhenEnabled(authConfig, middleware.RoleAdmin)).RegisterRoutes(v1)
+
+	return router
+}
+
+func authorizedRequest(t *testing.T, method, target, userID string, role middleware.UserRole) *http.Request {
+	token, err := middleware.GenerateJWT(userID, role, testSecret, time.Hour)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+type listAuditResponse struct {
+	Data  []models.AuditEntryV1 `json:"data"`
+	Total int64                 `json:"total"`
+}
+
+func listAudit(t *testing.T, router *gin.Engine, query string) listAuditResponse {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, http.MethodGet, "/api/v1/audit"+query, "root", middleware.RoleAdmin))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response listAuditResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestAudit_PruneRecordsActingUser(t *testing.T) {
+	db := setupAuditTestDB(t)
+	router := setupAuditRouter(t, db)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, http.MethodPost, "/api/v1/volumes/prune?dry_run=false&api_key=hunter2", "alice", middleware.RoleOperator))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Reads are excluded by default, including the audit query itself
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, http.MethodGet, "/api/v1/volumes", "alice", middleware.RoleOperator))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	response := listAudit(t, router, "")
+	require.Len(t, response.Data, 1)
+	assert.EqualValues(t, 1, response.Total)
+
+	entry := response.Data[0]
+	assert.Equal(t, "alice", entry.UserID)
+	assert.Equal(t, "operator", entry.UserRole)
+	assert.Equal(t, http.MethodPost, entry.Method)
+	assert.Equal(t, "/api/v1/volumes/prune", entry.Path)
+	assert.Equal(t, http.StatusOK, entry.StatusCode)
+	assert.NotEmpty(t, entry.RequestID)
+	assert.Equal(t, "POST /api/v1/volumes/prune api_key=[REDACTED] dry_run=false", entry.Summary)
+	assert.NotContains(t, entry.Summary, "hunter2")
+}
+
+func TestAudit_RecordsDeniedAttempts(t *testing.T) {
+	db := setupAuditTestDB(t)
+	router := setupAuditRouter(t, db)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, http.MethodPost, "/api/v1/volumes/prune", "viewer-1", middleware.RoleViewer))
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	response := listAudit(t, router, "?user_id=viewer-1&status=403")
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "viewer", response.Data[0].UserRole)
+}
+
+func TestAudit_ListRequiresAdmin(t *testing.T) {
+	db := setupAuditTestDB(t)
+	router := setupAuditRouter(t, db)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, http.MethodGet, "/api/v1/audit", "alice", middleware.RoleOperator))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, http.MethodGet, "/api/v1/audit?since=yesterday", "root", middleware.RoleAdmin))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// Router handles audit log routes
+type Router struct {
+	handler   *Handler
+	adminOnly gin.HandlerFunc
+}
+
+// NewRouter creates a new audit router. adminOnly guards the audit trail;
+// pass nil to leave it unguarded.
+func NewRouter(db *database.DB, adminOnly gin.HandlerFunc) *Router {
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
+	return &Router{
+		handler:   NewHandler(db),
+		adminOnly: adminOnly,
+	}
+}
+
+// RegisterRoutes registers all audit log routes
+func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
+	audit := group.Group("/audit", r.adminOnly)
+	{
+		// List recorded actions with pagination and filtering
+		audit.GET("", r.handler.ListAuditEntries)
+	}
+}
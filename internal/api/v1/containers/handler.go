@@ -0,0 +1,215 @@
+// Package containers provides HTTP handlers for the container inventory
+// Serves containers synced into the database by the Docker events subsystem
+package containers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// allowedSortFields are the fields accepted by the sort query parameter
+var allowedSortFields = []string{"name", "image", "state", "created_at", "started_at", "active_mounts"}
+
+// allowedMountSortFields are the fields accepted by the mount report sort parameter
+var allowedMountSortFields = []string{"volume_name", "container_name", "mount_path", "access_mode", "created_at", "updated_at"}
+
+// Handler handles container inventory HTTP requests
+type Handler struct {
+	containerRepo *database.ContainerRepository
+}
+
+// NewHandler creates a new container handler
+func NewHandler(db *database.DB) *Handler {
+	return &Handler{
+		containerRepo: database.NewContainerRepository(db),
+	}
+}
+
+// ListContainers returns paginated containers from the synced inventory
+// Implements GET /api/v1/containers?state=&image=&label=
+func (h *Handler) ListContainers(c *gin.Context) {
+	pagination, err := apiutils.ParsePaginationParams(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	sortParams, err := apiutils.ParseSortParams(c, allowedSortFields)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	labels, err := parseLabelFilters(c.QueryArray("label"))
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	options := &database.ContainerListOptions{
+		State:  strings.TrimSpace(c.Query("state")),
+		Image:  strings.TrimSpace(c.Query("image")),
+		Labels: labels,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	}
+	// Only the first sort field is applied, matching the volumes list
+	if len(sortParams) > 0 {
+		options.SortBy = sortParams[0].Field
+		options.SortDesc = sortParams[0].Direction == "desc"
+	}
+
+	containers, total, err := h.containerRepo.List(c.Request.Context(), options)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list containers", err)
+		return
+	}
+
+	apiContainers := make([]models.ContainerV1, 0, len(containers))
+	for _, container := range containers {
+		apiContainers = append(apiContainers, convertToAPIContainer(container))
+	}
+
+	// Build filters map for response
+	filtersMap := make(map[string]interface{})
+	if options.State != "" {
+		filtersMap["state"] = options.State
+	}
+	if options.Image != "" {
+		filtersMap["image"] = options.Image
+	}
+	if len(labels) > 0 {
+		filtersMap["label"] = labels
+	}
+
+	response := apiutils.BuildPagedResponse(apiContainers, pagination, int64(total), sortParams, filtersMap)
+	c.JSON(http.StatusOK, response)
+}
+
+// ListMounts returns a paginated host-wide inventory of volume mounts across
+// all volumes and containers, with volume and container names resolved
+// Implements GET /api/v1/reports/mounts?volume=&container=&access_mode=&active=
+func (h *Handler) ListMounts(c *gin.Context) {
+	pagination, err := apiutils.ParsePaginationParams(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	sortParams, err := apiutils.ParseSortParams(c, allowedMountSortFields)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	options := &database.VolumeMountListOptions{
+		Volume:     strings.TrimSpace(c.Query("volume")),
+		Container:  strings.TrimSpace(c.Query("container")),
+		AccessMode: strings.TrimSpace(c.Query("access_mode")),
+		Limit:      pagination.Limit,
+		Offset:     pagination.Offset,
+	}
+	if options.AccessMode != "" && options.AccessMode != "rw" && options.AccessMode != "ro" {
+		apiutils.RespondWithBadRequest(c, "invalid access_mode parameter: must be 'rw' or 'ro'", nil)
+		return
+	}
+	if activeStr := c.Query("active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			apiutils.RespondWithBadRequest(c, "invalid active parameter: must be 'true' or 'false'", nil)
+			return
+		}
+		options.Active = &active
+	}
+	// Only the first sort field is applied, matching the container list
+	if len(sortParams) > 0 {
+		options.SortBy = sortParams[0].Field
+		options.SortDesc = sortParams[0].Direction == "desc"
+	}
+
+	mounts, total, err := h.containerRepo.ListMounts(c.Request.Context(), options)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list mounts", err)
+		return
+	}
+
+	apiMounts := make([]models.MountV1, 0, len(mounts))
+	for _, mount := range mounts {
+		apiMounts = append(apiMounts, convertToAPIMount(mount))
+	}
+
+	// Build filters map for response
+	filtersMap := make(map[string]interface{})
+	if options.Volume != "" {
+		filtersMap["volume"] = options.Volume
+	}
+	if options.Container != "" {
+		filtersMap["container"] = options.Container
+	}
+	if options.AccessMode != "" {
+		filtersMap["access_mode"] = options.AccessMode
+	}
+	if options.Active != nil {
+		filtersMap["active"] = *options.Active
+	}
+
+	apiutils.RespondWithPagedReport(c, apiMounts, pagination, int64(total), sortParams, filtersMap)
+}
+
+// parseLabelFilters parses repeated label parameters of the form key=value or key
+func parseLabelFilters(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, labelValue, _ := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid label filter %q: expected key=value or key", value)
+		}
+		labels[key] = strings.TrimSpace(labelValue)
+	}
+
+	return labels, nil
+}
+
+// convertToAPIContainer converts a synced container to API format
+func convertToAPIContainer(container *database.ContainerWithMounts) models.ContainerV1 {
+	return models.ContainerV1{
+		ContainerID:  container.ContainerID,
+		Name:         strings.TrimPrefix(container.Name, "/"),
+		Image:        container.Image,
+		State:        container.State,
+		Status:       container.Status,
+		Labels:       container.Labels,
+		StartedAt:    container.StartedAt,
+		FinishedAt:   container.FinishedAt,
+		ActiveMounts: container.ActiveMounts,
+		CreatedAt:    container.CreatedAt,
+		UpdatedAt:    container.UpdatedAt,
+	}
+}
+
+// convertToAPIMount converts a synced volume mount to API format
+func convertToAPIMount(mount *database.VolumeMountWithNames) models.MountV1 {
+	return models.MountV1{
+		VolumeID:      mount.VolumeID,
+		VolumeName:    mount.VolumeName,
+		ContainerID:   mount.ContainerID,
+		ContainerName: strings.TrimPrefix(mount.ContainerName, "/"),
+		MountPath:     mount.MountPath,
+		AccessMode:    mount.AccessMode,
+		Active:        mount.IsActive,
+		CreatedAt:     mount.CreatedAt,
+		UpdatedAt:     mount.UpdatedAt,
+	}
+}
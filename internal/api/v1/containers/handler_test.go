@@ -0,0 +1,333 @@
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupContainerTestDB creates a SQLite database seeded the way the events handler would
+func setupContainerTestDB(t *testing.T) *database.DB {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "containers.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	_, err = db.Exec(migrations[0].UpSQL)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := database.NewEventRepository(db)
+	now := time.Now()
+
+	for _, name := range []string{"web-data", "db-data"} {
+		require.NoError(t, repo.UpsertVolume(ctx, &database.Volume{
+			VolumeID: name, Name: name, Driver: "local", Status: "active", IsActive: true,
+			BaseModel: database.BaseModel{CreatedAt: now, UpdatedAt: now},
+		}))
+	}
+
+	containers := []*database.Container{
+		{ContainerID: "c-web", Name: "/web", Image: "nginx:1.25", State: "running",
+			Labels: database.Labels{"com.docker.compose.project": "shop"}},
+		{ContainerID: "c-db", Name: "/db", Image: "postgres:16", State: "running",
+			Labels: database.Labels{"com.docker.compose.project": "shop", "tier": "data"}},
+		{ContainerID: "c-old", Name: "/old-web", Image: "nginx", State: "exited"},
+		{ContainerID: "c-proxy", Name: "/proxy", Image: "nginx-proxy:latest", State: "running"},
+	}
+	for _, container := range containers {
+		container.IsActive = true
+		container.CreatedAt = now
+		container.UpdatedAt = now
+		require.NoError(t, repo.UpsertContainer(ctx, container))
+	}
+
+	mounts := []*database.VolumeMount{
+		{VolumeID: "web-data", ContainerID: "c-web", MountPath: "/usr/share/nginx/html", IsActive: true},
+		{VolumeID: "db-data", ContainerID: "c-db", MountPath: "/var/lib/postgresql/data", IsActive: true},
+		{VolumeID: "web-data", ContainerID: "c-db", MountPath: "/backup", IsActive: true},
+		{VolumeID: "web-data", ContainerID: "c-old", MountPath: "/usr/share/nginx/html", IsActive: false},
+	}
+	for _, mount := range mounts {
+		mount.AccessMode = "rw"
+		mount.CreatedAt = now
+		mount.UpdatedAt = now
+		require.NoError(t, repo.UpsertVolumeMount(ctx, mount))
+	}
+
+	return db
+}
+
+type listContainersResponse struct {
+	Data     []models.ContainerV1   `json:"data"`
+	Total    int64                  `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"page_size"`
+	Filters  map[string]interface{} `json:"filters"`
+}
+
+func listContainers(t *testing.T, db *database.DB, query string) (int, listContainersResponse) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewRouter(db).RegisterRoutes(engine.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/containers"+query, nil))
+
+	var response listContainersResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w.Code, response
+}
+
+func containerNames(containers []models.ContainerV1) []string {
+	names := make([]string, len(containers))
+	for i, container := range containers {
+		names[i] = container.Name
+	}
+	return names
+}
+
+func TestListContainers(t *testing.T) {
+	db := setupContainerTestDB(t)
+
+	status, response := listContainers(t, db, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(4), response.Total)
+	assert.Equal(t, []string{"db", "old-web", "proxy", "web"}, containerNames(response.Data))
+
+	mounts := make(map[string]int)
+	for _, container := range response.Data {
+		mounts[container.Name] = container.ActiveMounts
+	}
+	// Inactive mounts are not counted
+	assert.Equal(t, map[string]int{"db": 2, "old-web": 0, "proxy": 0, "web": 1}, mounts)
+}
+
+func TestListContainers_StateFilter(t *testing.T) {
+	db := setupContainerTestDB(t)
+
+	status, response := listContainers(t, db, "?state=exited")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(1), response.Total)
+	assert.Equal(t, []string{"old-web"}, containerNames(response.Data))
+	assert.Equal(t, "exited", response.Filters["state"])
+
+	status, response = listContainers(t, db, "?state=running&sort=active_mounts:desc")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(3), response.Total)
+	assert.Equal(t, []string{"db", "web", "proxy"}, containerNames(response.Data))
+}
+
+func TestListContainers_ImageFilter(t *testing.T) {
+	db := setupContainerTestDB(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantNames []string
+	}{
+		{name: "repository matches any tag", query: "?image=nginx", wantNames: []string{"old-web", "web"}},
+		{name: "exact image with tag", query: "?image=nginx:1.25", wantNames: []string{"web"}},
+		{name: "combined with state", query: "?image=nginx&state=running", wantNames: []string{"web"}},
+		{name: "no match", query: "?image=redis", wantNames: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := listContainers(t, db, tt.query)
+			require.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(len(tt.wantNames)), response.Total)
+			assert.Equal(t, tt.wantNames, containerNames(response.Data))
+		})
+	}
+}
+
+func TestListContainers_LabelFilterAndPagination(t *testing.T) {
+	db := setupContainerTestDB(t)
+
+	status, response := listContainers(t, db, "?label=com.docker.compose.project=shop&label=tier")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"db"}, containerNames(response.Data))
+	assert.Equal(t, "data", response.Data[0].Labels["tier"])
+
+	status, response = listContainers(t, db, "?page=2&page_size=3")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(4), response.Total)
+	assert.Equal(t, []string{"web"}, containerNames(response.Data))
+
+	status, _ = listContainers(t, db, "?label==shop")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = listContainers(t, db, "?sort=labels:asc")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+type listMountsResponse struct {
+	Data    []models.MountV1       `json:"data"`
+	Total   int64                  `json:"total"`
+	Filters map[string]interface{} `json:"filters"`
+}
+
+func listMounts(t *testing.T, db *database.DB, query string) (int, listMountsResponse) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewRouter(db).RegisterRoutes(engine.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/reports/mounts"+query, nil))
+
+	var response listMountsResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w.Code, response
+}
+
+// mountKeys identifies mounts as volume:container:path
+func mountKeys(mounts []models.MountV1) []string {
+	keys := make([]string, len(mounts))
+	for i, mount := range mounts {
+		keys[i] = mount.VolumeName + ":" + mount.ContainerName + ":" + mount.MountPath
+	}
+	return keys
+}
+
+// setupMountTestDB extends the container fixture with an active and an ended
+// read-only mount
+func setupMountTestDB(t *testing.T) *database.DB {
+	db := setupContainerTestDB(t)
+	repo := database.NewEventRepository(db)
+	now := time.Now()
+
+	for _, mount := range []*database.VolumeMount{
+		{VolumeID: "db-data", ContainerID: "c-proxy", MountPath: "/data", AccessMode: "ro", IsActive: true},
+		{VolumeID: "db-data", ContainerID: "c-old", MountPath: "/restore", AccessMode: "ro", IsActive: false},
+	} {
+		mount.CreatedAt = now
+		mount.UpdatedAt = now
+		require.NoError(t, repo.UpsertVolumeMount(context.Background(), mount))
+	}
+	return db
+}
+
+func TestListMounts(t *testing.T) {
+	db := setupMountTestDB(t)
+
+	status, response := listMounts(t, db, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(6), response.Total)
+	assert.Equal(t, []string{
+		"db-data:db:/var/lib/postgresql/data",
+		"db-data:old-web:/restore",
+		"db-data:proxy:/data",
+		"web-data:db:/backup",
+		"web-data:old-web:/usr/share/nginx/html",
+		"web-data:web:/usr/share/nginx/html",
+	}, mountKeys(response.Data))
+
+	proxy := response.Data[2]
+	assert.Equal(t, "db-data", proxy.VolumeID)
+	assert.Equal(t, "c-proxy", proxy.ContainerID)
+	assert.Equal(t, "ro", proxy.AccessMode)
+	assert.True(t, proxy.Active)
+}
+
+func TestListMounts_AccessModeAndActiveFilters(t *testing.T) {
+	db := setupMountTestDB(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantKeys []string
+	}{
+		{name: "read-only", query: "?access_mode=ro", wantKeys: []string{"db-data:old-web:/restore", "db-data:proxy:/data"}},
+		{name: "active only", query: "?active=true", wantKeys: []string{
+			"db-data:db:/var/lib/postgresql/data", "db-data:proxy:/data", "web-data:db:/backup", "web-data:web:/usr/share/nginx/html",
+		}},
+		{name: "ended only", query: "?active=false", wantKeys: []string{"db-data:old-web:/restore", "web-data:old-web:/usr/share/nginx/html"}},
+		{name: "active read-write", query: "?access_mode=rw&active=true", wantKeys: []string{
+			"db-data:db:/var/lib/postgresql/data", "web-data:db:/backup", "web-data:web:/usr/share/nginx/html",
+		}},
+		{name: "active read-only", query: "?access_mode=ro&active=true", wantKeys: []string{"db-data:proxy:/data"}},
+		{name: "by volume and container name", query: "?volume=web-data&container=/db", wantKeys: []string{"web-data:db:/backup"}},
+		{name: "by container ID", query: "?container=c-db&sort=mount_path:desc", wantKeys: []string{
+			"db-data:db:/var/lib/postgresql/data", "web-data:db:/backup",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := listMounts(t, db, tt.query)
+			require.Equal(t, http.StatusOK, status)
+			assert.Equal(t, int64(len(tt.wantKeys)), response.Total)
+			assert.Equal(t, tt.wantKeys, mountKeys(response.Data))
+		})
+	}
+
+	status, response := listMounts(t, db, "?active=false&access_mode=ro")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, response.Filters["active"])
+	assert.Equal(t, "ro", response.Filters["access_mode"])
+
+	status, response = listMounts(t, db, "?active=true&page=2&page_size=3")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int64(4), response.Total)
+	assert.Equal(t, []string{"web-data:web:/usr/share/nginx/html"}, mountKeys(response.Data))
+
+	for _, query := range []string{"?access_mode=rx", "?active=maybe", "?sort=container_id:asc"} {
+		status, _ := listMounts(t, db, query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
+}
+
+func TestListMounts_ContainerRenamed(t *testing.T) {
+	db := setupMountTestDB(t)
+	handler := events.NewEventHandlerService(nil, database.NewEventRepository(db), nil)
+
+	require.NoError(t, handler.ProcessEvent(context.Background(), &events.DockerEvent{
+		Type:       events.ContainerRenamed,
+		ID:         "c-web",
+		Name:       "storefront",
+		Action:     "rename",
+		Time:       time.Now(),
+		Attributes: map[string]string{"name": "storefront", "oldName": "/web"},
+	}))
+
+	status, response := listMounts(t, db, "?volume=web-data&active=true")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{
+		"web-data:db:/backup",
+		"web-data:storefront:/usr/share/nginx/html",
+	}, mountKeys(response.Data))
+
+	// Filtering by container name finds it under its new name only
+	status, response = listMounts(t, db, "?container=storefront")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"web-data:storefront:/usr/share/nginx/html"}, mountKeys(response.Data))
+	status, response = listMounts(t, db, "?container=web")
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, response.Data)
+
+	_, containers := listContainers(t, db, "?state=running")
+	assert.Contains(t, containerNames(containers.Data), "storefront")
+	assert.NotContains(t, containerNames(containers.Data), "web")
+}
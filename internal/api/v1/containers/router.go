@@ -0,0 +1,33 @@
+package containers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// Router handles container-related routes
+type Router struct {
+	handler *Handler
+}
+
+// NewRouter creates a new container router
+func NewRouter(db *database.DB) *Router {
+	return &Router{
+		handler: NewHandler(db),
+	}
+}
+
+// RegisterRoutes registers all container-related routes
+func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
+	containers := group.Group("/containers")
+	{
+		// List synced containers with pagination, sorting, and filtering
+		containers.GET("", r.handler.ListContainers)
+	}
+
+	reports := group.Group("/reports")
+	{
+		// Host-wide inventory of volume mounts across all containers
+		reports.GET("/mounts", r.handler.ListMounts)
+	}
+}
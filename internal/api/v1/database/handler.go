@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -14,15 +15,25 @@ type Handler struct {
 	migrationMgr *database.MigrationManager
 	volumeRepo   *database.VolumeRepository
 	scanJobRepo  *database.ScanJobRepository
+	optimizer    *database.Optimizer
+	queryStats   database.QueryStatsBackend
 }
 
 // NewHandler creates a new database handler
 func NewHandler(db *database.DB) *Handler {
+	return NewHandlerWithOptimizer(db, database.NewOptimizer(db, 0))
+}
+
+// NewHandlerWithOptimizer creates a new database handler that shares an optimizer,
+// so on-demand and scheduled optimization runs never overlap
+func NewHandlerWithOptimizer(db *database.DB, optimizer *database.Optimizer) *Handler {
 	return &Handler{
 		db:           db,
 		migrationMgr: database.NewMigrationManager(db),
 		volumeRepo:   database.NewVolumeRepository(db),
 		scanJobRepo:  database.NewScanJobRepository(db),
+		optimizer:    optimizer,
+		queryStats:   db,
 	}
 }
 
@@ -96,6 +107,42 @@ func (h *Handler) GetMigrationHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, history)
 }
 
+// GetMigration returns the SQL of a single migration version
+// @Summary Get migration SQL
+// @Description Get the up and down SQL of a migration version from the migration files and, when it has been applied, the checksum and rollback SQL recorded in the migration history
+// @Tags database
+// @Accept json
+// @Produce json
+// @Param version path string true "Migration version" example("002")
+// @Success 200 {object} database.MigrationDetail "Migration retrieved successfully"
+// @Failure 404 {object} ErrorResponse "Migration not found"
+// @Failure 500 {object} ErrorResponse "Failed to get migration"
+// @Router /database/migrations/{version} [get]
+func (h *Handler) GetMigration(c *gin.Context) {
+	version := c.Param("version")
+
+	detail, err := h.migrationMgr.GetMigration(version)
+	if err != nil {
+		if errors.Is(err, database.ErrMigrationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Migration not found",
+				"code":    "MIGRATION_NOT_FOUND",
+				"details": "Migration version " + version + " is neither in the migration files nor applied",
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get migration",
+			"code":    "MIGRATION_GET_ERROR",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
 // ApplyPendingMigrations applies all pending database migrations
 // @Summary Apply pending migrations
 // @Description Apply all pending database migrations. Use with caution in production environments.
@@ -280,7 +327,7 @@ func (h *Handler) TestDatabaseConnection(c *gin.Context) {
 	err := h.db.Ping()
 	if err != nil {
 		result.Success = false
-		result.Error = err.Error()
+		result.Error = h.db.RedactError(err)
 		result.Message = "Database connection failed"
 		c.JSON(http.StatusServiceUnavailable, result)
 		return
@@ -291,7 +338,7 @@ func (h *Handler) TestDatabaseConnection(c *gin.Context) {
 	err = h.db.QueryRow("SELECT 1").Scan(&testResult)
 	if err != nil {
 		result.Success = false
-		result.Error = err.Error()
+		result.Error = h.db.RedactError(err)
 		result.Message = "Database query test failed"
 		c.JSON(http.StatusServiceUnavailable, result)
 		return
@@ -376,14 +423,14 @@ func (h *Handler) GetTableSizes(c *gin.Context) {
 
 // GetSlowQueries returns information about slow queries
 // @Summary Get slow queries
-// @Description Get information about slow-running database queries for performance analysis
+// @Description Get the statements with the most total execution time from pg_stat_statements, with the state of the extension. When statistics are unavailable the list is empty and the response explains how to enable them.
 // @Tags database
 // @Accept json
 // @Produce json
 // @Param limit query int false "Maximum number of queries to return" default(10)
-// @Success 200 {array} SlowQueryInfo "Slow queries retrieved successfully"
+// @Success 200 {object} SlowQueriesResponse "Slow queries retrieved successfully"
 // @Failure 500 {object} ErrorResponse "Failed to get slow queries"
-// @Router /database/slow-queries [get]
+// @Router /database/performance/slow-queries [get]
 func (h *Handler) GetSlowQueries(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
@@ -391,70 +438,204 @@ func (h *Handler) GetSlowQueries(c *gin.Context) {
 		limit = 10
 	}
 
-	// Note: This requires pg_stat_statements extension to be enabled
-	query := `
-		SELECT
-			query,
-			calls,
-			total_time,
-			mean_time,
-			min_time,
-			max_time,
-			stddev_time,
-			rows,
-			100.0 * shared_blks_hit / nullif(shared_blks_hit + shared_blks_read, 0) AS hit_percent
-		FROM pg_stat_statements
-		ORDER BY total_time DESC
-		LIMIT $1
-	`
+	status, err := h.queryStats.QueryStatsStatus()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to check query statistics",
+			"code":    "QUERY_STATS_STATUS_ERROR",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := SlowQueriesResponse{Queries: []SlowQueryInfo{}, QueryStats: status}
+	if !status.Enabled() {
+		response.Message, response.Instructions = queryStatsHelp(status)
+		c.JSON(http.StatusOK, response)
+		return
+	}
 
-	rows, err := h.db.Query(query, limit)
+	slowQueries, err := h.queryStats.SlowQueries(limit)
 	if err != nil {
-		// If pg_stat_statements is not available, return empty array
-		c.JSON(http.StatusOK, []SlowQueryInfo{})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get slow queries",
+			"code":    "SLOW_QUERY_ERROR",
+			"details": err.Error(),
+		})
 		return
 	}
-	defer rows.Close()
 
-	var slowQueries []SlowQueryInfo
-	for rows.Next() {
-		var info SlowQueryInfo
-		var hitPercent interface{}
+	for _, q := range slowQueries {
+		response.Queries = append(response.Queries, SlowQueryInfo{
+			Query:      q.Query,
+			Calls:      q.Calls,
+			TotalTime:  q.TotalTimeMs,
+			MeanTime:   q.MeanTimeMs,
+			MinTime:    q.MinTimeMs,
+			MaxTime:    q.MaxTimeMs,
+			StddevTime: q.StddevMs,
+			Rows:       q.Rows,
+			HitPercent: q.HitPercent,
+		})
+	}
 
-		err := rows.Scan(
-			&info.Query,
-			&info.Calls,
-			&info.TotalTime,
-			&info.MeanTime,
-			&info.MinTime,
-			&info.MaxTime,
-			&info.StddevTime,
-			&info.Rows,
-			&hitPercent,
-		)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to scan slow query data",
-				"code":    "SLOW_QUERY_SCAN_ERROR",
-				"details": err.Error(),
-			})
-			return
+	c.JSON(http.StatusOK, response)
+}
+
+// EnableQueryStats creates the pg_stat_statements extension when the database
+// user is allowed to
+// @Summary Enable slow query statistics
+// @Description Create the pg_stat_statements extension in the database. Needs a PostgreSQL user allowed to create extensions; the server must also preload the library for statistics to be collected. Requires the admin role when authentication is enabled.
+// @Tags database
+// @Accept json
+// @Produce json
+// @Success 200 {object} QueryStatsResponse "Extension created"
+// @Failure 400 {object} ErrorResponse "The database is not PostgreSQL"
+// @Failure 403 {object} ErrorResponse "The database user may not create the extension"
+// @Failure 500 {object} ErrorResponse "Failed to create the extension"
+// @Router /database/performance/slow-queries/enable [post]
+func (h *Handler) EnableQueryStats(c *gin.Context) {
+	status, err := h.queryStats.QueryStatsStatus()
+	if err == nil && status.State == database.QueryStatsUnsupported {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": database.QueryStatsExtension + " requires PostgreSQL",
+			"code":  "QUERY_STATS_UNSUPPORTED",
+		})
+		return
+	}
+	if err == nil {
+		err = h.queryStats.EnableQueryStats()
+	}
+	if errors.Is(err, database.ErrQueryStatsPermission) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Database user may not create " + database.QueryStatsExtension,
+			"code":    "QUERY_STATS_PERMISSION_DENIED",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		status, err = h.queryStats.QueryStatsStatus()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to enable query statistics",
+			"code":    "QUERY_STATS_ENABLE_ERROR",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := QueryStatsResponse{QueryStats: status}
+	if !status.Enabled() {
+		response.Message, response.Instructions = queryStatsHelp(status)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// queryStatsHelp explains why slow query statistics are unavailable and how
+// to make them available
+func queryStatsHelp(status *database.QueryStatsStatus) (string, []string) {
+	switch status.State {
+	case database.QueryStatsUnsupported:
+		return "Slow query statistics are only available with PostgreSQL", []string{
+			"Set DB_TYPE=postgres to collect statement statistics with pg_stat_statements",
+		}
+	case database.QueryStatsUnavailable:
+		return "The pg_stat_statements extension is not installed on the database server", []string{
+			"Install the PostgreSQL contrib package for your server version (e.g. postgresql-contrib)",
+			"Add pg_stat_statements to shared_preload_libraries in postgresql.conf and restart PostgreSQL",
+			"Run CREATE EXTENSION pg_stat_statements, or POST /api/v1/database/performance/slow-queries/enable",
 		}
+	case database.QueryStatsNotInstalled:
+		return "The pg_stat_statements extension has not been created in this database", []string{
+			"Run CREATE EXTENSION pg_stat_statements as a user allowed to create extensions, POST /api/v1/database/performance/slow-queries/enable, or set DB_ENABLE_QUERY_STATS=true",
+			"Make sure pg_stat_statements is in shared_preload_libraries in postgresql.conf",
+		}
+	case database.QueryStatsNotLoaded:
+		return "The pg_stat_statements extension is installed but the server does not load it, so no statistics are collected", []string{
+			"Add pg_stat_statements to shared_preload_libraries in postgresql.conf",
+			"Restart PostgreSQL",
+		}
+	}
+	return "", nil
+}
 
-		if hitPercent != nil {
-			if hp, ok := hitPercent.(float64); ok {
-				info.HitPercent = &hp
-			}
+// OptimizeDatabase runs database maintenance on demand
+// @Summary Optimize database
+// @Description Run backend-specific maintenance (ANALYZE, incremental vacuum and planner optimization for SQLite; ANALYZE and extended statistics for PostgreSQL). SQLite runs in the background because vacuuming can lock the database; poll the status endpoint for the outcome. Requires the admin role when authentication is enabled.
+// @Tags database
+// @Accept json
+// @Produce json
+// @Success 200 {object} database.OptimizationResult "Optimization completed"
+// @Success 202 {object} OptimizeAcceptedResponse "Optimization started in the background"
+// @Failure 409 {object} ErrorResponse "Optimization already in progress"
+// @Failure 500 {object} ErrorResponse "Optimization failed"
+// @Router /database/optimize [post]
+func (h *Handler) OptimizeDatabase(c *gin.Context) {
+	// VACUUM can hold the SQLite write lock for a long time, keep it off the request path
+	if h.optimizer.IsSQLite() {
+		if err := h.optimizer.RunAsync(); err != nil {
+			h.respondOptimizeError(c, err)
+			return
 		}
 
-		slowQueries = append(slowQueries, info)
+		c.JSON(http.StatusAccepted, OptimizeAcceptedResponse{
+			Status:  "accepted",
+			Backend: string(database.DatabaseTypeSQLite),
+			Message: "Optimization started in the background; check /database/optimize/status for the result",
+		})
+		return
+	}
+
+	result, err := h.optimizer.Run()
+	if err != nil {
+		h.respondOptimizeError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, slowQueries)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetOptimizeStatus returns the state of the database optimizer
+// @Summary Get database optimization status
+// @Description Get whether an optimization is running and the outcome of the last run. Requires the admin role when authentication is enabled.
+// @Tags database
+// @Accept json
+// @Produce json
+// @Success 200 {object} database.OptimizerStatus "Optimizer status"
+// @Router /database/optimize/status [get]
+func (h *Handler) GetOptimizeStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.optimizer.Status())
+}
+
+// respondOptimizeError maps optimizer errors to HTTP responses
+func (h *Handler) respondOptimizeError(c *gin.Context, err error) {
+	if errors.Is(err, database.ErrOptimizationInProgress) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Database optimization already in progress",
+			"code":    "OPTIMIZATION_IN_PROGRESS",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   "Failed to optimize database",
+		"code":    "OPTIMIZATION_ERROR",
+		"details": err.Error(),
+	})
 }
 
 // Response types for API documentation
 
+// OptimizeAcceptedResponse is returned when an optimization has been started in the background
+type OptimizeAcceptedResponse struct {
+	Status  string `json:"status"`
+	Backend string `json:"backend"`
+	Message string `json:"message"`
+}
+
 // DatabaseStats represents comprehensive database statistics
 type DatabaseStats struct {
 	VolumeStats     *database.VolumeStats     `json:"volume_stats"`
@@ -482,6 +663,23 @@ type TableSizeInfo struct {
 	Correlation    *float64 `json:"correlation"`
 }
 
+// SlowQueriesResponse lists slow queries with the state of the statistics
+// behind them; Message and Instructions are set when none are available
+type SlowQueriesResponse struct {
+	Queries      []SlowQueryInfo            `json:"queries"`
+	QueryStats   *database.QueryStatsStatus `json:"query_stats"`
+	Message      string                     `json:"message,omitempty"`
+	Instructions []string                   `json:"instructions,omitempty"`
+}
+
+// QueryStatsResponse reports the state of slow query statistics after an
+// attempt to enable them
+type QueryStatsResponse struct {
+	QueryStats   *database.QueryStatsStatus `json:"query_stats"`
+	Message      string                     `json:"message,omitempty"`
+	Instructions []string                   `json:"instructions,omitempty"`
+}
+
 // SlowQueryInfo represents slow query information
 type SlowQueryInfo struct {
 	Query      string   `json:"query"`
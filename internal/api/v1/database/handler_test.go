@@ -1,10 +1,17 @@
 package database
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConnectionTestResult_Structure(t *testing.T) {
@@ -350,3 +357,267 @@ func BenchmarkSlowQueryInfo_Analysis(b *testing.B) {
 		_ = performanceScore > 10.0 // Slow query threshold
 	}
 }
+
+// stubOptimizationBackend stands in for a PostgreSQL database
+type stubOptimizationBackend struct {
+	calls int
+}
+
+func (s *stubOptimizationBackend) RunOptimization() (*database.OptimizationResult, error) {
+	s.calls++
+	return &database.OptimizationResult{
+		Backend:    database.DatabaseTypePostgreSQL,
+		Operations: []string{"ANALYZE volumes", "ANALYZE"},
+		Duration:   15 * time.Millisecond,
+	}, nil
+}
+
+func (s *stubOptimizationBackend) IsSQLite() bool { return false }
+
+func newOptimizeTestRouter(handler *Handler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/database/optimize", handler.OptimizeDatabase)
+	router.GET("/database/optimize/status", handler.GetOptimizeStatus)
+	return router
+}
+
+func TestHandler_OptimizeDatabase_PostgreSQLRunsInline(t *testing.T) {
+	backend := &stubOptimizationBackend{}
+	handler := NewHandlerWithOptimizer(&database.DB{}, database.NewOptimizer(backend, 0))
+	router := newOptimizeTestRouter(handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/database/optimize", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, backend.calls)
+
+	var result database.OptimizationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, database.DatabaseTypePostgreSQL, result.Backend)
+	assert.Equal(t, []string{"ANALYZE volumes", "ANALYZE"}, result.Operations)
+	assert.Equal(t, 15*time.Millisecond, result.Duration)
+}
+
+func TestHandler_OptimizeDatabase_SQLiteRunsInBackground(t *testing.T) {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "optimize.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	handler := NewHandler(db)
+	router := newOptimizeTestRouter(handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/database/optimize", nil))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var accepted OptimizeAcceptedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	assert.Equal(t, "accepted", accepted.Status)
+	assert.Equal(t, "sqlite", accepted.Backend)
+
+	// The background run reports the SQLite maintenance operations
+	var status database.OptimizerStatus
+	assert.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/database/optimize/status", nil))
+		status = database.OptimizerStatus{}
+		return json.Unmarshal(w.Body.Bytes(), &status) == nil && !status.Running && status.LastResult != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Empty(t, status.LastError)
+	assert.Equal(t, database.DatabaseTypeSQLite, status.LastResult.Backend)
+	assert.Contains(t, status.LastResult.Operations, "ANALYZE")
+	assert.Contains(t, status.LastResult.Operations, "PRAGMA incremental_vacuum")
+}
+
+// stubQueryStatsBackend stands in for a PostgreSQL database with or without
+// pg_stat_statements
+type stubQueryStatsBackend struct {
+	state     string
+	enableErr error
+	enabled   int
+}
+
+func (s *stubQueryStatsBackend) QueryStatsStatus() (*database.QueryStatsStatus, error) {
+	return &database.QueryStatsStatus{Extension: database.QueryStatsExtension, State: s.state}, nil
+}
+
+func (s *stubQueryStatsBackend) EnableQueryStats() error {
+	s.enabled++
+	if s.enableErr == nil {
+		s.state = database.QueryStatsNotLoaded
+	}
+	return s.enableErr
+}
+
+func (s *stubQueryStatsBackend) SlowQueries(limit int) ([]database.SlowQuery, error) {
+	hit := 99.5
+	return []database.SlowQuery{
+		{Query: "SELECT * FROM volumes WHERE name = $1", Calls: 120, TotalTimeMs: 240, MeanTimeMs: 2, Rows: 120, HitPercent: &hit},
+	}[:min(limit, 1)], nil
+}
+
+func newQueryStatsTestRouter(backend database.QueryStatsBackend) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(&database.DB{})
+	handler.queryStats = backend
+	router := gin.New()
+	router.GET("/database/performance/slow-queries", handler.GetSlowQueries)
+	router.POST("/database/performance/slow-queries/enable", handler.EnableQueryStats)
+	return router
+}
+
+func TestHandler_GetSlowQueries_QueryStats(t *testing.T) {
+	get := func(t *testing.T, backend database.QueryStatsBackend) SlowQueriesResponse {
+		w := httptest.NewRecorder()
+		newQueryStatsTestRouter(backend).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/database/performance/slow-queries", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response SlowQueriesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("extension present", func(t *testing.T) {
+		response := get(t, &stubQueryStatsBackend{state: database.QueryStatsEnabled})
+		assert.Equal(t, database.QueryStatsEnabled, response.QueryStats.State)
+		require.Len(t, response.Queries, 1)
+		assert.Equal(t, int64(120), response.Queries[0].Calls)
+		assert.Equal(t, 240.0, response.Queries[0].TotalTime)
+		require.NotNil(t, response.Queries[0].HitPercent)
+		assert.Empty(t, response.Message)
+		assert.Empty(t, response.Instructions)
+	})
+
+	t.Run("extension absent", func(t *testing.T) {
+		for _, state := range []string{database.QueryStatsUnavailable, database.QueryStatsNotInstalled, database.QueryStatsNotLoaded} {
+			response := get(t, &stubQueryStatsBackend{state: state})
+			assert.Equal(t, state, response.QueryStats.State)
+			assert.NotNil(t, response.Queries)
+			assert.Empty(t, response.Queries)
+			assert.NotEmpty(t, response.Message, state)
+			assert.NotEmpty(t, response.Instructions, state)
+		}
+		response := get(t, &stubQueryStatsBackend{state: database.QueryStatsNotLoaded})
+		assert.Contains(t, response.Instructions[0], "shared_preload_libraries")
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		db, err := database.NewDB(&database.Config{
+			Type:         database.DatabaseTypeSQLite,
+			Path:         filepath.Join(t.TempDir(), "slow-queries.db"),
+			MaxOpenConns: 1,
+			MaxIdleConns: 1,
+		})
+		require.NoError(t, err)
+		defer db.Close()
+
+		response := get(t, db)
+		assert.Equal(t, database.QueryStatsUnsupported, response.QueryStats.State)
+		assert.Empty(t, response.Queries)
+		assert.Contains(t, response.Message, "PostgreSQL")
+	})
+}
+
+func TestHandler_EnableQueryStats(t *testing.T) {
+	enable := func(t *testing.T, backend database.QueryStatsBackend, code int) []byte {
+		w := httptest.NewRecorder()
+		newQueryStatsTestRouter(backend).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/database/performance/slow-queries/enable", nil))
+		require.Equal(t, code, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+
+	t.Run("creates the extension", func(t *testing.T) {
+		backend := &stubQueryStatsBackend{state: database.QueryStatsNotInstalled}
+		var response QueryStatsResponse
+		require.NoError(t, json.Unmarshal(enable(t, backend, http.StatusOK), &response))
+		assert.Equal(t, 1, backend.enabled)
+
+		// Created, but the server still has to preload it
+		assert.Equal(t, database.QueryStatsNotLoaded, response.QueryStats.State)
+		assert.Contains(t, response.Instructions[0], "shared_preload_libraries")
+	})
+
+	t.Run("without privileges", func(t *testing.T) {
+		backend := &stubQueryStatsBackend{state: database.QueryStatsNotInstalled, enableErr: database.ErrQueryStatsPermission}
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(enable(t, backend, http.StatusForbidden), &response))
+		assert.Equal(t, "QUERY_STATS_PERMISSION_DENIED", response.Code)
+	})
+
+	t.Run("not PostgreSQL", func(t *testing.T) {
+		backend := &stubQueryStatsBackend{state: database.QueryStatsUnsupported}
+		enable(t, backend, http.StatusBadRequest)
+		assert.Equal(t, 0, backend.enabled)
+	})
+}
+
+func TestHandler_GetMigration(t *testing.T) {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "migrations.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	mm := database.NewMigrationManager(db)
+	migrations, err := mm.LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(migrations), 2)
+	require.NoError(t, mm.EnsureMigrationTable())
+	require.NoError(t, mm.ApplyMigration(migrations[0]))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewRouter(db, nil, nil).RegisterRoutes(router.Group(""))
+
+	get := func(version string) (*httptest.ResponseRecorder, database.MigrationDetail) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/database/migrations/"+version, nil))
+		var detail database.MigrationDetail
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+		}
+		return w, detail
+	}
+
+	// An applied version has both the file SQL and the recorded rollback SQL
+	w, detail := get(migrations[0].Version)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, migrations[0].Description, detail.Description)
+	require.NotNil(t, detail.UpSQL)
+	assert.Equal(t, migrations[0].UpSQL, *detail.UpSQL)
+	require.NotNil(t, detail.DownSQL)
+	assert.Equal(t, migrations[0].DownSQL, *detail.DownSQL)
+	require.NotNil(t, detail.Applied)
+	assert.Equal(t, detail.Checksum, detail.Applied.Checksum)
+	require.NotNil(t, detail.Applied.RollbackSQL)
+	assert.Equal(t, migrations[0].DownSQL, *detail.Applied.RollbackSQL)
+	assert.False(t, detail.ChecksumMismatch)
+
+	// A pending version only has the file SQL
+	w, detail = get(migrations[1].Version)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, detail.UpSQL)
+	assert.Equal(t, migrations[1].UpSQL, *detail.UpSQL)
+	assert.NotEmpty(t, detail.Checksum)
+	assert.Nil(t, detail.Applied)
+
+	// The static migration routes are not taken for versions
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/database/migrations/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "total_migrations")
+
+	w, _ = get("999")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "MIGRATION_NOT_FOUND")
+}
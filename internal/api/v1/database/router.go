@@ -7,13 +7,23 @@ import (
 
 // Router handles database-related routes
 type Router struct {
-	handler *Handler
+	handler   *Handler
+	adminOnly gin.HandlerFunc
 }
 
-// NewRouter creates a new database router
-func NewRouter(db *database.DB) *Router {
+// NewRouter creates a new database router. adminOnly guards maintenance
+// endpoints; pass nil to leave them unguarded.
+func NewRouter(db *database.DB, optimizer *database.Optimizer, adminOnly gin.HandlerFunc) *Router {
+	if optimizer == nil {
+		optimizer = database.NewOptimizer(db, 0)
+	}
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
 	return &Router{
-		handler: NewHandler(db),
+		handler:   NewHandlerWithOptimizer(db, optimizer),
+		adminOnly: adminOnly,
 	}
 }
 
@@ -33,6 +43,7 @@ func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 			migrations.GET("/status", r.handler.GetMigrationStatus)
 			migrations.GET("/history", r.handler.GetMigrationHistory)
 			migrations.POST("/apply", r.handler.ApplyPendingMigrations)
+			migrations.GET("/:version", r.handler.GetMigration)
 			migrations.POST("/:version/rollback", r.handler.RollbackMigration)
 		}
 
@@ -41,6 +52,14 @@ func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 		{
 			performance.GET("/table-sizes", r.handler.GetTableSizes)
 			performance.GET("/slow-queries", r.handler.GetSlowQueries)
+			performance.POST("/slow-queries/enable", r.adminOnly, r.handler.EnableQueryStats)
+		}
+
+		// Maintenance endpoints
+		optimize := database.Group("/optimize", r.adminOnly)
+		{
+			optimize.POST("", r.handler.OptimizeDatabase)
+			optimize.GET("/status", r.handler.GetOptimizeStatus)
 		}
 	}
 }
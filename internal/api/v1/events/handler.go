@@ -0,0 +1,88 @@
+// Package events provides HTTP handlers for the Docker events subsystem
+// Exposes audits of the database inventory it maintains and on-demand reconciliation
+package events
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/events"
+)
+
+// Handler handles events-related HTTP requests
+type Handler struct {
+	driftDetector events.DriftDetector   // Nil when events integration is disabled
+	reconciler    events.ReconcileRunner // Nil when events integration is disabled
+}
+
+// NewHandler creates a new events handler
+func NewHandler(driftDetector events.DriftDetector, reconciler events.ReconcileRunner) *Handler {
+	return &Handler{
+		driftDetector: driftDetector,
+		reconciler:    reconciler,
+	}
+}
+
+// GetDrift compares the database inventory with live Docker state without changing either
+// GET /api/v1/events/drift
+func (h *Handler) GetDrift(c *gin.Context) {
+	if h.driftDetector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Events integration not enabled",
+			"code":  "EVENTS_DISABLED",
+		})
+		return
+	}
+
+	report, err := h.driftDetector.DetectDrift(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to compare database with Docker",
+			"code":    "DRIFT_CHECK_FAILED",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Reconcile syncs the database inventory with Docker and reports the changes.
+// With dry_run=true the changes are computed and reported but not applied.
+// POST /api/v1/events/reconcile?dry_run=true
+func (h *Handler) Reconcile(c *gin.Context) {
+	if h.reconciler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Events integration not enabled",
+			"code":  "EVENTS_DISABLED",
+		})
+		return
+	}
+
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid dry_run parameter",
+				"code":    "INVALID_DRY_RUN",
+				"details": "dry_run must be true or false",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := h.reconciler.Reconcile(c.Request.Context(), dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to reconcile database with Docker",
+			"code":    "RECONCILE_FAILED",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDriftDetector struct {
+	report *events.DriftReport
+	err    error
+}
+
+func (s *stubDriftDetector) DetectDrift(ctx context.Context) (*events.DriftReport, error) {
+	return s.report, s.err
+}
+
+func serveDrift(t *testing.T, detector events.DriftDetector) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	NewRouter(detector, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events/drift", nil))
+	return w
+}
+
+func TestGetDrift(t *testing.T) {
+	detector := &stubDriftDetector{report: &events.DriftReport{
+		InSync: false,
+		Volumes: events.ResourceDrift{
+			DockerCount:     1,
+			MissingInDB:     []string{"new-volume"},
+			MissingInDocker: []string{},
+			Mismatches:      []events.FieldMismatch{},
+		},
+		Containers: events.ResourceDrift{
+			MissingInDB:     []string{},
+			MissingInDocker: []string{},
+			Mismatches:      []events.FieldMismatch{{ID: "c1", Field: "state", Database: "running", Docker: "exited"}},
+		},
+	}}
+
+	w := serveDrift(t, detector)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, false, body["in_sync"])
+
+	volumes := body["volumes"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"new-volume"}, volumes["missing_in_database"])
+
+	containers := body["containers"].(map[string]interface{})
+	mismatches := containers["mismatches"].([]interface{})
+	require.Len(t, mismatches, 1)
+	mismatch := mismatches[0].(map[string]interface{})
+	assert.Equal(t, "c1", mismatch["id"])
+	assert.Equal(t, "state", mismatch["field"])
+	assert.Equal(t, "running", mismatch["database"])
+	assert.Equal(t, "exited", mismatch["docker"])
+}
+
+func TestGetDrift_EventsDisabled(t *testing.T) {
+	w := serveDrift(t, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "EVENTS_DISABLED")
+}
+
+func TestGetDrift_Error(t *testing.T) {
+	w := serveDrift(t, &stubDriftDetector{err: errors.New("docker unavailable")})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "docker unavailable")
+}
+
+type stubReconciler struct {
+	dryRuns []bool
+	err     error
+}
+
+func (s *stubReconciler) Reconcile(ctx context.Context, dryRun bool) (*events.ReconcileReport, error) {
+	s.dryRuns = append(s.dryRuns, dryRun)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &events.ReconcileReport{
+		DryRun:  dryRun,
+		Volumes: events.ReconcileChanges{Added: []string{"new-volume"}, Updated: []string{}, Removed: []string{}},
+	}, nil
+}
+
+func serveReconcile(t *testing.T, reconciler events.ReconcileRunner, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	NewRouter(nil, reconciler, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, nil))
+	return w
+}
+
+func TestReconcile(t *testing.T) {
+	reconciler := &stubReconciler{}
+
+	w := serveReconcile(t, reconciler, "/api/v1/events/reconcile?dry_run=true")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, true, body["dry_run"])
+	volumes := body["volumes"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"new-volume"}, volumes["added"])
+
+	w = serveReconcile(t, reconciler, "/api/v1/events/reconcile")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []bool{true, false}, reconciler.dryRuns)
+}
+
+func TestReconcile_InvalidDryRun(t *testing.T) {
+	reconciler := &stubReconciler{}
+	w := serveReconcile(t, reconciler, "/api/v1/events/reconcile?dry_run=maybe")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_DRY_RUN")
+	assert.Empty(t, reconciler.dryRuns)
+}
+
+func TestReconcile_Errors(t *testing.T) {
+	w := serveReconcile(t, nil, "/api/v1/events/reconcile?dry_run=true")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "EVENTS_DISABLED")
+
+	w = serveReconcile(t, &stubReconciler{err: errors.New("docker unavailable")}, "/api/v1/events/reconcile")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "RECONCILE_FAILED")
+}
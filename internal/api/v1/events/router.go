@@ -0,0 +1,37 @@
+package events
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/events"
+)
+
+// Router handles events-related routes
+type Router struct {
+	handler   *Handler
+	adminOnly gin.HandlerFunc
+}
+
+// NewRouter creates a new events router. adminOnly guards on-demand
+// reconciliation; pass nil to leave it unguarded.
+func NewRouter(driftDetector events.DriftDetector, reconciler events.ReconcileRunner, adminOnly gin.HandlerFunc) *Router {
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
+	return &Router{
+		handler:   NewHandler(driftDetector, reconciler),
+		adminOnly: adminOnly,
+	}
+}
+
+// RegisterRoutes registers all events-related routes
+func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
+	events := group.Group("/events")
+	{
+		// Read-only comparison of synced inventory against Docker
+		events.GET("/drift", r.handler.GetDrift)
+
+		// Sync the inventory with Docker now, or preview the changes with dry_run=true
+		events.POST("/reconcile", r.adminOnly, r.handler.Reconcile)
+	}
+}
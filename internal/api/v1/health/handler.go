@@ -196,22 +196,21 @@ func (h *Handler) getEventsHealth() gin.H {
 	connected := h.eventsService.IsConnected()
 	lastEventTime := h.eventsService.GetLastEventTime()
 
+	// Disconnected and stalled streams are unhealthy; see EVENTS_HEALTH_WINDOW
 	status := "healthy"
-	if !connected {
+	if !connected || !metrics.Healthy {
 		status = "unhealthy"
-	} else if lastEventTime != nil && time.Since(*lastEventTime) > 5*time.Minute {
-		// If no events for 5+ minutes, consider it degraded (but not unhealthy)
-		status = "degraded"
 	}
 
 	healthInfo := gin.H{
-		"status":           status,
-		"connected":        connected,
-		"queue_size":       metrics.QueueSize,
-		"processed_total":  len(metrics.ProcessedTotal),
-		"errors_total":     len(metrics.ErrorsTotal),
-		"dropped_total":    metrics.DroppedTotal,
-		"reconnects_total": metrics.ReconnectsTotal,
+		"status":             status,
+		"connected":          connected,
+		"queue_size":         metrics.QueueSize,
+		"processed_total":    len(metrics.ProcessedTotal),
+		"errors_total":       len(metrics.ErrorsTotal),
+		"dropped_total":      metrics.DroppedTotal,
+		"reconnects_total":   metrics.ReconnectsTotal,
+		"reconnect_attempts": metrics.ReconnectAttempts,
 	}
 
 	// Add last event time if available
@@ -220,6 +219,16 @@ func (h *Handler) getEventsHealth() gin.H {
 		healthInfo["last_event_age_seconds"] = int64(time.Since(*lastEventTime).Seconds())
 	}
 
+	if metrics.UnhealthyReason != "" {
+		healthInfo["reason"] = metrics.UnhealthyReason
+	}
+	if metrics.LastEventAt != nil {
+		healthInfo["last_event_at"] = metrics.LastEventAt.UTC()
+	}
+	if metrics.LastConnectedAt != nil {
+		healthInfo["last_connected_at"] = metrics.LastConnectedAt.UTC()
+	}
+
 	// Add last reconnect time if available
 	if metrics.LastReconnectTime != nil {
 		healthInfo["last_reconnect_timestamp"] = metrics.LastReconnectTime.Unix()
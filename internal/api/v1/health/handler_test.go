@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/events"
 	"github.com/mantonx/volumeviz/internal/mocks"
 	"github.com/mantonx/volumeviz/internal/services"
 )
@@ -236,6 +238,65 @@ func TestHandler_GetLiveness(t *testing.T) {
 	}
 }
 
+func TestHandler_GetEventsHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		metrics        events.EventMetrics
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:           "live stream",
+			metrics:        events.EventMetrics{Connected: true, Healthy: true},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"status":"healthy"`},
+		},
+		{
+			name:           "stalled stream",
+			metrics:        events.EventMetrics{Connected: true, UnhealthyReason: "no Docker events or reconnects for 2h0m0s (health window 1h0m0s)"},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   []string{`"status":"unhealthy"`, `"connected":true`, `"reason":"no Docker events or reconnects`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(nil, nil, &fakeEventService{metrics: tt.metrics}, nil)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/health/events", nil)
+
+			handler.GetEventsHealth(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			for _, want := range tt.expectedBody {
+				if !contains(w.Body.String(), want) {
+					t.Errorf("Expected %s in %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+// fakeEventService reports fixed events metrics
+type fakeEventService struct {
+	metrics events.EventMetrics
+}
+
+func (f *fakeEventService) Start(ctx context.Context) error { return nil }
+func (f *fakeEventService) Stop(ctx context.Context) error  { return nil }
+func (f *fakeEventService) IsConnected() bool               { return f.metrics.Connected }
+func (f *fakeEventService) GetLastEventTime() *time.Time    { return f.metrics.LastEventTime }
+func (f *fakeEventService) GetMetrics() *events.EventMetrics {
+	metrics := f.metrics
+	return &metrics
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsSubstring(s, substr))
@@ -0,0 +1,226 @@
+// Package metadata provides HTTP handlers to export and import VolumeViz-managed
+// volume metadata, so annotations can be version-controlled and shared across hosts
+package metadata
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// Change actions reported by an import
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+// Handler handles metadata export and import HTTP requests
+type Handler struct {
+	db *database.DB
+}
+
+// NewHandler creates a new metadata handler
+func NewHandler(db *database.DB) *Handler {
+	return &Handler{db: db}
+}
+
+// ExportConfig returns every volume annotation as a metadata document, in JSON
+// or, with format=yaml, YAML. The output is sorted so unchanged metadata
+// exports identically.
+// Implements GET /api/v1/config/export?format=json|yaml
+func (h *Handler) ExportConfig(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "yaml" {
+		apiutils.RespondWithBadRequest(c, "format must be json or yaml", nil)
+		return
+	}
+
+	annotations, err := database.NewVolumeAnnotationRepository(h.db).ListAll(c.Request.Context())
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to export metadata", err)
+		return
+	}
+
+	doc := models.MetadataDocumentV1{
+		Version: models.MetadataDocumentVersion,
+		Volumes: make(map[string]map[string]string),
+	}
+	for _, a := range annotations {
+		if doc.Volumes[a.VolumeName] == nil {
+			doc.Volumes[a.VolumeName] = make(map[string]string)
+		}
+		doc.Volumes[a.VolumeName][a.Key] = a.Value
+	}
+
+	if format == "yaml" {
+		c.YAML(http.StatusOK, doc)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// ImportConfig applies a metadata document in a single transaction and reports
+// every annotation it creates, updates or deletes. Annotations missing from the
+// document are kept unless replace=true, which makes the document the complete
+// set. With dry_run=true the changes are reported but not applied. The body is
+// JSON, or YAML with a YAML content type.
+// Implements POST /api/v1/config/import?dry_run=&replace=
+func (h *Handler) ImportConfig(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	dryRun, err := parseBoolQuery(c, "dry_run")
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+	replace, err := parseBoolQuery(c, "replace")
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	var doc models.MetadataDocumentV1
+	bind := binding.JSON
+	if ct := c.ContentType(); ct == binding.MIMEYAML || ct == binding.MIMEYAML2 {
+		bind = binding.YAML
+	}
+	if err := c.ShouldBindWith(&doc, bind); err != nil {
+		apiutils.RespondWithBadRequest(c, "Invalid metadata document", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	if err := validateDocument(&doc); err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	tx, err := h.db.BeginTx()
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to import metadata", err)
+		return
+	}
+	defer tx.Rollback()
+	repo := database.NewVolumeAnnotationRepository(h.db).WithTx(tx)
+
+	existing, err := repo.ListAll(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to import metadata", err)
+		return
+	}
+
+	result := diffDocument(existing, doc, replace)
+	result.DryRun = dryRun
+
+	if !dryRun {
+		for _, change := range result.Changes {
+			if change.Action == actionDelete {
+				err = repo.DeleteAnnotation(ctx, change.Volume, change.Key)
+			} else {
+				err = repo.SetAnnotation(ctx, change.Volume, change.Key, change.NewValue)
+			}
+			if err != nil {
+				apiutils.RespondWithInternalError(c, "Failed to import metadata", err)
+				return
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			apiutils.RespondWithInternalError(c, "Failed to import metadata", err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// validateDocument checks the document version, volume names and annotation keys.
+// Volume names are normalized in place.
+func validateDocument(doc *models.MetadataDocumentV1) error {
+	if doc.Version != models.MetadataDocumentVersion {
+		return fmt.Errorf("unsupported metadata document version %d (expected %d)", doc.Version, models.MetadataDocumentVersion)
+	}
+
+	volumes := make(map[string]map[string]string, len(doc.Volumes))
+	for name, annotations := range doc.Volumes {
+		normalized, err := apiutils.NormalizeVolumeName(name)
+		if err != nil {
+			return err
+		}
+		for key := range annotations {
+			if key == "" {
+				return fmt.Errorf("volume %s has an annotation with an empty key", name)
+			}
+		}
+		volumes[normalized] = annotations
+	}
+	doc.Volumes = volumes
+
+	return nil
+}
+
+// diffDocument compares the stored annotations with a document and returns the
+// changes that make them match, ordered by volume name then key
+func diffDocument(existing []*database.VolumeAnnotation, doc models.MetadataDocumentV1, replace bool) models.MetadataImportResultV1 {
+	result := models.MetadataImportResultV1{
+		Replace: replace,
+		Changes: make([]models.MetadataChangeV1, 0),
+	}
+
+	current := make(map[[2]string]string, len(existing))
+	for _, a := range existing {
+		current[[2]string{a.VolumeName, a.Key}] = a.Value
+	}
+
+	for volume, annotations := range doc.Volumes {
+		for key, value := range annotations {
+			old, ok := current[[2]string{volume, key}]
+			switch {
+			case !ok:
+				result.Changes = append(result.Changes, models.MetadataChangeV1{Volume: volume, Key: key, Action: actionCreate, NewValue: value})
+				result.Created++
+			case old != value:
+				result.Changes = append(result.Changes, models.MetadataChangeV1{Volume: volume, Key: key, Action: actionUpdate, OldValue: old, NewValue: value})
+				result.Updated++
+			default:
+				result.Unchanged++
+			}
+		}
+	}
+
+	if replace {
+		for _, a := range existing {
+			if _, ok := doc.Volumes[a.VolumeName][a.Key]; !ok {
+				result.Changes = append(result.Changes, models.MetadataChangeV1{Volume: a.VolumeName, Key: a.Key, Action: actionDelete, OldValue: a.Value})
+				result.Deleted++
+			}
+		}
+	}
+
+	sort.Slice(result.Changes, func(i, j int) bool {
+		if result.Changes[i].Volume != result.Changes[j].Volume {
+			return result.Changes[i].Volume < result.Changes[j].Volume
+		}
+		return result.Changes[i].Key < result.Changes[j].Key
+	})
+
+	return result
+}
+
+// parseBoolQuery parses an optional boolean query parameter, defaulting to false
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return value, nil
+}
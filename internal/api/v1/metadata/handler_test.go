@@ -0,0 +1,175 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupMetadataTestDB creates a SQLite database with the annotation migration applied
+func setupMetadataTestDB(t *testing.T) *database.DB {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "metadata.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "006" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+
+	return db
+}
+
+func setupMetadataRouter(db *database.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewRouter(db, nil).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func exportConfig(t *testing.T, router *gin.Engine, format string) string {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/config/export?format="+format, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	return w.Body.String()
+}
+
+func importConfig(t *testing.T, router *gin.Engine, query, contentType, body string) models.MetadataImportResultV1 {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/config/import"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result models.MetadataImportResultV1
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	return result
+}
+
+func seedAnnotations(t *testing.T, db *database.DB) {
+	repo := database.NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+	require.NoError(t, repo.SetAlias(ctx, "app_data_1a2b", "app_data"))
+	require.NoError(t, repo.SetAnnotation(ctx, "app_data_1a2b", database.AnnotationKeyPinned, "true"))
+	require.NoError(t, repo.SetAnnotation(ctx, "build_cache", database.AnnotationKeyRetention, "7d"))
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		format      string
+		contentType string
+	}{
+		{format: "json", contentType: "application/json"},
+		{format: "yaml", contentType: "application/yaml"},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			source := setupMetadataTestDB(t)
+			seedAnnotations(t, source)
+			exported := exportConfig(t, setupMetadataRouter(source), tt.format)
+
+			target := setupMetadataRouter(setupMetadataTestDB(t))
+			result := importConfig(t, target, "", tt.contentType, exported)
+			assert.False(t, result.DryRun)
+			assert.Equal(t, 3, result.Created)
+			assert.Len(t, result.Changes, 3)
+			assert.Equal(t, exported, exportConfig(t, target, tt.format))
+
+			// Importing the same document again changes nothing
+			again := importConfig(t, target, "", tt.contentType, exported)
+			assert.Empty(t, again.Changes)
+			assert.Equal(t, 3, again.Unchanged)
+			assert.Equal(t, exported, exportConfig(t, target, tt.format))
+		})
+	}
+}
+
+func TestExportConfig_Document(t *testing.T) {
+	db := setupMetadataTestDB(t)
+	seedAnnotations(t, db)
+
+	var doc models.MetadataDocumentV1
+	require.NoError(t, json.Unmarshal([]byte(exportConfig(t, setupMetadataRouter(db), "json")), &doc))
+	assert.Equal(t, models.MetadataDocumentVersion, doc.Version)
+	assert.Equal(t, map[string]map[string]string{
+		"app_data_1a2b": {"logical_key": "app_data", "pinned": "true"},
+		"build_cache":   {"retention": "7d"},
+	}, doc.Volumes)
+
+	w := httptest.NewRecorder()
+	setupMetadataRouter(db).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/config/export?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImportConfig_DiffAndDryRun(t *testing.T) {
+	db := setupMetadataTestDB(t)
+	seedAnnotations(t, db)
+	router := setupMetadataRouter(db)
+	before := exportConfig(t, router, "json")
+
+	doc := `{
+		"version": 1,
+		"volumes": {
+			"app_data_1a2b": {"logical_key": "app_data", "pinned": "false"},
+			"logs": {"retention": "72h"}
+		}
+	}`
+
+	result := importConfig(t, router, "?dry_run=true&replace=true", "application/json", doc)
+	assert.True(t, result.DryRun)
+	assert.True(t, result.Replace)
+	assert.Equal(t, []models.MetadataChangeV1{
+		{Volume: "app_data_1a2b", Key: "pinned", Action: "update", OldValue: "true", NewValue: "false"},
+		{Volume: "build_cache", Key: "retention", Action: "delete", OldValue: "7d"},
+		{Volume: "logs", Key: "retention", Action: "create", NewValue: "72h"},
+	}, result.Changes)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, before, exportConfig(t, router, "json"), "a dry run must not change anything")
+
+	// Without replace, annotations missing from the document are kept
+	result = importConfig(t, router, "", "application/json", doc)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 0, result.Deleted)
+
+	retention, err := database.NewVolumeAnnotationRepository(db).GetAnnotation(context.Background(), "build_cache", "retention")
+	require.NoError(t, err)
+	assert.Equal(t, "7d", retention)
+}
+
+func TestImportConfig_RejectsInvalidDocuments(t *testing.T) {
+	router := setupMetadataRouter(setupMetadataTestDB(t))
+
+	for name, body := range map[string]string{
+		"malformed":           `{"version": 1, "volumes": [`,
+		"unsupported version": `{"version": 2, "volumes": {}}`,
+		"invalid volume name": `{"version": 1, "volumes": {"../etc": {"pinned": "true"}}}`,
+		"empty key":           `{"version": 1, "volumes": {"logs": {"": "x"}}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/config/import", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
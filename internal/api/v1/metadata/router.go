@@ -0,0 +1,37 @@
+package metadata
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// Router handles metadata export and import routes
+type Router struct {
+	handler   *Handler
+	adminOnly gin.HandlerFunc
+}
+
+// NewRouter creates a new metadata router. adminOnly guards export and import;
+// pass nil to leave them unguarded.
+func NewRouter(db *database.DB, adminOnly gin.HandlerFunc) *Router {
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
+	return &Router{
+		handler:   NewHandler(db),
+		adminOnly: adminOnly,
+	}
+}
+
+// RegisterRoutes registers all metadata routes
+func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
+	config := group.Group("/config", r.adminOnly)
+	{
+		// Export all managed volume metadata as JSON or YAML
+		config.GET("/export", r.handler.ExportConfig)
+
+		// Apply an exported document, optionally as a dry run
+		config.POST("/import", r.handler.ImportConfig)
+	}
+}
@@ -3,6 +3,8 @@
 package metrics
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -15,18 +17,49 @@ import (
 
 // Handler handles HTTP requests for volume metrics
 type Handler struct {
-	metricsRepo *database.VolumeMetricsRepository
+	metricsRepo    *database.VolumeMetricsRepository
+	annotationRepo *database.VolumeAnnotationRepository
+	useRollups     bool
 }
 
+// Time ranges at least this long are served from the rollup tables when
+// rollups are enabled; shorter ranges read the raw metrics
+const (
+	dailyRollupMinRange  = 30 * 24 * time.Hour
+	weeklyRollupMinRange = 365 * 24 * time.Hour
+)
+
 // NewHandler creates a new metrics handler
 func NewHandler(db *database.DB) *Handler {
 	return &Handler{
-		metricsRepo: database.NewVolumeMetricsRepository(db),
+		metricsRepo:    database.NewVolumeMetricsRepository(db),
+		annotationRepo: database.NewVolumeAnnotationRepository(db),
+	}
+}
+
+// UseRollups serves coarse ranges from the daily and weekly metrics rollups
+func (h *Handler) UseRollups() {
+	h.useRollups = true
+}
+
+// rollupGranularity returns the rollup serving a time range, or false if the
+// range is fine enough for raw metrics or rollups are disabled
+func (h *Handler) rollupGranularity(duration time.Duration) (database.RollupGranularity, bool) {
+	switch {
+	case !h.useRollups || duration < dailyRollupMinRange:
+		return "", false
+	case duration < weeklyRollupMinRange:
+		return database.RollupDaily, true
+	default:
+		return database.RollupWeekly, true
 	}
 }
 
 // GetVolumeMetrics returns historical metrics for a specific volume
 // GET /api/v1/volumes/{id}/metrics?timeRange=1d&interval=1h
+// With rollups enabled, ranges of 30d and more return daily (1y: weekly)
+// rollup buckets in "rollups" instead of raw "metrics"; "resolution" is always
+// set to raw, daily or weekly to say which
 func (h *Handler) GetVolumeMetrics(c *gin.Context) {
 	volumeID := c.Param("name")
 	if err := validateVolumeID(volumeID); err != nil {
@@ -61,21 +94,35 @@ func (h *Handler) GetVolumeMetrics(c *gin.Context) {
 	startTime := time.Now().Add(-duration)
 	endTime := time.Now()
 
+	response := gin.H{
+		"volume_id": volumeID,
+		"timeRange": timeRange,
+		"interval":  interval,
+		"startTime": startTime,
+		"endTime":   endTime,
+	}
+
+	if granularity, ok := h.rollupGranularity(duration); ok {
+		rollups, err := h.metricsRepo.GetRollups(c.Request.Context(), volumeID, granularity, startTime, endTime, 1000)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch metrics", "details": err.Error()})
+			return
+		}
+		response["resolution"] = string(granularity)
+		response["rollups"] = rollups
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
 	// Query historical metrics
 	metrics, err := h.metricsRepo.GetMetrics(c.Request.Context(), volumeID, startTime, endTime, 1000)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch metrics", "details": err.Error()})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"volume_id": volumeID,
-		"timeRange": timeRange,
-		"interval":  interval,
-		"startTime": startTime,
-		"endTime":   endTime,
-		"metrics":   metrics,
-	})
+	response["resolution"] = "raw"
+	response["metrics"] = metrics
+	c.JSON(http.StatusOK, response)
 }
 
 // GetVolumeTrends returns trend analysis for one or more volumes
@@ -148,7 +195,8 @@ func (h *Handler) GetVolumeTrends(c *gin.Context) {
 }
 
 // GetVolumeHistory returns paginated historical data
-// GET /api/v1/volumes/history?limit=100&offset=0&volumeId=vol1
+// GET /api/v1/volumes/history?limit=100&offset=0&volumeId=vol1&aggregate=logical
+// With aggregate=logical, history for every volume aliased to the same logical key is merged
 func (h *Handler) GetVolumeHistory(c *gin.Context) {
 	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
 	if err != nil {
@@ -170,6 +218,15 @@ func (h *Handler) GetVolumeHistory(c *gin.Context) {
 
 	volumeID := c.Query("volumeId")
 
+	aggregate, err := parseAggregateMode(c.Query("aggregate"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid aggregate parameter",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Validate pagination limits
 	if limit < 1 || limit > 1000 {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -193,7 +250,18 @@ func (h *Handler) GetVolumeHistory(c *gin.Context) {
 	endTime := time.Now()
 	startTime := endTime.Add(-90 * 24 * time.Hour) // Last 90 days
 
-	history, err := h.metricsRepo.GetMetrics(c.Request.Context(), volumeID, startTime, endTime, limit+offset)
+	var history []database.VolumeMetrics
+	logicalKey := ""
+	if aggregate {
+		logicalKey, err = h.annotationRepo.ResolveLogicalKey(c.Request.Context(), volumeID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve volume alias"})
+			return
+		}
+		history, err = h.metricsRepo.GetMetricsByLogicalKey(c.Request.Context(), logicalKey, startTime, endTime, limit+offset)
+	} else {
+		history, err = h.metricsRepo.GetMetrics(c.Request.Context(), volumeID, startTime, endTime, limit+offset)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch history"})
 		return
@@ -213,22 +281,39 @@ func (h *Handler) GetVolumeHistory(c *gin.Context) {
 		history = history[start:end]
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"history": history,
 		"pagination": gin.H{
 			"limit":  limit,
 			"offset": offset,
 			"total":  total,
 		},
-	})
+	}
+	if aggregate {
+		response["logical_key"] = logicalKey
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // GetGrowthRates returns growth rate analysis
-// GET /api/v1/volumes/growth-rates?period=daily&volumeIds=vol1,vol2
+// GET /api/v1/volumes/growth-rates?period=daily&volumeIds=vol1,vol2&aggregate=logical
+// With aggregate=logical, rates are computed per logical key across aliased volumes.
+// With rollups enabled, the monthly period of a single volume is computed from
+// daily rollups; logical aggregates always read the raw metrics.
 func (h *Handler) GetGrowthRates(c *gin.Context) {
 	period := c.DefaultQuery("period", "daily") // daily, weekly, monthly
 	volumeIDsParam := c.Query("volumeIds")
 
+	aggregate, err := parseAggregateMode(c.Query("aggregate"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid aggregate parameter",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	var volumeIDs []string
 	if volumeIDsParam != "" {
 		volumeIDs = parseVolumeIDs(volumeIDsParam)
@@ -241,6 +326,14 @@ func (h *Handler) GetGrowthRates(c *gin.Context) {
 		volumeIDs = volumes
 	}
 
+	if aggregate {
+		volumeIDs, err = h.resolveLogicalKeys(c, volumeIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve volume aliases"})
+			return
+		}
+	}
+
 	growthRates := make(map[string]interface{})
 	for _, volumeID := range volumeIDs {
 		// Get recent metrics to calculate growth rates
@@ -256,7 +349,20 @@ func (h *Handler) GetGrowthRates(c *gin.Context) {
 			startTime = endTime.Add(-24 * time.Hour)
 		}
 
-		metrics, err := h.metricsRepo.GetMetrics(c.Request.Context(), volumeID, startTime, endTime, 100)
+		if granularity, ok := h.rollupGranularity(endTime.Sub(startTime)); ok && !aggregate {
+			if rate, ok := h.rollupGrowthRate(c, volumeID, granularity, startTime, endTime); ok {
+				rate["period"] = period
+				growthRates[volumeID] = rate
+			}
+			continue
+		}
+
+		var metrics []database.VolumeMetrics
+		if aggregate {
+			metrics, err = h.metricsRepo.GetMetricsByLogicalKey(c.Request.Context(), volumeID, startTime, endTime, 100)
+		} else {
+			metrics, err = h.metricsRepo.GetMetrics(c.Request.Context(), volumeID, startTime, endTime, 100)
+		}
 		if err != nil || len(metrics) < 2 {
 			continue
 		}
@@ -278,15 +384,132 @@ func (h *Handler) GetGrowthRates(c *gin.Context) {
 			"dataPoints": len(metrics),
 			"startSize":  oldest.TotalSize,
 			"endSize":    latest.TotalSize,
+			"resolution": "raw",
 		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"period":      period,
+		"aggregate":   aggregate,
 		"growthRates": growthRates,
 	})
 }
 
+// rollupGrowthRate computes a growth rate from the first sample of the oldest
+// rollup bucket to the last sample of the newest, matching the raw calculation.
+// Only buckets lying wholly inside the period count, so samples from before it
+// never enter the rate. It reports false when there are fewer than two samples
+// or the query fails.
+func (h *Handler) rollupGrowthRate(c *gin.Context, volumeID string, granularity database.RollupGranularity, startTime, endTime time.Time) (map[string]interface{}, bool) {
+	rollups, err := h.metricsRepo.GetRollups(c.Request.Context(), volumeID, granularity, startTime, endTime, 1000)
+	if err != nil {
+		return nil, false
+	}
+	rollups = database.RollupsWithin(rollups, startTime, endTime)
+	if len(rollups) == 0 {
+		return nil, false
+	}
+
+	dataPoints := 0
+	for _, r := range rollups {
+		dataPoints += r.SampleCount
+	}
+	if dataPoints < 2 {
+		return nil, false
+	}
+
+	latest := rollups[0]
+	oldest := rollups[len(rollups)-1]
+	timeDiff := latest.LastAt.Sub(oldest.FirstAt).Hours()
+	sizeDiff := latest.LastSize - oldest.FirstSize
+
+	var rate float64
+	if timeDiff > 0 {
+		rate = float64(sizeDiff) / timeDiff // bytes per hour
+	}
+
+	return map[string]interface{}{
+		"rate":       rate,
+		"dataPoints": dataPoints,
+		"startSize":  oldest.FirstSize,
+		"endSize":    latest.LastSize,
+		"resolution": string(granularity),
+	}, true
+}
+
+// ListVolumeAliases returns all alias mappings grouped by logical key
+// GET /api/v1/volume-aliases
+func (h *Handler) ListVolumeAliases(c *gin.Context) {
+	aliases, err := h.annotationRepo.ListAliases(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list volume aliases", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"aliases": aliases,
+		"total":   len(aliases),
+	})
+}
+
+// SetVolumeAliasRequest is the body for mapping a volume name onto a logical key
+type SetVolumeAliasRequest struct {
+	LogicalKey string `json:"logical_key" binding:"required"`
+}
+
+// SetVolumeAlias maps a physical volume name onto a logical key
+// PUT /api/v1/volume-aliases/{name}
+func (h *Handler) SetVolumeAlias(c *gin.Context) {
+	volumeName := c.Param("name")
+	if err := validateVolumeID(volumeName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req SetVolumeAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "details": err.Error()})
+		return
+	}
+
+	logicalKey := strings.TrimSpace(req.LogicalKey)
+	if err := validateVolumeID(logicalKey); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid logical key", "details": err.Error()})
+		return
+	}
+
+	if err := h.annotationRepo.SetAlias(c.Request.Context(), volumeName, logicalKey); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set volume alias", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"volume_name": volumeName,
+		"logical_key": logicalKey,
+	})
+}
+
+// DeleteVolumeAlias removes the logical key mapping for a volume name
+// DELETE /api/v1/volume-aliases/{name}
+func (h *Handler) DeleteVolumeAlias(c *gin.Context) {
+	volumeName := c.Param("name")
+	if err := validateVolumeID(volumeName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.annotationRepo.DeleteAlias(c.Request.Context(), volumeName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "volume alias not found", "volume_name": volumeName})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete volume alias", "details": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetCapacityForecast returns capacity forecasting data
 // GET /api/v1/volumes/{id}/capacity-forecast?days=30
 func (h *Handler) GetCapacityForecast(c *gin.Context) {
@@ -372,6 +595,36 @@ func parseTimeRange(timeRange string) (time.Duration, error) {
 	}
 }
 
+// parseAggregateMode parses the aggregate query parameter
+// Returns true when results should be grouped by logical key
+func parseAggregateMode(param string) (bool, error) {
+	switch param {
+	case "", "none":
+		return false, nil
+	case "logical":
+		return true, nil
+	default:
+		return false, fmt.Errorf("aggregate must be one of: none, logical")
+	}
+}
+
+// resolveLogicalKeys maps volume names to their logical keys, removing duplicates
+func (h *Handler) resolveLogicalKeys(c *gin.Context, volumeIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(volumeIDs))
+	keys := make([]string, 0, len(volumeIDs))
+	for _, volumeID := range volumeIDs {
+		key, err := h.annotationRepo.ResolveLogicalKey(c.Request.Context(), volumeID)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
 func parseVolumeIDs(param string) []string {
 	// Split by comma and trim whitespace
 	var ids []string
@@ -18,6 +18,12 @@ func New(db *database.DB) *Router {
 	}
 }
 
+// UseRollups serves coarse history and growth ranges from the metrics rollup
+// tables. Only enable it when the rollup job is running.
+func (r *Router) UseRollups() {
+	r.handler.UseRollups()
+}
+
 // RegisterRoutes registers all metrics routes
 func (r *Router) RegisterRoutes(rg *gin.RouterGroup) {
 	// Volume-specific metrics
@@ -34,4 +40,12 @@ func (r *Router) RegisterRoutes(rg *gin.RouterGroup) {
 		volumeRoutes.GET("/history", r.handler.GetVolumeHistory)
 		volumeRoutes.GET("/growth-rates", r.handler.GetGrowthRates)
 	}
+
+	// Alias mappings grouping recreated volumes under one logical key
+	aliasRoutes := rg.Group("/volume-aliases")
+	{
+		aliasRoutes.GET("", r.handler.ListVolumeAliases)
+		aliasRoutes.PUT("/:name", r.handler.SetVolumeAlias)
+		aliasRoutes.DELETE("/:name", r.handler.DeleteVolumeAlias)
+	}
 }
@@ -5,11 +5,17 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/v1/audit"
+	"github.com/mantonx/volumeviz/internal/api/v1/containers"
 	"github.com/mantonx/volumeviz/internal/api/v1/database"
+	eventsAPI "github.com/mantonx/volumeviz/internal/api/v1/events"
 	"github.com/mantonx/volumeviz/internal/api/v1/health"
+	"github.com/mantonx/volumeviz/internal/api/v1/metadata"
 	"github.com/mantonx/volumeviz/internal/api/v1/metrics"
 	"github.com/mantonx/volumeviz/internal/api/v1/scan"
 	"github.com/mantonx/volumeviz/internal/api/v1/system"
@@ -40,12 +46,30 @@ type Router struct {
 	websocketHub  *websocket.Hub
 	scheduler     scheduler.ScanScheduler // Optional scan scheduler
 	eventsService events.EventService     // Optional events service
+	driftDetector events.DriftDetector    // Optional, available with the events service
+	reconciler    events.ReconcileRunner  // Optional, available with the events service
+	optimizer     *databasePkg.Optimizer
+	rollupJob     *databasePkg.RollupJob // Optional, available with a database
+	quarantineJob *volumes.QuarantineJob // Optional, set up with the volume routes
+	duplicateJob  *volumes.DuplicateJob  // Optional, set up with the volume routes
+	authConfig    *middleware.AuthConfig
+	sizePolicy    *config.SizePolicy
+	pruneConfig   config.PruneConfig
+	appConfig     *config.Config
+	staleAfter    time.Duration // Age at which listed sizes are flagged stale
+	probeWorkers  int           // Network volume mounts probed at a time
+	probeTimeout  time.Duration
+	attachmentCap int // Most attachments embedded in volume details
 }
 
 // NewRouter creates a new v1 API router
 func NewRouter(dockerService *services.DockerService, database *databasePkg.DB, config *config.Config) *Router {
 	// Initialize WebSocket hub
 	hub := websocket.NewHub()
+	hub.SetSizeChangeThreshold(config.Scan.SizeChangeThreshold)
+	if err := hub.SetCompression(config.Server.WebSocketCompression, config.Server.WebSocketCompressionLevel); err != nil {
+		log.Printf("[WARN] WebSocket compression disabled: %v", err)
+	}
 	go hub.Run()
 
 	// Initialize the scanner with all dependencies
@@ -53,14 +77,35 @@ func NewRouter(dockerService *services.DockerService, database *databasePkg.DB,
 	cache := cache.NewMemoryCache(1000)
 
 	// Use Prometheus metrics for production monitoring
-	metricsCollector := coreMetrics.NewPrometheusMetricsCollector(
+	metricsCollector := coreMetrics.NewPrometheusMetricsCollectorWithOptions(
 		"volumeviz",
 		"scanner",
 		prometheus.Labels{"instance": "main"},
+		coreMetrics.PrometheusOptions{
+			OmitVolumeLabels:     !config.Metrics.VolumeLabels,
+			DockerLabels:         config.Metrics.DockerLabels,
+			DockerLabelMaxValues: config.Metrics.DockerLabelMaxValues,
+		},
 	)
 
 	// Use default scanner config for now
 	scannerConfig := models.DefaultConfig()
+	scannerConfig.Scanning.ExternalSizeCommand = config.Scan.ExternalSizeCommand
+	scannerConfig.Scanning.ExcludeHidden = config.Scan.ExcludeHidden
+	scannerConfig.Cache.HistorySamples = config.Scan.CacheHistory
+	scannerConfig.Scanning.PathRewrites = config.Scan.PathRewrites
+	scannerConfig.Scanning.FilesystemNames = config.Scan.FilesystemNames
+	scannerConfig.Scanning.AvailabilityRecheck = config.Scan.MethodRecheckInterval
+	if mode := models.SpecialFileMode(config.Scan.SpecialFiles); mode.Valid() {
+		scannerConfig.Scanning.SpecialFiles = mode
+	} else {
+		log.Printf("[WARN] Unknown SCAN_SPECIAL_FILES %q, skipping special files", config.Scan.SpecialFiles)
+	}
+	if mode := models.SymlinkMode(config.Scan.Symlinks); mode.Valid() {
+		scannerConfig.Scanning.Symlinks = mode
+	} else {
+		log.Printf("[WARN] Unknown SCAN_SYMLINKS %q, not following symlinks", config.Scan.Symlinks)
+	}
 
 	volumeScanner := scanner.NewVolumeScanner(
 		dockerService,
@@ -69,6 +114,10 @@ func NewRouter(dockerService *services.DockerService, database *databasePkg.DB,
 		logger,
 		scannerConfig,
 	)
+	// Cache results of volumes that rarely change for longer, from their scan history
+	if vs, ok := volumeScanner.(*scanner.VolumeScanner); ok && database != nil {
+		vs.SetSizeHistory(scheduler.NewRepository(database))
+	}
 
 	// Initialize scan scheduler if enabled
 	var scanScheduler scheduler.ScanScheduler
@@ -89,6 +138,14 @@ func NewRouter(dockerService *services.DockerService, database *databasePkg.DB,
 		if err != nil {
 			log.Printf("[WARN] Failed to initialize scan scheduler: %v", err)
 		} else {
+			// Push significant size changes from scheduled scans to WebSocket clients
+			schedulerInstance.SetSizeReporter(hub)
+			// Honor per-volume scan_enabled, scan_methods_denied and scan_lock annotations
+			if database != nil {
+				schedulerInstance.SetScanToggles(databasePkg.NewVolumeAnnotationRepository(database))
+				schedulerInstance.SetMethodDenials(databasePkg.NewVolumeAnnotationRepository(database))
+				schedulerInstance.SetScanLocks(databasePkg.NewVolumeAnnotationRepository(database))
+			}
 			scanScheduler = schedulerInstance
 			// Start the scheduler
 			if err := scanScheduler.Start(context.Background()); err != nil {
@@ -101,6 +158,8 @@ func NewRouter(dockerService *services.DockerService, database *databasePkg.DB,
 
 	// Initialize events service if enabled
 	var eventsService events.EventService
+	var driftDetector events.DriftDetector
+	var reconciler events.ReconcileRunner
 	if config.Events.Enabled {
 		// Create event repository
 		eventRepo := databasePkg.NewEventRepository(database)
@@ -117,30 +176,74 @@ func NewRouter(dockerService *services.DockerService, database *databasePkg.DB,
 
 		// Create event handler service
 		eventHandler := events.NewEventHandlerService(dockerClient, eventRepo, eventMetrics)
+		if config.Events.ScanOnCreate {
+			if scanScheduler != nil {
+				eventHandler.SetScanOnCreate(scanScheduler, config.Events.ScanOnCreateDelay)
+				log.Printf("[INFO] Scanning new volumes %v after creation", config.Events.ScanOnCreateDelay)
+			} else {
+				log.Printf("[WARN] EVENTS_SCAN_ON_CREATE is set but the scan scheduler is not running")
+			}
+		}
 
 		// Create event reconciler
-		eventReconcileMetrics := &events.EventMetrics{
-			ProcessedTotal: make(map[events.EventType]int64),
-			ErrorsTotal:    make(map[string]int64),
-			ReconcileRuns:  make(map[string]int64),
+		eventReconciler := events.NewReconcilerService(dockerClient, eventRepo, &config.Events, eventMetrics)
+
+		// Services keeping per-volume state drop it when a volume is removed
+		for _, service := range []any{metricsCollector, scanScheduler, hub} {
+			if listener, ok := service.(events.VolumeRemovalListener); ok {
+				eventHandler.AddVolumeRemovalListener(listener)
+				eventReconciler.AddVolumeRemovalListener(listener)
+			}
 		}
-		eventReconciler := events.NewReconcilerService(dockerClient, eventRepo, &config.Events, eventReconcileMetrics, eventMetrics)
 
 		// Create events client
 		eventsClient := events.NewEventsClient(dockerClient, &config.Events, eventHandler, eventReconciler, eventMetrics)
 		eventsService = eventsClient
+		driftDetector = eventReconciler
+		reconciler = eventReconciler
 
 		log.Printf("[INFO] Docker events integration initialized")
 	}
 
+	// Shared optimizer for on-demand and scheduled database maintenance
+	optimizer := databasePkg.NewOptimizer(database, config.Database.OptimizeInterval)
+	optimizer.Start()
+	if config.Database.OptimizeInterval > 0 {
+		log.Printf("[INFO] Scheduled database optimization every %v", config.Database.OptimizeInterval)
+	}
+
+	// Background rollups serving coarse history and growth ranges
+	var rollupJob *databasePkg.RollupJob
+	if database != nil && config.Database.RollupInterval > 0 {
+		rollupJob = databasePkg.NewRollupJob(databasePkg.NewVolumeMetricsRepository(database), config.Database.RollupInterval)
+		rollupJob.Start()
+		log.Printf("[INFO] Refreshing volume metrics rollups every %v", config.Database.RollupInterval)
+	}
+
+	// Route on the raw path so encoded slashes in names (e.g. %2F) stay inside
+	// a single path parameter and are decoded and validated by the handlers
+	engine := gin.New()
+	engine.UseRawPath = true
+
 	router := &Router{
-		engine:        gin.New(),
+		engine:        engine,
 		dockerService: dockerService,
 		scanner:       volumeScanner,
 		database:      database,
 		websocketHub:  hub,
 		scheduler:     scanScheduler,
 		eventsService: eventsService,
+		driftDetector: driftDetector,
+		reconciler:    reconciler,
+		optimizer:     optimizer,
+		rollupJob:     rollupJob,
+		sizePolicy:    config.Scan.SizePolicy(),
+		pruneConfig:   config.Prune,
+		staleAfter:    config.Scan.StaleAfter,
+		probeWorkers:  config.Scan.ProbeConcurrency,
+		probeTimeout:  config.Scan.ProbeTimeout,
+		attachmentCap: config.Server.InlineAttachmentsMax,
+		appConfig:     config,
 	}
 
 	router.setupMiddleware(config)
@@ -169,6 +272,28 @@ func (r *Router) Scheduler() scheduler.ScanScheduler {
 	return r.scheduler
 }
 
+// Optimizer returns the database optimizer
+func (r *Router) Optimizer() *databasePkg.Optimizer {
+	return r.optimizer
+}
+
+// RollupJob returns the metrics rollup job, or nil if rollups are disabled
+func (r *Router) RollupJob() *databasePkg.RollupJob {
+	return r.rollupJob
+}
+
+// QuarantineJob returns the volume quarantine job, or nil if quarantine is
+// disabled or the server is read-only
+func (r *Router) QuarantineJob() *volumes.QuarantineJob {
+	return r.quarantineJob
+}
+
+// DuplicateJob returns the volume fingerprinting job, or nil if duplicate
+// detection is disabled
+func (r *Router) DuplicateJob() *volumes.DuplicateJob {
+	return r.duplicateJob
+}
+
 // setupMiddleware configures all middleware for the router
 func (r *Router) setupMiddleware(config *config.Config) {
 	// Core middleware
@@ -181,19 +306,35 @@ func (r *Router) setupMiddleware(config *config.Config) {
 
 	// Security middleware
 	r.engine.Use(middleware.RequestIDMiddleware())
+
+	// Slow request logging, after the request ID so entries can be correlated
+	r.engine.Use(middleware.SlowRequestMiddleware(&middleware.SlowRequestConfig{
+		Threshold: config.Server.SlowRequestThreshold,
+		// WebSocket connections stay open for their whole session
+		SkipPaths: []string{"/api/v1/ws"},
+	}))
 	r.engine.Use(middleware.SecurityHeadersMiddleware(nil)) // Use defaults
 
 	// CORS middleware with configuration
 	corsConfig := &middleware.CORSConfig{
 		AllowedOrigins:   config.CORS.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Requested-With"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Requested-With", middleware.SizeEncodingHeader},
 		ExposedHeaders:   []string{"X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}
 	r.engine.Use(middleware.CORSMiddleware(corsConfig))
 
+	// Size encoding for large byte counts
+	r.engine.Use(middleware.SizeEncodingMiddleware(config.Server.SizeEncoding))
+
+	// Caps on report responses
+	r.engine.Use(middleware.ReportLimitsMiddleware(config.Server.ReportMaxItems, config.Server.ReportMaxBytes))
+
+	// Cap on the Docker API calls of a request
+	r.engine.Use(middleware.DockerCallBudgetMiddleware(config.Server.DockerCallBudget))
+
 	// Rate limiting
 	rateLimitConfig := &middleware.RateLimitConfig{
 		Enabled:   config.RateLimit.Enabled,
@@ -204,6 +345,18 @@ func (r *Router) setupMiddleware(config *config.Config) {
 	}
 	r.engine.Use(middleware.RateLimitMiddleware(rateLimitConfig))
 
+	// Audit logging runs before authentication so rejected attempts are recorded
+	// too; it reads the acting user once the request has been handled
+	if r.database != nil {
+		auditConfig := &middleware.AuditConfig{
+			Enabled:      config.Audit.Enabled,
+			IncludeReads: config.Audit.IncludeReads,
+			SkipPaths:    []string{"/api/v1/health", "/health", "/metrics", "/api/docs", "/openapi"},
+			Recorder:     databasePkg.NewAuditLogRepository(r.database),
+		}
+		r.engine.Use(middleware.AuditMiddleware(auditConfig))
+	}
+
 	// Authentication middleware (if enabled)
 	authConfig := &middleware.AuthConfig{
 		Enabled:      config.Auth.Enabled,
@@ -216,8 +369,44 @@ func (r *Router) setupMiddleware(config *config.Config) {
 			"/api/docs",
 			"/openapi",
 		},
+		Authenticators: authenticators(config.Auth),
+		Anonymous: middleware.AnonymousPolicy{
+			Role:  middleware.UserRole(config.Auth.AnonymousRole),
+			Paths: config.Auth.AnonymousPaths,
+		},
 	}
 	r.engine.Use(middleware.AuthMiddleware(authConfig))
+	r.authConfig = authConfig
+
+	// Read-only mode, after authentication so unauthenticated writes still get a 401
+	r.engine.Use(middleware.ReadOnlyMiddleware(config.Server.ReadOnly, middleware.ReadOnlyReadRoutes))
+	if config.Server.ReadOnly {
+		log.Printf("[INFO] Read-only mode enabled; requests changing state are rejected")
+	}
+}
+
+// authenticators builds the authenticator chain in AUTH_PROVIDERS order; the
+// configuration has been validated, so unusable providers are not expected
+func authenticators(auth config.AuthConfig) []middleware.Authenticator {
+	chain := make([]middleware.Authenticator, 0, len(auth.Providers))
+	for _, provider := range auth.Providers {
+		switch strings.ToLower(strings.TrimSpace(provider)) {
+		case middleware.ProviderJWT:
+			chain = append(chain, middleware.NewJWTAuthenticator(auth.Secret))
+		case middleware.ProviderAPIKey:
+			parsed, err := auth.ParseAPIKeys()
+			if err != nil {
+				log.Printf("[WARN] Ignoring AUTH_API_KEYS: %v", err)
+				continue
+			}
+			keys := make([]middleware.APIKey, 0, len(parsed))
+			for _, key := range parsed {
+				keys = append(keys, middleware.APIKey{Name: key.Name, Role: middleware.UserRole(key.Role), Key: key.Key})
+			}
+			chain = append(chain, middleware.NewAPIKeyAuthenticator(keys))
+		}
+	}
+	return chain
 }
 
 // setupRoutes configures all API routes
@@ -225,8 +414,13 @@ func (r *Router) setupRoutes() {
 	// Root health endpoint for load balancers
 	r.engine.GET("/", r.getRootHealth)
 
-	// Prometheus metrics endpoint
-	r.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	// Prometheus metrics endpoint, behind its own credentials when METRICS_AUTH is set
+	r.engine.GET("/metrics", middleware.MetricsAuthMiddleware(middleware.MetricsAuthConfig{
+		Mode:     r.appConfig.Metrics.AuthMode,
+		Token:    r.appConfig.Metrics.Token,
+		Username: r.appConfig.Metrics.Username,
+		Password: r.appConfig.Metrics.Password,
+	}), gin.WrapH(promhttp.Handler()))
 
 	// Serve OpenAPI specification directly at /openapi route
 	r.engine.Static("/openapi", "./docs")
@@ -249,20 +443,84 @@ func (r *Router) setupRoutes() {
 		healthRouter := health.NewRouter(r.dockerService, r.database, r.eventsService, r.scheduler)
 		healthRouter.RegisterRoutes(v1)
 
-		volumesRouter := volumes.NewRouter(r.dockerService, r.websocketHub, r.database)
+		volumesRouter := volumes.NewRouter(r.dockerService, r.websocketHub, r.database, r.sizePolicy,
+			middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleOperator),
+			middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
+		if r.pruneConfig.ConfirmationRequired {
+			if err := volumesRouter.RequirePruneConfirmation(r.pruneConfig.ConfirmationTTL); err != nil {
+				log.Printf("[ERROR] Prune confirmation unavailable; prune requests will be refused: %v", err)
+			}
+		}
+		volumesRouter.SetSizeStaleAfter(r.staleAfter)
+		volumesRouter.SetProbeLimits(r.probeWorkers, r.probeTimeout)
+		if rewrites, err := config.ParsePathRewrites(r.appConfig.Scan.PathRewrites); err != nil {
+			log.Printf("[WARN] Ignoring path rewrites: %v", err)
+		} else {
+			volumesRouter.SetPathRewrites(rewrites)
+		}
+		volumesRouter.SetInlineAttachments(r.attachmentCap)
+		volumesRouter.SetAnonymousOrphanGrace(r.pruneConfig.AnonymousOrphanGrace)
+		volumesRouter.SetDetachWindow(r.pruneConfig.DetachWindow)
+		volumesRouter.SetCountStopped(r.pruneConfig.OrphanCountStopped)
+		if r.pruneConfig.QuarantineEnabled {
+			switch {
+			case r.database == nil:
+				log.Printf("[WARN] Volume quarantine requires a database; orphaned volumes will not be quarantined")
+			case r.appConfig != nil && r.appConfig.Server.ReadOnly:
+				// Sweeps quarantine and delete volumes; the report still lists quarantined ones
+				volumesRouter.EnableQuarantine(r.pruneConfig.QuarantineAfter, r.pruneConfig.QuarantineGrace)
+				log.Printf("[INFO] Read-only mode enabled; quarantine sweeps are not run")
+			default:
+				volumesRouter.EnableQuarantine(r.pruneConfig.QuarantineAfter, r.pruneConfig.QuarantineGrace)
+				r.quarantineJob = volumesRouter.NewQuarantineJob(r.pruneConfig.QuarantineInterval)
+				r.quarantineJob.Start()
+				log.Printf("[INFO] Quarantining orphaned volumes for %v before deletion, sweeping every %v",
+					r.pruneConfig.QuarantineGrace, r.pruneConfig.QuarantineInterval)
+			}
+		}
+		if scan := r.appConfig.Scan; scan.DuplicatesEnabled {
+			volumesRouter.EnableDuplicateDetection(scan.DuplicatesSampleFiles, scan.DuplicatesMaxFiles)
+			r.duplicateJob = volumesRouter.NewDuplicateJob(scan.DuplicatesInterval)
+			r.duplicateJob.Start()
+			log.Printf("[INFO] Fingerprinting volumes for duplicate detection every %v", scan.DuplicatesInterval)
+		}
 		volumesRouter.RegisterRoutes(v1)
 
-		systemRouter := system.NewRouter(r.dockerService)
+		containersRouter := containers.NewRouter(r.database)
+		containersRouter.RegisterRoutes(v1)
+
+		eventsRouter := eventsAPI.NewRouter(r.driftDetector, r.reconciler,
+			middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
+		eventsRouter.RegisterRoutes(v1)
+
+		systemRouter := system.NewRouter(r.dockerService, r.appConfig,
+			middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
 		systemRouter.RegisterRoutes(v1)
 
-		scanRouter := scan.NewRouter(r.scanner, r.websocketHub, r.database, r.scheduler)
+		scanRouter := scan.NewRouter(r.scanner, r.websocketHub, r.database, r.scheduler,
+			middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleOperator),
+			middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
+		scanRouter.SetAdminCheck(func(c *gin.Context) bool {
+			return middleware.HasRoleWhenEnabled(r.authConfig, c, middleware.RoleAdmin)
+		})
 		scanRouter.RegisterRoutes(v1)
 
-		databaseRouter := database.NewRouter(r.database)
+		databaseRouter := database.NewRouter(r.database, r.optimizer, middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
 		databaseRouter.RegisterRoutes(v1)
 
+		if r.database != nil {
+			auditRouter := audit.NewRouter(r.database, middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
+			auditRouter.RegisterRoutes(v1)
+
+			metadataRouter := metadata.NewRouter(r.database, middleware.RequireRoleWhenEnabled(r.authConfig, middleware.RoleAdmin))
+			metadataRouter.RegisterRoutes(v1)
+		}
+
 		// Initialize metrics router with database access
 		metricsRouter := metrics.New(r.database)
+		if r.rollupJob != nil {
+			metricsRouter.UseRollups()
+		}
 		metricsRouter.RegisterRoutes(v1)
 	}
 }
@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+)
+
+// GetVolumeCache returns the scan result cached for a volume, if any
+// GET /api/v1/volumes/:name/cache
+func (h *Handler) GetVolumeCache(c *gin.Context) {
+	inspector, ok := h.scanner.(interfaces.CacheInspector)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, models.ErrorResponse{
+			Error: "Inspecting the scan cache is not supported by this scanner",
+			Code:  "CACHE_INSPECTION_UNSUPPORTED",
+		})
+		return
+	}
+
+	volumeID, ok := cacheVolumeID(c)
+	if !ok {
+		return
+	}
+
+	result := inspector.CachedResult(volumeID)
+	c.JSON(http.StatusOK, models.VolumeCacheResponse{
+		VolumeID: volumeID,
+		Cached:   result != nil,
+		Result:   models.ConvertScanResult(result),
+	})
+}
+
+// ClearVolumeCache drops the scan result and size estimate cached for one
+// volume, so the next size request scans it again. rescan=true starts that
+// scan right away in the background, subject to the volume's scan_enabled
+// annotation like any other scan.
+// DELETE /api/v1/volumes/:name/cache?rescan=true
+func (h *Handler) ClearVolumeCache(c *gin.Context) {
+	volumeID, ok := cacheVolumeID(c)
+	if !ok {
+		return
+	}
+
+	rescan := false
+	if raw := c.Query("rescan"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid rescan parameter",
+				"code":    "INVALID_RESCAN",
+				"details": err.Error(),
+			})
+			return
+		}
+		rescan = parsed
+	}
+	// Refuse a rescan before clearing anything, so a 409 changes nothing
+	if rescan {
+		force, ok := parseForce(c)
+		if !ok || h.rejectScanDisabled(c, force, volumeID) {
+			return
+		}
+	}
+
+	response := models.VolumeCacheClearResponse{VolumeID: volumeID}
+	if inspector, ok := h.scanner.(interfaces.CacheInspector); ok {
+		wasCached := inspector.CachedResult(volumeID) != nil
+		response.WasCached = &wasCached
+	}
+
+	if err := h.scanner.ClearCache(volumeID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to clear cache",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !rescan {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	scanID, err := h.scanner.ScanVolumeAsync(h.scanContext(c, volumeID), volumeID)
+	if err != nil {
+		h.handleScanError(c, err)
+		return
+	}
+	response.ScanID = scanID
+	response.StatusURL = fmt.Sprintf("/api/v1/scans/%s/status", scanID)
+	c.JSON(http.StatusAccepted, response)
+}
+
+// cacheVolumeID returns the volume named in the path, writing a 400 when the
+// name is invalid
+func cacheVolumeID(c *gin.Context) (string, bool) {
+	volumeID, err := apiutils.NormalizeVolumeName(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid volume name",
+			Code:    "INVALID_VOLUME_NAME",
+			Details: map[string]any{"error": err.Error(), "pattern": apiutils.VolumeNamePattern},
+		})
+		return "", false
+	}
+	return volumeID, true
+}
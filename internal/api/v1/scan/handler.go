@@ -3,17 +3,26 @@
 package scan
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
 	coremodels "github.com/mantonx/volumeviz/internal/core/models"
 	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/mantonx/volumeviz/internal/scheduler"
-	"github.com/mantonx/volumeviz/internal/utils"
 	"github.com/mantonx/volumeviz/internal/websocket"
 )
 
@@ -24,6 +33,11 @@ type Handler struct {
 	hub         *websocket.Hub
 	metricsRepo *database.VolumeMetricsRepository
 	scheduler   scheduler.ScanScheduler // Optional scheduler for manual scan triggers
+
+	// Optional source of per-volume scan switches; without it every volume is scannable
+	annotations *database.VolumeAnnotationRepository
+	// isAdmin reports whether a request may override a disabled scan; nil allows everyone
+	isAdmin func(c *gin.Context) bool
 }
 
 // NewHandler creates a new scan handler
@@ -37,6 +51,12 @@ func NewHandler(scanner interfaces.VolumeScanner, hub *websocket.Hub, metricsRep
 	}
 }
 
+// SetAdminCheck sets how requests that may scan volumes with scanning
+// disabled (force=true) are recognized
+func (h *Handler) SetAdminCheck(isAdmin func(c *gin.Context) bool) {
+	h.isAdmin = isAdmin
+}
+
 // GetVolumeSize returns volume size information
 // @Summary Get volume size
 // @Description Get the current size and statistics of a Docker volume
@@ -44,6 +64,7 @@ func NewHandler(scanner interfaces.VolumeScanner, hub *websocket.Hub, metricsRep
 // @Accept json
 // @Produce json
 // @Param name path string true "Volume Name"
+// @Param mode query string false "full (default) or estimate for a fast sampled size" Enums(full, estimate)
 // @Success 200 {object} models.ScanResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
@@ -61,7 +82,36 @@ func (h *Handler) GetVolumeSize(c *gin.Context) {
 		return
 	}
 
-	result, err := h.scanner.ScanVolume(c.Request.Context(), volumeID)
+	volumeID, err := apiutils.NormalizeVolumeName(volumeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid volume name",
+			Code:    "INVALID_VOLUME_NAME",
+			Details: map[string]any{"error": err.Error(), "pattern": apiutils.VolumeNamePattern},
+		})
+		return
+	}
+
+	force, ok := parseForce(c)
+	if !ok || h.rejectScanDisabled(c, force, volumeID) {
+		return
+	}
+
+	switch mode := c.DefaultQuery("mode", "full"); mode {
+	case "full":
+	case "estimate":
+		h.getVolumeSizeEstimate(c, volumeID)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid scan mode",
+			Code:    "INVALID_SCAN_MODE",
+			Details: map[string]any{"mode": mode, "allowed": []string{"full", "estimate"}},
+		})
+		return
+	}
+
+	result, err := h.scanner.ScanVolume(h.scanContext(c, volumeID), volumeID)
 	if err != nil {
 		h.handleScanError(c, err)
 		// Broadcast scan error via WebSocket
@@ -105,11 +155,29 @@ func (h *Handler) GetVolumeSize(c *gin.Context) {
 			Duration:       result.Duration,
 		}
 		h.hub.BroadcastScanComplete(volumeID, wsResult)
+		h.hub.ReportVolumeSize(volumeID, result.TotalSize)
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// getVolumeSizeEstimate serves a sampled size estimate
+// Estimates are not saved to metrics history or broadcast, so trends only ever
+// reflect full scans
+func (h *Handler) getVolumeSizeEstimate(c *gin.Context, volumeID string) {
+	result, err := h.scanner.EstimateVolumeSize(c.Request.Context(), volumeID)
+	if err != nil {
+		h.handleScanError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ScanResponse{
+		VolumeID: volumeID,
+		Result:   models.ConvertScanResult(result),
+		Cached:   result.CacheHit,
+	})
+}
+
 // RefreshVolumeSize forces a refresh of volume size calculation
 // @Summary Refresh volume size
 // @Description Clear cache and recalculate volume size, optionally async
@@ -135,6 +203,21 @@ func (h *Handler) RefreshVolumeSize(c *gin.Context) {
 		return
 	}
 
+	volumeID, err := apiutils.NormalizeVolumeName(volumeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid volume name",
+			"code":    "INVALID_VOLUME_NAME",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	force, ok := parseForce(c)
+	if !ok || h.rejectScanDisabled(c, force, volumeID) {
+		return
+	}
+
 	var req coremodels.RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// If JSON binding fails, use defaults
@@ -142,7 +225,7 @@ func (h *Handler) RefreshVolumeSize(c *gin.Context) {
 	}
 
 	if req.Async {
-		scanID, err := h.scanner.ScanVolumeAsync(c.Request.Context(), volumeID)
+		scanID, err := h.scanner.ScanVolumeAsync(h.scanContext(c, volumeID), volumeID)
 		if err != nil {
 			h.handleScanError(c, err)
 			return
@@ -165,7 +248,7 @@ func (h *Handler) RefreshVolumeSize(c *gin.Context) {
 		return
 	}
 
-	result, err := h.scanner.ScanVolume(c.Request.Context(), volumeID)
+	result, err := h.scanner.ScanVolume(h.scanContext(c, volumeID), volumeID)
 	if err != nil {
 		h.handleScanError(c, err)
 		// Broadcast scan error via WebSocket
@@ -186,6 +269,7 @@ func (h *Handler) RefreshVolumeSize(c *gin.Context) {
 			Duration:       result.Duration,
 		}
 		h.hub.BroadcastScanComplete(volumeID, wsResult)
+		h.hub.ReportVolumeSize(volumeID, result.TotalSize)
 	}
 
 	response := models.ScanResponse{
@@ -253,7 +337,8 @@ func (h *Handler) GetScanStatus(c *gin.Context) {
 	})
 }
 
-// BulkScan performs bulk scanning of multiple volumes
+// BulkScan performs bulk scanning of multiple volumes, reporting each
+// volume's outcome; missing volumes are listed under not_found
 // POST /api/v1/volumes/bulk-scan
 func (h *Handler) BulkScan(c *gin.Context) {
 	var req models.BulkScanRequest
@@ -265,34 +350,35 @@ func (h *Handler) BulkScan(c *gin.Context) {
 		return
 	}
 
-	if len(req.VolumeIDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "At least one volume ID is required",
-			"code":  "EMPTY_VOLUME_LIST",
-		})
+	volumeIDs, ok := apiutils.BatchIDs(c, "volume_ids", req.VolumeIDs)
+	if !ok {
 		return
 	}
 
+	force, ok := parseForce(c)
+	if !ok || h.rejectScanDisabled(c, force, volumeIDs...) {
+		return
+	}
+
+	batch := apiutils.NewBatch()
 	if req.Async {
 		// For async bulk scan, start all scans and return scan IDs
-		scanIDs := make([]string, len(req.VolumeIDs))
-		for i, volumeID := range req.VolumeIDs {
-			scanID, err := h.scanner.ScanVolumeAsync(c.Request.Context(), volumeID)
+		scanIDs := make([]string, 0, len(volumeIDs))
+		for _, volumeID := range volumeIDs {
+			scanID, err := h.scanner.ScanVolumeAsync(h.scanContext(c, volumeID), volumeID)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   "Failed to start async scan",
-					"volume":  volumeID,
-					"details": err.Error(),
-				})
-				return
+				recordScanFailure(batch, volumeID, err)
+				continue
 			}
-			scanIDs[i] = scanID
+			scanIDs = append(scanIDs, scanID)
+			batch.Succeeded(volumeID, http.StatusAccepted)
 		}
 
-		c.JSON(http.StatusAccepted, gin.H{
-			"message":  "Bulk async scan started",
-			"scan_ids": scanIDs,
-			"total":    len(req.VolumeIDs),
+		apiutils.RespondWithBatch(c, batch, models.BulkScanStartedResponse{
+			Message:       "Bulk async scan started",
+			ScanIDs:       scanIDs,
+			Total:         len(volumeIDs),
+			BatchResultV1: batch.Result(),
 		})
 		return
 	}
@@ -300,44 +386,206 @@ func (h *Handler) BulkScan(c *gin.Context) {
 	// Synchronous bulk scan
 	results := make(map[string]any)
 	failed := make(map[string]string)
-	successCount := 0
 
-	for _, volumeID := range req.VolumeIDs {
-		result, err := h.scanner.ScanVolume(c.Request.Context(), volumeID)
+	for _, volumeID := range volumeIDs {
+		result, err := h.scanner.ScanVolume(h.scanContext(c, volumeID), volumeID)
 		if err != nil {
 			failed[volumeID] = err.Error()
+			recordScanFailure(batch, volumeID, err)
 		} else {
 			results[volumeID] = result
-			successCount++
+			batch.Succeeded(volumeID, http.StatusOK)
 		}
 	}
 
-	response := models.BulkScanResponse{
-		Results:  results,
-		Failed:   failed,
-		Total:    len(req.VolumeIDs),
-		Success:  successCount,
-		Failures: len(failed),
-	}
+	apiutils.RespondWithBatch(c, batch, models.BulkScanResponse{
+		Results:       results,
+		Failed:        failed,
+		Total:         len(volumeIDs),
+		Success:       len(results),
+		Failures:      len(failed),
+		BatchResultV1: batch.Result(),
+	})
+}
 
-	statusCode := http.StatusOK
-	if len(failed) > 0 && successCount == 0 {
-		statusCode = http.StatusInternalServerError
-	} else if len(failed) > 0 {
-		statusCode = http.StatusPartialContent
+// recordScanFailure records a failed scan in batch, as not found when the
+// volume does not exist or was removed during the scan
+func recordScanFailure(batch *apiutils.Batch, volumeID string, err error) {
+	switch coremodels.ClassifyScanError(err) {
+	case coremodels.ErrorCodeVolumeNotFound, coremodels.ErrorCodeVolumeRemoved:
+		batch.NotFound(volumeID)
+		return
 	}
-
-	c.JSON(statusCode, response)
+	batch.Failed(volumeID, err)
 }
 
-// GetScanMethods returns available scan methods
-// GET /api/v1/scan-methods
+// GetScanMethods returns available scan methods and, when the scheduler is
+// running, the current preference order
+// GET /api/v1/scans/methods (also /api/v1/scan-methods)
 func (h *Handler) GetScanMethods(c *gin.Context) {
 	methods := h.scanner.GetAvailableMethods()
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"methods": methods,
 		"total":   len(methods),
+	}
+	if h.scheduler != nil {
+		response["order"] = h.scheduler.GetMethodsOrder()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetVolumeScanMethods returns the full-scan methods a volume is scanned with,
+// in the order they are tried, and the methods denied for it
+// GET /api/v1/volumes/:name/scan-methods
+func (h *Handler) GetVolumeScanMethods(c *gin.Context) {
+	volumeID, err := apiutils.NormalizeVolumeName(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid volume name",
+			Code:    "INVALID_VOLUME_NAME",
+			Details: map[string]any{"error": err.Error(), "pattern": apiutils.VolumeNamePattern},
+		})
+		return
+	}
+	denied := interfaces.DeniedMethods(h.scanContext(c, volumeID))
+
+	preferred := ""
+	if h.scheduler != nil {
+		preferred = h.scheduler.MethodForVolume(volumeID)
+	}
+
+	// The scanner tries the preferred method, then the rest in its own order
+	methods := make([]string, 0)
+	available := h.scanner.GetAvailableMethods()
+	for _, info := range available {
+		if info.Name == preferred && info.Available && !slices.Contains(denied, info.Name) {
+			methods = append(methods, info.Name)
+		}
+	}
+	for _, info := range available {
+		if info.Name == preferred || !info.Available || slices.Contains(info.Features, "estimate_only") || slices.Contains(denied, info.Name) {
+			continue
+		}
+		methods = append(methods, info.Name)
+	}
+
+	if denied == nil {
+		denied = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"volume":  volumeID,
+		"methods": methods,
+		"denied":  denied,
+	})
+}
+
+// SetScanMethodsOrder replaces the scan method preference order at runtime
+// PUT /api/v1/scans/methods
+func (h *Handler) SetScanMethodsOrder(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scan scheduler not available",
+			"code":  "SCHEDULER_UNAVAILABLE",
+		})
+		return
+	}
+
+	var req models.ScanMethodsOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"code":    "INVALID_REQUEST",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.scheduler.SetMethodsOrder(req.Order); err != nil {
+		if errors.Is(err, scheduler.ErrInvalidMethodsOrder) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid scan methods order",
+				"code":    "INVALID_METHODS_ORDER",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to set scan methods order",
+			"code":    "METHODS_ORDER_FAILED",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order": h.scheduler.GetMethodsOrder(),
+	})
+}
+
+// GetVolumeManifest streams a gzip-compressed JSONL manifest of a volume's contents
+// GET /api/v1/volumes/{name}/manifest
+//
+// Each line is a {path, size, mtime, mode} object. Errors found before the first
+// entry get a normal JSON error response; a failure mid-stream ends the response
+// without the gzip trailer so clients see a truncated archive rather than a
+// complete-looking manifest.
+func (h *Handler) GetVolumeManifest(c *gin.Context) {
+	walker, ok := h.scanner.(interfaces.ManifestWalker)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, models.ErrorResponse{
+			Error: "Volume manifests are not supported by this scanner",
+			Code:  "MANIFEST_UNSUPPORTED",
+		})
+		return
+	}
+
+	volumeID, err := apiutils.NormalizeVolumeName(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid volume name",
+			Code:    "INVALID_VOLUME_NAME",
+			Details: map[string]any{"error": err.Error(), "pattern": apiutils.VolumeNamePattern},
+		})
+		return
+	}
+
+	var gz *gzip.Writer
+	var encoder *json.Encoder
+	start := func() {
+		c.Header("Content-Type", "application/gzip")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-manifest.jsonl.gz"`, volumeID))
+		c.Status(http.StatusOK)
+		gz = gzip.NewWriter(c.Writer)
+		encoder = json.NewEncoder(gz)
+	}
+
+	// Walking a large volume takes longer than the server write timeout
+	apiutils.DisableWriteTimeout(c)
+
+	entries := 0
+	err = walker.WalkManifest(c.Request.Context(), volumeID, func(entry interfaces.ManifestEntry) error {
+		if gz == nil {
+			start()
+		}
+		entries++
+		return encoder.Encode(entry)
 	})
+	if err != nil {
+		if gz == nil {
+			h.handleScanError(c, err)
+			return
+		}
+		log.Printf("[WARN] Volume manifest for %s aborted after %d entries: %v", volumeID, entries, err)
+		return
+	}
+
+	// Empty volumes still get a valid, empty manifest
+	if gz == nil {
+		start()
+	}
+	if err := gz.Close(); err != nil {
+		log.Printf("[WARN] Failed to finish volume manifest for %s: %v", volumeID, err)
+	}
 }
 
 // handleScanError handles scan errors with appropriate HTTP responses
@@ -371,6 +619,10 @@ func (h *Handler) handleScanError(c *gin.Context, err error) {
 		response["suggestion"] = "Check that the volume ID is correct"
 		c.JSON(http.StatusNotFound, response)
 
+	case coremodels.ErrorCodeVolumeRemoved:
+		response["suggestion"] = "The volume was removed while it was scanned"
+		c.JSON(http.StatusNotFound, response)
+
 	case coremodels.ErrorCodePermissionDenied:
 		response["suggestion"] = "Check VolumeViz permissions for accessing the volume"
 		c.JSON(http.StatusForbidden, response)
@@ -383,30 +635,102 @@ func (h *Handler) handleScanError(c *gin.Context, err error) {
 		response["suggestion"] = "Try again with a longer timeout or scan smaller directories"
 		c.JSON(http.StatusRequestTimeout, response)
 
+	case coremodels.ErrorCodeVolumeUnscannable:
+		response["suggestion"] = "Set SCAN_EXTERNAL_SIZE_COMMAND to size volumes without a local mountpoint"
+		c.JSON(http.StatusUnprocessableEntity, response)
+
 	default:
 		c.JSON(http.StatusInternalServerError, response)
 	}
 }
 
-// ValidateVolumeID validates a volume ID format
-func (h *Handler) ValidateVolumeID(volumeID string) error {
-	if volumeID == "" {
-		return fmt.Errorf("volume ID cannot be empty")
+// parseForce reads the force query parameter, writing a 400 when it is not a boolean
+func parseForce(c *gin.Context) (force, ok bool) {
+	raw := c.Query("force")
+	if raw == "" {
+		return false, true
 	}
 
-	if utils.ContainsAny(volumeID, "..", "/") {
-		return fmt.Errorf("volume ID contains invalid characters")
+	force, err := strconv.ParseBool(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid force parameter",
+			"code":    "INVALID_FORCE",
+			"details": err.Error(),
+		})
+		return false, false
+	}
+	return force, true
+}
+
+// overridesScanDisabled reports whether a request scans volumes even when
+// their scanning is switched off: only an admin asking with force=true does
+func (h *Handler) overridesScanDisabled(c *gin.Context, force bool) bool {
+	return force && (h.isAdmin == nil || h.isAdmin(c))
+}
+
+// rejectScanDisabled writes a 409 and returns true when scanning of any of
+// volumeIDs is switched off (see database.AnnotationKeyScanEnabled) and the
+// request does not override it. A failed lookup is logged and rejects nothing,
+// matching the scheduler.
+func (h *Handler) rejectScanDisabled(c *gin.Context, force bool, volumeIDs ...string) bool {
+	if h.annotations == nil || h.overridesScanDisabled(c, force) {
+		return false
+	}
+
+	var disabled []string
+	for _, volumeID := range volumeIDs {
+		enabled, err := h.annotations.IsScanEnabled(c.Request.Context(), volumeID)
+		if err != nil {
+			log.Printf("[WARN] Could not check whether scanning of volume %s is enabled: %v", volumeID, err)
+			continue
+		}
+		if !enabled {
+			disabled = append(disabled, volumeID)
+		}
 	}
 
-	if len(volumeID) > 255 {
-		return fmt.Errorf("volume ID too long (max 255 characters)")
+	if len(disabled) == 0 {
+		return false
+	}
+	respondScanDisabled(c, disabled)
+	return true
+}
+
+// scanContext returns the request context barring the scan methods denied for
+// a volume (see database.AnnotationKeyScanMethodsDenied). A failed lookup is
+// logged and denies nothing, matching the scheduler.
+func (h *Handler) scanContext(c *gin.Context, volumeID string) context.Context {
+	ctx := c.Request.Context()
+	if h.annotations == nil {
+		return ctx
 	}
+	denied, err := h.annotations.DeniedScanMethods(ctx, volumeID)
+	if err != nil {
+		log.Printf("[WARN] Could not look up denied scan methods of volume %s: %v", volumeID, err)
+		return ctx
+	}
+	return interfaces.WithDeniedMethods(ctx, denied)
+}
 
-	return nil
+// respondScanDisabled writes the 409 for volumes whose scanning is switched off
+func respondScanDisabled(c *gin.Context, volumes []string) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error":      "Scanning is disabled for this volume",
+		"code":       "SCAN_DISABLED",
+		"volumes":    volumes,
+		"suggestion": "Set the scan_enabled annotation to true, or scan anyway as an admin with force=true",
+	})
+}
+
+// ValidateVolumeID validates a volume ID format
+func (h *Handler) ValidateVolumeID(volumeID string) error {
+	_, err := apiutils.NormalizeVolumeName(volumeID)
+	return err
 }
 
 // TriggerVolumeScan enqueues a single volume for scanning via the scheduler
-// POST /api/v1/volumes/{name}/scan
+// POST /api/v1/volumes/{name}/scan?force=true
 func (h *Handler) TriggerVolumeScan(c *gin.Context) {
 	if h.scheduler == nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{
@@ -425,8 +749,9 @@ func (h *Handler) TriggerVolumeScan(c *gin.Context) {
 		return
 	}
 
-	// Validate volume name format
-	if err := h.ValidateVolumeID(volumeName); err != nil {
+	// Validate and decode volume name
+	volumeName, err := apiutils.NormalizeVolumeName(volumeName)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid volume name",
 			"code":    "INVALID_VOLUME_NAME",
@@ -435,10 +760,60 @@ func (h *Handler) TriggerVolumeScan(c *gin.Context) {
 		return
 	}
 
+	// force=true bypasses the per-volume minimum scan interval and, for admins,
+	// the volume's scan_enabled switch
+	force, ok := parseForce(c)
+	if !ok {
+		return
+	}
+
 	// Enqueue the volume for scanning
-	scanID, err := h.scheduler.EnqueueVolume(volumeName)
+	scanID, err := h.scheduler.EnqueueVolumeWithOptions(volumeName, scheduler.EnqueueOptions{
+		Force:                force,
+		OverrideScanDisabled: h.overridesScanDisabled(c, force),
+	})
 	if err != nil {
 		// Handle different error types
+		var throttled *scheduler.ThrottledError
+		if errors.As(err, &throttled) {
+			retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":               "Volume was scanned recently",
+				"code":                "SCAN_THROTTLED",
+				"details":             err.Error(),
+				"volume":              volumeName,
+				"retry_after_seconds": retryAfter,
+			})
+			return
+		}
+		if errors.Is(err, scheduler.ErrSchedulerPaused) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Scan scheduler is paused",
+				"code":    "SCHEDULER_PAUSED",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, scheduler.ErrScanDisabled) {
+			respondScanDisabled(c, []string{volumeName})
+			return
+		}
+		var locked *scheduler.LockedError
+		if errors.As(err, &locked) {
+			retryAfter := max(int(math.Ceil(time.Until(locked.Lock.Until).Seconds())), 1)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusConflict, gin.H{
+				"error":        "Volume is locked against scanning",
+				"code":         "VOLUME_LOCKED",
+				"details":      err.Error(),
+				"volume":       volumeName,
+				"holder":       locked.Lock.Holder,
+				"locked_until": locked.Lock.Until.UTC(),
+				"suggestion":   "Wait for the lock to expire, or release it with DELETE /api/v1/volumes/" + volumeName + "/lock",
+			})
+			return
+		}
 		if strings.Contains(err.Error(), "scheduler not running") {
 			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"error":   "Scan scheduler is not running",
@@ -499,6 +874,14 @@ func (h *Handler) TriggerAllVolumesScan(c *gin.Context) {
 	batchID, err := h.scheduler.EnqueueAllVolumes()
 	if err != nil {
 		// Handle different error types
+		if errors.Is(err, scheduler.ErrSchedulerPaused) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Scan scheduler is paused",
+				"code":    "SCHEDULER_PAUSED",
+				"details": err.Error(),
+			})
+			return
+		}
 		if strings.Contains(err.Error(), "scheduler not running") {
 			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"error":   "Scan scheduler is not running",
@@ -550,6 +933,67 @@ func (h *Handler) GetSchedulerStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
+// PauseScheduler pauses periodic and batch scanning without stopping workers or the API
+// POST /api/v1/scheduler/pause?allow_manual=true
+func (h *Handler) PauseScheduler(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scan scheduler not available",
+			"code":  "SCHEDULER_UNAVAILABLE",
+		})
+		return
+	}
+
+	// allow_manual=true keeps single-volume scans available while paused
+	allowManual := false
+	if raw := c.Query("allow_manual"); raw != "" {
+		var err error
+		allowManual, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid allow_manual parameter",
+				"code":    "INVALID_ALLOW_MANUAL",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.scheduler.Pause(allowManual); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Scan scheduler is not running",
+			"code":    "SCHEDULER_NOT_RUNNING",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.scheduler.GetStatus())
+}
+
+// ResumeScheduler resumes periodic and batch scanning after a pause
+// POST /api/v1/scheduler/resume
+func (h *Handler) ResumeScheduler(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scan scheduler not available",
+			"code":  "SCHEDULER_UNAVAILABLE",
+		})
+		return
+	}
+
+	if err := h.scheduler.Resume(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Scan scheduler is not running",
+			"code":    "SCHEDULER_NOT_RUNNING",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.scheduler.GetStatus())
+}
+
 // GetSchedulerMetrics returns metrics for the scan scheduler (for Prometheus)
 // GET /api/v1/scheduler/metrics
 func (h *Handler) GetSchedulerMetrics(c *gin.Context) {
@@ -1,21 +1,31 @@
 package scan
 
 import (
+	"bufio"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mantonx/volumeviz/internal/api/models"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
 	coremodels "github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/mantonx/volumeviz/internal/core/services/cache"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/scheduler"
 	"github.com/mantonx/volumeviz/internal/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockVolumeScanner implements interfaces.VolumeScanner for testing
@@ -54,6 +64,14 @@ func (m *MockVolumeScanner) ClearCache(volumeID string) error {
 	return args.Error(0)
 }
 
+func (m *MockVolumeScanner) EstimateVolumeSize(ctx context.Context, volumeID string) (*interfaces.ScanResult, error) {
+	args := m.Called(ctx, volumeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.ScanResult), args.Error(1)
+}
+
 func setupTestRouter(scanner interfaces.VolumeScanner) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -278,6 +296,96 @@ func TestHandler_BulkScan_Sync(t *testing.T) {
 	mockScanner.AssertExpectations(t)
 }
 
+func TestHandler_BulkScan_PartialSuccess(t *testing.T) {
+	mockScanner := &MockVolumeScanner{}
+	router := setupTestRouter(mockScanner)
+
+	mockScanner.On("ScanVolume", mock.Anything, "vol1").Return(&interfaces.ScanResult{VolumeID: "vol1", TotalSize: 1024}, nil)
+	mockScanner.On("ScanVolume", mock.Anything, "ghost").Return(nil, &coremodels.ScanError{
+		VolumeID: "ghost", Code: coremodels.ErrorCodeVolumeNotFound, Message: "volume not found",
+	})
+	mockScanner.On("ScanVolume", mock.Anything, "vol2").Return(nil, fmt.Errorf("all methods failed"))
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/volumes/bulk-scan", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"volume_ids": ["vol1", "ghost", "vol2", "vol1"]}`)
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+
+	var response models.BulkScanResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 3, response.Total, "duplicates are scanned once")
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, []string{"ghost"}, response.NotFound)
+	assert.Equal(t, map[string]string{"vol2": "all methods failed"}, response.Errors)
+	require.Len(t, response.Items, 3)
+	assert.Equal(t, models.BatchItemV1{ID: "vol1", Status: http.StatusOK}, response.Items[0])
+	assert.Equal(t, http.StatusNotFound, response.Items[1].Status)
+	assert.Equal(t, models.BatchItemV1{ID: "vol2", Status: http.StatusInternalServerError, Error: "all methods failed"}, response.Items[2])
+	mockScanner.AssertNumberOfCalls(t, "ScanVolume", 3)
+
+	// Nothing to scan is a bad request rather than an empty success
+	w = send(`{"volume_ids": []}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "volume_ids must list at least one item")
+}
+
+func TestHandler_ScanVolumeRemoved(t *testing.T) {
+	mockScanner := &MockVolumeScanner{}
+	router := setupTestRouter(mockScanner)
+	// Registered under :name, as the v1 router does
+	router.GET("/named/:name/size", NewHandler(mockScanner, &websocket.Hub{}, nil, nil).GetVolumeSize)
+
+	mockScanner.On("ScanVolume", mock.Anything, "gone").Return(nil, &coremodels.ScanError{
+		VolumeID: "gone", Code: coremodels.ErrorCodeVolumeRemoved, Message: "volume removed during scan",
+	})
+
+	req, _ := http.NewRequest("GET", "/named/gone/size", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), coremodels.ErrorCodeVolumeRemoved)
+
+	req, _ = http.NewRequest("POST", "/volumes/bulk-scan", bytes.NewBufferString(`{"volume_ids": ["gone"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response models.BulkScanResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"gone"}, response.NotFound)
+	assert.Empty(t, response.Errors)
+}
+
+func TestHandler_BulkScan_AsyncPartialStart(t *testing.T) {
+	mockScanner := &MockVolumeScanner{}
+	router := setupTestRouter(mockScanner)
+
+	mockScanner.On("ScanVolumeAsync", mock.Anything, "vol1").Return("scan-1", nil)
+	mockScanner.On("ScanVolumeAsync", mock.Anything, "vol2").Return("", fmt.Errorf("queue full"))
+	mockScanner.On("ScanVolumeAsync", mock.Anything, "vol3").Return("scan-3", nil)
+
+	req, _ := http.NewRequest("POST", "/volumes/bulk-scan", bytes.NewBufferString(`{"volume_ids": ["vol1", "vol2", "vol3"], "async": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+
+	var response models.BulkScanStartedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"scan-1", "scan-3"}, response.ScanIDs, "a failed start does not stop the rest")
+	assert.Equal(t, 3, response.Total)
+	assert.Equal(t, 2, response.Succeeded)
+	assert.Empty(t, response.NotFound)
+	assert.Equal(t, map[string]string{"vol2": "queue full"}, response.Errors)
+	assert.Equal(t, http.StatusAccepted, response.Items[0].Status)
+}
+
 func TestHandler_GetScanMethods(t *testing.T) {
 	mockScanner := &MockVolumeScanner{}
 	router := setupTestRouter(mockScanner)
@@ -319,3 +427,648 @@ func TestHandler_GetScanMethods(t *testing.T) {
 
 	mockScanner.AssertExpectations(t)
 }
+
+func TestHandler_GetVolumeSize_EstimateMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockScanner := &MockVolumeScanner{}
+	router := gin.New()
+	handler := NewHandler(mockScanner, nil, nil, nil)
+	router.GET("/volumes/:name/size", handler.GetVolumeSize)
+
+	estimate := &interfaces.ScanResult{
+		VolumeID:      "big-volume",
+		TotalSize:     50 * 1024 * 1024 * 1024,
+		Method:        "sample",
+		Estimated:     true,
+		SampleSize:    20,
+		MarginOfError: 2 * 1024 * 1024 * 1024,
+	}
+	mockScanner.On("EstimateVolumeSize", mock.Anything, "big-volume").Return(estimate, nil)
+
+	req, _ := http.NewRequest("GET", "/volumes/big-volume/size?mode=estimate", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ScanResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Result.Estimated)
+	assert.Equal(t, 20, response.Result.SampleSize)
+	assert.Equal(t, int64(2*1024*1024*1024), response.Result.MarginOfError)
+
+	// Estimates never trigger a full scan
+	mockScanner.AssertNotCalled(t, "ScanVolume", mock.Anything, mock.Anything)
+	mockScanner.AssertExpectations(t)
+}
+
+func TestHandler_GetVolumeSize_InvalidMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockScanner := &MockVolumeScanner{}
+	router := gin.New()
+	handler := NewHandler(mockScanner, nil, nil, nil)
+	router.GET("/volumes/:name/size", handler.GetVolumeSize)
+
+	req, _ := http.NewRequest("GET", "/volumes/big-volume/size?mode=guess", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response models.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "INVALID_SCAN_MODE", response.Code)
+}
+
+// manifestScanner serves fixed manifest entries, failing after failAfter entries when set
+type manifestScanner struct {
+	MockVolumeScanner
+	entries   []interfaces.ManifestEntry
+	failAfter int
+	err       error
+}
+
+func (m *manifestScanner) WalkManifest(ctx context.Context, volumeID string, fn func(interfaces.ManifestEntry) error) error {
+	for i, entry := range m.entries {
+		if m.err != nil && i == m.failAfter {
+			return m.err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	if m.err != nil && m.failAfter >= len(m.entries) {
+		return m.err
+	}
+	return nil
+}
+
+func TestHandler_GetVolumeManifest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mtime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []interfaces.ManifestEntry{
+		{Path: "data", Mode: "drwxr-xr-x", ModTime: mtime},
+		{Path: "data/file.bin", Size: 2048, Mode: "-rw-r--r--", ModTime: mtime},
+	}
+
+	serve := func(scanner interfaces.VolumeScanner, path string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.GET("/volumes/:name/manifest", NewHandler(scanner, nil, nil, nil).GetVolumeManifest)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	readManifest := func(t *testing.T, body []byte) ([]interfaces.ManifestEntry, error) {
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		var decoded []interfaces.ManifestEntry
+		scanner := bufio.NewScanner(gz)
+		for scanner.Scan() {
+			var entry interfaces.ManifestEntry
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			decoded = append(decoded, entry)
+		}
+		return decoded, scanner.Err()
+	}
+
+	t.Run("streams gzip JSONL", func(t *testing.T) {
+		w := serve(&manifestScanner{entries: entries}, "/volumes/app-data/manifest")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/gzip", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "app-data-manifest.jsonl.gz")
+
+		decoded, err := readManifest(t, w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, entries, decoded)
+	})
+
+	t.Run("empty volume", func(t *testing.T) {
+		w := serve(&manifestScanner{}, "/volumes/empty/manifest")
+		assert.Equal(t, http.StatusOK, w.Code)
+		decoded, err := readManifest(t, w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Empty(t, decoded)
+	})
+
+	t.Run("error before first entry", func(t *testing.T) {
+		scanErr := &coremodels.ScanError{Code: coremodels.ErrorCodePathValidationFailed, Message: "path validation failed"}
+		w := serve(&manifestScanner{err: scanErr}, "/volumes/missing/manifest")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), coremodels.ErrorCodePathValidationFailed)
+	})
+
+	t.Run("error mid-stream truncates the archive", func(t *testing.T) {
+		w := serve(&manifestScanner{entries: entries, failAfter: 1, err: context.Canceled}, "/volumes/app-data/manifest")
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, err := readManifest(t, w.Body.Bytes())
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported scanner", func(t *testing.T) {
+		w := serve(&MockVolumeScanner{}, "/volumes/app-data/manifest")
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+}
+
+func TestHandler_ScanMethodsOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockScanner := &MockVolumeScanner{}
+	mockScanner.On("GetAvailableMethods").Return([]interfaces.MethodInfo{
+		{Name: "diskus", Available: true, Performance: "fast", Accuracy: "high"},
+		{Name: "du", Available: true, Performance: "medium", Accuracy: "high"},
+		{Name: "native", Available: true, Performance: "slow", Accuracy: "high"},
+		{Name: "sample", Available: true, Accuracy: "estimated", Features: []string{"estimate_only"}},
+	})
+
+	scanScheduler, err := scheduler.NewScheduler(
+		scheduler.NewSchedulerConfig(&config.ScanConfig{MethodsOrder: []string{"diskus", "du", "native"}}),
+		mockScanner, nil, nil, nil,
+	)
+	require.NoError(t, err)
+
+	router := gin.New()
+	NewRouter(mockScanner, nil, nil, scanScheduler, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+
+	getMethods := func() map[string]any {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/scans/methods", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	putOrder := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/scans/methods", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	response := getMethods()
+	assert.Len(t, response["methods"], 4)
+	assert.Equal(t, float64(4), response["total"])
+	assert.Equal(t, []any{"diskus", "du", "native"}, response["order"])
+
+	// Reorder takes effect immediately
+	w := putOrder(`{"order": ["native", "DU"]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"order": ["native", "du"]}`, w.Body.String())
+	assert.Equal(t, []string{"native", "du"}, scanScheduler.GetMethodsOrder())
+	assert.Equal(t, []any{"native", "du"}, getMethods()["order"])
+
+	// Invalid orders are rejected and leave the current order in place
+	for _, body := range []string{
+		`{"order": []}`,
+		`{"order": ["rsync"]}`,
+		`{"order": ["sample"]}`,
+		`{"order": ["du", "du"]}`,
+		`not json`,
+	} {
+		w := putOrder(body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	assert.Equal(t, []string{"native", "du"}, scanScheduler.GetMethodsOrder())
+}
+
+// enqueueRecorder is a scheduler that records single-volume enqueues and, like
+// the real one, rejects volumes in disabled unless overridden
+type enqueueRecorder struct {
+	scheduler.ScanScheduler
+	disabled map[string]bool
+	enqueued []scheduler.EnqueueOptions
+}
+
+func (e *enqueueRecorder) EnqueueVolumeWithOptions(volumeName string, opts scheduler.EnqueueOptions) (string, error) {
+	if e.disabled[volumeName] && !opts.OverrideScanDisabled {
+		return "", fmt.Errorf("volume %s: %w", volumeName, scheduler.ErrScanDisabled)
+	}
+	e.enqueued = append(e.enqueued, opts)
+	return "scan-1", nil
+}
+
+func TestHandler_ScanDisabledVolume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "scan.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "006" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, database.NewVolumeAnnotationRepository(db).SetAnnotation(context.Background(), "postgres_data", database.AnnotationKeyScanEnabled, "false"))
+
+	mockScanner := &MockVolumeScanner{}
+	recorder := &enqueueRecorder{disabled: map[string]bool{"postgres_data": true}}
+	scanRouter := NewRouter(mockScanner, nil, db, recorder, nil, nil)
+	scanRouter.SetAdminCheck(func(c *gin.Context) bool { return c.GetHeader("X-Admin") == "true" })
+	router := gin.New()
+	scanRouter.RegisterRoutes(router.Group("/api/v1"))
+
+	send := func(method, path, body string, admin bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if admin {
+			req.Header.Set("X-Admin", "true")
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+	assertDisabled := func(w *httptest.ResponseRecorder, volumes ...string) {
+		t.Helper()
+		require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "SCAN_DISABLED", response["code"])
+		assert.ElementsMatch(t, volumes, response["volumes"])
+	}
+
+	// Every manual scan of the volume is rejected, even when forced by a non-admin
+	assertDisabled(send(http.MethodGet, "/api/v1/volumes/postgres_data/size", "", false), "postgres_data")
+	assertDisabled(send(http.MethodPost, "/api/v1/volumes/postgres_data/size/refresh", "{}", false), "postgres_data")
+	assertDisabled(send(http.MethodPost, "/api/v1/volumes/postgres_data/size/refresh?force=true", "{}", false), "postgres_data")
+	assertDisabled(send(http.MethodPost, "/api/v1/volumes/bulk-scan", `{"volume_ids": ["app_data", "postgres_data"]}`, false), "postgres_data")
+	assertDisabled(send(http.MethodPost, "/api/v1/volumes/postgres_data/scan?force=true", "", false), "postgres_data")
+	mockScanner.AssertNotCalled(t, "ScanVolume", mock.Anything, mock.Anything)
+	assert.Empty(t, recorder.enqueued)
+
+	// Without force an admin is rejected too
+	assertDisabled(send(http.MethodPost, "/api/v1/volumes/postgres_data/scan", "", true), "postgres_data")
+
+	// An admin forcing the scan overrides the switch
+	w := send(http.MethodPost, "/api/v1/volumes/postgres_data/scan?force=true", "", true)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Equal(t, []scheduler.EnqueueOptions{{Force: true, OverrideScanDisabled: true}}, recorder.enqueued)
+
+	result := &interfaces.ScanResult{VolumeID: "postgres_data", TotalSize: 1024, Method: "du", ScannedAt: time.Now()}
+	mockScanner.On("ClearCache", "postgres_data").Return(nil)
+	mockScanner.On("ScanVolume", mock.Anything, "postgres_data").Return(result, nil)
+	w = send(http.MethodPost, "/api/v1/volumes/postgres_data/size/refresh?force=true", "{}", true)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Other volumes are unaffected
+	w = send(http.MethodPost, "/api/v1/volumes/app_data/scan", "", false)
+	assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	w = send(http.MethodPost, "/api/v1/volumes/postgres_data/scan?force=maybe", "", true)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// lockingScheduler is a scheduler rejecting every enqueue as locked by lock
+type lockingScheduler struct {
+	scheduler.ScanScheduler
+	lock database.ScanLock
+}
+
+func (l *lockingScheduler) EnqueueVolumeWithOptions(volumeName string, opts scheduler.EnqueueOptions) (string, error) {
+	return "", &scheduler.LockedError{VolumeName: volumeName, Lock: l.lock}
+}
+
+func TestHandler_TriggerScanOfLockedVolume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	until := time.Now().Add(90 * time.Second)
+	scanRouter := NewRouter(&MockVolumeScanner{}, nil, nil, &lockingScheduler{lock: database.ScanLock{Until: until, Holder: "pg-backup"}}, nil, nil)
+	router := gin.New()
+	scanRouter.RegisterRoutes(router.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/volumes/postgres_data/scan?force=true", nil))
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "VOLUME_LOCKED", response["code"])
+	assert.Equal(t, "pg-backup", response["holder"])
+	assert.Equal(t, until.UTC().Format(time.RFC3339Nano), response["locked_until"])
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 90, retryAfter, 1)
+}
+
+// methodChooser is a scheduler preferring one scan method for every volume
+type methodChooser struct {
+	scheduler.ScanScheduler
+	preferred map[string]string
+}
+
+func (m *methodChooser) MethodForVolume(volumeName string) string {
+	return m.preferred[volumeName]
+}
+
+func TestHandler_DeniedScanMethods(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "scan.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "006" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, database.NewVolumeAnnotationRepository(db).SetAnnotation(context.Background(), "fuse_data", database.AnnotationKeyScanMethodsDenied, "Diskus, sample"))
+
+	mockScanner := &MockVolumeScanner{}
+	mockScanner.On("GetAvailableMethods").Return([]interfaces.MethodInfo{
+		{Name: "diskus", Available: true},
+		{Name: "du", Available: true},
+		{Name: "native", Available: true},
+		{Name: "sample", Available: true, Features: []string{"estimate_only"}},
+	})
+	deniedIs := func(methods ...string) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			return assert.ObjectsAreEqual(methods, interfaces.DeniedMethods(ctx))
+		})
+	}
+	result := &interfaces.ScanResult{TotalSize: 1024, Method: "du", ScannedAt: time.Now()}
+	mockScanner.On("ScanVolume", deniedIs("diskus", "sample"), "fuse_data").Return(result, nil)
+	mockScanner.On("ScanVolume", deniedIs(), "app_data").Return(result, nil)
+
+	chooser := &methodChooser{preferred: map[string]string{"fuse_data": "du", "app_data": "diskus"}}
+	router := gin.New()
+	NewRouter(mockScanner, nil, db, chooser, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Scans of the tagged volume bar its denied methods; other volumes keep them all
+	assert.Equal(t, http.StatusOK, get("/api/v1/volumes/fuse_data/size").Code)
+	assert.Equal(t, http.StatusOK, get("/api/v1/volumes/app_data/size").Code)
+
+	w := get("/api/v1/volumes/fuse_data/scan-methods")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"volume": "fuse_data", "methods": ["du", "native"], "denied": ["diskus", "sample"]}`, w.Body.String())
+
+	w = get("/api/v1/volumes/app_data/scan-methods")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"volume": "app_data", "methods": ["diskus", "du", "native"], "denied": []}`, w.Body.String())
+
+	w = get("/api/v1/volumes/bad$name/scan-methods")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_VOLUME_NAME")
+	mockScanner.AssertExpectations(t)
+}
+
+// recentScansRecorder is a scheduler serving recorded scans and the filters it was asked for
+type recentScansRecorder struct {
+	scheduler.ScanScheduler
+	scans   []*scheduler.ScanStatus
+	limit   int
+	sources []string
+}
+
+func (r *recentScansRecorder) ListRecentScans(limit int, triggerSource string) ([]*scheduler.ScanStatus, error) {
+	r.limit = limit
+	r.sources = append(r.sources, triggerSource)
+	var scans []*scheduler.ScanStatus
+	for _, scan := range r.scans {
+		if triggerSource == "" || scan.TriggerSource == triggerSource {
+			scans = append(scans, scan)
+		}
+	}
+	return scans, nil
+}
+
+func (r *recentScansRecorder) GetScanStatus(scanID string) (*scheduler.ScanStatus, error) {
+	for _, scan := range r.scans {
+		if scan.ScanID == scanID {
+			return scan, nil
+		}
+	}
+	return nil, scheduler.ErrScanNotFound
+}
+
+func TestHandler_RecentScans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := &recentScansRecorder{scans: []*scheduler.ScanStatus{
+		{ScanID: "scan-2", VolumeName: "app_data", Status: "completed", TriggerSource: scheduler.TriggerSourceManual},
+		{ScanID: "scan-1", VolumeName: "app_data", Status: "completed", TriggerSource: scheduler.TriggerSourceScheduled},
+	}}
+	router := gin.New()
+	NewRouter(&MockVolumeScanner{}, nil, nil, recorder, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]any) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+		return w, response
+	}
+
+	w, response := get("/api/v1/scans")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, float64(2), response["count"])
+	assert.Equal(t, defaultRecentScansLimit, recorder.limit)
+
+	w, response = get("/api/v1/scans?trigger_source=manual&limit=10")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, response["scans"], 1)
+	scan := response["scans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "scan-2", scan["scan_id"])
+	assert.Equal(t, "manual", scan["trigger_source"])
+	assert.Equal(t, 10, recorder.limit)
+
+	w, response = get("/api/v1/scans?trigger_source=event")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []any{}, response["scans"])
+
+	w, response = get("/api/v1/scans?trigger_source=cron")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "INVALID_TRIGGER_SOURCE", response["code"])
+	w, _ = get("/api/v1/scans?limit=0")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{"", "manual", "event"}, recorder.sources, "invalid filters never reach the scheduler")
+
+	// Scan detail carries the trigger source too
+	w, response = get("/api/v1/scans/scan-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "scheduled", response["trigger_source"])
+	w, response = get("/api/v1/scans/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "SCAN_NOT_FOUND", response["code"])
+}
+
+// cachingScanner keeps scan results in a real memory cache
+type cachingScanner struct {
+	MockVolumeScanner
+	cache interfaces.Cache
+}
+
+func (s *cachingScanner) CachedResult(volumeID string) *interfaces.ScanResult {
+	return s.cache.Get(volumeID)
+}
+
+func (s *cachingScanner) ClearCache(volumeID string) error {
+	return s.cache.Delete(volumeID)
+}
+
+func TestHandler_VolumeCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scanner := &cachingScanner{cache: cache.NewMemoryCache(10)}
+	require.NoError(t, scanner.cache.Set("app-data", &interfaces.ScanResult{VolumeID: "app-data", TotalSize: 4096, Method: "du"}, time.Minute))
+	require.NoError(t, scanner.cache.Set("logs", &interfaces.ScanResult{VolumeID: "logs", TotalSize: 10}, time.Minute))
+
+	router := gin.New()
+	NewRouter(scanner, nil, nil, nil, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+	send := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+	fetch := func(volumeID string) models.VolumeCacheResponse {
+		w := send("GET", "/api/v1/volumes/"+volumeID+"/cache")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response models.VolumeCacheResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	clearCache := func(path string, code int) models.VolumeCacheClearResponse {
+		w := send("DELETE", path)
+		require.Equal(t, code, w.Code, w.Body.String())
+		var response models.VolumeCacheClearResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	cached := fetch("app-data")
+	assert.True(t, cached.Cached)
+	require.NotNil(t, cached.Result)
+	assert.Equal(t, int64(4096), cached.Result.TotalSize)
+
+	t.Run("clears only that volume", func(t *testing.T) {
+		cleared := clearCache("/api/v1/volumes/app-data/cache", http.StatusOK)
+		require.NotNil(t, cleared.WasCached)
+		assert.True(t, *cleared.WasCached)
+		assert.Empty(t, cleared.ScanID)
+
+		assert.False(t, fetch("app-data").Cached)
+		assert.Nil(t, fetch("app-data").Result)
+		assert.True(t, fetch("logs").Cached)
+
+		cleared = clearCache("/api/v1/volumes/app-data/cache", http.StatusOK)
+		assert.False(t, *cleared.WasCached)
+	})
+
+	t.Run("rescan", func(t *testing.T) {
+		scanner.On("ScanVolumeAsync", mock.Anything, "logs").Return("scan_logs_1", nil).Once()
+
+		cleared := clearCache("/api/v1/volumes/logs/cache?rescan=true", http.StatusAccepted)
+		assert.True(t, *cleared.WasCached)
+		assert.Equal(t, "scan_logs_1", cleared.ScanID)
+		assert.Equal(t, "/api/v1/scans/scan_logs_1/status", cleared.StatusURL)
+		assert.False(t, fetch("logs").Cached)
+		scanner.AssertExpectations(t)
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send("DELETE", "/api/v1/volumes/logs/cache?rescan=maybe").Code)
+		assert.Equal(t, http.StatusBadRequest, send("GET", "/api/v1/volumes/-bad/cache").Code)
+	})
+}
+
+func TestHandler_VolumeCacheWithoutInspection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockScanner := &MockVolumeScanner{}
+	mockScanner.On("ClearCache", "app-data").Return(nil)
+
+	router := gin.New()
+	NewRouter(mockScanner, nil, nil, nil, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/app-data/cache", nil))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	// Clearing still works; whether there was an entry is unknown
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/volumes/app-data/cache", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "was_cached")
+	mockScanner.AssertExpectations(t)
+}
+
+// queueSnapshotter is a scheduler holding a fixed scan queue
+type queueSnapshotter struct {
+	scheduler.ScanScheduler
+	queued []scheduler.QueuedTask
+	limit  int
+}
+
+func (q *queueSnapshotter) QueuedTasks(limit int) ([]scheduler.QueuedTask, int) {
+	q.limit = limit
+	return q.queued[:min(limit, len(q.queued))], len(q.queued)
+}
+
+func TestHandler_SchedulerQueue(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	enqueuedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshotter := &queueSnapshotter{queued: []scheduler.QueuedTask{
+		{Position: 1, ScanID: "scan-1", VolumeName: "postgres_data", Method: "du", Priority: 1, EnqueuedAt: enqueuedAt, TriggerSource: scheduler.TriggerSourceManual},
+		{Position: 2, ScanID: "scan-2", VolumeName: "app_data", Method: "diskus", Priority: 0, EnqueuedAt: enqueuedAt, TriggerSource: scheduler.TriggerSourceScheduled},
+		{Position: 3, ScanID: "scan-3", VolumeName: "logs", Method: "diskus", Priority: 0, EnqueuedAt: enqueuedAt, TriggerSource: scheduler.TriggerSourceScheduled},
+	}}
+	router := gin.New()
+	NewRouter(&MockVolumeScanner{}, nil, nil, snapshotter, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/v1/scheduler/queue")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, defaultQueueLimit, snapshotter.limit)
+	assert.JSONEq(t, `{
+		"tasks": [
+			{"position": 1, "scan_id": "scan-1", "volume_name": "postgres_data", "method": "du", "priority": 1, "enqueued_at": "2025-06-01T12:00:00Z", "trigger_source": "manual"},
+			{"position": 2, "scan_id": "scan-2", "volume_name": "app_data", "method": "diskus", "priority": 0, "enqueued_at": "2025-06-01T12:00:00Z", "trigger_source": "scheduled"},
+			{"position": 3, "scan_id": "scan-3", "volume_name": "logs", "method": "diskus", "priority": 0, "enqueued_at": "2025-06-01T12:00:00Z", "trigger_source": "scheduled"}
+		],
+		"count": 3,
+		"queue_depth": 3,
+		"truncated": false
+	}`, w.Body.String())
+
+	w = get("/api/v1/scheduler/queue?limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float64(1), response["count"])
+	assert.Equal(t, float64(3), response["queue_depth"])
+	assert.Equal(t, true, response["truncated"])
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/scheduler/queue?limit=0").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/scheduler/queue?limit=5000").Code)
+
+	router = gin.New()
+	NewRouter(&MockVolumeScanner{}, nil, nil, nil, nil, nil).RegisterRoutes(router.Group("/api/v1"))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/api/v1/scheduler/queue").Code)
+}
@@ -0,0 +1,52 @@
+package scan
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultQueueLimit is how many queued tasks the scan queue listing returns by default
+	defaultQueueLimit = 100
+	// maxQueueLimit caps the limit parameter of the scan queue listing
+	maxQueueLimit = 1000
+)
+
+// GetSchedulerQueue lists the tasks waiting in the scan queue in the order
+// workers will take them, so operators can see why a volume has not been
+// scanned yet
+// GET /api/v1/scheduler/queue?limit=100
+func (h *Handler) GetSchedulerQueue(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scan scheduler not available",
+			"code":  "SCHEDULER_UNAVAILABLE",
+		})
+		return
+	}
+
+	limit := defaultQueueLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxQueueLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid limit parameter",
+				"code":    "INVALID_LIMIT",
+				"details": fmt.Sprintf("limit must be between 1 and %d", maxQueueLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	tasks, total := h.scheduler.QueuedTasks(limit)
+	c.JSON(http.StatusOK, gin.H{
+		"tasks":       tasks,
+		"count":       len(tasks),
+		"queue_depth": total,
+		"truncated":   len(tasks) < total,
+	})
+}
@@ -0,0 +1,124 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/scheduler"
+)
+
+const (
+	// defaultRecentScansLimit is how many scans the recent scans list returns by default
+	defaultRecentScansLimit = 50
+	// maxRecentScansLimit caps the limit parameter of the recent scans list
+	maxRecentScansLimit = 500
+)
+
+// triggerSources are the accepted values of the trigger_source filter
+var triggerSources = []string{
+	scheduler.TriggerSourceScheduled,
+	scheduler.TriggerSourceStartup,
+	scheduler.TriggerSourceManual,
+	scheduler.TriggerSourceEvent,
+}
+
+// ListRecentScans lists the most recently started scheduler scans, newest first
+// GET /api/v1/scans?trigger_source=manual&limit=50
+func (h *Handler) ListRecentScans(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scan scheduler not available",
+			"code":  "SCHEDULER_UNAVAILABLE",
+		})
+		return
+	}
+
+	triggerSource := strings.TrimSpace(c.Query("trigger_source"))
+	if triggerSource != "" && !isTriggerSource(triggerSource) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid trigger_source parameter",
+			"code":    "INVALID_TRIGGER_SOURCE",
+			"details": fmt.Sprintf("trigger_source must be one of: %s", strings.Join(triggerSources, ", ")),
+		})
+		return
+	}
+
+	limit := defaultRecentScansLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxRecentScansLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid limit parameter",
+				"code":    "INVALID_LIMIT",
+				"details": fmt.Sprintf("limit must be between 1 and %d", maxRecentScansLimit),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	scans, err := h.scheduler.ListRecentScans(limit, triggerSource)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list recent scans",
+			"code":    "RECENT_SCANS_FAILED",
+			"details": err.Error(),
+		})
+		return
+	}
+	if scans == nil {
+		scans = []*scheduler.ScanStatus{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scans": scans,
+		"count": len(scans),
+	})
+}
+
+// GetScan returns a scheduler scan with its outcome and trigger source
+// GET /api/v1/scans/{id}
+func (h *Handler) GetScan(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Scan scheduler not available",
+			"code":  "SCHEDULER_UNAVAILABLE",
+		})
+		return
+	}
+
+	id := c.Param("id")
+	status, err := h.scheduler.GetScanStatus(id)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Scan not found",
+				"code":    "SCAN_NOT_FOUND",
+				"details": fmt.Sprintf("No scan found with ID %s", id),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get scan",
+			"code":    "SCAN_LOOKUP_FAILED",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// isTriggerSource reports whether source is a known trigger source
+func isTriggerSource(source string) bool {
+	for _, known := range triggerSources {
+		if source == known {
+			return true
+		}
+	}
+	return false
+}
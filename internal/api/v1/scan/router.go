@@ -10,40 +10,81 @@ import (
 
 // Router handles scan-related routes
 type Router struct {
-	handler *Handler
+	handler      *Handler
+	operatorOnly gin.HandlerFunc
+	adminOnly    gin.HandlerFunc
 }
 
-// NewRouter creates a new scan router
-func NewRouter(scanner interfaces.VolumeScanner, hub *websocket.Hub, db *database.DB, scanScheduler scheduler.ScanScheduler) *Router {
+// NewRouter creates a new scan router. operatorOnly guards scheduler control
+// and cache clearing endpoints and adminOnly guards scan method tuning; pass nil to leave them unguarded.
+func NewRouter(scanner interfaces.VolumeScanner, hub *websocket.Hub, db *database.DB, scanScheduler scheduler.ScanScheduler, operatorOnly, adminOnly gin.HandlerFunc) *Router {
+	if operatorOnly == nil {
+		operatorOnly = func(c *gin.Context) { c.Next() }
+	}
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
 	metricsRepo := database.NewVolumeMetricsRepository(db)
+	handler := NewHandler(scanner, hub, metricsRepo, scanScheduler)
+	if db != nil {
+		handler.annotations = database.NewVolumeAnnotationRepository(db)
+	}
 	return &Router{
-		handler: NewHandler(scanner, hub, metricsRepo, scanScheduler),
+		handler:      handler,
+		operatorOnly: operatorOnly,
+		adminOnly:    adminOnly,
 	}
 }
 
+// SetAdminCheck sets how requests allowed to scan volumes with scanning
+// disabled are recognized; by default every forced request is
+func (r *Router) SetAdminCheck(isAdmin func(c *gin.Context) bool) {
+	r.handler.SetAdminCheck(isAdmin)
+}
+
 // RegisterRoutes registers all scan-related routes
 func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 	// Volume size endpoints
 	group.GET("/volumes/:name/size", r.handler.GetVolumeSize)
 	group.POST("/volumes/:name/size/refresh", r.handler.RefreshVolumeSize)
 
+	// Cached scan result of a volume; clearing it can start a fresh scan
+	group.GET("/volumes/:name/cache", r.handler.GetVolumeCache)
+	group.DELETE("/volumes/:name/cache", r.operatorOnly, r.handler.ClearVolumeCache)
+
+	// Volume content manifest (gzip-compressed JSONL)
+	group.GET("/volumes/:name/manifest", r.handler.GetVolumeManifest)
+
 	// Volume scan status endpoint (per spec)
 	group.GET("/volumes/:name/scan/status", r.handler.GetScanStatus)
 
 	// Scan status by scan ID (used by tests and clients)
 	group.GET("/scans/:id/status", r.handler.GetScanStatus)
 
+	// Recorded scheduler scans, filterable by trigger source
+	group.GET("/scans", r.handler.ListRecentScans)
+	group.GET("/scans/:id", r.handler.GetScan)
+
 	// Bulk scanning
 	group.POST("/volumes/bulk-scan", r.handler.BulkScan)
 
 	// Scan methods
 	group.GET("/scan-methods", r.handler.GetScanMethods)
+	group.GET("/scans/methods", r.handler.GetScanMethods)
+	group.PUT("/scans/methods", r.adminOnly, r.handler.SetScanMethodsOrder) // Reorder method preference
+
+	// Scan methods tried for one volume, after its denied methods
+	group.GET("/volumes/:name/scan-methods", r.handler.GetVolumeScanMethods)
 
 	// Manual scan trigger endpoints (scheduler-based)
 	group.POST("/volumes/:name/scan", r.handler.TriggerVolumeScan) // Enqueue single volume
 	group.POST("/scan/now", r.handler.TriggerAllVolumesScan)       // Enqueue all volumes (admin-only)
 
 	// Scheduler management endpoints
-	group.GET("/scheduler/status", r.handler.GetSchedulerStatus)   // Get scheduler status
-	group.GET("/scheduler/metrics", r.handler.GetSchedulerMetrics) // Get scheduler metrics
+	group.GET("/scheduler/status", r.handler.GetSchedulerStatus)               // Get scheduler status
+	group.GET("/scheduler/metrics", r.handler.GetSchedulerMetrics)             // Get scheduler metrics
+	group.GET("/scheduler/queue", r.handler.GetSchedulerQueue)                 // List queued scans
+	group.POST("/scheduler/pause", r.operatorOnly, r.handler.PauseScheduler)   // Pause background scans
+	group.POST("/scheduler/resume", r.operatorOnly, r.handler.ResumeScheduler) // Resume background scans
 }
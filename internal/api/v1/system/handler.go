@@ -4,12 +4,16 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/services"
+	"github.com/mantonx/volumeviz/internal/version"
 )
 
 // Handler handles system-related HTTP requests
 type Handler struct {
 	dockerService *services.DockerService
+	config        *config.Config // Effective configuration; nil when not served
 }
 
 // NewHandler creates a new system handler
@@ -19,27 +23,30 @@ func NewHandler(dockerService *services.DockerService) *Handler {
 	}
 }
 
-// GetSystemInfo returns system information
+// GetSystemInfo returns system information, including whether the instance
+// is in read-only mode
 // GET /api/v1/system/info
 func (h *Handler) GetSystemInfo(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	version, err := h.dockerService.GetVersion(ctx)
+	dockerVersion, err := h.dockerService.GetVersion(ctx)
 	dockerAvailable := h.dockerService.IsDockerAvailable(ctx)
 
 	info := gin.H{
 		"service": "volumeviz",
-		"version": "1.0.0", // TODO: Get from build info
+		"version": version.Version,
+		"build":   h.buildInfo(),
 		"docker": gin.H{
 			"available": dockerAvailable,
 		},
+		"read_only": h.config != nil && h.config.Server.ReadOnly,
 	}
 
 	if err == nil && dockerAvailable {
 		info["docker"] = gin.H{
 			"available":   true,
-			"version":     version.Version,
-			"api_version": version.APIVersion,
+			"version":     dockerVersion.Version,
+			"api_version": dockerVersion.APIVersion,
 		}
 	}
 
@@ -52,7 +59,7 @@ func (h *Handler) GetVersion(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"api_version": "v1",
 		"service":     "volumeviz",
-		"version":     "1.0.0", // TODO: Get from build info
+		"version":     version.Version,
 		"endpoints": gin.H{
 			"health":  "/api/v1/health",
 			"volumes": "/api/v1/volumes",
@@ -60,3 +67,41 @@ func (h *Handler) GetVersion(c *gin.Context) {
 		},
 	})
 }
+
+// BuildInfo is the build of VolumeViz that is running
+type BuildInfo struct {
+	version.Info
+	// DockerAPIVersion is the Docker API version negotiated with the daemon
+	DockerAPIVersion string `json:"docker_api_version,omitempty"`
+}
+
+// buildInfo returns the running build, as set through ldflags at build time
+func (h *Handler) buildInfo() BuildInfo {
+	return BuildInfo{
+		Info:             version.Get(),
+		DockerAPIVersion: h.dockerService.NegotiatedAPIVersion(),
+	}
+}
+
+// GetBuildInfo returns the version, git commit and build date of the running
+// build, the Go version it was built with and the negotiated Docker API
+// version, for bug reports and checking what a rollout deployed
+// GET /api/v1/version
+func (h *Handler) GetBuildInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, h.buildInfo())
+}
+
+// GetConfig returns the effective configuration the server is running with,
+// after defaults and environment overrides, with secrets masked
+// GET /api/v1/config
+func (h *Handler) GetConfig(c *gin.Context) {
+	if h.config == nil {
+		apiutils.RespondWithError(c, http.StatusNotFound, apiutils.ErrorCodeNotFound,
+			"Configuration is not available", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"config": h.config.Effective(),
+	})
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/mocks"
+	"github.com/mantonx/volumeviz/internal/services"
+	"github.com/mantonx/volumeviz/internal/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// injectBuild sets the build variables as ldflags would, restoring them
+// when the test ends
+func injectBuild(t *testing.T, v, commit, date string) {
+	prevVersion, prevCommit, prevDate := version.Version, version.GitCommit, version.BuildDate
+	version.Version, version.GitCommit, version.BuildDate = v, commit, date
+	t.Cleanup(func() {
+		version.Version, version.GitCommit, version.BuildDate = prevVersion, prevCommit, prevDate
+	})
+}
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	client := &mocks.MockDockerClient{ClientVersionFunc: func() string { return "1.43" }}
+	router := gin.New()
+	NewRouter(services.NewDockerServiceWithClient(client), nil, nil).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestGetBuildInfo(t *testing.T) {
+	injectBuild(t, "1.4.2", "9f3c2ab", "2026-10-01T12:00:00Z")
+
+	w := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/version", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var info BuildInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, "1.4.2", info.Version)
+	assert.Equal(t, "9f3c2ab", info.GitCommit)
+	assert.Equal(t, "2026-10-01T12:00:00Z", info.BuildDate)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, info.Platform)
+	assert.Equal(t, "1.43", info.DockerAPIVersion)
+}
+
+func TestGetSystemInfo_IncludesBuild(t *testing.T) {
+	injectBuild(t, "1.4.2", "9f3c2ab", "2026-10-01T12:00:00Z")
+
+	w := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/info", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var info struct {
+		Version string    `json:"version"`
+		Build   BuildInfo `json:"build"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, "1.4.2", info.Version)
+	assert.Equal(t, "9f3c2ab", info.Build.GitCommit)
+	assert.Equal(t, "1.43", info.Build.DockerAPIVersion)
+}
@@ -2,18 +2,28 @@ package system
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/services"
 )
 
 // Router handles system-related routes
 type Router struct {
-	handler *Handler
+	handler   *Handler
+	adminOnly gin.HandlerFunc
 }
 
-// NewRouter creates a new system router
-func NewRouter(dockerService *services.DockerService) *Router {
+// NewRouter creates a new system router. adminOnly guards the effective
+// configuration; pass nil to leave it unguarded.
+func NewRouter(dockerService *services.DockerService, cfg *config.Config, adminOnly gin.HandlerFunc) *Router {
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
+	handler := NewHandler(dockerService)
+	handler.config = cfg
 	return &Router{
-		handler: NewHandler(dockerService),
+		handler:   handler,
+		adminOnly: adminOnly,
 	}
 }
 
@@ -24,4 +34,7 @@ func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 		system.GET("/info", r.handler.GetSystemInfo)
 		system.GET("/version", r.handler.GetVersion)
 	}
+
+	group.GET("/version", r.handler.GetBuildInfo)
+	group.GET("/config", r.adminOnly, r.handler.GetConfig)
 }
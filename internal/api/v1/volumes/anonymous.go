@@ -0,0 +1,115 @@
+package volumes
+
+import (
+	"sync"
+	"time"
+
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// anonymousVolumeLabel is set by Docker on the volumes it creates for a
+// container's unnamed mounts
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// defaultAnonymousOrphanGrace is how long an anonymous volume that lost its
+// containers is assumed to be torn down with them rather than orphaned
+const defaultAnonymousOrphanGrace = time.Minute
+
+// isContainerOwnedVolume reports whether Docker created a volume for a single
+// container's unnamed mount, so it goes away with that container (--rm). Docker
+// labels these volumes; older engines only give them a 64-character hex name.
+func isContainerOwnedVolume(vol coremodels.Volume) bool {
+	if _, ok := vol.Labels[anonymousVolumeLabel]; ok {
+		return true
+	}
+	return len(vol.Name) == 64 && isAnonymousVolume(vol.Name)
+}
+
+// attachmentTracker remembers when volumes were last seen mounted, so their
+// orphaned status can follow the container lifecycle. A nil tracker remembers
+// nothing.
+type attachmentTracker struct {
+	grace time.Duration
+	now   func() time.Time
+
+	mu        sync.Mutex
+	lastSeen  map[string]time.Time
+	lastSweep time.Time
+}
+
+// newAttachmentTracker creates a tracker with the given grace period
+func newAttachmentTracker(grace time.Duration) *attachmentTracker {
+	return &attachmentTracker{
+		grace:    grace,
+		now:      time.Now,
+		lastSeen: make(map[string]time.Time),
+	}
+}
+
+// attached records that a volume is mounted by at least one container
+func (t *attachmentTracker) attached(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.lastSeen[name] = now
+
+	// Volumes detached for longer than the grace period no longer matter;
+	// dropping them at most once per period keeps the map small
+	if now.Sub(t.lastSweep) >= t.grace {
+		for volume, seen := range t.lastSeen {
+			if now.Sub(seen) >= t.grace {
+				delete(t.lastSeen, volume)
+			}
+		}
+		t.lastSweep = now
+	}
+}
+
+// recentlyAttached reports whether a volume was seen mounted within the grace
+// period
+func (t *attachmentTracker) recentlyAttached(name string) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	seen, ok := t.lastSeen[name]
+	t.mu.Unlock()
+	return ok && t.now().Sub(seen) < t.grace
+}
+
+// tearingDown reports whether a container-owned volume without containers is
+// still within the grace period after it was created or last seen mounted.
+// Such a volume is most likely being removed together with its container, or
+// its container is still being created.
+func (t *attachmentTracker) tearingDown(vol coremodels.Volume) bool {
+	if t == nil || t.grace <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	settledAt := t.lastSeen[vol.Name]
+	t.mu.Unlock()
+	if vol.CreatedAt.After(settledAt) {
+		settledAt = vol.CreatedAt
+	}
+	return t.now().Sub(settledAt) < t.grace
+}
+
+// isOrphaned reports whether a volume mounted by attachments containers is
+// orphaned. A container-owned volume that just lost its container is not:
+// Docker removes it right after the container, and reporting it would only
+// flash a false orphan during the teardown.
+func (h *Handler) isOrphaned(vol coremodels.Volume, attachments int) bool {
+	if !isContainerOwnedVolume(vol) {
+		return attachments == 0
+	}
+	if attachments > 0 {
+		h.attachments.attached(vol.Name)
+		return false
+	}
+	return !h.attachments.tearingDown(vol)
+}
@@ -0,0 +1,236 @@
+package volumes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/config"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+	"github.com/mantonx/volumeviz/internal/utils"
+)
+
+// Directory listing limits
+const (
+	defaultListLimit = 1000
+	maxListLimit     = 10000
+)
+
+// errPathOutsideVolume rejects listing paths that leave the volume
+var errPathOutsideVolume = errors.New("path must stay within the volume")
+
+// ListVolumeDirectory lists one directory level of a volume with each entry's
+// type, size and modification time, without walking the tree below it. path
+// selects a directory relative to the volume root and must stay inside it,
+// including through symlinks. Directories come first, then files, each by
+// name; at most limit entries are read from the directory.
+// Implements GET /api/v1/volumes/{name}/ls?path=&limit=
+func (h *Handler) ListVolumeDirectory(c *gin.Context) {
+	ctx := c.Request.Context()
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+
+	relPath, err := parseListPath(c.Query("path"))
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), map[string]interface{}{"path": c.Query("path")})
+		return
+	}
+
+	limit := defaultListLimit
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxListLimit {
+			apiutils.RespondWithBadRequest(c, fmt.Sprintf("limit must be between 1 and %d", maxListLimit), nil)
+			return
+		}
+	}
+
+	volume, err := h.dockerService.GetVolume(ctx, volumeName)
+	if err != nil {
+		if utils.IsNotFound(err) {
+			apiutils.RespondWithNotFound(c, fmt.Sprintf("Volume '%s' not found", volumeName))
+			return
+		}
+		apiutils.RespondWithInternalError(c, "Failed to get volume", err)
+		return
+	}
+
+	rootPath, reason := h.volumeRootPath(*volume)
+	if reason != "" {
+		apiutils.RespondWithError(c, http.StatusUnprocessableEntity, apiutils.ErrorCodeBadRequest,
+			"Volume has no local path to list", map[string]interface{}{"reason": reason})
+		return
+	}
+
+	entries, truncated, err := readVolumeDirectory(rootPath, relPath, limit)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+	listing := models.DirectoryListingV1{
+		Volume:    volumeName,
+		Path:      filepath.ToSlash(relPath),
+		Entries:   make([]models.DirectoryEntryV1, 0, len(entries)),
+		Truncated: truncated,
+	}
+	if listing.Path == "." {
+		listing.Path = ""
+	}
+	for _, info := range entries {
+		entry := models.DirectoryEntryV1{
+			Name:       info.Name(),
+			Type:       entryType(info.Mode()),
+			Mode:       info.Mode().String(),
+			ModifiedAt: info.ModTime().UTC(),
+		}
+		if info.Mode().IsRegular() {
+			size := info.Size()
+			entry.SizeBytes = models.NewSizeBytes(&size, asStrings)
+		}
+		listing.Entries = append(listing.Entries, entry)
+	}
+
+	sort.Slice(listing.Entries, func(i, j int) bool {
+		a, b := listing.Entries[i], listing.Entries[j]
+		if (a.Type == models.EntryTypeDir) != (b.Type == models.EntryTypeDir) {
+			return a.Type == models.EntryTypeDir
+		}
+		return a.Name < b.Name
+	})
+
+	c.JSON(http.StatusOK, listing)
+}
+
+// parseListPath turns the path query parameter into a clean path relative to
+// the volume root, "." for the root. A leading slash is ignored; any path
+// that would climb out of the root is rejected.
+func parseListPath(raw string) (string, error) {
+	rel := strings.TrimLeft(filepath.FromSlash(raw), string(filepath.Separator))
+	if rel == "" {
+		return ".", nil
+	}
+	if strings.ContainsRune(rel, 0) || !filepath.IsLocal(rel) {
+		return "", errPathOutsideVolume
+	}
+	return filepath.Clean(rel), nil
+}
+
+// volumeRootPath returns the local directory holding a volume's files, found
+// like the scanner finds it, with the configured path rewrites applied.
+// reason explains why a volume has none.
+func (h *Handler) volumeRootPath(volume coremodels.Volume) (path string, reason string) {
+	root, reason := config.ResolveVolumeRoot(h.pathRewrites, volume.Options["device"], volume.Mountpoint)
+	return root.Path, reason
+}
+
+// SetPathRewrites sets the rules mapping the host paths Docker reports to the
+// paths visible here, tried in order
+func (h *Handler) SetPathRewrites(rewrites []config.PathRewrite) {
+	h.pathRewrites = rewrites
+}
+
+// readVolumeDirectory reads up to limit entries of the directory rel below
+// rootPath. Symlinks are resolved first so one pointing out of the volume is
+// rejected with errPathOutsideVolume; the resolved directory is then opened
+// through os.Root, which also refuses an escape swapped in after that check.
+// Entries are described without following symlinks.
+func readVolumeDirectory(rootPath, rel string, limit int) ([]fs.FileInfo, bool, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(rootPath)
+	if err != nil {
+		return nil, false, err
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(resolvedRoot, rel))
+	if err != nil {
+		return nil, false, err
+	}
+	within, err := filepath.Rel(resolvedRoot, resolved)
+	if err != nil || !filepath.IsLocal(within) {
+		return nil, false, errPathOutsideVolume
+	}
+
+	root, err := os.OpenRoot(resolvedRoot)
+	if err != nil {
+		return nil, false, err
+	}
+	defer root.Close()
+
+	dir, err := root.Open(within)
+	if err != nil {
+		return nil, false, err
+	}
+	defer dir.Close()
+
+	info, err := dir.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	if !info.IsDir() {
+		return nil, false, syscall.ENOTDIR
+	}
+
+	// One extra entry tells whether the listing was cut short
+	dirEntries, err := dir.ReadDir(limit + 1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, false, err
+	}
+	truncated := len(dirEntries) > limit
+	if truncated {
+		dirEntries = dirEntries[:limit]
+	}
+
+	entries := make([]fs.FileInfo, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		info, err := entry.Info()
+		if err != nil {
+			// Entries removed since the directory was read are left out
+			continue
+		}
+		entries = append(entries, info)
+	}
+	return entries, truncated, nil
+}
+
+// respondListError maps a directory listing failure to an API error
+func respondListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errPathOutsideVolume):
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+	case errors.Is(err, fs.ErrNotExist):
+		apiutils.RespondWithNotFound(c, "Path not found in volume")
+	case errors.Is(err, syscall.ENOTDIR):
+		apiutils.RespondWithBadRequest(c, "Path is not a directory", nil)
+	case errors.Is(err, fs.ErrPermission):
+		apiutils.RespondWithForbidden(c, "Permission denied reading path")
+	default:
+		apiutils.RespondWithInternalError(c, "Failed to list directory", err)
+	}
+}
+
+// entryType classifies a directory entry by its mode
+func entryType(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return models.EntryTypeDir
+	case mode&fs.ModeSymlink != 0:
+		return models.EntryTypeSymlink
+	case mode.IsRegular():
+		return models.EntryTypeFile
+	default:
+		return models.EntryTypeOther
+	}
+}
@@ -0,0 +1,139 @@
+package volumes
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// GetVolumesByLabel groups volumes and their total size by their value for a
+// label or annotation key, e.g. cost_center, for chargeback and showback
+// reporting. An annotation takes precedence over a Docker label with the same
+// key; volumes with neither are grouped as "unassigned". value= restricts the
+// report to a single group. Report limits apply to the volumes listed across
+// all groups; group totals always cover every volume.
+// Implements GET /api/v1/reports/by-label?key=&value=&system=
+func (h *Handler) GetVolumesByLabel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	key := strings.TrimSpace(c.Query("key"))
+	if key == "" {
+		apiutils.RespondWithBadRequest(c, "key is required", nil)
+		return
+	}
+	value, filterValue := c.GetQuery("value")
+	value = strings.TrimSpace(value)
+	includeSystem := c.DefaultQuery("system", "false") == "true"
+	cursor, err := apiutils.ParseReportCursor(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+
+	// Annotations are optional: without a database only Docker labels count
+	annotated := make(map[string]string)
+	if h.database != nil {
+		annotations, err := database.NewVolumeAnnotationRepository(h.database).ListByKey(ctx, key)
+		if err != nil {
+			apiutils.RespondWithInternalError(c, "Failed to list annotations", err)
+			return
+		}
+		for _, annotation := range annotations {
+			annotated[annotation.VolumeName] = annotation.Value
+		}
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+	byValue := make(map[string]*models.LabelVolumesV1)
+	totalVolumes := 0
+	totalSize := models.SizeBytes{AsString: asStrings}
+	for _, vol := range volumes {
+		if !includeSystem && h.isSystemVolume(vol) {
+			continue
+		}
+
+		groupValue, source := models.LabelValueUnassigned, ""
+		if annotation := strings.TrimSpace(annotated[vol.Name]); annotation != "" {
+			groupValue, source = annotation, models.LabelSourceAnnotation
+		} else if label := strings.TrimSpace(vol.Labels[key]); label != "" {
+			groupValue, source = label, models.LabelSourceLabel
+		}
+		if filterValue && groupValue != value {
+			continue
+		}
+
+		group, ok := byValue[groupValue]
+		if !ok {
+			group = &models.LabelVolumesV1{
+				Value:          groupValue,
+				TotalSizeBytes: models.SizeBytes{AsString: asStrings},
+				Volumes:        make([]models.LabelVolumeV1, 0),
+			}
+			byValue[groupValue] = group
+		}
+
+		// Unknown sizes and size-unsupported drivers do not count towards the totals
+		sizeBytes, sizeSupported := h.volumeSize(vol)
+		if sizeBytes != nil {
+			group.TotalSizeBytes.Value += *sizeBytes
+			totalSize.Value += *sizeBytes
+		}
+
+		group.Volumes = append(group.Volumes, models.LabelVolumeV1{
+			Name:          vol.Name,
+			Driver:        vol.Driver,
+			SizeBytes:     models.NewSizeBytes(sizeBytes, asStrings),
+			SizeSupported: sizeSupported,
+			Source:        source,
+		})
+		group.VolumeCount++
+		totalVolumes++
+	}
+
+	// Groups are listed by value with the unassigned volumes last
+	groups := make([]models.LabelVolumesV1, 0, len(byValue))
+	for _, group := range byValue {
+		sort.Slice(group.Volumes, func(i, j int) bool {
+			return group.Volumes[i].Name < group.Volumes[j].Name
+		})
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		iUnassigned := groups[i].Value == models.LabelValueUnassigned
+		jUnassigned := groups[j].Value == models.LabelValueUnassigned
+		if iUnassigned != jUnassigned {
+			return jUnassigned
+		}
+		return groups[i].Value < groups[j].Value
+	})
+
+	generatedAt := time.Now().UTC()
+	remaining := max(totalVolumes-cursor, 0)
+	apiutils.RespondWithReport(c, cursor, remaining, func(n int, page *models.ReportPageV1) interface{} {
+		return models.VolumesByLabelReportV1{
+			Key:            key,
+			Groups:         volumesWindow(groups, labelVolumes, cursor, n),
+			TotalVolumes:   totalVolumes,
+			TotalSizeBytes: totalSize,
+			GeneratedAt:    generatedAt,
+			ReportPageV1:   page,
+		}
+	})
+}
+
+// labelVolumes returns the volumes of a label value for volumesWindow
+func labelVolumes(group *models.LabelVolumesV1) *[]models.LabelVolumeV1 {
+	return &group.Volumes
+}
@@ -0,0 +1,151 @@
+package volumes
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/interfaces"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// composeProjectLabel is the label Docker Compose sets on the volumes it creates
+const composeProjectLabel = "com.docker.compose.project"
+
+// composeProject returns the Compose project a volume belongs to, or
+// "ungrouped" for volumes Compose did not create
+func composeProject(vol coremodels.Volume) string {
+	if project := strings.TrimSpace(vol.Labels[composeProjectLabel]); project != "" {
+		return project
+	}
+	return models.ProjectUngrouped
+}
+
+// GetVolumesByProject groups volumes, their total size and the containers
+// using them by Docker Compose project, from the com.docker.compose.project
+// label. Volumes without the label are grouped as "ungrouped". project=
+// restricts the report to a single project. Report limits apply to the
+// volumes listed across all projects; project totals always cover every
+// volume.
+// Implements GET /api/v1/reports/by-project?project=&system=
+func (h *Handler) GetVolumesByProject(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	project, filterProject := c.GetQuery("project")
+	project = strings.TrimSpace(project)
+	includeSystem := c.DefaultQuery("system", "false") == "true"
+	cursor, err := apiutils.ParseReportCursor(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+	byProject := make(map[string]*models.ProjectVolumesV1)
+	projectContainers := make(map[string]map[string]bool)
+	totalVolumes := 0
+	totalSize := models.SizeBytes{AsString: asStrings}
+	var partial *models.PartialResultV1
+	for _, vol := range volumes {
+		if !includeSystem && h.isSystemVolume(vol) {
+			continue
+		}
+		volumeProject := composeProject(vol)
+		if filterProject && volumeProject != project {
+			continue
+		}
+
+		// Containers of one project commonly share volumes; each counts once.
+		// A volume whose containers the budget leaves unlisted ends the report.
+		containers, err := h.dockerService.GetVolumeContainers(ctx, vol.ID)
+		if errors.Is(err, interfaces.ErrDockerCallBudgetExhausted) {
+			partial = &models.PartialResultV1{
+				Partial: true,
+				Warning: "The request reached its Docker API call budget; only the volumes checked before it ran out are reported",
+			}
+			break
+		}
+
+		group, ok := byProject[volumeProject]
+		if !ok {
+			group = &models.ProjectVolumesV1{
+				Project:        volumeProject,
+				TotalSizeBytes: models.SizeBytes{AsString: asStrings},
+				Volumes:        make([]models.ProjectVolumeV1, 0),
+			}
+			byProject[volumeProject] = group
+			projectContainers[volumeProject] = make(map[string]bool)
+		}
+
+		// Unknown sizes and size-unsupported drivers do not count towards the totals
+		sizeBytes, sizeSupported := h.volumeSize(vol)
+		if sizeBytes != nil {
+			group.TotalSizeBytes.Value += *sizeBytes
+			totalSize.Value += *sizeBytes
+		}
+
+		attachments := make([]string, 0, len(containers))
+		for _, container := range containers {
+			attachments = append(attachments, container.Name)
+			projectContainers[volumeProject][container.ID] = true
+		}
+		sort.Strings(attachments)
+		group.AttachmentCount = len(projectContainers[volumeProject])
+
+		group.Volumes = append(group.Volumes, models.ProjectVolumeV1{
+			Name:          vol.Name,
+			Driver:        vol.Driver,
+			SizeBytes:     models.NewSizeBytes(sizeBytes, asStrings),
+			SizeSupported: sizeSupported,
+			Attachments:   attachments,
+		})
+		group.VolumeCount++
+		totalVolumes++
+	}
+
+	// Projects are listed by name with the ungrouped volumes last
+	projects := make([]models.ProjectVolumesV1, 0, len(byProject))
+	for _, group := range byProject {
+		sort.Slice(group.Volumes, func(i, j int) bool {
+			return group.Volumes[i].Name < group.Volumes[j].Name
+		})
+		projects = append(projects, *group)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		iUngrouped := projects[i].Project == models.ProjectUngrouped
+		jUngrouped := projects[j].Project == models.ProjectUngrouped
+		if iUngrouped != jUngrouped {
+			return jUngrouped
+		}
+		return projects[i].Project < projects[j].Project
+	})
+
+	generatedAt := time.Now().UTC()
+	remaining := max(totalVolumes-cursor, 0)
+	apiutils.RespondWithReport(c, cursor, remaining, func(n int, page *models.ReportPageV1) interface{} {
+		return models.VolumesByProjectReportV1{
+			Projects:        volumesWindow(projects, projectVolumes, cursor, n),
+			TotalVolumes:    totalVolumes,
+			TotalSizeBytes:  totalSize,
+			GeneratedAt:     generatedAt,
+			ReportPageV1:    page,
+			PartialResultV1: partial,
+		}
+	})
+}
+
+// projectVolumes returns the volumes of a project for volumesWindow
+func projectVolumes(project *models.ProjectVolumesV1) *[]models.ProjectVolumeV1 {
+	return &project.Volumes
+}
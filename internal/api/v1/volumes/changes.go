@@ -0,0 +1,146 @@
+package volumes
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/interfaces"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// GetVolumeChanges lists the volumes created, updated (row, labels,
+// attachments or scanned size) and removed since a time, so a client keeping
+// the volume list can merge the changes instead of fetching every volume.
+// Created and updated volumes are in the list's format and take its filters;
+// pass server_time from the response as the next since.
+// Implements GET /api/v1/volumes/changes?since=<RFC3339>
+func (h *Handler) GetVolumeChanges(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	raw := c.Query("since")
+	if raw == "" {
+		apiutils.RespondWithBadRequest(c, "since parameter is required", nil)
+		return
+	}
+	since, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, "invalid since parameter: must be RFC3339", nil)
+		return
+	}
+
+	filters, err := apiutils.ParseVolumeFilters(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	if h.database == nil {
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Volume changes require a database", nil)
+		return
+	}
+
+	// Taken before the queries, so changes made while they run are listed again next time
+	serverTime := time.Now().UTC()
+	changes, err := database.NewEventRepository(h.database).ListVolumeChangesSince(ctx, since)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volume changes", err)
+		return
+	}
+
+	response := models.VolumeChangesV1{
+		Since:      since,
+		ServerTime: serverTime,
+		Created:    []models.VolumeV1{},
+		Updated:    []models.VolumeV1{},
+		Removed:    make([]models.RemovedVolumeV1, 0, len(changes.Removed)),
+	}
+	for _, tombstone := range changes.Removed {
+		response.Removed = append(response.Removed, models.RemovedVolumeV1{Name: tombstone.VolumeID, RemovedAt: tombstone.RemovedAt})
+	}
+
+	if len(changes.Created) > 0 || len(changes.Updated) > 0 {
+		created, updated, err := h.changedVolumes(c, changes, filters)
+		if err != nil {
+			apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+			return
+		}
+		response.Created, response.Updated = created, updated
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// changedVolumes converts the created and updated volumes Docker still lists
+// and the filters keep, sorted by name. Volumes the database knows but Docker
+// no longer lists are left for a later listing to report removed.
+func (h *Handler) changedVolumes(c *gin.Context, changes *database.VolumeChanges, filters *apiutils.VolumeFilters) (created, updated []models.VolumeV1, err error) {
+	ctx := c.Request.Context()
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	volumes, err = h.withoutQuarantined(ctx, volumes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	createdNames := make(map[string]bool, len(changes.Created))
+	for _, name := range changes.Created {
+		createdNames[name] = true
+	}
+	changedNames := make(map[string]bool, len(changes.Updated))
+	for _, name := range changes.Updated {
+		changedNames[name] = true
+	}
+
+	changed := make([]coremodels.Volume, 0, len(changes.Created)+len(changes.Updated))
+	for _, vol := range volumes {
+		if createdNames[vol.Name] || changedNames[vol.Name] {
+			changed = append(changed, vol)
+		}
+	}
+
+	// Partial changes would be merged as complete, so running out of Docker
+	// API call budget fails the request
+	kept, listed, partial := h.filterVolumes(ctx, changed, filters)
+	if partial {
+		return nil, nil, interfaces.ErrDockerCallBudgetExhausted
+	}
+	countStopped := h.countsStopped(filters.CountStopped)
+	apiVolumes := make([]models.VolumeV1, 0, len(kept))
+	for _, vol := range kept {
+		if containers, ok := listed[vol.ID]; ok {
+			apiVolumes = append(apiVolumes, h.apiVolume(vol, containers, countStopped))
+			continue
+		}
+		apiVol, err := h.convertToAPIVolume(ctx, vol, countStopped)
+		if err != nil {
+			return nil, nil, err
+		}
+		apiVolumes = append(apiVolumes, apiVol)
+	}
+	h.applyLatestScanStats(ctx, apiVolumes)
+	sort.Slice(apiVolumes, func(i, j int) bool {
+		return apiVolumes[i].Name < apiVolumes[j].Name
+	})
+
+	asStrings := middleware.SizesAsStrings(c)
+	created, updated = []models.VolumeV1{}, []models.VolumeV1{}
+	for _, vol := range apiVolumes {
+		if asStrings && vol.SizeBytes != nil {
+			vol.SizeBytes.AsString = true
+		}
+		if createdNames[vol.Name] {
+			created = append(created, vol)
+		} else {
+			updated = append(updated, vol)
+		}
+	}
+	return created, updated, nil
+}
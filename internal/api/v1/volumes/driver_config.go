@@ -0,0 +1,90 @@
+package volumes
+
+import (
+	"strings"
+
+	"github.com/mantonx/volumeviz/internal/api/models"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// driverConfig parses a volume's driver options into typed fields. Only the
+// local driver's type, o and device options have known semantics; volumes of
+// other drivers keep their options as they are, and so do options the local
+// driver does not define.
+func driverConfig(vol coremodels.Volume) models.DriverConfigV1 {
+	config := models.DriverConfigV1{Driver: vol.Driver}
+	if vol.Driver != "" && vol.Driver != "local" {
+		if len(vol.Options) > 0 {
+			config.Options = vol.Options
+		}
+		return config
+	}
+
+	config.Recognized = true
+	config.Type = vol.Options["type"]
+	config.Device = vol.Options["device"]
+	if o := vol.Options["o"]; o != "" {
+		config.MountOptions = strings.Split(o, ",")
+	}
+	for key, value := range vol.Options {
+		if key == "type" || key == "o" || key == "device" {
+			continue
+		}
+		if config.Options == nil {
+			config.Options = make(map[string]string)
+		}
+		config.Options[key] = value
+	}
+
+	switch fsType := strings.ToLower(config.Type); {
+	case config.Type == "" && config.Device == "" && len(config.MountOptions) == 0:
+		config.Kind = models.DriverConfigDefault
+	case hasMountOption(config.MountOptions, "bind"), hasMountOption(config.MountOptions, "rbind"):
+		config.Kind = models.DriverConfigBind
+		config.Source = config.Device
+	case fsType == "nfs" || fsType == "nfs4":
+		// The device is server:/export, or :/export with the server in addr=
+		config.Kind = models.DriverConfigNFS
+		config.Server = mountOption(config.MountOptions, "addr")
+		if host, export, ok := strings.Cut(config.Device, ":"); ok {
+			if config.Server == "" {
+				config.Server = host
+			}
+			config.Source = export
+		}
+	case fsType == "cifs" || fsType == "smb3" || fsType == "smbfs":
+		// The device is //server/share[/path]
+		config.Kind = models.DriverConfigCIFS
+		host, share, _ := strings.Cut(strings.TrimPrefix(config.Device, "//"), "/")
+		config.Server = mountOption(config.MountOptions, "addr")
+		if config.Server == "" {
+			config.Server = host
+		}
+		config.Source = share
+	case fsType == "tmpfs":
+		config.Kind = models.DriverConfigTmpfs
+	default:
+		config.Kind = models.DriverConfigDevice
+	}
+	return config
+}
+
+// hasMountOption reports whether a flag such as ro or bind is set
+func hasMountOption(options []string, flag string) bool {
+	for _, option := range options {
+		if option == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// mountOption returns the value of a key=value mount option
+func mountOption(options []string, key string) string {
+	for _, option := range options {
+		if k, value, ok := strings.Cut(option, "="); ok && k == key {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,420 @@
+package volumes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+)
+
+// Duplicate detection defaults, overridden by EnableDuplicateDetection
+const (
+	defaultDuplicateSampleFiles = 32
+	defaultDuplicateMaxFiles    = 100000
+)
+
+const (
+	// duplicateSampleChunk is how much of the start, and of the end, of each
+	// sampled file is hashed
+	duplicateSampleChunk = 64 << 10
+
+	// duplicateHighCoverage is the share of a group's bytes that must have been
+	// hashed for its match to count as high confidence
+	duplicateHighCoverage = 0.1
+
+	duplicatesNote = "Heuristic: volumes are matched on every file path and size and on the content of a sample of files; verify before deleting data"
+)
+
+// volumeFingerprint summarizes a volume's content cheaply enough to compare
+// across volumes
+type volumeFingerprint struct {
+	files        int
+	bytes        int64
+	structure    string // Digest of every path, type and file size walked
+	content      string // Digest of the sampled file content
+	sampledBytes int64
+	truncated    bool // The walk stopped at the file limit
+	at           time.Time
+}
+
+// duplicateIndex holds the latest fingerprint of every volume
+type duplicateIndex struct {
+	sampleFiles int
+	maxFiles    int
+
+	mu           sync.RWMutex
+	fingerprints map[string]volumeFingerprint
+	runAt        time.Time // End of the last fingerprinting run
+}
+
+// EnableDuplicateDetection makes FingerprintVolumes fingerprint volumes for
+// the duplicates report, hashing sampleFiles files of each volume and walking
+// at most maxFiles; values below one keep the defaults
+func (h *Handler) EnableDuplicateDetection(sampleFiles, maxFiles int) {
+	index := &duplicateIndex{
+		sampleFiles:  defaultDuplicateSampleFiles,
+		maxFiles:     defaultDuplicateMaxFiles,
+		fingerprints: make(map[string]volumeFingerprint),
+	}
+	if sampleFiles > 0 {
+		index.sampleFiles = sampleFiles
+	}
+	if maxFiles > 0 {
+		index.maxFiles = maxFiles
+	}
+	h.duplicates = index
+}
+
+// FingerprintVolumes fingerprints every volume that may be scanned, one at a
+// time, and replaces the previous fingerprints with the new ones. Volumes that
+// cannot be read are left out. It returns how many volumes were fingerprinted.
+func (h *Handler) FingerprintVolumes(ctx context.Context) (int, error) {
+	if h.duplicates == nil {
+		return 0, errors.New("duplicate detection is not enabled")
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list volumes: %w", err)
+	}
+
+	fingerprints := make(map[string]volumeFingerprint, len(volumes))
+	for _, vol := range volumes {
+		if err := ctx.Err(); err != nil {
+			return len(fingerprints), err
+		}
+		if h.isSystemVolume(vol) {
+			continue
+		}
+		if scannable, _ := h.volumeScannable(vol); !scannable {
+			continue
+		}
+		if enabled, err := h.volumeScanEnabled(ctx, vol.Name); err != nil || !enabled {
+			continue
+		}
+		root, reason := h.volumeRootPath(vol)
+		if reason != "" {
+			continue
+		}
+
+		fingerprint, err := fingerprintTree(ctx, root, h.duplicates.sampleFiles, h.duplicates.maxFiles)
+		if err != nil {
+			log.Printf("[WARN] Failed to fingerprint volume %s: %v", vol.Name, err)
+			continue
+		}
+		fingerprints[vol.Name] = fingerprint
+	}
+
+	h.duplicates.mu.Lock()
+	h.duplicates.fingerprints = fingerprints
+	h.duplicates.runAt = time.Now().UTC()
+	h.duplicates.mu.Unlock()
+	return len(fingerprints), nil
+}
+
+// treeFile is a regular file found while walking a volume
+type treeFile struct {
+	path string
+	size int64
+	rank string // Orders files for sampling by their path alone
+}
+
+// fingerprintTree walks the directory tree at root without following
+// symlinks, digesting every entry's path, type and size, and the content of
+// the sampleFiles files whose paths hash lowest. Sampling by path picks the
+// same files in identical trees. The walk stops after maxFiles files.
+func fingerprintTree(ctx context.Context, root string, sampleFiles, maxFiles int) (volumeFingerprint, error) {
+	fingerprint := volumeFingerprint{at: time.Now().UTC()}
+
+	dir, err := os.OpenRoot(root)
+	if err != nil {
+		return fingerprint, err
+	}
+	defer dir.Close()
+
+	structure := sha256.New()
+	files := make([]treeFile, 0)
+	errLimit := errors.New("file limit reached")
+	err = fs.WalkDir(dir.FS(), ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == "." {
+			return nil
+		}
+
+		switch {
+		case entry.IsDir():
+			fmt.Fprintf(structure, "d %s\n", path)
+		case entry.Type()&fs.ModeSymlink != 0:
+			// Readlink does not follow the link itself, so it stays in the volume
+			target, err := os.Readlink(filepath.Join(root, filepath.FromSlash(path)))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(structure, "l %s %s\n", path, target)
+		case entry.Type().IsRegular():
+			if len(files) == maxFiles {
+				fingerprint.truncated = true
+				return errLimit
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(structure, "f %s %d\n", path, info.Size())
+			rank := sha256.Sum256([]byte(path))
+			files = append(files, treeFile{path: path, size: info.Size(), rank: string(rank[:])})
+			fingerprint.bytes += info.Size()
+		default:
+			// Sockets, FIFOs and devices have no content worth comparing
+			fmt.Fprintf(structure, "s %s\n", path)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimit) {
+		return fingerprint, err
+	}
+	fingerprint.files = len(files)
+	fingerprint.structure = hex.EncodeToString(structure.Sum(nil))
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].rank < files[j].rank
+	})
+	sample := files[:min(sampleFiles, len(files))]
+	sort.Slice(sample, func(i, j int) bool {
+		return sample[i].path < sample[j].path
+	})
+
+	content := sha256.New()
+	for _, file := range sample {
+		sampled, err := hashFileSample(dir, file, content)
+		if err != nil {
+			return fingerprint, err
+		}
+		fingerprint.sampledBytes += sampled
+	}
+	fingerprint.content = hex.EncodeToString(content.Sum(nil))
+	return fingerprint, nil
+}
+
+// hashFileSample writes the path and the first and last duplicateSampleChunk
+// bytes of a file to content and returns how many bytes of it were read
+func hashFileSample(dir *os.Root, file treeFile, content hash.Hash) (int64, error) {
+	f, err := dir.Open(file.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(content, "%s\n", file.path)
+	if file.size <= 2*duplicateSampleChunk {
+		return io.Copy(content, f)
+	}
+
+	head, err := io.CopyN(content, f, duplicateSampleChunk)
+	if err != nil {
+		return head, err
+	}
+	if _, err := f.Seek(-duplicateSampleChunk, io.SeekEnd); err != nil {
+		return head, err
+	}
+	tail, err := io.Copy(content, f)
+	return head + tail, err
+}
+
+// confidence rates how likely volumes sharing this fingerprint hold the same data
+func (f volumeFingerprint) confidence() string {
+	if f.truncated {
+		return models.DuplicateConfidenceLow
+	}
+	if float64(f.sampledBytes) >= duplicateHighCoverage*float64(f.bytes) {
+		return models.DuplicateConfidenceHigh
+	}
+	return models.DuplicateConfidenceMedium
+}
+
+// GetDuplicatesReport lists groups of volumes with matching fingerprints from
+// the last fingerprinting run, most reclaimable bytes first. Empty volumes are
+// not reported, since any two of them match.
+// Implements GET /api/v1/reports/duplicates
+func (h *Handler) GetDuplicatesReport(c *gin.Context) {
+	if h.duplicates == nil {
+		apiutils.RespondWithError(c, http.StatusNotFound, apiutils.ErrorCodeNotFound,
+			"Duplicate detection is disabled; set SCAN_DUPLICATES_ENABLED=true to enable it", nil)
+		return
+	}
+	cursor, err := apiutils.ParseReportCursor(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	h.duplicates.mu.RLock()
+	byFingerprint := make(map[string][]string)
+	fingerprints := make(map[string]volumeFingerprint)
+	for name, fingerprint := range h.duplicates.fingerprints {
+		if fingerprint.files == 0 {
+			continue
+		}
+		key := fingerprint.structure + fingerprint.content
+		byFingerprint[key] = append(byFingerprint[key], name)
+		fingerprints[name] = fingerprint
+	}
+	fingerprinted := len(h.duplicates.fingerprints)
+	runAt := h.duplicates.runAt
+	h.duplicates.mu.RUnlock()
+
+	asStrings := middleware.SizesAsStrings(c)
+	report := models.DuplicatesReportV1{
+		Groups:                make([]models.DuplicateGroupV1, 0),
+		TotalReclaimableBytes: &models.SizeBytes{AsString: asStrings},
+		VolumesFingerprinted:  fingerprinted,
+		Heuristic:             true,
+		Note:                  duplicatesNote,
+		GeneratedAt:           time.Now().UTC(),
+	}
+	if !runAt.IsZero() {
+		report.FingerprintedAt = &runAt
+	}
+
+	for _, names := range byFingerprint {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+
+		group := models.DuplicateGroupV1{Volumes: names, Confidence: models.DuplicateConfidenceHigh}
+		for _, name := range names {
+			fingerprint := fingerprints[name]
+			// A group is only as certain as its least complete fingerprint
+			if confidence := fingerprint.confidence(); confidenceRank(confidence) < confidenceRank(group.Confidence) {
+				group.Confidence = confidence
+			}
+			if group.FingerprintedAt.IsZero() || fingerprint.at.Before(group.FingerprintedAt) {
+				group.FingerprintedAt = fingerprint.at
+			}
+		}
+		first := fingerprints[names[0]]
+		reclaimable := first.bytes * int64(len(names)-1)
+		group.FileCount = first.files
+		group.SizeBytes = models.NewSizeBytes(&first.bytes, asStrings)
+		group.ReclaimableBytes = models.NewSizeBytes(&reclaimable, asStrings)
+		group.SampledFraction = float64(first.sampledBytes) / float64(max(first.bytes, 1))
+
+		report.Groups = append(report.Groups, group)
+		report.TotalReclaimableBytes.Value += reclaimable
+	}
+	report.Total = len(report.Groups)
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.ReclaimableBytes.Value != b.ReclaimableBytes.Value {
+			return a.ReclaimableBytes.Value > b.ReclaimableBytes.Value
+		}
+		return a.Volumes[0] < b.Volumes[0]
+	})
+
+	remaining := report.Groups[min(cursor, len(report.Groups)):]
+	apiutils.RespondWithReport(c, cursor, len(remaining), func(n int, page *models.ReportPageV1) interface{} {
+		report.Groups = remaining[:n]
+		report.ReportPageV1 = page
+		return report
+	})
+}
+
+// confidenceRank orders duplicate confidences from low to high
+func confidenceRank(confidence string) int {
+	switch confidence {
+	case models.DuplicateConfidenceHigh:
+		return 2
+	case models.DuplicateConfidenceMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DuplicateJob periodically fingerprints volumes for the duplicates report
+type DuplicateJob struct {
+	handler  *Handler
+	interval time.Duration
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// newDuplicateJob creates a job fingerprinting the handler's volumes every interval
+func newDuplicateJob(handler *Handler, interval time.Duration) *DuplicateJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DuplicateJob{
+		handler:  handler,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start fingerprints immediately and then on every interval
+func (j *DuplicateJob) Start() {
+	if j.interval <= 0 {
+		close(j.doneCh)
+		return
+	}
+
+	go func() {
+		defer close(j.doneCh)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.Run()
+			select {
+			case <-ticker.C:
+			case <-j.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the job, abandoning a run in progress, and waits for it to return
+func (j *DuplicateJob) Stop() {
+	j.stopOnce.Do(j.cancel)
+	<-j.doneCh
+}
+
+// Run fingerprints volumes once
+func (j *DuplicateJob) Run() {
+	start := time.Now()
+	count, err := j.handler.FingerprintVolumes(j.ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Printf("[ERROR] Volume fingerprinting failed: %v", err)
+		}
+		return
+	}
+	log.Printf("[DEBUG] Fingerprinted %d volumes for duplicate detection in %v", count, time.Since(start))
+}
@@ -3,45 +3,170 @@
 package volumes
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"regexp"
+	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
 	"github.com/mantonx/volumeviz/internal/api/models"
 	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/mantonx/volumeviz/internal/interfaces"
 	coremodels "github.com/mantonx/volumeviz/internal/models"
+	"github.com/mantonx/volumeviz/internal/scheduler"
 	"github.com/mantonx/volumeviz/internal/utils"
 	"github.com/mantonx/volumeviz/internal/websocket"
 )
 
+// defaultInlineAttachments is the most attachments a volume detail lists
+const defaultInlineAttachments = 25
+
 // Handler handles volume-related HTTP requests
 // Provides REST endpoints for Docker volume operations
 type Handler struct {
-	dockerService    interfaces.DockerService
-	hub              *websocket.Hub
-	database         *database.DB
-	systemVolumeRegex *regexp.Regexp
+	dockerService     interfaces.DockerService
+	hub               *websocket.Hub
+	database          *database.DB
+	systemVolumeRegex *utils.Pattern
+	sizePolicy        *config.SizePolicy
+	pruneConfirmer    *pruneConfirmer // Set when prunes must be confirmed by a dry run
+	pruneConfirmErr   error           // Set when prunes must be confirmed but the confirmer failed; prunes are refused
+	sizeStaleAfter    time.Duration   // Age at which sizes from scan stats are flagged stale; zero never
+	attachments       *attachmentTracker
+	detaches          *attachmentTracker // When any volume was last seen mounted, for its orphaned state
+	countStopped      bool               // Whether stopped containers keep a volume from being orphaned by default
+	quarantine        *quarantinePolicy  // Set when orphaned volumes are quarantined before deletion
+	inlineAttachments int                // Most attachments listed in volume details; zero lists all
+	probes            *mountProbes
+	probeTimeout      time.Duration
+	probePath         func(path string) error // Checks that a volume's mounted directory answers
+	pathRewrites      []config.PathRewrite    // Map host paths to the paths visible here, first match wins
+	duplicates        *duplicateIndex         // Set when volumes are fingerprinted for the duplicates report
 }
 
 // NewHandler creates a new volume handler
 // Pass in your Docker service, WebSocket hub, and database to get started
 func NewHandler(dockerService interfaces.DockerService, hub *websocket.Hub, db *database.DB) *Handler {
 	// Default system volume regex pattern
-	pattern := `^(docker_|builder_|containerd|_data$)`
-	regex, _ := regexp.Compile(pattern)
-	
+	regex := utils.MustCompilePattern(`^(docker_|builder_|containerd|_data$)`)
+
 	return &Handler{
 		dockerService:     dockerService,
 		hub:               hub,
 		database:          db,
 		systemVolumeRegex: regex,
+		attachments:       newAttachmentTracker(defaultAnonymousOrphanGrace),
+		detaches:          newAttachmentTracker(defaultDetachWindow),
+		countStopped:      true,
+		inlineAttachments: defaultInlineAttachments,
+		probes:            newMountProbes(defaultProbeConcurrency),
+		probeTimeout:      defaultProbeTimeout,
+		probePath:         probeMountPath,
+	}
+}
+
+// NewHandlerWithSizePolicy creates a volume handler that reports volumes on
+// size-unsupported drivers without a size
+func NewHandlerWithSizePolicy(dockerService interfaces.DockerService, hub *websocket.Hub, db *database.DB, sizePolicy *config.SizePolicy) *Handler {
+	h := NewHandler(dockerService, hub, db)
+	h.sizePolicy = sizePolicy
+	return h
+}
+
+// RequirePruneConfirmation makes a prune present the token issued by a dry run
+// of the same selection within ttl. If the signing key cannot be created,
+// every prune request is refused instead.
+func (h *Handler) RequirePruneConfirmation(ttl time.Duration) error {
+	confirmer, err := newPruneConfirmer(ttl)
+	if err != nil {
+		h.pruneConfirmErr = fmt.Errorf("failed to create prune confirmation key: %w", err)
+		return h.pruneConfirmErr
+	}
+	h.pruneConfirmer = confirmer
+	return nil
+}
+
+// SetSizeStaleAfter sets the age at which listed sizes from scan stats are
+// flagged as stale; zero never flags them
+func (h *Handler) SetSizeStaleAfter(after time.Duration) {
+	h.sizeStaleAfter = after
+}
+
+// SetInlineAttachments sets the most attachments listed in volume details;
+// zero lists all of them
+func (h *Handler) SetInlineAttachments(limit int) {
+	h.inlineAttachments = limit
+}
+
+// SetAnonymousOrphanGrace sets how long an anonymous volume without containers
+// is treated as torn down with its container rather than orphaned; zero
+// reports it orphaned right away
+func (h *Handler) SetAnonymousOrphanGrace(grace time.Duration) {
+	h.attachments = newAttachmentTracker(grace)
+}
+
+// SetCountStopped sets whether containers that are not running count as
+// attachments when telling if a volume is orphaned, for requests that leave
+// count_stopped unset. Prunes and quarantine always count them.
+func (h *Handler) SetCountStopped(countStopped bool) {
+	h.countStopped = countStopped
+}
+
+// SetDetachWindow sets how long a volume whose last container went away is
+// reported as detaching rather than orphaned; zero reports it orphaned right away
+func (h *Handler) SetDetachWindow(window time.Duration) {
+	h.detaches = newAttachmentTracker(window)
+}
+
+// volumeSize returns the known size of a volume and whether its driver supports sizing.
+// Volumes on size-unsupported drivers never report a size, even if usage data is present.
+func (h *Handler) volumeSize(vol coremodels.Volume) (*int64, bool) {
+	if !h.sizePolicy.SizeSupported(vol.Driver) {
+		return nil, false
+	}
+	if vol.UsageData != nil && vol.UsageData.Size >= 0 {
+		size := vol.UsageData.Size
+		return &size, true
+	}
+	return nil, true
+}
+
+// volumeScannable reports whether a volume can be scanned, with the reason when it
+// cannot: its driver does not support sizing, or it has no local mountpoint and no
+// external size command is configured
+func (h *Handler) volumeScannable(vol coremodels.Volume) (bool, string) {
+	if !h.sizePolicy.SizeSupported(vol.Driver) {
+		return false, fmt.Sprintf("driver %s does not support size scanning", vol.Driver)
+	}
+	return h.sizePolicy.Scannable(vol.Mountpoint, vol.Options)
+}
+
+// volumeScanEnabled reports whether scanning of a volume was left switched on
+// (see database.AnnotationKeyScanEnabled); always without a database
+func (h *Handler) volumeScanEnabled(ctx context.Context, volumeName string) (bool, error) {
+	if h.database == nil {
+		return true, nil
+	}
+	return database.NewVolumeAnnotationRepository(h.database).IsScanEnabled(ctx, volumeName)
+}
+
+// volumeNode returns the node a volume lives on. Volumes without node
+// information, e.g. on non-Swarm hosts, are on the "local" node.
+func volumeNode(vol coremodels.Volume) string {
+	if vol.Node == "" {
+		return coremodels.LocalNodeName
 	}
+	return vol.Node
 }
 
 // ListVolumes returns paginated Docker volumes with metadata
@@ -74,16 +199,17 @@ func (h *Handler) ListVolumes(c *gin.Context) {
 	// Try to get volumes from database first
 	var apiVolumes []models.VolumeV1
 	var total int64
+	var partial bool
 
 	if h.database != nil {
 		apiVolumes, total, err = h.getVolumesFromDB(ctx, pagination, sortParams, filters)
 		if err != nil {
 			// Fall back to Docker API if DB fails
-			apiVolumes, total, err = h.getVolumesFromDocker(ctx, pagination, sortParams, filters)
+			apiVolumes, total, partial, err = h.getVolumesFromDocker(ctx, pagination, sortParams, filters)
 		}
 	} else {
 		// No database, use Docker API
-		apiVolumes, total, err = h.getVolumesFromDocker(ctx, pagination, sortParams, filters)
+		apiVolumes, total, partial, err = h.getVolumesFromDocker(ctx, pagination, sortParams, filters)
 	}
 
 	if err != nil {
@@ -91,6 +217,15 @@ func (h *Handler) ListVolumes(c *gin.Context) {
 		return
 	}
 
+	// Encode sizes as strings when the client asked for it
+	if middleware.SizesAsStrings(c) {
+		for i := range apiVolumes {
+			if apiVolumes[i].SizeBytes != nil {
+				apiVolumes[i].SizeBytes.AsString = true
+			}
+		}
+	}
+
 	// Build filters map for response
 	filtersMap := make(map[string]interface{})
 	if filters.Query != "" {
@@ -99,16 +234,38 @@ func (h *Handler) ListVolumes(c *gin.Context) {
 	if filters.Driver != "" {
 		filtersMap["driver"] = filters.Driver
 	}
+	if filters.Project != "" {
+		filtersMap["project"] = filters.Project
+	}
 	if filters.Orphaned != nil {
 		filtersMap["orphaned"] = *filters.Orphaned
 	}
+	if filters.AllReadOnly != nil {
+		filtersMap["all_readonly"] = *filters.AllReadOnly
+	}
+	if filters.Mountpoint != nil {
+		filtersMap["mountpoint"] = c.Query("mountpoint")
+	}
+	if filters.Device != nil {
+		filtersMap["device"] = c.Query("device")
+	}
 	if filters.System {
 		filtersMap["system"] = filters.System
 	}
 
 	// Build paginated response
 	response := apiutils.BuildPagedResponse(apiVolumes, pagination, total, sortParams, filtersMap)
-	c.JSON(http.StatusOK, response)
+	if !partial {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	c.JSON(http.StatusOK, struct {
+		apiutils.PagedResponse
+		models.PartialResultV1
+	}{response, models.PartialResultV1{
+		Partial: true,
+		Warning: "The request reached its Docker API call budget; only the volumes checked before it ran out are listed",
+	}})
 }
 
 // getVolumesFromDB retrieves volumes from the database with filtering and pagination
@@ -118,24 +275,42 @@ func (h *Handler) getVolumesFromDB(ctx context.Context, pagination *apiutils.Pag
 	return nil, 0, fmt.Errorf("database query not yet implemented")
 }
 
-// getVolumesFromDocker retrieves volumes from Docker API with filtering
-func (h *Handler) getVolumesFromDocker(ctx context.Context, pagination *apiutils.PaginationParams, sortParams []apiutils.SortParam, filters *apiutils.VolumeFilters) ([]models.VolumeV1, int64, error) {
+// getVolumesFromDocker retrieves volumes from Docker API with filtering. When
+// the request runs out of Docker API call budget it returns the volumes
+// converted so far with partial set.
+func (h *Handler) getVolumesFromDocker(ctx context.Context, pagination *apiutils.PaginationParams, sortParams []apiutils.SortParam, filters *apiutils.VolumeFilters) ([]models.VolumeV1, int64, bool, error) {
 	// Get all volumes from Docker
 	volumes, err := h.dockerService.ListVolumes(ctx)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, false, err
+	}
+	volumes, err = h.withoutQuarantined(ctx, volumes)
+	if err != nil {
+		return nil, 0, false, err
 	}
 
 	// Apply filters
-	filtered := h.filterVolumes(volumes, filters)
+	filtered, listed, partial := h.filterVolumes(ctx, volumes, filters)
 
-	// Convert to API format
+	// Convert to API format, reusing the containers the filters listed
+	countStopped := h.countsStopped(filters.CountStopped)
 	apiVolumes := make([]models.VolumeV1, 0, len(filtered))
 	for _, vol := range filtered {
-		apiVol := h.convertToAPIVolume(vol)
+		if containers, ok := listed[vol.ID]; ok {
+			apiVolumes = append(apiVolumes, h.apiVolume(vol, containers, countStopped))
+			continue
+		}
+		apiVol, err := h.convertToAPIVolume(ctx, vol, countStopped)
+		if err != nil {
+			partial = true
+			break
+		}
 		apiVolumes = append(apiVolumes, apiVol)
 	}
 
+	// Fill in scanned sizes before sorting so size_bytes ordering sees them
+	h.applyLatestScanStats(ctx, apiVolumes)
+
 	// Sort volumes
 	h.sortVolumes(apiVolumes, sortParams)
 
@@ -153,12 +328,50 @@ func (h *Handler) getVolumesFromDocker(ctx context.Context, pagination *apiutils
 	}
 	apiVolumes = apiVolumes[start:end]
 
-	return apiVolumes, total, nil
+	return apiVolumes, total, partial, nil
 }
 
-// filterVolumes applies filters to the volume list
-func (h *Handler) filterVolumes(volumes []coremodels.Volume, filters *apiutils.VolumeFilters) []coremodels.Volume {
-	filtered := make([]coremodels.Volume, 0, len(volumes))
+// applyLatestScanStats sets the last scan time of each volume from its latest
+// scan stats row and, when Docker reports no size, the scanned size together
+// with when it was measured and whether it is stale
+func (h *Handler) applyLatestScanStats(ctx context.Context, apiVolumes []models.VolumeV1) {
+	if h.database == nil || len(apiVolumes) == 0 {
+		return
+	}
+
+	latest, err := scheduler.NewRepository(h.database).GetLatestVolumeStatsByVolume(ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed to load latest scan stats for volume list: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range apiVolumes {
+		stats, ok := latest[apiVolumes[i].Name]
+		if !ok {
+			continue
+		}
+
+		scannedAt := stats.Timestamp
+		apiVolumes[i].LastScanAt = &scannedAt
+		if !apiVolumes[i].SizeSupported || apiVolumes[i].SizeBytes != nil {
+			continue
+		}
+
+		size := stats.SizeBytes
+		apiVolumes[i].SizeBytes = models.NewSizeBytes(&size, false)
+		apiVolumes[i].SizeScannedAt = &scannedAt
+		apiVolumes[i].SizeStale = h.sizeStaleAfter > 0 && now.Sub(scannedAt) > h.sizeStaleAfter
+	}
+}
+
+// filterVolumes applies filters to the volume list, returning the containers
+// of each volume it had to list them for
+// It stops at the first volume whose containers the request has no Docker API
+// call budget left to list, returning the volumes kept so far with partial set.
+func (h *Handler) filterVolumes(ctx context.Context, volumes []coremodels.Volume, filters *apiutils.VolumeFilters) (filtered []coremodels.Volume, listed map[string][]coremodels.VolumeContainer, partial bool) {
+	filtered = make([]coremodels.Volume, 0, len(volumes))
+	listed = make(map[string][]coremodels.VolumeContainer)
 
 	for _, vol := range volumes {
 		// Apply system filter
@@ -171,11 +384,24 @@ func (h *Handler) filterVolumes(volumes []coremodels.Volume, filters *apiutils.V
 			continue
 		}
 
+		// Apply Compose project filter
+		if filters.Project != "" && composeProject(vol) != filters.Project {
+			continue
+		}
+
 		// Apply search query
 		if filters.Query != "" && !h.volumeMatchesQuery(vol, filters.Query) {
 			continue
 		}
 
+		// Apply host path filters
+		if filters.Mountpoint != nil && !filters.Mountpoint.Matches(vol.Mountpoint) {
+			continue
+		}
+		if filters.Device != nil && !filters.Device.Matches(vol.Options["device"]) {
+			continue
+		}
+
 		// Apply date filters
 		if filters.CreatedAfter != nil && vol.CreatedAt.Before(*filters.CreatedAfter) {
 			continue
@@ -184,44 +410,69 @@ func (h *Handler) filterVolumes(volumes []coremodels.Volume, filters *apiutils.V
 			continue
 		}
 
-		// Apply orphaned filter (requires container check)
-		if filters.Orphaned != nil {
-			containers, _ := h.dockerService.GetVolumeContainers(context.Background(), vol.ID)
-			isOrphaned := len(containers) == 0
-			if *filters.Orphaned != isOrphaned {
+		// Apply orphaned and read-only filters (require container check)
+		if filters.Orphaned != nil || filters.AllReadOnly != nil {
+			containers, err := h.dockerService.GetVolumeContainers(ctx, vol.ID)
+			if errors.Is(err, interfaces.ErrDockerCallBudgetExhausted) {
+				return filtered, listed, true
+			}
+			listed[vol.ID] = containers
+			if filters.Orphaned != nil && *filters.Orphaned != h.isOrphaned(vol, orphanAttachments(containers, h.countsStopped(filters.CountStopped))) {
 				continue
 			}
+			if filters.AllReadOnly != nil {
+				if readOnly := allReadOnly(containers); readOnly == nil || *readOnly != *filters.AllReadOnly {
+					continue
+				}
+			}
 		}
 
 		filtered = append(filtered, vol)
 	}
 
-	return filtered
+	return filtered, listed, false
 }
 
 // convertToAPIVolume converts internal volume model to API format
-func (h *Handler) convertToAPIVolume(vol coremodels.Volume) models.VolumeV1 {
+// The only error it returns is ErrDockerCallBudgetExhausted, when the request
+// has no budget left to list the volume's containers. The volume itself is
+// not inspected again, so one removed since it was listed is still converted
+// as listed, and containers removed since they were listed are skipped.
+func (h *Handler) convertToAPIVolume(ctx context.Context, vol coremodels.Volume, countStopped bool) (models.VolumeV1, error) {
 	// Get container count for attachments_count
-	containers, _ := h.dockerService.GetVolumeContainers(context.Background(), vol.ID)
+	containers, err := h.dockerService.GetVolumeContainers(ctx, vol.ID)
+	if errors.Is(err, interfaces.ErrDockerCallBudgetExhausted) {
+		return models.VolumeV1{}, err
+	}
+	return h.apiVolume(vol, containers, countStopped), nil
+}
+
+// apiVolume converts internal volume model to API format given the
+// containers using it
+func (h *Handler) apiVolume(vol coremodels.Volume, containers []coremodels.VolumeContainer, countStopped bool) models.VolumeV1 {
 	attachmentsCount := len(containers)
 
 	// Get size if available from volume usage data
-	var sizeBytes *int64
-	if vol.UsageData != nil && vol.UsageData.Size >= 0 {
-		sizeBytes = &vol.UsageData.Size
-	}
+	sizeBytes, sizeSupported := h.volumeSize(vol)
+	scannable, unscannableReason := h.volumeScannable(vol)
 
 	return models.VolumeV1{
-		Name:             vol.Name,
-		Driver:           vol.Driver,
-		CreatedAt:        vol.CreatedAt,
-		Labels:           vol.Labels,
-		Scope:            vol.Scope,
-		Mountpoint:       vol.Mountpoint,
-		SizeBytes:        sizeBytes,
-		AttachmentsCount: attachmentsCount,
-		IsSystem:         h.isSystemVolume(vol),
-		IsOrphaned:       attachmentsCount == 0,
+		Name:              vol.Name,
+		Driver:            vol.Driver,
+		CreatedAt:         vol.CreatedAt,
+		Labels:            vol.Labels,
+		Scope:             vol.Scope,
+		Mountpoint:        vol.Mountpoint,
+		Node:              volumeNode(vol),
+		SizeBytes:         models.NewSizeBytes(sizeBytes, false),
+		SizeSupported:     sizeSupported,
+		Scannable:         scannable,
+		UnscannableReason: unscannableReason,
+		AttachmentsCount:  attachmentsCount,
+		AllReadOnly:       allReadOnly(containers),
+		IsSystem:          h.isSystemVolume(vol),
+		IsOrphaned:        h.isOrphaned(vol, orphanAttachments(containers, countStopped)),
+		OrphanedState:     h.orphanedState(vol, containers),
 	}
 }
 
@@ -302,63 +553,72 @@ func (h *Handler) sortVolumes(volumes []models.VolumeV1, sortParams []apiutils.S
 // Sorting helper functions
 func sortVolumesByName(volumes []models.VolumeV1, asc bool) {
 	sort.Slice(volumes, func(i, j int) bool {
-		if asc {
-			return volumes[i].Name < volumes[j].Name
-		}
-		return volumes[i].Name > volumes[j].Name
+		return orderedByNameOnTie(directed(strings.Compare(volumes[i].Name, volumes[j].Name), asc), volumes[i].Name, volumes[j].Name)
 	})
 }
 
 func sortVolumesByCreatedAt(volumes []models.VolumeV1, asc bool) {
 	sort.Slice(volumes, func(i, j int) bool {
-		if asc {
-			return volumes[i].CreatedAt.Before(volumes[j].CreatedAt)
-		}
-		return volumes[i].CreatedAt.After(volumes[j].CreatedAt)
+		return orderedByNameOnTie(directed(volumes[i].CreatedAt.Compare(volumes[j].CreatedAt), asc), volumes[i].Name, volumes[j].Name)
 	})
 }
 
 func sortVolumesBySize(volumes []models.VolumeV1, asc bool) {
 	sort.Slice(volumes, func(i, j int) bool {
-		sizeI := int64(0)
-		sizeJ := int64(0)
-		if volumes[i].SizeBytes != nil {
-			sizeI = *volumes[i].SizeBytes
-		}
-		if volumes[j].SizeBytes != nil {
-			sizeJ = *volumes[j].SizeBytes
-		}
-		if asc {
-			return sizeI < sizeJ
-		}
-		return sizeI > sizeJ
+		return orderedByNameOnTie(directed(cmp.Compare(sizeValue(volumes[i].SizeBytes), sizeValue(volumes[j].SizeBytes)), asc), volumes[i].Name, volumes[j].Name)
 	})
 }
 
 func sortVolumesByDriver(volumes []models.VolumeV1, asc bool) {
 	sort.Slice(volumes, func(i, j int) bool {
-		if asc {
-			return volumes[i].Driver < volumes[j].Driver
-		}
-		return volumes[i].Driver > volumes[j].Driver
+		return orderedByNameOnTie(directed(strings.Compare(volumes[i].Driver, volumes[j].Driver), asc), volumes[i].Name, volumes[j].Name)
 	})
 }
 
+// directed flips a comparison result for descending sorts
+func directed(result int, asc bool) int {
+	if asc {
+		return result
+	}
+	return -result
+}
+
+// orderedByNameOnTie reports whether a volume sorts before another given their
+// directed comparison, breaking ties by the unique volume name (always ascending)
+// so that pages stay stable across requests
+func orderedByNameOnTie(result int, nameI, nameJ string) bool {
+	if result != 0 {
+		return result < 0
+	}
+	return nameI < nameJ
+}
+
+// sizeValue returns a size for sorting, treating unknown sizes as zero
+func sizeValue(size *models.SizeBytes) int64 {
+	if size == nil {
+		return 0
+	}
+	return size.Value
+}
+
 // GetVolume returns detailed information about a specific volume
 // Implements GET /api/v1/volumes/{name}
 func (h *Handler) GetVolume(c *gin.Context) {
 	ctx := c.Request.Context()
-	volumeName := c.Param("name")
-
-	if volumeName == "" {
-		apiutils.RespondWithBadRequest(c, "Volume name is required", nil)
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+	countStopped, err := apiutils.ParseCountStopped(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
 		return
 	}
 
 	// Get volume from Docker
 	volume, err := h.dockerService.GetVolume(ctx, volumeName)
 	if err != nil {
-		if isNotFoundError(err) {
+		if utils.IsNotFound(err) {
 			apiutils.RespondWithNotFound(c, fmt.Sprintf("Volume '%s' not found", volumeName))
 			return
 		}
@@ -368,12 +628,84 @@ func (h *Handler) GetVolume(c *gin.Context) {
 
 	// Get container attachments
 	containers, err := h.dockerService.GetVolumeContainers(ctx, volumeName)
+	budgetExhausted := errors.Is(err, interfaces.ErrDockerCallBudgetExhausted)
 	if err != nil {
 		// Don't fail the request, just log the error
 		containers = []coremodels.VolumeContainer{}
 	}
 
-	// Convert containers to attachments
+	detail := h.volumeDetail(c, *volume, containers, h.countsStopped(countStopped))
+	detail.SizeDrift, err = h.volumeSizeDrift(ctx, *volume, middleware.SizesAsStrings(c))
+	if err != nil {
+		// Drift is advisory; the volume is still returned without it
+		log.Printf("[WARN] Failed to compute size drift of volume %s: %v", volumeName, err)
+	}
+	if detail.ScanEnabled, err = h.volumeScanEnabled(ctx, volumeName); err != nil {
+		log.Printf("[WARN] Failed to check whether scanning of volume %s is enabled: %v", volumeName, err)
+	}
+
+	if !budgetExhausted {
+		c.JSON(http.StatusOK, detail)
+		return
+	}
+	// The attachments were not all inspected, so none are reported
+	c.JSON(http.StatusOK, struct {
+		models.VolumeDetailV1
+		models.PartialResultV1
+	}{detail, models.PartialResultV1{
+		Partial: true,
+		Warning: "The request reached its Docker API call budget before the volume's attachments were listed",
+	}})
+}
+
+// volumeDetail builds the detail representation of a volume
+func (h *Handler) volumeDetail(c *gin.Context, volume coremodels.Volume, containers []coremodels.VolumeContainer, countStopped bool) models.VolumeDetailV1 {
+	sizeBytes, sizeSupported := h.volumeSize(volume)
+	scannable, unscannableReason := h.volumeScannable(volume)
+
+	meta := map[string]interface{}{
+		"driver_opts":   volume.Options,
+		"driver_config": driverConfig(volume),
+	}
+	// Driver-reported backend state such as capacity or health
+	if len(volume.Status) > 0 {
+		meta["status"] = volume.Status
+	}
+
+	detail := models.VolumeDetailV1{
+		Name:              volume.Name,
+		Driver:            volume.Driver,
+		CreatedAt:         volume.CreatedAt,
+		Labels:            volume.Labels,
+		Scope:             volume.Scope,
+		Mountpoint:        volume.Mountpoint,
+		Node:              volumeNode(volume),
+		SizeBytes:         models.NewSizeBytes(sizeBytes, middleware.SizesAsStrings(c)),
+		SizeSupported:     sizeSupported,
+		Scannable:         scannable,
+		UnscannableReason: unscannableReason,
+		ScanEnabled:       true,
+		Attachments:       toAttachments(containers),
+		AttachmentsTotal:  len(containers),
+		AllReadOnly:       allReadOnly(containers),
+		IsSystem:          h.isSystemVolume(volume),
+		IsOrphaned:        h.isOrphaned(volume, orphanAttachments(containers, countStopped)),
+		OrphanedState:     h.orphanedState(volume, containers),
+		Meta:              meta,
+	}
+
+	// Heavily shared volumes list their first attachments and point to the
+	// attachments endpoint, whose second page starts after them
+	if limit := h.inlineAttachments; limit > 0 && len(detail.Attachments) > limit {
+		detail.Attachments = detail.Attachments[:limit]
+		detail.MoreAttachments = true
+		detail.AttachmentsURL = fmt.Sprintf("/api/v1/volumes/%s/attachments?page=2&page_size=%d", url.PathEscape(volume.Name), limit)
+	}
+	return detail
+}
+
+// toAttachments converts the containers using a volume to API attachments
+func toAttachments(containers []coremodels.VolumeContainer) []models.AttachmentV1 {
 	attachments := make([]models.AttachmentV1, len(containers))
 	for i, container := range containers {
 		attachments[i] = models.AttachmentV1{
@@ -381,50 +713,42 @@ func (h *Handler) GetVolume(c *gin.Context) {
 			ContainerName: container.Name,
 			MountPath:     container.MountPath,
 			RW:            container.AccessMode == "rw",
+			Propagation:   container.Propagation,
+			Consistency:   container.Consistency,
 		}
 	}
+	return attachments
+}
 
-	// Get size if available
-	var sizeBytes *int64
-	if volume.UsageData != nil && volume.UsageData.Size >= 0 {
-		sizeBytes = &volume.UsageData.Size
+// allReadOnly reports whether every container mounts the volume read-only,
+// or nil when none mounts it, as nothing is known about its consumers then
+func allReadOnly(containers []coremodels.VolumeContainer) *bool {
+	if len(containers) == 0 {
+		return nil
 	}
-
-	// Build response
-	response := models.VolumeDetailV1{
-		Name:        volume.Name,
-		Driver:      volume.Driver,
-		CreatedAt:   volume.CreatedAt,
-		Labels:      volume.Labels,
-		Scope:       volume.Scope,
-		Mountpoint:  volume.Mountpoint,
-		SizeBytes:   sizeBytes,
-		Attachments: attachments,
-		IsSystem:    h.isSystemVolume(*volume),
-		IsOrphaned:  len(attachments) == 0,
-		Meta: map[string]interface{}{
-			"driver_opts": volume.Options,
-		},
+	readOnly := true
+	for _, container := range containers {
+		if container.AccessMode != "ro" {
+			readOnly = false
+			break
+		}
 	}
-
-	c.JSON(http.StatusOK, response)
+	return &readOnly
 }
 
 // GetVolumeAttachments returns all containers using a specific volume
 // Implements GET /api/v1/volumes/{name}/attachments
 func (h *Handler) GetVolumeAttachments(c *gin.Context) {
 	ctx := c.Request.Context()
-	volumeName := c.Param("name")
-
-	if volumeName == "" {
-		apiutils.RespondWithBadRequest(c, "Volume name is required", nil)
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
 		return
 	}
 
 	// First, verify the volume exists
 	_, err := h.dockerService.GetVolume(ctx, volumeName)
 	if err != nil {
-		if isNotFoundError(err) {
+		if utils.IsNotFound(err) {
 			apiutils.RespondWithNotFound(c, fmt.Sprintf("Volume '%s' not found", volumeName))
 			return
 		}
@@ -439,44 +763,60 @@ func (h *Handler) GetVolumeAttachments(c *gin.Context) {
 		return
 	}
 
-	// Convert to API format
-	attachments := make([]models.AttachmentV1, len(containers))
-	for i, container := range containers {
-		attachments[i] = models.AttachmentV1{
-			ContainerID:   container.ID,
-			ContainerName: container.Name,
-			MountPath:     container.MountPath,
-			RW:            container.AccessMode == "rw",
-			// TODO: Add first_seen and last_seen from database
-		}
-	}
-
+	// TODO: Add first_seen and last_seen from database
+	attachments := toAttachments(containers)
 	response := models.AttachmentsListV1{
 		Data:  attachments,
 		Total: len(attachments),
 	}
 
+	// Pages are only cut when asked for, so existing clients keep every attachment
+	_, hasPage := c.GetQuery("page")
+	_, hasPageSize := c.GetQuery("page_size")
+	if hasPage || hasPageSize {
+		pagination, err := apiutils.ParsePaginationParams(c)
+		if err != nil {
+			apiutils.RespondWithBadRequest(c, err.Error(), nil)
+			return
+		}
+		start := min(pagination.Offset, len(attachments))
+		end := min(start+pagination.Limit, len(attachments))
+		response.Data = attachments[start:end]
+		response.Page = pagination.Page
+		response.PageSize = pagination.PageSize
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
 // GetVolumeStats returns volume statistics and usage information
-// GET /api/v1/volumes/:id/stats
+// GET /api/v1/volumes/:name/stats
 func (h *Handler) GetVolumeStats(c *gin.Context) {
 	ctx := c.Request.Context()
-	volumeID := c.Param("id")
+	rawName := c.Param("name")
 
-	if volumeID == "" {
+	if rawName == "" {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Volume ID is required",
-			Code:    "MISSING_VOLUME_ID",
-			Details: map[string]any{"message": "Volume ID parameter is missing from the request"},
+			Error:   "Volume name is required",
+			Code:    "MISSING_VOLUME_NAME",
+			Details: map[string]any{"message": "Volume name parameter is missing from the request"},
+		})
+		return
+	}
+
+	volumeID, err := apiutils.NormalizeVolumeName(rawName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid volume name",
+			Code:    "INVALID_VOLUME_NAME",
+			Details: map[string]any{"error": err.Error(), "pattern": apiutils.VolumeNamePattern},
 		})
 		return
 	}
 
 	volume, err := h.dockerService.GetVolume(ctx, volumeID)
 	if err != nil {
-		if isNotFoundError(err) {
+		if utils.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error:   "Volume not found",
 				Code:    "VOLUME_NOT_FOUND",
@@ -520,21 +860,6 @@ func (h *Handler) GetVolumeStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
-// isNotFoundError checks if the error is a "not found" type error
-func isNotFoundError(err error) bool {
-	return err != nil && (
-	// Check for common Docker "not found" error patterns
-	containsIgnoreCase(err.Error(), "not found") ||
-		containsIgnoreCase(err.Error(), "no such") ||
-		containsIgnoreCase(err.Error(), "doesn't exist"))
-}
-
-// containsIgnoreCase checks if a string contains a substring (case-insensitive)
-// Using our utils for consistency
-func containsIgnoreCase(s, substr string) bool {
-	return utils.ContainsIgnoreCase(s, substr)
-}
-
 // filterUserVolumes filters volumes to only return user-mounted volumes
 // Excludes Docker infrastructure volumes and anonymous volumes
 func filterUserVolumes(volumes []coremodels.Volume) []coremodels.Volume {
@@ -643,7 +968,8 @@ func isAnonymousVolume(name string) bool {
 	return false
 }
 
-// GetOrphanedVolumes returns all volumes with zero attachments
+// GetOrphanedVolumes returns all volumes with zero attachments, leaving out
+// anonymous volumes still being torn down with their container
 // Implements GET /api/v1/reports/orphaned
 func (h *Handler) GetOrphanedVolumes(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -669,36 +995,75 @@ func (h *Handler) GetOrphanedVolumes(c *gin.Context) {
 	// Parse system filter
 	includeSystem := c.DefaultQuery("system", "false") == "true"
 
-	// Get all volumes
+	countStoppedParam, err := apiutils.ParseCountStopped(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+	countStopped := h.countsStopped(countStoppedParam)
+
+	// Get all volumes; quarantined volumes are listed by the quarantine report
 	volumes, err := h.dockerService.ListVolumes(ctx)
 	if err != nil {
 		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
 		return
 	}
+	volumes, err = h.withoutQuarantined(ctx, volumes)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list quarantined volumes", err)
+		return
+	}
+
+	pinned, err := h.pinnedAnnotations(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list pinned volumes", err)
+		return
+	}
 
 	// Filter for orphaned volumes only
+	asStrings := middleware.SizesAsStrings(c)
 	orphaned := make([]models.OrphanedVolumeV1, 0)
+	reclaimable := models.OrphanedReclaimableV1{ReclaimableBytes: models.SizeBytes{AsString: asStrings}}
+	var partial *models.PartialResultV1
 	for _, vol := range volumes {
 		// Skip system volumes unless requested
 		if !includeSystem && h.isSystemVolume(vol) {
 			continue
 		}
 
-		// Check if volume has any containers
-		containers, _ := h.dockerService.GetVolumeContainers(ctx, vol.ID)
-		if len(containers) == 0 {
-			// Get size if available
-			var sizeBytes int64
-			if vol.UsageData != nil && vol.UsageData.Size >= 0 {
-				sizeBytes = vol.UsageData.Size
+		// Check if volume has any containers; a volume whose containers the
+		// budget leaves unlisted is not known to be orphaned, so the report
+		// stops there
+		containers, err := h.dockerService.GetVolumeContainers(ctx, vol.ID)
+		if errors.Is(err, interfaces.ErrDockerCallBudgetExhausted) {
+			partial = &models.PartialResultV1{
+				Partial: true,
+				Warning: "The request reached its Docker API call budget; only the volumes checked before it ran out are reported",
+			}
+			break
+		}
+		if h.isOrphaned(vol, orphanAttachments(containers, countStopped)) {
+			// Get size if available; unsupported drivers report no size at all
+			sizeBytes, sizeSupported := h.volumeSize(vol)
+			if !h.isSystemVolume(vol) && !volumePinned(vol, pinned) {
+				if sizeBytes != nil {
+					reclaimable.ReclaimableBytes.Value += *sizeBytes
+				} else {
+					reclaimable.UnsizedCount++
+				}
+			}
+			if sizeSupported && sizeBytes == nil {
+				zero := int64(0)
+				sizeBytes = &zero
 			}
 
 			orphaned = append(orphaned, models.OrphanedVolumeV1{
-				Name:      vol.Name,
-				Driver:    vol.Driver,
-				SizeBytes: sizeBytes,
-				CreatedAt: vol.CreatedAt,
-				IsSystem:  h.isSystemVolume(vol),
+				Name:          vol.Name,
+				Driver:        vol.Driver,
+				SizeBytes:     models.NewSizeBytes(sizeBytes, asStrings),
+				SizeSupported: sizeSupported,
+				CreatedAt:     vol.CreatedAt,
+				IsSystem:      h.isSystemVolume(vol),
 			})
 		}
 	}
@@ -720,9 +1085,153 @@ func (h *Handler) GetOrphanedVolumes(c *gin.Context) {
 	}
 	orphaned = orphaned[start:end]
 
-	// Build paginated response
-	response := apiutils.BuildPagedResponse(orphaned, pagination, total, sortParams, nil)
-	c.JSON(http.StatusOK, response)
+	// Reclaimable totals always cover every orphaned volume, not just this page
+	apiutils.RespondWithReport(c, pagination.Offset, len(orphaned), func(n int, page *models.ReportPageV1) interface{} {
+		response := apiutils.BuildPagedResponse(orphaned[:n], pagination, total, sortParams, nil)
+		response.ReportPageV1 = page
+		return struct {
+			apiutils.PagedResponse
+			models.OrphanedReclaimableV1
+			*models.PartialResultV1
+		}{response, reclaimable, partial}
+	})
+}
+
+// pinnedAnnotations returns the pinned annotation of every volume that has
+// one; none without a database
+func (h *Handler) pinnedAnnotations(ctx context.Context) (map[string]string, error) {
+	pinned := make(map[string]string)
+	if h.database == nil {
+		return pinned, nil
+	}
+	annotations, err := database.NewVolumeAnnotationRepository(h.database).ListByKey(ctx, database.AnnotationKeyPinned)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range annotations {
+		pinned[a.VolumeName] = a.Value
+	}
+	return pinned, nil
+}
+
+// volumePinned reports whether a volume is pinned, by its annotation or else
+// its label, like prune policies see it
+func volumePinned(vol coremodels.Volume, annotations map[string]string) bool {
+	value, ok := annotations[vol.Name]
+	if !ok {
+		value = vol.Labels[database.AnnotationKeyPinned]
+	}
+	pinned, _ := strconv.ParseBool(value)
+	return pinned
+}
+
+// GetVolumesByNode groups volumes and their total size by the node they live on.
+// On hosts that are not part of a Swarm every volume is on the "local" node.
+// Report limits apply to the volumes listed across all nodes; node totals
+// always cover every volume.
+// Implements GET /api/v1/reports/by-node
+func (h *Handler) GetVolumesByNode(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Parse system filter
+	includeSystem := c.DefaultQuery("system", "false") == "true"
+	cursor, err := apiutils.ParseReportCursor(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+	byNode := make(map[string]*models.NodeVolumesV1)
+	totalVolumes := 0
+	for _, vol := range volumes {
+		if !includeSystem && h.isSystemVolume(vol) {
+			continue
+		}
+
+		node := volumeNode(vol)
+		group, ok := byNode[node]
+		if !ok {
+			group = &models.NodeVolumesV1{
+				Node:           node,
+				TotalSizeBytes: models.SizeBytes{AsString: asStrings},
+				Volumes:        make([]models.NodeVolumeV1, 0),
+			}
+			byNode[node] = group
+		}
+
+		// Unknown sizes and size-unsupported drivers do not count towards the total
+		sizeBytes, sizeSupported := h.volumeSize(vol)
+		if sizeBytes != nil {
+			group.TotalSizeBytes.Value += *sizeBytes
+		}
+
+		group.Volumes = append(group.Volumes, models.NodeVolumeV1{
+			Name:          vol.Name,
+			Driver:        vol.Driver,
+			SizeBytes:     models.NewSizeBytes(sizeBytes, asStrings),
+			SizeSupported: sizeSupported,
+		})
+		group.VolumeCount++
+		totalVolumes++
+	}
+
+	nodes := make([]models.NodeVolumesV1, 0, len(byNode))
+	for _, group := range byNode {
+		sort.Slice(group.Volumes, func(i, j int) bool {
+			return group.Volumes[i].Name < group.Volumes[j].Name
+		})
+		nodes = append(nodes, *group)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Node < nodes[j].Node
+	})
+
+	generatedAt := time.Now().UTC()
+	remaining := max(totalVolumes-cursor, 0)
+	apiutils.RespondWithReport(c, cursor, remaining, func(n int, page *models.ReportPageV1) interface{} {
+		return models.VolumesByNodeReportV1{
+			Nodes:        volumesWindow(nodes, nodeVolumes, cursor, n),
+			TotalVolumes: totalVolumes,
+			GeneratedAt:  generatedAt,
+			ReportPageV1: page,
+		}
+	})
+}
+
+// volumesWindow returns the groups holding the n volumes starting at offset,
+// counting volumes across groups in order, with each group's volumes cut to
+// that window. Group counts and totals are kept as they are. The grouped
+// reports page through their volumes with it; volumes returns a group's list.
+func volumesWindow[G, V any](groups []G, volumes func(*G) *[]V, offset, n int) []G {
+	window := make([]G, 0, len(groups))
+	for _, group := range groups {
+		if n <= 0 {
+			break
+		}
+		list := volumes(&group)
+		if offset >= len(*list) {
+			offset -= len(*list)
+			continue
+		}
+		end := min(offset+n, len(*list))
+		n -= end - offset
+		*list = (*list)[offset:end]
+		offset = 0
+		window = append(window, group)
+	}
+	return window
+}
+
+// nodeVolumes returns the volumes of a node for volumesWindow
+func nodeVolumes(node *models.NodeVolumesV1) *[]models.NodeVolumeV1 {
+	return &node.Volumes
 }
 
 // sortOrphanedVolumes sorts orphaned volumes based on sort parameters
@@ -732,34 +1241,25 @@ func (h *Handler) sortOrphanedVolumes(volumes []models.OrphanedVolumeV1, sortPar
 	}
 
 	param := sortParams[0]
+	asc := param.Direction == "asc"
+
+	var compare func(a, b models.OrphanedVolumeV1) int
 	switch param.Field {
 	case "name":
-		sort.Slice(volumes, func(i, j int) bool {
-			if param.Direction == "asc" {
-				return volumes[i].Name < volumes[j].Name
-			}
-			return volumes[i].Name > volumes[j].Name
-		})
+		compare = func(a, b models.OrphanedVolumeV1) int { return strings.Compare(a.Name, b.Name) }
 	case "driver":
-		sort.Slice(volumes, func(i, j int) bool {
-			if param.Direction == "asc" {
-				return volumes[i].Driver < volumes[j].Driver
-			}
-			return volumes[i].Driver > volumes[j].Driver
-		})
+		compare = func(a, b models.OrphanedVolumeV1) int { return strings.Compare(a.Driver, b.Driver) }
 	case "created_at":
-		sort.Slice(volumes, func(i, j int) bool {
-			if param.Direction == "asc" {
-				return volumes[i].CreatedAt.Before(volumes[j].CreatedAt)
-			}
-			return volumes[i].CreatedAt.After(volumes[j].CreatedAt)
-		})
+		compare = func(a, b models.OrphanedVolumeV1) int { return a.CreatedAt.Compare(b.CreatedAt) }
 	case "size_bytes":
-		sort.Slice(volumes, func(i, j int) bool {
-			if param.Direction == "asc" {
-				return volumes[i].SizeBytes < volumes[j].SizeBytes
-			}
-			return volumes[i].SizeBytes > volumes[j].SizeBytes
-		})
+		compare = func(a, b models.OrphanedVolumeV1) int {
+			return cmp.Compare(sizeValue(a.SizeBytes), sizeValue(b.SizeBytes))
+		}
+	default:
+		return
 	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return orderedByNameOnTie(directed(compare(volumes[i], volumes[j]), asc), volumes[i].Name, volumes[j].Name)
+	})
 }
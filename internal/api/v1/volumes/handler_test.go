@@ -356,6 +356,7 @@ type mockDockerService struct {
 	isDockerAvailable   func(ctx context.Context) bool
 	getVolumesByDriver  func(ctx context.Context, driver string) ([]models.Volume, error)
 	getVolumesByLabel   func(ctx context.Context, labelKey, labelValue string) ([]models.Volume, error)
+	removeVolume        func(ctx context.Context, volumeName string) error
 }
 
 func (m *mockDockerService) Ping(ctx context.Context) error {
@@ -414,6 +415,13 @@ func (m *mockDockerService) GetVolumesByLabel(ctx context.Context, labelKey, lab
 	return []models.Volume{}, nil
 }
 
+func (m *mockDockerService) RemoveVolume(ctx context.Context, volumeName string) error {
+	if m.removeVolume != nil {
+		return m.removeVolume(ctx, volumeName)
+	}
+	return errors.New("not implemented")
+}
+
 func (m *mockDockerService) Close() error {
 	return nil
 }
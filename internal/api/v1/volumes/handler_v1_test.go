@@ -1,18 +1,38 @@
 package volumes
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
 	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/config"
+	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/mantonx/volumeviz/internal/mocks"
 	coremodels "github.com/mantonx/volumeviz/internal/models"
+	"github.com/mantonx/volumeviz/internal/scheduler"
+	"github.com/mantonx/volumeviz/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Removed setupTestHandler as it's unused
@@ -203,6 +223,30 @@ func TestGetVolume_V1API(t *testing.T) {
 				assert.Equal(t, "container1", attachment["container_id"])
 				assert.Equal(t, "/data", attachment["mount_path"])
 				assert.Equal(t, true, attachment["rw"])
+				meta := response["meta"].(map[string]interface{})
+				assert.NotContains(t, meta, "status", "empty driver status is omitted")
+			},
+		},
+		{
+			name:       "volume with driver status",
+			volumeName: "csi-volume",
+			setupMock: func(m *mocks.DockerService) {
+				volume := &coremodels.Volume{
+					Name:       "csi-volume",
+					Driver:     "csi-driver",
+					CreatedAt:  time.Now(),
+					Mountpoint: "/var/lib/docker/plugins/csi/csi-volume",
+					Scope:      "global",
+					Status:     map[string]string{"capacity": "10GiB", "health": "ok"},
+				}
+				m.On("GetVolume", mock.Anything, "csi-volume").Return(volume, nil)
+				m.On("GetVolumeContainers", mock.Anything, "csi-volume").Return([]coremodels.VolumeContainer{}, nil)
+			},
+			expectedStatus: 200,
+			checkResponse: func(t *testing.T, body []byte) {
+				var response models.VolumeDetailV1
+				assert.NoError(t, json.Unmarshal(body, &response))
+				assert.Equal(t, map[string]interface{}{"capacity": "10GiB", "health": "ok"}, response.Meta["status"])
 			},
 		},
 		{
@@ -216,6 +260,24 @@ func TestGetVolume_V1API(t *testing.T) {
 				assert.Contains(t, string(body), "not found")
 			},
 		},
+		{
+			name:       "Docker not found error",
+			volumeName: "nonexistent",
+			setupMock: func(m *mocks.DockerService) {
+				m.On("GetVolume", mock.Anything, "nonexistent").Return(nil,
+					fmt.Errorf("failed to get volume nonexistent: %w", errdefs.NotFound(errors.New("Error response from daemon: get nonexistent"))))
+			},
+			expectedStatus: 404,
+		},
+		{
+			name:       "unrelated Docker error mentioning not found",
+			volumeName: "broken",
+			setupMock: func(m *mocks.DockerService) {
+				m.On("GetVolume", mock.Anything, "broken").Return(nil,
+					fmt.Errorf("failed to get volume broken: %w", errdefs.System(errors.New("Error response from daemon: driver plugin not found"))))
+			},
+			expectedStatus: 500,
+		},
 	}
 
 	for _, tt := range tests {
@@ -258,10 +320,12 @@ func TestGetVolumeAttachments_V1API(t *testing.T) {
 				volume := &coremodels.Volume{ID: "vol1", Name: "test-volume"}
 				containers := []coremodels.VolumeContainer{
 					{
-						ID:         "container1",
-						Name:       "test-container-1",
-						MountPath:  "/data",
-						AccessMode: "rw",
+						ID:          "container1",
+						Name:        "test-container-1",
+						MountPath:   "/data",
+						AccessMode:  "rw",
+						Propagation: "rshared",
+						Consistency: "cached",
 					},
 					{
 						ID:         "container2",
@@ -287,11 +351,15 @@ func TestGetVolumeAttachments_V1API(t *testing.T) {
 				assert.Equal(t, "test-container-1", attachment1["container_name"])
 				assert.Equal(t, "/data", attachment1["mount_path"])
 				assert.Equal(t, true, attachment1["rw"])
+				assert.Equal(t, "rshared", attachment1["propagation"])
+				assert.Equal(t, "cached", attachment1["consistency"])
 
 				attachment2 := data[1].(map[string]interface{})
 				assert.Equal(t, "container2", attachment2["container_id"])
 				assert.Equal(t, "/app", attachment2["mount_path"])
 				assert.Equal(t, false, attachment2["rw"])
+				assert.NotContains(t, attachment2, "propagation")
+				assert.NotContains(t, attachment2, "consistency")
 			},
 		},
 		{
@@ -435,4 +503,2844 @@ func TestGetOrphanedVolumes(t *testing.T) {
 			mockDocker.AssertExpectations(t)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestGetOrphanedVolumes_AnonymousVolumeTeardown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now()
+	hexName := strings.Repeat("ab12", 16)
+	anonymous := coremodels.Volume{
+		ID:        hexName,
+		Name:      hexName,
+		Driver:    "local",
+		Labels:    map[string]string{anonymousVolumeLabel: ""},
+		CreatedAt: now.Add(-time.Hour),
+	}
+	leftover := coremodels.Volume{
+		ID:        strings.Repeat("cd34", 16),
+		Name:      strings.Repeat("cd34", 16),
+		Driver:    "local",
+		CreatedAt: now.Add(-time.Hour),
+	}
+	starting := coremodels.Volume{
+		ID:        "starting",
+		Name:      "starting",
+		Driver:    "local",
+		Labels:    map[string]string{anonymousVolumeLabel: ""},
+		CreatedAt: now.Add(-5 * time.Second),
+	}
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return([]coremodels.Volume{anonymous, leftover, starting}, nil)
+	// The container running with --rm is removed between the first and second report
+	mockDocker.On("GetVolumeContainers", mock.Anything, anonymous.ID).Return([]coremodels.VolumeContainer{{ID: "job"}}, nil).Once()
+	mockDocker.On("GetVolumeContainers", mock.Anything, anonymous.ID).Return([]coremodels.VolumeContainer{}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, leftover.ID).Return([]coremodels.VolumeContainer{}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, starting.ID).Return([]coremodels.VolumeContainer{}, nil)
+
+	handler := NewHandler(mockDocker, nil, nil)
+	clock := now
+	handler.attachments.now = func() time.Time { return clock }
+
+	orphanedNames := func() []string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?system=true&sort=name:asc", nil)
+		handler.GetOrphanedVolumes(c)
+		require.Equal(t, 200, w.Code)
+
+		var response struct {
+			Data []models.OrphanedVolumeV1 `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		names := make([]string, len(response.Data))
+		for i, vol := range response.Data {
+			names[i] = vol.Name
+		}
+		return names
+	}
+
+	// A leftover anonymous volume never seen mounted is orphaned right away,
+	// one created moments ago is still waiting for its container
+	assert.Equal(t, []string{leftover.Name}, orphanedNames())
+
+	// Its container is gone but Docker has not removed the volume yet
+	clock = now.Add(10 * time.Second)
+	assert.Equal(t, []string{leftover.Name}, orphanedNames())
+
+	// Still there after the grace period: it outlived its container
+	clock = now.Add(2 * time.Minute)
+	assert.Equal(t, []string{anonymous.Name, leftover.Name, starting.Name}, orphanedNames())
+
+	mockDocker.AssertExpectations(t)
+}
+
+func TestGetOrphanedVolumes_ReclaimableBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupOverviewTestDB(t)
+	require.NoError(t, database.NewVolumeAnnotationRepository(db).SetAnnotation(context.Background(), "pinned-by-annotation", database.AnnotationKeyPinned, "true"))
+	require.NoError(t, database.NewVolumeAnnotationRepository(db).SetAnnotation(context.Background(), "unpinned-label", database.AnnotationKeyPinned, "false"))
+
+	sized := func(name string, size int64, labels map[string]string) coremodels.Volume {
+		return coremodels.Volume{ID: name, Name: name, Driver: "local", Labels: labels, UsageData: &coremodels.VolumeUsage{Size: size}}
+	}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return([]coremodels.Volume{
+		sized("cache", 100, nil),
+		sized("logs", 250, nil),
+		{ID: "unscanned", Name: "unscanned", Driver: "local"},
+		sized("pinned-by-label", 500, map[string]string{"pinned": "true"}),
+		sized("pinned-by-annotation", 700, nil),
+		sized("unpinned-label", 40, map[string]string{"pinned": "true"}), // annotation overrides the label
+		sized("docker_system", 900, nil),
+		sized("in-use", 2000, nil),
+	}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, "in-use").Return([]coremodels.VolumeContainer{{ID: "c1"}}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+	handler := NewHandler(mockDocker, nil, db)
+
+	report := func(query string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+		handler.GetOrphanedVolumes(c)
+		require.Equal(t, 200, w.Code, w.Body.String())
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// System volumes are listed on request but never counted as reclaimable,
+	// and the totals cover every page
+	response := report("system=true&page=2&page_size=2")
+	assert.Equal(t, float64(7), response["total"])
+	assert.Len(t, response["data"], 2)
+	assert.Equal(t, float64(100+250+40), response["reclaimable_bytes"])
+	assert.Equal(t, float64(1), response["unsized_count"])
+
+	response = report("")
+	assert.Equal(t, float64(6), response["total"])
+	assert.Equal(t, float64(390), response["reclaimable_bytes"])
+}
+
+func TestOrphaned_CountStopped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return([]coremodels.Volume{
+		{ID: "backups", Name: "backups", Driver: "local"},
+		{ID: "db", Name: "db", Driver: "local"},
+		{ID: "scratch", Name: "scratch", Driver: "local"},
+	}, nil)
+	mockDocker.On("GetVolume", mock.Anything, "backups").Return(&coremodels.Volume{ID: "backups", Name: "backups", Driver: "local"}, nil)
+	// backups is only mounted by stopped containers, db by a running one
+	mockDocker.On("GetVolumeContainers", mock.Anything, "backups").Return([]coremodels.VolumeContainer{
+		{ID: "restic", State: "exited"},
+		{ID: "restore", State: "created"},
+	}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, "db").Return([]coremodels.VolumeContainer{
+		{ID: "postgres", State: "running"},
+		{ID: "migrate", State: "exited"},
+	}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, "scratch").Return([]coremodels.VolumeContainer{}, nil)
+
+	get := func(t *testing.T, handler *Handler, serve func(*gin.Context), query string, params gin.Params) []byte {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Params = params
+		c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+		serve(c)
+		require.Equal(t, 200, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+	orphanedList := func(t *testing.T, handler *Handler, query string) []string {
+		t.Helper()
+		var response struct {
+			Data []models.VolumeV1 `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(get(t, handler, handler.ListVolumes, "orphaned=true&sort=name:asc&"+query, nil), &response))
+		var names []string
+		for _, vol := range response.Data {
+			assert.True(t, vol.IsOrphaned, vol.Name)
+			names = append(names, vol.Name)
+		}
+		return names
+	}
+	orphanedReport := func(t *testing.T, handler *Handler, query string) []string {
+		t.Helper()
+		var response struct {
+			Data []models.OrphanedVolumeV1 `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(get(t, handler, handler.GetOrphanedVolumes, "sort=name:asc&"+query, nil), &response))
+		var names []string
+		for _, vol := range response.Data {
+			names = append(names, vol.Name)
+		}
+		return names
+	}
+	detailOrphaned := func(t *testing.T, handler *Handler, query string) bool {
+		t.Helper()
+		var detail models.VolumeDetailV1
+		require.NoError(t, json.Unmarshal(get(t, handler, handler.GetVolume, query, gin.Params{{Key: "name", Value: "backups"}}), &detail))
+		assert.Equal(t, 2, detail.AttachmentsTotal, "stopped containers are still listed as attachments")
+		assert.Equal(t, models.OrphanedStateDormant, detail.OrphanedState)
+		return detail.IsOrphaned
+	}
+
+	t.Run("stopped containers count by default", func(t *testing.T) {
+		handler := NewHandler(mockDocker, nil, nil)
+		assert.Equal(t, []string{"scratch"}, orphanedList(t, handler, ""))
+		assert.Equal(t, []string{"scratch"}, orphanedReport(t, handler, ""))
+		assert.False(t, detailOrphaned(t, handler, ""))
+
+		assert.Equal(t, []string{"backups", "scratch"}, orphanedList(t, handler, "count_stopped=false"))
+		assert.Equal(t, []string{"backups", "scratch"}, orphanedReport(t, handler, "count_stopped=false"))
+		assert.True(t, detailOrphaned(t, handler, "count_stopped=false"))
+	})
+
+	t.Run("configured to ignore stopped containers", func(t *testing.T) {
+		handler := NewHandler(mockDocker, nil, nil)
+		handler.SetCountStopped(false)
+		assert.Equal(t, []string{"backups", "scratch"}, orphanedList(t, handler, ""))
+		assert.Equal(t, []string{"backups", "scratch"}, orphanedReport(t, handler, ""))
+		assert.True(t, detailOrphaned(t, handler, ""))
+
+		assert.Equal(t, []string{"scratch"}, orphanedList(t, handler, "count_stopped=true"))
+		assert.Equal(t, []string{"scratch"}, orphanedReport(t, handler, "count_stopped=true"))
+		assert.False(t, detailOrphaned(t, handler, "count_stopped=true"))
+	})
+
+	t.Run("invalid parameter", func(t *testing.T) {
+		handler := NewHandler(mockDocker, nil, nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?count_stopped=sometimes", nil)
+		handler.ListVolumes(c)
+		assert.Equal(t, 400, w.Code)
+	})
+}
+
+func TestIsOrphaned_NamedAndUngracedVolumes(t *testing.T) {
+	handler := NewHandler(&mocks.DockerService{}, nil, nil)
+	named := coremodels.Volume{Name: "app-data", CreatedAt: time.Now()}
+	anonymous := coremodels.Volume{Name: "job", Labels: map[string]string{anonymousVolumeLabel: ""}, CreatedAt: time.Now()}
+
+	assert.True(t, handler.isOrphaned(named, 0), "named volumes are orphaned as soon as they have no containers")
+	assert.False(t, handler.isOrphaned(named, 1))
+	assert.False(t, handler.isOrphaned(anonymous, 0))
+
+	handler.SetAnonymousOrphanGrace(0)
+	assert.True(t, handler.isOrphaned(anonymous, 0), "a zero grace period disables the heuristic")
+	assert.False(t, handler.isOrphaned(anonymous, 1))
+}
+
+func TestOrphanedState(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	named := coremodels.Volume{Name: "app-data", CreatedAt: old}
+	anonymous := coremodels.Volume{Name: "job", Labels: map[string]string{anonymousVolumeLabel: ""}, CreatedAt: time.Now()}
+	withStates := func(states ...string) []coremodels.VolumeContainer {
+		containers := make([]coremodels.VolumeContainer, len(states))
+		for i, state := range states {
+			containers[i] = coremodels.VolumeContainer{ID: fmt.Sprintf("c%d", i), State: state}
+		}
+		return containers
+	}
+
+	tests := []struct {
+		name       string
+		volume     coremodels.Volume
+		containers []coremodels.VolumeContainer
+		expected   string
+	}{
+		{name: "running container", volume: named, containers: withStates("running"), expected: models.OrphanedStateAttached},
+		{name: "one of several running", volume: named, containers: withStates("exited", "running"), expected: models.OrphanedStateAttached},
+		{name: "paused container", volume: named, containers: withStates("paused"), expected: models.OrphanedStateAttached},
+		{name: "restarting container", volume: named, containers: withStates("restarting"), expected: models.OrphanedStateAttached},
+		{name: "stopped containers", volume: named, containers: withStates("exited", "created"), expected: models.OrphanedStateDormant},
+		{name: "dead container", volume: named, containers: withStates("dead"), expected: models.OrphanedStateDormant},
+		{name: "stopped and removing", volume: named, containers: withStates("exited", "removing"), expected: models.OrphanedStateDormant},
+		{name: "containers being removed", volume: named, containers: withStates("removing"), expected: models.OrphanedStateDetaching},
+		{name: "never mounted", volume: named, expected: models.OrphanedStateOrphaned},
+		{name: "new anonymous volume", volume: anonymous, expected: models.OrphanedStateDetaching},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&mocks.DockerService{}, nil, nil)
+			assert.Equal(t, tt.expected, handler.orphanedState(tt.volume, tt.containers))
+		})
+	}
+}
+
+func TestOrphanedState_DetachRecency(t *testing.T) {
+	handler := NewHandler(&mocks.DockerService{}, nil, nil)
+	handler.SetDetachWindow(time.Minute)
+	now := time.Now()
+	handler.detaches.now = func() time.Time { return now }
+	volume := coremodels.Volume{Name: "app-data", CreatedAt: now.Add(-time.Hour)}
+
+	running := []coremodels.VolumeContainer{{ID: "c1", State: "running"}}
+	assert.Equal(t, models.OrphanedStateAttached, handler.orphanedState(volume, running))
+
+	// The container was removed since the volume was last seen mounted
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, models.OrphanedStateDetaching, handler.orphanedState(volume, nil))
+	assert.True(t, handler.isOrphaned(volume, 0), "is_orphaned keeps its meaning for named volumes")
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, models.OrphanedStateOrphaned, handler.orphanedState(volume, nil))
+
+	// A zero window reports the volume orphaned right away
+	handler.SetDetachWindow(0)
+	assert.Equal(t, models.OrphanedStateAttached, handler.orphanedState(volume, running))
+	assert.Equal(t, models.OrphanedStateOrphaned, handler.orphanedState(volume, nil))
+}
+
+func TestVolumeNamePathParams_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		dockerName     string // name the Docker service should receive, empty if it must not be called
+		expectedStatus int
+	}{
+		{name: "valid name", path: "my-volume", dockerName: "my-volume", expectedStatus: 200},
+		{name: "compose name", path: "project_db.data", dockerName: "project_db.data", expectedStatus: 200},
+		{name: "encoded valid name", path: "my%2Evolume", dockerName: "my.volume", expectedStatus: 200},
+		{name: "leading dash", path: "-volume", expectedStatus: 400},
+		{name: "invalid characters", path: "bad$name", expectedStatus: 400},
+		{name: "encoded space", path: "my%20volume", expectedStatus: 400},
+		{name: "encoded slash", path: "host%2Fpath", expectedStatus: 400},
+		{name: "encoded traversal", path: "..%2F..%2Fetc", expectedStatus: 400},
+		// Gin decodes the path once; the name is never decoded again
+		{name: "literal percent", path: "bad%25zz", expectedStatus: 400},
+		{name: "double encoded", path: "a%2541", expectedStatus: 400},
+	}
+
+	endpoints := []string{"", "/attachments", "/stats"}
+
+	for _, endpoint := range endpoints {
+		for _, tt := range tests {
+			t.Run("GET /volumes/{name}"+endpoint+" "+tt.name, func(t *testing.T) {
+				mockDocker := &mocks.DockerService{}
+				if tt.dockerName != "" {
+					volume := &coremodels.Volume{ID: tt.dockerName, Name: tt.dockerName, Driver: "local"}
+					mockDocker.On("GetVolume", mock.Anything, tt.dockerName).Return(volume, nil)
+					mockDocker.On("GetVolumeContainers", mock.Anything, tt.dockerName).Return([]coremodels.VolumeContainer{}, nil)
+				}
+
+				engine := gin.New()
+				engine.UseRawPath = true
+				NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+				w := httptest.NewRecorder()
+				engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/"+tt.path+endpoint, nil))
+
+				assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+				if tt.dockerName == "" {
+					mockDocker.AssertNotCalled(t, "GetVolume", mock.Anything, mock.Anything)
+				} else {
+					mockDocker.AssertCalled(t, "GetVolume", mock.Anything, tt.dockerName)
+				}
+			})
+		}
+	}
+}
+
+func TestSizeUnsupportedDriver_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	volumes := []coremodels.Volume{
+		{
+			ID:        "local-vol",
+			Name:      "local-vol",
+			Driver:    "local",
+			UsageData: &coremodels.VolumeUsage{Size: 2048},
+		},
+		{
+			ID:        "nfs-vol",
+			Name:      "nfs-vol",
+			Driver:    "csi-nfs",
+			UsageData: &coremodels.VolumeUsage{Size: 12345}, // garbage reported by the driver
+		},
+	}
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	mockDocker.On("GetVolume", mock.Anything, "nfs-vol").Return(&volumes[1], nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+	engine := gin.New()
+	NewRouter(mockDocker, nil, nil, config.NewSizePolicy([]string{"CSI-NFS"}), nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	get := func(path string) []byte {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, 200, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+
+	assertSize := func(t *testing.T, vol map[string]interface{}) {
+		switch vol["name"] {
+		case "local-vol":
+			assert.Equal(t, true, vol["size_supported"])
+			assert.Equal(t, float64(2048), vol["size_bytes"])
+		case "nfs-vol":
+			assert.Equal(t, false, vol["size_supported"])
+			sizeBytes, present := vol["size_bytes"]
+			assert.True(t, present, "size_bytes should be present as null")
+			assert.Nil(t, sizeBytes)
+		default:
+			t.Fatalf("unexpected volume %v", vol["name"])
+		}
+	}
+
+	t.Run("list", func(t *testing.T) {
+		var response apiutils.PagedResponse
+		assert.NoError(t, json.Unmarshal(get("/api/v1/volumes"), &response))
+		data := response.Data.([]interface{})
+		assert.Len(t, data, 2)
+		for _, item := range data {
+			assertSize(t, item.(map[string]interface{}))
+		}
+	})
+
+	t.Run("detail", func(t *testing.T) {
+		var vol map[string]interface{}
+		assert.NoError(t, json.Unmarshal(get("/api/v1/volumes/nfs-vol"), &vol))
+		assertSize(t, vol)
+	})
+
+	t.Run("orphaned report", func(t *testing.T) {
+		var response apiutils.PagedResponse
+		assert.NoError(t, json.Unmarshal(get("/api/v1/reports/orphaned"), &response))
+		data := response.Data.([]interface{})
+		assert.Len(t, data, 2)
+		// The unsupported volume does not contribute a size and sorts last
+		assert.Equal(t, "local-vol", data[0].(map[string]interface{})["name"])
+		for _, item := range data {
+			assertSize(t, item.(map[string]interface{}))
+		}
+	})
+}
+
+func TestUnscannableVolumes_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	volumes := []coremodels.Volume{
+		{ID: "local-vol", Name: "local-vol", Driver: "local", Mountpoint: "/var/lib/docker/volumes/local-vol/_data"},
+		{ID: "empty-vol", Name: "empty-vol", Driver: "rexray"},
+		{ID: "remote-vol", Name: "remote-vol", Driver: "netshare", Mountpoint: "nfs://storage/export"},
+	}
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	mockDocker.On("GetVolume", mock.Anything, "remote-vol").Return(&volumes[2], nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+	get := func(policy *config.SizePolicy, path string) []byte {
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, policy, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, 200, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+
+	var response apiutils.PagedResponse
+	assert.NoError(t, json.Unmarshal(get(nil, "/api/v1/volumes"), &response))
+	for _, item := range response.Data.([]interface{}) {
+		vol := item.(map[string]interface{})
+		switch vol["name"] {
+		case "local-vol":
+			assert.Equal(t, true, vol["scannable"])
+			assert.NotContains(t, vol, "unscannable_reason")
+		case "empty-vol":
+			assert.Equal(t, false, vol["scannable"])
+			assert.Equal(t, "driver reported no mountpoint", vol["unscannable_reason"])
+		case "remote-vol":
+			assert.Equal(t, false, vol["scannable"])
+			assert.Equal(t, `mountpoint "nfs://storage/export" is not a local path`, vol["unscannable_reason"])
+		}
+	}
+
+	// An external size command makes them scannable
+	policy := (&config.ScanConfig{ExternalSizeCommand: "/usr/local/bin/volume-size"}).SizePolicy()
+	var vol map[string]interface{}
+	assert.NoError(t, json.Unmarshal(get(policy, "/api/v1/volumes/remote-vol"), &vol))
+	assert.Equal(t, true, vol["scannable"])
+	assert.NotContains(t, vol, "unscannable_reason")
+}
+
+func TestVolumesByNode_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	get := func(t *testing.T, volumes []coremodels.Volume, path string) []byte {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, config.NewSizePolicy([]string{"csi-nfs"}), nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, 200, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+
+	t.Run("multiple nodes", func(t *testing.T) {
+		volumes := []coremodels.Volume{
+			{ID: "db-data", Name: "db-data", Driver: "local", Node: "worker-1", UsageData: &coremodels.VolumeUsage{Size: 3000}},
+			{ID: "cache", Name: "cache", Driver: "local", Node: "worker-1", UsageData: &coremodels.VolumeUsage{Size: 1000}},
+			{ID: "uploads", Name: "uploads", Driver: "local", Node: "worker-2", UsageData: &coremodels.VolumeUsage{Size: 500}},
+			// Size-unsupported and unsized volumes are listed but add nothing to the total
+			{ID: "shared", Name: "shared", Driver: "csi-nfs", Node: "worker-2", UsageData: &coremodels.VolumeUsage{Size: 99999}},
+			{ID: "logs", Name: "logs", Driver: "local", Node: "manager-1"},
+		}
+
+		var report models.VolumesByNodeReportV1
+		assert.NoError(t, json.Unmarshal(get(t, volumes, "/api/v1/reports/by-node"), &report))
+		assert.Equal(t, 5, report.TotalVolumes)
+		if assert.Len(t, report.Nodes, 3) {
+			assert.Equal(t, "manager-1", report.Nodes[0].Node)
+			assert.Equal(t, int64(0), report.Nodes[0].TotalSizeBytes.Value)
+			assert.Nil(t, report.Nodes[0].Volumes[0].SizeBytes)
+
+			assert.Equal(t, "worker-1", report.Nodes[1].Node)
+			assert.Equal(t, 2, report.Nodes[1].VolumeCount)
+			assert.Equal(t, int64(4000), report.Nodes[1].TotalSizeBytes.Value)
+			assert.Equal(t, "cache", report.Nodes[1].Volumes[0].Name)
+
+			assert.Equal(t, "worker-2", report.Nodes[2].Node)
+			assert.Equal(t, 2, report.Nodes[2].VolumeCount)
+			assert.Equal(t, int64(500), report.Nodes[2].TotalSizeBytes.Value)
+			assert.False(t, report.Nodes[2].Volumes[0].SizeSupported)
+		}
+	})
+
+	t.Run("non-swarm host", func(t *testing.T) {
+		volumes := []coremodels.Volume{
+			{ID: "db-data", Name: "db-data", Driver: "local", UsageData: &coremodels.VolumeUsage{Size: 3000}},
+			{ID: "cache", Name: "cache", Driver: "local", UsageData: &coremodels.VolumeUsage{Size: 1000}},
+		}
+
+		var report models.VolumesByNodeReportV1
+		assert.NoError(t, json.Unmarshal(get(t, volumes, "/api/v1/reports/by-node"), &report))
+		if assert.Len(t, report.Nodes, 1) {
+			assert.Equal(t, "local", report.Nodes[0].Node)
+			assert.Equal(t, 2, report.Nodes[0].VolumeCount)
+			assert.Equal(t, int64(4000), report.Nodes[0].TotalSizeBytes.Value)
+		}
+
+		// Volumes also carry their node
+		var response apiutils.PagedResponse
+		assert.NoError(t, json.Unmarshal(get(t, volumes, "/api/v1/volumes"), &response))
+		for _, item := range response.Data.([]interface{}) {
+			assert.Equal(t, "local", item.(map[string]interface{})["node"])
+		}
+	})
+
+	t.Run("truncated by the report limits", func(t *testing.T) {
+		volumes := []coremodels.Volume{
+			{ID: "a", Name: "a", Driver: "local", Node: "worker-1", UsageData: &coremodels.VolumeUsage{Size: 1}},
+			{ID: "b", Name: "b", Driver: "local", Node: "worker-1", UsageData: &coremodels.VolumeUsage{Size: 2}},
+			{ID: "c", Name: "c", Driver: "local", Node: "worker-2", UsageData: &coremodels.VolumeUsage{Size: 4}},
+			{ID: "d", Name: "d", Driver: "local", Node: "worker-2", UsageData: &coremodels.VolumeUsage{Size: 8}},
+			{ID: "e", Name: "e", Driver: "local", Node: "worker-3", UsageData: &coremodels.VolumeUsage{Size: 16}},
+		}
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+
+		engine := gin.New()
+		engine.Use(middleware.ReportLimitsMiddleware(3, 0))
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		read := func(path string) models.VolumesByNodeReportV1 {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			require.Equal(t, 200, w.Code, w.Body.String())
+			var report models.VolumesByNodeReportV1
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			return report
+		}
+
+		first := read("/api/v1/reports/by-node")
+		require.NotNil(t, first.ReportPageV1)
+		assert.True(t, first.Truncated)
+		assert.Equal(t, "3", first.NextCursor)
+		assert.Equal(t, 5, first.TotalVolumes)
+		require.Len(t, first.Nodes, 2)
+		assert.Len(t, first.Nodes[0].Volumes, 2)
+		require.Len(t, first.Nodes[1].Volumes, 1)
+		assert.Equal(t, "c", first.Nodes[1].Volumes[0].Name)
+		// Node totals still cover the volumes left out
+		assert.Equal(t, 2, first.Nodes[1].VolumeCount)
+		assert.Equal(t, int64(12), first.Nodes[1].TotalSizeBytes.Value)
+
+		rest := read(first.Next)
+		assert.False(t, rest.Truncated)
+		require.Len(t, rest.Nodes, 2)
+		assert.Equal(t, "d", rest.Nodes[0].Volumes[0].Name)
+		assert.Equal(t, "worker-3", rest.Nodes[1].Node)
+	})
+}
+
+func TestVolumesByLabel_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupOverviewTestDB(t)
+	ctx := context.Background()
+
+	// An annotation overrides the label of the same key
+	annotations := database.NewVolumeAnnotationRepository(db)
+	require.NoError(t, annotations.SetAnnotation(ctx, "reports", "cost_center", "finance"))
+	require.NoError(t, annotations.SetAnnotation(ctx, "scratch", "cost_center", "research"))
+
+	costCenter := func(value string) map[string]string { return map[string]string{"cost_center": value} }
+	volumes := []coremodels.Volume{
+		{ID: "db-data", Name: "db-data", Driver: "local", Labels: costCenter("engineering"), UsageData: &coremodels.VolumeUsage{Size: 3000}},
+		{ID: "cache", Name: "cache", Driver: "local", Labels: costCenter("engineering"), UsageData: &coremodels.VolumeUsage{Size: 1000}},
+		{ID: "reports", Name: "reports", Driver: "local", Labels: costCenter("engineering"), UsageData: &coremodels.VolumeUsage{Size: 200}},
+		{ID: "ledger", Name: "ledger", Driver: "local", Labels: costCenter("finance"), UsageData: &coremodels.VolumeUsage{Size: 50}},
+		{ID: "scratch", Name: "scratch", Driver: "local", UsageData: &coremodels.VolumeUsage{Size: 7}},
+		{ID: "logs", Name: "logs", Driver: "local", Labels: map[string]string{"team": "ops"}, UsageData: &coremodels.VolumeUsage{Size: 400}},
+		{ID: "tmp", Name: "tmp", Driver: "local"},
+	}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+
+	engine := gin.New()
+	NewRouter(mockDocker, nil, db, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+	read := func(t *testing.T, path string, code int) models.VolumesByLabelReportV1 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, code, w.Code, w.Body.String())
+		var report models.VolumesByLabelReportV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+
+	t.Run("groups by value", func(t *testing.T) {
+		report := read(t, "/api/v1/reports/by-label?key=cost_center", 200)
+		assert.Equal(t, "cost_center", report.Key)
+		assert.Equal(t, 7, report.TotalVolumes)
+		assert.Equal(t, int64(4657), report.TotalSizeBytes.Value)
+
+		require.Len(t, report.Groups, 4)
+		assert.Equal(t, "engineering", report.Groups[0].Value)
+		assert.Equal(t, 2, report.Groups[0].VolumeCount)
+		assert.Equal(t, int64(4000), report.Groups[0].TotalSizeBytes.Value)
+		assert.Equal(t, "cache", report.Groups[0].Volumes[0].Name)
+		assert.Equal(t, models.LabelSourceLabel, report.Groups[0].Volumes[0].Source)
+
+		assert.Equal(t, "finance", report.Groups[1].Value)
+		assert.Equal(t, int64(250), report.Groups[1].TotalSizeBytes.Value)
+		require.Len(t, report.Groups[1].Volumes, 2)
+		assert.Equal(t, "reports", report.Groups[1].Volumes[1].Name)
+		assert.Equal(t, models.LabelSourceAnnotation, report.Groups[1].Volumes[1].Source)
+
+		assert.Equal(t, "research", report.Groups[2].Value)
+		assert.Equal(t, int64(7), report.Groups[2].TotalSizeBytes.Value)
+
+		// Volumes without the key come last, unsized ones adding nothing to the total
+		assert.Equal(t, models.LabelValueUnassigned, report.Groups[3].Value)
+		assert.Equal(t, 2, report.Groups[3].VolumeCount)
+		assert.Equal(t, int64(400), report.Groups[3].TotalSizeBytes.Value)
+		assert.Empty(t, report.Groups[3].Volumes[0].Source)
+	})
+
+	t.Run("filters to a single value", func(t *testing.T) {
+		report := read(t, "/api/v1/reports/by-label?key=cost_center&value=finance", 200)
+		assert.Equal(t, 2, report.TotalVolumes)
+		assert.Equal(t, int64(250), report.TotalSizeBytes.Value)
+		require.Len(t, report.Groups, 1)
+		assert.Equal(t, "finance", report.Groups[0].Value)
+
+		report = read(t, "/api/v1/reports/by-label?key=cost_center&value=unassigned", 200)
+		require.Len(t, report.Groups, 1)
+		assert.Equal(t, []string{"logs", "tmp"}, []string{report.Groups[0].Volumes[0].Name, report.Groups[0].Volumes[1].Name})
+
+		report = read(t, "/api/v1/reports/by-label?key=cost_center&value=marketing", 200)
+		assert.Empty(t, report.Groups)
+		assert.Equal(t, 0, report.TotalVolumes)
+	})
+
+	t.Run("any key", func(t *testing.T) {
+		report := read(t, "/api/v1/reports/by-label?key=team", 200)
+		require.Len(t, report.Groups, 2)
+		assert.Equal(t, "ops", report.Groups[0].Value)
+		assert.Equal(t, 6, report.Groups[1].VolumeCount)
+	})
+
+	t.Run("key is required", func(t *testing.T) {
+		read(t, "/api/v1/reports/by-label", 400)
+	})
+}
+
+func TestVolumesByProject_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	project := func(name string) map[string]string {
+		return map[string]string{"com.docker.compose.project": name, "com.docker.compose.volume": "data"}
+	}
+	volumes := []coremodels.Volume{
+		{ID: "shop_db", Name: "shop_db", Driver: "local", Labels: project("shop"), UsageData: &coremodels.VolumeUsage{Size: 3000}},
+		{ID: "shop_cache", Name: "shop_cache", Driver: "local", Labels: project("shop"), UsageData: &coremodels.VolumeUsage{Size: 1000}},
+		{ID: "blog_uploads", Name: "blog_uploads", Driver: "local", Labels: project("blog"), UsageData: &coremodels.VolumeUsage{Size: 50}},
+		{ID: "blog_db", Name: "blog_db", Driver: "local", Labels: project("blog")},
+		{ID: "backups", Name: "backups", Driver: "local", Labels: map[string]string{"team": "ops"}, UsageData: &coremodels.VolumeUsage{Size: 400}},
+	}
+	containers := map[string][]coremodels.VolumeContainer{
+		// The web container mounts both shop volumes and counts once for the project
+		"shop_db":      {{ID: "c-db", Name: "shop-db-1"}, {ID: "c-web", Name: "shop-web-1"}},
+		"shop_cache":   {{ID: "c-web", Name: "shop-web-1"}},
+		"blog_uploads": {{ID: "c-blog", Name: "blog-app-1"}},
+	}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	for _, vol := range volumes {
+		mockDocker.On("GetVolumeContainers", mock.Anything, vol.ID).Return(append([]coremodels.VolumeContainer{}, containers[vol.ID]...), nil)
+	}
+
+	engine := gin.New()
+	NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+	read := func(t *testing.T, path string) models.VolumesByProjectReportV1 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var report models.VolumesByProjectReportV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+
+	t.Run("groups by project", func(t *testing.T) {
+		report := read(t, "/api/v1/reports/by-project")
+		assert.Equal(t, 5, report.TotalVolumes)
+		assert.Equal(t, int64(4450), report.TotalSizeBytes.Value)
+		require.Len(t, report.Projects, 3)
+
+		blog := report.Projects[0]
+		assert.Equal(t, "blog", blog.Project)
+		assert.Equal(t, 2, blog.VolumeCount)
+		assert.Equal(t, 1, blog.AttachmentCount)
+		assert.Equal(t, int64(50), blog.TotalSizeBytes.Value, "unsized volumes add nothing")
+		assert.Equal(t, "blog_db", blog.Volumes[0].Name)
+		assert.Empty(t, blog.Volumes[0].Attachments)
+
+		shop := report.Projects[1]
+		assert.Equal(t, "shop", shop.Project)
+		assert.Equal(t, 2, shop.VolumeCount)
+		assert.Equal(t, 2, shop.AttachmentCount)
+		assert.Equal(t, int64(4000), shop.TotalSizeBytes.Value)
+		assert.Equal(t, "shop_cache", shop.Volumes[0].Name)
+		assert.Equal(t, []string{"shop-db-1", "shop-web-1"}, shop.Volumes[1].Attachments)
+
+		// Volumes outside any project come last
+		ungrouped := report.Projects[2]
+		assert.Equal(t, models.ProjectUngrouped, ungrouped.Project)
+		assert.Equal(t, 1, ungrouped.VolumeCount)
+		assert.Equal(t, int64(400), ungrouped.TotalSizeBytes.Value)
+	})
+
+	t.Run("filters to a single project", func(t *testing.T) {
+		report := read(t, "/api/v1/reports/by-project?project=shop")
+		assert.Equal(t, 2, report.TotalVolumes)
+		require.Len(t, report.Projects, 1)
+		assert.Equal(t, "shop", report.Projects[0].Project)
+
+		report = read(t, "/api/v1/reports/by-project?project=ungrouped")
+		require.Len(t, report.Projects, 1)
+		assert.Equal(t, "backups", report.Projects[0].Volumes[0].Name)
+
+		report = read(t, "/api/v1/reports/by-project?project=missing")
+		assert.Empty(t, report.Projects)
+	})
+
+	t.Run("volume list filter", func(t *testing.T) {
+		list := func(query string) []string {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes?sort=name:asc&"+query, nil))
+			require.Equal(t, 200, w.Code, w.Body.String())
+			var response struct {
+				Data []models.VolumeV1 `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			var names []string
+			for _, vol := range response.Data {
+				names = append(names, vol.Name)
+			}
+			return names
+		}
+		assert.Equal(t, []string{"blog_db", "blog_uploads"}, list("project=blog"))
+		assert.Equal(t, []string{"backups"}, list("project=ungrouped"))
+		assert.Len(t, list(""), 5)
+	})
+}
+
+func TestSortTiesBreakByName_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Equal drivers, sizes and creation times, listed out of name order
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var volumes []coremodels.Volume
+	for _, name := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		volumes = append(volumes, coremodels.Volume{
+			ID: name, Name: name, Driver: "local", CreatedAt: created,
+			UsageData: &coremodels.VolumeUsage{Size: 1024},
+		})
+	}
+	sorted := []string{"alpha", "bravo", "charlie", "delta", "echo"}
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+	engine := gin.New()
+	NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	// listPages collects the names from every page of size 2
+	listPages := func(t *testing.T, path string) []string {
+		var names []string
+		for page := 1; page <= 3; page++ {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("%s&page=%d&page_size=2", path, page), nil))
+			assert.Equal(t, 200, w.Code, w.Body.String())
+
+			var response apiutils.PagedResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			for _, item := range response.Data.([]interface{}) {
+				names = append(names, item.(map[string]interface{})["name"].(string))
+			}
+		}
+		return names
+	}
+
+	for _, sortParam := range []string{"driver:asc", "driver:desc", "size_bytes:asc", "size_bytes:desc", "created_at:asc", "created_at:desc"} {
+		t.Run(sortParam, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				assert.Equal(t, sorted, listPages(t, "/api/v1/volumes?sort="+sortParam))
+				assert.Equal(t, sorted, listPages(t, "/api/v1/reports/orphaned?sort="+sortParam))
+			}
+		})
+	}
+}
+
+func TestSizeEncoding_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Beyond 2^53: a float64 client would round this to ...992
+	const largeSize int64 = 1<<53 + 1
+	volumes := []coremodels.Volume{
+		{
+			ID:        "big-vol",
+			Name:      "big-vol",
+			Driver:    "local",
+			UsageData: &coremodels.VolumeUsage{Size: largeSize},
+		},
+	}
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	mockDocker.On("GetVolume", mock.Anything, "big-vol").Return(&volumes[0], nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+	newEngine := func(defaultEncoding string) *gin.Engine {
+		engine := gin.New()
+		engine.Use(middleware.SizeEncodingMiddleware(defaultEncoding))
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		return engine
+	}
+
+	get := func(engine *gin.Engine, path, encoding string) string {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		if encoding != "" {
+			req.Header.Set(middleware.SizeEncodingHeader, encoding)
+		}
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code, w.Body.String())
+		return w.Body.String()
+	}
+
+	paths := []string{"/api/v1/volumes", "/api/v1/volumes/big-vol", "/api/v1/reports/orphaned"}
+
+	t.Run("number by default", func(t *testing.T) {
+		engine := newEngine("")
+		for _, path := range paths {
+			assert.Contains(t, get(engine, path, ""), `"size_bytes":9007199254740993`, path)
+		}
+	})
+
+	t.Run("string via header", func(t *testing.T) {
+		engine := newEngine("")
+		for _, path := range paths {
+			body := get(engine, path, "string")
+			assert.Contains(t, body, `"size_bytes":"9007199254740993"`, path)
+		}
+	})
+
+	t.Run("string via config, header overrides", func(t *testing.T) {
+		engine := newEngine("string")
+		assert.Contains(t, get(engine, "/api/v1/volumes/big-vol", ""), `"size_bytes":"9007199254740993"`)
+		assert.Contains(t, get(engine, "/api/v1/volumes/big-vol", "number"), `"size_bytes":9007199254740993`)
+		// Unknown header values keep the configured default
+		assert.Contains(t, get(engine, "/api/v1/volumes/big-vol", "hex"), `"size_bytes":"9007199254740993"`)
+	})
+
+	t.Run("string mode round-trips", func(t *testing.T) {
+		var vol models.VolumeDetailV1
+		assert.NoError(t, json.Unmarshal([]byte(get(newEngine(""), "/api/v1/volumes/big-vol", "string")), &vol))
+		if assert.NotNil(t, vol.SizeBytes) {
+			assert.Equal(t, largeSize, vol.SizeBytes.Value)
+		}
+	})
+}
+
+func TestPruneVolumes_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	volumes := []coremodels.Volume{
+		{ID: "expired", Name: "expired", Driver: "local", CreatedAt: recent, Labels: map[string]string{"retention": "expired"}},
+		{ID: "past-retention", Name: "past-retention", Driver: "local", CreatedAt: old, Labels: map[string]string{"retention": "7d", "team": "ci"}},
+		{ID: "within-retention", Name: "within-retention", Driver: "local", CreatedAt: recent, Labels: map[string]string{"retention": "7d", "team": "ci"}},
+		{ID: "no-retention", Name: "no-retention", Driver: "local", CreatedAt: old, Labels: map[string]string{"team": "ci"}},
+		{ID: "pinned", Name: "pinned", Driver: "local", CreatedAt: old, Labels: map[string]string{"retention": "expired", "pinned": "true"}},
+		{ID: "in-use", Name: "in-use", Driver: "local", CreatedAt: old, Labels: map[string]string{"retention": "expired"}},
+		{ID: "docker_cache", Name: "docker_cache", Driver: "local", CreatedAt: old, Labels: map[string]string{"retention": "expired"}},
+	}
+
+	newEngine := func() (*gin.Engine, *mocks.DockerService) {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, "in-use").Return([]coremodels.VolumeContainer{{ID: "c1", Name: "app"}}, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+		mockDocker.On("RemoveVolume", mock.Anything, mock.Anything).Return(nil)
+
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		return engine, mockDocker
+	}
+
+	prune := func(engine *gin.Engine, body string) models.PruneResponseV1 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/prune", strings.NewReader(body)))
+		assert.Equal(t, 200, w.Code, w.Body.String())
+
+		var response models.PruneResponseV1
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	names := func(response models.PruneResponseV1) []string {
+		result := make([]string, 0, len(response.Volumes))
+		for _, v := range response.Volumes {
+			result = append(result, v.Name)
+		}
+		return result
+	}
+
+	t.Run("dry run selects only volumes past retention", func(t *testing.T) {
+		engine, mockDocker := newEngine()
+		response := prune(engine, `{"dry_run": true}`)
+
+		assert.True(t, response.DryRun)
+		assert.Equal(t, []string{"expired", "past-retention"}, names(response))
+		assert.Equal(t, 2, response.Selected)
+		assert.Equal(t, 0, response.Deleted)
+		mockDocker.AssertNotCalled(t, "RemoveVolume", mock.Anything, mock.Anything)
+	})
+
+	t.Run("match narrows the policy and selects volumes without retention", func(t *testing.T) {
+		engine, _ := newEngine()
+		response := prune(engine, `{"match": {"team": "ci"}, "dry_run": true}`)
+
+		// within-retention matches but its retention has not passed
+		assert.Equal(t, []string{"no-retention", "past-retention"}, names(response))
+	})
+
+	t.Run("deletes the selected volumes", func(t *testing.T) {
+		engine, mockDocker := newEngine()
+		response := prune(engine, `{"dry_run": false}`)
+
+		assert.Equal(t, 2, response.Deleted)
+		mockDocker.AssertCalled(t, "RemoveVolume", mock.Anything, "expired")
+		mockDocker.AssertCalled(t, "RemoveVolume", mock.Anything, "past-retention")
+		mockDocker.AssertNumberOfCalls(t, "RemoveVolume", 2)
+	})
+
+	t.Run("requires a body", func(t *testing.T) {
+		engine, _ := newEngine()
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/prune", nil))
+		assert.Equal(t, 400, w.Code)
+	})
+}
+
+// setupOverviewTestDB creates a SQLite database with the tables the volume
+// overview reads: volume_metrics, volume_annotations and volume_stats
+func setupOverviewTestDB(t *testing.T) *database.DB {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "overview.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "001" || m.Version == "006" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE volume_stats (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			volume_name TEXT NOT NULL,
+			size_bytes INTEGER NOT NULL DEFAULT 0,
+			file_count INTEGER DEFAULT 0,
+			scan_method TEXT NOT NULL DEFAULT 'du',
+			scan_accuracy TEXT NOT NULL DEFAULT '',
+			duration_ms INTEGER DEFAULT 0,
+			ts DATETIME DEFAULT CURRENT_TIMESTAMP,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestVolumeOverview_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	volume := &coremodels.Volume{ID: "app-data", Name: "app-data", Driver: "local", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	containers := []coremodels.VolumeContainer{{ID: "c1", Name: "app", MountPath: "/data", AccessMode: "rw"}}
+
+	get := func(engine *gin.Engine, path string) (int, models.VolumeOverviewV1) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		var overview models.VolumeOverviewV1
+		if w.Code == 200 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &overview))
+		}
+		return w.Code, overview
+	}
+
+	t.Run("all sections populated", func(t *testing.T) {
+		db := setupOverviewTestDB(t)
+		ctx := context.Background()
+
+		_, err := db.Exec(`INSERT INTO volumes (volume_id, name, driver, mountpoint) VALUES ('app-data', 'app-data', 'local', '/data')`)
+		require.NoError(t, err)
+
+		metricsRepo := database.NewVolumeMetricsRepository(db)
+		require.NoError(t, metricsRepo.SaveMetrics(ctx, "app-data", 1000, 10, 2, "du"))
+		require.NoError(t, metricsRepo.SaveMetrics(ctx, "app-data", 2000, 20, 2, "du"))
+		require.NoError(t, database.NewVolumeAnnotationRepository(db).SetAnnotation(ctx, "app-data", "owner", "team-a"))
+
+		// The scheduled scan is newer than the history
+		fileCount := 25
+		require.NoError(t, scheduler.NewRepository(db).InsertVolumeStats(ctx, &database.VolumeScanStats{
+			VolumeName: "app-data",
+			SizeBytes:  2500,
+			FileCount:  &fileCount,
+			ScanMethod: "diskus",
+			Timestamp:  time.Now().Add(time.Minute),
+		}))
+
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("GetVolume", mock.Anything, "app-data").Return(volume, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, "app-data").Return(containers, nil)
+
+		engine := gin.New()
+		NewRouter(mockDocker, nil, db, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+		code, overview := get(engine, "/api/v1/volumes/app-data/overview")
+		require.Equal(t, 200, code)
+
+		assert.Equal(t, "app-data", overview.Volume.Name)
+		assert.Len(t, overview.Volume.Attachments, 1)
+		assert.NotNil(t, overview.Volume.LastScanAt)
+		assert.True(t, overview.Volume.ScanEnabled)
+
+		require.NotNil(t, overview.LatestSize)
+		assert.Equal(t, int64(2500), overview.LatestSize.SizeBytes.Value)
+		assert.Equal(t, "diskus", overview.LatestSize.Method)
+
+		require.Len(t, overview.History, 2)
+		assert.Equal(t, int64(2000), overview.History[0].SizeBytes.Value)
+
+		require.Len(t, overview.Attachments, 1)
+		assert.Equal(t, "app", overview.Attachments[0].ContainerName)
+		assert.Equal(t, map[string]string{"owner": "team-a"}, overview.Annotations)
+		assert.Empty(t, overview.Warnings)
+
+		code, _ = get(engine, "/api/v1/volumes/app-data/overview?history_limit=0")
+		assert.Equal(t, 400, code)
+	})
+
+	t.Run("partial failures are reported as warnings", func(t *testing.T) {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("GetVolume", mock.Anything, "app-data").Return(volume, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, "app-data").Return([]coremodels.VolumeContainer(nil), errors.New("docker unavailable"))
+
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+		code, overview := get(engine, "/api/v1/volumes/app-data/overview")
+		require.Equal(t, 200, code)
+
+		assert.Equal(t, "app-data", overview.Volume.Name)
+		assert.Nil(t, overview.LatestSize)
+		assert.Empty(t, overview.History)
+		assert.Empty(t, overview.Attachments)
+		assert.Len(t, overview.Warnings, 2)
+		assert.Contains(t, overview.Warnings[0], "attachments unavailable: docker unavailable")
+		assert.True(t, overview.Volume.ScanEnabled, "scanning is enabled without a database")
+	})
+
+	t.Run("missing volume", func(t *testing.T) {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("GetVolume", mock.Anything, "gone").Return(nil, errors.New("volume gone not found"))
+		mockDocker.On("GetVolumeContainers", mock.Anything, "gone").Return([]coremodels.VolumeContainer{}, nil)
+
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+		code, _ := get(engine, "/api/v1/volumes/gone/overview")
+		assert.Equal(t, 404, code)
+	})
+}
+
+func TestPruneVolumes_Confirmation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	expired := coremodels.Volume{ID: "expired", Name: "expired", Driver: "local", Labels: map[string]string{"retention": "expired"}}
+	stale := coremodels.Volume{ID: "stale", Name: "stale", Driver: "local", Labels: map[string]string{"retention": "expired"}}
+
+	newEngine := func(listings ...[]coremodels.Volume) (*gin.Engine, *Router, *mocks.DockerService) {
+		mockDocker := &mocks.DockerService{}
+		for _, volumes := range listings {
+			mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil).Once()
+		}
+		mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+		mockDocker.On("RemoveVolume", mock.Anything, mock.Anything).Return(nil)
+
+		router := NewRouter(mockDocker, nil, nil, nil, nil, nil)
+		require.NoError(t, router.RequirePruneConfirmation(time.Minute))
+
+		engine := gin.New()
+		router.RegisterRoutes(engine.Group("/api/v1"))
+		return engine, router, mockDocker
+	}
+
+	prune := func(engine *gin.Engine, req models.PruneRequestV1) (*httptest.ResponseRecorder, models.PruneResponseV1) {
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/prune", bytes.NewReader(body)))
+
+		var response models.PruneResponseV1
+		if w.Code == 200 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response
+	}
+
+	t.Run("dry run issues a token", func(t *testing.T) {
+		engine, _, _ := newEngine([]coremodels.Volume{expired})
+		w, response := prune(engine, models.PruneRequestV1{DryRun: true})
+
+		assert.Equal(t, 200, w.Code)
+		assert.NotEmpty(t, response.ConfirmationToken)
+		require.NotNil(t, response.ConfirmationExpiresAt)
+		assert.True(t, response.ConfirmationExpiresAt.After(time.Now()))
+	})
+
+	t.Run("confirmed prune deletes the dry run selection", func(t *testing.T) {
+		engine, _, mockDocker := newEngine([]coremodels.Volume{expired}, []coremodels.Volume{expired})
+		_, dryRun := prune(engine, models.PruneRequestV1{DryRun: true})
+
+		w, response := prune(engine, models.PruneRequestV1{ConfirmationToken: dryRun.ConfirmationToken})
+		assert.Equal(t, 200, w.Code, w.Body.String())
+		assert.Equal(t, 1, response.Deleted)
+		assert.Empty(t, response.ConfirmationToken)
+		mockDocker.AssertCalled(t, "RemoveVolume", mock.Anything, "expired")
+	})
+
+	t.Run("refuses a prune without a token", func(t *testing.T) {
+		engine, _, mockDocker := newEngine([]coremodels.Volume{expired})
+		w, _ := prune(engine, models.PruneRequestV1{})
+
+		assert.Equal(t, 400, w.Code)
+		mockDocker.AssertNotCalled(t, "RemoveVolume", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses a prune when the selection drifted", func(t *testing.T) {
+		engine, _, mockDocker := newEngine([]coremodels.Volume{expired}, []coremodels.Volume{expired, stale})
+		_, dryRun := prune(engine, models.PruneRequestV1{DryRun: true})
+
+		w, _ := prune(engine, models.PruneRequestV1{ConfirmationToken: dryRun.ConfirmationToken})
+		assert.Equal(t, 409, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"conflict"`)
+		assert.Contains(t, w.Body.String(), "stale")
+		mockDocker.AssertNotCalled(t, "RemoveVolume", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses an expired token", func(t *testing.T) {
+		engine, router, mockDocker := newEngine([]coremodels.Volume{expired}, []coremodels.Volume{expired})
+		_, dryRun := prune(engine, models.PruneRequestV1{DryRun: true})
+
+		router.handler.pruneConfirmer.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		w, _ := prune(engine, models.PruneRequestV1{ConfirmationToken: dryRun.ConfirmationToken})
+		assert.Equal(t, 409, w.Code)
+		mockDocker.AssertNotCalled(t, "RemoveVolume", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses a malformed token", func(t *testing.T) {
+		engine, _, _ := newEngine([]coremodels.Volume{expired})
+		w, _ := prune(engine, models.PruneRequestV1{ConfirmationToken: "not-a-token"})
+		assert.Equal(t, 400, w.Code)
+	})
+
+	t.Run("refuses prunes when confirmation is unavailable", func(t *testing.T) {
+		engine, router, mockDocker := newEngine([]coremodels.Volume{expired})
+		router.handler.pruneConfirmer = nil
+		router.handler.pruneConfirmErr = errors.New("no entropy")
+
+		for _, req := range []models.PruneRequestV1{{DryRun: true}, {}} {
+			w, _ := prune(engine, req)
+			assert.Equal(t, 503, w.Code)
+		}
+		mockDocker.AssertNotCalled(t, "RemoveVolume", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExportVolumeHistory_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupOverviewTestDB(t)
+	repo := scheduler.NewRepository(db)
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	fileCount := 120
+	// Inserted out of order; exports are ordered by scan time
+	for _, stat := range []*database.VolumeScanStats{
+		{VolumeName: "app-data", SizeBytes: 3000, FileCount: &fileCount, ScanMethod: "du", ScanAccuracy: "exact", DurationMs: 30, Timestamp: base.Add(2 * time.Hour)},
+		{VolumeName: "app-data", SizeBytes: 1000, FileCount: &fileCount, ScanMethod: "du", DurationMs: 10, Timestamp: base},
+		{VolumeName: "app-data", SizeBytes: 2000, ScanMethod: "find", DurationMs: 20, Timestamp: base.Add(time.Hour)},
+		{VolumeName: "other", SizeBytes: 9999, ScanMethod: "du", Timestamp: base},
+	} {
+		require.NoError(t, repo.InsertVolumeStats(context.Background(), stat))
+	}
+
+	engine := gin.New()
+	NewRouter(&mocks.DockerService{}, nil, db, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	t.Run("csv columns and row order", func(t *testing.T) {
+		w := get("/api/v1/volumes/app-data/history/export")
+		require.Equal(t, 200, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"timestamp", "volume_name", "size_bytes", "file_count", "scan_method", "duration_ms", "scan_accuracy"},
+			{"2025-03-01T12:00:00Z", "app-data", "1000", "120", "du", "10", ""},
+			{"2025-03-01T13:00:00Z", "app-data", "2000", "", "find", "20", ""},
+			{"2025-03-01T14:00:00Z", "app-data", "3000", "120", "du", "30", "exact"},
+		}, records)
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		w := get("/api/v1/volumes/app-data/history/export?since=2025-03-01T13:00:00Z&until=2025-03-01T14:00:00Z")
+		require.Equal(t, 200, w.Code)
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "2000", records[1][2])
+	})
+
+	t.Run("never scanned volume exports only the header", func(t *testing.T) {
+		w := get("/api/v1/volumes/unknown/history/export")
+		require.Equal(t, 200, w.Code)
+		assert.Equal(t, "timestamp,volume_name,size_bytes,file_count,scan_method,duration_ms,scan_accuracy\n", w.Body.String())
+	})
+
+	t.Run("prometheus matrix", func(t *testing.T) {
+		w := get("/api/v1/volumes/app-data/history/export?format=prometheus")
+		require.Equal(t, 200, w.Code, w.Body.String())
+
+		var response struct {
+			Status string `json:"status"`
+			Data   struct {
+				ResultType string `json:"resultType"`
+				Result     []struct {
+					Metric map[string]string `json:"metric"`
+					Values [][2]interface{}  `json:"values"`
+				} `json:"result"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+		assert.Equal(t, "success", response.Status)
+		assert.Equal(t, "matrix", response.Data.ResultType)
+		require.Len(t, response.Data.Result, 2)
+
+		size := response.Data.Result[0]
+		assert.Equal(t, map[string]string{"__name__": "volumeviz_volume_size_bytes", "volume": "app-data"}, size.Metric)
+		assert.Equal(t, [][2]interface{}{
+			{float64(base.Unix()), "1000"},
+			{float64(base.Add(time.Hour).Unix()), "2000"},
+			{float64(base.Add(2 * time.Hour).Unix()), "3000"},
+		}, size.Values)
+
+		// The scan without a file count is left out of that series
+		files := response.Data.Result[1]
+		assert.Equal(t, "volumeviz_volume_file_count", files.Metric["__name__"])
+		assert.Len(t, files.Values, 2)
+	})
+
+	t.Run("empty prometheus matrix is valid JSON", func(t *testing.T) {
+		w := get("/api/v1/volumes/unknown/history/export?format=prometheus")
+		require.Equal(t, 200, w.Code)
+		assert.True(t, json.Valid(w.Body.Bytes()), w.Body.String())
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		assert.Equal(t, 400, get("/api/v1/volumes/app-data/history/export?format=xml").Code)
+		assert.Equal(t, 400, get("/api/v1/volumes/app-data/history/export?since=yesterday").Code)
+		assert.Equal(t, 400, get("/api/v1/volumes/app-data/history/export?since=2025-03-02T00:00:00Z&until=2025-03-01T00:00:00Z").Code)
+	})
+}
+
+func TestParseSizeExpectation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    int64
+		tolerance   int64
+		ok          bool
+		expectErr   bool
+	}{
+		{name: "no expected size", annotations: map[string]string{"owner": "team-a"}},
+		{name: "plain bytes with default tolerance", annotations: map[string]string{"expected_size": "1000"}, expected: 1000, tolerance: 100, ok: true},
+		{name: "decimal unit", annotations: map[string]string{"expected_size": "500MB"}, expected: 500e6, tolerance: 50e6, ok: true},
+		{name: "binary unit and percent tolerance", annotations: map[string]string{"expected_size": "10GiB", "size_tolerance": "20%"}, expected: 10 << 30, tolerance: 2 << 30, ok: true},
+		{name: "absolute tolerance", annotations: map[string]string{"expected_size": "1.5 GiB", "size_tolerance": "256MiB"}, expected: 3 << 29, tolerance: 256 << 20, ok: true},
+		{name: "zero tolerance", annotations: map[string]string{"expected_size": "1KB", "size_tolerance": "0%"}, expected: 1000, ok: true},
+		{name: "invalid expected size", annotations: map[string]string{"expected_size": "big"}, expectErr: true},
+		{name: "negative expected size", annotations: map[string]string{"expected_size": "-1GB"}, expectErr: true},
+		{name: "invalid percent tolerance", annotations: map[string]string{"expected_size": "1GB", "size_tolerance": "lots%"}, expectErr: true},
+		{name: "invalid absolute tolerance", annotations: map[string]string{"expected_size": "1GB", "size_tolerance": "1XB"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectation, ok, err := parseSizeExpectation(tt.annotations)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.False(t, ok)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, expectation.expected)
+			assert.Equal(t, tt.tolerance, expectation.tolerance)
+		})
+	}
+}
+
+func TestSizeDrift_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupOverviewTestDB(t)
+	ctx := context.Background()
+
+	annotations := database.NewVolumeAnnotationRepository(db)
+	stats := scheduler.NewRepository(db)
+	scanned := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	expect := func(volume, expected, tolerance string, size *int64) {
+		require.NoError(t, annotations.SetAnnotation(ctx, volume, database.AnnotationKeyExpectedSize, expected))
+		if tolerance != "" {
+			require.NoError(t, annotations.SetAnnotation(ctx, volume, database.AnnotationKeySizeTolerance, tolerance))
+		}
+		if size != nil {
+			require.NoError(t, stats.InsertVolumeStats(ctx, &database.VolumeScanStats{VolumeName: volume, SizeBytes: *size, ScanMethod: "du", Timestamp: scanned}))
+		}
+	}
+	size := func(n int64) *int64 { return &n }
+
+	expect("steady", "1GB", "10%", size(1.05e9))
+	expect("runaway", "1GB", "10%", size(1.5e9))
+	expect("shrunk", "1GiB", "100MiB", size(512<<20))
+	expect("unscanned", "1GB", "", nil)
+	expect("broken", "1GB", "ten percent", size(1))
+	expect("removed", "1GB", "", size(5e9))
+
+	localVolume := func(name string) coremodels.Volume {
+		return coremodels.Volume{ID: name, Name: name, Driver: "local", Mountpoint: "/var/lib/docker/volumes/" + name + "/_data"}
+	}
+	volumes := []coremodels.Volume{localVolume("steady"), localVolume("runaway"), localVolume("shrunk"), localVolume("unscanned"), localVolume("broken"), localVolume("untracked")}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	for i := range volumes {
+		vol := volumes[i]
+		mockDocker.On("GetVolume", mock.Anything, vol.Name).Return(&vol, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, vol.Name).Return([]coremodels.VolumeContainer{}, nil)
+	}
+
+	engine := gin.New()
+	NewRouter(mockDocker, nil, db, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	report := func(path string) models.SizeDriftReportV1 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var report models.SizeDriftReportV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+	detail := func(name string) models.VolumeDetailV1 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/"+name, nil))
+		require.Equal(t, 200, w.Code)
+		var detail models.VolumeDetailV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+		return detail
+	}
+
+	t.Run("report lists volumes out of range", func(t *testing.T) {
+		r := report("/api/v1/reports/size-drift")
+		assert.Equal(t, 4, r.Expected, "invalid and removed volumes are not counted")
+		assert.Equal(t, 2, r.OutOfRange)
+		require.Len(t, r.Volumes, 2)
+
+		assert.Equal(t, "runaway", r.Volumes[0].Name)
+		assert.Equal(t, models.SizeDriftOver, r.Volumes[0].Status)
+		assert.Equal(t, int64(1.1e9), r.Volumes[0].MaxSizeBytes.Value)
+		assert.Equal(t, int64(0.5e9), r.Volumes[0].DeviationBytes.Value)
+		assert.Equal(t, 50.0, *r.Volumes[0].DeviationPercent)
+		require.NotNil(t, r.Volumes[0].ScannedAt)
+		assert.True(t, scanned.Equal(*r.Volumes[0].ScannedAt))
+
+		assert.Equal(t, "shrunk", r.Volumes[1].Name)
+		assert.Equal(t, models.SizeDriftUnder, r.Volumes[1].Status)
+		assert.Equal(t, int64(924<<20), r.Volumes[1].MinSizeBytes.Value)
+		assert.Equal(t, -50.0, *r.Volumes[1].DeviationPercent)
+	})
+
+	t.Run("report with all volumes", func(t *testing.T) {
+		r := report("/api/v1/reports/size-drift?all=true")
+		require.Len(t, r.Volumes, 4)
+
+		statuses := map[string]string{}
+		for _, v := range r.Volumes {
+			statuses[v.Name] = v.Status
+		}
+		assert.Equal(t, map[string]string{
+			"runaway":   models.SizeDriftOver,
+			"shrunk":    models.SizeDriftUnder,
+			"steady":    models.SizeDriftWithin,
+			"unscanned": models.SizeDriftUnknown,
+		}, statuses)
+	})
+
+	t.Run("volume detail includes drift", func(t *testing.T) {
+		d := detail("runaway")
+		require.NotNil(t, d.SizeDrift)
+		assert.Equal(t, models.SizeDriftOver, d.SizeDrift.Status)
+		assert.Equal(t, int64(1.5e9), d.SizeDrift.ActualSizeBytes.Value)
+
+		d = detail("unscanned")
+		require.NotNil(t, d.SizeDrift)
+		assert.Equal(t, models.SizeDriftUnknown, d.SizeDrift.Status)
+		assert.Nil(t, d.SizeDrift.ActualSizeBytes)
+		assert.Nil(t, d.SizeDrift.DeviationBytes)
+
+		assert.Nil(t, detail("broken").SizeDrift, "invalid annotations are ignored")
+		assert.Nil(t, detail("untracked").SizeDrift)
+	})
+
+	t.Run("report requires a database", func(t *testing.T) {
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/reports/size-drift", nil))
+		assert.Equal(t, 503, w.Code)
+	})
+}
+
+func TestListVolumes_SizeStaleness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupOverviewTestDB(t)
+	ctx := context.Background()
+	stats := scheduler.NewRepository(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	recent, old := now.Add(-time.Hour), now.Add(-72*time.Hour)
+	require.NoError(t, stats.InsertVolumeStats(ctx, &database.VolumeScanStats{VolumeName: "fresh", SizeBytes: 100, ScanMethod: "du", Timestamp: now.Add(-96 * time.Hour)}))
+	require.NoError(t, stats.InsertVolumeStats(ctx, &database.VolumeScanStats{VolumeName: "fresh", SizeBytes: 200, ScanMethod: "du", Timestamp: recent}))
+	require.NoError(t, stats.InsertVolumeStats(ctx, &database.VolumeScanStats{VolumeName: "outdated", SizeBytes: 300, ScanMethod: "du", Timestamp: old}))
+	require.NoError(t, stats.InsertVolumeStats(ctx, &database.VolumeScanStats{VolumeName: "docker-sized", SizeBytes: 400, ScanMethod: "du", Timestamp: old}))
+
+	volumes := []coremodels.Volume{
+		{ID: "fresh", Name: "fresh", Driver: "local"},
+		{ID: "outdated", Name: "outdated", Driver: "local"},
+		{ID: "docker-sized", Name: "docker-sized", Driver: "local", UsageData: &coremodels.VolumeUsage{Size: 500}},
+		{ID: "unscanned", Name: "unscanned", Driver: "local"},
+	}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+	list := func(staleAfter time.Duration) map[string]models.VolumeV1 {
+		router := NewRouter(mockDocker, nil, db, nil, nil, nil)
+		router.SetSizeStaleAfter(staleAfter)
+		engine := gin.New()
+		router.RegisterRoutes(engine.Group("/api/v1"))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes?sort=size_bytes:desc", nil))
+		require.Equal(t, 200, w.Code)
+
+		var response struct {
+			Data []models.VolumeV1 `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		// Sizes from scan stats take part in size ordering
+		names := make([]string, 0, len(response.Data))
+		byName := make(map[string]models.VolumeV1, len(response.Data))
+		for _, vol := range response.Data {
+			names = append(names, vol.Name)
+			byName[vol.Name] = vol
+		}
+		assert.Equal(t, []string{"docker-sized", "outdated", "fresh", "unscanned"}, names)
+		return byName
+	}
+
+	t.Run("recent scan is fresh and old scan is stale", func(t *testing.T) {
+		byName := list(24 * time.Hour)
+
+		fresh := byName["fresh"]
+		require.NotNil(t, fresh.SizeBytes)
+		assert.Equal(t, int64(200), fresh.SizeBytes.Value, "the latest stats row is used")
+		require.NotNil(t, fresh.SizeScannedAt)
+		assert.True(t, recent.Equal(*fresh.SizeScannedAt))
+		assert.False(t, fresh.SizeStale)
+
+		outdated := byName["outdated"]
+		require.NotNil(t, outdated.SizeBytes)
+		assert.Equal(t, int64(300), outdated.SizeBytes.Value)
+		require.NotNil(t, outdated.SizeScannedAt)
+		assert.True(t, old.Equal(*outdated.SizeScannedAt))
+		assert.True(t, outdated.SizeStale)
+
+		// A size reported by Docker is current; only the last scan time is added
+		dockerSized := byName["docker-sized"]
+		assert.Equal(t, int64(500), dockerSized.SizeBytes.Value)
+		assert.Nil(t, dockerSized.SizeScannedAt)
+		assert.False(t, dockerSized.SizeStale)
+		require.NotNil(t, dockerSized.LastScanAt)
+
+		unscanned := byName["unscanned"]
+		assert.Nil(t, unscanned.SizeBytes)
+		assert.Nil(t, unscanned.SizeScannedAt)
+		assert.Nil(t, unscanned.LastScanAt)
+		assert.False(t, unscanned.SizeStale)
+	})
+
+	t.Run("zero threshold never flags stale", func(t *testing.T) {
+		byName := list(0)
+		assert.False(t, byName["outdated"].SizeStale)
+		assert.NotNil(t, byName["outdated"].SizeScannedAt)
+	})
+}
+
+func TestListVolumeDirectory_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mountpoint := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(mountpoint, "config", "nested"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(mountpoint, "b.log"), []byte("12345"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(mountpoint, "a.txt"), []byte("hi"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(mountpoint, "config", "app.yml"), []byte("key: value"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("s3cret"), 0600))
+	require.NoError(t, os.Symlink(outside, filepath.Join(mountpoint, "escape")))
+	require.NoError(t, os.Symlink("config", filepath.Join(mountpoint, "config-link")))
+
+	dockerService := &mocks.DockerService{}
+	dockerService.On("GetVolume", mock.Anything, "app-data").Return(&coremodels.Volume{Name: "app-data", Driver: "local", Mountpoint: mountpoint}, nil)
+	dockerService.On("GetVolume", mock.Anything, "remote").Return(&coremodels.Volume{Name: "remote", Driver: "nfs", Mountpoint: "nfs://server/export"}, nil)
+	dockerService.On("GetVolume", mock.Anything, "missing").Return(nil, errors.New("volume missing not found"))
+	// Docker reports the host path, mounted here at the temporary directory
+	dockerService.On("GetVolume", mock.Anything, "host-data").Return(&coremodels.Volume{Name: "host-data", Driver: "local", Mountpoint: "/host/volumes/host-data/_data"}, nil)
+	rewrites, err := config.ParsePathRewrites([]string{"/host/volumes/host-data/_data=>" + mountpoint})
+	require.NoError(t, err)
+
+	engine := gin.New()
+	router := NewRouter(dockerService, nil, nil, nil, nil, nil)
+	router.SetPathRewrites(rewrites)
+	router.RegisterRoutes(engine.Group("/api/v1"))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+	list := func(path string) models.DirectoryListingV1 {
+		w := get(path)
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var listing models.DirectoryListingV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listing))
+		return listing
+	}
+
+	t.Run("root lists one level, directories first", func(t *testing.T) {
+		listing := list("/api/v1/volumes/app-data/ls")
+		assert.Equal(t, "app-data", listing.Volume)
+		assert.Equal(t, "", listing.Path)
+		assert.False(t, listing.Truncated)
+
+		names := make([]string, len(listing.Entries))
+		types := make(map[string]string)
+		for i, entry := range listing.Entries {
+			names[i] = entry.Name
+			types[entry.Name] = entry.Type
+		}
+		assert.Equal(t, []string{"config", "a.txt", "b.log", "config-link", "escape"}, names)
+		assert.Equal(t, models.EntryTypeDir, types["config"])
+		assert.Equal(t, models.EntryTypeFile, types["b.log"])
+		assert.Equal(t, models.EntryTypeSymlink, types["escape"])
+
+		assert.Nil(t, listing.Entries[0].SizeBytes, "directories are not sized")
+		require.NotNil(t, listing.Entries[2].SizeBytes)
+		assert.Equal(t, int64(5), listing.Entries[2].SizeBytes.Value)
+		assert.False(t, listing.Entries[2].ModifiedAt.IsZero())
+	})
+
+	t.Run("navigates into subdirectories", func(t *testing.T) {
+		listing := list("/api/v1/volumes/app-data/ls?path=config")
+		assert.Equal(t, "config", listing.Path)
+		require.Len(t, listing.Entries, 2)
+		assert.Equal(t, "nested", listing.Entries[0].Name)
+		assert.Equal(t, "app.yml", listing.Entries[1].Name)
+
+		assert.Len(t, list("/api/v1/volumes/app-data/ls?path=/config/").Entries, 2, "leading and trailing slashes are ignored")
+		assert.Len(t, list("/api/v1/volumes/app-data/ls?path=config-link").Entries, 2, "symlinks within the volume are followed")
+	})
+
+	t.Run("host paths are rewritten", func(t *testing.T) {
+		listing := list("/api/v1/volumes/host-data/ls?path=config")
+		assert.Len(t, listing.Entries, 2)
+	})
+
+	t.Run("limit truncates the listing", func(t *testing.T) {
+		listing := list("/api/v1/volumes/app-data/ls?limit=2")
+		assert.Len(t, listing.Entries, 2)
+		assert.True(t, listing.Truncated)
+	})
+
+	t.Run("rejects paths leaving the volume", func(t *testing.T) {
+		for _, path := range []string{"..", "../", "config/../..", "config/../../" + filepath.Base(outside), "escape", "escape/"} {
+			w := get("/api/v1/volumes/app-data/ls?path=" + url.QueryEscape(path))
+			assert.Equal(t, 400, w.Code, "path %q: %s", path, w.Body.String())
+			assert.NotContains(t, w.Body.String(), "secret")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, 404, get("/api/v1/volumes/app-data/ls?path=nope").Code)
+		assert.Equal(t, 400, get("/api/v1/volumes/app-data/ls?path=a.txt").Code)
+		assert.Equal(t, 400, get("/api/v1/volumes/app-data/ls?limit=0").Code)
+		assert.Equal(t, 404, get("/api/v1/volumes/missing/ls").Code)
+		assert.Equal(t, 422, get("/api/v1/volumes/remote/ls").Code)
+	})
+}
+
+func TestVolumeQuarantine_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	old := now.Add(-10 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	volumes := []coremodels.Volume{
+		{ID: "stale", Name: "stale", Driver: "local", CreatedAt: old, UsageData: &coremodels.VolumeUsage{Size: 100}},
+		{ID: "expired", Name: "expired", Driver: "local", CreatedAt: recent, Labels: map[string]string{"retention": "expired"}},
+		{ID: "fresh", Name: "fresh", Driver: "local", CreatedAt: recent},
+		{ID: "pinned", Name: "pinned", Driver: "local", CreatedAt: old, Labels: map[string]string{"pinned": "true"}},
+		{ID: "in-use", Name: "in-use", Driver: "local", CreatedAt: old},
+		{ID: "docker_cache", Name: "docker_cache", Driver: "local", CreatedAt: old},
+	}
+	newDocker := func(inUse ...string) *mocks.DockerService {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+		for _, name := range append(inUse, "in-use") {
+			mockDocker.On("GetVolumeContainers", mock.Anything, name).Return([]coremodels.VolumeContainer{{ID: "c1", Name: "app"}}, nil)
+		}
+		mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+		mockDocker.On("RemoveVolume", mock.Anything, mock.Anything).Return(nil)
+		return mockDocker
+	}
+	newRouter := func(t *testing.T) (*Router, *gin.Engine, *database.DB) {
+		db := setupOverviewTestDB(t)
+		router := NewRouter(newDocker(), nil, db, nil, nil, nil)
+		router.EnableQuarantine(7*24*time.Hour, 24*time.Hour)
+		engine := gin.New()
+		router.RegisterRoutes(engine.Group("/api/v1"))
+		return router, engine, db
+	}
+	request := func(t *testing.T, engine *gin.Engine, method, path string, code int) []byte {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		require.Equal(t, code, w.Code, w.Body.String())
+		return w.Body.Bytes()
+	}
+
+	t.Run("quarantines orphaned volumes and hides them from listings", func(t *testing.T) {
+		router, engine, _ := newRouter(t)
+
+		sweep, err := router.handler.SweepQuarantine(ctx, now)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"stale", "expired"}, sweep.quarantined)
+		assert.Empty(t, sweep.deleted)
+
+		var listed apiutils.PagedResponse
+		require.NoError(t, json.Unmarshal(request(t, engine, "GET", "/api/v1/volumes", 200), &listed))
+		assert.Equal(t, int64(3), listed.Total, "fresh, pinned and in-use remain listed")
+		var orphaned apiutils.PagedResponse
+		require.NoError(t, json.Unmarshal(request(t, engine, "GET", "/api/v1/reports/orphaned", 200), &orphaned))
+		assert.Equal(t, int64(2), orphaned.Total, "only fresh and pinned remain orphaned")
+
+		var report models.QuarantineReportV1
+		require.NoError(t, json.Unmarshal(request(t, engine, "GET", "/api/v1/reports/quarantine", 200), &report))
+		require.Equal(t, 2, report.Total)
+		assert.Equal(t, "expired", report.Volumes[0].Name)
+		assert.Equal(t, "stale", report.Volumes[1].Name)
+		assert.True(t, now.Equal(report.Volumes[1].QuarantinedAt))
+		require.NotNil(t, report.Volumes[1].DeleteAfter)
+		assert.True(t, now.Add(24*time.Hour).Equal(*report.Volumes[1].DeleteAfter))
+		assert.Equal(t, int64(100), report.Volumes[1].SizeBytes.Value)
+	})
+
+	t.Run("deletes volumes after the grace period", func(t *testing.T) {
+		router, engine, db := newRouter(t)
+		mockDocker := router.handler.dockerService.(*mocks.DockerService)
+
+		_, err := router.handler.SweepQuarantine(ctx, now)
+		require.NoError(t, err)
+
+		sweep, err := router.handler.SweepQuarantine(ctx, now.Add(23*time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, sweep.deleted)
+		mockDocker.AssertNumberOfCalls(t, "RemoveVolume", 0)
+
+		sweep, err = router.handler.SweepQuarantine(ctx, now.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"stale", "expired"}, sweep.deleted)
+		mockDocker.AssertCalled(t, "RemoveVolume", mock.Anything, "stale")
+		mockDocker.AssertCalled(t, "RemoveVolume", mock.Anything, "expired")
+		mockDocker.AssertNumberOfCalls(t, "RemoveVolume", 2)
+
+		quarantined, err := database.NewVolumeAnnotationRepository(db).ListByKey(ctx, database.AnnotationKeyQuarantined)
+		require.NoError(t, err)
+		assert.Empty(t, quarantined)
+
+		var report models.QuarantineReportV1
+		require.NoError(t, json.Unmarshal(request(t, engine, "GET", "/api/v1/reports/quarantine", 200), &report))
+		assert.Equal(t, 0, report.Total)
+	})
+
+	t.Run("restore restarts the orphan clock", func(t *testing.T) {
+		router, engine, _ := newRouter(t)
+
+		_, err := router.handler.SweepQuarantine(ctx, now)
+		require.NoError(t, err)
+
+		var restored models.QuarantineRestoreResponseV1
+		require.NoError(t, json.Unmarshal(request(t, engine, "POST", "/api/v1/volumes/stale/restore", 200), &restored))
+		assert.Equal(t, "stale", restored.Name)
+		request(t, engine, "POST", "/api/v1/volumes/stale/restore", 404)
+		request(t, engine, "POST", "/api/v1/volumes/fresh/restore", 404)
+
+		// The volume was orphaned for 10 days, but only the time since its
+		// restore counts towards quarantining it again
+		sweep, err := router.handler.SweepQuarantine(ctx, restored.RestoredAt.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, sweep.quarantined)
+		assert.Empty(t, sweep.deleted)
+
+		sweep, err = router.handler.SweepQuarantine(ctx, restored.RestoredAt.Add(7*24*time.Hour))
+		require.NoError(t, err)
+		assert.Contains(t, sweep.quarantined, "stale")
+	})
+
+	t.Run("lifts quarantine from volumes mounted again", func(t *testing.T) {
+		router, _, db := newRouter(t)
+
+		_, err := router.handler.SweepQuarantine(ctx, now)
+		require.NoError(t, err)
+
+		router.handler.dockerService = newDocker("stale")
+		sweep, err := router.handler.SweepQuarantine(ctx, now.Add(30*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stale"}, sweep.restored)
+		assert.Equal(t, []string{"expired"}, sweep.deleted)
+
+		restoredAt, err := database.NewVolumeAnnotationRepository(db).GetAnnotation(ctx, "stale", database.AnnotationKeyQuarantineRestored)
+		require.NoError(t, err)
+		assert.Equal(t, now.Add(30*time.Hour).UTC().Format(time.RFC3339), restoredAt)
+	})
+
+	t.Run("keeps volumes pinned during the grace period", func(t *testing.T) {
+		router, _, db := newRouter(t)
+		mockDocker := router.handler.dockerService.(*mocks.DockerService)
+
+		_, err := router.handler.SweepQuarantine(ctx, now)
+		require.NoError(t, err)
+		annotations := database.NewVolumeAnnotationRepository(db)
+		require.NoError(t, annotations.SetAnnotation(ctx, "stale", database.AnnotationKeyPinned, "true"))
+
+		sweep, err := router.handler.SweepQuarantine(ctx, now.Add(25*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"expired"}, sweep.deleted)
+		assert.Equal(t, []string{"stale"}, sweep.restored)
+		mockDocker.AssertNotCalled(t, "RemoveVolume", mock.Anything, "stale")
+
+		quarantined, err := annotations.ListByKey(ctx, database.AnnotationKeyQuarantined)
+		require.NoError(t, err)
+		assert.Empty(t, quarantined, "the pinned volume leaves quarantine")
+	})
+
+	t.Run("disabled without a policy", func(t *testing.T) {
+		db := setupOverviewTestDB(t)
+		router := NewRouter(newDocker(), nil, db, nil, nil, nil)
+
+		sweep, err := router.handler.SweepQuarantine(ctx, now)
+		require.NoError(t, err)
+		assert.Empty(t, sweep.quarantined)
+	})
+}
+
+func TestProbeVolumes_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	reachable := t.TempDir()
+	hung := t.TempDir()
+	nfs := func(device string) map[string]string {
+		return map[string]string{"type": "nfs", "o": "addr=10.0.0.5,rw", "device": device}
+	}
+	volumes := []coremodels.Volume{
+		{ID: "media", Name: "media", Driver: "local", Mountpoint: reachable, Options: nfs(":/export/media")},
+		{ID: "backups", Name: "backups", Driver: "local", Mountpoint: filepath.Join(reachable, "missing"), Options: nfs(":/export/backups")},
+		{ID: "share", Name: "share", Driver: "local", Mountpoint: hung, Options: map[string]string{"type": "cifs", "device": "//nas/share"}},
+		{ID: "plugin", Name: "plugin", Driver: "rexray", Mountpoint: reachable},
+		{ID: "app-data", Name: "app-data", Driver: "local", Mountpoint: reachable},
+	}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+
+	router := NewRouter(mockDocker, nil, nil, nil, nil, nil)
+	router.SetProbeLimits(2, 100*time.Millisecond)
+
+	// The hung mount never answers; the others are counted to check the pool bound
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	var inFlight, maxInFlight, hungProbes atomic.Int32
+	router.handler.probePath = func(path string) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		if path == hung {
+			hungProbes.Add(1)
+			<-release
+		}
+		return probeMountPath(path)
+	}
+
+	engine := gin.New()
+	router.RegisterRoutes(engine.Group("/api/v1"))
+	probe := func(t *testing.T, body string, code int) models.VolumeProbeReportV1 {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/probe", strings.NewReader(body)))
+		require.Equal(t, code, w.Code, w.Body.String())
+		var report models.VolumeProbeReportV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+
+	t.Run("probes every network volume", func(t *testing.T) {
+		start := time.Now()
+		report := probe(t, "", 200)
+		assert.Less(t, time.Since(start), time.Second, "a hung mount must not hold up the sweep")
+
+		assert.Equal(t, 4, report.Total)
+		assert.Equal(t, 2, report.Reachable)
+		assert.Equal(t, 2, report.Unreachable)
+		results := make(map[string]models.VolumeProbeResultV1)
+		for _, result := range report.Volumes {
+			results[result.Name] = result
+		}
+		assert.NotContains(t, results, "app-data", "local volumes are not probed")
+
+		assert.True(t, results["media"].Reachable)
+		assert.Equal(t, "nfs", results["media"].Type)
+		assert.True(t, results["plugin"].Reachable)
+		assert.False(t, results["backups"].Reachable)
+		assert.Contains(t, results["backups"].Error, "no such file")
+		assert.False(t, results["share"].Reachable)
+		assert.Contains(t, results["share"].Error, "did not respond within 100ms")
+		assert.GreaterOrEqual(t, results["share"].LatencyMs, 100.0)
+
+		assert.Equal(t, 0, report.InFlight)
+
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(2), "the abandoned hung probe keeps its slot")
+	})
+
+	t.Run("skips mounts whose earlier probe has not returned", func(t *testing.T) {
+		report := probe(t, `{"volumes":["share","media"]}`, 200)
+		require.Len(t, report.Volumes, 2)
+		assert.True(t, report.Volumes[0].Reachable)
+		share := report.Volumes[1]
+		assert.False(t, share.Reachable)
+		assert.True(t, share.InFlight)
+		assert.Contains(t, share.Error, "has not returned")
+		assert.Equal(t, 1, report.InFlight)
+		assert.Equal(t, 1, report.Unreachable)
+
+		assert.Equal(t, int32(1), hungProbes.Load(), "the hung mount is not stat'ed again")
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	})
+
+	t.Run("filters by name and driver", func(t *testing.T) {
+		report := probe(t, `{"volumes":["app-data","media"]}`, 200)
+		require.Len(t, report.Volumes, 2)
+		assert.Equal(t, "app-data", report.Volumes[0].Name, "named volumes are probed even when local")
+		assert.Equal(t, "media", report.Volumes[1].Name)
+
+		report = probe(t, `{"driver":"rexray"}`, 200)
+		require.Len(t, report.Volumes, 1)
+		assert.Equal(t, "plugin", report.Volumes[0].Name)
+	})
+
+	t.Run("lists unknown volumes as not found", func(t *testing.T) {
+		report := probe(t, `{"volumes":["media","ghost","media"]}`, 207)
+		require.Len(t, report.Volumes, 1)
+		assert.Equal(t, "media", report.Volumes[0].Name)
+		assert.Equal(t, 1, report.Succeeded)
+		assert.Equal(t, []string{"ghost"}, report.NotFound)
+		assert.Empty(t, report.Errors)
+		assert.Equal(t, []models.BatchItemV1{
+			{ID: "media", Status: 200},
+			{ID: "ghost", Status: 404, Error: "not found"},
+		}, report.Items)
+
+		report = probe(t, `{"volumes":["ghost"]}`, 404)
+		assert.Empty(t, report.Volumes)
+		assert.Equal(t, []string{"ghost"}, report.NotFound)
+	})
+
+	t.Run("rejects empty lists and bad bodies", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/probe", strings.NewReader(`{"volumes":[]}`)))
+		assert.Equal(t, 400, w.Code)
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/probe", strings.NewReader(`{"volumes":`)))
+		assert.Equal(t, 400, w.Code)
+	})
+}
+
+func TestGetVolume_InlineAttachmentsCap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	containersOf := func(n int) []coremodels.VolumeContainer {
+		containers := make([]coremodels.VolumeContainer, n)
+		for i := range containers {
+			containers[i] = coremodels.VolumeContainer{ID: fmt.Sprintf("c%02d", i), Name: fmt.Sprintf("app-%02d", i), MountPath: "/data", AccessMode: "rw"}
+		}
+		return containers
+	}
+	newEngine := func(attachments int) *gin.Engine {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("GetVolume", mock.Anything, "shared").Return(&coremodels.Volume{ID: "shared", Name: "shared", Driver: "local"}, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, "shared").Return(containersOf(attachments), nil)
+
+		router := NewRouter(mockDocker, nil, nil, nil, nil, nil)
+		router.SetInlineAttachments(5)
+		engine := gin.New()
+		router.RegisterRoutes(engine.Group("/api/v1"))
+		return engine
+	}
+	get := func(t *testing.T, engine *gin.Engine, path string, out interface{}) {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, 200, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), out))
+	}
+
+	t.Run("below the cap", func(t *testing.T) {
+		var detail models.VolumeDetailV1
+		get(t, newEngine(5), "/api/v1/volumes/shared", &detail)
+		assert.Len(t, detail.Attachments, 5)
+		assert.Equal(t, 5, detail.AttachmentsTotal)
+		assert.False(t, detail.MoreAttachments)
+		assert.Empty(t, detail.AttachmentsURL)
+	})
+
+	t.Run("above the cap", func(t *testing.T) {
+		engine := newEngine(12)
+		var detail models.VolumeDetailV1
+		get(t, engine, "/api/v1/volumes/shared", &detail)
+		require.Len(t, detail.Attachments, 5)
+		assert.Equal(t, "c00", detail.Attachments[0].ContainerID)
+		assert.Equal(t, 12, detail.AttachmentsTotal)
+		assert.True(t, detail.MoreAttachments)
+		assert.Equal(t, "/api/v1/volumes/shared/attachments?page=2&page_size=5", detail.AttachmentsURL)
+
+		// The pointer continues right after the inline attachments
+		var page models.AttachmentsListV1
+		get(t, engine, detail.AttachmentsURL, &page)
+		require.Len(t, page.Data, 5)
+		assert.Equal(t, "c05", page.Data[0].ContainerID)
+		assert.Equal(t, 12, page.Total)
+		assert.Equal(t, 2, page.Page)
+
+		get(t, engine, "/api/v1/volumes/shared/attachments?page=3&page_size=5", &page)
+		require.Len(t, page.Data, 2)
+		assert.Equal(t, "c11", page.Data[1].ContainerID)
+
+		// Without paging the endpoint still lists every attachment
+		var all models.AttachmentsListV1
+		get(t, engine, "/api/v1/volumes/shared/attachments", &all)
+		assert.Len(t, all.Data, 12)
+		assert.Zero(t, all.Page)
+	})
+
+	t.Run("cap disabled", func(t *testing.T) {
+		mockDocker := &mocks.DockerService{}
+		mockDocker.On("GetVolume", mock.Anything, "shared").Return(&coremodels.Volume{ID: "shared", Name: "shared", Driver: "local"}, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, "shared").Return(containersOf(40), nil)
+		router := NewRouter(mockDocker, nil, nil, nil, nil, nil)
+		router.SetInlineAttachments(0)
+		engine := gin.New()
+		router.RegisterRoutes(engine.Group("/api/v1"))
+
+		var detail models.VolumeDetailV1
+		get(t, engine, "/api/v1/volumes/shared", &detail)
+		assert.Len(t, detail.Attachments, 40)
+		assert.False(t, detail.MoreAttachments)
+	})
+}
+
+func TestAllReadOnly_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	consumers := map[string][]coremodels.VolumeContainer{
+		"backups": {
+			{ID: "c1", Name: "restic", MountPath: "/backups", AccessMode: "ro"},
+			{ID: "c2", Name: "exporter", MountPath: "/data", AccessMode: "ro"},
+		},
+		"shared": {
+			{ID: "c3", Name: "reader", MountPath: "/data", AccessMode: "ro"},
+			{ID: "c4", Name: "writer", MountPath: "/data", AccessMode: "rw"},
+		},
+		"unused": {},
+	}
+	mockDocker := &mocks.DockerService{}
+	var volumes []coremodels.Volume
+	for _, name := range []string{"backups", "shared", "unused"} {
+		volume := coremodels.Volume{ID: name, Name: name, Driver: "local"}
+		volumes = append(volumes, volume)
+		mockDocker.On("GetVolume", mock.Anything, name).Return(&volume, nil)
+		mockDocker.On("GetVolumeContainers", mock.Anything, name).Return(consumers[name], nil)
+	}
+	mockDocker.On("ListVolumes", mock.Anything).Return(volumes, nil)
+
+	router := NewRouter(mockDocker, nil, nil, nil, nil, nil)
+	engine := gin.New()
+	router.RegisterRoutes(engine.Group("/api/v1"))
+	get := func(t *testing.T, path string, out interface{}) int {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code == 200 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), out))
+		}
+		return w.Code
+	}
+
+	t.Run("detail", func(t *testing.T) {
+		readOnly, writable := true, false
+		for name, want := range map[string]*bool{"backups": &readOnly, "shared": &writable, "unused": nil} {
+			var detail models.VolumeDetailV1
+			require.Equal(t, 200, get(t, "/api/v1/volumes/"+name, &detail))
+			assert.Equal(t, want, detail.AllReadOnly, name)
+		}
+
+		// No attachments is reported as null, not false
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/unused", nil))
+		assert.Contains(t, w.Body.String(), `"all_readonly":null`)
+	})
+
+	t.Run("list filter", func(t *testing.T) {
+		names := func(t *testing.T, query string) []string {
+			var page struct {
+				Data []models.VolumeV1 `json:"data"`
+			}
+			require.Equal(t, 200, get(t, "/api/v1/volumes"+query, &page))
+			listed := []string{}
+			for _, volume := range page.Data {
+				listed = append(listed, volume.Name)
+			}
+			return listed
+		}
+
+		assert.Equal(t, []string{"backups", "shared", "unused"}, names(t, "?sort=name:asc"))
+		// Volumes without attachments match neither value
+		assert.Equal(t, []string{"backups"}, names(t, "?all_readonly=true"))
+		assert.Equal(t, []string{"shared"}, names(t, "?all_readonly=false"))
+
+		assert.Equal(t, 400, get(t, "/api/v1/volumes?all_readonly=maybe", nil))
+	})
+}
+
+func TestDriverConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		volume   coremodels.Volume
+		expected models.DriverConfigV1
+	}{
+		{
+			name:     "docker-managed directory",
+			volume:   coremodels.Volume{Driver: "local"},
+			expected: models.DriverConfigV1{Driver: "local", Recognized: true, Kind: models.DriverConfigDefault},
+		},
+		{
+			name: "nfs with server in addr",
+			volume: coremodels.Volume{Driver: "local", Options: map[string]string{
+				"type": "nfs", "o": "addr=10.0.0.5,rw,nfsvers=4", "device": ":/exports/media",
+			}},
+			expected: models.DriverConfigV1{
+				Driver: "local", Recognized: true, Kind: models.DriverConfigNFS, Type: "nfs",
+				MountOptions: []string{"addr=10.0.0.5", "rw", "nfsvers=4"},
+				Device:       ":/exports/media", Server: "10.0.0.5", Source: "/exports/media",
+			},
+		},
+		{
+			name: "nfs4 with server in device",
+			volume: coremodels.Volume{Driver: "local", Options: map[string]string{
+				"type": "nfs4", "device": "nas.local:/backups",
+			}},
+			expected: models.DriverConfigV1{
+				Driver: "local", Recognized: true, Kind: models.DriverConfigNFS, Type: "nfs4",
+				Device: "nas.local:/backups", Server: "nas.local", Source: "/backups",
+			},
+		},
+		{
+			name: "cifs share",
+			volume: coremodels.Volume{Driver: "local", Options: map[string]string{
+				"type": "cifs", "o": "username=media,vers=3.0", "device": "//fileserver/media/movies",
+			}},
+			expected: models.DriverConfigV1{
+				Driver: "local", Recognized: true, Kind: models.DriverConfigCIFS, Type: "cifs",
+				MountOptions: []string{"username=media", "vers=3.0"},
+				Device:       "//fileserver/media/movies", Server: "fileserver", Source: "media/movies",
+			},
+		},
+		{
+			name: "bind mount with an unknown option",
+			volume: coremodels.Volume{Driver: "local", Options: map[string]string{
+				"type": "none", "o": "bind,ro", "device": "/srv/data", "uid": "1000",
+			}},
+			expected: models.DriverConfigV1{
+				Driver: "local", Recognized: true, Kind: models.DriverConfigBind, Type: "none",
+				MountOptions: []string{"bind", "ro"}, Device: "/srv/data", Source: "/srv/data",
+				Options: map[string]string{"uid": "1000"},
+			},
+		},
+		{
+			name: "block device",
+			volume: coremodels.Volume{Driver: "local", Options: map[string]string{
+				"type": "ext4", "device": "/dev/sdb1",
+			}},
+			expected: models.DriverConfigV1{
+				Driver: "local", Recognized: true, Kind: models.DriverConfigDevice, Type: "ext4", Device: "/dev/sdb1",
+			},
+		},
+		{
+			name: "unknown driver passes options through",
+			volume: coremodels.Volume{Driver: "rexray/ebs", Options: map[string]string{
+				"size": "20", "volumetype": "gp3", "type": "nfs",
+			}},
+			expected: models.DriverConfigV1{
+				Driver:  "rexray/ebs",
+				Options: map[string]string{"size": "20", "volumetype": "gp3", "type": "nfs"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, driverConfig(tt.volume))
+		})
+	}
+}
+
+func TestGetVolume_DriverConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	options := map[string]string{"type": "nfs", "o": "addr=10.0.0.5,rw", "device": ":/exports/media"}
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("GetVolume", mock.Anything, "media").Return(&coremodels.Volume{ID: "media", Name: "media", Driver: "local", Options: options}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, "media").Return([]coremodels.VolumeContainer{}, nil)
+
+	router := NewRouter(mockDocker, nil, nil, nil, nil, nil)
+	engine := gin.New()
+	router.RegisterRoutes(engine.Group("/api/v1"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/media", nil))
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	var detail struct {
+		Meta struct {
+			DriverOpts   map[string]string     `json:"driver_opts"`
+			DriverConfig models.DriverConfigV1 `json:"driver_config"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+
+	// The raw options are still returned next to the parsed ones
+	assert.Equal(t, options, detail.Meta.DriverOpts)
+	assert.Equal(t, models.DriverConfigNFS, detail.Meta.DriverConfig.Kind)
+	assert.Equal(t, "10.0.0.5", detail.Meta.DriverConfig.Server)
+	assert.Equal(t, "/exports/media", detail.Meta.DriverConfig.Source)
+	assert.Equal(t, []string{"addr=10.0.0.5", "rw"}, detail.Meta.DriverConfig.MountOptions)
+}
+
+// writeTree creates the given files, keyed by slash-separated path, below a new temp dir
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	return root
+}
+
+func TestFingerprintTree(t *testing.T) {
+	files := map[string]string{
+		"config/app.yml":   "key: value",
+		"data/records.db":  strings.Repeat("row\n", 50000),
+		"data/index.idx":   "0123456789",
+		"logs/current.log": "started",
+	}
+	different := map[string]string{
+		"config/app.yml":   "key: other",
+		"data/records.db":  strings.Repeat("row\n", 50000),
+		"data/index.idx":   "0123456789",
+		"logs/current.log": "started",
+	}
+
+	first, err := fingerprintTree(context.Background(), writeTree(t, files), 32, 1000)
+	require.NoError(t, err)
+	second, err := fingerprintTree(context.Background(), writeTree(t, files), 32, 1000)
+	require.NoError(t, err)
+	other, err := fingerprintTree(context.Background(), writeTree(t, different), 32, 1000)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, first.files)
+	assert.Equal(t, first.structure, second.structure)
+	assert.Equal(t, first.content, second.content)
+	assert.Equal(t, models.DuplicateConfidenceHigh, first.confidence())
+
+	// Same paths and sizes, but one file's content differs
+	assert.Equal(t, first.structure, other.structure)
+	assert.NotEqual(t, first.content, other.content)
+
+	t.Run("only the head and tail of large files are hashed", func(t *testing.T) {
+		assert.Less(t, first.sampledBytes, first.bytes)
+		assert.Equal(t, int64(2*duplicateSampleChunk+len("key: value")+len("0123456789")+len("started")), first.sampledBytes)
+	})
+
+	t.Run("low sample coverage lowers the confidence", func(t *testing.T) {
+		large := make(map[string]string)
+		for i := range 10 {
+			large[fmt.Sprintf("chunk-%d", i)] = strings.Repeat("y", 4*duplicateSampleChunk)
+		}
+		sampled, err := fingerprintTree(context.Background(), writeTree(t, large), 1, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2*duplicateSampleChunk), sampled.sampledBytes)
+		assert.Equal(t, models.DuplicateConfidenceMedium, sampled.confidence())
+	})
+
+	t.Run("file limit truncates with low confidence", func(t *testing.T) {
+		truncated, err := fingerprintTree(context.Background(), writeTree(t, files), 32, 2)
+		require.NoError(t, err)
+		assert.True(t, truncated.truncated)
+		assert.Equal(t, 2, truncated.files)
+		assert.Equal(t, models.DuplicateConfidenceLow, truncated.confidence())
+	})
+}
+
+func TestDuplicatesReport_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	files := map[string]string{
+		"config/app.yml": "key: value",
+		"data/blob.bin":  strings.Repeat("x", 4096),
+	}
+	original, copied := writeTree(t, files), writeTree(t, files)
+	for _, root := range []string{original, copied} {
+		require.NoError(t, os.Symlink("config", filepath.Join(root, "current")))
+	}
+	dockerService := &mocks.DockerService{}
+	dockerService.On("ListVolumes", mock.Anything).Return([]coremodels.Volume{
+		{Name: "app-data", Driver: "local", Mountpoint: original},
+		{Name: "app-data-copy", Driver: "local", Mountpoint: copied},
+		{Name: "other", Driver: "local", Mountpoint: writeTree(t, map[string]string{"notes.txt": "unrelated"})},
+		{Name: "empty", Driver: "local", Mountpoint: t.TempDir()},
+		{Name: "empty-too", Driver: "local", Mountpoint: t.TempDir()},
+		{Name: "remote", Driver: "nfs", Mountpoint: "nfs://server/export"},
+	}, nil)
+
+	router := NewRouter(dockerService, nil, nil, nil, nil, nil)
+	engine := gin.New()
+	router.RegisterRoutes(engine.Group("/api/v1"))
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/reports/duplicates", nil))
+		return w
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, 404, get().Code)
+	})
+
+	router.EnableDuplicateDetection(0, 0)
+	count, err := router.handler.FingerprintVolumes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	w := get()
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var report models.DuplicatesReportV1
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+
+	assert.True(t, report.Heuristic)
+	assert.NotEmpty(t, report.Note)
+	assert.Equal(t, 5, report.VolumesFingerprinted)
+	require.NotNil(t, report.FingerprintedAt)
+
+	// Empty volumes all match each other and are left out
+	require.Len(t, report.Groups, 1)
+	group := report.Groups[0]
+	assert.Equal(t, []string{"app-data", "app-data-copy"}, group.Volumes)
+	assert.Equal(t, models.DuplicateConfidenceHigh, group.Confidence)
+	assert.Equal(t, 2, group.FileCount)
+	assert.Equal(t, int64(4096+len("key: value")), group.SizeBytes.Value)
+	assert.Equal(t, group.SizeBytes.Value, group.ReclaimableBytes.Value)
+	assert.Equal(t, group.ReclaimableBytes.Value, report.TotalReclaimableBytes.Value)
+	assert.InDelta(t, 1.0, group.SampledFraction, 0.001)
+}
+
+func TestGetVolumeChanges_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db := setupOverviewTestDB(t)
+	migrations, err := database.NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "010" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	since := now.Add(-time.Hour).UTC().Truncate(time.Second)
+	events := database.NewEventRepository(db)
+	for _, vol := range []struct {
+		name      string
+		createdAt time.Time
+	}{{"fresh", now}, {"resized", old}, {"untouched", old}, {"gone", old}} {
+		require.NoError(t, events.UpsertVolume(ctx, &database.Volume{
+			VolumeID: vol.name, Name: vol.name, Driver: "local", Mountpoint: "/data/" + vol.name,
+			Scope: "local", Status: "active", IsActive: true,
+			BaseModel: database.BaseModel{CreatedAt: vol.createdAt, UpdatedAt: vol.createdAt},
+		}))
+	}
+	require.NoError(t, scheduler.NewRepository(db).InsertVolumeStats(ctx, &database.VolumeScanStats{
+		VolumeName: "resized", SizeBytes: 2048, ScanMethod: "du", Timestamp: now,
+	}))
+	require.NoError(t, events.DeleteVolume(ctx, "gone"))
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return([]coremodels.Volume{
+		{ID: "fresh", Name: "fresh", Driver: "local", CreatedAt: now},
+		{ID: "resized", Name: "resized", Driver: "local", CreatedAt: old},
+		{ID: "untouched", Name: "untouched", Driver: "local", CreatedAt: old},
+	}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+
+	engine := gin.New()
+	NewRouter(mockDocker, nil, db, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	t.Run("creates, updates and removals since the time", func(t *testing.T) {
+		before := time.Now()
+		w := get("/api/v1/volumes/changes?since=" + url.QueryEscape(since.Format(time.RFC3339)))
+		require.Equal(t, 200, w.Code, w.Body.String())
+
+		var changes models.VolumeChangesV1
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &changes))
+		assert.True(t, since.Equal(changes.Since))
+		assert.False(t, changes.ServerTime.Before(before.Truncate(time.Second)))
+
+		require.Len(t, changes.Created, 1)
+		assert.Equal(t, "fresh", changes.Created[0].Name)
+		require.Len(t, changes.Updated, 1)
+		assert.Equal(t, "resized", changes.Updated[0].Name)
+		require.NotNil(t, changes.Updated[0].SizeBytes)
+		assert.Equal(t, int64(2048), changes.Updated[0].SizeBytes.Value)
+		require.Len(t, changes.Removed, 1)
+		assert.Equal(t, "gone", changes.Removed[0].Name)
+	})
+
+	t.Run("nothing changed since server time", func(t *testing.T) {
+		next := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+		w := get("/api/v1/volumes/changes?since=" + url.QueryEscape(next))
+		require.Equal(t, 200, w.Code, w.Body.String())
+		assert.JSONEq(t, `[]`, mustJSONField(t, w.Body.Bytes(), "created"))
+		assert.JSONEq(t, `[]`, mustJSONField(t, w.Body.Bytes(), "updated"))
+		assert.JSONEq(t, `[]`, mustJSONField(t, w.Body.Bytes(), "removed"))
+	})
+
+	t.Run("since is required and must be RFC3339", func(t *testing.T) {
+		assert.Equal(t, 400, get("/api/v1/volumes/changes").Code)
+		assert.Equal(t, 400, get("/api/v1/volumes/changes?since=yesterday").Code)
+	})
+
+	t.Run("requires a database", func(t *testing.T) {
+		engine := gin.New()
+		NewRouter(mockDocker, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/changes?since="+url.QueryEscape(since.Format(time.RFC3339)), nil))
+		assert.Equal(t, 503, w.Code)
+	})
+}
+
+// mustJSONField returns the raw JSON of a top-level field of body
+func mustJSONField(t *testing.T, body []byte, field string) string {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &fields))
+	return string(fields[field])
+}
+
+func TestVolumeLock_V1API(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db := setupOverviewTestDB(t)
+	engine := gin.New()
+	NewRouter(&mocks.DockerService{}, nil, db, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	call := func(t *testing.T, method, path, body string, code int) models.VolumeLockV1 {
+		t.Helper()
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		require.Equal(t, code, w.Code, w.Body.String())
+		var lock models.VolumeLockV1
+		if code == 200 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &lock))
+		}
+		return lock
+	}
+
+	t.Run("locks until released", func(t *testing.T) {
+		lock := call(t, "GET", "/api/v1/volumes/pg_data/lock", "", 200)
+		assert.False(t, lock.Locked)
+
+		before := time.Now()
+		lock = call(t, "POST", "/api/v1/volumes/pg_data/lock", `{"ttl":"2h","holder":"restic nightly"}`, 200)
+		assert.True(t, lock.Locked)
+		assert.Equal(t, "pg_data", lock.Name)
+		assert.Equal(t, "restic nightly", lock.Holder)
+		require.NotNil(t, lock.LockedUntil)
+		assert.WithinDuration(t, before.Add(2*time.Hour), *lock.LockedUntil, 2*time.Second)
+		assert.InDelta(t, 7200, lock.ExpiresInSeconds, 2)
+
+		// The scheduler reads the lock from the volume's annotations
+		held, err := database.NewVolumeAnnotationRepository(db).ActiveScanLock(ctx, "pg_data", time.Now())
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		assert.Equal(t, "restic nightly", held.Holder)
+
+		// Locking again renews the lock
+		lock = call(t, "POST", "/api/v1/volumes/pg_data/lock", `{"ttl":"1d"}`, 200)
+		assert.Empty(t, lock.Holder)
+		assert.InDelta(t, 86400, lock.ExpiresInSeconds, 2)
+		assert.True(t, call(t, "GET", "/api/v1/volumes/pg_data/lock", "", 200).Locked)
+
+		lock = call(t, "DELETE", "/api/v1/volumes/pg_data/lock", "", 200)
+		assert.False(t, lock.Locked)
+		assert.False(t, call(t, "GET", "/api/v1/volumes/pg_data/lock", "", 200).Locked)
+		call(t, "DELETE", "/api/v1/volumes/pg_data/lock", "", 404)
+	})
+
+	t.Run("expired locks are reported unlocked", func(t *testing.T) {
+		repo := database.NewVolumeAnnotationRepository(db)
+		require.NoError(t, repo.LockScans(ctx, "old_data", database.ScanLock{Until: time.Now().Add(-time.Minute), Holder: "crashed job"}))
+
+		assert.False(t, call(t, "GET", "/api/v1/volumes/old_data/lock", "", 200).Locked)
+		// The stale annotation can still be cleaned up
+		call(t, "DELETE", "/api/v1/volumes/old_data/lock", "", 200)
+	})
+
+	t.Run("rejects bad requests", func(t *testing.T) {
+		call(t, "POST", "/api/v1/volumes/pg_data/lock", `{}`, 400)
+		call(t, "POST", "/api/v1/volumes/pg_data/lock", `{"ttl":"soon"}`, 400)
+		call(t, "POST", "/api/v1/volumes/pg_data/lock", `{"ttl":"-1h"}`, 400)
+		call(t, "POST", "/api/v1/volumes/pg_data/lock", `{"ttl":"30d"}`, 400)
+		call(t, "POST", "/api/v1/volumes/pg_data/lock", `{"ttl":"1h","holder":"line\nbreak"}`, 400)
+		call(t, "POST", "/api/v1/volumes/bad%20name/lock", `{"ttl":"1h"}`, 400)
+		assert.False(t, call(t, "GET", "/api/v1/volumes/pg_data/lock", "", 200).Locked)
+	})
+}
+
+func TestListVolumes_DockerCallBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Three volumes and two containers, one of which mounts alpha: listing a
+	// volume's containers costs one list and two inspects
+	client := &mocks.MockDockerClient{
+		ListVolumesFunc: func(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
+			return volume.ListResponse{Volumes: []*volume.Volume{
+				{Name: "alpha", Driver: "local"},
+				{Name: "bravo", Driver: "local"},
+				{Name: "charlie", Driver: "local"},
+			}}, nil
+		},
+		ListContainersFunc: func(ctx context.Context, filterMap map[string][]string) ([]containertypes.Summary, error) {
+			return []containertypes.Summary{{ID: "web", State: "running"}, {ID: "worker", State: "running"}}, nil
+		},
+		InspectContainerFunc: func(ctx context.Context, containerID string) (containertypes.InspectResponse, error) {
+			response := containertypes.InspectResponse{ContainerJSONBase: &containertypes.ContainerJSONBase{ID: containerID, Name: "/" + containerID}}
+			if containerID == "web" {
+				response.Mounts = []containertypes.MountPoint{{Type: mount.TypeVolume, Name: "alpha", Destination: "/data", RW: true}}
+			}
+			return response, nil
+		},
+	}
+	dockerService := services.NewDockerServiceWithClient(client)
+
+	list := func(t *testing.T, budget int, query string) (names []string, partial bool, warning string) {
+		t.Helper()
+		engine := gin.New()
+		engine.Use(middleware.DockerCallBudgetMiddleware(budget))
+		NewRouter(dockerService, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes?sort=name:asc&"+query, nil))
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var response struct {
+			Data    []models.VolumeV1 `json:"data"`
+			Total   int64             `json:"total"`
+			Partial bool              `json:"partial"`
+			Warning string            `json:"warning"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		for _, vol := range response.Data {
+			names = append(names, vol.Name)
+		}
+		assert.Equal(t, int64(len(names)), response.Total)
+		return names, response.Partial, response.Warning
+	}
+
+	t.Run("within budget", func(t *testing.T) {
+		names, partial, warning := list(t, 10, "")
+		assert.Equal(t, []string{"alpha", "bravo", "charlie"}, names)
+		assert.False(t, partial)
+		assert.Empty(t, warning)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		names, partial, _ := list(t, 0, "orphaned=true")
+		assert.Equal(t, []string{"bravo", "charlie"}, names)
+		assert.False(t, partial)
+	})
+
+	t.Run("exceeded while converting", func(t *testing.T) {
+		// One call to list the volumes leaves attachments for two of them
+		names, partial, warning := list(t, 7, "")
+		assert.Equal(t, []string{"alpha", "bravo"}, names)
+		assert.True(t, partial)
+		assert.Contains(t, warning, "Docker API call budget")
+	})
+
+	t.Run("exceeded while filtering orphans", func(t *testing.T) {
+		// alpha and bravo are checked and charlie runs out; bravo keeps the
+		// attachments the filter listed
+		names, partial, _ := list(t, 8, "orphaned=true")
+		assert.Equal(t, []string{"bravo"}, names)
+		assert.True(t, partial)
+	})
+
+	get := func(t *testing.T, budget int, path string) map[string]any {
+		t.Helper()
+		engine := gin.New()
+		engine.Use(middleware.DockerCallBudgetMiddleware(budget))
+		NewRouter(dockerService, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("orphaned report", func(t *testing.T) {
+		// Unlimited, bravo and charlie are orphaned
+		response := get(t, 0, "/api/v1/reports/orphaned?sort=name:asc")
+		assert.Equal(t, float64(2), response["total"])
+		assert.NotContains(t, response, "partial")
+
+		// Running out at charlie stops the report at bravo rather than
+		// reporting charlie orphaned
+		response = get(t, 8, "/api/v1/reports/orphaned?sort=name:asc")
+		require.Len(t, response["data"], 1)
+		assert.Equal(t, "bravo", response["data"].([]any)[0].(map[string]any)["name"])
+		assert.Equal(t, true, response["partial"])
+		assert.Contains(t, response["warning"], "Docker API call budget")
+	})
+
+	t.Run("by project report", func(t *testing.T) {
+		response := get(t, 0, "/api/v1/reports/by-project")
+		assert.Equal(t, float64(3), response["total_volumes"])
+		assert.NotContains(t, response, "partial")
+
+		// Running out at charlie leaves it out of the report and its totals
+		response = get(t, 8, "/api/v1/reports/by-project")
+		assert.Equal(t, float64(2), response["total_volumes"])
+		assert.Equal(t, true, response["partial"])
+		assert.Contains(t, response["warning"], "Docker API call budget")
+	})
+
+	t.Run("volume detail", func(t *testing.T) {
+		client.InspectVolumeFunc = func(ctx context.Context, volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		}
+		defer func() { client.InspectVolumeFunc = nil }()
+
+		response := get(t, 0, "/api/v1/volumes/alpha")
+		assert.Equal(t, float64(1), response["attachments_total"])
+		assert.NotContains(t, response, "partial")
+
+		// The inspect leaves too little to list alpha's containers
+		response = get(t, 3, "/api/v1/volumes/alpha")
+		assert.Equal(t, "alpha", response["name"])
+		assert.Equal(t, true, response["partial"])
+		assert.Contains(t, response["warning"], "Docker API call budget")
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		prune := func(t *testing.T, budget int) models.PruneResponseV1 {
+			t.Helper()
+			engine := gin.New()
+			engine.Use(middleware.DockerCallBudgetMiddleware(budget))
+			NewRouter(dockerService, nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+			w := httptest.NewRecorder()
+			body := strings.NewReader(`{"dry_run": true, "match": {"tier": "scratch"}}`)
+			engine.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/volumes/prune", body))
+			require.Equal(t, 200, w.Code, w.Body.String())
+			var response models.PruneResponseV1
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			return response
+		}
+		listVolumes := client.ListVolumesFunc
+		client.ListVolumesFunc = func(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
+			scratch := map[string]string{"tier": "scratch"}
+			return volume.ListResponse{Volumes: []*volume.Volume{
+				{Name: "alpha", Driver: "local", Labels: scratch},
+				{Name: "bravo", Driver: "local", Labels: scratch},
+				{Name: "charlie", Driver: "local", Labels: scratch},
+			}}, nil
+		}
+		defer func() { client.ListVolumesFunc = listVolumes }()
+
+		response := prune(t, 0)
+		assert.Equal(t, 2, response.Selected)
+		assert.Nil(t, response.PartialResultV1)
+
+		// charlie cannot be checked, so it is left out rather than selected
+		response = prune(t, 8)
+		require.Len(t, response.Volumes, 1)
+		assert.Equal(t, "bravo", response.Volumes[0].Name)
+		require.NotNil(t, response.PartialResultV1)
+		assert.True(t, response.Partial)
+		assert.Contains(t, response.Warning, "Docker API call budget")
+	})
+}
+
+func TestListVolumes_RemovedDuringList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// bravo and the container using it are removed after being listed
+	removed := errdefs.NotFound(errors.New("Error response from daemon: no such object"))
+	client := &mocks.MockDockerClient{
+		ListVolumesFunc: func(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
+			return volume.ListResponse{Volumes: []*volume.Volume{
+				{Name: "alpha", Driver: "local"},
+				{Name: "bravo", Driver: "local"},
+			}}, nil
+		},
+		InspectVolumeFunc: func(ctx context.Context, volumeID string) (volume.Volume, error) {
+			if volumeID == "bravo" {
+				return volume.Volume{}, removed
+			}
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+		ListContainersFunc: func(ctx context.Context, filterMap map[string][]string) ([]containertypes.Summary, error) {
+			return []containertypes.Summary{{ID: "web", State: "running"}, {ID: "job", State: "running"}}, nil
+		},
+		InspectContainerFunc: func(ctx context.Context, containerID string) (containertypes.InspectResponse, error) {
+			if containerID == "job" {
+				return containertypes.InspectResponse{}, removed
+			}
+			return containertypes.InspectResponse{
+				ContainerJSONBase: &containertypes.ContainerJSONBase{ID: containerID, Name: "/" + containerID},
+				Mounts:            []containertypes.MountPoint{{Type: mount.TypeVolume, Name: "alpha", Destination: "/data", RW: true}},
+			}, nil
+		},
+	}
+	engine := gin.New()
+	NewRouter(services.NewDockerServiceWithClient(client), nil, nil, nil, nil, nil).RegisterRoutes(engine.Group("/api/v1"))
+
+	// The list is a snapshot: both volumes are listed as Docker listed them,
+	// without the removed container, and the response is complete
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes?sort=name:asc", nil))
+	require.Equal(t, 200, w.Code, w.Body.String())
+	var response struct {
+		Data    []models.VolumeV1 `json:"data"`
+		Partial bool              `json:"partial"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, "alpha", response.Data[0].Name)
+	assert.Equal(t, 1, response.Data[0].AttachmentsCount)
+	assert.Equal(t, "bravo", response.Data[1].Name)
+	assert.Zero(t, response.Data[1].AttachmentsCount)
+	assert.False(t, response.Partial)
+
+	// Following the list to the removed volume finds it gone
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/volumes/bravo", nil))
+	assert.Equal(t, 404, w.Code, w.Body.String())
+}
+
+func TestListVolumes_PathFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockDocker := &mocks.DockerService{}
+	mockDocker.On("ListVolumes", mock.Anything).Return([]coremodels.Volume{
+		{ID: "app", Name: "app", Driver: "local", Mountpoint: "/var/lib/docker/volumes/app/_data"},
+		{ID: "media", Name: "media", Driver: "local", Mountpoint: "/var/lib/docker/volumes/media/_data",
+			Options: map[string]string{"type": "none", "o": "bind", "device": "/mnt/data/foo"}},
+		{ID: "media-cache", Name: "media-cache", Driver: "local", Mountpoint: "/var/lib/docker/volumes/media-cache/_data",
+			Options: map[string]string{"type": "none", "o": "bind", "device": "/mnt/data/foo/cache"}},
+		{ID: "share", Name: "share", Driver: "nfs", Mountpoint: "/mnt/nfs/share"},
+	}, nil)
+	mockDocker.On("GetVolumeContainers", mock.Anything, mock.Anything).Return([]coremodels.VolumeContainer{}, nil)
+	handler := NewHandler(mockDocker, nil, nil)
+
+	list := func(t *testing.T, query string) ([]string, map[string]interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?sort=name:asc&"+query, nil)
+		handler.ListVolumes(c)
+		require.Equal(t, 200, w.Code, w.Body.String())
+		var response struct {
+			Data    []models.VolumeV1      `json:"data"`
+			Filters map[string]interface{} `json:"filters"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		names := []string{}
+		for _, vol := range response.Data {
+			names = append(names, vol.Name)
+		}
+		return names, response.Filters
+	}
+
+	t.Run("mountpoint prefix", func(t *testing.T) {
+		names, filters := list(t, "mountpoint=/var/lib/docker/volumes/media*")
+		assert.Equal(t, []string{"media", "media-cache"}, names)
+		assert.Equal(t, "/var/lib/docker/volumes/media*", filters["mountpoint"])
+
+		names, _ = list(t, "mountpoint=/mnt/*")
+		assert.Equal(t, []string{"share"}, names)
+	})
+
+	t.Run("exact mountpoint", func(t *testing.T) {
+		names, _ := list(t, "mountpoint=/mnt/nfs/share/")
+		assert.Equal(t, []string{"share"}, names)
+	})
+
+	t.Run("exact device", func(t *testing.T) {
+		names, filters := list(t, "device=/mnt/data/foo")
+		assert.Equal(t, []string{"media"}, names)
+		assert.Equal(t, "/mnt/data/foo", filters["device"])
+
+		// Volumes without a device option never match
+		names, _ = list(t, "device=/mnt/nfs/share")
+		assert.Empty(t, names)
+	})
+
+	t.Run("device prefix", func(t *testing.T) {
+		names, _ := list(t, "device=/mnt/data/*")
+		assert.Equal(t, []string{"media", "media-cache"}, names)
+	})
+
+	t.Run("invalid wildcard", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?device=/mnt/*/foo", nil)
+		handler.ListVolumes(c)
+		assert.Equal(t, 400, w.Code)
+	})
+}
@@ -0,0 +1,212 @@
+package volumes
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/scheduler"
+)
+
+// historyCSVHeader is the column order of the CSV history export
+var historyCSVHeader = []string{"timestamp", "volume_name", "size_bytes", "file_count", "scan_method", "duration_ms", "scan_accuracy"}
+
+// Series in the Prometheus history export
+const (
+	promSizeMetric      = "volumeviz_volume_size_bytes"
+	promFileCountMetric = "volumeviz_volume_file_count"
+)
+
+// ExportVolumeHistory streams a volume's full scan statistics time series,
+// oldest first, for offline analysis. format=csv (the default) writes one row
+// per scan; format=prometheus writes a Prometheus query_range matrix response
+// with size and file count series. since and until (RFC3339) bound the range.
+// Implements GET /api/v1/volumes/{name}/history/export?format=csv|prometheus&since=&until=
+func (h *Handler) ExportVolumeHistory(c *gin.Context) {
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "prometheus" {
+		apiutils.RespondWithBadRequest(c, "format must be csv or prometheus", nil)
+		return
+	}
+
+	since, until, err := parseHistoryRange(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	if h.database == nil {
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Size history requires a database", nil)
+		return
+	}
+	repo := scheduler.NewRepository(h.database)
+
+	// Long histories stream for longer than the server write timeout
+	apiutils.DisableWriteTimeout(c)
+	if format == "csv" {
+		h.streamHistoryCSV(c, repo, volumeName, since, until)
+	} else {
+		h.streamHistoryPrometheus(c, repo, volumeName, since, until)
+	}
+}
+
+// streamHistoryCSV writes one CSV row per scan. The header is only sent once
+// the query has started, so a failing query still gets a JSON error response.
+func (h *Handler) streamHistoryCSV(c *gin.Context, repo *scheduler.Repository, volumeName string, since, until time.Time) {
+	var writer *csv.Writer
+	start := func() {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-history.csv"`, volumeName))
+		c.Status(http.StatusOK)
+		writer = csv.NewWriter(c.Writer)
+		writer.Write(historyCSVHeader)
+	}
+
+	rows := 0
+	err := repo.WalkVolumeStats(c.Request.Context(), volumeName, since, until, func(stat *database.VolumeScanStats) error {
+		if writer == nil {
+			start()
+		}
+		rows++
+
+		fileCount := ""
+		if stat.FileCount != nil {
+			fileCount = strconv.Itoa(*stat.FileCount)
+		}
+		writer.Write([]string{
+			stat.Timestamp.UTC().Format(time.RFC3339),
+			stat.VolumeName,
+			strconv.FormatInt(stat.SizeBytes, 10),
+			fileCount,
+			stat.ScanMethod,
+			strconv.FormatInt(stat.DurationMs, 10),
+			stat.ScanAccuracy,
+		})
+		// Flush periodically so long exports reach the client as they are read
+		if rows%1000 == 0 {
+			writer.Flush()
+		}
+		return writer.Error()
+	})
+	if err != nil {
+		if writer == nil {
+			apiutils.RespondWithInternalError(c, "Failed to export size history", err)
+			return
+		}
+		log.Printf("[WARN] Size history export for %s aborted after %d rows: %v", volumeName, rows, err)
+		return
+	}
+
+	// Volumes never scanned in the range still get a valid CSV with a header
+	if writer == nil {
+		start()
+	}
+	writer.Flush()
+}
+
+// promSeriesHeader is the start of a matrix series, up to its values array
+type promSeriesHeader struct {
+	Metric map[string]string `json:"metric"`
+}
+
+// streamHistoryPrometheus writes a query_range compatible matrix. Each series
+// is streamed by its own pass over the rows, so no series is held in memory.
+// Nothing is written until the first query succeeds, so it can still fail with
+// a JSON error response.
+func (h *Handler) streamHistoryPrometheus(c *gin.Context, repo *scheduler.Repository, volumeName string, since, until time.Time) {
+	series := []struct {
+		metric string
+		value  func(*database.VolumeScanStats) (string, bool)
+	}{
+		{promSizeMetric, func(stat *database.VolumeScanStats) (string, bool) {
+			return strconv.FormatInt(stat.SizeBytes, 10), true
+		}},
+		{promFileCountMetric, func(stat *database.VolumeScanStats) (string, bool) {
+			if stat.FileCount == nil {
+				return "", false
+			}
+			return strconv.Itoa(*stat.FileCount), true
+		}},
+	}
+
+	started := false
+	write := func(s string) error {
+		if !started {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.Status(http.StatusOK)
+			c.Writer.WriteString(`{"status":"success","data":{"resultType":"matrix","result":[`)
+			started = true
+		}
+		_, err := c.Writer.WriteString(s)
+		return err
+	}
+
+	for i, s := range series {
+		header, _ := json.Marshal(promSeriesHeader{Metric: map[string]string{"__name__": s.metric, "volume": volumeName}})
+		pending := string(header[:len(header)-1]) + `,"values":[`
+		if i > 0 {
+			pending = "," + pending
+		}
+
+		points := 0
+		err := repo.WalkVolumeStats(c.Request.Context(), volumeName, since, until, func(stat *database.VolumeScanStats) error {
+			value, ok := s.value(stat)
+			if !ok {
+				return nil
+			}
+			if points > 0 {
+				pending = ","
+			}
+			points++
+			// Prometheus encodes sample values as strings to keep float precision
+			ts := strconv.FormatFloat(float64(stat.Timestamp.UnixMilli())/1000, 'f', -1, 64)
+			err := write(fmt.Sprintf(`%s[%s,"%s"]`, pending, ts, value))
+			pending = ""
+			return err
+		})
+		if err != nil {
+			if !started {
+				apiutils.RespondWithInternalError(c, "Failed to export size history", err)
+				return
+			}
+			log.Printf("[WARN] Prometheus size history export for %s aborted: %v", volumeName, err)
+			return
+		}
+
+		if err := write(pending + "]}"); err != nil {
+			return
+		}
+	}
+
+	write("]}}")
+}
+
+// parseHistoryRange parses the optional since/until RFC3339 bounds of an export
+func parseHistoryRange(c *gin.Context) (since, until time.Time, err error) {
+	if raw := c.Query("since"); raw != "" {
+		if since, err = time.Parse(time.RFC3339, raw); err != nil {
+			return since, until, fmt.Errorf("invalid since parameter: must be RFC3339")
+		}
+	}
+	if raw := c.Query("until"); raw != "" {
+		if until, err = time.Parse(time.RFC3339, raw); err != nil {
+			return since, until, fmt.Errorf("invalid until parameter: must be RFC3339")
+		}
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return since, until, fmt.Errorf("invalid time range: since must be before until")
+	}
+	return since, until, nil
+}
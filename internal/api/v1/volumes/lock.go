@@ -0,0 +1,129 @@
+package volumes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+const (
+	// maxScanLockTTL is the longest a scan lock may be taken for; longer jobs
+	// renew their lock, so a crashed one cannot keep a volume unscanned for long
+	maxScanLockTTL = 7 * 24 * time.Hour
+
+	// maxLockHolderLength is the longest holder name a lock accepts
+	maxLockHolderLength = 128
+)
+
+// LockVolume locks a volume against scanning for the requested TTL, for
+// backups and migrations writing it: the scheduler does not scan it until the
+// lock expires or is released. Locking a locked volume replaces its lock, which
+// is how long-running jobs renew theirs.
+// Implements POST /api/v1/volumes/:name/lock
+func (h *Handler) LockVolume(c *gin.Context) {
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+	if h.database == nil {
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Scan locks require a database", nil)
+		return
+	}
+
+	var req models.VolumeLockRequestV1
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apiutils.RespondWithBadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+	ttl, err := parseRetention(req.TTL)
+	if err != nil || ttl <= 0 || ttl > maxScanLockTTL {
+		apiutils.RespondWithBadRequest(c, fmt.Sprintf("ttl must be a positive duration of at most %v, such as \"2h\" or \"1d\"", maxScanLockTTL), map[string]interface{}{"ttl": req.TTL})
+		return
+	}
+	holder := strings.TrimSpace(req.Holder)
+	if len(holder) > maxLockHolderLength || strings.ContainsFunc(holder, unicode.IsControl) {
+		apiutils.RespondWithBadRequest(c, fmt.Sprintf("holder must be at most %d printable characters", maxLockHolderLength), nil)
+		return
+	}
+
+	lock := database.ScanLock{Until: time.Now().Add(ttl).Truncate(time.Second), Holder: holder}
+	if err := database.NewVolumeAnnotationRepository(h.database).LockScans(c.Request.Context(), volumeName, lock); err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to lock volume", err)
+		return
+	}
+
+	log.Printf("[INFO] Locked volume %s against scanning until %s (holder: %q)", volumeName, lock.Until.UTC().Format(time.RFC3339), holder)
+	c.JSON(http.StatusOK, volumeLockResponse(volumeName, &lock, time.Now()))
+}
+
+// UnlockVolume releases a volume's scan lock, so the scheduler scans it again
+// Implements DELETE /api/v1/volumes/:name/lock
+func (h *Handler) UnlockVolume(c *gin.Context) {
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+	if h.database == nil {
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Scan locks require a database", nil)
+		return
+	}
+
+	err := database.NewVolumeAnnotationRepository(h.database).UnlockScans(c.Request.Context(), volumeName)
+	if errors.Is(err, sql.ErrNoRows) {
+		apiutils.RespondWithNotFound(c, fmt.Sprintf("Volume %s is not locked", volumeName))
+		return
+	}
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to unlock volume", err)
+		return
+	}
+
+	log.Printf("[INFO] Released scan lock of volume %s", volumeName)
+	c.JSON(http.StatusOK, volumeLockResponse(volumeName, nil, time.Now()))
+}
+
+// GetVolumeLock returns the scan lock a volume holds; expired locks are
+// reported as unlocked
+// Implements GET /api/v1/volumes/:name/lock
+func (h *Handler) GetVolumeLock(c *gin.Context) {
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+	if h.database == nil {
+		c.JSON(http.StatusOK, volumeLockResponse(volumeName, nil, time.Now()))
+		return
+	}
+
+	now := time.Now()
+	lock, err := database.NewVolumeAnnotationRepository(h.database).ActiveScanLock(c.Request.Context(), volumeName, now)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to get scan lock", err)
+		return
+	}
+	c.JSON(http.StatusOK, volumeLockResponse(volumeName, lock, now))
+}
+
+// volumeLockResponse describes the lock held on a volume at now; nil means none
+func volumeLockResponse(volumeName string, lock *database.ScanLock, now time.Time) models.VolumeLockV1 {
+	response := models.VolumeLockV1{Name: volumeName}
+	if lock == nil || !lock.Active(now) {
+		return response
+	}
+	until := lock.Until.UTC()
+	response.Locked = true
+	response.Holder = lock.Holder
+	response.LockedUntil = &until
+	response.ExpiresInSeconds = int64(lock.Until.Sub(now).Round(time.Second).Seconds())
+	return response
+}
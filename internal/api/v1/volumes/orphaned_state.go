@@ -0,0 +1,70 @@
+package volumes
+
+import (
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/api/models"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// defaultDetachWindow is how long a volume whose last container went away is
+// reported as detaching rather than orphaned
+const defaultDetachWindow = 5 * time.Minute
+
+// orphanAttachments counts the containers that keep a volume from being
+// orphaned: all of them, or with countStopped off only those running,
+// restarting or paused, so a volume only stopped containers mount is orphaned
+func orphanAttachments(containers []coremodels.VolumeContainer, countStopped bool) int {
+	if countStopped {
+		return len(containers)
+	}
+	running := 0
+	for _, container := range containers {
+		switch container.State {
+		case "running", "restarting", "paused":
+			running++
+		}
+	}
+	return running
+}
+
+// countsStopped resolves whether stopped containers count as attachments for
+// a request, from its count_stopped parameter or else the handler's default
+func (h *Handler) countsStopped(param *bool) bool {
+	if param != nil {
+		return *param
+	}
+	return h.countStopped
+}
+
+// orphanedState classifies a volume by the containers mounting it. A volume is
+// attached while one of them runs and dormant when all of them are stopped.
+// One whose containers are being removed, or were seen within the detach
+// window, is detaching; only a volume without containers for longer is orphaned.
+func (h *Handler) orphanedState(vol coremodels.Volume, containers []coremodels.VolumeContainer) string {
+	if len(containers) == 0 {
+		if isContainerOwnedVolume(vol) && h.attachments.tearingDown(vol) {
+			return models.OrphanedStateDetaching
+		}
+		if h.detaches.recentlyAttached(vol.Name) {
+			return models.OrphanedStateDetaching
+		}
+		return models.OrphanedStateOrphaned
+	}
+	h.detaches.attached(vol.Name)
+
+	removing := true
+	for _, container := range containers {
+		switch container.State {
+		case "running", "restarting", "paused":
+			return models.OrphanedStateAttached
+		case "removing":
+		default:
+			removing = false
+		}
+	}
+	if removing {
+		return models.OrphanedStateDetaching
+	}
+	return models.OrphanedStateDormant
+}
@@ -0,0 +1,188 @@
+package volumes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+	"github.com/mantonx/volumeviz/internal/scheduler"
+	"github.com/mantonx/volumeviz/internal/utils"
+)
+
+const (
+	// overviewHistoryWindow is how far back the overview reports size history
+	overviewHistoryWindow = 30 * 24 * time.Hour
+
+	// Default and maximum number of history points in the overview
+	defaultOverviewHistoryLimit = 30
+	maxOverviewHistoryLimit     = 500
+)
+
+// GetVolumeOverview returns a volume's detail, latest scanned size, recent size
+// history, attachments and annotations in one response. The lookups run in
+// parallel; only a missing volume fails the request, other failures are
+// reported in the warnings and leave their section empty.
+// Implements GET /api/v1/volumes/{name}/overview
+func (h *Handler) GetVolumeOverview(c *gin.Context) {
+	ctx := c.Request.Context()
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+
+	historyLimit := defaultOverviewHistoryLimit
+	if raw := c.Query("history_limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxOverviewHistoryLimit {
+			apiutils.RespondWithBadRequest(c, fmt.Sprintf("history_limit must be between 1 and %d", maxOverviewHistoryLimit), nil)
+			return
+		}
+		historyLimit = limit
+	}
+
+	countStopped, err := apiutils.ParseCountStopped(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	var (
+		wg             sync.WaitGroup
+		volume         *coremodels.Volume
+		volumeErr      error
+		containers     []coremodels.VolumeContainer
+		containersErr  error
+		latest         *database.VolumeScanStats
+		latestErr      error
+		history        []database.VolumeMetrics
+		historyErr     error
+		annotations    map[string]string
+		annotationsErr error
+	)
+
+	lookup := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+
+	lookup(func() { volume, volumeErr = h.dockerService.GetVolume(ctx, volumeName) })
+	lookup(func() { containers, containersErr = h.dockerService.GetVolumeContainers(ctx, volumeName) })
+	if h.database != nil {
+		lookup(func() {
+			latest, latestErr = scheduler.NewRepository(h.database).GetLatestVolumeStats(ctx, volumeName)
+		})
+		lookup(func() {
+			now := time.Now()
+			history, historyErr = database.NewVolumeMetricsRepository(h.database).
+				GetMetrics(ctx, volumeName, now.Add(-overviewHistoryWindow), now, historyLimit)
+		})
+		lookup(func() {
+			annotations, annotationsErr = database.NewVolumeAnnotationRepository(h.database).GetAnnotations(ctx, volumeName)
+		})
+	}
+	wg.Wait()
+
+	if volumeErr != nil {
+		if utils.IsNotFound(volumeErr) {
+			apiutils.RespondWithNotFound(c, fmt.Sprintf("Volume '%s' not found", volumeName))
+			return
+		}
+		apiutils.RespondWithInternalError(c, "Failed to get volume", volumeErr)
+		return
+	}
+
+	var warnings []string
+	warn := func(section string, err error) {
+		warnings = append(warnings, fmt.Sprintf("%s unavailable: %v", section, err))
+	}
+
+	if containersErr != nil {
+		warn("attachments", containersErr)
+		containers = nil
+	}
+	if h.database == nil {
+		warnings = append(warnings, "latest size, history and annotations unavailable: no database configured")
+	}
+	if latestErr != nil {
+		warn("latest size", latestErr)
+		latest = nil
+	}
+	if historyErr != nil {
+		warn("history", historyErr)
+		history = nil
+	}
+	if annotationsErr != nil {
+		warn("annotations", annotationsErr)
+		annotations = nil
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+
+	samples := make([]models.SizeSampleV1, len(history))
+	for i, point := range history {
+		sizeBytes, fileCount := point.TotalSize, point.FileCount
+		samples[i] = models.SizeSampleV1{
+			SizeBytes: models.NewSizeBytes(&sizeBytes, asStrings),
+			FileCount: &fileCount,
+			Timestamp: point.MetricTimestamp,
+		}
+	}
+
+	// The newest of the last scheduled scan and the last history point
+	var latestSize *models.SizeSampleV1
+	if latest != nil {
+		sizeBytes := latest.SizeBytes
+		latestSize = &models.SizeSampleV1{
+			SizeBytes: models.NewSizeBytes(&sizeBytes, asStrings),
+			Method:    latest.ScanMethod,
+			Accuracy:  latest.ScanAccuracy,
+			Timestamp: latest.Timestamp,
+		}
+		if latest.FileCount != nil {
+			fileCount := int64(*latest.FileCount)
+			latestSize.FileCount = &fileCount
+		}
+	}
+	if len(samples) > 0 && (latestSize == nil || samples[0].Timestamp.After(latestSize.Timestamp)) {
+		latestSize = &samples[0]
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	detail := h.volumeDetail(c, *volume, containers, h.countsStopped(countStopped))
+	detail.ScanEnabled = database.ScanEnabled(annotations)
+	if latestSize != nil {
+		scannedAt := latestSize.Timestamp
+		detail.LastScanAt = &scannedAt
+	}
+
+	// Drift is judged against the last scheduled scan, as in the drift report
+	driftSize, _ := h.volumeSize(*volume)
+	var driftScannedAt *time.Time
+	if latest != nil {
+		driftSize, driftScannedAt = &latest.SizeBytes, &latest.Timestamp
+	}
+	detail.SizeDrift = sizeDriftFor(volumeName, annotations, driftSize, driftScannedAt, asStrings)
+
+	c.JSON(http.StatusOK, models.VolumeOverviewV1{
+		Volume:      detail,
+		LatestSize:  latestSize,
+		History:     samples,
+		Attachments: detail.Attachments,
+		Annotations: annotations,
+		Warnings:    warnings,
+	})
+}
@@ -0,0 +1,302 @@
+package volumes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// Probe defaults, overridden by SetProbeLimits
+const (
+	defaultProbeConcurrency = 8
+	defaultProbeTimeout     = 5 * time.Second
+)
+
+// networkFilesystems are the mount types of local-driver volumes backed by
+// network storage
+var networkFilesystems = map[string]bool{
+	"nfs":        true,
+	"nfs4":       true,
+	"cifs":       true,
+	"smb3":       true,
+	"smbfs":      true,
+	"glusterfs":  true,
+	"ceph":       true,
+	"9p":         true,
+	"sshfs":      true,
+	"fuse.sshfs": true,
+}
+
+// isNetworkVolume reports whether a volume is backed by network storage: a
+// local-driver volume mounting a network filesystem, or a volume plugin,
+// which is assumed to be remote
+func isNetworkVolume(vol coremodels.Volume) bool {
+	if vol.Driver != "" && vol.Driver != "local" {
+		return true
+	}
+	if networkFilesystems[strings.ToLower(vol.Options["type"])] {
+		return true
+	}
+	// NFS mounts carry the server in their options, e.g. addr=10.0.0.5,rw
+	return strings.Contains(vol.Options["o"], "addr=")
+}
+
+// errProbeInFlight reports a volume left unprobed because an earlier probe of
+// its mount has not returned
+var errProbeInFlight = errors.New("an earlier probe of this mount has not returned")
+
+// mountProbes bounds the filesystem calls of volume probes across requests.
+// A probe keeps its slot until its call returns, even after its request gave
+// up on it, so hung mounts count against the limit; a volume whose earlier
+// probe is still running is not probed again.
+type mountProbes struct {
+	slots    chan struct{}
+	mu       sync.Mutex
+	inFlight map[string]bool
+}
+
+// newMountProbes creates a tracker allowing limit probes at a time
+func newMountProbes(limit int) *mountProbes {
+	return &mountProbes{
+		slots:    make(chan struct{}, limit),
+		inFlight: make(map[string]bool),
+	}
+}
+
+// start reserves a probe of a volume, waiting for a free slot. It returns
+// errProbeInFlight while an earlier probe of the volume is running, or the
+// context's error if it ends first. A nil error must be paired with finish.
+func (p *mountProbes) start(ctx context.Context, volumeName string) error {
+	p.mu.Lock()
+	if p.inFlight[volumeName] {
+		p.mu.Unlock()
+		return errProbeInFlight
+	}
+	p.inFlight[volumeName] = true
+	p.mu.Unlock()
+
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		delete(p.inFlight, volumeName)
+		p.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// finish releases the slot and volume taken by start
+func (p *mountProbes) finish(volumeName string) {
+	<-p.slots
+	p.mu.Lock()
+	delete(p.inFlight, volumeName)
+	p.mu.Unlock()
+}
+
+// SetProbeLimits sets how many volume mounts are probed at a time and how
+// long each probe may take; values below one keep the defaults
+func (h *Handler) SetProbeLimits(concurrency int, timeout time.Duration) {
+	if concurrency > 0 {
+		h.probes = newMountProbes(concurrency)
+	}
+	if timeout > 0 {
+		h.probeTimeout = timeout
+	}
+}
+
+// ProbeVolumes checks that the mounts of network-backed volumes respond,
+// probing up to the configured number of volumes at a time. Each probe
+// opens the volume's directory and reads an entry from it, and is abandoned
+// after the probe timeout so a hung mount only fails its own volume.
+// Volumes whose abandoned probe has not returned yet are reported in_flight
+// rather than probed again. Requested volumes that do not exist are listed
+// under not_found.
+// Implements POST /api/v1/volumes/probe
+func (h *Handler) ProbeVolumes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// The body is optional; without one every network volume is probed
+	var req models.VolumeProbeRequestV1
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apiutils.RespondWithBadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.Volumes != nil {
+		names, ok := apiutils.BatchIDs(c, "volumes", req.Volumes)
+		if !ok {
+			return
+		}
+		req.Volumes = names
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+
+	selected, missing := selectForProbe(volumes, req)
+	report := models.VolumeProbeReportV1{
+		Volumes:     h.probeVolumes(ctx, selected),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	// An unreachable mount is a probe result, not a failure of the item
+	batch := apiutils.NewBatch()
+	for _, result := range report.Volumes {
+		if result.Reachable {
+			report.Reachable++
+		} else {
+			report.Unreachable++
+		}
+		if result.InFlight {
+			report.InFlight++
+		}
+		batch.Succeeded(result.Name, http.StatusOK)
+	}
+	for _, name := range missing {
+		batch.NotFound(name)
+	}
+	report.Total = len(report.Volumes)
+	report.BatchResultV1 = batch.Result()
+
+	apiutils.RespondWithBatch(c, batch, report)
+}
+
+// selectForProbe returns the volumes a probe request covers, sorted by name,
+// and any requested names that do not exist
+func selectForProbe(volumes []coremodels.Volume, req models.VolumeProbeRequestV1) ([]coremodels.Volume, []string) {
+	requested := make(map[string]bool, len(req.Volumes))
+	for _, name := range req.Volumes {
+		requested[name] = true
+	}
+
+	selected := make([]coremodels.Volume, 0)
+	for _, vol := range volumes {
+		if len(req.Volumes) > 0 {
+			if !requested[vol.Name] {
+				continue
+			}
+			delete(requested, vol.Name)
+		} else if !isNetworkVolume(vol) {
+			continue
+		}
+		if req.Driver != "" && vol.Driver != req.Driver {
+			continue
+		}
+		selected = append(selected, vol)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	missing := make([]string, 0, len(requested))
+	for name := range requested {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return selected, missing
+}
+
+// probeVolumes probes volumes within the handler's probe limit and returns
+// their results in the same order
+func (h *Handler) probeVolumes(ctx context.Context, volumes []coremodels.Volume) []models.VolumeProbeResultV1 {
+	results := make([]models.VolumeProbeResultV1, len(volumes))
+
+	var wg sync.WaitGroup
+	for i, vol := range volumes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.probeVolume(ctx, vol)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// probeVolume probes a single volume's mount within the probe timeout. A
+// filesystem call on a hung mount cannot be interrupted, so a probe that
+// times out is left to finish in the background, holding its slot and
+// keeping the volume in flight until it returns.
+func (h *Handler) probeVolume(ctx context.Context, vol coremodels.Volume) models.VolumeProbeResultV1 {
+	if err := h.probes.start(ctx, vol.Name); err != nil {
+		result := probeResult(vol, 0, err)
+		result.InFlight = errors.Is(err, errProbeInFlight)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.probeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer h.probes.finish(vol.Name)
+		// Resolving the path stats the device, which can hang on its own
+		path, reason := h.volumeRootPath(vol)
+		if reason != "" {
+			done <- errors.New(reason)
+			return
+		}
+		done <- h.probePath(path)
+	}()
+
+	select {
+	case err := <-done:
+		return probeResult(vol, time.Since(start), err)
+	case <-ctx.Done():
+		err := ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("mount did not respond within %s", h.probeTimeout)
+		}
+		return probeResult(vol, time.Since(start), err)
+	}
+}
+
+// probeResult describes the outcome of a probe that took latency
+func probeResult(vol coremodels.Volume, latency time.Duration, err error) models.VolumeProbeResultV1 {
+	result := models.VolumeProbeResultV1{
+		Name:      vol.Name,
+		Driver:    vol.Driver,
+		Type:      vol.Options["type"],
+		Reachable: err == nil,
+		LatencyMs: float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// probeMountPath checks that a mounted directory answers by reading an entry
+// from it, which reaches the storage behind a network mount
+func probeMountPath(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	if _, err := dir.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
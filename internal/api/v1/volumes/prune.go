@@ -0,0 +1,261 @@
+package volumes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/interfaces"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// retentionExpired is the retention value that makes an orphaned volume prunable right away
+const retentionExpired = "expired"
+
+// pruneCandidate is an orphaned volume considered by a prune policy
+type pruneCandidate struct {
+	volume        coremodels.Volume
+	metadata      map[string]string // Docker labels overlaid with annotations
+	orphanedSince time.Time
+}
+
+// PruneVolumes deletes the orphaned volumes selected by a label/annotation policy,
+// or only lists them on a dry run. System and pinned volumes are never selected.
+// Implements POST /api/v1/volumes/prune
+func (h *Handler) PruneVolumes(c *gin.Context) {
+	ctx := c.Request.Context()
+	if h.pruneConfirmErr != nil {
+		// Never prune unguarded when confirmation is required
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Prune confirmation is required but unavailable",
+			map[string]interface{}{"error": h.pruneConfirmErr.Error()})
+		return
+	}
+
+	var req models.PruneRequestV1
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apiutils.RespondWithBadRequest(c, "Invalid request body", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+
+	now := time.Now()
+	selected := make([]models.PrunedVolumeV1, 0)
+	var partial *models.PartialResultV1
+	for _, vol := range volumes {
+		if h.isSystemVolume(vol) {
+			continue
+		}
+
+		// Only orphaned volumes are pruned; skip any whose usage cannot be checked.
+		// Stopped containers always count, as Docker will not remove a volume
+		// any container still references. Once the budget runs out no further
+		// volume can be checked, so the selection stops there.
+		containers, err := h.dockerService.GetVolumeContainers(ctx, vol.ID)
+		if errors.Is(err, interfaces.ErrDockerCallBudgetExhausted) {
+			partial = &models.PartialResultV1{
+				Partial: true,
+				Warning: "The request reached its Docker API call budget; only the volumes checked before it ran out were considered",
+			}
+			break
+		}
+		if err != nil || !h.isOrphaned(vol, len(containers)) {
+			continue
+		}
+
+		candidate, err := h.pruneCandidate(ctx, vol)
+		if err != nil {
+			apiutils.RespondWithInternalError(c, "Failed to evaluate prune policy", err)
+			return
+		}
+
+		reason := selectForPrune(candidate, req.Match, now)
+		if reason == "" {
+			continue
+		}
+
+		sizeBytes, _ := h.volumeSize(vol)
+		selected = append(selected, models.PrunedVolumeV1{
+			Name:          vol.Name,
+			Driver:        vol.Driver,
+			SizeBytes:     models.NewSizeBytes(sizeBytes, middleware.SizesAsStrings(c)),
+			OrphanedSince: candidate.orphanedSince,
+			Reason:        reason,
+		})
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	response := models.PruneResponseV1{
+		DryRun:          req.DryRun,
+		Selected:        len(selected),
+		Volumes:         selected,
+		PartialResultV1: partial,
+	}
+
+	if h.pruneConfirmer != nil {
+		names := make([]string, len(selected))
+		for i, vol := range selected {
+			names[i] = vol.Name
+		}
+
+		if req.DryRun {
+			token, expiresAt := h.pruneConfirmer.issue(names)
+			response.ConfirmationToken = token
+			response.ConfirmationExpiresAt = &expiresAt
+		} else if !h.confirmPrune(c, req.ConfirmationToken, names) {
+			return
+		}
+	}
+
+	if !req.DryRun {
+		for i := range selected {
+			if err := h.dockerService.RemoveVolume(ctx, selected[i].Name); err != nil {
+				log.Printf("[WARN] Failed to prune volume %s: %v", selected[i].Name, err)
+				selected[i].Error = err.Error()
+				continue
+			}
+			log.Printf("[INFO] Pruned volume %s (%s)", selected[i].Name, selected[i].Reason)
+			response.Deleted++
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// confirmPrune checks the confirmation token of a prune against the volumes it
+// would delete, responding with an error and returning false when it does not match
+func (h *Handler) confirmPrune(c *gin.Context, token string, names []string) bool {
+	if token == "" {
+		apiutils.RespondWithBadRequest(c, "confirmation_token is required: run a dry run first and pass its token", nil)
+		return false
+	}
+
+	switch err := h.pruneConfirmer.verify(token, names); {
+	case err == nil:
+		return true
+	case errors.Is(err, errPruneTokenMalformed):
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+	default:
+		// Expired or drifted: the client must review a fresh dry run
+		log.Printf("[WARN] Refused prune of %d volumes: %v", len(names), err)
+		apiutils.RespondWithConflict(c, err.Error()+"; run a new dry run and review its selection", map[string]interface{}{
+			"selected": names,
+		})
+	}
+	return false
+}
+
+// pruneCandidate collects the labels, annotations and orphan time of a volume.
+// Without a database only Docker labels are known and a volume counts as
+// orphaned since it was created.
+func (h *Handler) pruneCandidate(ctx context.Context, vol coremodels.Volume) (pruneCandidate, error) {
+	candidate := pruneCandidate{
+		volume:        vol,
+		metadata:      make(map[string]string, len(vol.Labels)),
+		orphanedSince: vol.CreatedAt,
+	}
+	for key, value := range vol.Labels {
+		candidate.metadata[key] = value
+	}
+
+	if h.database == nil {
+		return candidate, nil
+	}
+
+	annotations, err := database.NewVolumeAnnotationRepository(h.database).GetAnnotations(ctx, vol.Name)
+	if err != nil {
+		return candidate, err
+	}
+	for key, value := range annotations {
+		candidate.metadata[key] = value
+	}
+
+	detachedAt, err := database.NewContainerRepository(h.database).GetVolumeLastDetachedAt(ctx, vol.Name)
+	if err != nil {
+		return candidate, fmt.Errorf("failed to get last detach time of volume %s: %w", vol.Name, err)
+	}
+	if detachedAt != nil && detachedAt.After(candidate.orphanedSince) {
+		candidate.orphanedSince = *detachedAt
+	}
+
+	return candidate, nil
+}
+
+// selectForPrune returns why a policy selects an orphaned volume, or "" to keep it.
+// A volume must carry every label or annotation in match. Its retention, when
+// set, must have passed; without one it is selected only by a non-empty match,
+// so an empty policy prunes nothing but volumes past their retention.
+func selectForPrune(candidate pruneCandidate, match map[string]string, now time.Time) string {
+	if pinned, _ := strconv.ParseBool(candidate.metadata[database.AnnotationKeyPinned]); pinned {
+		return ""
+	}
+
+	for key, value := range match {
+		if actual, ok := candidate.metadata[key]; !ok || actual != value {
+			return ""
+		}
+	}
+
+	retention, ok := candidate.metadata[database.AnnotationKeyRetention]
+	if !ok {
+		if len(match) == 0 {
+			return ""
+		}
+		return "matches policy"
+	}
+
+	if strings.EqualFold(retention, retentionExpired) {
+		return "retention expired"
+	}
+
+	keep, err := parseRetention(retention)
+	if err != nil {
+		log.Printf("[WARN] Ignoring volume %s with invalid retention %q: %v", candidate.volume.Name, retention, err)
+		return ""
+	}
+
+	orphanedFor := now.Sub(candidate.orphanedSince)
+	if orphanedFor < keep {
+		return ""
+	}
+	return fmt.Sprintf("orphaned for %s, past retention of %s", orphanedFor.Truncate(time.Second), retention)
+}
+
+// parseRetention parses a retention period: a Go duration ("72h") or a number of days ("7d")
+func parseRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", days)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("retention must not be negative")
+	}
+	return d, nil
+}
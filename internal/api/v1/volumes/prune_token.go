@@ -0,0 +1,74 @@
+package volumes
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errPruneTokenMalformed   = errors.New("malformed confirmation token")
+	errPruneTokenExpired     = errors.New("confirmation token expired")
+	errPruneSelectionChanged = errors.New("the volumes selected for pruning changed since the dry run")
+)
+
+// pruneConfirmer issues and checks the tokens that confirm a prune. A token is
+// bound to the exact set of volume names a dry run selected and expires after
+// ttl. Tokens are signed with a per-process key and do not survive a restart.
+type pruneConfirmer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+// newPruneConfirmer creates a confirmer with a random signing key
+func newPruneConfirmer(ttl time.Duration) (*pruneConfirmer, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &pruneConfirmer{key: key, ttl: ttl, now: time.Now}, nil
+}
+
+// issue returns a token confirming the given selection and when it expires
+func (p *pruneConfirmer) issue(names []string) (string, time.Time) {
+	expiresAt := p.now().Add(p.ttl).Truncate(time.Second)
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + "." + p.sign(expiry, names), expiresAt
+}
+
+// verify checks that a token was issued for exactly these names and has not expired
+func (p *pruneConfirmer) verify(token string, names []string) error {
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return errPruneTokenMalformed
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return errPruneTokenMalformed
+	}
+	if p.now().After(time.Unix(expiresAt, 0)) {
+		return errPruneTokenExpired
+	}
+	if !hmac.Equal([]byte(signature), []byte(p.sign(expiry, names))) {
+		return errPruneSelectionChanged
+	}
+	return nil
+}
+
+// sign returns the signature over an expiry and a sorted list of volume names
+func (p *pruneConfirmer) sign(expiry string, names []string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(expiry))
+	for _, name := range names {
+		// Volume names cannot contain NUL, so the encoding is unambiguous
+		mac.Write([]byte{0})
+		mac.Write([]byte(name))
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
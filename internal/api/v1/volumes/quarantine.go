@@ -0,0 +1,372 @@
+package volumes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+)
+
+// quarantinePolicy decides when orphaned volumes enter quarantine and when
+// quarantined volumes are deleted
+type quarantinePolicy struct {
+	after time.Duration // Orphan time quarantining volumes without a retention; zero never
+	grace time.Duration // Time in quarantine before deletion
+}
+
+// quarantineSweep lists the volumes one quarantine run changed
+type quarantineSweep struct {
+	quarantined []string
+	restored    []string // Mounted again while in quarantine
+	deleted     []string
+}
+
+// EnableQuarantine makes SweepQuarantine quarantine orphaned volumes past
+// their retention, or orphaned for after when they have none, and delete
+// volumes quarantined for longer than grace
+func (h *Handler) EnableQuarantine(after, grace time.Duration) {
+	h.quarantine = &quarantinePolicy{after: after, grace: grace}
+}
+
+// quarantinedVolumes returns when each quarantined volume entered quarantine.
+// A volume whose quarantine time cannot be read maps to the zero time: it
+// stays out of listings but is never deleted automatically.
+func (h *Handler) quarantinedVolumes(ctx context.Context) (map[string]time.Time, error) {
+	quarantined := make(map[string]time.Time)
+	if h.database == nil {
+		return quarantined, nil
+	}
+
+	annotations, err := database.NewVolumeAnnotationRepository(h.database).ListByKey(ctx, database.AnnotationKeyQuarantined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined volumes: %w", err)
+	}
+	for _, annotation := range annotations {
+		at, err := time.Parse(time.RFC3339, annotation.Value)
+		if err != nil {
+			log.Printf("[WARN] Volume %s has an invalid quarantine time %q; it will not be deleted automatically", annotation.VolumeName, annotation.Value)
+		}
+		quarantined[annotation.VolumeName] = at
+	}
+	return quarantined, nil
+}
+
+// withoutQuarantined drops quarantined volumes from a listing
+func (h *Handler) withoutQuarantined(ctx context.Context, volumes []coremodels.Volume) ([]coremodels.Volume, error) {
+	quarantined, err := h.quarantinedVolumes(ctx)
+	if err != nil || len(quarantined) == 0 {
+		return volumes, err
+	}
+
+	kept := make([]coremodels.Volume, 0, len(volumes))
+	for _, vol := range volumes {
+		if _, ok := quarantined[vol.Name]; !ok {
+			kept = append(kept, vol)
+		}
+	}
+	return kept, nil
+}
+
+// SweepQuarantine runs one quarantine pass at now: orphaned volumes selected
+// by the policy enter quarantine, quarantined volumes mounted again leave it,
+// and volumes quarantined for longer than the grace period are deleted.
+// System and pinned volumes are never quarantined, and a quarantined volume
+// pinned or given a retention that has not passed is restored, not deleted.
+func (h *Handler) SweepQuarantine(ctx context.Context, now time.Time) (*quarantineSweep, error) {
+	if h.quarantine == nil || h.database == nil {
+		return &quarantineSweep{}, nil
+	}
+
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	quarantined, err := h.quarantinedVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := database.NewVolumeAnnotationRepository(h.database)
+	sweep := &quarantineSweep{}
+	for _, vol := range volumes {
+		if h.isSystemVolume(vol) {
+			continue
+		}
+
+		// Skip any volume whose usage cannot be checked; like prunes, stopped
+		// containers always count
+		containers, err := h.dockerService.GetVolumeContainers(ctx, vol.ID)
+		if err != nil {
+			continue
+		}
+		orphaned := h.isOrphaned(vol, len(containers))
+
+		if quarantinedAt, ok := quarantined[vol.Name]; ok {
+			switch {
+			case !orphaned:
+				// Something still needs the volume
+				if err := h.restoreFromQuarantine(ctx, vol.Name, now); err != nil {
+					return sweep, err
+				}
+				log.Printf("[INFO] Restored volume %s from quarantine: it is mounted again", vol.Name)
+				sweep.restored = append(sweep.restored, vol.Name)
+			case !quarantinedAt.IsZero() && now.Sub(quarantinedAt) >= h.quarantine.grace:
+				// Pins and retentions may have been set during the grace period
+				candidate, err := h.pruneCandidate(ctx, vol)
+				if err != nil {
+					return sweep, err
+				}
+				if h.quarantine.selects(candidate, now) == "" {
+					if err := h.restoreFromQuarantine(ctx, vol.Name, now); err != nil {
+						return sweep, err
+					}
+					log.Printf("[INFO] Restored volume %s from quarantine: it is pinned or kept by its retention", vol.Name)
+					sweep.restored = append(sweep.restored, vol.Name)
+					continue
+				}
+				if err := h.dockerService.RemoveVolume(ctx, vol.Name); err != nil {
+					log.Printf("[WARN] Failed to delete quarantined volume %s: %v", vol.Name, err)
+					continue
+				}
+				for _, key := range []string{database.AnnotationKeyQuarantined, database.AnnotationKeyQuarantineRestored} {
+					if err := annotations.DeleteAnnotation(ctx, vol.Name, key); err != nil && !errors.Is(err, sql.ErrNoRows) {
+						log.Printf("[WARN] Failed to clear %s of deleted volume %s: %v", key, vol.Name, err)
+					}
+				}
+				log.Printf("[INFO] Deleted volume %s after %s in quarantine", vol.Name, now.Sub(quarantinedAt).Truncate(time.Second))
+				sweep.deleted = append(sweep.deleted, vol.Name)
+			}
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+
+		candidate, err := h.pruneCandidate(ctx, vol)
+		if err != nil {
+			return sweep, err
+		}
+		reason := h.quarantine.selects(candidate, now)
+		if reason == "" {
+			continue
+		}
+
+		if err := annotations.SetAnnotation(ctx, vol.Name, database.AnnotationKeyQuarantined, now.UTC().Format(time.RFC3339)); err != nil {
+			return sweep, fmt.Errorf("failed to quarantine volume %s: %w", vol.Name, err)
+		}
+		log.Printf("[INFO] Quarantined volume %s (%s)", vol.Name, reason)
+		sweep.quarantined = append(sweep.quarantined, vol.Name)
+	}
+
+	return sweep, nil
+}
+
+// selects returns why an orphaned volume enters quarantine, or "" to keep it.
+// A volume with a retention is quarantined once the retention has passed,
+// as prune would select it; one without is quarantined after the policy's
+// orphan time. Orphan time counts from the last restore from quarantine.
+func (p *quarantinePolicy) selects(candidate pruneCandidate, now time.Time) string {
+	if restored, err := time.Parse(time.RFC3339, candidate.metadata[database.AnnotationKeyQuarantineRestored]); err == nil && restored.After(candidate.orphanedSince) {
+		candidate.orphanedSince = restored
+	}
+
+	if _, ok := candidate.metadata[database.AnnotationKeyRetention]; ok {
+		return selectForPrune(candidate, nil, now)
+	}
+	if pinned, _ := strconv.ParseBool(candidate.metadata[database.AnnotationKeyPinned]); pinned || p.after <= 0 {
+		return ""
+	}
+
+	orphanedFor := now.Sub(candidate.orphanedSince)
+	if orphanedFor < p.after {
+		return ""
+	}
+	return fmt.Sprintf("orphaned for %s, past quarantine threshold of %s", orphanedFor.Truncate(time.Second), p.after)
+}
+
+// restoreFromQuarantine lifts the quarantine of a volume and restarts the
+// orphan time quarantine counts for it
+func (h *Handler) restoreFromQuarantine(ctx context.Context, volumeName string, now time.Time) error {
+	tx, err := h.database.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	annotations := database.NewVolumeAnnotationRepository(h.database).WithTx(tx)
+
+	if err := annotations.DeleteAnnotation(ctx, volumeName, database.AnnotationKeyQuarantined); err != nil {
+		return err
+	}
+	if err := annotations.SetAnnotation(ctx, volumeName, database.AnnotationKeyQuarantineRestored, now.UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetQuarantineReport lists the volumes in quarantine with when each will be
+// deleted, soonest first
+// Implements GET /api/v1/reports/quarantine
+func (h *Handler) GetQuarantineReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	cursor, err := apiutils.ParseReportCursor(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	quarantined, err := h.quarantinedVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list quarantined volumes", err)
+		return
+	}
+
+	// Annotations outlive their volumes; only report volumes that still exist
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+	report := models.QuarantineReportV1{
+		Volumes:     make([]models.QuarantinedVolumeV1, 0),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, vol := range volumes {
+		quarantinedAt, ok := quarantined[vol.Name]
+		if !ok {
+			continue
+		}
+
+		sizeBytes, _ := h.volumeSize(vol)
+		entry := models.QuarantinedVolumeV1{
+			Name:          vol.Name,
+			Driver:        vol.Driver,
+			SizeBytes:     models.NewSizeBytes(sizeBytes, asStrings),
+			QuarantinedAt: quarantinedAt,
+		}
+		if h.quarantine != nil && !quarantinedAt.IsZero() {
+			deleteAfter := quarantinedAt.Add(h.quarantine.grace)
+			entry.DeleteAfter = &deleteAfter
+		}
+		report.Volumes = append(report.Volumes, entry)
+	}
+	report.Total = len(report.Volumes)
+
+	sort.Slice(report.Volumes, func(i, j int) bool {
+		a, b := report.Volumes[i], report.Volumes[j]
+		if !a.QuarantinedAt.Equal(b.QuarantinedAt) {
+			return a.QuarantinedAt.Before(b.QuarantinedAt)
+		}
+		return a.Name < b.Name
+	})
+
+	remaining := report.Volumes[min(cursor, len(report.Volumes)):]
+	apiutils.RespondWithReport(c, cursor, len(remaining), func(n int, page *models.ReportPageV1) interface{} {
+		report.Volumes = remaining[:n]
+		report.ReportPageV1 = page
+		return report
+	})
+}
+
+// RestoreVolume returns a quarantined volume to normal listings and keeps it
+// from being quarantined again until it has been orphaned past the threshold
+// once more
+// Implements POST /api/v1/volumes/{name}/restore
+func (h *Handler) RestoreVolume(c *gin.Context) {
+	volumeName, ok := apiutils.ParseVolumeNameParam(c, "name")
+	if !ok {
+		return
+	}
+	if h.database == nil {
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Quarantine requires a database", nil)
+		return
+	}
+
+	now := time.Now()
+	err := h.restoreFromQuarantine(c.Request.Context(), volumeName, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		apiutils.RespondWithNotFound(c, fmt.Sprintf("Volume %s is not in quarantine", volumeName))
+		return
+	}
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to restore volume", err)
+		return
+	}
+
+	log.Printf("[INFO] Restored volume %s from quarantine", volumeName)
+	c.JSON(http.StatusOK, models.QuarantineRestoreResponseV1{Name: volumeName, RestoredAt: now.UTC()})
+}
+
+// QuarantineJob sweeps volumes for quarantine on an interval
+type QuarantineJob struct {
+	handler  *Handler
+	interval time.Duration
+
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// newQuarantineJob creates a job sweeping the handler's volumes every interval
+func newQuarantineJob(handler *Handler, interval time.Duration) *QuarantineJob {
+	return &QuarantineJob{
+		handler:  handler,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start sweeps immediately and then on every interval
+func (j *QuarantineJob) Start() {
+	if j.interval <= 0 {
+		close(j.doneCh)
+		return
+	}
+
+	go func() {
+		defer close(j.doneCh)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.Run()
+			select {
+			case <-ticker.C:
+			case <-j.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the job and waits for a sweep in progress to finish
+func (j *QuarantineJob) Stop() {
+	j.stopOnce.Do(func() { close(j.stopCh) })
+	<-j.doneCh
+}
+
+// Run sweeps volumes once
+func (j *QuarantineJob) Run() {
+	sweep, err := j.handler.SweepQuarantine(context.Background(), time.Now())
+	if err != nil {
+		log.Printf("[ERROR] Quarantine sweep failed: %v", err)
+		return
+	}
+	log.Printf("[DEBUG] Quarantine sweep: %d quarantined, %d restored, %d deleted",
+		len(sweep.quarantined), len(sweep.restored), len(sweep.deleted))
+}
@@ -1,7 +1,10 @@
 package volumes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/mantonx/volumeviz/internal/interfaces"
 	"github.com/mantonx/volumeviz/internal/websocket"
@@ -9,16 +12,97 @@ import (
 
 // Router handles volume-related routes
 type Router struct {
-	handler *Handler
+	handler      *Handler
+	operatorOnly gin.HandlerFunc
+	adminOnly    gin.HandlerFunc
 }
 
-// NewRouter creates a new volume router
-func NewRouter(dockerService interfaces.DockerService, hub *websocket.Hub, db *database.DB) *Router {
+// NewRouter creates a new volume router. operatorOnly guards scan locks and
+// adminOnly volume deletion; pass nil to leave them unguarded.
+func NewRouter(dockerService interfaces.DockerService, hub *websocket.Hub, db *database.DB, sizePolicy *config.SizePolicy, operatorOnly, adminOnly gin.HandlerFunc) *Router {
+	if operatorOnly == nil {
+		operatorOnly = func(c *gin.Context) { c.Next() }
+	}
+	if adminOnly == nil {
+		adminOnly = func(c *gin.Context) { c.Next() }
+	}
+
 	return &Router{
-		handler: NewHandler(dockerService, hub, db),
+		handler:      NewHandlerWithSizePolicy(dockerService, hub, db, sizePolicy),
+		operatorOnly: operatorOnly,
+		adminOnly:    adminOnly,
 	}
 }
 
+// RequirePruneConfirmation makes a prune present the token issued by a dry run
+// of the same selection within ttl
+func (r *Router) RequirePruneConfirmation(ttl time.Duration) error {
+	return r.handler.RequirePruneConfirmation(ttl)
+}
+
+// SetSizeStaleAfter sets the age at which listed sizes from scan stats are flagged stale
+func (r *Router) SetSizeStaleAfter(after time.Duration) {
+	r.handler.SetSizeStaleAfter(after)
+}
+
+// SetCountStopped sets whether stopped containers keep a volume from being
+// reported orphaned when a request leaves count_stopped unset
+func (r *Router) SetCountStopped(countStopped bool) {
+	r.handler.SetCountStopped(countStopped)
+}
+
+// SetAnonymousOrphanGrace sets how long an anonymous volume without containers
+// is treated as torn down with its container rather than orphaned
+func (r *Router) SetAnonymousOrphanGrace(grace time.Duration) {
+	r.handler.SetAnonymousOrphanGrace(grace)
+}
+
+// SetDetachWindow sets how long a volume whose last container went away is
+// reported as detaching rather than orphaned
+func (r *Router) SetDetachWindow(window time.Duration) {
+	r.handler.SetDetachWindow(window)
+}
+
+// SetInlineAttachments sets the most attachments listed in volume details
+func (r *Router) SetInlineAttachments(limit int) {
+	r.handler.SetInlineAttachments(limit)
+}
+
+// SetPathRewrites sets the rules mapping the host paths Docker reports to the
+// paths visible here, which browsing, probes and fingerprints read through
+func (r *Router) SetPathRewrites(rewrites []config.PathRewrite) {
+	r.handler.SetPathRewrites(rewrites)
+}
+
+// SetProbeLimits sets how many volume mounts are probed at a time and how long
+// each probe may take
+func (r *Router) SetProbeLimits(concurrency int, timeout time.Duration) {
+	r.handler.SetProbeLimits(concurrency, timeout)
+}
+
+// EnableQuarantine makes orphaned volumes selected for deletion spend grace in
+// quarantine first; volumes without a retention are selected after orphaned
+// for after, or never when it is zero
+func (r *Router) EnableQuarantine(after, grace time.Duration) {
+	r.handler.EnableQuarantine(after, grace)
+}
+
+// NewQuarantineJob creates a job sweeping volumes for quarantine every interval
+func (r *Router) NewQuarantineJob(interval time.Duration) *QuarantineJob {
+	return newQuarantineJob(r.handler, interval)
+}
+
+// EnableDuplicateDetection serves the duplicates report from fingerprints
+// hashing sampleFiles files of each volume and walking at most maxFiles
+func (r *Router) EnableDuplicateDetection(sampleFiles, maxFiles int) {
+	r.handler.EnableDuplicateDetection(sampleFiles, maxFiles)
+}
+
+// NewDuplicateJob creates a job fingerprinting volumes every interval
+func (r *Router) NewDuplicateJob(interval time.Duration) *DuplicateJob {
+	return newDuplicateJob(r.handler, interval)
+}
+
 // RegisterRoutes registers all volume-related routes
 func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 	// Volume endpoints
@@ -27,10 +111,36 @@ func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 		// List and filter volumes with pagination
 		volumes.GET("", r.handler.ListVolumes)
 
+		// Volumes created, updated and removed since a time, for incremental refreshes
+		volumes.GET("/changes", r.handler.GetVolumeChanges)
+
+		// Delete orphaned volumes selected by a label/annotation policy
+		volumes.POST("/prune", r.adminOnly, r.handler.PruneVolumes)
+
+		// Check that the mounts of network-backed volumes respond
+		volumes.POST("/probe", r.handler.ProbeVolumes)
+
+		// Return a quarantined volume to normal listings
+		volumes.POST("/:name/restore", r.adminOnly, r.handler.RestoreVolume)
+
 		// Volume operations (using name instead of id)
 		volumes.GET("/:name", r.handler.GetVolume)
 		volumes.GET("/:name/attachments", r.handler.GetVolumeAttachments)
 		volumes.GET("/:name/stats", r.handler.GetVolumeStats)
+
+		// Detail, latest size, history, attachments and annotations in one call
+		volumes.GET("/:name/overview", r.handler.GetVolumeOverview)
+
+		// Full scan statistics time series as CSV or a Prometheus matrix
+		volumes.GET("/:name/history/export", r.handler.ExportVolumeHistory)
+
+		// One directory level of the volume's files, for browsing without a scan
+		volumes.GET("/:name/ls", r.handler.ListVolumeDirectory)
+
+		// Temporary locks keeping the scheduler off volumes backups are writing
+		volumes.GET("/:name/lock", r.handler.GetVolumeLock)
+		volumes.POST("/:name/lock", r.operatorOnly, r.handler.LockVolume)
+		volumes.DELETE("/:name/lock", r.operatorOnly, r.handler.UnlockVolume)
 	}
 
 	// Reports endpoints
@@ -38,5 +148,23 @@ func (r *Router) RegisterRoutes(group *gin.RouterGroup) {
 	{
 		// Orphaned volumes report
 		reports.GET("/orphaned", r.handler.GetOrphanedVolumes)
+
+		// Quarantined volumes and when they will be deleted
+		reports.GET("/quarantine", r.handler.GetQuarantineReport)
+
+		// Volumes and their total size per Swarm node
+		reports.GET("/by-node", r.handler.GetVolumesByNode)
+
+		// Volumes and their total size per value of a label or annotation key
+		reports.GET("/by-label", r.handler.GetVolumesByLabel)
+
+		// Volumes, their total size and attachments per Docker Compose project
+		reports.GET("/by-project", r.handler.GetVolumesByProject)
+
+		// Volumes whose size is outside their expected range
+		reports.GET("/size-drift", r.handler.GetSizeDriftReport)
+
+		// Volumes that likely hold the same data, from sampled fingerprints
+		reports.GET("/duplicates", r.handler.GetDuplicatesReport)
 	}
 }
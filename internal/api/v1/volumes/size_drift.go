@@ -0,0 +1,255 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mantonx/volumeviz/internal/api/middleware"
+	"github.com/mantonx/volumeviz/internal/api/models"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/database"
+	coremodels "github.com/mantonx/volumeviz/internal/models"
+	"github.com/mantonx/volumeviz/internal/scheduler"
+)
+
+// defaultSizeTolerancePercent applies to volumes with an expected size but no tolerance
+const defaultSizeTolerancePercent = 10
+
+// byteSizeUnits are the unit suffixes accepted by parseByteSize, longest first
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// sizeExpectation is the allowed size range of a volume
+type sizeExpectation struct {
+	expected  int64
+	tolerance int64
+}
+
+// GetSizeDriftReport lists volumes whose latest scanned size is outside the
+// range set by their expected_size and size_tolerance annotations, catching
+// both runaway growth and unexpected shrinkage. With all=true every volume
+// with an expected size is listed, including those within range.
+// Implements GET /api/v1/reports/size-drift?all=
+func (h *Handler) GetSizeDriftReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	includeAll := c.DefaultQuery("all", "false") == "true"
+	cursor, err := apiutils.ParseReportCursor(c)
+	if err != nil {
+		apiutils.RespondWithBadRequest(c, err.Error(), nil)
+		return
+	}
+
+	if h.database == nil {
+		apiutils.RespondWithError(c, http.StatusServiceUnavailable, apiutils.ErrorCodeInternal, "Size drift requires a database", nil)
+		return
+	}
+
+	expected, err := database.NewVolumeAnnotationRepository(h.database).ListByKey(ctx, database.AnnotationKeyExpectedSize)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list expected sizes", err)
+		return
+	}
+
+	// Annotations outlive their volumes; only report volumes that still exist
+	volumes, err := h.dockerService.ListVolumes(ctx)
+	if err != nil {
+		apiutils.RespondWithInternalError(c, "Failed to list volumes", err)
+		return
+	}
+	existing := make(map[string]coremodels.Volume, len(volumes))
+	for _, vol := range volumes {
+		existing[vol.Name] = vol
+	}
+
+	asStrings := middleware.SizesAsStrings(c)
+	report := models.SizeDriftReportV1{
+		Volumes:     make([]models.SizeDriftVolumeV1, 0),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, annotation := range expected {
+		vol, ok := existing[annotation.VolumeName]
+		if !ok {
+			continue
+		}
+
+		drift, err := h.volumeSizeDrift(ctx, vol, asStrings)
+		if err != nil {
+			apiutils.RespondWithInternalError(c, "Failed to compute size drift", err)
+			return
+		}
+		if drift == nil {
+			continue
+		}
+
+		report.Expected++
+		outOfRange := drift.Status == models.SizeDriftOver || drift.Status == models.SizeDriftUnder
+		if outOfRange {
+			report.OutOfRange++
+		}
+		if outOfRange || includeAll {
+			report.Volumes = append(report.Volumes, models.SizeDriftVolumeV1{Name: vol.Name, SizeDriftV1: *drift})
+		}
+	}
+
+	sort.Slice(report.Volumes, func(i, j int) bool {
+		return report.Volumes[i].Name < report.Volumes[j].Name
+	})
+
+	// The counts cover every volume; the listing continues at the cursor
+	remaining := report.Volumes[min(cursor, len(report.Volumes)):]
+	apiutils.RespondWithReport(c, cursor, len(remaining), func(n int, page *models.ReportPageV1) interface{} {
+		report.Volumes = remaining[:n]
+		report.ReportPageV1 = page
+		return report
+	})
+}
+
+// volumeSizeDrift compares a volume's latest scanned size, or its Docker
+// reported size when it was never scanned, with its expected size. It returns
+// nil without a database or a valid expected_size annotation.
+func (h *Handler) volumeSizeDrift(ctx context.Context, vol coremodels.Volume, asStrings bool) (*models.SizeDriftV1, error) {
+	if h.database == nil {
+		return nil, nil
+	}
+
+	annotations, err := database.NewVolumeAnnotationRepository(h.database).GetAnnotations(ctx, vol.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := annotations[database.AnnotationKeyExpectedSize]; !ok {
+		return nil, nil
+	}
+
+	latest, err := scheduler.NewRepository(h.database).GetLatestVolumeStats(ctx, vol.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := h.volumeSize(vol)
+	var scannedAt *time.Time
+	if latest != nil {
+		actual, scannedAt = &latest.SizeBytes, &latest.Timestamp
+	}
+
+	return sizeDriftFor(vol.Name, annotations, actual, scannedAt, asStrings), nil
+}
+
+// sizeDriftFor computes the size drift of a volume from its annotations, or
+// nil when it has no valid expected size
+func sizeDriftFor(volumeName string, annotations map[string]string, actual *int64, scannedAt *time.Time, asStrings bool) *models.SizeDriftV1 {
+	expectation, ok, err := parseSizeExpectation(annotations)
+	if err != nil {
+		log.Printf("[WARN] Ignoring invalid expected size of volume %s: %v", volumeName, err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	return sizeDrift(expectation, actual, scannedAt, asStrings)
+}
+
+// sizeDrift compares a size with an expectation; a nil size is unknown
+func sizeDrift(expectation sizeExpectation, actual *int64, scannedAt *time.Time, asStrings bool) *models.SizeDriftV1 {
+	minSize := expectation.expected - expectation.tolerance
+	if minSize < 0 {
+		minSize = 0
+	}
+	maxSize := expectation.expected + expectation.tolerance
+
+	drift := &models.SizeDriftV1{
+		Status:            models.SizeDriftUnknown,
+		ExpectedSizeBytes: models.NewSizeBytes(&expectation.expected, asStrings),
+		MinSizeBytes:      models.NewSizeBytes(&minSize, asStrings),
+		MaxSizeBytes:      models.NewSizeBytes(&maxSize, asStrings),
+		ActualSizeBytes:   models.NewSizeBytes(actual, asStrings),
+		ScannedAt:         scannedAt,
+	}
+	if actual == nil {
+		return drift
+	}
+
+	deviation := *actual - expectation.expected
+	drift.DeviationBytes = models.NewSizeBytes(&deviation, asStrings)
+	if expectation.expected > 0 {
+		percent := math.Round(float64(deviation)/float64(expectation.expected)*10000) / 100
+		drift.DeviationPercent = &percent
+	}
+
+	switch {
+	case *actual > maxSize:
+		drift.Status = models.SizeDriftOver
+	case *actual < minSize:
+		drift.Status = models.SizeDriftUnder
+	default:
+		drift.Status = models.SizeDriftWithin
+	}
+	return drift
+}
+
+// parseSizeExpectation reads the expected_size and size_tolerance annotations.
+// ok is false when the volume has no expected size.
+func parseSizeExpectation(annotations map[string]string) (expectation sizeExpectation, ok bool, err error) {
+	rawExpected, ok := annotations[database.AnnotationKeyExpectedSize]
+	if !ok {
+		return expectation, false, nil
+	}
+	if expectation.expected, err = parseByteSize(rawExpected); err != nil {
+		return expectation, false, fmt.Errorf("invalid %s %q: %w", database.AnnotationKeyExpectedSize, rawExpected, err)
+	}
+
+	rawTolerance, ok := annotations[database.AnnotationKeySizeTolerance]
+	if !ok {
+		expectation.tolerance = expectation.expected * defaultSizeTolerancePercent / 100
+		return expectation, true, nil
+	}
+
+	rawTolerance = strings.TrimSpace(rawTolerance)
+	if percent, isPercent := strings.CutSuffix(rawTolerance, "%"); isPercent {
+		p, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || p < 0 || math.IsInf(p, 0) || math.IsNaN(p) {
+			return expectation, false, fmt.Errorf("invalid %s %q", database.AnnotationKeySizeTolerance, rawTolerance)
+		}
+		expectation.tolerance = int64(math.Round(float64(expectation.expected) * p / 100))
+	} else if expectation.tolerance, err = parseByteSize(rawTolerance); err != nil {
+		return expectation, false, fmt.Errorf("invalid %s %q: %w", database.AnnotationKeySizeTolerance, rawTolerance, err)
+	}
+
+	return expectation, true, nil
+}
+
+// parseByteSize parses a non-negative size in bytes, optionally with a decimal
+// (KB, MB, GB, TB) or binary (KiB, MiB, GiB, TiB) unit, e.g. "1.5GiB"
+func parseByteSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	multiplier := int64(1)
+	for _, unit := range byteSizeUnits {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, multiplier = strings.TrimSpace(number), unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 || math.IsNaN(n) {
+		return 0, fmt.Errorf("must be a non-negative size such as 1073741824, 500MB or 10GiB")
+	}
+	size := n * float64(multiplier)
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size too large")
+	}
+	return int64(size), nil
+}
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mantonx/volumeviz/internal/core/services/metrics"
 	"github.com/mantonx/volumeviz/internal/database"
 )
 
@@ -16,19 +19,67 @@ type Config struct {
 	Database  DatabaseConfig
 	CORS      CORSConfig
 	Auth      AuthConfig
+	Audit     AuditConfig
+	Metrics   MetricsConfig
 	Security  SecurityConfig
 	RateLimit RateLimitConfig
 	TLS       TLSConfig
 	Lifecycle LifecycleConfig
 	Events    EventsConfig
 	Scan      ScanConfig
+	Prune     PruneConfig
 }
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
-	Host string
+	Host string // IP, hostname or unix:///path/to.sock; the port is unused for sockets
 	Port string
 	Mode string
+
+	// SocketMode is the permission of the unix socket the server listens on
+	SocketMode os.FileMode
+
+	// SizeEncoding is the default JSON encoding for size_bytes ("number" or "string");
+	// clients can override it per request with the X-Size-Encoding header
+	SizeEncoding string
+
+	// WebSocketCompression negotiates permessage-deflate with WebSocket clients that offer it
+	WebSocketCompression bool
+	// WebSocketCompressionLevel is the flate level for compressed messages (-2 to 9)
+	WebSocketCompressionLevel int
+
+	// SlowRequestThreshold is the latency above which requests are logged as slow; zero disables it
+	SlowRequestThreshold time.Duration
+
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout bound how
+	// long a connection may take to send request headers, send the whole
+	// request, receive the response and sit idle between requests. Streaming
+	// responses and WebSockets manage their own write deadlines. Zero disables a timeout.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// HTTP2 serves HTTP/2 over TLS and, without TLS, to clients speaking h2c
+	// with prior knowledge; HTTP/1.1 is always served
+	HTTP2 bool
+
+	// ReportMaxItems and ReportMaxBytes cap the items and encoded size of a
+	// report response; truncated reports carry a cursor to continue. Zero disables a cap.
+	ReportMaxItems int
+	ReportMaxBytes int
+
+	// DockerCallBudget caps the Docker API calls one request may make; list
+	// requests over it return partial results. Zero disables the cap.
+	DockerCallBudget int
+
+	// InlineAttachmentsMax caps the attachments embedded in a volume detail;
+	// the rest are paged from the attachments endpoint. Zero embeds all.
+	InlineAttachmentsMax int
+
+	// ReadOnly rejects every API request that would change state, for demos
+	// and locked-down instances; reads work as usual
+	ReadOnly bool
 }
 
 // DockerConfig holds Docker-specific configuration
@@ -47,6 +98,21 @@ type DatabaseConfig struct {
 	Name     string
 	SSLMode  string
 	Path     string // SQLite database file path
+
+	// OptimizeInterval schedules automatic database optimization; zero disables it
+	OptimizeInterval time.Duration
+
+	// RollupInterval schedules refreshes of the daily and weekly metrics rollups
+	// served for coarse history ranges; zero disables them and serves raw metrics
+	RollupInterval time.Duration
+
+	// MigrationsDir holds custom migrations applied after the built-in ones;
+	// empty applies only the built-in migrations
+	MigrationsDir string
+
+	// EnableQueryStats creates the pg_stat_statements extension at startup so
+	// slow queries can be reported; needs a user allowed to create extensions
+	EnableQueryStats bool
 }
 
 // CORSConfig holds CORS-specific configuration
@@ -58,6 +124,72 @@ type CORSConfig struct {
 type AuthConfig struct {
 	Enabled bool
 	Secret  string
+
+	// Providers are the authenticators tried in order, see AuthProviders
+	Providers []string
+
+	// APIKeys are name:role:key entries accepted by the api_key provider
+	APIKeys []string
+
+	// AnonymousRole lets requests without credentials read with this role,
+	// only below AnonymousPaths when any are set; empty requires credentials
+	AnonymousRole  string
+	AnonymousPaths []string
+}
+
+// APIKey is a parsed AUTH_API_KEYS entry
+type APIKey struct {
+	Name string
+	Role string
+	Key  string
+}
+
+// ParseAPIKeys parses the name:role:key entries of APIKeys; the key is
+// everything after the second colon, so it may contain colons itself
+func (ac AuthConfig) ParseAPIKeys() ([]APIKey, error) {
+	keys := make([]APIKey, 0, len(ac.APIKeys))
+	for i, entry := range ac.APIKeys {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("entry %d is not name:role:key", i+1)
+		}
+		key := APIKey{Name: parts[0], Role: parts[1], Key: parts[2]}
+		if !slices.Contains(AuthRoles, key.Role) {
+			return nil, fmt.Errorf("key %s has role %q, want one of %s", key.Name, key.Role, strings.Join(AuthRoles, ", "))
+		}
+		if len(key.Key) < minAPIKeyLength {
+			return nil, fmt.Errorf("key %s is shorter than %d characters", key.Name, minAPIKeyLength)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// AuditConfig holds audit logging configuration
+type AuditConfig struct {
+	Enabled bool
+
+	// IncludeReads also records read-only (GET, HEAD, OPTIONS) requests
+	IncludeReads bool
+}
+
+// MetricsConfig controls the Prometheus /metrics endpoint, which the API's
+// authentication skips so scrapers need no JWT
+type MetricsConfig struct {
+	// AuthMode is none, bearer or basic; none leaves /metrics open
+	AuthMode string
+	Token    string // Bearer token, for AuthMode bearer
+	Username string // For AuthMode basic
+	Password string // For AuthMode basic
+
+	// VolumeLabels labels scan metrics with volume IDs and names, one series
+	// per volume; turning it off hides volume names from scrapers too
+	VolumeLabels bool
+	// DockerLabels lists Docker volume label keys exported as labels on the
+	// per-volume gauges; each distinct value is another series
+	DockerLabels []string
+	// DockerLabelMaxValues caps the distinct values exported per Docker label
+	DockerLabelMaxValues int
 }
 
 // SecurityConfig holds security headers configuration
@@ -69,6 +201,10 @@ type SecurityConfig struct {
 	FrameOptions          string
 	ReferrerPolicy        string
 	ContentSecurityPolicy string
+
+	// RedactKeys are extra connection string keys, beyond the password keys,
+	// whose values are masked in logs and error responses
+	RedactKeys []string
 }
 
 // RateLimitConfig holds rate limiting configuration
@@ -101,7 +237,63 @@ type EventsConfig struct {
 	QueueSize          int
 	BackoffMinDuration time.Duration
 	BackoffMaxDuration time.Duration
-	ReconcileInterval  time.Duration
+	// BackoffResetAfter is how long a stream must stay up before the
+	// reconnect backoff starts over from BackoffMinDuration
+	BackoffResetAfter time.Duration
+	ReconcileInterval time.Duration
+	// ReconcileIncrementalInterval is how often a cheap pass relists Docker
+	// and only syncs what changed since the last reconciliation, between the
+	// full passes of ReconcileInterval; 0 disables it
+	ReconcileIncrementalInterval time.Duration
+
+	// ReconcileBatchSize is the number of volumes written per bulk upsert statement
+	ReconcileBatchSize int
+	// ReconcileConcurrency is the number of upsert batches written in parallel
+	ReconcileConcurrency int
+	// ReconcileDryRun makes periodic reconciliation only log the changes it
+	// would make, so its effect on existing state can be checked first
+	ReconcileDryRun bool
+
+	// ScanOnCreate enqueues a scan of each volume created while events are watched
+	ScanOnCreate bool
+	// ScanOnCreateDelay is how long a new volume must exist before it is scanned;
+	// volumes removed within the delay are not scanned
+	ScanOnCreateDelay time.Duration
+
+	// HealthWindow is how long the events stream may go without an event or a
+	// reconnect before it is reported unhealthy; 0 disables the check
+	HealthWindow time.Duration
+}
+
+// PruneConfig holds volume pruning configuration
+type PruneConfig struct {
+	// ConfirmationRequired makes a prune present the token from a dry run that
+	// selected exactly the same volumes
+	ConfirmationRequired bool
+	// ConfirmationTTL is how long a dry-run confirmation token stays valid
+	ConfirmationTTL time.Duration
+	// AnonymousOrphanGrace is how long an anonymous volume that lost its
+	// container is assumed to be removed with it rather than orphaned
+	AnonymousOrphanGrace time.Duration
+	// DetachWindow is how long a volume whose last container went away is
+	// reported as detaching rather than orphaned
+	DetachWindow time.Duration
+	// OrphanCountStopped makes stopped containers keep the volumes they mount
+	// from being reported orphaned, unless a request sets count_stopped;
+	// prunes and quarantine always count them
+	OrphanCountStopped bool
+
+	// QuarantineEnabled periodically moves orphaned volumes past their
+	// retention, or past QuarantineAfter, into quarantine instead of deleting
+	// them; quarantined volumes are deleted once QuarantineGrace has passed
+	QuarantineEnabled bool
+	// QuarantineAfter quarantines orphaned volumes without a retention
+	// annotation after this long; zero quarantines only volumes with one
+	QuarantineAfter time.Duration
+	// QuarantineGrace is how long a volume stays in quarantine before deletion
+	QuarantineGrace time.Duration
+	// QuarantineInterval is how often volumes are checked for quarantine
+	QuarantineInterval time.Duration
 }
 
 // ScanConfig holds scan scheduler configuration
@@ -114,6 +306,122 @@ type ScanConfig struct {
 	BindMountsEnabled bool
 	BindAllowList     []string
 	SkipPattern       string
+
+	// PersistTimeout bounds each database write recording a scheduled scan,
+	// separately from TimeoutPerVolume; zero leaves the writes unbounded
+	PersistTimeout time.Duration
+
+	// FailureLogDetail controls scan failure logging: "full" logs the complete
+	// error chain, "code" logs only the classified error code
+	FailureLogDetail string
+
+	// SizeUnsupportedDrivers lists volume drivers that never report a usable size.
+	// Volumes on these drivers are not scanned and are listed without a size.
+	SizeUnsupportedDrivers []string
+
+	// MinVolumeInterval is the minimum time between scans of the same volume.
+	// Re-requests inside the window are skipped unless forced; zero disables it.
+	MinVolumeInterval time.Duration
+
+	// AutoBenchmark periodically times each scan method on sample volumes and
+	// prefers the fastest accurate method per filesystem type. Volumes on
+	// filesystems without a benchmark use MethodsOrder.
+	AutoBenchmark     bool
+	BenchmarkInterval time.Duration
+
+	// MethodLabel is the Docker volume label whose value overrides the scan method
+	// for that volume, e.g. volumeviz.scan.method=native; empty disables overrides
+	MethodLabel string
+
+	// ExternalSizeCommand sizes volumes that have no local mountpoint. It is run
+	// with the volume name as its last argument and must print the size in bytes.
+	// Without it, such volumes are reported as unscannable and never scanned.
+	ExternalSizeCommand string
+
+	// PathRewrites map the host paths Docker reports to the paths the server
+	// sees, as from=>to rules tried in order, see ParsePathRewrites
+	PathRewrites []string
+
+	// FilesystemNames name filesystems the scanner does not recognize by their
+	// statfs magic number, as magic=name entries, see ParseFilesystemNames
+	FilesystemNames []string
+
+	// SpecialFiles is how native scans treat sockets, FIFOs and device files:
+	// skip, count (counted separately) or include (sized like regular files)
+	SpecialFiles string
+	// ExcludeHidden leaves dot files and directories out of native scans
+	ExcludeHidden bool
+	// Symlinks is whether native scans follow symlinks: skip (count the link
+	// only), follow-within (targets inside the volume) or follow-all
+	Symlinks string
+
+	// MethodRecheckInterval is how long the availability of a scan method,
+	// such as whether its binary is on PATH, is cached; 0 checks every scan
+	MethodRecheckInterval time.Duration
+
+	// CacheHistory is how many recent scans of a volume decide how long its
+	// scan result stays cached: results of volumes whose size rarely changes
+	// are kept longer, volatile ones shorter. 0 caches by volume size alone.
+	CacheHistory int
+
+	// SizeChangeThreshold is the smallest change in bytes between two scans of a
+	// volume that is pushed to WebSocket clients as a size_changed message
+	SizeChangeThreshold int64
+
+	// OnStartup runs a full scan pass StartupDelay after the scheduler starts,
+	// instead of waiting a full Interval for the first pass
+	OnStartup    bool
+	StartupDelay time.Duration
+
+	// Jitter moves each scheduled pass by a random offset of up to this
+	// fraction of Interval either way, and spreads the pass's volumes over that
+	// fraction of Interval as they are enqueued; 0 disables it, and values
+	// above 0.5 are treated as 0.5
+	Jitter float64
+
+	// BatchWindow paces batch enqueues of all volumes: a volume is only
+	// enqueued while fewer than this many scans are queued, so a large batch
+	// keeps pace with the workers instead of filling the queue. Manual batches
+	// then carry on in the background. 0 enqueues a batch all at once.
+	BatchWindow int
+
+	// BatchOrder is the order a batch enqueues volumes in: "fair" puts the
+	// volumes whose last scan was quickest first, so a slow volume does not
+	// hold up quick ones queued behind it; "listed" keeps the listed order
+	BatchOrder string
+
+	// EmptyScansBeforeBackoff is how many consecutive scans must find a volume
+	// at most EmptyThresholdBytes before scheduled passes scan it less often:
+	// every second pass at first, doubling with each further empty scan, until
+	// it is scanned once per EmptyBackoffMax. A scan finding it larger ends the
+	// backoff. 0 scans empty volumes every pass.
+	EmptyScansBeforeBackoff int
+	EmptyThresholdBytes     int64
+	EmptyBackoffMax         time.Duration
+
+	// StaleAfter is the age at which sizes listed from scan stats are flagged
+	// as stale (size_stale); zero never flags them
+	StaleAfter time.Duration
+
+	// StatsBatchSize buffers the stats of completed scans and commits them this
+	// many at a time in one transaction, or every StatsFlushInterval if sooner;
+	// 1 or less inserts each scan's stats as it completes
+	StatsBatchSize     int
+	StatsFlushInterval time.Duration
+
+	// ProbeConcurrency is how many network volume mounts are probed at a time,
+	// and ProbeTimeout how long each probe may take before the mount is
+	// reported unreachable
+	ProbeConcurrency int
+	ProbeTimeout     time.Duration
+
+	// DuplicatesEnabled fingerprints volumes every DuplicatesInterval for the
+	// duplicates report, hashing the content of DuplicatesSampleFiles files of
+	// each volume and walking at most DuplicatesMaxFiles files
+	DuplicatesEnabled     bool
+	DuplicatesInterval    time.Duration
+	DuplicatesSampleFiles int
+	DuplicatesMaxFiles    int
 }
 
 // Load loads configuration from environment variables with defaults
@@ -123,6 +431,24 @@ func Load() *Config {
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Port: getEnv("SERVER_PORT", "8080"),
 			Mode: getEnv("GIN_MODE", "release"),
+
+			SocketMode: getFileModeEnv("SERVER_SOCKET_MODE", 0660),
+
+			SizeEncoding: getEnv("API_SIZE_ENCODING", "number"),
+
+			WebSocketCompression:      getBoolEnv("WEBSOCKET_COMPRESSION", true),
+			WebSocketCompressionLevel: getIntEnv("WEBSOCKET_COMPRESSION_LEVEL", 1),
+			SlowRequestThreshold:      getDurationEnv("HTTP_SLOW_REQUEST_THRESHOLD", time.Second),
+			ReadHeaderTimeout:         getDurationEnv("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+			ReadTimeout:               getDurationEnv("HTTP_READ_TIMEOUT", 30*time.Second),
+			WriteTimeout:              getDurationEnv("HTTP_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:               getDurationEnv("HTTP_IDLE_TIMEOUT", 120*time.Second),
+			HTTP2:                     getBoolEnv("HTTP2_ENABLED", true),
+			ReportMaxItems:            getIntEnv("API_REPORT_MAX_ITEMS", 5000),
+			ReportMaxBytes:            getIntEnv("API_REPORT_MAX_BYTES", 8<<20),
+			DockerCallBudget:          getIntEnv("API_DOCKER_CALL_BUDGET", 10000),
+			InlineAttachmentsMax:      getIntEnv("API_INLINE_ATTACHMENTS_MAX", 25),
+			ReadOnly:                  getBoolEnv("READ_ONLY", false),
 		},
 		Docker: DockerConfig{
 			Host:    getEnv("DOCKER_HOST", ""),
@@ -137,6 +463,11 @@ func Load() *Config {
 			Name:     getEnv("DB_NAME", "volumeviz"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 			Path:     getEnv("DB_PATH", "./volumeviz.db"),
+
+			OptimizeInterval: getDurationEnv("DB_OPTIMIZE_INTERVAL", 0),
+			RollupInterval:   getDurationEnv("DB_ROLLUP_INTERVAL", time.Hour),
+			MigrationsDir:    getEnv("MIGRATIONS_DIR", ""),
+			EnableQueryStats: getBoolEnv("DB_ENABLE_QUERY_STATS", false),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: getStringSliceEnv("ALLOW_ORIGINS", []string{"http://localhost:3000"}),
@@ -144,6 +475,26 @@ func Load() *Config {
 		Auth: AuthConfig{
 			Enabled: getBoolEnv("AUTH_ENABLED", false),
 			Secret:  getEnv("AUTH_HS256_SECRET", ""),
+
+			Providers: getStringSliceEnv("AUTH_PROVIDERS", []string{"jwt"}),
+			APIKeys:   getStringSliceEnv("AUTH_API_KEYS", nil),
+
+			AnonymousRole:  getEnv("AUTH_ANONYMOUS_ROLE", ""),
+			AnonymousPaths: getStringSliceEnv("AUTH_ANONYMOUS_PATHS", nil),
+		},
+		Audit: AuditConfig{
+			Enabled:      getBoolEnv("AUDIT_ENABLED", true),
+			IncludeReads: getBoolEnv("AUDIT_INCLUDE_READS", false),
+		},
+		Metrics: MetricsConfig{
+			AuthMode:     strings.ToLower(getEnv("METRICS_AUTH", "none")),
+			Token:        getEnv("METRICS_TOKEN", ""),
+			Username:     getEnv("METRICS_USERNAME", ""),
+			Password:     getEnv("METRICS_PASSWORD", ""),
+			VolumeLabels: getBoolEnv("METRICS_VOLUME_LABELS", true),
+			DockerLabels: getStringSliceEnv("METRICS_DOCKER_LABELS", nil),
+
+			DockerLabelMaxValues: getIntEnv("METRICS_DOCKER_LABEL_MAX_VALUES", metrics.DefaultDockerLabelMaxValues),
 		},
 		Security: SecurityConfig{
 			HideServerHeader:      getBoolEnv("SECURITY_HIDE_SERVER", true),
@@ -153,6 +504,7 @@ func Load() *Config {
 			FrameOptions:          getEnv("SECURITY_FRAME_OPTIONS", "SAMEORIGIN"),
 			ReferrerPolicy:        getEnv("SECURITY_REFERRER_POLICY", "no-referrer"),
 			ContentSecurityPolicy: getEnv("SECURITY_CSP", "default-src 'none'; frame-ancestors 'self';"),
+			RedactKeys:            getStringSliceEnv("SECURITY_REDACT_KEYS", []string{}),
 		},
 		RateLimit: RateLimitConfig{
 			Enabled: getBoolEnv("RATE_LIMIT_ENABLED", true),
@@ -182,7 +534,18 @@ func Load() *Config {
 			QueueSize:          getIntEnv("EVENTS_QUEUE_SIZE", 1000),
 			BackoffMinDuration: getDurationEnv("EVENTS_BACKOFF_MIN", 1*time.Second),
 			BackoffMaxDuration: getDurationEnv("EVENTS_BACKOFF_MAX", 30*time.Second),
+			BackoffResetAfter:  getDurationEnv("EVENTS_BACKOFF_RESET", time.Minute),
 			ReconcileInterval:  getDurationEnv("EVENTS_RECONCILE_INTERVAL", 6*time.Hour),
+
+			ReconcileIncrementalInterval: getDurationEnv("EVENTS_RECONCILE_INCREMENTAL_INTERVAL", 0),
+			ReconcileBatchSize:           getIntEnv("EVENTS_RECONCILE_BATCH_SIZE", 500),
+			ReconcileConcurrency:         getIntEnv("EVENTS_RECONCILE_CONCURRENCY", 1),
+			ReconcileDryRun:              getBoolEnv("EVENTS_RECONCILE_DRY_RUN", false),
+
+			ScanOnCreate:      getBoolEnv("EVENTS_SCAN_ON_CREATE", false),
+			ScanOnCreateDelay: getDurationEnv("EVENTS_SCAN_ON_CREATE_DELAY", 30*time.Second),
+
+			HealthWindow: getDurationEnv("EVENTS_HEALTH_WINDOW", time.Hour),
 		},
 		Scan: ScanConfig{
 			Enabled:           getScanEnabledDefault(),
@@ -193,6 +556,72 @@ func Load() *Config {
 			BindMountsEnabled: getBoolEnv("SCAN_BIND_MOUNTS_ENABLED", false),
 			BindAllowList:     getStringSliceEnv("SCAN_BIND_ALLOWLIST", []string{}),
 			SkipPattern:       getEnv("SCAN_SKIP_PATTERN", "^docker_|^builder_|^containerd"),
+
+			PersistTimeout: getDurationEnv("SCAN_PERSIST_TIMEOUT", 10*time.Second),
+
+			FailureLogDetail: getEnv("SCAN_FAILURE_LOG_DETAIL", "full"),
+
+			SizeUnsupportedDrivers: getStringSliceEnv("SCAN_SIZE_UNSUPPORTED_DRIVERS", []string{}),
+
+			MinVolumeInterval: getDurationEnv("SCAN_MIN_VOLUME_INTERVAL", 30*time.Second),
+
+			AutoBenchmark:     getBoolEnv("SCAN_AUTO_BENCHMARK", false),
+			BenchmarkInterval: getDurationEnv("SCAN_BENCHMARK_INTERVAL", 24*time.Hour),
+
+			MethodLabel: getEnv("SCAN_METHOD_LABEL", "volumeviz.scan.method"),
+
+			ExternalSizeCommand: getEnv("SCAN_EXTERNAL_SIZE_COMMAND", ""),
+
+			PathRewrites: getStringSliceEnv("SCAN_PATH_REWRITES", nil),
+
+			FilesystemNames: getStringSliceEnv("SCAN_FILESYSTEM_NAMES", nil),
+
+			SpecialFiles:  getEnv("SCAN_SPECIAL_FILES", "skip"),
+			ExcludeHidden: getBoolEnv("SCAN_EXCLUDE_HIDDEN", false),
+			Symlinks:      getEnv("SCAN_SYMLINKS", "skip"),
+
+			MethodRecheckInterval: getDurationEnv("SCAN_METHOD_RECHECK_INTERVAL", time.Minute),
+
+			CacheHistory: getIntEnv("SCAN_CACHE_HISTORY", 10),
+
+			SizeChangeThreshold: int64(getIntEnv("SCAN_SIZE_CHANGE_THRESHOLD", 1024*1024)),
+
+			OnStartup:    getBoolEnv("SCAN_ON_STARTUP", false),
+			StartupDelay: getDurationEnv("SCAN_STARTUP_DELAY", 30*time.Second),
+
+			Jitter: getFloatEnv("SCAN_JITTER", 0.1),
+
+			BatchWindow: getIntEnv("SCAN_BATCH_WINDOW", 0),
+			BatchOrder:  getEnv("SCAN_BATCH_ORDER", "fair"),
+
+			EmptyScansBeforeBackoff: getIntEnv("SCAN_EMPTY_BACKOFF_AFTER", 3),
+			EmptyThresholdBytes:     int64(getIntEnv("SCAN_EMPTY_THRESHOLD_BYTES", 4096)),
+			EmptyBackoffMax:         getDurationEnv("SCAN_EMPTY_BACKOFF_MAX", 24*time.Hour),
+
+			StaleAfter: getDurationEnv("SCAN_STALE_AFTER", 24*time.Hour),
+
+			StatsBatchSize:     getIntEnv("SCAN_STATS_BATCH_SIZE", 1),
+			StatsFlushInterval: getDurationEnv("SCAN_STATS_FLUSH_INTERVAL", 5*time.Second),
+
+			ProbeConcurrency: getIntEnv("SCAN_PROBE_CONCURRENCY", 8),
+			ProbeTimeout:     getDurationEnv("SCAN_PROBE_TIMEOUT", 5*time.Second),
+
+			DuplicatesEnabled:     getBoolEnv("SCAN_DUPLICATES_ENABLED", false),
+			DuplicatesInterval:    getDurationEnv("SCAN_DUPLICATES_INTERVAL", 24*time.Hour),
+			DuplicatesSampleFiles: getIntEnv("SCAN_DUPLICATES_SAMPLE_FILES", 32),
+			DuplicatesMaxFiles:    getIntEnv("SCAN_DUPLICATES_MAX_FILES", 100000),
+		},
+		Prune: PruneConfig{
+			ConfirmationRequired: getBoolEnv("PRUNE_CONFIRMATION_REQUIRED", true),
+			ConfirmationTTL:      getDurationEnv("PRUNE_CONFIRMATION_TTL", 5*time.Minute),
+			AnonymousOrphanGrace: getDurationEnv("PRUNE_ANONYMOUS_ORPHAN_GRACE", time.Minute),
+			DetachWindow:         getDurationEnv("PRUNE_DETACH_WINDOW", 5*time.Minute),
+			OrphanCountStopped:   getBoolEnv("ORPHAN_COUNT_STOPPED", true),
+
+			QuarantineEnabled:  getBoolEnv("PRUNE_QUARANTINE_ENABLED", false),
+			QuarantineAfter:    getDurationEnv("PRUNE_QUARANTINE_AFTER", 0),
+			QuarantineGrace:    getDurationEnv("PRUNE_QUARANTINE_GRACE", 7*24*time.Hour),
+			QuarantineInterval: getDurationEnv("PRUNE_QUARANTINE_INTERVAL", time.Hour),
 		},
 	}
 }
@@ -247,6 +676,26 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getFloatEnv gets float environment variable with default value
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getFileModeEnv gets octal file mode environment variable (e.g. "0660") with default value
+func getFileModeEnv(key string, defaultValue os.FileMode) os.FileMode {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseUint(value, 8, 32); err == nil && parsed <= 0777 {
+			return os.FileMode(parsed)
+		}
+	}
+	return defaultValue
+}
+
 // getScanEnabledDefault returns the default value for scan enabled based on environment
 func getScanEnabledDefault() bool {
 	// Check for explicit setting first
@@ -262,6 +711,39 @@ func getScanEnabledDefault() bool {
 	return isDev
 }
 
+// String describes the database configuration with the password masked
+func (dc DatabaseConfig) String() string {
+	password := ""
+	if dc.Password != "" {
+		password = database.RedactedValue
+	}
+	return fmt.Sprintf("{Type:%s Host:%s Port:%s User:%s Password:%s Name:%s SSLMode:%s Path:%s OptimizeInterval:%v RollupInterval:%v}",
+		dc.Type, dc.Host, dc.Port, dc.User, password, dc.Name, dc.SSLMode, dc.Path, dc.OptimizeInterval, dc.RollupInterval)
+}
+
+// String describes the authentication configuration with the secret masked
+func (ac AuthConfig) String() string {
+	secret := ""
+	if ac.Secret != "" {
+		secret = database.RedactedValue
+	}
+	return fmt.Sprintf("{Enabled:%t Secret:%s Providers:%v APIKeys:%d AnonymousRole:%s AnonymousPaths:%v}",
+		ac.Enabled, secret, ac.Providers, len(ac.APIKeys), ac.AnonymousRole, ac.AnonymousPaths)
+}
+
+// String describes the metrics configuration with the credentials masked
+func (mc MetricsConfig) String() string {
+	token, password := "", ""
+	if mc.Token != "" {
+		token = database.RedactedValue
+	}
+	if mc.Password != "" {
+		password = database.RedactedValue
+	}
+	return fmt.Sprintf("{AuthMode:%s Token:%s Username:%s Password:%s VolumeLabels:%t}",
+		mc.AuthMode, token, mc.Username, password, mc.VolumeLabels)
+}
+
 // ToDatabaseConfig converts the config.DatabaseConfig to database.Config
 func (dc *DatabaseConfig) ToDatabaseConfig() *database.Config {
 	var dbType database.DatabaseType
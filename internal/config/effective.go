@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// secretFields are the settings masked in the effective configuration, by
+// section and field name
+var secretFields = map[string]bool{
+	"Database.Password": true,
+	"Auth.Secret":       true,
+	"Auth.APIKeys":      true,
+	"Metrics.Token":     true,
+	"Metrics.Password":  true,
+}
+
+// Effective returns the configuration as sections of snake_case settings for
+// display, with durations and file modes in their usual notation and secrets
+// masked. It walks the config structs, so new settings are listed as added.
+func (c *Config) Effective() map[string]map[string]interface{} {
+	sections := make(map[string]map[string]interface{})
+	config := reflect.ValueOf(c).Elem()
+	for i := 0; i < config.NumField(); i++ {
+		section := config.Type().Field(i)
+		fields := config.Field(i)
+
+		settings := make(map[string]interface{}, fields.NumField())
+		for j := 0; j < fields.NumField(); j++ {
+			field := fields.Type().Field(j)
+			if !field.IsExported() {
+				continue
+			}
+			value := effectiveValue(fields.Field(j))
+			if secretFields[section.Name+"."+field.Name] && !isEmpty(fields.Field(j)) {
+				value = database.RedactedValue
+			}
+			settings[snakeCase(field.Name)] = value
+		}
+		sections[snakeCase(section.Name)] = settings
+	}
+	return sections
+}
+
+// isEmpty reports whether a setting is unset, counting empty lists as unset
+func isEmpty(value reflect.Value) bool {
+	if value.Kind() == reflect.Slice {
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// effectiveValue converts a setting to the value it is displayed as
+func effectiveValue(value reflect.Value) interface{} {
+	switch v := value.Interface().(type) {
+	case time.Duration:
+		return v.String()
+	case os.FileMode:
+		return fmt.Sprintf("%04o", uint32(v.Perm()))
+	case []string:
+		if v == nil {
+			return []string{}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// snakeCase converts a Go field name such as ReadHeaderTimeout or
+// MetricsTTLDays to read_header_timeout or metrics_ttl_days
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filesystemNamePattern is what a filesystem name may look like, so it is
+// safe to use as a metric label value
+var filesystemNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ParseFilesystemNames parses magic=name entries naming filesystems by the
+// magic number statfs reports for them, e.g. 0x65735546=fuse. The magic is
+// hexadecimal after 0x, else decimal. Blank entries are ignored.
+func ParseFilesystemNames(entries []string) (map[uint32]string, error) {
+	names := make(map[uint32]string, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rawMagic, name, ok := strings.Cut(entry, "=")
+		rawMagic, name = strings.TrimSpace(rawMagic), strings.TrimSpace(name)
+		if !ok || rawMagic == "" || name == "" {
+			return nil, fmt.Errorf("entry %q is not magic=name", entry)
+		}
+		magic, err := strconv.ParseUint(rawMagic, 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: magic must be a 32-bit number such as 0x65735546", entry)
+		}
+		if !filesystemNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("entry %q: name may only contain letters, digits, '_', '.' and '-'", entry)
+		}
+		if previous, exists := names[uint32(magic)]; exists && previous != name {
+			return nil, fmt.Errorf("magic 0x%x is named both %q and %q", magic, previous, name)
+		}
+		names[uint32(magic)] = name
+	}
+	return names, nil
+}
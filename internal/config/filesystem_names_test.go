@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilesystemNames(t *testing.T) {
+	names, err := ParseFilesystemNames([]string{
+		" 0x65735546 = fuse ",
+		"",
+		"13364=nilfs",
+		"0x65735546=fuse",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[uint32]string{0x65735546: "fuse", 0x3434: "nilfs"}, names)
+
+	for _, entry := range []string{"fuse", "0x65735546=", "=fuse", "fuse=0x65735546", "0x1ffffffff=big", "0x65735546=fuse fs"} {
+		_, err := ParseFilesystemNames([]string{entry})
+		assert.Error(t, err, entry)
+	}
+
+	_, err = ParseFilesystemNames([]string{"0x65735546=fuse", "0x65735546=sshfs"})
+	assert.EqualError(t, err, `magic 0x65735546 is named both "fuse" and "sshfs"`)
+}
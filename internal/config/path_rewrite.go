@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mantonx/volumeviz/internal/utils"
+)
+
+// pathRewriteRegexPrefix marks a SCAN_PATH_REWRITES rule whose match is a regular expression
+const pathRewriteRegexPrefix = "re:"
+
+// PathRewrite maps a path Docker reports on the host to the path the server
+// sees, e.g. /var/lib/docker/volumes=>/host/volumes when the host's volume
+// directory is mounted into the container at /host/volumes
+type PathRewrite struct {
+	Rule        string         // The rule as configured
+	Prefix      string         // Path prefix replaced, matched on whole path elements; empty for regex rules
+	Pattern     *utils.Pattern // Regular expression replaced, for rules starting with re:
+	Replacement string         // May reference the pattern's groups as $1 or ${name}
+}
+
+// Apply rewrites path, reporting whether the rule matched it
+func (r PathRewrite) Apply(path string) (string, bool) {
+	if r.Pattern != nil {
+		if !r.Pattern.MatchString(path) {
+			return path, false
+		}
+		return r.Pattern.ReplaceAllString(path, r.Replacement), true
+	}
+
+	rest, ok := strings.CutPrefix(path, r.Prefix)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/") && !strings.HasSuffix(r.Prefix, "/")) {
+		return path, false
+	}
+	return r.Replacement + rest, true
+}
+
+// ParsePathRewrites parses from=>to rules, where from is a path prefix or,
+// after re:, a regular expression. Blank entries are ignored.
+func ParsePathRewrites(rules []string) ([]PathRewrite, error) {
+	rewrites := make([]PathRewrite, 0, len(rules))
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(rule, "=>")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || from == pathRewriteRegexPrefix {
+			return nil, fmt.Errorf("rule %q is not from=>to", rule)
+		}
+
+		rewrite := PathRewrite{Rule: rule, Replacement: to}
+		if pattern, isRegex := strings.CutPrefix(from, pathRewriteRegexPrefix); isRegex {
+			compiled, err := utils.CompilePattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", rule, err)
+			}
+			rewrite.Pattern = compiled
+		} else {
+			if !strings.HasPrefix(from, "/") {
+				return nil, fmt.Errorf("rule %q must rewrite an absolute path", rule)
+			}
+			rewrite.Prefix = from
+		}
+		rewrites = append(rewrites, rewrite)
+	}
+	return rewrites, nil
+}
+
+// RewritePath applies the first rule matching path, so earlier rules take
+// precedence. It returns the path unchanged and a nil rule when none matches.
+func RewritePath(rewrites []PathRewrite, path string) (string, *PathRewrite) {
+	for i := range rewrites {
+		if rewritten, ok := rewrites[i].Apply(path); ok {
+			return rewritten, &rewrites[i]
+		}
+	}
+	return path, nil
+}
+
+// VolumeRoot is the local directory holding a volume's files
+type VolumeRoot struct {
+	Path    string
+	Device  bool         // Path is the volume's device option rather than its mountpoint
+	Rewrite *PathRewrite // Rule that rewrote Path; nil when none matched
+}
+
+// ResolveVolumeRoot returns where the files of a volume with the given device
+// option and mountpoint are found here, each path mapped by the first matching
+// rewrite: the device path of bind-style volumes when that is an accessible
+// directory, otherwise the mountpoint. reason explains why a volume has none.
+func ResolveVolumeRoot(rewrites []PathRewrite, device, mountpoint string) (root VolumeRoot, reason string) {
+	if device != "" {
+		path, rule := RewritePath(rewrites, device)
+		if filepath.IsAbs(path) {
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				return VolumeRoot{Path: path, Device: true, Rewrite: rule}, ""
+			}
+		}
+	}
+	if reason := UnresolvedMountpointReason(mountpoint); reason != "" {
+		return VolumeRoot{}, reason
+	}
+	path, rule := RewritePath(rewrites, mountpoint)
+	return VolumeRoot{Path: path, Rewrite: rule}, ""
+}
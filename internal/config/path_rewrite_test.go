@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePathRewrites(t *testing.T) {
+	rewrites, err := ParsePathRewrites([]string{
+		" /var/lib/docker/volumes => /host/volumes ",
+		"",
+		`re:^/mnt/disk(\d+)/=>/disks/$1/`,
+	})
+	require.NoError(t, err)
+	require.Len(t, rewrites, 2)
+	assert.Equal(t, "/var/lib/docker/volumes", rewrites[0].Prefix)
+	assert.Equal(t, "/host/volumes", rewrites[0].Replacement)
+	assert.NotNil(t, rewrites[1].Pattern)
+
+	for _, rule := range []string{"/var/lib/docker", "=>/host", "re:=>/host", "docker/volumes=>/host", "re:((=>/host"} {
+		_, err := ParsePathRewrites([]string{rule})
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestRewritePath_Precedence(t *testing.T) {
+	rewrites, err := ParsePathRewrites([]string{
+		"/var/lib/docker/volumes/media=>/media",
+		`re:^/var/lib/docker/volumes/([^/]+)/_data$=>/volumes/$1`,
+		"/var/lib/docker=>/host/docker",
+		"/mnt/=>/host/mnt/",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path string
+		want string
+		rule int // Index of the rule applied, -1 for none
+	}{
+		// The first matching rule wins, even when later rules match too
+		{"/var/lib/docker/volumes/media/_data", "/media/_data", 0},
+		{"/var/lib/docker/volumes/app/_data", "/volumes/app", 1},
+		{"/var/lib/docker/volumes/app/_data/sub", "/host/docker/volumes/app/_data/sub", 2},
+		{"/var/lib/docker", "/host/docker", 2},
+		{"/mnt/nas/share", "/host/mnt/nas/share", 3},
+		// Prefixes match whole path elements only
+		{"/var/lib/docker-old/volumes", "/var/lib/docker-old/volumes", -1},
+		{"/var/lib/docker/volumes/mediaserver/_data", "/volumes/mediaserver", 1},
+		{"/srv/data", "/srv/data", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, rule := RewritePath(rewrites, tt.path)
+			assert.Equal(t, tt.want, got)
+			if tt.rule < 0 {
+				assert.Nil(t, rule)
+			} else {
+				assert.Same(t, &rewrites[tt.rule], rule)
+			}
+		})
+	}
+}
+
+func TestResolveVolumeRoot(t *testing.T) {
+	host := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(host, "volumes", "app", "_data"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(host, "srv", "bind"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(host, "disk.img"), nil, 0o644))
+
+	rewrites, err := ParsePathRewrites([]string{
+		"/var/lib/docker=>" + host,
+		"/srv=>" + filepath.Join(host, "srv"),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		device     string
+		mountpoint string
+		want       string
+		fromDevice bool
+		rule       int // Index of the rule applied, -1 for none
+		reason     string
+	}{
+		{"mountpoint", "", "/var/lib/docker/volumes/app/_data", filepath.Join(host, "volumes", "app", "_data"), false, 0, ""},
+		{"bind device", "/srv/bind", "/var/lib/docker/volumes/app/_data", filepath.Join(host, "srv", "bind"), true, 1, ""},
+		{"device used as is", filepath.Join(host, "srv", "bind"), "/var/lib/docker/volumes/app/_data", filepath.Join(host, "srv", "bind"), true, -1, ""},
+		// Devices that are not local directories fall back to the mountpoint
+		{"missing device", "/srv/gone", "/var/lib/docker/volumes/app/_data", filepath.Join(host, "volumes", "app", "_data"), false, 0, ""},
+		{"file device", filepath.Join(host, "disk.img"), "/var/lib/docker/volumes/app/_data", filepath.Join(host, "volumes", "app", "_data"), false, 0, ""},
+		{"nfs device", ":/export/media", "/data/media", "/data/media", false, -1, ""},
+		{"no mountpoint", ":/export/media", "", "", false, -1, "driver reported no mountpoint"},
+		{"remote mountpoint", "", "nfs://server/export", "", false, -1, `mountpoint "nfs://server/export" is not a local path`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, reason := ResolveVolumeRoot(rewrites, tt.device, tt.mountpoint)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.want, root.Path)
+			assert.Equal(t, tt.fromDevice, root.Device)
+			if tt.rule < 0 {
+				assert.Nil(t, root.Rewrite)
+			} else {
+				assert.Same(t, &rewrites[tt.rule], root.Rewrite)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SizePolicy decides whether volumes on a given driver can be sized.
+// A nil policy treats every driver as supported.
+type SizePolicy struct {
+	unsupported    map[string]bool
+	externalSizing bool // An external size command can size volumes without a local path
+}
+
+// NewSizePolicy creates a size policy from a list of size-unsupported drivers.
+// Driver names are matched case-insensitively; blank entries are ignored.
+func NewSizePolicy(unsupportedDrivers []string) *SizePolicy {
+	unsupported := make(map[string]bool, len(unsupportedDrivers))
+	for _, driver := range unsupportedDrivers {
+		driver = strings.ToLower(strings.TrimSpace(driver))
+		if driver != "" {
+			unsupported[driver] = true
+		}
+	}
+	return &SizePolicy{unsupported: unsupported}
+}
+
+// SizePolicy returns the size policy for the configured unsupported drivers
+// and external size command
+func (sc *ScanConfig) SizePolicy() *SizePolicy {
+	policy := NewSizePolicy(sc.SizeUnsupportedDrivers)
+	policy.externalSizing = strings.TrimSpace(sc.ExternalSizeCommand) != ""
+	return policy
+}
+
+// SizeSupported reports whether volumes on the driver report a usable size
+func (p *SizePolicy) SizeSupported(driver string) bool {
+	if p == nil || len(p.unsupported) == 0 {
+		return true
+	}
+	return !p.unsupported[strings.ToLower(strings.TrimSpace(driver))]
+}
+
+// HasUnsupportedDrivers reports whether any driver is configured as size-unsupported
+func (p *SizePolicy) HasUnsupportedDrivers() bool {
+	return p != nil && len(p.unsupported) > 0
+}
+
+// Scannable reports whether a volume can be scanned, with the reason when it
+// cannot. Volumes without a local path are only scannable through an external
+// size command.
+func (p *SizePolicy) Scannable(mountpoint string, options map[string]string) (bool, string) {
+	if p != nil && p.externalSizing {
+		return true, ""
+	}
+	if isLocalPath(options["device"]) {
+		return true, ""
+	}
+	if reason := UnresolvedMountpointReason(mountpoint); reason != "" {
+		return false, reason
+	}
+	return true, ""
+}
+
+// UnresolvedMountpointReason explains why a mountpoint is not a local path that
+// can be scanned, or returns "" when it is
+func UnresolvedMountpointReason(mountpoint string) string {
+	mountpoint = strings.TrimSpace(mountpoint)
+	if mountpoint == "" {
+		return "driver reported no mountpoint"
+	}
+	if !isLocalPath(mountpoint) {
+		return fmt.Sprintf("mountpoint %q is not a local path", mountpoint)
+	}
+	return ""
+}
+
+// isLocalPath reports whether path is an absolute local filesystem path rather
+// than empty, relative or a remote location such as nfs://host/export
+func isLocalPath(path string) bool {
+	return filepath.IsAbs(path) && !strings.Contains(path, "://")
+}
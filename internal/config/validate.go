@@ -0,0 +1,362 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/services/metrics"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/utils"
+)
+
+// ScanMethods are the full-scan methods SCAN_METHODS_ORDER may list
+var ScanMethods = []string{"diskus", "du", "native"}
+
+// AuthProviders are the authenticators AUTH_PROVIDERS may list: HS256 JWTs in
+// the Authorization header and AUTH_API_KEYS keys in the X-API-Key header
+var AuthProviders = []string{"jwt", "api_key"}
+
+// AuthRoles are the roles API keys and anonymous requests may act with
+var AuthRoles = []string{"viewer", "operator", "admin"}
+
+// minAPIKeyLength keeps API keys too long to guess
+const minAPIKeyLength = 16
+
+// maxMetricsDockerLabels bounds METRICS_DOCKER_LABELS, since every promoted
+// label multiplies the series a volume can export
+const maxMetricsDockerLabels = 10
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problem(s): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// validator collects problems so they can be reported together
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) positive(name string, d time.Duration) {
+	if d <= 0 {
+		v.addf("%s must be positive, got %v", name, d)
+	}
+}
+
+func (v *validator) nonNegative(name string, d time.Duration) {
+	if d < 0 {
+		v.addf("%s must not be negative, got %v", name, d)
+	}
+}
+
+func (v *validator) atLeast(name string, value, least int) {
+	if value < least {
+		v.addf("%s must be at least %d, got %d", name, least, value)
+	}
+}
+
+func (v *validator) oneOf(name, value string, allowed ...string) {
+	for _, option := range allowed {
+		if value == option {
+			return
+		}
+	}
+	v.addf("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) port(name, value string) {
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		v.addf("%s must be a port between 1 and 65535, got %q", name, value)
+	}
+}
+
+// Validate checks the whole configuration and returns a *ValidationError
+// listing every problem, named by environment variable, or nil if it is usable
+func (c *Config) Validate() error {
+	v := &validator{}
+	c.Server.validate(v)
+	c.Docker.validate(v)
+	c.Database.validate(v)
+	c.Auth.validate(v)
+	c.Metrics.validate(v)
+	c.TLS.validate(v)
+	c.RateLimit.validate(v)
+	c.Lifecycle.validate(v)
+	c.Events.validate(v)
+	c.Scan.validate(v)
+	c.Prune.validate(v)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (sc *ServerConfig) validate(v *validator) {
+	v.oneOf("GIN_MODE", sc.Mode, "debug", "release", "test")
+	if !strings.HasPrefix(sc.Host, "unix://") {
+		v.port("SERVER_PORT", sc.Port)
+	} else if strings.TrimPrefix(sc.Host, "unix://") == "" {
+		v.addf("SERVER_HOST must name a socket path after unix://")
+	}
+	v.oneOf("API_SIZE_ENCODING", sc.SizeEncoding, "number", "string")
+	if sc.WebSocketCompressionLevel < -2 || sc.WebSocketCompressionLevel > 9 {
+		v.addf("WEBSOCKET_COMPRESSION_LEVEL must be between -2 and 9, got %d", sc.WebSocketCompressionLevel)
+	}
+	v.nonNegative("HTTP_SLOW_REQUEST_THRESHOLD", sc.SlowRequestThreshold)
+	v.nonNegative("HTTP_READ_HEADER_TIMEOUT", sc.ReadHeaderTimeout)
+	v.nonNegative("HTTP_READ_TIMEOUT", sc.ReadTimeout)
+	v.nonNegative("HTTP_WRITE_TIMEOUT", sc.WriteTimeout)
+	v.nonNegative("HTTP_IDLE_TIMEOUT", sc.IdleTimeout)
+	v.atLeast("API_REPORT_MAX_ITEMS", sc.ReportMaxItems, 0)
+	v.atLeast("API_REPORT_MAX_BYTES", sc.ReportMaxBytes, 0)
+	v.atLeast("API_DOCKER_CALL_BUDGET", sc.DockerCallBudget, 0)
+	v.atLeast("API_INLINE_ATTACHMENTS_MAX", sc.InlineAttachmentsMax, 0)
+	// The detail links the rest as one page of the attachments endpoint
+	if sc.InlineAttachmentsMax > maxInlineAttachments {
+		v.addf("API_INLINE_ATTACHMENTS_MAX must be at most %d, the largest attachments page, got %d", maxInlineAttachments, sc.InlineAttachmentsMax)
+	}
+}
+
+// maxInlineAttachments is the largest page_size the API serves, which the
+// attachments link of a volume detail asks for
+const maxInlineAttachments = 200
+
+func (dc *DockerConfig) validate(v *validator) {
+	v.positive("DOCKER_TIMEOUT", dc.Timeout)
+}
+
+func (dc *DatabaseConfig) validate(v *validator) {
+	switch dc.Type {
+	case "postgres":
+		if dc.Host == "" {
+			v.addf("DB_HOST is required for DB_TYPE postgres")
+		}
+		v.port("DB_PORT", dc.Port)
+		if dc.User == "" {
+			v.addf("DB_USER is required for DB_TYPE postgres")
+		}
+		if dc.Name == "" {
+			v.addf("DB_NAME is required for DB_TYPE postgres")
+		}
+		v.oneOf("DB_SSLMODE", dc.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	case "sqlite":
+		if dc.Path == "" {
+			v.addf("DB_PATH is required for DB_TYPE sqlite")
+		}
+		if dc.EnableQueryStats {
+			v.addf("DB_ENABLE_QUERY_STATS requires DB_TYPE postgres")
+		}
+	default:
+		v.addf("DB_TYPE must be one of postgres, sqlite, got %q", dc.Type)
+	}
+	v.nonNegative("DB_OPTIMIZE_INTERVAL", dc.OptimizeInterval)
+	v.nonNegative("DB_ROLLUP_INTERVAL", dc.RollupInterval)
+}
+
+func (ac *AuthConfig) validate(v *validator) {
+	if !ac.Enabled {
+		return
+	}
+
+	if len(ac.Providers) == 0 {
+		v.addf("AUTH_PROVIDERS must list at least one provider")
+	}
+	seen := make(map[string]bool, len(ac.Providers))
+	for _, provider := range ac.Providers {
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if seen[provider] {
+			v.addf("AUTH_PROVIDERS lists %q more than once", provider)
+		}
+		seen[provider] = true
+		v.oneOf("AUTH_PROVIDERS", provider, AuthProviders...)
+	}
+	if seen["jwt"] && ac.Secret == "" {
+		v.addf("AUTH_HS256_SECRET is required when AUTH_ENABLED is true and AUTH_PROVIDERS lists jwt")
+	}
+	if seen["api_key"] {
+		if keys, err := ac.ParseAPIKeys(); err != nil {
+			v.addf("AUTH_API_KEYS is invalid: %v", err)
+		} else if len(keys) == 0 {
+			v.addf("AUTH_API_KEYS must list at least one key when AUTH_PROVIDERS lists api_key")
+		}
+	}
+	if ac.AnonymousRole != "" {
+		v.oneOf("AUTH_ANONYMOUS_ROLE", ac.AnonymousRole, AuthRoles...)
+	}
+}
+
+func (mc *MetricsConfig) validate(v *validator) {
+	v.oneOf("METRICS_AUTH", mc.AuthMode, "none", "bearer", "basic")
+	switch mc.AuthMode {
+	case "bearer":
+		if len(mc.Token) < minAPIKeyLength {
+			v.addf("METRICS_TOKEN must be at least %d characters when METRICS_AUTH is bearer", minAPIKeyLength)
+		}
+	case "basic":
+		if mc.Username == "" || mc.Password == "" {
+			v.addf("METRICS_USERNAME and METRICS_PASSWORD are required when METRICS_AUTH is basic")
+		}
+	}
+
+	v.atLeast("METRICS_DOCKER_LABEL_MAX_VALUES", mc.DockerLabelMaxValues, 1)
+	if len(mc.DockerLabels) == 0 {
+		return
+	}
+	if !mc.VolumeLabels {
+		v.addf("METRICS_DOCKER_LABELS requires METRICS_VOLUME_LABELS, which exports the per-volume gauges they label")
+	}
+	if len(mc.DockerLabels) > maxMetricsDockerLabels {
+		v.addf("METRICS_DOCKER_LABELS lists %d labels, at most %d are allowed", len(mc.DockerLabels), maxMetricsDockerLabels)
+	}
+	names := make(map[string]string, len(mc.DockerLabels))
+	for _, key := range mc.DockerLabels {
+		if key == "" {
+			v.addf("METRICS_DOCKER_LABELS must not contain empty label keys")
+			continue
+		}
+		name := metrics.DockerLabelName(key)
+		if previous, exists := names[name]; exists {
+			v.addf("METRICS_DOCKER_LABELS keys %q and %q both export as %s", previous, key, name)
+			continue
+		}
+		names[name] = key
+	}
+}
+
+func (tc *TLSConfig) validate(v *validator) {
+	if (tc.CertFile == "") != (tc.KeyFile == "") {
+		v.addf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+}
+
+func (rc *RateLimitConfig) validate(v *validator) {
+	if rc.Enabled {
+		v.atLeast("RATE_LIMIT_RPM", rc.RPM, 1)
+		v.atLeast("RATE_LIMIT_BURST", rc.Burst, 1)
+	}
+}
+
+func (lc *LifecycleConfig) validate(v *validator) {
+	if !lc.Enabled {
+		return
+	}
+	v.positive("LIFECYCLE_INTERVAL", lc.Interval)
+	v.nonNegative("LIFECYCLE_INITIAL_DELAY", lc.InitialDelay)
+	v.atLeast("VOLUME_METRICS_TTL_DAYS", lc.MetricsTTLDays, 0)
+	v.atLeast("VOLUME_SIZES_TTL_DAYS", lc.SizesTTLDays, 0)
+}
+
+func (ec *EventsConfig) validate(v *validator) {
+	if !ec.Enabled {
+		return
+	}
+	v.atLeast("EVENTS_QUEUE_SIZE", ec.QueueSize, 1)
+	v.positive("EVENTS_BACKOFF_MIN", ec.BackoffMinDuration)
+	if ec.BackoffMaxDuration < ec.BackoffMinDuration {
+		v.addf("EVENTS_BACKOFF_MAX (%v) must not be below EVENTS_BACKOFF_MIN (%v)", ec.BackoffMaxDuration, ec.BackoffMinDuration)
+	}
+	v.positive("EVENTS_BACKOFF_RESET", ec.BackoffResetAfter)
+	v.nonNegative("EVENTS_RECONCILE_INTERVAL", ec.ReconcileInterval)
+	v.nonNegative("EVENTS_RECONCILE_INCREMENTAL_INTERVAL", ec.ReconcileIncrementalInterval)
+	if ec.ReconcileIncrementalInterval > 0 && ec.ReconcileIncrementalInterval >= ec.ReconcileInterval {
+		v.addf("EVENTS_RECONCILE_INCREMENTAL_INTERVAL (%v) must be below EVENTS_RECONCILE_INTERVAL (%v)", ec.ReconcileIncrementalInterval, ec.ReconcileInterval)
+	}
+	v.atLeast("EVENTS_RECONCILE_BATCH_SIZE", ec.ReconcileBatchSize, 1)
+	// Each batch is one multi-row statement, bounded by SQLite's parameter limit
+	if ec.ReconcileBatchSize > database.MaxBulkVolumeUpsert {
+		v.addf("EVENTS_RECONCILE_BATCH_SIZE must be at most %d, the most volumes one statement can write, got %d", database.MaxBulkVolumeUpsert, ec.ReconcileBatchSize)
+	}
+	v.atLeast("EVENTS_RECONCILE_CONCURRENCY", ec.ReconcileConcurrency, 1)
+	v.nonNegative("EVENTS_SCAN_ON_CREATE_DELAY", ec.ScanOnCreateDelay)
+	v.nonNegative("EVENTS_HEALTH_WINDOW", ec.HealthWindow)
+}
+
+func (sc *ScanConfig) validate(v *validator) {
+	// On-demand scans resolve paths even without the scheduler
+	if _, err := ParsePathRewrites(sc.PathRewrites); err != nil {
+		v.addf("SCAN_PATH_REWRITES is invalid: %v", err)
+	}
+	if _, err := ParseFilesystemNames(sc.FilesystemNames); err != nil {
+		v.addf("SCAN_FILESYSTEM_NAMES is invalid: %v", err)
+	}
+	// Duplicate detection walks volumes itself, without the scheduler
+	if sc.DuplicatesEnabled {
+		v.positive("SCAN_DUPLICATES_INTERVAL", sc.DuplicatesInterval)
+	}
+	if !sc.Enabled {
+		return
+	}
+
+	if sc.SkipPattern != "" {
+		if _, err := utils.CompilePattern(sc.SkipPattern); err != nil {
+			v.addf("SCAN_SKIP_PATTERN is invalid: %v", err)
+		}
+	}
+	v.positive("SCAN_INTERVAL", sc.Interval)
+	v.atLeast("SCAN_CONCURRENCY", sc.Concurrency, 1)
+	v.positive("SCAN_TIMEOUT_PER_VOLUME", sc.TimeoutPerVolume)
+	if len(sc.MethodsOrder) == 0 {
+		v.addf("SCAN_METHODS_ORDER must list at least one method")
+	}
+	seen := make(map[string]bool, len(sc.MethodsOrder))
+	for _, method := range sc.MethodsOrder {
+		method = strings.ToLower(strings.TrimSpace(method))
+		if seen[method] {
+			v.addf("SCAN_METHODS_ORDER lists %q more than once", method)
+		}
+		seen[method] = true
+		v.oneOf("SCAN_METHODS_ORDER", method, ScanMethods...)
+	}
+	v.oneOf("SCAN_FAILURE_LOG_DETAIL", sc.FailureLogDetail, "full", "code")
+	v.oneOf("SCAN_SPECIAL_FILES", sc.SpecialFiles, "skip", "count", "include")
+	v.oneOf("SCAN_SYMLINKS", sc.Symlinks, "skip", "follow-within", "follow-all")
+	v.atLeast("SCAN_CACHE_HISTORY", sc.CacheHistory, 0)
+	v.nonNegative("SCAN_METHOD_RECHECK_INTERVAL", sc.MethodRecheckInterval)
+	v.nonNegative("SCAN_PERSIST_TIMEOUT", sc.PersistTimeout)
+	v.nonNegative("SCAN_MIN_VOLUME_INTERVAL", sc.MinVolumeInterval)
+	if sc.AutoBenchmark {
+		v.positive("SCAN_BENCHMARK_INTERVAL", sc.BenchmarkInterval)
+	}
+	if sc.OnStartup {
+		v.nonNegative("SCAN_STARTUP_DELAY", sc.StartupDelay)
+	}
+	if sc.Jitter < 0 {
+		v.addf("SCAN_JITTER must not be negative, got %g", sc.Jitter)
+	}
+	v.atLeast("SCAN_BATCH_WINDOW", sc.BatchWindow, 0)
+	v.oneOf("SCAN_BATCH_ORDER", sc.BatchOrder, "fair", "listed")
+	v.atLeast("SCAN_EMPTY_BACKOFF_AFTER", sc.EmptyScansBeforeBackoff, 0)
+	if sc.EmptyScansBeforeBackoff > 0 {
+		if sc.EmptyThresholdBytes < 0 {
+			v.addf("SCAN_EMPTY_THRESHOLD_BYTES must not be negative, got %d", sc.EmptyThresholdBytes)
+		}
+		v.nonNegative("SCAN_EMPTY_BACKOFF_MAX", sc.EmptyBackoffMax)
+	}
+	v.nonNegative("SCAN_STALE_AFTER", sc.StaleAfter)
+	if sc.StatsBatchSize > 1 {
+		v.positive("SCAN_STATS_FLUSH_INTERVAL", sc.StatsFlushInterval)
+	}
+}
+
+func (pc *PruneConfig) validate(v *validator) {
+	if pc.ConfirmationRequired {
+		v.positive("PRUNE_CONFIRMATION_TTL", pc.ConfirmationTTL)
+	}
+	v.nonNegative("PRUNE_ANONYMOUS_ORPHAN_GRACE", pc.AnonymousOrphanGrace)
+	v.nonNegative("PRUNE_DETACH_WINDOW", pc.DetachWindow)
+	if pc.QuarantineEnabled {
+		v.nonNegative("PRUNE_QUARANTINE_AFTER", pc.QuarantineAfter)
+		v.positive("PRUNE_QUARANTINE_GRACE", pc.QuarantineGrace)
+		v.positive("PRUNE_QUARANTINE_INTERVAL", pc.QuarantineInterval)
+	}
+}
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig returns the defaults with scanning enabled, which validate cleanly
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := Load()
+	cfg.Scan.Enabled = true
+	require.NoError(t, cfg.Validate())
+	return cfg
+}
+
+func TestValidate_Defaults(t *testing.T) {
+	validConfig(t)
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		problems []string
+	}{
+		{
+			name: "bad intervals",
+			modify: func(cfg *Config) {
+				cfg.Scan.Interval = 0
+				cfg.Scan.TimeoutPerVolume = -time.Second
+				cfg.Lifecycle.Interval = 0
+			},
+			problems: []string{
+				"LIFECYCLE_INTERVAL must be positive, got 0s",
+				"SCAN_INTERVAL must be positive, got 0s",
+				"SCAN_TIMEOUT_PER_VOLUME must be positive, got -1s",
+			},
+		},
+		{
+			name: "scan settings",
+			modify: func(cfg *Config) {
+				cfg.Scan.SkipPattern = "^docker_(("
+				cfg.Scan.MethodsOrder = []string{"du", "rsync", "du"}
+				cfg.Scan.SpecialFiles = "follow"
+				cfg.Scan.Symlinks = "follow"
+				cfg.Scan.CacheHistory = -1
+				cfg.Scan.MethodRecheckInterval = -time.Second
+				cfg.Scan.BatchWindow = -1
+				cfg.Scan.BatchOrder = "fifo"
+				cfg.Scan.EmptyThresholdBytes = -1
+				cfg.Scan.EmptyBackoffMax = -time.Hour
+			},
+			problems: []string{
+				"SCAN_SKIP_PATTERN is invalid",
+				`SCAN_METHODS_ORDER must be one of diskus, du, native, got "rsync"`,
+				`SCAN_METHODS_ORDER lists "du" more than once`,
+				`SCAN_SPECIAL_FILES must be one of skip, count, include, got "follow"`,
+				`SCAN_SYMLINKS must be one of skip, follow-within, follow-all, got "follow"`,
+				"SCAN_CACHE_HISTORY must be at least 0, got -1",
+				"SCAN_METHOD_RECHECK_INTERVAL must not be negative, got -1s",
+				"SCAN_BATCH_WINDOW must be at least 0, got -1",
+				`SCAN_BATCH_ORDER must be one of fair, listed, got "fifo"`,
+				"SCAN_EMPTY_THRESHOLD_BYTES must not be negative, got -1",
+				"SCAN_EMPTY_BACKOFF_MAX must not be negative, got -1h0m0s",
+			},
+		},
+		{
+			name: "inline attachments over a page",
+			modify: func(cfg *Config) {
+				cfg.Server.InlineAttachmentsMax = 500
+			},
+			problems: []string{"API_INLINE_ATTACHMENTS_MAX must be at most 200, the largest attachments page, got 500"},
+		},
+		{
+			name: "reconcile batch over a statement",
+			modify: func(cfg *Config) {
+				cfg.Events.ReconcileBatchSize = 5000
+			},
+			problems: []string{"EVENTS_RECONCILE_BATCH_SIZE must be at most 2978, the most volumes one statement can write, got 5000"},
+		},
+		{
+			name: "postgres without connection settings",
+			modify: func(cfg *Config) {
+				cfg.Database.Type = "postgres"
+				cfg.Database.Host = ""
+				cfg.Database.Port = "postgres"
+				cfg.Database.SSLMode = "strict"
+			},
+			problems: []string{
+				"DB_HOST is required for DB_TYPE postgres",
+				`DB_PORT must be a port between 1 and 65535, got "postgres"`,
+				`DB_SSLMODE must be one of`,
+			},
+		},
+		{
+			name: "conflicting database flags",
+			modify: func(cfg *Config) {
+				cfg.Database.Type = "sqlite"
+				cfg.Database.Path = ""
+				cfg.Database.EnableQueryStats = true
+			},
+			problems: []string{
+				"DB_PATH is required for DB_TYPE sqlite",
+				"DB_ENABLE_QUERY_STATS requires DB_TYPE postgres",
+			},
+		},
+		{
+			name: "unknown database type",
+			modify: func(cfg *Config) {
+				cfg.Database.Type = "mysql"
+			},
+			problems: []string{`DB_TYPE must be one of postgres, sqlite, got "mysql"`},
+		},
+		{
+			name: "conflicting security settings",
+			modify: func(cfg *Config) {
+				cfg.Auth.Enabled = true
+				cfg.Auth.Secret = ""
+				cfg.TLS.CertFile = "/etc/volumeviz/cert.pem"
+				cfg.Events.BackoffMaxDuration = time.Millisecond
+				cfg.Events.BackoffResetAfter = 0
+			},
+			problems: []string{
+				"AUTH_HS256_SECRET is required when AUTH_ENABLED is true",
+				"TLS_CERT_FILE and TLS_KEY_FILE must be set together",
+				"EVENTS_BACKOFF_MAX (1ms) must not be below EVENTS_BACKOFF_MIN (1s)",
+				"EVENTS_BACKOFF_RESET must be positive, got 0s",
+			},
+		},
+		{
+			name: "incremental reconciliation",
+			modify: func(cfg *Config) {
+				cfg.Events.ReconcileIncrementalInterval = 12 * time.Hour
+			},
+			problems: []string{
+				"EVENTS_RECONCILE_INCREMENTAL_INTERVAL (12h0m0s) must be below EVENTS_RECONCILE_INTERVAL (6h0m0s)",
+			},
+		},
+		{
+			name: "auth providers",
+			modify: func(cfg *Config) {
+				cfg.Auth.Enabled = true
+				cfg.Auth.Secret = "signing-key"
+				cfg.Auth.Providers = []string{"jwt", "api_key", "oidc"}
+				cfg.Auth.APIKeys = []string{"ci:deployer:0123456789abcdef"}
+				cfg.Auth.AnonymousRole = "guest"
+			},
+			problems: []string{
+				`AUTH_PROVIDERS must be one of jwt, api_key, got "oidc"`,
+				`AUTH_API_KEYS is invalid: key ci has role "deployer"`,
+				`AUTH_ANONYMOUS_ROLE must be one of viewer, operator, admin, got "guest"`,
+			},
+		},
+		{
+			name: "metrics auth",
+			modify: func(cfg *Config) {
+				cfg.Metrics.AuthMode = "bearer"
+				cfg.Metrics.Token = "short"
+			},
+			problems: []string{
+				"METRICS_TOKEN must be at least 16 characters when METRICS_AUTH is bearer",
+			},
+		},
+		{
+			name: "metrics docker labels",
+			modify: func(cfg *Config) {
+				cfg.Metrics.VolumeLabels = false
+				cfg.Metrics.DockerLabels = []string{"team", "", "com.example/app", "com.example.app"}
+			},
+			problems: []string{
+				"METRICS_DOCKER_LABELS requires METRICS_VOLUME_LABELS",
+				"METRICS_DOCKER_LABELS must not contain empty label keys",
+				`METRICS_DOCKER_LABELS keys "com.example/app" and "com.example.app" both export as label_com_example_app`,
+			},
+		},
+		{
+			name: "path rewrites",
+			modify: func(cfg *Config) {
+				cfg.Scan.Enabled = false
+				cfg.Scan.PathRewrites = []string{"/var/lib/docker/volumes=>/host/volumes", "/srv"}
+			},
+			problems: []string{
+				`SCAN_PATH_REWRITES is invalid: rule "/srv" is not from=>to`,
+			},
+		},
+		{
+			name: "filesystem names",
+			modify: func(cfg *Config) {
+				cfg.Scan.Enabled = false
+				cfg.Scan.FilesystemNames = []string{"0x65735546=fuse", "zfs=0x2fc12fc1"}
+			},
+			problems: []string{
+				`SCAN_FILESYSTEM_NAMES is invalid: entry "zfs=0x2fc12fc1": magic must be a 32-bit number such as 0x65735546`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a ValidationError, got %v", err)
+			require.Len(t, validationErr.Problems, len(tt.problems), validationErr.Problems)
+			for i, problem := range tt.problems {
+				assert.Contains(t, validationErr.Problems[i], problem)
+				assert.Contains(t, err.Error(), problem)
+			}
+		})
+	}
+}
+
+func TestValidate_SkipsDisabledSubsystems(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Scan.Enabled = false
+	cfg.Scan.Interval = 0
+	cfg.Scan.SkipPattern = "(("
+	cfg.Events.Enabled = false
+	cfg.Events.QueueSize = 0
+	cfg.Prune.QuarantineEnabled = false
+	cfg.Prune.QuarantineGrace = 0
+
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestAuthConfig_ParseAPIKeys(t *testing.T) {
+	auth := AuthConfig{APIKeys: []string{"ci:operator:key-with:colons-0123", " grafana:viewer:0123456789abcdef"}}
+	keys, err := auth.ParseAPIKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []APIKey{
+		{Name: "ci", Role: "operator", Key: "key-with:colons-0123"},
+		{Name: "grafana", Role: "viewer", Key: "0123456789abcdef"},
+	}, keys)
+
+	for _, entry := range []string{"ci:operator", ":viewer:0123456789abcdef", "ci:viewer:short"} {
+		_, err := AuthConfig{APIKeys: []string{entry}}.ParseAPIKeys()
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestEffective_RedactsSecrets(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Database.Password = "hunter2"
+	cfg.Auth.Secret = "signing-key"
+	cfg.Auth.APIKeys = []string{"ci:operator:0123456789abcdef"}
+	cfg.Metrics.Token = "scrape-token-0123456789"
+	cfg.Server.Host = "unix:///run/volumeviz.sock"
+
+	effective := cfg.Effective()
+
+	assert.Equal(t, database.RedactedValue, effective["database"]["password"])
+	assert.Equal(t, database.RedactedValue, effective["auth"]["secret"])
+	assert.Equal(t, database.RedactedValue, effective["auth"]["api_keys"])
+	assert.Equal(t, database.RedactedValue, effective["metrics"]["token"])
+	assert.NotContains(t, effective["database"], "Password")
+	assert.Equal(t, "unix:///run/volumeviz.sock", effective["server"]["host"])
+	assert.Equal(t, "0660", effective["server"]["socket_mode"])
+	assert.Equal(t, "6h0m0s", effective["scan"]["interval"])
+	assert.Equal(t, "10s", effective["server"]["read_header_timeout"])
+	assert.Contains(t, effective["lifecycle"], "metrics_ttl_days")
+
+	// Unset secrets stay empty so it is clear they are missing
+	cfg.Auth.Secret = ""
+	assert.Equal(t, "", cfg.Effective()["auth"]["secret"])
+}
@@ -12,6 +12,44 @@ type VolumeScanner interface {
 	GetScanProgress(scanID string) (*ScanProgress, error)
 	GetAvailableMethods() []MethodInfo
 	ClearCache(volumeID string) error
+
+	// EstimateVolumeSize returns a fast sampled size estimate (Estimated is set on the result)
+	EstimateVolumeSize(ctx context.Context, volumeID string) (*ScanResult, error)
+}
+
+// MethodBenchmarker is implemented by scanners that can time every scan method
+// against the same volume, bypassing the cache and the fallback chain
+type MethodBenchmarker interface {
+	BenchmarkMethods(ctx context.Context, volumeID string) (*MethodBenchmark, error)
+}
+
+// ManifestWalker is implemented by scanners that can list a volume's contents.
+// fn is called once per file or directory; returning an error stops the walk.
+type ManifestWalker interface {
+	WalkManifest(ctx context.Context, volumeID string, fn func(ManifestEntry) error) error
+}
+
+// CacheInspector is implemented by scanners that can show the scan result they
+// hold cached for a volume. Looking does not count as a cache hit.
+type CacheInspector interface {
+	CachedResult(volumeID string) *ScanResult
+}
+
+// SizeHistory supplies the sizes recent scans measured for a volume, newest
+// first, so scanners can cache the results of volumes that rarely change longer
+type SizeHistory interface {
+	RecentSizes(ctx context.Context, volumeID string, limit int) ([]int64, error)
+}
+
+// ManifestEntry describes one file or directory in a volume manifest
+type ManifestEntry struct {
+	Path    string    `json:"path"`  // Slash-separated, relative to the volume root
+	Size    int64     `json:"size"`  // Bytes allocated on disk, like scan sizes; zero for directories
+	ModTime time.Time `json:"mtime"`
+	Mode    string    `json:"mode"` // Unix-style permissions, e.g. -rw-r--r-- or drwxr-xr-x
+	// External is set for what a followed symlink leads to outside the volume,
+	// and everything below it; such entries are listed under the link's path
+	External bool `json:"external,omitempty"`
 }
 
 // ScanMethod defines the interface for specific scanning implementations
@@ -42,15 +80,17 @@ type MetricsCollector interface {
 
 	// Enhanced metrics for production monitoring
 	RecordScanFailure(method, errorCode string)
-	UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, size int64, fileCount int, scanMethod string)
+	UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, volumeLabels map[string]string, size int64, fileCount int, scanMethod string)
 	SetDockerConnectionStatus(connected bool)
 	SetCacheSize(size int)
 	SetActiveScanners(count int)
+	SetMethodAvailable(method string, available bool)
 	ScanStarted(method string)
 	ScanFinished(method string)
 	
 	// Scheduler-specific metrics
 	SetSchedulerRunningStatus(running bool)
+	SetSchedulerPausedStatus(paused bool)
 	UpdateSchedulerQueueDepth(depth int)
 	UpdateSchedulerWorkerUtilization(utilization float64)
 }
@@ -67,6 +107,84 @@ type ScanResult struct {
 	Duration       time.Duration `json:"duration"`
 	CacheHit       bool          `json:"cache_hit"`
 	FilesystemType string        `json:"filesystem_type"`
+	// Accuracy is how far TotalSize can be trusted, one of the models.Accuracy constants
+	Accuracy       string        `json:"accuracy"`
+
+	// Set by sampling methods: the totals are extrapolated from SampleSize subtrees
+	// and the true size is expected within ±MarginOfError bytes (~95% confidence)
+	Estimated     bool  `json:"estimated,omitempty"`
+	SampleSize    int   `json:"sample_size,omitempty"`
+	MarginOfError int64 `json:"margin_of_error,omitempty"`
+
+	// Set by the native method: entries left out of the totals (hidden or
+	// special files), special files counted apart, and entries that could not
+	// be read and were left out rather than failing the scan
+	SkippedCount int `json:"skipped_count,omitempty"`
+	SpecialCount int `json:"special_count,omitempty"`
+	ErrorCount   int `json:"error_count,omitempty"`
+
+	// Set by the native method, whose TotalSize counts allocated blocks: the
+	// files' apparent size, and whether it far exceeds TotalSize as it does
+	// for volumes of sparse files
+	ApparentSize int64 `json:"apparent_size,omitempty"`
+	Sparse       bool  `json:"sparse,omitempty"`
+
+	// Set by the native method: symlinks counted as link entries only, and
+	// symlinks whose target was counted instead (see models.SymlinkMode)
+	SymlinksSkipped  int `json:"symlinks_skipped,omitempty"`
+	SymlinksFollowed int `json:"symlinks_followed,omitempty"`
+}
+
+// MethodBenchmark holds the timings of each scan method on one volume
+type MethodBenchmark struct {
+	VolumeID       string         `json:"volume_id"`
+	FilesystemType string         `json:"filesystem_type"`
+	Timings        []MethodTiming `json:"timings"`
+	MeasuredAt     time.Time      `json:"measured_at"`
+}
+
+// MethodTiming is the outcome of running a single scan method during a benchmark
+type MethodTiming struct {
+	Method    string        `json:"method"`
+	Duration  time.Duration `json:"duration"`
+	SizeBytes int64         `json:"size_bytes"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// preferredMethodKey is the context key for WithPreferredMethod
+type preferredMethodKey struct{}
+
+// WithPreferredMethod returns a context asking the scanner to try method first,
+// falling back to its usual order if the method is unavailable or fails
+func WithPreferredMethod(ctx context.Context, method string) context.Context {
+	if method == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, preferredMethodKey{}, method)
+}
+
+// PreferredMethod returns the method requested with WithPreferredMethod, if any
+func PreferredMethod(ctx context.Context) string {
+	method, _ := ctx.Value(preferredMethodKey{}).(string)
+	return method
+}
+
+// deniedMethodsKey is the context key for WithDeniedMethods
+type deniedMethodsKey struct{}
+
+// WithDeniedMethods returns a context barring the scanner from the named
+// methods, even when one of them is preferred
+func WithDeniedMethods(ctx context.Context, methods []string) context.Context {
+	if len(methods) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, deniedMethodsKey{}, methods)
+}
+
+// DeniedMethods returns the methods barred with WithDeniedMethods, if any
+func DeniedMethods(ctx context.Context) []string {
+	methods, _ := ctx.Value(deniedMethodsKey{}).([]string)
+	return methods
 }
 
 // ScanProgress represents the progress of an ongoing scan
@@ -85,12 +203,13 @@ type ScanProgress struct {
 
 // MethodInfo provides information about available scan methods
 type MethodInfo struct {
-	Name        string   `json:"name"`
-	Available   bool     `json:"available"`
-	Description string   `json:"description"`
-	Performance string   `json:"performance"` // "fast", "medium", "slow"
-	Accuracy    string   `json:"accuracy"`    // "high", "medium", "basic"
-	Features    []string `json:"features"`
+	Name          string     `json:"name"`
+	Available     bool       `json:"available"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"` // When Available was last checked; nil if checked on every call
+	Description   string     `json:"description"`
+	Performance   string     `json:"performance"` // "fast", "medium", "slow"
+	Accuracy      string     `json:"accuracy"`    // "high", "medium", "basic"
+	Features      []string   `json:"features"`
 }
 
 // ProgressUpdate represents a progress update during scanning
@@ -1,8 +1,15 @@
 package models
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"strings"
+	"syscall"
 	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
 )
 
 // ScanError represents detailed error information for scan operations
@@ -27,6 +34,97 @@ func (e *ScanError) Unwrap() error {
 	return e.Err
 }
 
+// wrapperErrorCodes are codes that describe where a scan failed rather than why,
+// so classification looks past them for a more specific cause
+var wrapperErrorCodes = map[string]bool{
+	ErrorCodeAllMethodsFailed:  true,
+	ErrorCodeMethodUnavailable: true,
+	ErrorCodeVolumePathError:   true,
+	ErrorCodeUnknown:           true,
+}
+
+// ClassifyScanError returns the most specific error code for a scan failure,
+// looking through wrapping ScanErrors to the underlying cause. It returns
+// "success" for a nil error and ErrorCodeUnknown when nothing more specific applies.
+func ClassifyScanError(err error) string {
+	if err == nil {
+		return "success"
+	}
+
+	// Root causes take priority over the codes of the errors wrapping them
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, syscall.ETIMEDOUT):
+		return ErrorCodeScanTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeScanCanceled
+	case errors.Is(err, fs.ErrPermission):
+		return ErrorCodePermissionDenied
+	case errors.Is(err, syscall.ENOSPC):
+		return ErrorCodeInsufficientSpace
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrorCodePathNotFound
+	}
+
+	// Otherwise use the innermost specific ScanError code
+	code := ""
+	wrapper := ""
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		scanErr, ok := e.(*ScanError)
+		if !ok {
+			continue
+		}
+		if wrapperErrorCodes[scanErr.Code] {
+			if wrapper == "" || scanErr.Code == ErrorCodeVolumePathError {
+				wrapper = scanErr.Code
+			}
+			continue
+		}
+		code = scanErr.Code
+	}
+	if code != "" {
+		return code
+	}
+
+	// The Docker client marks the lookups of volumes that are gone
+	if wrapper == ErrorCodeVolumePathError && cerrdefs.IsNotFound(err) {
+		return ErrorCodeVolumeNotFound
+	}
+
+	// Fall back to message patterns for errors that lost their type along the way
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "permission denied"):
+		return ErrorCodePermissionDenied
+	case strings.Contains(msg, "timed out") || strings.Contains(msg, "timeout"):
+		return ErrorCodeScanTimeout
+	case strings.Contains(msg, "no space left"):
+		return ErrorCodeInsufficientSpace
+	case wrapper == ErrorCodeVolumePathError && (strings.Contains(msg, "not found") || strings.Contains(msg, "no such volume")):
+		return ErrorCodeVolumeNotFound
+	case strings.Contains(msg, "no such file or directory"):
+		return ErrorCodePathNotFound
+	}
+
+	if wrapper != "" {
+		return wrapper
+	}
+	return ErrorCodeUnknown
+}
+
+// VolumeRemoved reports whether a scan failed because its volume is gone
+// from Docker: removed while it was scanned, or before the scan of a volume
+// that was listed could look it up
+func VolumeRemoved(err error) bool {
+	switch ClassifyScanError(err) {
+	case ErrorCodeVolumeRemoved:
+		return true
+	case ErrorCodeVolumeNotFound:
+		// Only Docker's own answer counts, not a scan method missing a path
+		return cerrdefs.IsNotFound(err)
+	}
+	return false
+}
+
 // BulkScanRequest represents a request to scan multiple volumes
 type BulkScanRequest struct {
 	VolumeIDs []string `json:"volume_ids" binding:"required"`
@@ -56,6 +154,75 @@ type ScanConfig struct {
 	MaxConcurrent     int           `yaml:"max_concurrent"`
 	PreferredMethods  []string      `yaml:"preferred_methods"`
 	ProgressReporting bool          `yaml:"progress_reporting"`
+	SampleSize        int           `yaml:"sample_size"` // Subtrees walked by the sample estimate method
+
+	// AvailabilityRecheck is how long the availability of a scan method is
+	// cached before it is checked again; zero checks on every scan
+	AvailabilityRecheck time.Duration `yaml:"availability_recheck"`
+
+	// ExternalSizeCommand sizes volumes without a local mountpoint; empty disables it
+	ExternalSizeCommand string `yaml:"external_size_command"`
+
+	// PathRewrites map host paths to the paths visible to the scanner, as
+	// from=>to rules tried in order; see config.ParsePathRewrites
+	PathRewrites []string `yaml:"path_rewrites"`
+
+	// FilesystemNames name filesystems by their statfs magic number, as
+	// magic=name entries; see config.ParseFilesystemNames
+	FilesystemNames []string `yaml:"filesystem_names"`
+
+	// SpecialFiles is how the native method treats sockets, FIFOs and devices;
+	// empty skips them
+	SpecialFiles SpecialFileMode `yaml:"special_files"`
+	// ExcludeHidden leaves dot files and directories out of native scans
+	ExcludeHidden bool `yaml:"exclude_hidden"`
+	// Symlinks is whether the native method follows symlinks; empty skips them
+	Symlinks SymlinkMode `yaml:"symlinks"`
+}
+
+// SpecialFileMode is how scans treat entries that are neither regular files,
+// directories nor symlinks: sockets, FIFOs, devices and irregular files
+type SpecialFileMode string
+
+const (
+	// SpecialFilesSkip leaves special files out of every total and counts them as skipped
+	SpecialFilesSkip SpecialFileMode = "skip"
+	// SpecialFilesCount leaves special files out of the size and file count
+	// but counts them separately
+	SpecialFilesCount SpecialFileMode = "count"
+	// SpecialFilesInclude counts and sizes special files like regular files
+	SpecialFilesInclude SpecialFileMode = "include"
+)
+
+// Valid reports whether m is a known special file mode
+func (m SpecialFileMode) Valid() bool {
+	switch m {
+	case SpecialFilesSkip, SpecialFilesCount, SpecialFilesInclude:
+		return true
+	}
+	return false
+}
+
+// SymlinkMode is how scans treat symlinks inside a volume
+type SymlinkMode string
+
+const (
+	// SymlinksSkip counts the link entry itself and never follows it
+	SymlinksSkip SymlinkMode = "skip"
+	// SymlinksFollowWithin follows links whose target is inside the volume
+	SymlinksFollowWithin SymlinkMode = "follow-within"
+	// SymlinksFollowAll also follows links out of the volume, sizing whatever
+	// they point at, up to the whole host filesystem
+	SymlinksFollowAll SymlinkMode = "follow-all"
+)
+
+// Valid reports whether m is a known symlink mode
+func (m SymlinkMode) Valid() bool {
+	switch m {
+	case SymlinksSkip, SymlinksFollowWithin, SymlinksFollowAll:
+		return true
+	}
+	return false
 }
 
 // CacheConfig holds configuration for caching
@@ -64,6 +231,10 @@ type CacheConfig struct {
 	TTL      time.Duration `yaml:"ttl"`
 	MaxSize  int           `yaml:"max_size"`
 	RedisURL string        `yaml:"redis_url"`
+
+	// HistorySamples is how many recent scans of a volume scale its TTL by how
+	// often its size changed; fewer than 2 leaves the size-based TTL alone
+	HistorySamples int `yaml:"history_samples"`
 }
 
 // Config holds all scanner configuration
@@ -76,15 +247,20 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		Scanning: ScanConfig{
-			DefaultTimeout:    5 * time.Minute,
-			MaxConcurrent:     5,
-			PreferredMethods:  []string{"diskus", "du", "native"},
-			ProgressReporting: true,
+			DefaultTimeout:      5 * time.Minute,
+			MaxConcurrent:       5,
+			PreferredMethods:    []string{"diskus", "du", "native"},
+			ProgressReporting:   true,
+			SampleSize:          20,
+			AvailabilityRecheck: time.Minute,
+			SpecialFiles:        SpecialFilesSkip,
+			Symlinks:            SymlinksSkip,
 		},
 		Cache: CacheConfig{
-			Type:    "memory",
-			TTL:     5 * time.Minute,
-			MaxSize: 1000,
+			Type:           "memory",
+			TTL:            5 * time.Minute,
+			MaxSize:        1000,
+			HistorySamples: 10,
 		},
 	}
 }
@@ -98,11 +274,13 @@ const (
 	ErrorCodeResultValidationFailed = "RESULT_VALIDATION_FAILED"
 	ErrorCodePermissionDenied       = "PERMISSION_DENIED"
 	ErrorCodeVolumeNotFound         = "VOLUME_NOT_FOUND"
+	ErrorCodeVolumeRemoved          = "VOLUME_REMOVED"
 	ErrorCodeScanCanceled           = "SCAN_CANCELED"
 	ErrorCodeMethodUnavailable      = "METHOD_UNAVAILABLE"
 	ErrorCodePathNotFound           = "PATH_NOT_FOUND"
 	ErrorCodeInsufficientSpace      = "INSUFFICIENT_SPACE"
 	ErrorCodeScanTimeout            = "SCAN_TIMEOUT"
+	ErrorCodeVolumeUnscannable      = "VOLUME_UNSCANNABLE"
 	ErrorCodeUnknown                = "UNKNOWN"
 )
 
@@ -114,3 +292,14 @@ const (
 	ScanStatusFailed    = "failed"
 	ScanStatusCanceled  = "canceled"
 )
+
+// ScanAccuracy says how far a scan result's size can be trusted
+const (
+	// AccuracyExact results counted every file of the volume
+	AccuracyExact = "exact"
+	// AccuracyApproximate results were measured but left entries out, or come
+	// from a source the scanner cannot check
+	AccuracyApproximate = "approximate"
+	// AccuracyEstimated results were extrapolated from a sample
+	AccuracyEstimated = "estimated"
+)
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
@@ -23,6 +25,7 @@ type PrometheusMetricsCollector struct {
 	scanQueueDepthGauge   prometheus.Gauge
 	scanSizeHistogram     prometheus.HistogramVec
 	scansInProgressGauge  prometheus.GaugeVec
+	methodAvailableGauge  prometheus.GaugeVec
 
 	// Volume metrics
 	volumeTotalSizeGauge     prometheus.GaugeVec
@@ -35,15 +38,115 @@ type PrometheusMetricsCollector struct {
 	
 	// Scheduler metrics
 	schedulerRunningStatus prometheus.Gauge
+	schedulerPausedStatus prometheus.Gauge
 	schedulerQueueDepthGauge prometheus.Gauge
 	schedulerWorkerUtilization prometheus.Gauge
+
+	// volumeLabels is false when volume names and IDs are left out of labels
+	volumeLabels bool
+
+	// dockerLabels are the Docker volume labels promoted onto the per-volume
+	// gauges; dockerLabelValues tracks the values seen for each so no label
+	// exports more than maxDockerLabelValues of them
+	dockerLabels         []dockerLabel
+	maxDockerLabelValues int
+	dockerLabelMu        sync.Mutex
+	dockerLabelValues    map[string]map[string]bool
+
+	// filesystems remembers the filesystem_type label of each volume, as
+	// reported by UpdateVolumeMetrics, for the scan histograms
+	filesystemMu sync.Mutex
+	filesystems  map[string]string
+}
+
+// dockerLabel maps a Docker label key to the Prometheus label it is exported as
+type dockerLabel struct {
+	key  string
+	name string
+}
+
+// DefaultDockerLabelMaxValues caps the distinct values exported per promoted
+// Docker label when PrometheusOptions leaves it unset
+const DefaultDockerLabelMaxValues = 100
+
+// DockerLabelOverflow replaces the values of a promoted Docker label once it
+// has exported its maximum number of distinct values
+const DockerLabelOverflow = "other"
+
+// UnknownFilesystem is the filesystem_type label of volumes whose filesystem
+// was not recognized
+const UnknownFilesystem = "unknown"
+
+// FilesystemLabel returns the filesystem_type label for a filesystem type as
+// the scanner reports it. Unrecognized filesystems, reported with their magic
+// number as unknown(0x...), all collapse to UnknownFilesystem so every
+// unnamed filesystem does not become a series of its own.
+func FilesystemLabel(fsType string) string {
+	if fsType == "" || fsType == UnknownFilesystem || strings.HasPrefix(fsType, UnknownFilesystem+"(") {
+		return UnknownFilesystem
+	}
+	return fsType
+}
+
+// DockerLabelName returns the Prometheus label a Docker label key is exported
+// as: the key with every character other than a letter, digit or underscore
+// replaced by an underscore, prefixed with label_ so it cannot clash with the
+// collector's own labels. com.example/team becomes label_com_example_team.
+func DockerLabelName(key string) string {
+	return "label_" + strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// PrometheusOptions tunes what a PrometheusMetricsCollector exports
+type PrometheusOptions struct {
+	// Registerer receives the metrics; nil registers them with the default registry
+	Registerer prometheus.Registerer
+
+	// OmitVolumeLabels leaves volume IDs and names out of labels, so the
+	// number of series no longer grows with the number of volumes. Scan
+	// histograms are kept per method and the per-volume gauges are not exported.
+	OmitVolumeLabels bool
+
+	// DockerLabels lists Docker volume label keys exported as labels on the
+	// per-volume gauges, named by DockerLabelName. Volumes without a label
+	// export it empty. Keys whose names clash with an earlier key are dropped.
+	DockerLabels []string
+	// DockerLabelMaxValues caps the distinct values each Docker label exports;
+	// later values are exported as DockerLabelOverflow. Zero uses
+	// DefaultDockerLabelMaxValues.
+	DockerLabelMaxValues int
 }
 
 // NewPrometheusMetricsCollector creates a new Prometheus metrics collector
 func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheus.Labels) interfaces.MetricsCollector {
-	return &PrometheusMetricsCollector{
+	return NewPrometheusMetricsCollectorWithOptions(namespace, subsystem, labels, PrometheusOptions{})
+}
+
+// NewPrometheusMetricsCollectorWithOptions creates a Prometheus metrics collector tuned by opts
+func NewPrometheusMetricsCollectorWithOptions(namespace, subsystem string, labels prometheus.Labels, opts PrometheusOptions) interfaces.MetricsCollector {
+	registerer := opts.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	factory := promauto.With(registerer)
+
+	scanLabels := []string{"method", "filesystem_type"}
+	if !opts.OmitVolumeLabels {
+		scanLabels = append(scanLabels, "volume_id")
+	}
+
+	p := &PrometheusMetricsCollector{
+		volumeLabels:         !opts.OmitVolumeLabels,
+		maxDockerLabelValues: opts.DockerLabelMaxValues,
+		dockerLabelValues:    make(map[string]map[string]bool),
+		filesystems:          make(map[string]string),
+
 		// Cache metrics
-		cacheHitsTotal: promauto.NewCounter(prometheus.CounterOpts{
+		cacheHitsTotal: factory.NewCounter(prometheus.CounterOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "cache_hits_total",
@@ -51,7 +154,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}),
 
-		cacheMissesTotal: promauto.NewCounter(prometheus.CounterOpts{
+		cacheMissesTotal: factory.NewCounter(prometheus.CounterOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "cache_misses_total",
@@ -59,7 +162,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}),
 
-		cacheSize: promauto.NewGauge(prometheus.GaugeOpts{
+		cacheSize: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "cache_size",
@@ -68,16 +171,16 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 		}),
 
 		// Scan duration histogram with appropriate buckets for volume scanning
-		scanDurationHistogram: *promauto.NewHistogramVec(prometheus.HistogramOpts{
+		scanDurationHistogram: *factory.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "scan_duration_seconds",
 			Help:        "Duration of volume scans in seconds",
 			Buckets:     []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1200}, // Up to 20 minutes
 			ConstLabels: labels,
-		}, []string{"method", "volume_id", "filesystem_type"}),
+		}, scanLabels),
 
-		scanAttemptsTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
+		scanAttemptsTotal: *factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "scan_attempts_total",
@@ -85,7 +188,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}, []string{"method"}),
 
-		scanSuccessTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
+		scanSuccessTotal: *factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "scan_success_total",
@@ -93,7 +196,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}, []string{"method"}),
 
-		scanFailuresTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
+		scanFailuresTotal: *factory.NewCounterVec(prometheus.CounterOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "scan_failures_total",
@@ -101,7 +204,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}, []string{"method", "error_code"}),
 
-		scanQueueDepthGauge: promauto.NewGauge(prometheus.GaugeOpts{
+		scanQueueDepthGauge: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "scan_queue_depth",
@@ -110,7 +213,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 		}),
 
 		// Size histogram with buckets appropriate for volume sizes
-		scanSizeHistogram: *promauto.NewHistogramVec(prometheus.HistogramOpts{
+		scanSizeHistogram: *factory.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "volume_size_bytes",
@@ -127,9 +230,9 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 				100 * 1024 * 1024 * 1024 * 1024, // 100TB
 			},
 			ConstLabels: labels,
-		}, []string{"volume_id", "method", "filesystem_type"}),
+		}, scanLabels),
 
-		scansInProgressGauge: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+		scansInProgressGauge: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "scans_in_progress",
@@ -137,33 +240,16 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}, []string{"method"}),
 
-		// Volume-specific metrics
-		volumeTotalSizeGauge: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+		methodAvailableGauge: *factory.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
-			Name:        "volume_total_size_bytes",
-			Help:        "Total size of each volume in bytes",
+			Name:        "method_available",
+			Help:        "Whether a scan method was available at its last check (1=available, 0=unavailable)",
 			ConstLabels: labels,
-		}, []string{"volume_id", "volume_name", "driver"}),
-
-		volumeFileCountGauge: *promauto.NewGaugeVec(prometheus.GaugeOpts{
-			Namespace:   namespace,
-			Subsystem:   subsystem,
-			Name:        "volume_file_count",
-			Help:        "Number of files in each volume",
-			ConstLabels: labels,
-		}, []string{"volume_id", "volume_name"}),
-
-		volumeScanTimestampGauge: *promauto.NewGaugeVec(prometheus.GaugeOpts{
-			Namespace:   namespace,
-			Subsystem:   subsystem,
-			Name:        "volume_last_scan_timestamp",
-			Help:        "Unix timestamp of last successful scan for each volume",
-			ConstLabels: labels,
-		}, []string{"volume_id", "method"}),
+		}, []string{"method"}),
 
 		// System health metrics
-		dockerConnectionStatus: promauto.NewGauge(prometheus.GaugeOpts{
+		dockerConnectionStatus: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "docker_connection_status",
@@ -171,7 +257,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}),
 
-		activeScanners: promauto.NewGauge(prometheus.GaugeOpts{
+		activeScanners: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
 			Name:        "active_scanners",
@@ -180,7 +266,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 		}),
 		
 		// Scheduler status metrics
-		schedulerRunningStatus: promauto.NewGauge(prometheus.GaugeOpts{
+		schedulerRunningStatus: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   "scheduler",
 			Name:        "running_status",
@@ -188,7 +274,15 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}),
 		
-		schedulerQueueDepthGauge: promauto.NewGauge(prometheus.GaugeOpts{
+		schedulerPausedStatus: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "scheduler",
+			Name:        "paused_status",
+			Help:        "Whether background scanning is paused (1=paused, 0=active)",
+			ConstLabels: labels,
+		}),
+		
+		schedulerQueueDepthGauge: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   "scheduler",
 			Name:        "queue_depth",
@@ -196,7 +290,7 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}),
 		
-		schedulerWorkerUtilization: promauto.NewGauge(prometheus.GaugeOpts{
+		schedulerWorkerUtilization: factory.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   "scheduler",
 			Name:        "worker_utilization",
@@ -204,6 +298,101 @@ func NewPrometheusMetricsCollector(namespace, subsystem string, labels prometheu
 			ConstLabels: labels,
 		}),
 	}
+
+	if p.maxDockerLabelValues <= 0 {
+		p.maxDockerLabelValues = DefaultDockerLabelMaxValues
+	}
+	promoted := []string{}
+	for _, key := range opts.DockerLabels {
+		name := DockerLabelName(key)
+		if _, seen := p.dockerLabelValues[name]; seen {
+			continue
+		}
+		p.dockerLabels = append(p.dockerLabels, dockerLabel{key: key, name: name})
+		p.dockerLabelValues[name] = make(map[string]bool)
+		promoted = append(promoted, name)
+	}
+
+	// Volume-specific metrics are one series per volume, so they need volume labels
+	if p.volumeLabels {
+		p.volumeTotalSizeGauge = *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "volume_total_size_bytes",
+			Help:        "Total size of each volume in bytes",
+			ConstLabels: labels,
+		}, append([]string{"volume_id", "volume_name", "driver"}, promoted...))
+
+		p.volumeFileCountGauge = *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "volume_file_count",
+			Help:        "Number of files in each volume",
+			ConstLabels: labels,
+		}, append([]string{"volume_id", "volume_name"}, promoted...))
+
+		p.volumeScanTimestampGauge = *factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "volume_last_scan_timestamp",
+			Help:        "Unix timestamp of last successful scan for each volume",
+			ConstLabels: labels,
+		}, []string{"volume_id", "method"})
+	}
+	return p
+}
+
+// scanLabels labels an observation of the scan histograms
+func (p *PrometheusMetricsCollector) scanLabels(volumeID, method string) prometheus.Labels {
+	labels := prometheus.Labels{"method": method, "filesystem_type": p.filesystemLabel(volumeID)}
+	if p.volumeLabels {
+		labels["volume_id"] = volumeID
+	}
+	return labels
+}
+
+// filesystemLabel returns the filesystem_type label last reported for a volume
+func (p *PrometheusMetricsCollector) filesystemLabel(volumeID string) string {
+	p.filesystemMu.Lock()
+	defer p.filesystemMu.Unlock()
+	if label, ok := p.filesystems[volumeID]; ok {
+		return label
+	}
+	return UnknownFilesystem
+}
+
+// VolumeRemoved forgets the filesystem_type label of a volume removed from
+// Docker
+func (p *PrometheusMetricsCollector) VolumeRemoved(volumeID string) {
+	p.filesystemMu.Lock()
+	defer p.filesystemMu.Unlock()
+	delete(p.filesystems, volumeID)
+}
+
+// promotedLabelValues returns the value of each promoted Docker label in
+// volumeLabels, replacing values past a label's cap with DockerLabelOverflow
+func (p *PrometheusMetricsCollector) promotedLabelValues(volumeLabels map[string]string) []string {
+	if len(p.dockerLabels) == 0 {
+		return nil
+	}
+
+	p.dockerLabelMu.Lock()
+	defer p.dockerLabelMu.Unlock()
+
+	values := make([]string, len(p.dockerLabels))
+	for i, label := range p.dockerLabels {
+		value := volumeLabels[label.key]
+		seen := p.dockerLabelValues[label.name]
+		if value != "" && !seen[value] {
+			if len(seen) >= p.maxDockerLabelValues {
+				value = DockerLabelOverflow
+			} else {
+				seen[value] = true
+			}
+		}
+		values[i] = value
+	}
+	return values
 }
 
 // CacheHit records a cache hit
@@ -222,13 +411,16 @@ func (p *PrometheusMetricsCollector) ScanCompleted(volumeID, method string, dura
 	p.scanSuccessTotal.WithLabelValues(method).Inc()
 
 	// Record scan duration
-	p.scanDurationHistogram.WithLabelValues(method, volumeID, "unknown").Observe(duration.Seconds())
+	p.scanDurationHistogram.With(p.scanLabels(volumeID, method)).Observe(duration.Seconds())
 
 	// Record volume size distribution
-	p.scanSizeHistogram.WithLabelValues(volumeID, method, "unknown").Observe(float64(size))
+	p.scanSizeHistogram.With(p.scanLabels(volumeID, method)).Observe(float64(size))
 
 	// Update volume-specific metrics
-	p.volumeTotalSizeGauge.WithLabelValues(volumeID, volumeID, "unknown").Set(float64(size))
+	if !p.volumeLabels {
+		return
+	}
+	p.volumeTotalSizeGauge.WithLabelValues(append([]string{volumeID, volumeID, "unknown"}, p.promotedLabelValues(nil)...)...).Set(float64(size))
 	p.volumeScanTimestampGauge.WithLabelValues(volumeID, method).SetToCurrentTime()
 }
 
@@ -254,9 +446,18 @@ func (p *PrometheusMetricsCollector) RecordScanFailure(method, errorCode string)
 }
 
 // UpdateVolumeMetrics updates comprehensive volume metrics
-func (p *PrometheusMetricsCollector) UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, size int64, fileCount int, scanMethod string) {
-	p.volumeTotalSizeGauge.WithLabelValues(volumeID, volumeName, driver).Set(float64(size))
-	p.volumeFileCountGauge.WithLabelValues(volumeID, volumeName).Set(float64(fileCount))
+func (p *PrometheusMetricsCollector) UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, volumeLabels map[string]string, size int64, fileCount int, scanMethod string) {
+	// The scan histograms carry the filesystem label even without volume labels
+	p.filesystemMu.Lock()
+	p.filesystems[volumeID] = FilesystemLabel(filesystemType)
+	p.filesystemMu.Unlock()
+
+	if !p.volumeLabels {
+		return
+	}
+	promoted := p.promotedLabelValues(volumeLabels)
+	p.volumeTotalSizeGauge.WithLabelValues(append([]string{volumeID, volumeName, driver}, promoted...)...).Set(float64(size))
+	p.volumeFileCountGauge.WithLabelValues(append([]string{volumeID, volumeName}, promoted...)...).Set(float64(fileCount))
 	p.volumeScanTimestampGauge.WithLabelValues(volumeID, scanMethod).SetToCurrentTime()
 }
 
@@ -279,6 +480,15 @@ func (p *PrometheusMetricsCollector) SetActiveScanners(count int) {
 	p.activeScanners.Set(float64(count))
 }
 
+// SetMethodAvailable updates whether a scan method is available
+func (p *PrometheusMetricsCollector) SetMethodAvailable(method string, available bool) {
+	if available {
+		p.methodAvailableGauge.WithLabelValues(method).Set(1)
+	} else {
+		p.methodAvailableGauge.WithLabelValues(method).Set(0)
+	}
+}
+
 // ScanStarted records when a scan starts
 func (p *PrometheusMetricsCollector) ScanStarted(method string) {
 	p.scansInProgressGauge.WithLabelValues(method).Inc()
@@ -298,6 +508,15 @@ func (p *PrometheusMetricsCollector) SetSchedulerRunningStatus(running bool) {
 	}
 }
 
+// SetSchedulerPausedStatus updates scheduler paused status
+func (p *PrometheusMetricsCollector) SetSchedulerPausedStatus(paused bool) {
+	if paused {
+		p.schedulerPausedStatus.Set(1)
+	} else {
+		p.schedulerPausedStatus.Set(0)
+	}
+}
+
 // UpdateSchedulerQueueDepth updates scheduler queue depth
 func (p *PrometheusMetricsCollector) UpdateSchedulerQueueDepth(depth int) {
 	p.schedulerQueueDepthGauge.Set(float64(depth))
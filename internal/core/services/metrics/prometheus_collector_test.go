@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportedLabels gathers reg and returns each metric family's label names
+func exportedLabels(t *testing.T, reg *prometheus.Registry) map[string][]string {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	labels := make(map[string][]string, len(families))
+	for _, family := range families {
+		names := []string{}
+		if metrics := family.GetMetric(); len(metrics) > 0 {
+			for _, pair := range metrics[0].GetLabel() {
+				names = append(names, pair.GetName())
+			}
+		}
+		labels[family.GetName()] = names
+	}
+	return labels
+}
+
+func TestPrometheusMetricsCollector_VolumeLabels(t *testing.T) {
+	record := func(opts PrometheusOptions) map[string][]string {
+		reg := prometheus.NewRegistry()
+		opts.Registerer = reg
+		collector := NewPrometheusMetricsCollectorWithOptions("volumeviz", "scanner", prometheus.Labels{"instance": "main"}, opts)
+		collector.ScanCompleted("postgres_data", "du", 2*time.Second, 4096)
+		collector.UpdateVolumeMetrics("postgres_data", "postgres_data", "local", "ext4", nil, 4096, 12, "du")
+		return exportedLabels(t, reg)
+	}
+
+	labels := record(PrometheusOptions{})
+	assert.Contains(t, labels["volumeviz_scanner_scan_duration_seconds"], "volume_id")
+	assert.Contains(t, labels["volumeviz_scanner_volume_total_size_bytes"], "volume_name")
+	assert.Contains(t, labels, "volumeviz_scanner_volume_file_count")
+
+	labels = record(PrometheusOptions{OmitVolumeLabels: true})
+	assert.ElementsMatch(t, []string{"instance", "method", "filesystem_type"}, labels["volumeviz_scanner_scan_duration_seconds"])
+	assert.ElementsMatch(t, []string{"instance", "method", "filesystem_type"}, labels["volumeviz_scanner_volume_size_bytes"])
+	assert.NotContains(t, labels, "volumeviz_scanner_volume_total_size_bytes")
+	assert.NotContains(t, labels, "volumeviz_scanner_volume_file_count")
+	assert.NotContains(t, labels, "volumeviz_scanner_volume_last_scan_timestamp")
+	assert.Contains(t, labels, "volumeviz_scanner_scan_success_total")
+}
+
+func TestPrometheusMetricsCollector_DockerLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := NewPrometheusMetricsCollectorWithOptions("volumeviz", "scanner", nil, PrometheusOptions{
+		Registerer:           reg,
+		DockerLabels:         []string{"team", "com.example/app", "com.example.app"},
+		DockerLabelMaxValues: 2,
+	})
+
+	labels := map[string]string{"team": "payments", "com.example/app": "ledger", "secret": "do-not-export"}
+	collector.UpdateVolumeMetrics("ledger_data", "ledger_data", "local", "ext4", labels, 4096, 12, "du")
+	collector.UpdateVolumeMetrics("search_data", "search_data", "local", "ext4", map[string]string{"team": "search"}, 2048, 3, "du")
+	// A third team is past the cap of two values
+	collector.UpdateVolumeMetrics("billing_data", "billing_data", "local", "ext4", map[string]string{"team": "billing"}, 1024, 1, "du")
+
+	exported := exportedLabels(t, reg)
+	assert.ElementsMatch(t, []string{"volume_id", "volume_name", "driver", "label_team", "label_com_example_app"},
+		exported["volumeviz_scanner_volume_total_size_bytes"])
+	assert.ElementsMatch(t, []string{"volume_id", "volume_name", "label_team", "label_com_example_app"},
+		exported["volumeviz_scanner_volume_file_count"])
+	assert.NotContains(t, exported["volumeviz_scanner_volume_total_size_bytes"], "label_secret")
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	teams := map[string]string{}
+	for _, family := range families {
+		if family.GetName() != "volumeviz_scanner_volume_total_size_bytes" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			values := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				values[pair.GetName()] = pair.GetValue()
+			}
+			teams[values["volume_id"]] = values["label_team"]
+		}
+	}
+	assert.Equal(t, map[string]string{"ledger_data": "payments", "search_data": "search", "billing_data": DockerLabelOverflow}, teams)
+}
+
+func TestPrometheusMetricsCollector_FilesystemLabel(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := NewPrometheusMetricsCollectorWithOptions("volumeviz", "scanner", nil, PrometheusOptions{Registerer: reg})
+
+	collector.UpdateVolumeMetrics("postgres_data", "postgres_data", "local", "ext4", nil, 4096, 12, "du")
+	collector.ScanCompleted("postgres_data", "du", time.Second, 4096)
+	collector.UpdateVolumeMetrics("fuse_data", "fuse_data", "local", "unknown(0x65735546)", nil, 2048, 3, "du")
+	collector.ScanCompleted("fuse_data", "du", time.Second, 2048)
+	// Never reported by UpdateVolumeMetrics
+	collector.ScanCompleted("cache_data", "du", time.Second, 1024)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	filesystems := map[string]string{}
+	for _, family := range families {
+		if family.GetName() != "volumeviz_scanner_scan_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			values := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				values[pair.GetName()] = pair.GetValue()
+			}
+			filesystems[values["volume_id"]] = values["filesystem_type"]
+		}
+	}
+	assert.Equal(t, map[string]string{"postgres_data": "ext4", "fuse_data": UnknownFilesystem, "cache_data": UnknownFilesystem}, filesystems)
+
+	// A removed volume's label is forgotten
+	prom := collector.(*PrometheusMetricsCollector)
+	prom.VolumeRemoved("postgres_data")
+	assert.Equal(t, UnknownFilesystem, prom.filesystemLabel("postgres_data"))
+	assert.NotContains(t, prom.filesystems, "postgres_data")
+}
+
+func TestFilesystemLabel(t *testing.T) {
+	assert.Equal(t, "xfs", FilesystemLabel("xfs"))
+	assert.Equal(t, "fuse", FilesystemLabel("fuse"))
+	assert.Equal(t, UnknownFilesystem, FilesystemLabel("unknown(0x65735546)"))
+	assert.Equal(t, UnknownFilesystem, FilesystemLabel("unknown"))
+	assert.Equal(t, UnknownFilesystem, FilesystemLabel(""))
+
+	// Configured names are kept even when they start with unknown
+	assert.Equal(t, "unknownfs", FilesystemLabel("unknownfs"))
+	assert.Equal(t, "unknown-nas", FilesystemLabel("unknown-nas"))
+}
+
+func TestDockerLabelName(t *testing.T) {
+	assert.Equal(t, "label_team", DockerLabelName("team"))
+	assert.Equal(t, "label_com_docker_compose_project", DockerLabelName("com.docker.compose.project"))
+	assert.Equal(t, "label_app_kubernetes_io_name", DockerLabelName("app.kubernetes.io/name"))
+	assert.Equal(t, "label_2fa_", DockerLabelName("2fa-"))
+}
@@ -179,7 +179,7 @@ func (s *SimpleMetricsCollector) RecordScanFailure(method, errorCode string) {
 }
 
 // UpdateVolumeMetrics updates comprehensive volume metrics
-func (s *SimpleMetricsCollector) UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, size int64, fileCount int, scanMethod string) {
+func (s *SimpleMetricsCollector) UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, volumeLabels map[string]string, size int64, fileCount int, scanMethod string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -233,6 +233,18 @@ func (s *SimpleMetricsCollector) SetActiveScanners(count int) {
 	}
 }
 
+// SetMethodAvailable updates whether a scan method is available
+func (s *SimpleMetricsCollector) SetMethodAvailable(method string, available bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats["method_available_"+method] = available
+
+	if s.logger != nil {
+		s.logger.Printf("METHOD_AVAILABLE method=%s available=%t", method, available)
+	}
+}
+
 // ScanStarted records when a scan starts
 func (s *SimpleMetricsCollector) ScanStarted(method string) {
 	s.mu.Lock()
@@ -287,6 +299,22 @@ func (s *SimpleMetricsCollector) SetSchedulerRunningStatus(running bool) {
 	}
 }
 
+// SetSchedulerPausedStatus updates scheduler paused status
+func (s *SimpleMetricsCollector) SetSchedulerPausedStatus(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats["scheduler_paused"] = paused
+
+	if s.logger != nil {
+		status := "ACTIVE"
+		if paused {
+			status = "PAUSED"
+		}
+		s.logger.Printf("SCHEDULER_PAUSE status=%s", status)
+	}
+}
+
 // UpdateSchedulerQueueDepth updates scheduler queue depth
 func (s *SimpleMetricsCollector) UpdateSchedulerQueueDepth(depth int) {
 	s.mu.Lock()
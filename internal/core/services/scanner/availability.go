@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+)
+
+// checkedMethod caches whether a scan method is available, since the check
+// may look a binary up on PATH, and rechecks once the recheck interval has
+// passed so binaries installed or removed at runtime are noticed. A zero
+// interval checks on every call.
+type checkedMethod struct {
+	interfaces.ScanMethod
+	interval time.Duration
+	metrics  interfaces.MetricsCollector
+	now      func() time.Time
+
+	mu        sync.Mutex
+	available bool
+	checkedAt time.Time
+}
+
+// newCheckedMethod wraps method and checks its availability once up front,
+// so the first scan does not pay for the check
+func newCheckedMethod(method interfaces.ScanMethod, interval time.Duration, metrics interfaces.MetricsCollector) *checkedMethod {
+	m := &checkedMethod{
+		ScanMethod: method,
+		interval:   interval,
+		metrics:    metrics,
+		now:        time.Now,
+	}
+	m.mu.Lock()
+	m.recheck()
+	m.mu.Unlock()
+	return m
+}
+
+// Available returns the cached availability, rechecking it when it is older
+// than the recheck interval
+func (m *checkedMethod) Available() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.now().Sub(m.checkedAt) >= m.interval {
+		m.recheck()
+	}
+	return m.available
+}
+
+// LastChecked returns when the method's availability was last checked
+func (m *checkedMethod) LastChecked() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.checkedAt
+}
+
+// recheck runs the method's own availability check; m.mu must be held
+func (m *checkedMethod) recheck() {
+	m.available = m.ScanMethod.Available()
+	m.checkedAt = m.now()
+	if m.metrics != nil {
+		m.metrics.SetMethodAvailable(m.Name(), m.available)
+	}
+}
+
+// lastChecked returns when the availability of method was last checked, or
+// nil for methods checked on every call
+func lastChecked(method interfaces.ScanMethod) *time.Time {
+	checked, ok := method.(*checkedMethod)
+	if !ok {
+		return nil
+	}
+	at := checked.LastChecked()
+	return &at
+}
@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/mantonx/volumeviz/internal/core/services/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckedMethod_RechecksOnInterval(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+
+	collector := metrics.NewSimpleMetricsCollector(nil)
+	clock := time.Now()
+	method := newCheckedMethod(NewDiskusMethod(models.DefaultConfig().Scanning), time.Minute, collector)
+	method.now = func() time.Time { return clock }
+	warmedUpAt := method.LastChecked()
+
+	assert.False(t, method.Available())
+	assert.Equal(t, false, collector.(*metrics.SimpleMetricsCollector).GetStats()["method_available_diskus"])
+
+	// diskus is installed at runtime; the cached result stands until the interval passes
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "diskus"), []byte("#!/bin/sh\n"), 0o755))
+	assert.False(t, method.Available())
+	assert.Equal(t, warmedUpAt, method.LastChecked())
+
+	clock = clock.Add(time.Hour)
+	assert.True(t, method.Available())
+	assert.Equal(t, clock, method.LastChecked())
+	assert.Equal(t, true, collector.(*metrics.SimpleMetricsCollector).GetStats()["method_available_diskus"])
+
+	// and removed again
+	require.NoError(t, os.Remove(filepath.Join(bin, "diskus")))
+	clock = clock.Add(30 * time.Second)
+	assert.True(t, method.Available())
+	clock = clock.Add(30 * time.Second)
+	assert.False(t, method.Available())
+}
+
+func TestCheckedMethod_ZeroIntervalChecksEveryCall(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+
+	method := newCheckedMethod(NewDuMethod(models.DefaultConfig().Scanning), 0, nil)
+	assert.False(t, method.Available())
+
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "du"), []byte("#!/bin/sh\n"), 0o755))
+	assert.True(t, method.Available())
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"context"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+)
+
+// BenchmarkMethods runs every available scan method against a volume and reports
+// how long each took. The cache, fallback chain and scan metrics are bypassed so
+// the timings reflect the methods alone.
+func (vs *VolumeScanner) BenchmarkMethods(ctx context.Context, volumeID string) (*interfaces.MethodBenchmark, error) {
+	select {
+	case vs.semaphore <- struct{}{}:
+		defer func() { <-vs.semaphore }()
+	case <-ctx.Done():
+		return nil, &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeScanQueueTimeout,
+			Message:  "scan queue timeout",
+			Err:      ctx.Err(),
+		}
+	}
+
+	volumePath, err := vs.getVolumePath(volumeID)
+	if err != nil {
+		return nil, &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeVolumePathError,
+			Message:  "failed to resolve volume path",
+			Err:      err,
+		}
+	}
+
+	if err := vs.validatePath(volumePath); err != nil {
+		return nil, &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodePathValidationFailed,
+			Message:  "path validation failed",
+			Path:     volumePath,
+			Err:      err,
+		}
+	}
+
+	benchmark := &interfaces.MethodBenchmark{
+		VolumeID:       volumeID,
+		FilesystemType: vs.detectFilesystemType(volumePath),
+		MeasuredAt:     time.Now(),
+	}
+
+	for _, method := range vs.methods {
+		if !method.Available() {
+			continue
+		}
+
+		timing := interfaces.MethodTiming{Method: method.Name()}
+
+		scanCtx, cancel := context.WithTimeout(ctx, vs.config.Scanning.DefaultTimeout)
+		start := time.Now()
+		result, err := method.Scan(scanCtx, volumePath)
+		timing.Duration = time.Since(start)
+		cancel()
+
+		if err != nil {
+			timing.Error = err.Error()
+		} else {
+			timing.SizeBytes = result.TotalSize
+		}
+		benchmark.Timings = append(benchmark.Timings, timing)
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	if vs.logger != nil {
+		vs.logger.Printf("Benchmarked %d scan methods on volume %s (filesystem: %s)",
+			len(benchmark.Timings), volumeID, benchmark.FilesystemType)
+	}
+
+	return benchmark, nil
+}
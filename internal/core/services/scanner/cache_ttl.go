@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"context"
+	"math"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+)
+
+const (
+	// minHistorySizes is the fewest sizes, the current one included, that say
+	// anything about how often a volume changes
+	minHistorySizes = 3
+
+	// maxChangeFactor scales the TTL of a volume whose size never changed up,
+	// and of one that changed on every scan down, by this much
+	maxChangeFactor = 4.0
+
+	// changeTolerance is the relative difference between two sizes below which
+	// the volume is considered unchanged, so metadata churn does not count
+	changeTolerance = 0.01
+)
+
+// SetSizeHistory makes cache TTLs follow how often each volume's size
+// changed over its recent scans, as recorded in history
+func (vs *VolumeScanner) SetSizeHistory(history interfaces.SizeHistory) {
+	vs.sizeHistory = history
+}
+
+// changeFactor returns how much to scale the cache TTL of result by how often
+// the volume's size changed across its recent scans: up to maxChangeFactor
+// for a volume that never changed, down to 1/maxChangeFactor for one that
+// changed every time, and 1 without enough history
+func (vs *VolumeScanner) changeFactor(ctx context.Context, result *interfaces.ScanResult) float64 {
+	samples := vs.config.Cache.HistorySamples
+	if vs.sizeHistory == nil || samples < 2 {
+		return 1
+	}
+
+	sizes, err := vs.sizeHistory.RecentSizes(ctx, result.VolumeID, samples)
+	if err != nil {
+		if vs.logger != nil {
+			vs.logger.Printf("Failed to load size history for volume %s: %v", result.VolumeID, err)
+		}
+		return 1
+	}
+	// A full scan is the newest size; an estimate would look like a change
+	if !result.Estimated {
+		sizes = append([]int64{result.TotalSize}, sizes...)
+		sizes = sizes[:min(len(sizes), samples)]
+	}
+	if len(sizes) < minHistorySizes {
+		return 1
+	}
+
+	changes := 0
+	for i := 1; i < len(sizes); i++ {
+		if sizeChanged(sizes[i-1], sizes[i]) {
+			changes++
+		}
+	}
+	rate := float64(changes) / float64(len(sizes)-1)
+	return math.Pow(maxChangeFactor, 1-2*rate)
+}
+
+// sizeChanged reports whether two sizes of a volume differ by more than changeTolerance
+func sizeChanged(a, b int64) bool {
+	diff := math.Abs(float64(a - b))
+	return diff > changeTolerance*float64(max(a, b))
+}
@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+)
+
+// externalMethodName is reported as the method of results from the external size command
+const externalMethodName = "external"
+
+// ExternalSizer sizes volumes that have no local path by running a configured
+// command, typically a wrapper around the storage driver's API or CLI. The
+// command gets the volume name as its last argument, plus VOLUMEVIZ_VOLUME_NAME
+// and VOLUMEVIZ_VOLUME_DRIVER in its environment, and must print the size in
+// bytes as the first field of its output.
+type ExternalSizer struct {
+	command []string
+	timeout time.Duration
+}
+
+// NewExternalSizer creates an external sizer, or returns nil when no command is configured
+func NewExternalSizer(config models.ScanConfig) *ExternalSizer {
+	command := strings.Fields(config.ExternalSizeCommand)
+	if len(command) == 0 {
+		return nil
+	}
+
+	return &ExternalSizer{
+		command: command,
+		timeout: config.DefaultTimeout,
+	}
+}
+
+// Size runs the external size command for a volume
+func (e *ExternalSizer) Size(ctx context.Context, volumeName, driver string) (*interfaces.ScanResult, error) {
+	scanCtx := ctx
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	args := append(append([]string{}, e.command[1:]...), volumeName)
+	cmd := exec.CommandContext(scanCtx, e.command[0], args...)
+	cmd.Env = append(os.Environ(),
+		"VOLUMEVIZ_VOLUME_NAME="+volumeName,
+		"VOLUMEVIZ_VOLUME_DRIVER="+driver,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := scanCtx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return nil, &models.ScanError{
+			VolumeID: volumeName,
+			Method:   externalMethodName,
+			Code:     models.ErrorCodeMethodUnavailable,
+			Message:  "external size command failed",
+			Err:      err,
+			Context: map[string]any{
+				"command": e.command[0],
+				"stderr":  stderr.String(),
+			},
+		}
+	}
+
+	output := strings.Fields(stdout.String())
+	if len(output) == 0 {
+		return nil, &models.ScanError{
+			VolumeID: volumeName,
+			Method:   externalMethodName,
+			Code:     models.ErrorCodeResultValidationFailed,
+			Message:  "external size command returned empty output",
+			Context: map[string]any{
+				"stderr": stderr.String(),
+			},
+		}
+	}
+
+	totalSize, err := strconv.ParseInt(output[0], 10, 64)
+	if err != nil || totalSize < 0 {
+		if err == nil {
+			err = fmt.Errorf("negative size %d", totalSize)
+		}
+		return nil, &models.ScanError{
+			VolumeID: volumeName,
+			Method:   externalMethodName,
+			Code:     models.ErrorCodeResultValidationFailed,
+			Message:  fmt.Sprintf("failed to parse external size output '%s'", output[0]),
+			Err:      err,
+			Context: map[string]any{
+				"raw_output": stdout.String(),
+			},
+		}
+	}
+
+	return &interfaces.ScanResult{
+		VolumeID:  volumeName,
+		TotalSize: totalSize,
+		Method:    externalMethodName,
+		ScannedAt: time.Now(),
+		Duration:  time.Since(start),
+	}, nil
+}
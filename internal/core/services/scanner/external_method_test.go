@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/mantonx/volumeviz/internal/core/services/cache"
+	"github.com/mantonx/volumeviz/internal/core/services/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExternalSizer_DisabledWithoutCommand(t *testing.T) {
+	assert.Nil(t, NewExternalSizer(models.ScanConfig{}))
+	assert.Nil(t, NewExternalSizer(models.ScanConfig{ExternalSizeCommand: "   "}))
+}
+
+func TestExternalSizer_Size(t *testing.T) {
+	ctx := context.Background()
+	config := models.DefaultConfig().Scanning
+
+	// The volume name is appended as the last argument: "echo 4096 data"
+	config.ExternalSizeCommand = "echo 4096"
+	result, err := NewExternalSizer(config).Size(ctx, "data", "netshare")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), result.TotalSize)
+	assert.Equal(t, "external", result.Method)
+	assert.Equal(t, "data", result.VolumeID)
+
+	// The volume name and driver are also passed in the environment
+	script := filepath.Join(t.TempDir(), "volume-size")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n[ \"$VOLUMEVIZ_VOLUME_NAME\" = \"$1\" ] && [ \"$VOLUMEVIZ_VOLUME_DRIVER\" = netshare ] && echo 2048\n"), 0755))
+	config.ExternalSizeCommand = script
+	result, err = NewExternalSizer(config).Size(ctx, "data", "netshare")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), result.TotalSize)
+
+	config.ExternalSizeCommand = "false"
+	_, err = NewExternalSizer(config).Size(ctx, "data", "netshare")
+	assert.Equal(t, models.ErrorCodeMethodUnavailable, models.ClassifyScanError(err))
+
+	config.ExternalSizeCommand = "echo unknown"
+	_, err = NewExternalSizer(config).Size(ctx, "data", "netshare")
+	assert.Equal(t, models.ErrorCodeResultValidationFailed, models.ClassifyScanError(err))
+}
+
+func TestVolumeScanner_ScanUnresolved(t *testing.T) {
+	ctx := context.Background()
+	vs := &VolumeScanner{
+		cache:   cache.NewMemoryCache(10),
+		metrics: metrics.NewSimpleMetricsCollector(nil),
+	}
+
+	tests := []struct {
+		name       string
+		mountpoint string
+		reason     string
+	}{
+		{name: "empty mountpoint", mountpoint: "", reason: "driver reported no mountpoint"},
+		{name: "non-local mountpoint", mountpoint: "nfs://storage/export", reason: `mountpoint "nfs://storage/export" is not a local path`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unresolved := &unresolvedMountpointError{driver: "netshare", reason: tt.reason}
+
+			// Without an external size command the volume is reported as unscannable
+			vs.external = nil
+			_, err := vs.scanUnresolved(ctx, "data", unresolved)
+			var scanErr *models.ScanError
+			require.ErrorAs(t, err, &scanErr)
+			assert.Equal(t, models.ErrorCodeVolumeUnscannable, scanErr.Code)
+			assert.Equal(t, tt.reason, scanErr.Context["reason"])
+			assert.Equal(t, models.ErrorCodeVolumeUnscannable, models.ClassifyScanError(err))
+
+			// With one it is sized externally
+			vs.external = NewExternalSizer(models.ScanConfig{ExternalSizeCommand: "echo 512"})
+			result, err := vs.scanUnresolved(ctx, "data", unresolved)
+			require.NoError(t, err)
+			assert.Equal(t, int64(512), result.TotalSize)
+		})
+	}
+}
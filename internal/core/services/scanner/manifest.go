@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+)
+
+// WalkManifest walks a volume with the native method's directory walk and reports
+// every file and directory to fn, depth first in directory order. Entries are
+// streamed rather than collected, so memory use does not grow with the size of the
+// volume.
+func (vs *VolumeScanner) WalkManifest(ctx context.Context, volumeID string, fn func(interfaces.ManifestEntry) error) error {
+	select {
+	case vs.semaphore <- struct{}{}:
+		defer func() { <-vs.semaphore }()
+	case <-ctx.Done():
+		return &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeScanQueueTimeout,
+			Message:  "scan queue timeout",
+			Err:      ctx.Err(),
+		}
+	}
+
+	volumePath, err := vs.getVolumePath(volumeID)
+	if err != nil {
+		return &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeVolumePathError,
+			Message:  "failed to resolve volume path",
+			Err:      err,
+		}
+	}
+
+	if err := vs.validatePath(volumePath); err != nil {
+		return &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodePathValidationFailed,
+			Message:  "path validation failed",
+			Path:     volumePath,
+			Err:      err,
+		}
+	}
+
+	return walkManifest(ctx, volumePath, vs.config.Scanning.Symlinks, fn)
+}
+
+// walkManifest calls fn for every entry below root, walking it like the native
+// scan method: symlinks are followed as symlinks says, each directory reached
+// through a link is listed once, entries that cannot be read are skipped and
+// file sizes are the bytes allocated on disk. What a link leads to outside the
+// volume is listed under the link's path and marked external, as is everything
+// in a directory it leads to. Only an unreadable root fails the walk.
+func walkManifest(ctx context.Context, root string, symlinks models.SymlinkMode, fn func(interfaces.ManifestEntry) error) error {
+	var rootErr error
+	var followed string // Last link followed, reported before its target
+	// External directories by their real path, with the path they are listed at
+	external := make(map[string]string)
+	err := walkNative(root, func(path string, info fs.FileInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		isExternal := !filepath.IsLocal(relPath)
+		if isExternal {
+			var ok bool
+			if relPath, ok = externalPath(external, path); !ok {
+				return nil
+			}
+		}
+		if path == followed {
+			// Links within the volume are counted in place, so a followed one
+			// reaching here leads outside; a directory's entries come from its target
+			isExternal = true
+			if info.IsDir() {
+				target, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					return fs.SkipDir
+				}
+				external[target] = relPath
+			}
+		}
+
+		entry := interfaces.ManifestEntry{
+			Path:     filepath.ToSlash(relPath),
+			ModTime:  info.ModTime().UTC(),
+			Mode:     info.Mode().String(),
+			External: isExternal,
+		}
+		if !info.IsDir() {
+			entry.Size = info.Size()
+			if allocated, ok := allocatedSize(info); ok {
+				entry.Size = allocated
+			}
+		}
+
+		return fn(entry)
+	}, nativeWalkOptions{
+		Symlinks: symlinks,
+		OnSymlink: func(path string, wasFollowed bool) {
+			if wasFollowed {
+				followed = path
+			}
+		},
+		OnError: func(path string, err error) {
+			if path == root {
+				rootErr = err
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return rootErr
+}
+
+// externalPath returns the manifest path of path, which lies below one of the
+// external directories, or false if it lies below none of them
+func externalPath(external map[string]string, path string) (string, bool) {
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if listedAt, ok := external[dir]; ok {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return "", false
+			}
+			return filepath.Join(listedAt, rel), true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return "", false
+		}
+	}
+}
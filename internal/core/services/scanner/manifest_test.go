@@ -0,0 +1,158 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkManifest(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "data", "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "top.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data", "nested", "blob.bin"), make([]byte, 2048), 0o600))
+
+	mtime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "top.txt"), mtime, mtime))
+
+	entries := manifestOf(t, root, models.SymlinksSkip)
+	assert.ElementsMatch(t, []string{"data", "data/nested", "data/nested/blob.bin", "top.txt"}, manifestPaths(entries))
+
+	// Sizes are what the files allocate, like the native scan counts them
+	info, err := os.Lstat(filepath.Join(root, "data", "nested", "blob.bin"))
+	require.NoError(t, err)
+	blobSize, ok := allocatedSize(info)
+	require.True(t, ok)
+
+	byPath := make(map[string]interfaces.ManifestEntry)
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+	assert.Equal(t, int64(0), byPath["data"].Size)
+	assert.Equal(t, "drwxr-xr-x", byPath["data"].Mode)
+	assert.Equal(t, blobSize, byPath["data/nested/blob.bin"].Size)
+	assert.Equal(t, "-rw-------", byPath["data/nested/blob.bin"].Mode)
+	assert.True(t, byPath["top.txt"].ModTime.Equal(mtime))
+}
+
+func TestWalkManifest_Symlinks(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "shared.txt"), []byte("shared"), 0o644))
+
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "data"), 0o755))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "ext")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "ext-again")))
+	require.NoError(t, os.Symlink(root, filepath.Join(root, "data", "loop")))
+
+	// Without following, links are listed as themselves
+	entries := manifestOf(t, root, models.SymlinksSkip)
+	assert.ElementsMatch(t, []string{"data", "data/loop", "ext", "ext-again"}, manifestPaths(entries))
+
+	// Following all walks the outside target once and ends the loop back to
+	// the root, which is listed where it lives
+	entries = manifestOf(t, root, models.SymlinksFollowAll)
+	paths := manifestPaths(entries)
+	assert.Contains(t, paths, "data")
+	assert.NotContains(t, paths, "data/loop", "the loop leads to data counted in place")
+	var shared []interfaces.ManifestEntry
+	for _, entry := range entries {
+		assert.True(t, filepath.IsLocal(entry.Path), "%s lies outside the volume", entry.Path)
+		if filepath.Base(entry.Path) == "shared.txt" {
+			shared = append(shared, entry)
+		}
+	}
+	require.Len(t, shared, 1, "the outside target is listed once: %v", paths)
+	// Listed under whichever link reached it first
+	assert.Contains(t, []string{"ext/shared.txt", "ext-again/shared.txt"}, shared[0].Path)
+	assert.True(t, shared[0].External)
+}
+
+func TestWalkManifest_ExternalTargets(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(outside, "nested"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "nested", "deep.txt"), []byte("deep"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "file.txt"), []byte("file"), 0o644))
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "local.txt"), []byte("local"), 0o644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "mnt")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "file.txt"), filepath.Join(root, "linked.txt")))
+
+	external := make(map[string]bool)
+	for _, entry := range manifestOf(t, root, models.SymlinksFollowAll) {
+		external[entry.Path] = entry.External
+	}
+	assert.Equal(t, map[string]bool{
+		"local.txt":           false,
+		"linked.txt":          true,
+		"mnt":                 true,
+		"mnt/file.txt":        true,
+		"mnt/nested":          true,
+		"mnt/nested/deep.txt": true,
+	}, external)
+
+	// Following only links within the volume lists the links themselves
+	external = make(map[string]bool)
+	for _, entry := range manifestOf(t, root, models.SymlinksFollowWithin) {
+		external[entry.Path] = entry.External
+	}
+	assert.Equal(t, map[string]bool{"local.txt": false, "linked.txt": false, "mnt": false}, external)
+}
+
+// manifestOf collects the manifest walkManifest reports for root
+func manifestOf(t *testing.T, root string, symlinks models.SymlinkMode) []interfaces.ManifestEntry {
+	t.Helper()
+	var entries []interfaces.ManifestEntry
+	err := walkManifest(context.Background(), root, symlinks, func(entry interfaces.ManifestEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+// manifestPaths returns the paths of entries in walk order
+func manifestPaths(entries []interfaces.ManifestEntry) []string {
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.Path
+	}
+	return paths
+}
+
+func TestWalkManifest_StopsOnCancellationAndCallbackError(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), nil, 0o644))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := walkManifest(ctx, root, models.SymlinksSkip, func(entry interfaces.ManifestEntry) error {
+		calls++
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+
+	stop := errors.New("client went away")
+	err = walkManifest(context.Background(), root, models.SymlinksSkip, func(entry interfaces.ManifestEntry) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+
+	err = walkManifest(context.Background(), filepath.Join(root, "missing"), models.SymlinksSkip, func(entry interfaces.ManifestEntry) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
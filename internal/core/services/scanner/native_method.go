@@ -2,25 +2,68 @@ package scanner
 
 import (
 	"context"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
 	"github.com/mantonx/volumeviz/internal/core/models"
 )
 
-// NativeMethod implements directory scanning using pure Go filepath.Walk
+// NativeMethod implements directory scanning as a pure Go directory walk.
+// Only running totals are kept and directories are read in fixed-size batches
+// (see walkNative), so memory stays flat regardless of the number of files.
+// Entries that cannot be read are counted in ErrorCount instead of failing
+// the scan. Sizes are the blocks files occupy on disk, like du, so sparse
+// files count what they allocate rather than their apparent size. Symlinks
+// are followed as configured by models.SymlinkMode.
 type NativeMethod struct {
 	timeout          time.Duration
+	specialFiles     models.SpecialFileMode
+	symlinks         models.SymlinkMode
+	excludeHidden    bool
 	progressCallback func(interfaces.ProgressUpdate)
 }
 
+// specialFileModes are the entry types treated as special files
+const specialFileModes = fs.ModeNamedPipe | fs.ModeSocket | fs.ModeDevice | fs.ModeCharDevice | fs.ModeIrregular
+
+// A scan is flagged sparse when its apparent size is over sparseRatio times
+// the allocated size and exceeds it by at least sparseMinExcess bytes
+const (
+	sparseRatio     = 2
+	sparseMinExcess = 1 << 20
+)
+
 // NewNativeMethod creates a new native Go scan method
 func NewNativeMethod(config models.ScanConfig) interfaces.ScanMethod {
+	specialFiles := config.SpecialFiles
+	if !specialFiles.Valid() {
+		specialFiles = models.SpecialFilesSkip
+	}
+	symlinks := config.Symlinks
+	if !symlinks.Valid() {
+		symlinks = models.SymlinksSkip
+	}
+
 	return &NativeMethod{
-		timeout: config.DefaultTimeout,
+		timeout:       config.DefaultTimeout,
+		specialFiles:  specialFiles,
+		symlinks:      symlinks,
+		excludeHidden: config.ExcludeHidden,
+	}
+}
+
+// allocatedSize returns the bytes a file occupies on disk, from its st_blocks
+// count in 512-byte units
+func allocatedSize(info fs.FileInfo) (int64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
 	}
+	return stat.Blocks * 512, true
 }
 
 func (n *NativeMethod) Name() string {
@@ -59,15 +102,18 @@ func (n *NativeMethod) Scan(ctx context.Context, path string) (*interfaces.ScanR
 	scanCtx, cancel := context.WithTimeout(ctx, n.timeout)
 	defer cancel()
 
-	var totalSize int64
+	var apparentSize, allocated int64
+	var blocksReported bool
 	var fileCount, dirCount int
 	var largestFile int64
+	var skippedCount, specialCount, errorCount int
+	var symlinksSkipped, symlinksFollowed int
 	var progressCounter int
 
 	start := time.Now()
 	lastProgressUpdate := start
 
-	err := filepath.Walk(path, func(currentPath string, info os.FileInfo, err error) error {
+	err := walkNative(path, func(currentPath string, info fs.FileInfo) error {
 		// Check context cancellation frequently
 		select {
 		case <-scanCtx.Done():
@@ -85,20 +131,17 @@ func (n *NativeMethod) Scan(ctx context.Context, path string) (*interfaces.ScanR
 		default:
 		}
 
-		if err != nil {
-			// Check if it's a permission error
-			if os.IsPermission(err) {
-				// Log permission errors but continue scanning
-				// Don't fail the entire scan for individual file permission issues
-				return nil
-			}
+		if n.excludeHidden && currentPath != path && strings.HasPrefix(info.Name(), ".") {
+			skippedCount++
+			return fs.SkipDir
+		}
 
-			// Check if it's a "no such file" error (broken symlink, etc.)
-			if os.IsNotExist(err) {
-				return nil
+		if info.Mode()&specialFileModes != 0 && n.specialFiles != models.SpecialFilesInclude {
+			if n.specialFiles == models.SpecialFilesCount {
+				specialCount++
+			} else {
+				skippedCount++
 			}
-
-			// For other errors, continue but could log them
 			return nil
 		}
 
@@ -107,7 +150,11 @@ func (n *NativeMethod) Scan(ctx context.Context, path string) (*interfaces.ScanR
 		} else {
 			fileCount++
 			fileSize := info.Size()
-			totalSize += fileSize
+			apparentSize += fileSize
+			if blocks, ok := allocatedSize(info); ok {
+				allocated += blocks
+				blocksReported = blocksReported || blocks > 0
+			}
 
 			if fileSize > largestFile {
 				largestFile = fileSize
@@ -129,6 +176,18 @@ func (n *NativeMethod) Scan(ctx context.Context, path string) (*interfaces.ScanR
 		}
 
 		return nil
+	}, nativeWalkOptions{
+		Symlinks: n.symlinks,
+		OnError: func(string, error) {
+			errorCount++
+		},
+		OnSymlink: func(_ string, followed bool) {
+			if followed {
+				symlinksFollowed++
+			} else {
+				symlinksSkipped++
+			}
+		},
 	})
 
 	duration := time.Since(start)
@@ -139,7 +198,7 @@ func (n *NativeMethod) Scan(ctx context.Context, path string) (*interfaces.ScanR
 			return nil, scanErr
 		}
 
-		// Handle other filepath.Walk errors
+		// Handle other walk errors
 		return nil, &models.ScanError{
 			Method:  "native",
 			Path:    path,
@@ -153,14 +212,27 @@ func (n *NativeMethod) Scan(ctx context.Context, path string) (*interfaces.ScanR
 		}
 	}
 
+	// Filesystems that report no blocks at all are sized by apparent size
+	totalSize := apparentSize
+	if blocksReported {
+		totalSize = allocated
+	}
+
 	return &interfaces.ScanResult{
-		TotalSize:      totalSize,
-		FileCount:      fileCount,
-		DirectoryCount: dirCount,
-		LargestFile:    largestFile,
-		Method:         "native",
-		ScannedAt:      time.Now(),
-		Duration:       duration,
-		FilesystemType: "", // Will be filled by the scanner
+		TotalSize:        totalSize,
+		ApparentSize:     apparentSize,
+		Sparse:           apparentSize > sparseRatio*totalSize && apparentSize-totalSize >= sparseMinExcess,
+		FileCount:        fileCount,
+		DirectoryCount:   dirCount,
+		LargestFile:      largestFile,
+		SkippedCount:     skippedCount,
+		SpecialCount:     specialCount,
+		ErrorCount:       errorCount,
+		SymlinksSkipped:  symlinksSkipped,
+		SymlinksFollowed: symlinksFollowed,
+		Method:           "native",
+		ScannedAt:        time.Now(),
+		Duration:         duration,
+		FilesystemType:   "", // Will be filled by the scanner
 	}, nil
 }
@@ -0,0 +1,349 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNativeMethod_ScanCountsTree(t *testing.T) {
+	root := t.TempDir()
+	// More entries than one read batch, in nested directories
+	for i := 0; i < nativeReadBatch+10; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(root, fmt.Sprintf("file-%03d", i)), make([]byte, i%7), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a", "b", "c"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "b", "c", "big"), make([]byte, 4096), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", "small"), make([]byte, 10), 0o644))
+	// Symlinks are counted but not followed
+	require.NoError(t, os.Symlink(filepath.Join(root, "a"), filepath.Join(root, "link")))
+
+	var wantSize, wantAllocated, wantLargest int64
+	var wantFiles, wantDirs int
+	require.NoError(t, filepath.Walk(root, func(_ string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if info.IsDir() {
+			wantDirs++
+			return nil
+		}
+		wantFiles++
+		wantSize += info.Size()
+		wantAllocated += info.Sys().(*syscall.Stat_t).Blocks * 512
+		if info.Size() > wantLargest {
+			wantLargest = info.Size()
+		}
+		return nil
+	}))
+
+	result, err := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute}).Scan(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, wantAllocated, result.TotalSize)
+	assert.Equal(t, wantSize, result.ApparentSize)
+	assert.False(t, result.Sparse)
+	assert.Equal(t, wantFiles, result.FileCount)
+	assert.Equal(t, wantDirs, result.DirectoryCount)
+	assert.Equal(t, int64(4096), result.LargestFile)
+	assert.Equal(t, wantLargest, result.LargestFile)
+}
+
+// specialFileTree creates a tree with a FIFO, a hidden file and a hidden
+// directory next to 100 bytes of regular files
+func specialFileTree(t *testing.T) string {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data"), make([]byte, 60), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sub", "more"), make([]byte, 40), 0o644))
+	require.NoError(t, syscall.Mkfifo(filepath.Join(root, "sub", "pipe"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".env"), make([]byte, 7), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".cache"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".cache", "blob"), make([]byte, 1000), 0o644))
+	return root
+}
+
+func TestNativeMethod_SpecialFiles(t *testing.T) {
+	root := specialFileTree(t)
+
+	tests := []struct {
+		name        string
+		config      models.ScanConfig
+		wantSize    int64
+		wantFiles   int
+		wantDirs    int
+		wantSkipped int
+		wantSpecial int
+	}{
+		{"skip by default", models.ScanConfig{}, 1107, 4, 3, 1, 0},
+		{"count separately", models.ScanConfig{SpecialFiles: models.SpecialFilesCount}, 1107, 4, 3, 0, 1},
+		{"include", models.ScanConfig{SpecialFiles: models.SpecialFilesInclude}, 1107, 5, 3, 0, 0},
+		{"exclude hidden", models.ScanConfig{ExcludeHidden: true}, 100, 2, 2, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.DefaultTimeout = time.Minute
+			result, err := NewNativeMethod(tt.config).Scan(context.Background(), root)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSize, result.ApparentSize)
+			assert.Equal(t, tt.wantFiles, result.FileCount)
+			assert.Equal(t, tt.wantDirs, result.DirectoryCount)
+			assert.Equal(t, tt.wantSkipped, result.SkippedCount)
+			assert.Equal(t, tt.wantSpecial, result.SpecialCount)
+			assert.Zero(t, result.ErrorCount)
+		})
+	}
+}
+
+func TestNativeMethod_UnreadableDirectory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+
+	root := specialFileTree(t)
+	locked := filepath.Join(root, "locked")
+	require.NoError(t, os.Mkdir(locked, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(locked, "hidden-away"), make([]byte, 500), 0o644))
+	require.NoError(t, os.Chmod(locked, 0))
+	t.Cleanup(func() { os.Chmod(locked, 0o755) })
+
+	result, err := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute}).Scan(context.Background(), root)
+	require.NoError(t, err, "an unreadable directory must not fail the scan")
+	assert.Equal(t, int64(1107), result.ApparentSize)
+	assert.Equal(t, 4, result.FileCount)
+	assert.Equal(t, 4, result.DirectoryCount, "the unreadable directory itself is still counted")
+	assert.Equal(t, 1, result.SkippedCount)
+	assert.Equal(t, 1, result.ErrorCount)
+}
+
+// symlinkTree creates a volume of 150 bytes in files with links into it, a
+// link escaping to a 1000-byte directory that links to itself and back into
+// the volume, a dangling link and a loop of two links
+func symlinkTree(t *testing.T) (root string, linkBytes func(names ...string) int64) {
+	root, outside := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data"), make([]byte, 100), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sub", "more"), make([]byte, 50), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "big"), make([]byte, 1000), 0o644))
+
+	links := map[string]string{
+		filepath.Join(root, "inside"):      "sub",
+		filepath.Join(root, "inside-file"): filepath.Join(root, "data"),
+		filepath.Join(root, "escape"):      outside,
+		filepath.Join(root, "dangling"):    filepath.Join(root, "missing"),
+		filepath.Join(root, "ping"):        "pong",
+		filepath.Join(root, "pong"):        "ping",
+		filepath.Join(outside, "loop"):     outside,
+		filepath.Join(outside, "back"):     root,
+	}
+	for link, target := range links {
+		require.NoError(t, os.Symlink(target, link))
+	}
+
+	// Links counted as entries weigh the length of their target
+	return root, func(names ...string) int64 {
+		var total int64
+		for _, name := range names {
+			path := filepath.Join(root, name)
+			if name == "loop" {
+				path = filepath.Join(outside, name)
+			}
+			total += int64(len(links[path]))
+		}
+		return total
+	}
+}
+
+func TestNativeMethod_Symlinks(t *testing.T) {
+	root, linkBytes := symlinkTree(t)
+
+	tests := []struct {
+		name         string
+		mode         models.SymlinkMode
+		wantSize     int64
+		wantFiles    int
+		wantDirs     int
+		wantSkipped  int
+		wantFollowed int
+	}{
+		{
+			name:        "skip counts every link entry",
+			mode:        "",
+			wantSize:    150 + linkBytes("inside", "inside-file", "escape", "dangling", "ping", "pong"),
+			wantFiles:   8,
+			wantDirs:    2,
+			wantSkipped: 6,
+		},
+		{
+			// Targets inside are counted where they live, not a second time
+			name:         "follow-within stays in the volume",
+			mode:         models.SymlinksFollowWithin,
+			wantSize:     150 + linkBytes("escape", "dangling", "ping", "pong"),
+			wantFiles:    6,
+			wantDirs:     2,
+			wantSkipped:  4,
+			wantFollowed: 2,
+		},
+		{
+			// The escaping directory is walked once: its link to itself is a
+			// cycle and its link back into the volume is already counted
+			name:         "follow-all leaves the volume",
+			mode:         models.SymlinksFollowAll,
+			wantSize:     150 + 1000 + linkBytes("dangling", "ping", "pong", "loop"),
+			wantFiles:    7,
+			wantDirs:     3,
+			wantSkipped:  4,
+			wantFollowed: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute, Symlinks: tt.mode}).Scan(context.Background(), root)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSize, result.ApparentSize)
+			assert.Equal(t, tt.wantFiles, result.FileCount)
+			assert.Equal(t, tt.wantDirs, result.DirectoryCount)
+			assert.Equal(t, tt.wantSkipped, result.SymlinksSkipped)
+			assert.Equal(t, tt.wantFollowed, result.SymlinksFollowed)
+			assert.Zero(t, result.ErrorCount)
+		})
+	}
+}
+
+func TestNativeMethod_SymlinkCycles(t *testing.T) {
+	// Two directories linking to each other and a link to the whole tree
+	// from inside it, reached through a link out of the volume
+	root, outside := t.TempDir(), t.TempDir()
+	a, b := filepath.Join(outside, "a"), filepath.Join(outside, "b")
+	require.NoError(t, os.Mkdir(a, 0o755))
+	require.NoError(t, os.Mkdir(b, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(a, "file"), make([]byte, 10), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(b, "file"), make([]byte, 20), 0o644))
+	require.NoError(t, os.Symlink(b, filepath.Join(a, "to-b")))
+	require.NoError(t, os.Symlink(a, filepath.Join(b, "to-a")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(b, "to-top")))
+	require.NoError(t, os.Symlink(a, filepath.Join(root, "a")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "top")))
+
+	done := make(chan struct{})
+	var result *interfaces.ScanResult
+	var err error
+	go func() {
+		defer close(done)
+		result, err = NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute, Symlinks: models.SymlinksFollowAll}).Scan(context.Background(), root)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("scan did not finish; symlink cycle not broken")
+	}
+
+	require.NoError(t, err)
+	// Which links end up followed depends on directory order, but each
+	// directory is walked once and links not followed count as entries
+	assert.Equal(t, 4, result.DirectoryCount, "root, a, b and the top directory")
+	assert.Equal(t, 2+result.SymlinksSkipped, result.FileCount, "each file is counted once")
+	assert.Equal(t, 5, result.SymlinksSkipped+result.SymlinksFollowed, "links reached")
+	assert.Zero(t, result.ErrorCount)
+}
+
+func TestNativeMethod_SparseFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config"), make([]byte, 4096), 0o644))
+
+	// A VM image of 64 MiB with only its first 8 KiB written
+	image, err := os.Create(filepath.Join(root, "disk.img"))
+	require.NoError(t, err)
+	_, err = image.Write(make([]byte, 8192))
+	require.NoError(t, err)
+	require.NoError(t, image.Truncate(64<<20))
+	require.NoError(t, image.Close())
+
+	info, err := os.Stat(filepath.Join(root, "disk.img"))
+	require.NoError(t, err)
+	if info.Sys().(*syscall.Stat_t).Blocks*512 >= info.Size() {
+		t.Skip("the temp filesystem does not support sparse files")
+	}
+
+	result, err := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute}).Scan(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, int64(64<<20+4096), result.ApparentSize)
+	assert.Less(t, result.TotalSize, result.ApparentSize)
+	assert.Less(t, result.TotalSize, int64(1<<20), "only the written blocks are counted")
+	assert.True(t, result.Sparse)
+	assert.Equal(t, int64(64<<20), result.LargestFile, "the largest file is by apparent size")
+}
+
+func TestNativeMethod_ScanCanceled(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file"), nil, 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute}).Scan(ctx, root)
+	var scanErr *models.ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, models.ErrorCodeScanCanceled, scanErr.Code)
+}
+
+// TestNativeMethod_BoundedMemory scans a directory whose full listing would
+// take several megabytes and checks the live heap stays well below that.
+// The heap is sampled from the progress callback, i.e. mid-walk.
+func TestNativeMethod_BoundedMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates a large tree")
+	}
+
+	const files = 30000
+	root := t.TempDir()
+	dir := filepath.Join(root, "flat")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	// Long names make a full in-memory listing expensive: ~4MB for this tree
+	padding := strings.Repeat("x", 120)
+	for i := 0; i < files; i++ {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("%s-%06d", padding, i)))
+		require.NoError(t, err)
+		f.Close()
+	}
+
+	var baseline, peak uint64
+	sample := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+
+	method := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute}).(*NativeMethod)
+	samples := 0
+	method.SetProgressCallback(func(interfaces.ProgressUpdate) {
+		samples++
+		if heap := sample(); heap > peak {
+			peak = heap
+		}
+	})
+
+	baseline = sample()
+	result, err := method.Scan(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, files, result.FileCount)
+	require.Greater(t, samples, 10, "the heap should be sampled throughout the walk")
+
+	var growth uint64
+	if peak > baseline {
+		growth = peak - baseline
+	}
+	t.Logf("peak live heap growth during scan of %d files: %d bytes", files, growth)
+	assert.Less(t, growth, uint64(1<<20), "native scan memory must not grow with directory size")
+}
@@ -0,0 +1,244 @@
+package scanner
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/mantonx/volumeviz/internal/core/models"
+)
+
+// nativeReadBatch is how many directory entries the native walk reads at once.
+// The walk holds at most one batch per directory on the current path, so its
+// memory is bounded by tree depth times this batch, not by the number of files.
+const nativeReadBatch = 256
+
+// nativeWalkFrame is a directory being read by walkNative
+type nativeWalkFrame struct {
+	path    string
+	dir     *os.File
+	entries []fs.DirEntry
+	next    int
+	// done is set once a read fails, so the failure is reported only once
+	done bool
+	// outside is set for directories reached through a symlink out of the root
+	outside bool
+}
+
+// fileID identifies a file or directory across paths and links
+type fileID struct {
+	dev, ino uint64
+}
+
+// fileIDOf returns the identity of the file info describes
+func fileIDOf(info fs.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(stat.Dev), ino: stat.Ino}, true
+}
+
+// nativeWalkOptions controls how walkNative treats symlinks and unreadable entries
+type nativeWalkOptions struct {
+	// Symlinks is whether links are followed; empty follows none
+	Symlinks models.SymlinkMode
+	// OnError is passed the entries that cannot be read; optional
+	OnError func(path string, err error)
+	// OnSymlink is told whether each symlink was followed; optional
+	OnSymlink func(path string, followed bool)
+}
+
+// symlinkWalk resolves the symlinks met by one walk and remembers what
+// following them reached, so a target is counted once and cycles end
+type symlinkWalk struct {
+	mode     models.SymlinkMode
+	realRoot string
+	rootID   fileID
+	// visited holds the targets of followed links and every directory
+	// below them; it only grows while following links out of the root
+	visited map[fileID]bool
+}
+
+// symlinkAction is what the walk does with a symlink
+type symlinkAction int
+
+const (
+	symlinkCountLink   symlinkAction = iota // Count the link entry and move on
+	symlinkCountedHere                      // Followed to data counted where it lives
+	symlinkWalkTarget                       // Count the target in place of the link
+)
+
+// resolve decides what to do with the symlink at path. Targets inside the
+// root are counted where they live, so following never counts them twice;
+// targets outside are only walked by follow-all, once each. Dangling links,
+// link loops and targets seen before are counted as link entries.
+func (w *symlinkWalk) resolve(path string) (action symlinkAction, target string, info fs.FileInfo) {
+	if w.mode != models.SymlinksFollowWithin && w.mode != models.SymlinksFollowAll {
+		return symlinkCountLink, "", nil
+	}
+
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		// Dangling links and loops of links (ELOOP) cannot be followed
+		return symlinkCountLink, "", nil
+	}
+	if target == w.realRoot || strings.HasPrefix(target, w.realRoot+string(filepath.Separator)) {
+		return symlinkCountedHere, target, nil
+	}
+	if w.mode != models.SymlinksFollowAll {
+		return symlinkCountLink, "", nil
+	}
+
+	info, err = os.Stat(target)
+	if err != nil {
+		return symlinkCountLink, "", nil
+	}
+	if w.seen(info) {
+		return symlinkCountLink, "", nil
+	}
+	return symlinkWalkTarget, target, info
+}
+
+// seen reports whether info was reached through a link before, marking it
+// reached. The root counts as reached, so links leading back into it from
+// outside end there.
+func (w *symlinkWalk) seen(info fs.FileInfo) bool {
+	id, ok := fileIDOf(info)
+	if !ok {
+		return false
+	}
+	if id == w.rootID || w.visited[id] {
+		return true
+	}
+	w.visited[id] = true
+	return false
+}
+
+// walkNative calls fn for root and every entry below it, depth first and in
+// directory order. Unlike filepath.Walk it never reads or sorts a whole
+// directory listing, so a directory with millions of files costs no more
+// memory than a small one. Symlinks are followed as opts.Symlinks says, with
+// fn given the info of a followed link's target: directories reached through
+// a link out of the root are remembered so each is walked only once, which
+// also breaks symlink cycles. Entries that cannot be read are skipped and
+// passed to opts.OnError. fn should check for cancellation; returning
+// fs.SkipDir skips the entry and, for a directory, everything below it,
+// while any other error ends the walk and is returned.
+func walkNative(root string, fn func(path string, info fs.FileInfo) error, opts nativeWalkOptions) error {
+	reportError := func(path string, err error) {
+		if opts.OnError != nil {
+			opts.OnError(path, err)
+		}
+	}
+	reportSymlink := func(path string, followed bool) {
+		if opts.OnSymlink != nil {
+			opts.OnSymlink(path, followed)
+		}
+	}
+
+	info, err := os.Lstat(root)
+	if err != nil {
+		reportError(root, err)
+		return nil
+	}
+	links := &symlinkWalk{mode: opts.Symlinks, realRoot: root, visited: make(map[fileID]bool)}
+	if realRoot, err := filepath.EvalSymlinks(root); err == nil {
+		links.realRoot = realRoot
+	}
+	links.rootID, _ = fileIDOf(info)
+	if err := fn(root, info); err != nil {
+		if errors.Is(err, fs.SkipDir) {
+			return nil
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	var stack []*nativeWalkFrame
+	defer func() {
+		for _, frame := range stack {
+			frame.dir.Close()
+		}
+	}()
+
+	push := func(path string, outside bool) {
+		dir, err := os.Open(path)
+		if err != nil {
+			// Unreadable directories are counted but not descended into
+			reportError(path, err)
+			return
+		}
+		stack = append(stack, &nativeWalkFrame{path: path, dir: dir, outside: outside})
+	}
+	push(root, false)
+
+	for len(stack) > 0 {
+		frame := stack[len(stack)-1]
+		if frame.next >= len(frame.entries) {
+			// An empty batch is the end of the directory or a read error;
+			// entries returned alongside an error are still visited
+			var entries []fs.DirEntry
+			if !frame.done {
+				var err error
+				entries, err = frame.dir.ReadDir(nativeReadBatch)
+				if err != nil && !errors.Is(err, io.EOF) {
+					reportError(frame.path, err)
+					frame.done = true
+				}
+			}
+			if len(entries) == 0 {
+				frame.dir.Close()
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			frame.entries, frame.next = entries, 0
+		}
+
+		entry := frame.entries[frame.next]
+		frame.next++
+
+		path := filepath.Join(frame.path, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			// Entries removed since the directory was read are not errors
+			if !errors.Is(err, fs.ErrNotExist) {
+				reportError(path, err)
+			}
+			continue
+		}
+
+		descend, outside := path, frame.outside
+		if info.Mode()&fs.ModeSymlink != 0 {
+			action, target, targetInfo := links.resolve(path)
+			reportSymlink(path, action != symlinkCountLink)
+			switch action {
+			case symlinkCountedHere:
+				continue
+			case symlinkWalkTarget:
+				info, descend, outside = targetInfo, target, true
+			}
+		} else if outside && info.IsDir() && links.seen(info) {
+			// Reached before through another link, or the root itself
+			continue
+		}
+
+		if err := fn(path, info); err != nil {
+			if errors.Is(err, fs.SkipDir) {
+				continue
+			}
+			return err
+		}
+		if info.IsDir() {
+			push(descend, outside)
+		}
+	}
+
+	return nil
+}
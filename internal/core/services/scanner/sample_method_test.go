@@ -0,0 +1,153 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildSyntheticTree creates dirCount subdirectories with a varying number of files
+// and returns the exact total size in bytes
+func buildSyntheticTree(t *testing.T, root string, dirCount int) int64 {
+	rng := rand.New(rand.NewSource(42))
+	var total int64
+
+	// A few files at the root are always counted exactly
+	for i := 0; i < 3; i++ {
+		data := make([]byte, 1000+i)
+		require.NoError(t, os.WriteFile(filepath.Join(root, fmt.Sprintf("root-%d.bin", i)), data, 0644))
+		total += int64(len(data))
+	}
+
+	for d := 0; d < dirCount; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("shard-%03d", d), "nested")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		files := 4 + rng.Intn(4)
+		for f := 0; f < files; f++ {
+			data := make([]byte, 2000+rng.Intn(2000))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%d.bin", f)), data, 0644))
+			total += int64(len(data))
+		}
+	}
+
+	return total
+}
+
+func newTestSampleMethod(sampleSize int, seed int64) *SampleMethod {
+	method := NewSampleMethod(models.ScanConfig{
+		DefaultTimeout: time.Minute,
+		SampleSize:     sampleSize,
+	}).(*SampleMethod)
+	method.rng = rand.New(rand.NewSource(seed))
+	return method
+}
+
+func TestSampleMethod_Name(t *testing.T) {
+	method := NewSampleMethod(models.ScanConfig{DefaultTimeout: time.Minute})
+
+	assert.Equal(t, "sample", method.Name())
+	assert.True(t, method.Available())
+	assert.False(t, method.SupportsProgress())
+	assert.Equal(t, defaultSampleSize, method.(*SampleMethod).sampleSize)
+}
+
+func TestSampleMethod_EstimateWithinMargin(t *testing.T) {
+	root := t.TempDir()
+	actual := buildSyntheticTree(t, root, 200)
+
+	// Several seeds so the check does not depend on one lucky draw
+	for seed := int64(1); seed <= 5; seed++ {
+		method := newTestSampleMethod(20, seed)
+
+		result, err := method.Scan(context.Background(), root)
+		require.NoError(t, err)
+
+		assert.True(t, result.Estimated)
+		assert.Equal(t, 20, result.SampleSize)
+		assert.Equal(t, "sample", result.Method)
+		assert.Greater(t, result.MarginOfError, int64(0))
+
+		diff := result.TotalSize - actual
+		if diff < 0 {
+			diff = -diff
+		}
+		assert.LessOrEqualf(t, diff, result.MarginOfError,
+			"seed %d: estimate %d outside ±%d of actual %d", seed, result.TotalSize, result.MarginOfError, actual)
+
+		// The stated margin should also be tight enough to be useful
+		assert.Less(t, float64(result.MarginOfError)/float64(actual), 0.15)
+	}
+}
+
+func TestSampleMethod_ConcurrentEstimates(t *testing.T) {
+	root := t.TempDir()
+	buildSyntheticTree(t, root, 50)
+
+	// One method serves every estimate; run with -race to check they share it safely
+	method := newTestSampleMethod(10, 1)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := method.Scan(context.Background(), root)
+			assert.NoError(t, err)
+			assert.Equal(t, 10, result.SampleSize)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSampleMethod_SmallTreeIsExact(t *testing.T) {
+	root := t.TempDir()
+	actual := buildSyntheticTree(t, root, 5)
+
+	result, err := newTestSampleMethod(20, 1).Scan(context.Background(), root)
+	require.NoError(t, err)
+
+	// Every subtree fits in the sample, so nothing is extrapolated
+	assert.False(t, result.Estimated)
+	assert.Equal(t, int64(0), result.MarginOfError)
+	assert.Equal(t, actual, result.TotalSize)
+
+	// Cross-check against a full native walk, which sums the same apparent sizes
+	native, err := NewNativeMethod(models.ScanConfig{DefaultTimeout: time.Minute}).Scan(context.Background(), root)
+	require.NoError(t, err)
+	assert.Equal(t, native.ApparentSize, result.TotalSize)
+	assert.Equal(t, native.FileCount, result.FileCount)
+	assert.Equal(t, native.DirectoryCount, result.DirectoryCount)
+}
+
+func TestSampleMethod_MissingPath(t *testing.T) {
+	_, err := newTestSampleMethod(20, 1).Scan(context.Background(), "/nonexistent/sample/path")
+
+	var scanErr *models.ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, models.ErrorCodePathNotFound, scanErr.Code)
+}
+
+func TestSampleMethod_Canceled(t *testing.T) {
+	root := t.TempDir()
+	buildSyntheticTree(t, root, 50)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newTestSampleMethod(20, 1).Scan(ctx, root)
+
+	var scanErr *models.ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, models.ErrorCodeScanCanceled, scanErr.Code)
+}
+
+var _ interfaces.ScanMethod = (*SampleMethod)(nil)
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+)
+
+// scanFlight is one in-flight scan shared by every caller asking for it
+type scanFlight struct {
+	done    chan struct{}
+	result  *interfaces.ScanResult
+	err     error
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// scanFlightGroup collapses concurrent scans with the same key into a single
+// scan whose result every caller receives. Unlike the cache it only covers
+// scans still running, so duplicate work is avoided even on a cold cache.
+// The shared scan runs detached from any one caller and is canceled once
+// every caller waiting for it has given up.
+type scanFlightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*scanFlight
+}
+
+// newScanFlightGroup creates an empty scan flight group
+func newScanFlightGroup() *scanFlightGroup {
+	return &scanFlightGroup{flights: make(map[string]*scanFlight)}
+}
+
+// do runs scan for key unless a scan for key is already in flight, in which
+// case it waits for that scan instead. shared reports whether the result came
+// from a scan started by another caller.
+func (g *scanFlightGroup) do(ctx context.Context, key, volumeID string, scan func(ctx context.Context) (*interfaces.ScanResult, error)) (result *interfaces.ScanResult, shared bool, err error) {
+	g.mu.Lock()
+	flight, shared := g.flights[key]
+	if !shared {
+		// Keep the caller's values, such as the preferred method, but not its cancellation
+		scanCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		flight = &scanFlight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = flight
+
+		go func() {
+			defer cancel()
+			flight.result, flight.err = scan(scanCtx)
+
+			g.mu.Lock()
+			if g.flights[key] == flight {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			close(flight.done)
+		}()
+	}
+	flight.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-flight.done:
+		return flight.result, shared, flight.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		flight.waiters--
+		if flight.waiters == 0 {
+			// Nobody wants the result any more; later callers start afresh
+			flight.cancel()
+			if g.flights[key] == flight {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+
+		return nil, shared, &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeScanCanceled,
+			Message:  "scan canceled while waiting for result",
+			Err:      ctx.Err(),
+		}
+	}
+}
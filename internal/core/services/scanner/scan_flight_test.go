@@ -0,0 +1,249 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/mantonx/volumeviz/internal/core/services/cache"
+	"github.com/mantonx/volumeviz/internal/core/services/metrics"
+	"github.com/mantonx/volumeviz/internal/mocks"
+	"github.com/mantonx/volumeviz/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingMethod is a scan method that counts its scans and holds each one
+// until released
+type blockingMethod struct {
+	scans   atomic.Int32
+	release chan struct{}
+}
+
+func (m *blockingMethod) Name() string                           { return "blocking" }
+func (m *blockingMethod) Available() bool                        { return true }
+func (m *blockingMethod) EstimatedDuration(string) time.Duration { return time.Second }
+func (m *blockingMethod) SupportsProgress() bool                 { return false }
+
+func (m *blockingMethod) Scan(ctx context.Context, path string) (*interfaces.ScanResult, error) {
+	m.scans.Add(1)
+	select {
+	case <-m.release:
+		return &interfaces.ScanResult{TotalSize: 4096, FileCount: 1, Method: m.Name(), ScannedAt: time.Now()}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lockedDockerClient serializes volume lookups, which the mock counts unguarded
+type lockedDockerClient struct {
+	mocks.MockDockerClient
+	mu sync.Mutex
+}
+
+func (c *lockedDockerClient) InspectVolume(ctx context.Context, volumeID string) (volume.Volume, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.MockDockerClient.InspectVolume(ctx, volumeID)
+}
+
+// newFlightTestScanner creates a scanner whose only method is a blockingMethod
+// and whose volumes all resolve to a temporary directory
+func newFlightTestScanner(t *testing.T) (*VolumeScanner, *blockingMethod) {
+	mountpoint := t.TempDir()
+	docker := services.NewDockerServiceWithClient(&lockedDockerClient{MockDockerClient: mocks.MockDockerClient{
+		InspectVolumeFunc: func(ctx context.Context, volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local", Mountpoint: mountpoint}, nil
+		},
+	}})
+
+	config := models.DefaultConfig()
+	config.Scanning.DefaultTimeout = time.Minute
+	vs := NewVolumeScanner(docker, cache.NewMemoryCache(10), metrics.NewSimpleMetricsCollector(nil), nil, config).(*VolumeScanner)
+
+	method := &blockingMethod{release: make(chan struct{})}
+	vs.methods = []interfaces.ScanMethod{method}
+	return vs, method
+}
+
+// waitForWaiters waits until n callers are waiting for the scan with key
+func waitForWaiters(t *testing.T, vs *VolumeScanner, key string, n int) {
+	require.Eventually(t, func() bool {
+		vs.flights.mu.Lock()
+		defer vs.flights.mu.Unlock()
+		flight, ok := vs.flights.flights[key]
+		return ok && flight.waiters == n
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestVolumeScanner_ConcurrentScansShareOneFlight(t *testing.T) {
+	vs, method := newFlightTestScanner(t)
+
+	const callers = 8
+	results := make([]*interfaces.ScanResult, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = vs.ScanVolume(context.Background(), "app-data")
+		}(i)
+	}
+
+	waitForWaiters(t, vs, "app-data", callers)
+	close(method.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), method.scans.Load(), "the scan method should run once for all callers")
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Same(t, results[0], results[i])
+	}
+	assert.Equal(t, int64(4096), results[0].TotalSize)
+
+	// Finished scans leave the group; later calls are served from the cache
+	assert.Empty(t, vs.flights.flights)
+	_, err := vs.ScanVolume(context.Background(), "app-data")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), method.scans.Load())
+}
+
+func TestVolumeScanner_FlightSurvivesCanceledCaller(t *testing.T) {
+	vs, method := newFlightTestScanner(t)
+
+	// The caller that started the scan gives up; the other still gets the result
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := vs.ScanVolume(ctx, "app-data")
+		firstErr <- err
+	}()
+	waitForWaiters(t, vs, "app-data", 1)
+
+	second := make(chan *interfaces.ScanResult, 1)
+	go func() {
+		result, err := vs.ScanVolume(context.Background(), "app-data")
+		assert.NoError(t, err)
+		second <- result
+	}()
+	waitForWaiters(t, vs, "app-data", 2)
+
+	cancel()
+	assert.Equal(t, models.ErrorCodeScanCanceled, models.ClassifyScanError(<-firstErr))
+
+	close(method.release)
+	result := <-second
+	require.NotNil(t, result)
+	assert.Equal(t, int64(4096), result.TotalSize)
+	assert.Equal(t, int32(1), method.scans.Load())
+}
+
+func TestVolumeScanner_FlightCanceledWithoutWaiters(t *testing.T) {
+	vs, method := newFlightTestScanner(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := vs.ScanVolume(ctx, "app-data")
+		done <- err
+	}()
+	waitForWaiters(t, vs, "app-data", 1)
+	cancel()
+	assert.Equal(t, models.ErrorCodeScanCanceled, models.ClassifyScanError(<-done))
+
+	// The abandoned scan is canceled and a new caller starts a fresh one
+	close(method.release)
+	result, err := vs.ScanVolume(context.Background(), "app-data")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4096), result.TotalSize)
+	assert.Equal(t, int32(2), method.scans.Load())
+}
+
+func TestVolumeScanner_FlightsKeyedByPreferredMethod(t *testing.T) {
+	vs, method := newFlightTestScanner(t)
+
+	var wg sync.WaitGroup
+	for _, ctx := range []context.Context{
+		context.Background(),
+		interfaces.WithPreferredMethod(context.Background(), "du"),
+	} {
+		wg.Add(1)
+		go func(ctx context.Context) {
+			defer wg.Done()
+			_, err := vs.ScanVolume(ctx, "app-data")
+			assert.NoError(t, err)
+		}(ctx)
+	}
+
+	waitForWaiters(t, vs, "app-data", 1)
+	waitForWaiters(t, vs, "app-data|du", 1)
+	close(method.release)
+	wg.Wait()
+	assert.Equal(t, int32(2), method.scans.Load())
+}
+
+// vanishingMethod is a scan method that fails the way a walk of a volume
+// removed under it does
+type vanishingMethod struct{}
+
+func (vanishingMethod) Name() string                           { return "vanishing" }
+func (vanishingMethod) Available() bool                        { return true }
+func (vanishingMethod) EstimatedDuration(string) time.Duration { return time.Second }
+func (vanishingMethod) SupportsProgress() bool                 { return false }
+
+func (vanishingMethod) Scan(ctx context.Context, path string) (*interfaces.ScanResult, error) {
+	return nil, &models.ScanError{
+		Code:    models.ErrorCodePathNotFound,
+		Message: "failed to read volume root",
+		Err:     &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist},
+	}
+}
+
+// recheckKey marks the context of a scan in TestVolumeScanner_VolumeRemovedDuringScan
+type recheckKey struct{}
+
+func TestVolumeScanner_VolumeRemovedDuringScan(t *testing.T) {
+	mountpoint := t.TempDir()
+	lookups := 0 // of "gone", serialized by lockedDockerClient
+	docker := services.NewDockerServiceWithClient(&lockedDockerClient{MockDockerClient: mocks.MockDockerClient{
+		InspectVolumeFunc: func(ctx context.Context, volumeID string) (volume.Volume, error) {
+			if volumeID == "gone" {
+				// Resolved, then removed before the scan finishes
+				lookups++
+				if lookups > 1 {
+					// The recheck runs under the scan's context
+					assert.Equal(t, "scan", ctx.Value(recheckKey{}))
+					_, bounded := ctx.Deadline()
+					assert.True(t, bounded)
+					return volume.Volume{}, errdefs.NotFound(errors.New("Error response from daemon: get gone: no such volume"))
+				}
+			}
+			return volume.Volume{Name: volumeID, Driver: "local", Mountpoint: mountpoint}, nil
+		},
+	}})
+
+	config := models.DefaultConfig()
+	config.Scanning.DefaultTimeout = time.Minute
+	vs := NewVolumeScanner(docker, cache.NewMemoryCache(10), metrics.NewSimpleMetricsCollector(nil), nil, config).(*VolumeScanner)
+	vs.methods = []interfaces.ScanMethod{vanishingMethod{}}
+
+	_, err := vs.ScanVolume(context.WithValue(context.Background(), recheckKey{}, "scan"), "gone")
+	require.Error(t, err)
+	assert.Equal(t, models.ErrorCodeVolumeRemoved, models.ClassifyScanError(err))
+	assert.True(t, models.VolumeRemoved(err))
+
+	// A volume still present whose scan fails is a failure as before
+	_, err = vs.ScanVolume(context.Background(), "present")
+	require.Error(t, err)
+	assert.Equal(t, models.ErrorCodePathNotFound, models.ClassifyScanError(err))
+	assert.False(t, models.VolumeRemoved(err))
+}
@@ -4,15 +4,21 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
 	"github.com/mantonx/volumeviz/internal/core/models"
+	dockermodels "github.com/mantonx/volumeviz/internal/models"
 	"github.com/mantonx/volumeviz/internal/services"
 	"github.com/mantonx/volumeviz/internal/utils"
 )
@@ -21,6 +27,8 @@ import (
 // Uses multiple scanning methods with intelligent fallback
 type VolumeScanner struct {
 	methods       []interfaces.ScanMethod
+	sampler       interfaces.ScanMethod // Estimate-only method, never part of the fallback chain
+	external      *ExternalSizer        // Sizes volumes without a local path; nil when not configured
 	cache         interfaces.Cache
 	metrics       interfaces.MetricsCollector
 	logger        *log.Logger
@@ -30,6 +38,10 @@ type VolumeScanner struct {
 	activeScans   map[string]*interfaces.ScanProgress // Track active scans by scan ID
 	volumeToScan  map[string]string                   // Map volume ID to active scan ID
 	scanMutex     sync.RWMutex                        // Protect scan maps
+	flights       *scanFlightGroup                    // Shares in-flight synchronous scans
+	pathRewrites  []config.PathRewrite                // Map host paths to the paths visible here, first match wins
+	fsNames       map[uint32]string                   // Configured names of filesystems by statfs magic number
+	sizeHistory   interfaces.SizeHistory              // Recent scanned sizes for change-aware cache TTLs; optional
 }
 
 // NewVolumeScanner creates a new volume scanner instance
@@ -41,15 +53,19 @@ func NewVolumeScanner(
 	logger *log.Logger,
 	config models.Config,
 ) interfaces.VolumeScanner {
-	// Initialize scan methods in order of preference
+	// Initialize scan methods in order of preference, checking their
+	// availability now and then rather than on every scan
+	recheck := config.Scanning.AvailabilityRecheck
 	methods := []interfaces.ScanMethod{
-		NewDiskusMethod(config.Scanning),
-		NewDuMethod(config.Scanning),
-		NewNativeMethod(config.Scanning),
+		newCheckedMethod(NewDiskusMethod(config.Scanning), recheck, metrics),
+		newCheckedMethod(NewDuMethod(config.Scanning), recheck, metrics),
+		newCheckedMethod(NewNativeMethod(config.Scanning), recheck, metrics),
 	}
 
 	return &VolumeScanner{
 		methods:       methods,
+		sampler:       newCheckedMethod(NewSampleMethod(config.Scanning), recheck, metrics),
+		external:      NewExternalSizer(config.Scanning),
 		cache:         cache,
 		metrics:       metrics,
 		logger:        logger,
@@ -58,10 +74,34 @@ func NewVolumeScanner(
 		config:        config,
 		activeScans:   make(map[string]*interfaces.ScanProgress),
 		volumeToScan:  make(map[string]string),
+		flights:       newScanFlightGroup(),
+		pathRewrites:  parsePathRewrites(config.Scanning.PathRewrites, logger),
+		fsNames:       parseFilesystemNames(config.Scanning.FilesystemNames, logger),
 	}
 }
 
+// parseFilesystemNames parses the configured filesystem names; like path
+// rewrites they are validated at startup
+func parseFilesystemNames(entries []string, logger *log.Logger) map[uint32]string {
+	names, err := config.ParseFilesystemNames(entries)
+	if err != nil && logger != nil {
+		logger.Printf("[WARN] Ignoring filesystem names: %v", err)
+	}
+	return names
+}
+
+// parsePathRewrites parses the configured path rewrite rules; the
+// configuration is validated at startup, so bad rules are not expected
+func parsePathRewrites(rules []string, logger *log.Logger) []config.PathRewrite {
+	rewrites, err := config.ParsePathRewrites(rules)
+	if err != nil && logger != nil {
+		logger.Printf("[WARN] Ignoring path rewrites: %v", err)
+	}
+	return rewrites
+}
+
 // ScanVolume scans a volume and returns size information
+// Concurrent calls for the same volume and preferred method share one scan
 func (vs *VolumeScanner) ScanVolume(ctx context.Context, volumeID string) (*interfaces.ScanResult, error) {
 	// Check cache first
 	if result := vs.cache.Get(volumeID); result != nil {
@@ -74,6 +114,27 @@ func (vs *VolumeScanner) ScanVolume(ctx context.Context, volumeID string) (*inte
 
 	vs.metrics.CacheMiss(volumeID)
 
+	// A caller asking for a specific method must not get another method's
+	// result, nor one barring a method the result of one that allows it
+	key := volumeID
+	if preferred := interfaces.PreferredMethod(ctx); preferred != "" {
+		key += "|" + preferred
+	}
+	if denied := interfaces.DeniedMethods(ctx); len(denied) > 0 {
+		key += "|-" + strings.Join(denied, ",")
+	}
+
+	result, shared, err := vs.flights.do(ctx, key, volumeID, func(ctx context.Context) (*interfaces.ScanResult, error) {
+		return vs.scanVolume(ctx, volumeID)
+	})
+	if shared && vs.logger != nil {
+		vs.logger.Printf("Joined in-flight scan of volume %s", volumeID)
+	}
+	return result, err
+}
+
+// scanVolume runs the fallback chain of scan methods for a volume
+func (vs *VolumeScanner) scanVolume(ctx context.Context, volumeID string) (*interfaces.ScanResult, error) {
 	// Acquire semaphore for concurrent scan limiting
 	select {
 	case vs.semaphore <- struct{}{}:
@@ -92,6 +153,10 @@ func (vs *VolumeScanner) ScanVolume(ctx context.Context, volumeID string) (*inte
 
 	// Get volume path from Docker
 	volumePath, err := vs.getVolumePath(volumeID)
+	var unresolved *unresolvedMountpointError
+	if errors.As(err, &unresolved) {
+		return vs.scanUnresolved(ctx, volumeID, unresolved)
+	}
 	if err != nil {
 		return nil, &models.ScanError{
 			VolumeID: volumeID,
@@ -106,7 +171,8 @@ func (vs *VolumeScanner) ScanVolume(ctx context.Context, volumeID string) (*inte
 
 	// Try scan methods in order of preference
 	var lastErr error
-	for _, method := range vs.methods {
+	methods := vs.methodsFor(ctx)
+	for _, method := range methods {
 		if !method.Available() {
 			if vs.logger != nil {
 				vs.logger.Printf("Scan method %s not available for volume %s",
@@ -131,26 +197,28 @@ func (vs *VolumeScanner) ScanVolume(ctx context.Context, volumeID string) (*inte
 		}
 
 		// Cache successful result
-		cacheTTL := vs.calculateCacheTTL(result)
+		cacheTTL := vs.calculateCacheTTL(ctx, result)
 		if err := vs.cache.Set(volumeID, result, cacheTTL); err != nil && vs.logger != nil {
 			vs.logger.Printf("Failed to cache scan result for volume %s: %v", volumeID, err)
 		}
 
-		vs.metrics.ScanCompleted(volumeID, method.Name(), result.Duration, result.TotalSize)
-
-		// Get volume metadata for enhanced metrics
+		// Get volume metadata for enhanced metrics; reported first so the scan
+		// observations are labeled with the volume's filesystem
 		if volume, err := vs.dockerService.GetVolume(context.Background(), volumeID); err == nil {
 			vs.metrics.UpdateVolumeMetrics(
 				volumeID,
 				volume.Name,
 				volume.Driver,
 				result.FilesystemType,
+				volume.Labels,
 				result.TotalSize,
 				result.FileCount,
 				method.Name(),
 			)
 		}
 
+		vs.metrics.ScanCompleted(volumeID, method.Name(), result.Duration, result.TotalSize)
+
 		if vs.logger != nil {
 			vs.logger.Printf("Volume scan completed: volume=%s method=%s size=%d duration=%v",
 				volumeID, method.Name(), result.TotalSize, result.Duration)
@@ -159,17 +227,107 @@ func (vs *VolumeScanner) ScanVolume(ctx context.Context, volumeID string) (*inte
 		return result, nil
 	}
 
+	// A volume removed while it was scanned fails every method; that is
+	// reported as the removal rather than as a scan failure
+	if lastErr != nil && ctx.Err() == nil {
+		if removedErr := vs.volumeRemoved(ctx, volumeID); removedErr != nil {
+			return nil, &models.ScanError{
+				VolumeID: volumeID,
+				Code:     models.ErrorCodeVolumeRemoved,
+				Message:  "volume removed during scan",
+				Err:      removedErr,
+				Context: map[string]any{
+					"volume_path": volumePath,
+					"last_error":  lastErr.Error(),
+				},
+			}
+		}
+	}
+
 	// All methods failed
-	return nil, &models.ScanError{
+	scanErr := &models.ScanError{
 		VolumeID: volumeID,
 		Code:     models.ErrorCodeAllMethodsFailed,
 		Message:  "all scan methods failed",
 		Err:      lastErr,
 		Context: map[string]any{
-			"attempted_methods": vs.getMethodNames(),
+			"attempted_methods": methodNames(methods),
 			"volume_path":       volumePath,
 		},
 	}
+	if denied := interfaces.DeniedMethods(ctx); len(denied) > 0 {
+		scanErr.Context["denied_methods"] = denied
+		if len(methods) == 0 {
+			scanErr.Message = "every scan method is denied for this volume"
+		}
+	}
+	return nil, scanErr
+}
+
+// volumeRecheckTimeout bounds the lookup telling a removed volume from a
+// failed scan, within what is left of the scan's own deadline
+const volumeRecheckTimeout = 5 * time.Second
+
+// volumeRemoved looks volumeID up again after a failed scan, returning
+// Docker's not-found error if the volume is gone
+func (vs *VolumeScanner) volumeRemoved(ctx context.Context, volumeID string) error {
+	ctx, cancel := context.WithTimeout(ctx, volumeRecheckTimeout)
+	defer cancel()
+	_, err := vs.dockerService.GetVolume(ctx, volumeID)
+	if err != nil && cerrdefs.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// EstimateVolumeSize returns a sampled size estimate for a volume
+// A cached full scan is returned as-is since it is strictly better than an estimate
+func (vs *VolumeScanner) EstimateVolumeSize(ctx context.Context, volumeID string) (*interfaces.ScanResult, error) {
+	if result := vs.cache.Get(volumeID); result != nil {
+		vs.metrics.CacheHit(volumeID)
+		return result, nil
+	}
+
+	cacheKey := estimateCacheKey(volumeID)
+	if result := vs.cache.Get(cacheKey); result != nil {
+		vs.metrics.CacheHit(cacheKey)
+		return result, nil
+	}
+	vs.metrics.CacheMiss(cacheKey)
+
+	volumePath, err := vs.getVolumePath(volumeID)
+	if err != nil {
+		return nil, &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeVolumePathError,
+			Message:  "failed to resolve volume path",
+			Err:      err,
+			Context: map[string]any{
+				"volume_id": volumeID,
+			},
+		}
+	}
+
+	result, err := vs.scanWithMethod(ctx, vs.sampler, volumeID, volumePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vs.cache.Set(cacheKey, result, vs.calculateCacheTTL(ctx, result)); err != nil && vs.logger != nil {
+		vs.logger.Printf("Failed to cache size estimate for volume %s: %v", volumeID, err)
+	}
+
+	if vs.logger != nil {
+		vs.logger.Printf("Volume size estimated: volume=%s size=%d margin=%d samples=%d duration=%v",
+			volumeID, result.TotalSize, result.MarginOfError, result.SampleSize, result.Duration)
+	}
+
+	return result, nil
+}
+
+// estimateCacheKey keeps sampled estimates separate from full scan results in the cache
+func estimateCacheKey(volumeID string) string {
+	return "estimate:" + volumeID
 }
 
 // ScanVolumeAsync starts an async scan and returns a scan ID
@@ -202,7 +360,8 @@ func (vs *VolumeScanner) ScanVolumeAsync(ctx context.Context, volumeID string) (
 		progress.Status = models.ScanStatusRunning
 		vs.scanMutex.Unlock()
 
-		result, err := vs.ScanVolume(context.Background(), volumeID)
+		// Keep the caller's preferred and denied methods, but not its deadline
+		result, err := vs.ScanVolume(context.WithoutCancel(ctx), volumeID)
 
 		vs.scanMutex.Lock()
 		defer vs.scanMutex.Unlock()
@@ -291,23 +450,42 @@ func (vs *VolumeScanner) GetAvailableMethods() []interfaces.MethodInfo {
 		}
 
 		methods[i] = interfaces.MethodInfo{
-			Name:        method.Name(),
-			Available:   method.Available(),
-			Description: fmt.Sprintf("%s-based volume scanning", method.Name()),
-			Performance: performance,
-			Accuracy:    accuracy,
-			Features:    features,
+			Name:          method.Name(),
+			Available:     method.Available(),
+			LastCheckedAt: lastChecked(method),
+			Description:   fmt.Sprintf("%s-based volume scanning", method.Name()),
+			Performance:   performance,
+			Accuracy:      accuracy,
+			Features:      features,
 		}
 	}
 
+	methods = append(methods, interfaces.MethodInfo{
+		Name:          vs.sampler.Name(),
+		Available:     vs.sampler.Available(),
+		LastCheckedAt: lastChecked(vs.sampler),
+		Description:   "sampled size estimate extrapolated from a random subset of subdirectories",
+		Performance:   "very_fast",
+		Accuracy:      "estimated",
+		Features:      []string{"estimate_only", "margin_of_error", "always_available"},
+	})
+
 	return methods
 }
 
 // ClearCache removes a volume from cache
 func (vs *VolumeScanner) ClearCache(volumeID string) error {
+	if err := vs.cache.Delete(estimateCacheKey(volumeID)); err != nil && vs.logger != nil {
+		vs.logger.Printf("Failed to clear size estimate cache for volume %s: %v", volumeID, err)
+	}
 	return vs.cache.Delete(volumeID)
 }
 
+// CachedResult returns the full scan result cached for a volume, or nil
+func (vs *VolumeScanner) CachedResult(volumeID string) *interfaces.ScanResult {
+	return vs.cache.Get(volumeID)
+}
+
 // scanWithMethod executes a scan using a specific method
 func (vs *VolumeScanner) scanWithMethod(
 	ctx context.Context,
@@ -357,6 +535,7 @@ func (vs *VolumeScanner) scanWithMethod(
 	result.VolumeID = volumeID
 	result.Duration = duration
 	result.FilesystemType = vs.detectFilesystemType(path)
+	result.Accuracy = scanAccuracy(result)
 
 	if err := vs.validateResult(result); err != nil {
 		return nil, &models.ScanError{
@@ -384,24 +563,79 @@ func (vs *VolumeScanner) getVolumePath(volumeID string) (string, error) {
 	if err != nil {
 		return "", utils.WrapError(err, "failed to get volume info")
 	}
+	return vs.volumeRoot(volume)
+}
 
-	// For user-mounted volumes, use the device path if available
-	if device, hasDevice := volume.Options["device"]; hasDevice {
-		// Validate the device path exists and is accessible
-		if _, err := os.Stat(device); err == nil {
-			if vs.logger != nil {
-				vs.logger.Printf("Using device path for volume %s: %s", volumeID, device)
-			}
-			return device, nil
-		} else {
-			if vs.logger != nil {
-				vs.logger.Printf("Device path %s not accessible for volume %s, falling back to mountpoint", device, volumeID)
-			}
+// volumeRoot returns the directory a volume is scanned in, logging which path
+// was chosen and the rewrite rule applied. User-mounted volumes are read from
+// their device path when accessible, others from the Docker mountpoint, which
+// some drivers leave empty or set to a remote location that cannot be walked.
+func (vs *VolumeScanner) volumeRoot(volume *dockermodels.Volume) (string, error) {
+	volumeID := volume.Name
+	device := volume.Options["device"]
+	root, reason := config.ResolveVolumeRoot(vs.pathRewrites, device, volume.Mountpoint)
+	if reason != "" {
+		return "", &unresolvedMountpointError{driver: volume.Driver, reason: reason}
+	}
+
+	if vs.logger != nil {
+		source := volume.Mountpoint
+		switch {
+		case root.Device:
+			source = device
+			vs.logger.Printf("Using device path for volume %s: %s", volumeID, root.Path)
+		case device != "":
+			vs.logger.Printf("Device path %s not accessible for volume %s, falling back to mountpoint", device, volumeID)
+		}
+		if root.Rewrite != nil {
+			vs.logger.Printf("Rewrote path for volume %s: %s -> %s (rule %q)", volumeID, source, root.Path, root.Rewrite.Rule)
 		}
 	}
+	return root.Path, nil
+}
 
-	// Fall back to Docker internal mountpoint
-	return volume.Mountpoint, nil
+// unresolvedMountpointError reports a volume with no local path to scan
+type unresolvedMountpointError struct {
+	driver string
+	reason string
+}
+
+func (e *unresolvedMountpointError) Error() string {
+	return e.reason
+}
+
+// scanUnresolved sizes a volume without a local path through the external size
+// command, or reports it as unscannable when none is configured
+func (vs *VolumeScanner) scanUnresolved(ctx context.Context, volumeID string, unresolved *unresolvedMountpointError) (*interfaces.ScanResult, error) {
+	if vs.external == nil {
+		return nil, &models.ScanError{
+			VolumeID: volumeID,
+			Code:     models.ErrorCodeVolumeUnscannable,
+			Message:  "volume has no local mountpoint to scan",
+			Err:      unresolved,
+			Context: map[string]any{
+				"driver": unresolved.driver,
+				"reason": unresolved.reason,
+			},
+		}
+	}
+
+	result, err := vs.external.Size(ctx, volumeID, unresolved.driver)
+	if err != nil {
+		return nil, err
+	}
+	result.Accuracy = scanAccuracy(result)
+
+	if err := vs.cache.Set(volumeID, result, vs.calculateCacheTTL(ctx, result)); err != nil && vs.logger != nil {
+		vs.logger.Printf("Failed to cache scan result for volume %s: %v", volumeID, err)
+	}
+	vs.metrics.ScanCompleted(volumeID, result.Method, result.Duration, result.TotalSize)
+
+	if vs.logger != nil {
+		vs.logger.Printf("Volume sized externally: volume=%s driver=%s size=%d duration=%v",
+			volumeID, unresolved.driver, result.TotalSize, result.Duration)
+	}
+	return result, nil
 }
 
 // validatePath validates that a path exists and is accessible
@@ -418,6 +652,29 @@ func (vs *VolumeScanner) validatePath(path string) error {
 	return nil
 }
 
+// scanAccuracy derives how far a result's size can be trusted from the
+// method that measured it. du and diskus fail rather than skip what they
+// cannot read, so their sizes are exact, as are those of samples small enough
+// to walk everything; a native walk is exact unless it left unreadable entries
+// out. Sizes reported by the external command, or by methods the scanner does
+// not know, cannot be checked.
+func scanAccuracy(result *interfaces.ScanResult) string {
+	if result.Estimated {
+		return models.AccuracyEstimated
+	}
+	switch result.Method {
+	case "du", "diskus", "sample":
+		return models.AccuracyExact
+	case "native":
+		if result.ErrorCount > 0 {
+			return models.AccuracyApproximate
+		}
+		return models.AccuracyExact
+	default:
+		return models.AccuracyApproximate
+	}
+}
+
 // validateResult validates scan results
 func (vs *VolumeScanner) validateResult(result *interfaces.ScanResult) error {
 	if result.TotalSize < 0 {
@@ -459,10 +716,11 @@ func (vs *VolumeScanner) wrapScanError(
 	}
 }
 
-// calculateCacheTTL determines appropriate cache TTL based on scan result
-func (vs *VolumeScanner) calculateCacheTTL(result *interfaces.ScanResult) time.Duration {
-	// Base TTL from config
-	baseTTL := vs.config.Cache.TTL
+// calculateCacheTTL determines appropriate cache TTL based on scan result:
+// how often the volume's size changed recently, then how large it is
+func (vs *VolumeScanner) calculateCacheTTL(ctx context.Context, result *interfaces.ScanResult) time.Duration {
+	// Base TTL from config, longer for stable volumes and shorter for volatile ones
+	baseTTL := time.Duration(float64(vs.config.Cache.TTL) * vs.changeFactor(ctx, result))
 
 	// Adjust based on size (larger volumes cached longer)
 	if result.TotalSize > 100*1024*1024*1024 { // >100GB
@@ -474,6 +732,17 @@ func (vs *VolumeScanner) calculateCacheTTL(result *interfaces.ScanResult) time.D
 	return baseTTL
 }
 
+// knownFilesystems names common filesystems by their statfs magic number
+var knownFilesystems = map[uint32]string{
+	0x58465342: "xfs",
+	0xEF53:     "ext4", // EXT2/EXT3/EXT4
+	0x9123683E: "btrfs",
+	0x6969:     "nfs",
+	0xFF534D42: "cifs",
+	0x01021994: "tmpfs",
+	0x858458F6: "ramfs",
+}
+
 // detectFilesystemType detects the filesystem type of a path
 func (vs *VolumeScanner) detectFilesystemType(path string) string {
 	var stat syscall.Statfs_t
@@ -481,32 +750,49 @@ func (vs *VolumeScanner) detectFilesystemType(path string) string {
 	if err != nil {
 		return "unknown"
 	}
+	return filesystemTypeName(uint32(stat.Type), vs.fsNames)
+}
 
-	// Common filesystem type detection based on magic numbers
-	switch stat.Type {
-	case 0x58465342: // XFS
-		return "xfs"
-	case 0xEF53: // EXT2/EXT3/EXT4
-		return "ext4"
-	case 0x9123683E: // BTRFS
-		return "btrfs"
-	case 0x6969: // NFS
-		return "nfs"
-	case 0xFF534D42: // CIFS
-		return "cifs"
-	case 0x01021994: // TMPFS
-		return "tmpfs"
-	case 0x858458F6: // RAMFS
-		return "ramfs"
-	default:
-		return fmt.Sprintf("unknown(0x%x)", stat.Type)
+// filesystemTypeName names a filesystem by its magic number, preferring the
+// configured names over the built-in ones. Unrecognized filesystems are
+// reported as unknown(0x...), which metrics collapse to plain unknown.
+func filesystemTypeName(magic uint32, configured map[uint32]string) string {
+	if name, ok := configured[magic]; ok {
+		return name
+	}
+	if name, ok := knownFilesystems[magic]; ok {
+		return name
 	}
+	return fmt.Sprintf("unknown(0x%x)", magic)
 }
 
-// getMethodNames returns a list of method names for error context
-func (vs *VolumeScanner) getMethodNames() []string {
-	names := make([]string, len(vs.methods))
-	for i, method := range vs.methods {
+// methodsFor returns the fallback chain with the context's preferred method, if any, moved first
+// and its denied methods left out
+func (vs *VolumeScanner) methodsFor(ctx context.Context) []interfaces.ScanMethod {
+	preferred := interfaces.PreferredMethod(ctx)
+	denied := interfaces.DeniedMethods(ctx)
+	if preferred == "" && len(denied) == 0 {
+		return vs.methods
+	}
+
+	ordered := make([]interfaces.ScanMethod, 0, len(vs.methods))
+	for _, method := range vs.methods {
+		if method.Name() == preferred && !slices.Contains(denied, method.Name()) {
+			ordered = append(ordered, method)
+		}
+	}
+	for _, method := range vs.methods {
+		if method.Name() != preferred && !slices.Contains(denied, method.Name()) {
+			ordered = append(ordered, method)
+		}
+	}
+	return ordered
+}
+
+// methodNames returns a list of method names for error context
+func methodNames(methods []interfaces.ScanMethod) []string {
+	names := make([]string, len(methods))
+	for i, method := range methods {
 		names[i] = method.Name()
 	}
 	return names
@@ -514,27 +800,5 @@ func (vs *VolumeScanner) getMethodNames() []string {
 
 // classifyError classifies errors for metrics reporting
 func (vs *VolumeScanner) classifyError(err error) string {
-	if err == nil {
-		return "success"
-	}
-
-	// Check for specific error types
-	if scanErr, ok := err.(*models.ScanError); ok {
-		return scanErr.Code
-	}
-
-	// Classify based on error message patterns
-	errMsg := err.Error()
-	switch {
-	case syscall.EACCES.Error() == errMsg || os.IsPermission(err):
-		return models.ErrorCodePermissionDenied
-	case syscall.ENOENT.Error() == errMsg || os.IsNotExist(err):
-		return models.ErrorCodePathNotFound
-	case syscall.ENOSPC.Error() == errMsg:
-		return models.ErrorCodeInsufficientSpace
-	case syscall.ETIMEDOUT.Error() == errMsg:
-		return models.ErrorCodeScanTimeout
-	default:
-		return models.ErrorCodeUnknown
-	}
+	return models.ClassifyScanError(err)
 }
@@ -0,0 +1,190 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/core/models"
+	dockermodels "github.com/mantonx/volumeviz/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeScanner_MethodsForPreferredMethod(t *testing.T) {
+	config := models.DefaultConfig()
+	vs := &VolumeScanner{
+		methods: []interfaces.ScanMethod{
+			NewDiskusMethod(config.Scanning),
+			NewDuMethod(config.Scanning),
+			NewNativeMethod(config.Scanning),
+		},
+	}
+
+	ctx := context.Background()
+	assert.Equal(t, []string{"diskus", "du", "native"}, methodNames(vs.methodsFor(ctx)))
+
+	// The preferred method moves to the front; the rest keep their fallback order
+	ctx = interfaces.WithPreferredMethod(ctx, "du")
+	assert.Equal(t, []string{"du", "diskus", "native"}, methodNames(vs.methodsFor(ctx)))
+
+	// Unknown methods leave the order unchanged
+	ctx = interfaces.WithPreferredMethod(context.Background(), "unknown")
+	assert.Equal(t, []string{"diskus", "du", "native"}, methodNames(vs.methodsFor(ctx)))
+}
+
+func TestVolumeScanner_MethodsForDeniedMethods(t *testing.T) {
+	config := models.DefaultConfig()
+	vs := &VolumeScanner{
+		methods: []interfaces.ScanMethod{
+			NewDiskusMethod(config.Scanning),
+			NewDuMethod(config.Scanning),
+			NewNativeMethod(config.Scanning),
+		},
+	}
+
+	// Denied methods drop out of the fallback chain
+	ctx := interfaces.WithDeniedMethods(context.Background(), []string{"diskus"})
+	assert.Equal(t, []string{"du", "native"}, methodNames(vs.methodsFor(ctx)))
+
+	// A denial wins over a preference for the same method
+	ctx = interfaces.WithPreferredMethod(ctx, "diskus")
+	assert.Equal(t, []string{"du", "native"}, methodNames(vs.methodsFor(ctx)))
+
+	ctx = interfaces.WithDeniedMethods(interfaces.WithPreferredMethod(context.Background(), "native"), []string{"du"})
+	assert.Equal(t, []string{"native", "diskus"}, methodNames(vs.methodsFor(ctx)))
+}
+
+func TestVolumeScanner_RewritePath(t *testing.T) {
+	var logs bytes.Buffer
+	vs := &VolumeScanner{
+		logger: log.New(&logs, "", 0),
+		pathRewrites: parsePathRewrites([]string{
+			"/var/lib/docker/volumes/media=>/media",
+			"/var/lib/docker/volumes=>/host/volumes",
+		}, nil),
+	}
+	root := func(name, mountpoint string) string {
+		t.Helper()
+		path, err := vs.volumeRoot(&dockermodels.Volume{Name: name, Driver: "local", Mountpoint: mountpoint})
+		require.NoError(t, err)
+		return path
+	}
+
+	assert.Equal(t, "/media/_data", root("media", "/var/lib/docker/volumes/media/_data"))
+	assert.Contains(t, logs.String(), `Rewrote path for volume media: /var/lib/docker/volumes/media/_data -> /media/_data (rule "/var/lib/docker/volumes/media=>/media")`)
+
+	assert.Equal(t, "/host/volumes/app/_data", root("app", "/var/lib/docker/volumes/app/_data"))
+
+	// Paths no rule matches are scanned where Docker reports them, without a log line
+	logs.Reset()
+	assert.Equal(t, "/srv/app", root("app", "/srv/app"))
+	assert.Empty(t, logs.String())
+
+	// Device paths are rewritten too, and used when they are directories here
+	device := t.TempDir()
+	rewriting := &VolumeScanner{pathRewrites: parsePathRewrites([]string{"/host/bind=>" + device}, nil)}
+	path, err := rewriting.volumeRoot(&dockermodels.Volume{Name: "bind", Driver: "local",
+		Mountpoint: "/var/lib/docker/volumes/bind/_data", Options: map[string]string{"device": "/host/bind", "o": "bind"}})
+	require.NoError(t, err)
+	assert.Equal(t, device, path)
+
+	_, err = vs.volumeRoot(&dockermodels.Volume{Name: "remote", Driver: "nfs", Mountpoint: "nfs://server/export"})
+	assert.Error(t, err)
+}
+
+func TestFilesystemTypeName(t *testing.T) {
+	names := parseFilesystemNames([]string{"0x65735546=fuse", "0xEF53=ext"}, nil)
+
+	assert.Equal(t, "fuse", filesystemTypeName(0x65735546, names))
+	// Configured names win over the built-in ones
+	assert.Equal(t, "ext", filesystemTypeName(0xEF53, names))
+	assert.Equal(t, "xfs", filesystemTypeName(0x58465342, names))
+	// Unnamed filesystems keep their magic number
+	assert.Equal(t, "unknown(0x2fc12fc1)", filesystemTypeName(0x2FC12FC1, names))
+	assert.Equal(t, "unknown(0x65735546)", filesystemTypeName(0x65735546, nil))
+}
+
+func TestScanAccuracy(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interfaces.ScanResult
+		want   string
+	}{
+		{"du", interfaces.ScanResult{Method: "du"}, models.AccuracyExact},
+		{"diskus", interfaces.ScanResult{Method: "diskus"}, models.AccuracyExact},
+		{"native", interfaces.ScanResult{Method: "native", SkippedCount: 3, SpecialCount: 1}, models.AccuracyExact},
+		{"native with unreadable entries", interfaces.ScanResult{Method: "native", ErrorCount: 2}, models.AccuracyApproximate},
+		{"sample that walked everything", interfaces.ScanResult{Method: "sample"}, models.AccuracyExact},
+		{"sampled estimate", interfaces.ScanResult{Method: "sample", Estimated: true, SampleSize: 20}, models.AccuracyEstimated},
+		{"external command", interfaces.ScanResult{Method: externalMethodName}, models.AccuracyApproximate},
+		{"unknown method", interfaces.ScanResult{Method: "custom"}, models.AccuracyApproximate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scanAccuracy(&tt.result))
+		})
+	}
+}
+
+// fakeSizeHistory serves fixed size histories, newest first
+type fakeSizeHistory map[string][]int64
+
+func (h fakeSizeHistory) RecentSizes(ctx context.Context, volumeID string, limit int) ([]int64, error) {
+	sizes := h[volumeID]
+	return sizes[:min(len(sizes), limit)], nil
+}
+
+func TestVolumeScanner_CacheTTLFollowsChangeFrequency(t *testing.T) {
+	const gib = 1 << 30
+	vs := &VolumeScanner{config: models.DefaultConfig()}
+	vs.SetSizeHistory(fakeSizeHistory{
+		"stable":   {5 * gib, 5 * gib, 5 * gib, 5 * gib, 5 * gib},
+		"volatile": {4 * gib, 6 * gib, 3 * gib, 7 * gib, 2 * gib},
+		"mixed":    {7 * gib, 6 * gib, 6 * gib, 5 * gib},
+		"new":      {5 * gib},
+		// Differences under a percent are not changes
+		"jitter": {5*gib + 1000, 5 * gib, 5*gib + 2000, 5 * gib},
+	})
+	ctx := context.Background()
+	ttl := func(volumeID string, size int64) time.Duration {
+		return vs.calculateCacheTTL(ctx, &interfaces.ScanResult{VolumeID: volumeID, TotalSize: size})
+	}
+
+	base := vs.config.Cache.TTL
+	assert.Equal(t, 4*base, ttl("stable", 5*gib))
+	assert.Equal(t, base/4, ttl("volatile", 3*gib))
+	assert.Greater(t, ttl("stable", 5*gib), ttl("volatile", 3*gib))
+	assert.Equal(t, base, ttl("mixed", 7*gib), "half the scans changed the size")
+	assert.Equal(t, 4*base, ttl("jitter", 5*gib))
+
+	// Without enough history only the size counts
+	assert.Equal(t, base, ttl("new", 5*gib))
+	assert.Equal(t, base, ttl("unknown", 5*gib))
+
+	// A change now counts against a volume that was stable until this scan
+	assert.Less(t, ttl("stable", 9*gib), 4*base)
+
+	// Size stays a secondary factor
+	vs.SetSizeHistory(fakeSizeHistory{
+		"small": {512 << 20, 512 << 20, 512 << 20},
+		"big":   {200 * gib, 200 * gib, 200 * gib},
+	})
+	assert.Equal(t, 2*base, ttl("small", 512<<20))
+	assert.Equal(t, 8*base, ttl("big", 200*gib))
+}
+
+func TestVolumeScanner_CacheTTLWithoutHistory(t *testing.T) {
+	config := models.DefaultConfig()
+	vs := &VolumeScanner{config: config}
+	result := &interfaces.ScanResult{VolumeID: "stable", TotalSize: 5 << 30}
+	assert.Equal(t, config.Cache.TTL, vs.calculateCacheTTL(context.Background(), result))
+
+	// Disabled history leaves the TTL to size even with a history source
+	vs.config.Cache.HistorySamples = 0
+	vs.SetSizeHistory(fakeSizeHistory{"stable": {5 << 30, 5 << 30, 5 << 30}})
+	assert.Equal(t, config.Cache.TTL, vs.calculateCacheTTL(context.Background(), result))
+}
@@ -0,0 +1,420 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AnnotationKeyLogicalKey groups several physical volume names under one logical key
+// Used to keep history continuous when Compose recreates a volume under a new name
+const AnnotationKeyLogicalKey = "logical_key"
+
+// AnnotationKeyPinned protects a volume from policy pruning when set to "true"
+const AnnotationKeyPinned = "pinned"
+
+// AnnotationKeyRetention is how long a volume is kept once orphaned, as a
+// duration such as "72h" or "7d", or "expired" to make it prunable right away
+const AnnotationKeyRetention = "retention"
+
+// AnnotationKeyExpectedSize is the size a volume is expected to have, in bytes
+// or with a unit such as "500MB" or "10GiB"; scans outside the tolerance are
+// reported as size drift
+const AnnotationKeyExpectedSize = "expected_size"
+
+// AnnotationKeySizeTolerance is how far a volume may deviate from its expected
+// size: a percentage of it ("20%") or an absolute size ("1GiB")
+const AnnotationKeySizeTolerance = "size_tolerance"
+
+// AnnotationKeyScanEnabled switches scanning of a volume off when set to
+// "false": the scheduler skips it and manual scans need an admin override
+const AnnotationKeyScanEnabled = "scan_enabled"
+
+// AnnotationKeyScanMethodsDenied lists scan methods, comma-separated, that
+// must not be used for a volume, e.g. "diskus" for a FUSE mount it crashes on;
+// scans of the volume fall back to the remaining methods
+const AnnotationKeyScanMethodsDenied = "scan_methods_denied"
+
+// AnnotationKeyQuarantined marks a volume as quarantined since the RFC 3339
+// time it holds: it is left out of normal listings and deleted once the
+// quarantine grace period has passed
+const AnnotationKeyQuarantined = "quarantined"
+
+// AnnotationKeyQuarantineRestored is the RFC 3339 time a volume was restored
+// from quarantine; quarantine counts its orphan time from then on
+const AnnotationKeyQuarantineRestored = "quarantine_restored"
+
+// AnnotationKeyScanLock locks a volume against scanning while an external
+// process such as a backup writes it: the RFC 3339 time the lock expires,
+// followed by a space and who holds it when known (see ScanLock)
+const AnnotationKeyScanLock = "scan_lock"
+
+// ScanLock is a temporary lock against scanning a volume
+type ScanLock struct {
+	Until  time.Time
+	Holder string // Who took the lock, e.g. "restic nightly"; optional
+}
+
+// Active reports whether the lock still holds at now
+func (l ScanLock) Active(now time.Time) bool {
+	return now.Before(l.Until)
+}
+
+// String formats the lock as stored in AnnotationKeyScanLock
+func (l ScanLock) String() string {
+	value := l.Until.UTC().Format(time.RFC3339)
+	if l.Holder != "" {
+		value += " " + l.Holder
+	}
+	return value
+}
+
+// ParseScanLock parses a lock stored in AnnotationKeyScanLock
+func ParseScanLock(value string) (ScanLock, error) {
+	until, holder, _ := strings.Cut(strings.TrimSpace(value), " ")
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return ScanLock{}, fmt.Errorf("invalid scan lock expiry %q: %w", until, err)
+	}
+	return ScanLock{Until: t, Holder: strings.TrimSpace(holder)}, nil
+}
+
+// ScanEnabled reports whether a volume's annotations leave scanning enabled.
+// Only a false boolean disables it, so a mistyped value never stops scans.
+func ScanEnabled(annotations map[string]string) bool {
+	value, ok := annotations[AnnotationKeyScanEnabled]
+	if !ok {
+		return true
+	}
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err != nil || enabled
+}
+
+// DeniedScanMethods returns the lowercased methods a volume's annotations deny
+// (see AnnotationKeyScanMethodsDenied), or nil when none are
+func DeniedScanMethods(annotations map[string]string) []string {
+	var denied []string
+	for _, method := range strings.Split(annotations[AnnotationKeyScanMethodsDenied], ",") {
+		method = strings.ToLower(strings.TrimSpace(method))
+		if method != "" && !slices.Contains(denied, method) {
+			denied = append(denied, method)
+		}
+	}
+	return denied
+}
+
+// VolumeAnnotationRepository handles user-managed volume annotations
+// Annotations are keyed by volume name so they outlive the Docker volume itself
+type VolumeAnnotationRepository struct {
+	*BaseRepository
+}
+
+// NewVolumeAnnotationRepository creates a new annotation repository
+func NewVolumeAnnotationRepository(db *DB) *VolumeAnnotationRepository {
+	return &VolumeAnnotationRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// WithTx returns a new annotation repository instance using the provided transaction
+func (r *VolumeAnnotationRepository) WithTx(tx *Tx) *VolumeAnnotationRepository {
+	return &VolumeAnnotationRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// SetAnnotation creates or replaces a single annotation on a volume
+func (r *VolumeAnnotationRepository) SetAnnotation(ctx context.Context, volumeName, key, value string) error {
+	query := `
+		INSERT INTO volume_annotations (volume_name, annotation_key, annotation_value, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (volume_name, annotation_key)
+		DO UPDATE SET
+			annotation_value = EXCLUDED.annotation_value,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	executor := r.getExecutor()
+	if _, err := executor.Exec(query, volumeName, key, value, now, now); err != nil {
+		return fmt.Errorf("failed to set annotation %s on volume %s: %w", key, volumeName, err)
+	}
+
+	return nil
+}
+
+// GetAnnotation returns a single annotation value
+// Returns sql.ErrNoRows if the volume has no such annotation
+func (r *VolumeAnnotationRepository) GetAnnotation(ctx context.Context, volumeName, key string) (string, error) {
+	query := `
+		SELECT annotation_value
+		FROM volume_annotations
+		WHERE volume_name = $1 AND annotation_key = $2
+	`
+
+	var value string
+	executor := r.getExecutor()
+	if err := executor.QueryRow(query, volumeName, key).Scan(&value); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// GetAnnotations returns every annotation on a volume keyed by annotation key
+func (r *VolumeAnnotationRepository) GetAnnotations(ctx context.Context, volumeName string) (map[string]string, error) {
+	query := `
+		SELECT annotation_key, annotation_value
+		FROM volume_annotations
+		WHERE volume_name = $1
+	`
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query, volumeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations for volume %s: %w", volumeName, err)
+	}
+	defer rows.Close()
+
+	annotations := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		annotations[key] = value
+	}
+
+	return annotations, rows.Err()
+}
+
+// DeleteAnnotation removes a single annotation from a volume
+// Returns sql.ErrNoRows if there was nothing to delete
+func (r *VolumeAnnotationRepository) DeleteAnnotation(ctx context.Context, volumeName, key string) error {
+	query := `DELETE FROM volume_annotations WHERE volume_name = $1 AND annotation_key = $2`
+
+	executor := r.getExecutor()
+	result, err := executor.Exec(query, volumeName, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete annotation %s on volume %s: %w", key, volumeName, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListByKey returns every annotation with the given key, ordered by value then volume name
+func (r *VolumeAnnotationRepository) ListByKey(ctx context.Context, key string) ([]*VolumeAnnotation, error) {
+	query := `
+		SELECT volume_name, annotation_key, annotation_value, created_at, updated_at
+		FROM volume_annotations
+		WHERE annotation_key = $1
+		ORDER BY annotation_value, volume_name
+	`
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+	defer rows.Close()
+
+	return ScanRows(rows, r.scanAnnotationRow)
+}
+
+// ListAll returns every annotation, ordered by volume name then key
+func (r *VolumeAnnotationRepository) ListAll(ctx context.Context) ([]*VolumeAnnotation, error) {
+	query := `
+		SELECT volume_name, annotation_key, annotation_value, created_at, updated_at
+		FROM volume_annotations
+		ORDER BY volume_name, annotation_key
+	`
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annotations: %w", err)
+	}
+	defer rows.Close()
+
+	return ScanRows(rows, r.scanAnnotationRow)
+}
+
+// IsScanEnabled reports whether scanning of a volume is enabled (see AnnotationKeyScanEnabled)
+func (r *VolumeAnnotationRepository) IsScanEnabled(ctx context.Context, volumeName string) (bool, error) {
+	value, err := r.GetAnnotation(ctx, volumeName, AnnotationKeyScanEnabled)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to get scan toggle of volume %s: %w", volumeName, err)
+	}
+	return ScanEnabled(map[string]string{AnnotationKeyScanEnabled: value}), nil
+}
+
+// DeniedScanMethods returns the scan methods denied for a volume (see
+// AnnotationKeyScanMethodsDenied)
+func (r *VolumeAnnotationRepository) DeniedScanMethods(ctx context.Context, volumeName string) ([]string, error) {
+	value, err := r.GetAnnotation(ctx, volumeName, AnnotationKeyScanMethodsDenied)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get denied scan methods of volume %s: %w", volumeName, err)
+	}
+	return DeniedScanMethods(map[string]string{AnnotationKeyScanMethodsDenied: value}), nil
+}
+
+// ScanDisabledVolumes returns the names of every volume whose scanning is switched off
+func (r *VolumeAnnotationRepository) ScanDisabledVolumes(ctx context.Context) (map[string]bool, error) {
+	annotations, err := r.ListByKey(ctx, AnnotationKeyScanEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := make(map[string]bool)
+	for _, a := range annotations {
+		if !ScanEnabled(map[string]string{AnnotationKeyScanEnabled: a.Value}) {
+			disabled[a.VolumeName] = true
+		}
+	}
+
+	return disabled, nil
+}
+
+// LockScans locks a volume against scanning, replacing any lock it held
+func (r *VolumeAnnotationRepository) LockScans(ctx context.Context, volumeName string, lock ScanLock) error {
+	return r.SetAnnotation(ctx, volumeName, AnnotationKeyScanLock, lock.String())
+}
+
+// UnlockScans releases a volume's scan lock, expired or not
+// Returns sql.ErrNoRows if the volume held no lock
+func (r *VolumeAnnotationRepository) UnlockScans(ctx context.Context, volumeName string) error {
+	return r.DeleteAnnotation(ctx, volumeName, AnnotationKeyScanLock)
+}
+
+// ActiveScanLock returns the scan lock a volume holds at now, or nil when it
+// holds none or it expired. A lock that cannot be parsed locks nothing, so a
+// mistyped annotation never stops scans.
+func (r *VolumeAnnotationRepository) ActiveScanLock(ctx context.Context, volumeName string, now time.Time) (*ScanLock, error) {
+	value, err := r.GetAnnotation(ctx, volumeName, AnnotationKeyScanLock)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan lock of volume %s: %w", volumeName, err)
+	}
+	lock, err := ParseScanLock(value)
+	if err != nil || !lock.Active(now) {
+		return nil, nil
+	}
+	return &lock, nil
+}
+
+// ScanLockedVolumes returns the scan locks held at now, keyed by volume name
+func (r *VolumeAnnotationRepository) ScanLockedVolumes(ctx context.Context, now time.Time) (map[string]ScanLock, error) {
+	annotations, err := r.ListByKey(ctx, AnnotationKeyScanLock)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]ScanLock)
+	for _, a := range annotations {
+		if lock, err := ParseScanLock(a.Value); err == nil && lock.Active(now) {
+			locked[a.VolumeName] = lock
+		}
+	}
+
+	return locked, nil
+}
+
+// SetAlias maps a physical volume name onto a logical key
+func (r *VolumeAnnotationRepository) SetAlias(ctx context.Context, volumeName, logicalKey string) error {
+	return r.SetAnnotation(ctx, volumeName, AnnotationKeyLogicalKey, logicalKey)
+}
+
+// DeleteAlias removes the logical key mapping for a volume name
+func (r *VolumeAnnotationRepository) DeleteAlias(ctx context.Context, volumeName string) error {
+	return r.DeleteAnnotation(ctx, volumeName, AnnotationKeyLogicalKey)
+}
+
+// ListAliases returns all alias mappings grouped by logical key
+func (r *VolumeAnnotationRepository) ListAliases(ctx context.Context) (map[string][]string, error) {
+	annotations, err := r.ListByKey(ctx, AnnotationKeyLogicalKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[string][]string)
+	for _, a := range annotations {
+		aliases[a.Value] = append(aliases[a.Value], a.VolumeName)
+	}
+
+	return aliases, nil
+}
+
+// ResolveLogicalKey returns the logical key for a name
+// The name may be a physical volume name or already a logical key; unmapped
+// names resolve to themselves so callers can always group by the result
+func (r *VolumeAnnotationRepository) ResolveLogicalKey(ctx context.Context, name string) (string, error) {
+	key, err := r.GetAnnotation(ctx, name, AnnotationKeyLogicalKey)
+	if err == sql.ErrNoRows {
+		return name, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve logical key for %s: %w", name, err)
+	}
+	return key, nil
+}
+
+// GetAliasedVolumes returns every physical volume name grouped under a logical key
+// The logical key itself is always included so history recorded before any
+// alias was created is not lost
+func (r *VolumeAnnotationRepository) GetAliasedVolumes(ctx context.Context, logicalKey string) ([]string, error) {
+	query := `
+		SELECT volume_name
+		FROM volume_annotations
+		WHERE annotation_key = $1 AND annotation_value = $2
+		ORDER BY volume_name
+	`
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query, AnnotationKeyLogicalKey, logicalKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliased volumes: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{logicalKey}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if name != logicalKey {
+			names = append(names, name)
+		}
+	}
+
+	return names, rows.Err()
+}
+
+// scanAnnotationRow scans a single annotation from query rows
+func (r *VolumeAnnotationRepository) scanAnnotationRow(rows *sql.Rows) (*VolumeAnnotation, error) {
+	var a VolumeAnnotation
+	if err := rows.Scan(&a.VolumeName, &a.Key, &a.Value, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAnnotationTestDB creates a SQLite database with the annotation migration
+// applied and a minimal volume_metrics table for history queries
+func setupAnnotationTestDB(t *testing.T) *DB {
+	config := &Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "annotations.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	}
+
+	db, err := NewDB(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+
+	applied := false
+	for _, m := range migrations {
+		if m.Version == "006" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+			applied = true
+		}
+	}
+	require.True(t, applied, "migration 006 should be embedded")
+
+	_, err = db.Exec(`
+		CREATE TABLE volume_metrics (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			volume_id TEXT NOT NULL,
+			metric_timestamp DATETIME NOT NULL,
+			total_size INTEGER NOT NULL,
+			file_count INTEGER NOT NULL,
+			directory_count INTEGER NOT NULL,
+			growth_rate REAL,
+			access_frequency INTEGER DEFAULT 0,
+			container_count INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func insertTestMetric(t *testing.T, db *DB, volumeID string, ts time.Time, size int64) {
+	_, err := db.Exec(`
+		INSERT INTO volume_metrics (volume_id, metric_timestamp, total_size, file_count, directory_count, created_at, updated_at)
+		VALUES (?, ?, ?, 0, 0, ?, ?)
+	`, volumeID, ts, size, ts, ts)
+	require.NoError(t, err)
+}
+
+func TestVolumeAnnotationRepository_AliasLifecycle(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	repo := NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+
+	// Unmapped names resolve to themselves
+	key, err := repo.ResolveLogicalKey(ctx, "app_data_1a2b")
+	require.NoError(t, err)
+	assert.Equal(t, "app_data_1a2b", key)
+
+	require.NoError(t, repo.SetAlias(ctx, "app_data_1a2b", "app-data"))
+	require.NoError(t, repo.SetAlias(ctx, "app_data_3c4d", "app-data"))
+
+	key, err = repo.ResolveLogicalKey(ctx, "app_data_3c4d")
+	require.NoError(t, err)
+	assert.Equal(t, "app-data", key)
+
+	names, err := repo.GetAliasedVolumes(ctx, "app-data")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app-data", "app_data_1a2b", "app_data_3c4d"}, names)
+
+	aliases, err := repo.ListAliases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"app-data": {"app_data_1a2b", "app_data_3c4d"}}, aliases)
+
+	// Re-pointing an alias replaces the previous mapping
+	require.NoError(t, repo.SetAlias(ctx, "app_data_1a2b", "legacy"))
+	key, err = repo.ResolveLogicalKey(ctx, "app_data_1a2b")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", key)
+
+	require.NoError(t, repo.DeleteAlias(ctx, "app_data_1a2b"))
+	assert.ErrorIs(t, repo.DeleteAlias(ctx, "app_data_1a2b"), sql.ErrNoRows)
+}
+
+func TestVolumeAnnotationRepository_GetAnnotations(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	repo := NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+
+	annotations, err := repo.GetAnnotations(ctx, "build_cache")
+	require.NoError(t, err)
+	assert.Empty(t, annotations)
+
+	require.NoError(t, repo.SetAnnotation(ctx, "build_cache", AnnotationKeyRetention, "7d"))
+	require.NoError(t, repo.SetAnnotation(ctx, "build_cache", AnnotationKeyPinned, "true"))
+	require.NoError(t, repo.SetAnnotation(ctx, "other", AnnotationKeyRetention, "expired"))
+
+	annotations, err = repo.GetAnnotations(ctx, "build_cache")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"retention": "7d", "pinned": "true"}, annotations)
+}
+
+func TestVolumeAnnotationRepository_ListAll(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	repo := NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.SetAnnotation(ctx, "other", AnnotationKeyRetention, "expired"))
+	require.NoError(t, repo.SetAnnotation(ctx, "build_cache", AnnotationKeyRetention, "7d"))
+	require.NoError(t, repo.SetAnnotation(ctx, "build_cache", AnnotationKeyPinned, "true"))
+
+	annotations, err := repo.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, annotations, 3)
+	assert.Equal(t, [][2]string{
+		{"build_cache", "pinned"},
+		{"build_cache", "retention"},
+		{"other", "retention"},
+	}, [][2]string{
+		{annotations[0].VolumeName, annotations[0].Key},
+		{annotations[1].VolumeName, annotations[1].Key},
+		{annotations[2].VolumeName, annotations[2].Key},
+	})
+}
+
+func TestVolumeAnnotationRepository_ScanToggle(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	repo := NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+
+	enabled, err := repo.IsScanEnabled(ctx, "postgres_data")
+	require.NoError(t, err)
+	assert.True(t, enabled, "volumes without the annotation are scanned")
+
+	require.NoError(t, repo.SetAnnotation(ctx, "postgres_data", AnnotationKeyScanEnabled, "false"))
+	require.NoError(t, repo.SetAnnotation(ctx, "redis_data", AnnotationKeyScanEnabled, "0"))
+	require.NoError(t, repo.SetAnnotation(ctx, "app_data", AnnotationKeyScanEnabled, "true"))
+	require.NoError(t, repo.SetAnnotation(ctx, "typo", AnnotationKeyScanEnabled, "nope"))
+
+	enabled, err = repo.IsScanEnabled(ctx, "postgres_data")
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	disabled, err := repo.ScanDisabledVolumes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"postgres_data": true, "redis_data": true}, disabled)
+}
+
+func TestVolumeAnnotationRepository_DeniedScanMethods(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	repo := NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+
+	denied, err := repo.DeniedScanMethods(ctx, "postgres_data")
+	require.NoError(t, err)
+	assert.Empty(t, denied, "volumes without the annotation may use every method")
+
+	require.NoError(t, repo.SetAnnotation(ctx, "postgres_data", AnnotationKeyScanMethodsDenied, " DU, diskus,du,,"))
+
+	denied, err = repo.DeniedScanMethods(ctx, "postgres_data")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"du", "diskus"}, denied)
+}
+
+func TestVolumeAnnotationRepository_ScanLocks(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	repo := NewVolumeAnnotationRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 14, 2, 0, 0, 0, time.UTC)
+
+	lock, err := repo.ActiveScanLock(ctx, "postgres_data", now)
+	require.NoError(t, err)
+	assert.Nil(t, lock, "volumes without the annotation are not locked")
+
+	backup := ScanLock{Until: now.Add(time.Hour), Holder: "restic nightly"}
+	require.NoError(t, repo.LockScans(ctx, "postgres_data", backup))
+	require.NoError(t, repo.LockScans(ctx, "redis_data", ScanLock{Until: now.Add(-time.Minute)}))
+	require.NoError(t, repo.SetAnnotation(ctx, "typo", AnnotationKeyScanLock, "tomorrow"))
+
+	value, err := repo.GetAnnotation(ctx, "postgres_data", AnnotationKeyScanLock)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-14T03:00:00Z restic nightly", value)
+
+	lock, err = repo.ActiveScanLock(ctx, "postgres_data", now)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.True(t, backup.Until.Equal(lock.Until))
+	assert.Equal(t, "restic nightly", lock.Holder)
+
+	lock, err = repo.ActiveScanLock(ctx, "postgres_data", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, lock, "locks end at their expiry")
+
+	locked, err := repo.ScanLockedVolumes(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"postgres_data"}, slices.Collect(maps.Keys(locked)), "expired and invalid locks lock nothing")
+
+	require.NoError(t, repo.UnlockScans(ctx, "postgres_data"))
+	assert.ErrorIs(t, repo.UnlockScans(ctx, "postgres_data"), sql.ErrNoRows)
+	locked, err = repo.ScanLockedVolumes(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, locked)
+}
+
+func TestVolumeMetricsRepository_GetMetricsByLogicalKey(t *testing.T) {
+	db := setupAnnotationTestDB(t)
+	annotations := NewVolumeAnnotationRepository(db)
+	metrics := NewVolumeMetricsRepository(db)
+	ctx := context.Background()
+
+	base := time.Now().Add(-48 * time.Hour).UTC()
+
+	// Original volume, then its recreation under a new generated name
+	insertTestMetric(t, db, "app_data_1a2b", base, 100)
+	insertTestMetric(t, db, "app_data_1a2b", base.Add(6*time.Hour), 200)
+	insertTestMetric(t, db, "app_data_3c4d", base.Add(12*time.Hour), 300)
+	insertTestMetric(t, db, "app_data_3c4d", base.Add(18*time.Hour), 400)
+	// Unrelated volume must not leak into the aggregate
+	insertTestMetric(t, db, "other", base.Add(3*time.Hour), 999)
+
+	require.NoError(t, annotations.SetAlias(ctx, "app_data_1a2b", "app-data"))
+	require.NoError(t, annotations.SetAlias(ctx, "app_data_3c4d", "app-data"))
+
+	start := base.Add(-time.Hour)
+	end := time.Now().UTC()
+
+	history, err := metrics.GetMetricsByLogicalKey(ctx, "app-data", start, end, 100)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	// Merged newest-first across both physical names
+	sizes := make([]int64, len(history))
+	for i, m := range history {
+		sizes[i] = m.TotalSize
+	}
+	assert.Equal(t, []int64{400, 300, 200, 100}, sizes)
+	assert.Equal(t, "app_data_3c4d", history[0].VolumeID)
+	assert.Equal(t, "app_data_1a2b", history[len(history)-1].VolumeID)
+
+	// Without aliasing each physical name only sees its own series
+	single, err := metrics.GetMetrics(ctx, "app_data_3c4d", start, end, 100)
+	require.NoError(t, err)
+	assert.Len(t, single, 2)
+
+	limited, err := metrics.GetMetricsByLogicalKey(ctx, "app-data", start, end, 3)
+	require.NoError(t, err)
+	assert.Len(t, limited, 3)
+}
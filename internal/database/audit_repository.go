@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditLogListOptions holds filters and pagination for audit log queries
+type AuditLogListOptions struct {
+	UserID     string    // Exact user ID
+	UserRole   string    // Exact role
+	Method     string    // HTTP method, e.g. POST
+	PathPrefix string    // Request path prefix, e.g. /api/v1/database
+	StatusCode int       // Exact status code; zero matches any
+	Since      time.Time // Inclusive lower bound on occurred_at; zero for none
+	Until      time.Time // Exclusive upper bound on occurred_at; zero for none
+
+	Limit  int
+	Offset int
+}
+
+// AuditLogRepository stores and queries the API audit trail
+type AuditLogRepository struct {
+	*BaseRepository
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// WithTx returns a new audit log repository instance using the provided transaction
+func (r *AuditLogRepository) WithTx(tx *Tx) *AuditLogRepository {
+	return &AuditLogRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// Insert records an audit entry. OccurredAt defaults to now when unset.
+func (r *AuditLogRepository) Insert(ctx context.Context, entry *AuditLogEntry) error {
+	if entry.OccurredAt.IsZero() {
+		entry.OccurredAt = time.Now()
+	}
+	// Stored in UTC so time range filters compare consistently on SQLite
+	entry.OccurredAt = entry.OccurredAt.UTC()
+
+	query := `
+		INSERT INTO audit_log (occurred_at, user_id, user_role, method, path, route, status_code, summary, request_id, client_ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	executor := r.getExecutor()
+	_, err := executor.Exec(query,
+		entry.OccurredAt, entry.UserID, entry.UserRole, entry.Method, entry.Path,
+		entry.Route, entry.StatusCode, entry.Summary, entry.RequestID, entry.ClientIP,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// List returns audit entries matching the options, newest first, and the total number of matches
+func (r *AuditLogRepository) List(ctx context.Context, options *AuditLogListOptions) ([]*AuditLogEntry, int, error) {
+	if options == nil {
+		options = &AuditLogListOptions{}
+	}
+
+	qb := NewQueryBuilder().
+		Select("id", "occurred_at", "user_id", "user_role", "method", "path", "route",
+			"status_code", "summary", "request_id", "client_ip").
+		From(TableNames.AuditLog)
+	applyAuditFilters(qb, options)
+	qb.OrderBy("occurred_at DESC").OrderBy("id DESC")
+
+	if options.Limit > 0 {
+		qb.Limit(options.Limit)
+	}
+	if options.Offset > 0 {
+		qb.Offset(options.Offset)
+	}
+
+	query, args := qb.Build()
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := ScanRows(rows, func(rows *sql.Rows) (*AuditLogEntry, error) {
+		var entry AuditLogEntry
+		err := rows.Scan(&entry.ID, &entry.OccurredAt, &entry.UserID, &entry.UserRole, &entry.Method,
+			&entry.Path, &entry.Route, &entry.StatusCode, &entry.Summary, &entry.RequestID, &entry.ClientIP)
+		return &entry, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	countQB := NewQueryBuilder().Select("COUNT(*)").From(TableNames.AuditLog)
+	applyAuditFilters(countQB, options)
+	countQuery, countArgs := countQB.Build()
+
+	var total int
+	if err := executor.QueryRow(countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// applyAuditFilters adds the WHERE conditions shared by the list and count queries
+func applyAuditFilters(qb *QueryBuilder, options *AuditLogListOptions) {
+	if options.UserID != "" {
+		qb.Where("user_id = ?", options.UserID)
+	}
+	if options.UserRole != "" {
+		qb.Where("user_role = ?", options.UserRole)
+	}
+	if options.Method != "" {
+		qb.Where("method = ?", options.Method)
+	}
+	if options.PathPrefix != "" {
+		qb.Where(`path LIKE ? ESCAPE '\'`, escapeLikePattern(options.PathPrefix)+"%")
+	}
+	if options.StatusCode != 0 {
+		qb.Where("status_code = ?", options.StatusCode)
+	}
+	if !options.Since.IsZero() {
+		qb.Where("occurred_at >= ?", options.Since.UTC())
+	}
+	if !options.Until.IsZero() {
+		qb.Where("occurred_at < ?", options.Until.UTC())
+	}
+}
+
+// escapeLikePattern escapes LIKE wildcards so a prefix matches literally
+func escapeLikePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAuditTestDB creates a SQLite database with the audit log migration applied
+func setupAuditTestDB(t *testing.T) *DB {
+	db, err := NewDB(&Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "audit.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+
+	applied := false
+	for _, m := range migrations {
+		if m.Version == "007" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+			applied = true
+		}
+	}
+	require.True(t, applied, "migration 007 should be embedded")
+
+	return db
+}
+
+func TestAuditLogRepository_InsertAndList(t *testing.T) {
+	db := setupAuditTestDB(t)
+	repo := NewAuditLogRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*AuditLogEntry{
+		{OccurredAt: base, UserID: "alice", UserRole: "admin", Method: "POST", Path: "/api/v1/database/optimize", StatusCode: 202},
+		{OccurredAt: base.Add(time.Minute), UserID: "bob", UserRole: "operator", Method: "POST", Path: "/api/v1/volumes/data/scan", StatusCode: 200},
+		{OccurredAt: base.Add(2 * time.Minute), UserID: "bob", UserRole: "operator", Method: "DELETE", Path: "/api/v1/volumes/data_1", StatusCode: 403},
+	}
+	for _, entry := range entries {
+		require.NoError(t, repo.Insert(ctx, entry))
+	}
+
+	// Newest first
+	all, total, err := repo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, all, 3)
+	assert.Equal(t, "DELETE", all[0].Method)
+	assert.True(t, all[2].OccurredAt.Equal(base))
+
+	byUser, total, err := repo.List(ctx, &AuditLogListOptions{UserID: "bob", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, byUser, 1)
+	assert.Equal(t, "/api/v1/volumes/data_1", byUser[0].Path)
+
+	byStatus, _, err := repo.List(ctx, &AuditLogListOptions{StatusCode: 403})
+	require.NoError(t, err)
+	require.Len(t, byStatus, 1)
+	assert.Equal(t, "bob", byStatus[0].UserID)
+
+	byWindow, _, err := repo.List(ctx, &AuditLogListOptions{Since: base.Add(30 * time.Second), Until: base.Add(90 * time.Second)})
+	require.NoError(t, err)
+	require.Len(t, byWindow, 1)
+	assert.Equal(t, "/api/v1/volumes/data/scan", byWindow[0].Path)
+
+	// Path prefixes match literally: "_" is not a wildcard
+	byPath, _, err := repo.List(ctx, &AuditLogListOptions{PathPrefix: "/api/v1/volumes/data_"})
+	require.NoError(t, err)
+	require.Len(t, byPath, 1)
+	assert.Equal(t, "DELETE", byPath[0].Method)
+}
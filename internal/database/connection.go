@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"  // PostgreSQL driver
@@ -32,6 +33,8 @@ type Config struct {
 	MaxIdleConns int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
 	ConnMaxLife  time.Duration `yaml:"conn_max_life" env:"DB_CONN_MAX_LIFE"`
 	Timeout      time.Duration `yaml:"timeout" env:"DB_TIMEOUT"`
+	// MigrationsDir holds additional migrations applied after the built-in ones
+	MigrationsDir string `yaml:"migrations_dir" env:"MIGRATIONS_DIR"`
 }
 
 // DefaultConfig returns database configuration with sensible defaults
@@ -97,11 +100,21 @@ func (c *Config) DSN() string {
 func (c *Config) postgresqlDSN() string {
 	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d application_name=volumeviz statement_timeout=%d lock_timeout=30000 idle_in_transaction_session_timeout=60000",
-		c.Host, c.Port, c.User, c.Password, c.Database, c.SSLMode,
+		dsnValue(c.Host), c.Port, dsnValue(c.User), dsnValue(c.Password), dsnValue(c.Database), c.SSLMode,
 		int(c.Timeout.Seconds()), int(c.Timeout.Milliseconds()),
 	)
 }
 
+// dsnValue quotes a libpq connection string value when it is empty or holds
+// whitespace, quotes or backslashes. Unquoted, such a password would split
+// the DSN and the driver would echo a fragment of it in its parse error.
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\\") {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
 // sqliteDSN returns the SQLite connection string
 func (c *Config) sqliteDSN() string {
 	path := c.Path
@@ -147,7 +160,7 @@ func NewDB(config *Config) (*DB, error) {
 
 	sqlDB, err := sql.Open(driverName, config.DSN())
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
+		return nil, fmt.Errorf("failed to open database connection: %w", config.redactError(err))
 	}
 
 	// Configure connection pool based on database type
@@ -165,7 +178,7 @@ func NewDB(config *Config) (*DB, error) {
 	// Test connection
 	if err := sqlDB.Ping(); err != nil {
 		sqlDB.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", config.redactError(err))
 	}
 
 	db := &DB{
@@ -177,7 +190,7 @@ func NewDB(config *Config) (*DB, error) {
 	// Apply database-specific performance optimizations
 	if err := db.applyPerformanceOptimizations(); err != nil {
 		sqlDB.Close()
-		return nil, fmt.Errorf("failed to apply performance optimizations: %w", err)
+		return nil, fmt.Errorf("failed to apply performance optimizations: %w", config.redactError(err))
 	}
 
 	return db, nil
@@ -329,18 +342,43 @@ func (db *DB) applyPostgreSQLOptimizations() error {
 	return nil
 }
 
+// OptimizationResult describes a completed optimization run
+type OptimizationResult struct {
+	Backend    DatabaseType  `json:"backend"`
+	Operations []string      `json:"operations"`
+	StartedAt  time.Time     `json:"started_at"`
+	Duration   time.Duration `json:"duration"`
+}
+
 // OptimizeDatabase runs database-specific optimization routines
 // For SQLite: VACUUM, ANALYZE, and other maintenance operations
 // For PostgreSQL: ANALYZE and other maintenance operations
 func (db *DB) OptimizeDatabase() error {
+	_, err := db.RunOptimization()
+	return err
+}
+
+// RunOptimization runs the database-specific optimization routines and reports
+// which operations were performed and how long they took
+func (db *DB) RunOptimization() (*OptimizationResult, error) {
+	result := &OptimizationResult{
+		Backend:   db.dbType,
+		StartedAt: time.Now(),
+	}
+
+	var err error
 	if db.IsSQLite() {
-		return db.optimizeSQLite()
+		result.Operations, err = db.optimizeSQLite()
+	} else {
+		result.Operations, err = db.optimizePostgreSQL()
 	}
-	return db.optimizePostgreSQL()
+	result.Duration = time.Since(result.StartedAt)
+
+	return result, err
 }
 
 // optimizeSQLite runs SQLite-specific optimization operations
-func (db *DB) optimizeSQLite() error {
+func (db *DB) optimizeSQLite() ([]string, error) {
 	operations := []string{
 		// Analyze database for query planner statistics
 		"ANALYZE",
@@ -352,17 +390,21 @@ func (db *DB) optimizeSQLite() error {
 		"PRAGMA optimize",
 	}
 
+	performed := make([]string, 0, len(operations))
 	for _, op := range operations {
 		if _, err := db.Exec(op); err != nil {
-			return fmt.Errorf("failed to execute SQLite optimization '%s': %w", op, err)
+			return performed, fmt.Errorf("failed to execute SQLite optimization '%s': %w", op, err)
 		}
+		performed = append(performed, op)
 	}
 
-	return nil
+	return performed, nil
 }
 
 // optimizePostgreSQL runs PostgreSQL-specific optimization operations
-func (db *DB) optimizePostgreSQL() error {
+func (db *DB) optimizePostgreSQL() ([]string, error) {
+	var performed []string
+
 	// Get list of tables in the volumeviz schema
 	tables := []string{
 		"volumes", "volume_sizes", "containers", "volume_mounts",
@@ -375,13 +417,16 @@ func (db *DB) optimizePostgreSQL() error {
 		if _, err := db.Exec("ANALYZE " + table); err != nil {
 			// Log warning but continue with other tables
 			fmt.Printf("Warning: Failed to analyze table '%s': %v\n", table, err)
+			continue
 		}
+		performed = append(performed, "ANALYZE "+table)
 	}
 
 	// Run global ANALYZE for cross-table statistics
 	if _, err := db.Exec("ANALYZE"); err != nil {
-		return fmt.Errorf("failed to analyze PostgreSQL database: %w", err)
+		return performed, fmt.Errorf("failed to analyze PostgreSQL database: %w", err)
 	}
+	performed = append(performed, "ANALYZE")
 
 	// Update extension statistics if available (PostgreSQL 10+)
 	extensions := []string{
@@ -394,7 +439,9 @@ func (db *DB) optimizePostgreSQL() error {
 			// Extended statistics might not be supported or might already exist
 			// This is not critical, so we just log warnings
 			fmt.Printf("Info: Extended statistics creation skipped: %v\n", err)
+			continue
 		}
+		performed = append(performed, stmt)
 	}
 
 	// Refresh materialized views if any exist (future-proofing)
@@ -406,10 +453,12 @@ func (db *DB) optimizePostgreSQL() error {
 	for _, refresh := range refreshViews {
 		if _, err := db.Exec(refresh); err != nil {
 			fmt.Printf("Warning: Failed to refresh materialized view: %v\n", err)
+			continue
 		}
+		performed = append(performed, refresh)
 	}
 
-	return nil
+	return performed, nil
 }
 
 // BeginTx starts a new database transaction with context
@@ -472,7 +521,7 @@ func (db *DB) Health() *HealthStatus {
 
 	if err != nil {
 		status.Status = "unhealthy"
-		status.Error = err.Error()
+		status.Error = db.RedactError(err)
 		return status
 	}
 
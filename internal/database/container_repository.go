@@ -0,0 +1,383 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// ContainerWithMounts is a synced container together with its active volume mount count
+type ContainerWithMounts struct {
+	Container
+	ActiveMounts int `db:"active_mounts" json:"active_mounts"`
+}
+
+// ContainerListOptions holds filters, sorting and pagination for container listing
+type ContainerListOptions struct {
+	State  string            // Exact container state (running, exited, ...)
+	Image  string            // Exact image, or repository without tag (nginx matches nginx:1.25)
+	Labels map[string]string // Label filters; an empty value only requires the key to exist
+
+	SortBy   string // One of ContainerSortFields
+	SortDesc bool
+	Limit    int
+	Offset   int
+}
+
+// ContainerSortFields maps sortable API fields to container list columns
+var ContainerSortFields = map[string]string{
+	"name":          "c.name",
+	"image":         "c.image",
+	"state":         "c.state",
+	"created_at":    "c.created_at",
+	"started_at":    "c.started_at",
+	"active_mounts": "active_mounts",
+}
+
+// ContainerRepository provides read access to containers synced from Docker events
+// Writes go through EventRepository so the events handler and reconciler own the data
+type ContainerRepository struct {
+	*BaseRepository
+}
+
+// NewContainerRepository creates a new container repository
+func NewContainerRepository(db *DB) *ContainerRepository {
+	return &ContainerRepository{
+		BaseRepository: NewBaseRepository(db),
+	}
+}
+
+// WithTx returns a new container repository instance using the provided transaction
+func (r *ContainerRepository) WithTx(tx *Tx) *ContainerRepository {
+	return &ContainerRepository{
+		BaseRepository: r.BaseRepository.WithTx(tx),
+	}
+}
+
+// List returns active containers matching the options and the total number of matches
+func (r *ContainerRepository) List(ctx context.Context, options *ContainerListOptions) ([]*ContainerWithMounts, int, error) {
+	if options == nil {
+		options = &ContainerListOptions{}
+	}
+
+	mountCounts := `LEFT JOIN (
+		SELECT container_id, COUNT(*) AS active_mounts
+		FROM volume_mounts
+		WHERE is_active = true
+		GROUP BY container_id
+	) m ON m.container_id = c.container_id`
+
+	qb := NewQueryBuilder().
+		Select("c.id", "c.container_id", "c.name", "c.image", "c.state", "c.status", "c.labels",
+			"c.started_at", "c.finished_at", "c.is_active", "c.created_at", "c.updated_at",
+			"COALESCE(m.active_mounts, 0) AS active_mounts").
+		From(TableNames.Containers + " c").
+		Join(mountCounts)
+	r.applyContainerFilters(qb, options)
+
+	orderBy := "c.name"
+	if column, ok := ContainerSortFields[options.SortBy]; ok {
+		orderBy = column
+	}
+	if options.SortDesc {
+		orderBy += " DESC"
+	}
+	qb.OrderBy(orderBy)
+	if orderBy != "c.name" {
+		// Stable ordering across pages
+		qb.OrderBy("c.name")
+	}
+
+	if options.Limit > 0 {
+		qb.Limit(options.Limit)
+	}
+	if options.Offset > 0 {
+		qb.Offset(options.Offset)
+	}
+
+	query, args := qb.Build()
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	containers, err := ScanRows(rows, scanContainerWithMounts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.countContainers(options)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return containers, total, nil
+}
+
+// GetVolumeLastDetachedAt returns when a container last stopped using a volume,
+// or nil if the volume has an active mount or was never seen mounted
+func (r *ContainerRepository) GetVolumeLastDetachedAt(ctx context.Context, volumeName string) (*time.Time, error) {
+	executor := r.getExecutor()
+
+	var active int
+	activeQuery := `SELECT COUNT(*) FROM volume_mounts WHERE volume_id = $1 AND is_active = true`
+	if err := executor.QueryRow(activeQuery, volumeName).Scan(&active); err != nil {
+		return nil, err
+	}
+	if active > 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT updated_at
+		FROM volume_mounts
+		WHERE volume_id = $1 AND is_active = false
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`
+
+	var detachedAt time.Time
+	err := executor.QueryRow(query, volumeName).Scan(&detachedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &detachedAt, nil
+}
+
+// countContainers returns the number of active containers matching the filters
+func (r *ContainerRepository) countContainers(options *ContainerListOptions) (int, error) {
+	qb := NewQueryBuilder().
+		Select("COUNT(*)").
+		From(TableNames.Containers + " c")
+	r.applyContainerFilters(qb, options)
+
+	query, args := qb.Build()
+
+	executor := r.getExecutor()
+	var count int
+	err := executor.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// applyContainerFilters adds the WHERE conditions shared by the list and count queries
+func (r *ContainerRepository) applyContainerFilters(qb *QueryBuilder, options *ContainerListOptions) {
+	qb.Where("c.is_active = ?", true)
+
+	if options.State != "" {
+		qb.Where("c.state = ?", options.State)
+	}
+
+	if options.Image != "" {
+		qb.Where("(c.image = ? OR c.image LIKE ?)", options.Image, options.Image+":%")
+	}
+
+	for key, value := range options.Labels {
+		if r.db.IsSQLite() {
+			path := `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+			if value == "" {
+				qb.Where("json_extract(c.labels, ?) IS NOT NULL", path)
+			} else {
+				qb.Where("json_extract(c.labels, ?) = ?", path, value)
+			}
+			continue
+		}
+
+		if value == "" {
+			qb.Where("c.labels->>? IS NOT NULL", key)
+		} else {
+			qb.Where("c.labels->>? = ?", key, value)
+		}
+	}
+}
+
+// scanContainerWithMounts scans a container list row
+func scanContainerWithMounts(rows *sql.Rows) (*ContainerWithMounts, error) {
+	container := &ContainerWithMounts{}
+	var startedAt, finishedAt sql.NullTime
+	var status sql.NullString
+
+	err := rows.Scan(
+		&container.ID,
+		&container.ContainerID,
+		&container.Name,
+		&container.Image,
+		&container.State,
+		&status,
+		&container.Labels,
+		&startedAt,
+		&finishedAt,
+		&container.IsActive,
+		&container.CreatedAt,
+		&container.UpdatedAt,
+		&container.ActiveMounts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	container.Status = status.String
+	if startedAt.Valid {
+		container.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		container.FinishedAt = &finishedAt.Time
+	}
+
+	return container, nil
+}
+
+// VolumeMountWithNames is a volume mount joined with its volume and container names
+type VolumeMountWithNames struct {
+	VolumeMount
+	VolumeName    string `db:"volume_name" json:"volume_name"`
+	ContainerName string `db:"container_name" json:"container_name"`
+}
+
+// VolumeMountListOptions holds filters, sorting and pagination for mount listing
+type VolumeMountListOptions struct {
+	Volume     string // Volume ID or name
+	Container  string // Container ID or name, with or without the leading slash
+	AccessMode string // rw or ro
+	Active     *bool  // Only active or only ended mounts; nil for both
+
+	SortBy   string // One of VolumeMountSortFields
+	SortDesc bool
+	Limit    int
+	Offset   int
+}
+
+// VolumeMountSortFields maps sortable API fields to mount list columns
+var VolumeMountSortFields = map[string]string{
+	"volume_name":    "volume_name",
+	"container_name": "container_name",
+	"mount_path":     "m.mount_path",
+	"access_mode":    "m.access_mode",
+	"created_at":     "m.created_at",
+	"updated_at":     "m.updated_at",
+}
+
+// volumeMountJoins resolves the volume and container names of a mount; foreign
+// keys guarantee both rows exist
+const volumeMountJoins = `JOIN volumes v ON v.volume_id = m.volume_id
+	JOIN containers c ON c.container_id = m.container_id`
+
+// ListMounts returns volume mounts across all volumes and containers matching
+// the options, and the total number of matches. Unlike
+// EventRepository.ListAllVolumeMounts it includes ended mounts unless filtered.
+func (r *ContainerRepository) ListMounts(ctx context.Context, options *VolumeMountListOptions) ([]*VolumeMountWithNames, int, error) {
+	if options == nil {
+		options = &VolumeMountListOptions{}
+	}
+
+	qb := NewQueryBuilder().
+		Select("m.id", "m.volume_id", "m.container_id", "m.mount_path", "m.access_mode", "m.is_active",
+			"m.created_at", "m.updated_at",
+			"v.name AS volume_name", "c.name AS container_name").
+		From(TableNames.VolumeMounts + " m").
+		Join(volumeMountJoins)
+	r.applyMountFilters(qb, options)
+
+	orderBy := "volume_name"
+	if column, ok := VolumeMountSortFields[options.SortBy]; ok {
+		orderBy = column
+	}
+	if options.SortDesc {
+		orderBy += " DESC"
+	}
+	qb.OrderBy(orderBy)
+	// Stable ordering across pages
+	qb.OrderBy("volume_name").OrderBy("container_name").OrderBy("m.mount_path").OrderBy("m.id")
+
+	if options.Limit > 0 {
+		qb.Limit(options.Limit)
+	}
+	if options.Offset > 0 {
+		qb.Offset(options.Offset)
+	}
+
+	query, args := qb.Build()
+
+	executor := r.getExecutor()
+	rows, err := executor.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	mounts, err := ScanRows(rows, scanVolumeMountWithNames)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.countMounts(options)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return mounts, total, nil
+}
+
+// countMounts returns the number of volume mounts matching the filters
+func (r *ContainerRepository) countMounts(options *VolumeMountListOptions) (int, error) {
+	qb := NewQueryBuilder().
+		Select("COUNT(*)").
+		From(TableNames.VolumeMounts + " m").
+		Join(volumeMountJoins)
+	r.applyMountFilters(qb, options)
+
+	query, args := qb.Build()
+
+	executor := r.getExecutor()
+	var count int
+	err := executor.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// applyMountFilters adds the WHERE conditions shared by the mount list and count queries
+func (r *ContainerRepository) applyMountFilters(qb *QueryBuilder, options *VolumeMountListOptions) {
+	if options.Volume != "" {
+		qb.Where("(m.volume_id = ? OR v.name = ?)", options.Volume, options.Volume)
+	}
+
+	if options.Container != "" {
+		// Docker stores container names with a leading slash
+		name := "/" + strings.TrimPrefix(options.Container, "/")
+		qb.Where("(m.container_id = ? OR c.name = ?)", options.Container, name)
+	}
+
+	if options.AccessMode != "" {
+		qb.Where("m.access_mode = ?", options.AccessMode)
+	}
+
+	if options.Active != nil {
+		qb.Where("m.is_active = ?", *options.Active)
+	}
+}
+
+// scanVolumeMountWithNames scans a mount list row
+func scanVolumeMountWithNames(rows *sql.Rows) (*VolumeMountWithNames, error) {
+	mount := &VolumeMountWithNames{}
+	err := rows.Scan(
+		&mount.ID,
+		&mount.VolumeID,
+		&mount.ContainerID,
+		&mount.MountPath,
+		&mount.AccessMode,
+		&mount.IsActive,
+		&mount.CreatedAt,
+		&mount.UpdatedAt,
+		&mount.VolumeName,
+		&mount.ContainerName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return mount, nil
+}
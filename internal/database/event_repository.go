@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 )
 
 // EventRepository handles event-related database operations
@@ -68,6 +70,186 @@ func (r *EventRepository) UpsertVolume(ctx context.Context, volume *Volume) erro
 	return nil
 }
 
+// volumeUpsertColumns is the column list shared by single and bulk volume upserts
+const volumeUpsertColumns = "volume_id, name, driver, mountpoint, labels, options, scope, status, is_active, created_at, updated_at"
+
+// volumeUpsertColumnCount is the number of columns in volumeUpsertColumns
+const volumeUpsertColumnCount = 11
+
+// MaxBulkVolumeUpsert is the most volumes one BulkUpsertVolumes statement can
+// hold under SQLite's default limit of 32766 bound parameters; PostgreSQL
+// allows twice as many
+const MaxBulkVolumeUpsert = 32766 / volumeUpsertColumnCount
+
+// volumeUpsertConflictClause updates every mutable column when a volume_id already exists
+const volumeUpsertConflictClause = `
+		ON CONFLICT (volume_id)
+		DO UPDATE SET
+			name = EXCLUDED.name,
+			driver = EXCLUDED.driver,
+			mountpoint = EXCLUDED.mountpoint,
+			labels = EXCLUDED.labels,
+			options = EXCLUDED.options,
+			scope = EXCLUDED.scope,
+			status = EXCLUDED.status,
+			is_active = EXCLUDED.is_active,
+			updated_at = EXCLUDED.updated_at
+	`
+
+// VolumeUpsertFailure records a single volume that could not be written during a bulk upsert
+type VolumeUpsertFailure struct {
+	VolumeID string
+	Err      error
+}
+
+// BulkUpsertResult summarizes the outcome of a bulk volume upsert
+type BulkUpsertResult struct {
+	Upserted int
+	Failed   []VolumeUpsertFailure
+}
+
+// BulkUpsertVolumes creates or updates many volumes with a single multi-row statement
+// of at most MaxBulkVolumeUpsert volumes; callers split larger sets into batches.
+// Runs inside a transaction (the repository's own, or a new one). If the batch
+// statement fails, rows are retried one at a time under savepoints so a single
+// bad row is reported in the result instead of aborting the whole batch.
+func (r *EventRepository) BulkUpsertVolumes(ctx context.Context, volumes []*Volume) (*BulkUpsertResult, error) {
+	result := &BulkUpsertResult{}
+	volumes = dedupeVolumesByID(volumes)
+	if len(volumes) == 0 {
+		return result, nil
+	}
+
+	// Own the transaction unless the caller already supplied one
+	txRepo := r
+	if r.tx == nil {
+		tx, err := r.db.BeginTx()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		txRepo = r.WithTx(tx)
+	}
+
+	executor := txRepo.getExecutor()
+
+	if _, err := executor.Exec("SAVEPOINT bulk_upsert_volumes"); err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	query, args := buildBulkVolumeUpsert(volumes, r.db.IsSQLite())
+	if _, err := executor.Exec(query, args...); err == nil {
+		if _, err := executor.Exec("RELEASE SAVEPOINT bulk_upsert_volumes"); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		result.Upserted = len(volumes)
+	} else {
+		log.Printf("[WARN] Bulk volume upsert of %d rows failed, retrying row by row: %v", len(volumes), err)
+		if _, err := executor.Exec("ROLLBACK TO SAVEPOINT bulk_upsert_volumes"); err != nil {
+			return nil, fmt.Errorf("failed to roll back to savepoint: %w", err)
+		}
+
+		for _, volume := range volumes {
+			if err := txRepo.upsertVolumeWithSavepoint(volume); err != nil {
+				result.Failed = append(result.Failed, VolumeUpsertFailure{VolumeID: volume.VolumeID, Err: err})
+				continue
+			}
+			result.Upserted++
+		}
+	}
+
+	if r.tx == nil {
+		if err := txRepo.tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit bulk volume upsert: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// upsertVolumeWithSavepoint writes a single volume, rolling back only that row on failure
+func (r *EventRepository) upsertVolumeWithSavepoint(volume *Volume) error {
+	executor := r.getExecutor()
+
+	if _, err := executor.Exec("SAVEPOINT upsert_volume_row"); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	query, args := buildBulkVolumeUpsert([]*Volume{volume}, r.db.IsSQLite())
+	if _, err := executor.Exec(query, args...); err != nil {
+		if _, rbErr := executor.Exec("ROLLBACK TO SAVEPOINT upsert_volume_row"); rbErr != nil {
+			return fmt.Errorf("failed to upsert volume: %w (rollback failed: %v)", err, rbErr)
+		}
+		return fmt.Errorf("failed to upsert volume: %w", err)
+	}
+
+	if _, err := executor.Exec("RELEASE SAVEPOINT upsert_volume_row"); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+// buildBulkVolumeUpsert builds a multi-row INSERT ... ON CONFLICT statement.
+// The SQLite driver looks up every $N parameter among all the arguments, which
+// grows with the square of the batch, so SQLite statements bind ? in order.
+func buildBulkVolumeUpsert(volumes []*Volume, sqlite bool) (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO volumes (" + volumeUpsertColumns + ") VALUES ")
+
+	args := make([]interface{}, 0, len(volumes)*volumeUpsertColumnCount)
+	for i, volume := range volumes {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for col := 0; col < volumeUpsertColumnCount; col++ {
+			if col > 0 {
+				sb.WriteString(", ")
+			}
+			if sqlite {
+				sb.WriteString("?")
+			} else {
+				fmt.Fprintf(&sb, "$%d", i*volumeUpsertColumnCount+col+1)
+			}
+		}
+		sb.WriteString(")")
+
+		args = append(args,
+			volume.VolumeID,
+			volume.Name,
+			volume.Driver,
+			volume.Mountpoint,
+			volume.Labels,
+			volume.Options,
+			volume.Scope,
+			volume.Status,
+			volume.IsActive,
+			volume.CreatedAt,
+			volume.UpdatedAt,
+		)
+	}
+	sb.WriteString(volumeUpsertConflictClause)
+
+	return sb.String(), args
+}
+
+// dedupeVolumesByID keeps the last entry for each volume_id
+// PostgreSQL rejects ON CONFLICT DO UPDATE touching the same row twice in one statement
+func dedupeVolumesByID(volumes []*Volume) []*Volume {
+	index := make(map[string]int, len(volumes))
+	deduped := make([]*Volume, 0, len(volumes))
+	for _, volume := range volumes {
+		if i, exists := index[volume.VolumeID]; exists {
+			deduped[i] = volume
+			continue
+		}
+		index[volume.VolumeID] = len(deduped)
+		deduped = append(deduped, volume)
+	}
+	return deduped
+}
+
 // DeleteVolume deletes a volume by its volume_id (Docker volume name)
 func (r *EventRepository) DeleteVolume(ctx context.Context, volumeID string) error {
 	// First deactivate all volume mounts for this volume
@@ -98,6 +280,12 @@ func (r *EventRepository) DeleteVolume(ctx context.Context, volumeID string) err
 	if rowsAffected == 0 {
 		// Volume didn't exist, but that's ok for idempotent operations
 		log.Printf("[DEBUG] Volume %s not found during delete (already removed)", volumeID)
+		return nil
+	}
+
+	// Leave a tombstone so change listings can report the removal
+	if err := r.recordVolumeTombstone(volumeID, time.Now()); err != nil {
+		log.Printf("[WARN] Failed to record removal of volume %s: %v", volumeID, err)
 	}
 
 	return nil
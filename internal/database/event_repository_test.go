@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEventRepositoryTestDB creates a SQLite database with the initial schema applied
+func setupEventRepositoryTestDB(t *testing.T) *DB {
+	config := &Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "events.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	}
+
+	db, err := NewDB(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	require.Equal(t, "001", migrations[0].Version)
+
+	_, err = db.Exec(migrations[0].UpSQL)
+	require.NoError(t, err)
+
+	return db
+}
+
+func makeBulkTestVolumes(count int, driver string) []*Volume {
+	now := time.Now()
+	volumes := make([]*Volume, count)
+	for i := range volumes {
+		name := fmt.Sprintf("bulk-volume-%04d", i)
+		volumes[i] = &Volume{
+			VolumeID:   name,
+			Name:       name,
+			Driver:     driver,
+			Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+			Scope:      "local",
+			Status:     "active",
+			IsActive:   true,
+			BaseModel: BaseModel{
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+		}
+	}
+	return volumes
+}
+
+func countVolumes(t *testing.T, db *DB, where string, args ...interface{}) int {
+	var count int
+	query := "SELECT COUNT(*) FROM volumes"
+	if where != "" {
+		query += " WHERE " + where
+	}
+	require.NoError(t, db.QueryRow(query, args...).Scan(&count))
+	return count
+}
+
+func TestEventRepository_BulkUpsertVolumes(t *testing.T) {
+	db := setupEventRepositoryTestDB(t)
+	repo := NewEventRepository(db)
+	ctx := context.Background()
+
+	// Initial insert
+	volumes := makeBulkTestVolumes(250, "local")
+	result, err := repo.BulkUpsertVolumes(ctx, volumes)
+	require.NoError(t, err)
+	assert.Equal(t, 250, result.Upserted)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, 250, countVolumes(t, db, ""))
+
+	// Overlapping upsert: 100 existing rows change driver, 50 rows are new
+	updated := makeBulkTestVolumes(300, "nfs")[150:]
+	result, err = repo.BulkUpsertVolumes(ctx, updated)
+	require.NoError(t, err)
+	assert.Equal(t, 150, result.Upserted)
+	assert.Equal(t, 300, countVolumes(t, db, ""))
+	assert.Equal(t, 150, countVolumes(t, db, "driver = $1", "nfs"))
+	assert.Equal(t, 150, countVolumes(t, db, "driver = $1", "local"))
+
+	// Duplicate IDs within one batch keep the last entry
+	dup := makeBulkTestVolumes(1, "first")
+	dup = append(dup, makeBulkTestVolumes(1, "second")...)
+	result, err = repo.BulkUpsertVolumes(ctx, dup)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Upserted)
+	assert.Equal(t, 1, countVolumes(t, db, "volume_id = $1 AND driver = $2", "bulk-volume-0000", "second"))
+
+	// Empty input is a no-op
+	result, err = repo.BulkUpsertVolumes(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Upserted)
+}
+
+func TestEventRepository_BulkUpsertVolumes_PerRowErrors(t *testing.T) {
+	db := setupEventRepositoryTestDB(t)
+	repo := NewEventRepository(db)
+	ctx := context.Background()
+
+	// Reject one specific row so the batch statement fails
+	_, err := db.Exec(`
+		CREATE TRIGGER reject_bad_volume BEFORE INSERT ON volumes
+		WHEN NEW.volume_id = 'bulk-volume-0003'
+		BEGIN
+			SELECT RAISE(ABORT, 'rejected by test');
+		END
+	`)
+	require.NoError(t, err)
+
+	volumes := makeBulkTestVolumes(10, "local")
+	result, err := repo.BulkUpsertVolumes(ctx, volumes)
+	require.NoError(t, err)
+
+	// The bad row is reported, every other row is still written
+	assert.Equal(t, 9, result.Upserted)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "bulk-volume-0003", result.Failed[0].VolumeID)
+	assert.Error(t, result.Failed[0].Err)
+	assert.Equal(t, 9, countVolumes(t, db, ""))
+	assert.Equal(t, 0, countVolumes(t, db, "volume_id = $1", "bulk-volume-0003"))
+}
+
+func TestEventRepository_BulkUpsertVolumes_CallerTransaction(t *testing.T) {
+	db := setupEventRepositoryTestDB(t)
+	ctx := context.Background()
+
+	tx, err := db.BeginTx()
+	require.NoError(t, err)
+
+	result, err := NewEventRepository(db).WithTx(tx).BulkUpsertVolumes(ctx, makeBulkTestVolumes(5, "local"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Upserted)
+
+	// Nothing is visible until the caller decides, and a rollback discards everything
+	require.NoError(t, tx.Rollback())
+	assert.Equal(t, 0, countVolumes(t, db, ""))
+}
+
+func TestBuildBulkVolumeUpsert(t *testing.T) {
+	query, args := buildBulkVolumeUpsert(makeBulkTestVolumes(2, "local"), false)
+
+	assert.Contains(t, query, "($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11), ($12,")
+	assert.Contains(t, query, "$22)")
+	assert.Contains(t, query, "ON CONFLICT (volume_id)")
+	assert.Len(t, args, 22)
+	assert.Equal(t, "bulk-volume-0000", args[0])
+	assert.Equal(t, "bulk-volume-0001", args[11])
+
+	// SQLite binds its parameters in order
+	query, args = buildBulkVolumeUpsert(makeBulkTestVolumes(2, "local"), true)
+	assert.Contains(t, query, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?), (?,")
+	assert.NotContains(t, query, "$")
+	assert.Len(t, args, 22)
+}
+
+// BenchmarkEventRepository_BulkUpsertVolumes compares writing volumes one
+// Create at a time with bulk upserts in batches like the reconciler's
+func BenchmarkEventRepository_BulkUpsertVolumes(b *testing.B) {
+	const numVolumes = 1000
+	ctx := context.Background()
+
+	b.Run("Serial", func(b *testing.B) {
+		for range b.N {
+			b.StopTimer()
+			repo := NewVolumeRepository(setupVolumeTestDB(b))
+			volumes := makeBulkTestVolumes(numVolumes, "local")
+			b.StartTimer()
+
+			for _, volume := range volumes {
+				if err := repo.Create(volume); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(numVolumes*b.N)/b.Elapsed().Seconds(), "volumes/s")
+	})
+
+	for _, batchSize := range []int{10, 100, 500} {
+		b.Run(fmt.Sprintf("Batch%d", batchSize), func(b *testing.B) {
+			for range b.N {
+				b.StopTimer()
+				repo := NewEventRepository(setupVolumeTestDB(b))
+				volumes := makeBulkTestVolumes(numVolumes, "local")
+				b.StartTimer()
+
+				for start := 0; start < len(volumes); start += batchSize {
+					result, err := repo.BulkUpsertVolumes(ctx, volumes[start:min(start+batchSize, len(volumes))])
+					if err != nil || len(result.Failed) > 0 {
+						b.Fatal(err, result)
+					}
+				}
+			}
+			b.ReportMetric(float64(numVolumes*b.N)/b.Elapsed().Seconds(), "volumes/s")
+		})
+	}
+}
@@ -3,6 +3,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 )
@@ -106,33 +107,34 @@ func (r *VolumeMetricsRepository) GetMetrics(ctx context.Context, volumeID strin
 	}
 	defer rows.Close()
 
-	var metrics []VolumeMetrics
-	for rows.Next() {
-		var m VolumeMetrics
-		var growthRate *float64
+	return r.scanMetricsRows(rows)
+}
 
-		err := rows.Scan(
-			&m.ID,
-			&m.CreatedAt,
-			&m.UpdatedAt,
-			&m.VolumeID,
-			&m.MetricTimestamp,
-			&m.TotalSize,
-			&m.FileCount,
-			&m.DirectoryCount,
-			&growthRate,
-			&m.AccessFrequency,
-			&m.ContainerCount,
-		)
-		if err != nil {
-			return nil, err
-		}
+// GetMetricsByLogicalKey retrieves historical metrics for every physical volume
+// grouped under a logical key (see AnnotationKeyLogicalKey), merged into one
+// series so trends survive a volume being recreated under a new name
+func (r *VolumeMetricsRepository) GetMetricsByLogicalKey(ctx context.Context, logicalKey string, startTime, endTime time.Time, limit int) ([]VolumeMetrics, error) {
+	query := `
+		SELECT id, created_at, updated_at, volume_id, metric_timestamp,
+		       total_size, file_count, directory_count, growth_rate,
+		       access_frequency, container_count
+		FROM volume_metrics
+		WHERE (volume_id = ? OR volume_id IN (
+		          SELECT volume_name FROM volume_annotations
+		          WHERE annotation_key = ? AND annotation_value = ?
+		      ))
+		  AND metric_timestamp BETWEEN ? AND ?
+		ORDER BY metric_timestamp DESC
+		LIMIT ?
+	`
 
-		m.GrowthRate = growthRate
-		metrics = append(metrics, m)
+	rows, err := r.db.QueryContext(ctx, query, logicalKey, AnnotationKeyLogicalKey, logicalKey, startTime, endTime, limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return metrics, rows.Err()
+	return r.scanMetricsRows(rows)
 }
 
 // GetTrends calculates growth trends for one or more volumes
@@ -230,6 +232,37 @@ func (r *VolumeMetricsRepository) GetAllActiveVolumeIDs(ctx context.Context) ([]
 
 // Helper functions
 
+// scanMetricsRows scans volume_metrics rows selected in the standard column order
+func (r *VolumeMetricsRepository) scanMetricsRows(rows *sql.Rows) ([]VolumeMetrics, error) {
+	var metrics []VolumeMetrics
+	for rows.Next() {
+		var m VolumeMetrics
+		var growthRate *float64
+
+		err := rows.Scan(
+			&m.ID,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+			&m.VolumeID,
+			&m.MetricTimestamp,
+			&m.TotalSize,
+			&m.FileCount,
+			&m.DirectoryCount,
+			&growthRate,
+			&m.AccessFrequency,
+			&m.ContainerCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		m.GrowthRate = growthRate
+		metrics = append(metrics, m)
+	}
+
+	return metrics, rows.Err()
+}
+
 // Transaction-aware helper functions
 
 func (r *VolumeMetricsRepository) calculateGrowthRateInTx(ctx context.Context, tx *Tx, volumeID string, currentSize int64) (*float64, error) {
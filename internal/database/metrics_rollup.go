@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RollupGranularity is the bucket width of a volume metrics rollup
+type RollupGranularity string
+
+// Supported rollup granularities
+const (
+	RollupDaily  RollupGranularity = "daily"
+	RollupWeekly RollupGranularity = "weekly"
+)
+
+// RollupGranularities lists every granularity maintained by RollupMetrics
+var RollupGranularities = []RollupGranularity{RollupDaily, RollupWeekly}
+
+// table returns the rollup table holding buckets of this granularity
+func (g RollupGranularity) table() (string, error) {
+	switch g {
+	case RollupDaily:
+		return "volume_metrics_daily", nil
+	case RollupWeekly:
+		return "volume_metrics_weekly", nil
+	default:
+		return "", fmt.Errorf("unknown rollup granularity %q", g)
+	}
+}
+
+// BucketStart returns the UTC start of the bucket containing t.
+// Weekly buckets start on Monday.
+func (g RollupGranularity) BucketStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if g == RollupWeekly {
+		// Weekday is 0 on Sunday; shift so Monday is the first day of the week
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+// VolumeMetricsRollup summarizes the volume_metrics samples of one volume in
+// one daily or weekly bucket
+type VolumeMetricsRollup struct {
+	VolumeID    string    `db:"volume_id" json:"volume_id"`
+	BucketStart time.Time `db:"bucket_start" json:"bucket_start"`
+	MinSize     int64     `db:"min_size" json:"min_size"`
+	MaxSize     int64     `db:"max_size" json:"max_size"`
+	AvgSize     float64   `db:"avg_size" json:"avg_size"`
+	FirstSize   int64     `db:"first_size" json:"first_size"`
+	FirstAt     time.Time `db:"first_at" json:"first_at"`
+	LastSize    int64     `db:"last_size" json:"last_size"`
+	LastAt      time.Time `db:"last_at" json:"last_at"`
+	SampleCount int       `db:"sample_count" json:"sample_count"`
+}
+
+// add folds a raw sample into the rollup; samples must arrive oldest first
+func (r *VolumeMetricsRollup) add(ts time.Time, size int64) {
+	if r.SampleCount == 0 {
+		r.MinSize, r.MaxSize = size, size
+		r.FirstSize, r.FirstAt = size, ts
+	}
+	if size < r.MinSize {
+		r.MinSize = size
+	}
+	if size > r.MaxSize {
+		r.MaxSize = size
+	}
+	// Running mean avoids overflowing a sum of large sizes
+	r.SampleCount++
+	r.AvgSize += (float64(size) - r.AvgSize) / float64(r.SampleCount)
+	r.LastSize, r.LastAt = size, ts
+}
+
+// RollupMetrics refreshes the rollup table of a granularity from volume_metrics
+// and returns the number of buckets written. Only the newest stored bucket and
+// later ones are recomputed, since older buckets cannot receive new samples.
+func (r *VolumeMetricsRepository) RollupMetrics(ctx context.Context, granularity RollupGranularity) (int, error) {
+	table, err := granularity.table()
+	if err != nil {
+		return 0, err
+	}
+
+	var from time.Time
+	err = r.db.QueryRowContext(ctx, `SELECT bucket_start FROM `+table+` ORDER BY bucket_start DESC LIMIT 1`).Scan(&from)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to read rollup watermark: %w", err)
+	}
+
+	query := `
+		SELECT volume_id, metric_timestamp, total_size
+		FROM volume_metrics
+	`
+	args := []interface{}{}
+	if !from.IsZero() {
+		// Raw samples are stored in local time; compare in the same zone
+		query += ` WHERE metric_timestamp >= ?`
+		args = append(args, from.Local())
+	}
+	query += ` ORDER BY volume_id, metric_timestamp`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query metrics for rollup: %w", err)
+	}
+
+	// Rows arrive grouped by volume and in time order, so each bucket is
+	// complete once the next one starts
+	var buckets []*VolumeMetricsRollup
+	var current *VolumeMetricsRollup
+	for rows.Next() {
+		var volumeID string
+		var ts time.Time
+		var size int64
+		if err := rows.Scan(&volumeID, &ts, &size); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		start := granularity.BucketStart(ts)
+		if current == nil || current.VolumeID != volumeID || !current.BucketStart.Equal(start) {
+			current = &VolumeMetricsRollup{VolumeID: volumeID, BucketStart: start}
+			buckets = append(buckets, current)
+		}
+		current.add(ts, size)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	upsert := `
+		INSERT INTO ` + table + ` (
+			volume_id, bucket_start, min_size, max_size, avg_size,
+			first_size, first_at, last_size, last_at, sample_count, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (volume_id, bucket_start)
+		DO UPDATE SET
+			min_size = EXCLUDED.min_size,
+			max_size = EXCLUDED.max_size,
+			avg_size = EXCLUDED.avg_size,
+			first_size = EXCLUDED.first_size,
+			first_at = EXCLUDED.first_at,
+			last_size = EXCLUDED.last_size,
+			last_at = EXCLUDED.last_at,
+			sample_count = EXCLUDED.sample_count,
+			updated_at = EXCLUDED.updated_at
+	`
+	now := time.Now()
+	for _, b := range buckets {
+		_, err := tx.ExecContext(ctx, upsert,
+			b.VolumeID, b.BucketStart, b.MinSize, b.MaxSize, b.AvgSize,
+			b.FirstSize, b.FirstAt, b.LastSize, b.LastAt, b.SampleCount, now,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to write %s rollup: %w", granularity, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(buckets), nil
+}
+
+// GetRollups retrieves the rollup buckets of a volume overlapping a time range,
+// newest first
+func (r *VolumeMetricsRepository) GetRollups(ctx context.Context, volumeID string, granularity RollupGranularity, startTime, endTime time.Time, limit int) ([]VolumeMetricsRollup, error) {
+	table, err := granularity.table()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT volume_id, bucket_start, min_size, max_size, avg_size,
+		       first_size, first_at, last_size, last_at, sample_count
+		FROM ` + table + `
+		WHERE volume_id = ? AND bucket_start BETWEEN ? AND ?
+		ORDER BY bucket_start DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, volumeID, granularity.BucketStart(startTime), endTime.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rollups := make([]VolumeMetricsRollup, 0)
+	for rows.Next() {
+		var b VolumeMetricsRollup
+		err := rows.Scan(
+			&b.VolumeID,
+			&b.BucketStart,
+			&b.MinSize,
+			&b.MaxSize,
+			&b.AvgSize,
+			&b.FirstSize,
+			&b.FirstAt,
+			&b.LastSize,
+			&b.LastAt,
+			&b.SampleCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rollups = append(rollups, b)
+	}
+
+	return rollups, rows.Err()
+}
+
+// RollupsWithin returns the buckets, in their order, whose samples all fall in
+// [startTime, endTime]. Buckets straddling either end also summarize samples
+// outside the range, so they are left out.
+func RollupsWithin(rollups []VolumeMetricsRollup, startTime, endTime time.Time) []VolumeMetricsRollup {
+	within := make([]VolumeMetricsRollup, 0, len(rollups))
+	for _, b := range rollups {
+		if b.FirstAt.Before(startTime) || b.LastAt.After(endTime) {
+			continue
+		}
+		within = append(within, b)
+	}
+	return within
+}
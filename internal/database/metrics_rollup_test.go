@@ -0,0 +1,265 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupRollupTestDB creates a SQLite database with the rollup migration applied
+// and a minimal volume_metrics table holding the raw samples
+func setupRollupTestDB(t *testing.T) *DB {
+	db, err := NewDB(&Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "rollups.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+
+	applied := false
+	for _, m := range migrations {
+		if m.Version == "008" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+			applied = true
+		}
+	}
+	require.True(t, applied, "migration 008 should be embedded")
+
+	_, err = db.Exec(`
+		CREATE TABLE volume_metrics (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			volume_id TEXT NOT NULL,
+			metric_timestamp DATETIME NOT NULL,
+			total_size INTEGER NOT NULL,
+			file_count INTEGER NOT NULL,
+			directory_count INTEGER NOT NULL
+		)
+	`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func insertRawMetric(t *testing.T, db *DB, volumeID string, ts time.Time, size int64) {
+	_, err := db.Exec(`INSERT INTO volume_metrics (volume_id, metric_timestamp, total_size, file_count, directory_count) VALUES (?, ?, ?, 0, 0)`,
+		volumeID, ts.Local(), size)
+	require.NoError(t, err)
+}
+
+// seedRawMetrics inserts an irregular series of samples; sizes grow, shrink and
+// repeat so every aggregate differs from the others
+func seedRawMetrics(t *testing.T, db *DB, start time.Time, days int) {
+	for _, volumeID := range []string{"app_data", "logs"} {
+		for day := 0; day < days; day++ {
+			for sample := 0; sample <= day%4; sample++ {
+				ts := start.Add(time.Duration(day)*24*time.Hour + time.Duration(sample*5+1)*time.Hour + 17*time.Minute)
+				size := int64(1<<40) + int64(day*1000) - int64(sample*sample*300) + int64(len(volumeID))
+				insertRawMetric(t, db, volumeID, ts, size)
+			}
+		}
+	}
+}
+
+// rawAggregate computes a bucket from volume_metrics directly with SQL
+func rawAggregate(t *testing.T, db *DB, volumeID string, from, to time.Time) VolumeMetricsRollup {
+	agg := VolumeMetricsRollup{VolumeID: volumeID, BucketStart: from}
+	err := db.QueryRow(`
+		SELECT MIN(total_size), MAX(total_size), AVG(total_size), COUNT(*)
+		FROM volume_metrics
+		WHERE volume_id = ? AND metric_timestamp >= ? AND metric_timestamp < ?
+	`, volumeID, from.Local(), to.Local()).Scan(&agg.MinSize, &agg.MaxSize, &agg.AvgSize, &agg.SampleCount)
+	require.NoError(t, err)
+
+	err = db.QueryRow(`
+		SELECT total_size FROM volume_metrics
+		WHERE volume_id = ? AND metric_timestamp >= ? AND metric_timestamp < ?
+		ORDER BY metric_timestamp ASC LIMIT 1
+	`, volumeID, from.Local(), to.Local()).Scan(&agg.FirstSize)
+	require.NoError(t, err)
+
+	err = db.QueryRow(`
+		SELECT total_size FROM volume_metrics
+		WHERE volume_id = ? AND metric_timestamp >= ? AND metric_timestamp < ?
+		ORDER BY metric_timestamp DESC LIMIT 1
+	`, volumeID, from.Local(), to.Local()).Scan(&agg.LastSize)
+	require.NoError(t, err)
+
+	return agg
+}
+
+// assertRollupsMatchRaw checks every stored bucket against the raw aggregate
+// and that the buckets account for every raw sample
+func assertRollupsMatchRaw(t *testing.T, db *DB, repo *VolumeMetricsRepository, granularity RollupGranularity, start, end time.Time) {
+	width := 24 * time.Hour
+	if granularity == RollupWeekly {
+		width = 7 * 24 * time.Hour
+	}
+
+	for _, volumeID := range []string{"app_data", "logs"} {
+		rollups, err := repo.GetRollups(context.Background(), volumeID, granularity, start, end, 1000)
+		require.NoError(t, err)
+		require.NotEmpty(t, rollups)
+
+		var total int
+		for _, r := range rollups {
+			raw := rawAggregate(t, db, volumeID, r.BucketStart, r.BucketStart.Add(width))
+			assert.Equal(t, granularity.BucketStart(r.BucketStart), r.BucketStart.UTC(), "bucket start must be aligned")
+			assert.Equal(t, raw.MinSize, r.MinSize, "min of %s %v", volumeID, r.BucketStart)
+			assert.Equal(t, raw.MaxSize, r.MaxSize, "max of %s %v", volumeID, r.BucketStart)
+			assert.InDelta(t, raw.AvgSize, r.AvgSize, 0.5, "avg of %s %v", volumeID, r.BucketStart)
+			assert.Equal(t, raw.FirstSize, r.FirstSize, "first of %s %v", volumeID, r.BucketStart)
+			assert.Equal(t, raw.LastSize, r.LastSize, "last of %s %v", volumeID, r.BucketStart)
+			assert.Equal(t, raw.SampleCount, r.SampleCount, "count of %s %v", volumeID, r.BucketStart)
+			total += r.SampleCount
+		}
+
+		var rawTotal int
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM volume_metrics WHERE volume_id = ?`, volumeID).Scan(&rawTotal))
+		assert.Equal(t, rawTotal, total, "rollups of %s must cover every sample", volumeID)
+	}
+}
+
+func TestRollupGranularity_BucketStart(t *testing.T) {
+	// 2026-10-14 is a Wednesday
+	ts := time.Date(2026, 10, 14, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600))
+
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), RollupDaily.BucketStart(ts))
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), RollupWeekly.BucketStart(ts))
+
+	sunday := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), RollupWeekly.BucketStart(sunday))
+}
+
+func TestRollupMetrics_MatchesRawAggregates(t *testing.T) {
+	db := setupRollupTestDB(t)
+	repo := NewVolumeMetricsRepository(db)
+	ctx := context.Background()
+
+	start := time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC)
+	seedRawMetrics(t, db, start, 45)
+	end := start.Add(60 * 24 * time.Hour)
+
+	for _, granularity := range RollupGranularities {
+		t.Run(string(granularity), func(t *testing.T) {
+			buckets, err := repo.RollupMetrics(ctx, granularity)
+			require.NoError(t, err)
+			assert.Greater(t, buckets, 0)
+			assertRollupsMatchRaw(t, db, repo, granularity, start, end)
+
+			// New samples in the newest bucket and the next one are folded in
+			last := start.Add(44*24*time.Hour + 22*time.Hour)
+			insertRawMetric(t, db, "app_data", last, 1)
+			insertRawMetric(t, db, "logs", last.Add(4*24*time.Hour), 1<<41)
+
+			_, err = repo.RollupMetrics(ctx, granularity)
+			require.NoError(t, err)
+			assertRollupsMatchRaw(t, db, repo, granularity, start, end)
+
+			// Refreshing without new samples changes nothing
+			_, err = repo.RollupMetrics(ctx, granularity)
+			require.NoError(t, err)
+			assertRollupsMatchRaw(t, db, repo, granularity, start, end)
+		})
+	}
+}
+
+func TestRollupMetrics_Empty(t *testing.T) {
+	repo := NewVolumeMetricsRepository(setupRollupTestDB(t))
+
+	buckets, err := repo.RollupMetrics(context.Background(), RollupDaily)
+	require.NoError(t, err)
+	assert.Equal(t, 0, buckets)
+
+	rollups, err := repo.GetRollups(context.Background(), "app_data", RollupDaily, time.Now().Add(-24*time.Hour), time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, rollups)
+
+	_, err = repo.RollupMetrics(context.Background(), RollupGranularity("hourly"))
+	assert.Error(t, err)
+}
+
+func TestRollupsWithin(t *testing.T) {
+	db := setupRollupTestDB(t)
+	repo := NewVolumeMetricsRepository(db)
+	ctx := context.Background()
+
+	start := time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC)
+	seedRawMetrics(t, db, start, 10)
+	_, err := repo.RollupMetrics(ctx, RollupDaily)
+	require.NoError(t, err)
+
+	// The range starts and ends mid-day, so the daily buckets at both ends
+	// hold samples outside it
+	from := start.Add(2*24*time.Hour + 3*time.Hour)
+	to := start.Add(7*24*time.Hour + 3*time.Hour)
+	rollups, err := repo.GetRollups(ctx, "app_data", RollupDaily, from, to, 1000)
+	require.NoError(t, err)
+	require.Len(t, rollups, 6)
+
+	within := RollupsWithin(rollups, from, to)
+	require.Len(t, within, 4)
+	assert.Equal(t, start.Add(6*24*time.Hour), within[0].BucketStart.UTC(), "newest first")
+	assert.Equal(t, start.Add(3*24*time.Hour), within[3].BucketStart.UTC())
+	for _, b := range within {
+		assert.False(t, b.FirstAt.Before(from), "bucket %v starts before the range", b.BucketStart)
+		assert.False(t, b.LastAt.After(to), "bucket %v ends after the range", b.BucketStart)
+	}
+
+	assert.Empty(t, RollupsWithin(nil, from, to))
+}
+
+// fakeRollupBackend records the granularities it was asked to refresh
+type fakeRollupBackend struct {
+	mu    sync.Mutex
+	calls []RollupGranularity
+}
+
+func (f *fakeRollupBackend) RollupMetrics(ctx context.Context, granularity RollupGranularity) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, granularity)
+	return 0, nil
+}
+
+func (f *fakeRollupBackend) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func TestRollupJob_Schedule(t *testing.T) {
+	backend := &fakeRollupBackend{}
+	job := NewRollupJob(backend, 10*time.Millisecond)
+	assert.True(t, job.Enabled())
+
+	// The first refresh runs immediately and covers every granularity
+	job.Start()
+	assert.Eventually(t, func() bool { return backend.callCount() >= 2*len(RollupGranularities) }, time.Second, 5*time.Millisecond)
+	job.Stop()
+
+	calls := backend.callCount()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, calls, backend.callCount())
+	assert.Equal(t, RollupGranularities, backend.calls[:len(RollupGranularities)])
+}
+
+func TestRollupJob_Disabled(t *testing.T) {
+	backend := &fakeRollupBackend{}
+	job := NewRollupJob(backend, 0)
+	assert.False(t, job.Enabled())
+
+	job.Start()
+	job.Stop()
+	assert.Equal(t, 0, backend.callCount())
+}
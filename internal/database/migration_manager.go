@@ -6,7 +6,11 @@ import (
 	"crypto/md5"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -19,6 +23,10 @@ import (
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
+// ErrMigrationNotFound is returned for a migration version that is neither
+// among the migration files nor in the migration history
+var ErrMigrationNotFound = errors.New("migration not found")
+
 // Migration represents a database migration
 type Migration struct {
 	Version     string
@@ -29,21 +37,102 @@ type Migration struct {
 
 // MigrationManager handles database migrations
 type MigrationManager struct {
-	db     *sql.DB
-	dbType DatabaseType
+	db            *sql.DB
+	dbType        DatabaseType
+	migrationsDir string // Optional directory of migrations added to the embedded ones
 }
 
 // NewMigrationManager creates a new migration manager
 func NewMigrationManager(db *DB) *MigrationManager {
-	return &MigrationManager{
+	mm := &MigrationManager{
 		db:     db.DB,
 		dbType: db.GetDatabaseType(),
 	}
+	if db.config != nil {
+		mm.migrationsDir = db.config.MigrationsDir
+	}
+	return mm
 }
 
-// LoadMigrationsFromFiles reads migration files from embedded filesystem
+// LoadMigrationsFromFiles reads migration files from embedded filesystem and,
+// when a migrations directory is configured, adds the migrations found there.
+// Migrations on disk extend the embedded ones: their versions must be higher
+// than every embedded version.
 func (mm *MigrationManager) LoadMigrationsFromFiles() ([]Migration, error) {
-	entries, err := migrationFiles.ReadDir("migrations")
+	embedded, err := mm.loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	if mm.migrationsDir == "" {
+		return embedded, nil
+	}
+
+	additions, err := mm.loadMigrations(os.DirFS(mm.migrationsDir), ".")
+	if err != nil {
+		return nil, utils.WrapErrorf(err, "failed to load migrations from %s", mm.migrationsDir)
+	}
+	return mergeMigrations(embedded, additions)
+}
+
+// mergeMigrations appends additional migrations to the embedded base. An
+// addition may neither reuse an embedded version nor sort before the latest
+// embedded migration, which would apply it out of order on fresh databases.
+func mergeMigrations(base, additions []Migration) ([]Migration, error) {
+	latest := 0
+	versions := make(map[string]Migration, len(base))
+	for _, m := range base {
+		versions[m.Version] = m
+		latest = max(latest, migrationVersionNumber(m.Version))
+	}
+
+	merged := append([]Migration(nil), base...)
+	for _, m := range additions {
+		if existing, ok := versions[m.Version]; ok {
+			return nil, fmt.Errorf("migration version %s (%s) conflicts with built-in migration %s (%s)",
+				m.Version, m.Description, existing.Version, existing.Description)
+		}
+		if migrationVersionNumber(m.Version) <= latest {
+			return nil, fmt.Errorf("migration version %s (%s) must be higher than the latest built-in version %03d",
+				m.Version, m.Description, latest)
+		}
+		merged = append(merged, m)
+	}
+
+	sortMigrations(merged)
+	return merged, nil
+}
+
+// migrationVersionNumber returns the numeric value of a version, zero when it
+// is not a number
+func migrationVersionNumber(version string) int {
+	n, err := strconv.Atoi(version)
+	if err != nil {
+		return 0 // Default to 0 if version parsing fails
+	}
+	return n
+}
+
+// isMigrationVersion reports whether a filename prefix is a version number
+func isMigrationVersion(prefix string) bool {
+	for _, char := range prefix {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// sortMigrations sorts migrations by version number
+func sortMigrations(migrations []Migration) {
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrationVersionNumber(migrations[i].Version) < migrationVersionNumber(migrations[j].Version)
+	})
+}
+
+// loadMigrations reads the migration files in dir of fsys. Two migrations
+// sharing a version are rejected.
+func (mm *MigrationManager) loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return nil, utils.WrapError(err, "failed to read migrations directory")
 	}
@@ -67,7 +156,7 @@ func (mm *MigrationManager) LoadMigrationsFromFiles() ([]Migration, error) {
 		var dbSuffix string
 
 		// Extract version number (first 3 digits)
-		if len(filename) >= 4 && filename[3] == '_' {
+		if len(filename) >= 4 && filename[3] == '_' && isMigrationVersion(filename[:3]) {
 			version = filename[:3]
 			remaining := filename[4:]
 
@@ -87,7 +176,7 @@ func (mm *MigrationManager) LoadMigrationsFromFiles() ([]Migration, error) {
 			} else if mm.dbType == DatabaseTypeSQLite {
 				// For SQLite, prefer database-specific files if they exist
 				sqliteVariant := strings.Replace(filename, ".sql", "_sqlite.sql", 1)
-				if _, err := migrationFiles.Open("migrations/" + sqliteVariant); err == nil {
+				if _, err := fs.Stat(fsys, path.Join(dir, sqliteVariant)); err == nil {
 					continue // Skip generic file in favor of SQLite-specific one
 				}
 			}
@@ -122,7 +211,7 @@ func (mm *MigrationManager) LoadMigrationsFromFiles() ([]Migration, error) {
 		}
 
 		// Read file content
-		content, err := migrationFiles.ReadFile("migrations/" + filename)
+		content, err := fs.ReadFile(fsys, path.Join(dir, filename))
 		if err != nil {
 			return nil, utils.WrapErrorf(err, "failed to read migration file %s", filename)
 		}
@@ -133,6 +222,9 @@ func (mm *MigrationManager) LoadMigrationsFromFiles() ([]Migration, error) {
 				Version:     version,
 				Description: description,
 			}
+		} else if migrationMap[version].Description != description {
+			return nil, fmt.Errorf("migration version %s is used by both %q and %q",
+				version, migrationMap[version].Description, description)
 		}
 
 		// Set appropriate SQL
@@ -153,18 +245,7 @@ func (mm *MigrationManager) LoadMigrationsFromFiles() ([]Migration, error) {
 		migrations = append(migrations, *migration)
 	}
 
-	// Sort by version number
-	sort.Slice(migrations, func(i, j int) bool {
-		vi, err := strconv.Atoi(migrations[i].Version)
-		if err != nil {
-			vi = 0 // Default to 0 if version parsing fails
-		}
-		vj, err := strconv.Atoi(migrations[j].Version)
-		if err != nil {
-			vj = 0 // Default to 0 if version parsing fails
-		}
-		return vi < vj
-	})
+	sortMigrations(migrations)
 
 	return migrations, nil
 }
@@ -320,7 +401,7 @@ func (mm *MigrationManager) ApplyMigration(migration Migration) error {
 	}
 
 	// Calculate checksum
-	checksum := fmt.Sprintf("%x", md5.Sum([]byte(migration.UpSQL)))
+	checksum := migrationChecksum(migration)
 
 	// Record migration in history
 	_, err = tx.Exec(`
@@ -453,6 +534,74 @@ func (mm *MigrationManager) GetMigrationStatus() (*MigrationStatus, error) {
 	return status, nil
 }
 
+// GetMigration returns the SQL of a migration version as the migration files
+// give it for this database type and, when the version has been applied, as
+// recorded in the migration history
+func (mm *MigrationManager) GetMigration(version string) (*MigrationDetail, error) {
+	migrations, err := mm.LoadMigrationsFromFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	detail := &MigrationDetail{Version: version}
+	for _, m := range migrations {
+		if m.Version == version {
+			detail.Description = m.Description
+			detail.UpSQL = &m.UpSQL
+			detail.DownSQL = &m.DownSQL
+			detail.Checksum = migrationChecksum(m)
+			break
+		}
+	}
+
+	var applied MigrationHistory
+	err = mm.db.QueryRow(`
+		SELECT id, version, description, applied_at, rollback_sql, checksum, execution_time
+		FROM migration_history
+		WHERE version = $1
+	`, version).Scan(&applied.ID, &applied.Version, &applied.Description, &applied.AppliedAt,
+		&applied.RollbackSQL, &applied.Checksum, &applied.ExecutionTime)
+	switch {
+	case err == sql.ErrNoRows:
+		if detail.UpSQL == nil {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationNotFound, version)
+		}
+	case err != nil:
+		return nil, utils.WrapErrorf(err, "failed to get migration %s", version)
+	default:
+		if detail.UpSQL == nil {
+			// Applied from a migration file that is no longer there
+			detail.Description = applied.Description
+		}
+		detail.Applied = &applied
+		detail.ChecksumMismatch = detail.UpSQL != nil && detail.Checksum != applied.Checksum
+	}
+
+	return detail, nil
+}
+
+// migrationChecksum returns the checksum recorded for a migration when it is
+// applied
+func migrationChecksum(migration Migration) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(migration.UpSQL)))
+}
+
+// MigrationDetail is the SQL of a single migration version
+type MigrationDetail struct {
+	Version     string  `json:"version"`
+	Description string  `json:"description"`
+	UpSQL       *string `json:"up_sql,omitempty"`   // From the migration files; nil when no file has the version
+	DownSQL     *string `json:"down_sql,omitempty"` // From the migration files; empty when the migration has no down file
+	Checksum    string  `json:"checksum,omitempty"` // Checksum of UpSQL
+
+	// Applied is the migration history entry, whose rollback SQL and checksum
+	// are what was recorded when the version was applied
+	Applied *MigrationHistory `json:"applied,omitempty"`
+	// ChecksumMismatch is set when the migration file has changed since the
+	// version was applied
+	ChecksumMismatch bool `json:"checksum_mismatch"`
+}
+
 // MigrationStatus represents the current state of database migrations
 type MigrationStatus struct {
 	TotalMigrations   int                `json:"total_migrations"`
@@ -1,10 +1,13 @@
 package database
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMigrationHistory_Structure(t *testing.T) {
@@ -333,3 +336,146 @@ func BenchmarkMigrationStatus_IsUpToDate(b *testing.B) {
 		_ = status.IsUpToDate()
 	}
 }
+
+// setupMigrationsDirDB creates a SQLite database whose migration manager also
+// reads the given migration files from a directory
+func setupMigrationsDirDB(t *testing.T, files map[string]string) *DB {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	db, err := NewDB(&Config{
+		Type:          DatabaseTypeSQLite,
+		Path:          filepath.Join(t.TempDir(), "migrations.db"),
+		MaxOpenConns:  1,
+		MaxIdleConns:  1,
+		MigrationsDir: dir,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestLoadMigrationsFromFiles_MergesMigrationsDir(t *testing.T) {
+	db := setupMigrationsDirDB(t, map[string]string{
+		"101_org_reports.sql":             "CREATE TABLE org_reports (id INTEGER PRIMARY KEY);",
+		"101_org_reports_down.sql":        "DROP TABLE org_reports;",
+		"100_org_indexes_postgres.sql":    "CREATE TABLE org_settings (key TEXT PRIMARY KEY) WITH (fillfactor = 90);",
+		"100_org_indexes.sql":             "CREATE TABLE org_settings (key TEXT PRIMARY KEY);",
+		"README.md":                       "not a migration",
+		"not_a_migration.sql":             "SELECT 1;",
+		"102_org_cleanup_sqlite.sql":      "DELETE FROM org_reports;",
+		"102_org_cleanup_postgres.sql":    "TRUNCATE org_reports;",
+		"102_org_cleanup_sqlite_down.sql": "SELECT 1;",
+	})
+	mm := NewMigrationManager(db)
+
+	migrations, err := mm.LoadMigrationsFromFiles()
+	require.NoError(t, err)
+
+	embedded, err := (&MigrationManager{dbType: DatabaseTypeSQLite}).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	require.Len(t, migrations, len(embedded)+3)
+	assert.Equal(t, embedded, migrations[:len(embedded)], "built-in migrations come first, unchanged")
+
+	additions := migrations[len(embedded):]
+	assert.Equal(t, []string{"100", "101", "102"}, []string{additions[0].Version, additions[1].Version, additions[2].Version})
+	assert.Equal(t, "CREATE TABLE org_settings (key TEXT PRIMARY KEY);", additions[0].UpSQL)
+	assert.Equal(t, "Org Reports", additions[1].Description)
+	assert.Equal(t, "DROP TABLE org_reports;", additions[1].DownSQL)
+	assert.Equal(t, "DELETE FROM org_reports;", additions[2].UpSQL, "the SQLite variant is preferred")
+
+	// The on-disk migrations apply like the built-in ones
+	require.NoError(t, mm.EnsureMigrationTable())
+	for _, m := range additions {
+		require.NoError(t, mm.ApplyMigration(m))
+	}
+	_, err = db.Exec("INSERT INTO org_reports (id) VALUES (1)")
+	require.NoError(t, err)
+}
+
+func TestLoadMigrationsFromFiles_MigrationsDirConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "built-in version",
+			files:    map[string]string{"005_my_scan_tables.sql": "SELECT 1;"},
+			expected: "conflicts with built-in migration 005",
+		},
+		{
+			name:     "below the latest built-in version",
+			files:    map[string]string{"000_early.sql": "SELECT 1;"},
+			expected: "must be higher than the latest built-in version",
+		},
+		{
+			name: "duplicate on-disk version",
+			files: map[string]string{
+				"100_first.sql":  "SELECT 1;",
+				"100_second.sql": "SELECT 2;",
+			},
+			expected: "migration version 100 is used by both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupMigrationsDirDB(t, tt.files)
+			mm := NewMigrationManager(db)
+
+			_, err := mm.LoadMigrationsFromFiles()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+
+			// Nothing is applied from a conflicting set of migrations
+			assert.Error(t, mm.ApplyAllPending())
+		})
+	}
+}
+
+func TestLoadMigrationsFromFiles_MissingMigrationsDir(t *testing.T) {
+	mm := &MigrationManager{dbType: DatabaseTypeSQLite, migrationsDir: filepath.Join(t.TempDir(), "missing")}
+	_, err := mm.LoadMigrationsFromFiles()
+	assert.Error(t, err)
+}
+
+func TestGetMigration_ChecksumMismatch(t *testing.T) {
+	db := setupMigrationsDirDB(t, map[string]string{
+		"101_org_reports.sql":      "CREATE TABLE org_reports (id INTEGER PRIMARY KEY);",
+		"101_org_reports_down.sql": "DROP TABLE org_reports;",
+	})
+	mm := NewMigrationManager(db)
+	require.NoError(t, mm.EnsureMigrationTable())
+	migrations, err := mm.LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	require.NoError(t, mm.ApplyMigration(migrations[len(migrations)-1]))
+
+	detail, err := mm.GetMigration("101")
+	require.NoError(t, err)
+	assert.False(t, detail.ChecksumMismatch)
+
+	// The file is edited after the migration was applied
+	require.NoError(t, os.WriteFile(filepath.Join(db.config.MigrationsDir, "101_org_reports.sql"),
+		[]byte("CREATE TABLE org_reports (id INTEGER PRIMARY KEY, name TEXT);"), 0o644))
+	detail, err = mm.GetMigration("101")
+	require.NoError(t, err)
+	assert.True(t, detail.ChecksumMismatch)
+	assert.Equal(t, "CREATE TABLE org_reports (id INTEGER PRIMARY KEY, name TEXT);", *detail.UpSQL)
+	require.NotNil(t, detail.Applied.RollbackSQL)
+	assert.Equal(t, "DROP TABLE org_reports;", *detail.Applied.RollbackSQL)
+
+	// A version applied from a file that has since been removed is still known
+	require.NoError(t, os.Remove(filepath.Join(db.config.MigrationsDir, "101_org_reports.sql")))
+	require.NoError(t, os.Remove(filepath.Join(db.config.MigrationsDir, "101_org_reports_down.sql")))
+	detail, err = mm.GetMigration("101")
+	require.NoError(t, err)
+	assert.Nil(t, detail.UpSQL)
+	assert.Equal(t, "Org Reports", detail.Description)
+	assert.NotNil(t, detail.Applied)
+
+	_, err = mm.GetMigration("102")
+	assert.ErrorIs(t, err, ErrMigrationNotFound)
+}
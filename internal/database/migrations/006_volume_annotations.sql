@@ -0,0 +1,17 @@
+-- Migration: 006_volume_annotations
+-- Description: Add volume_annotations table for user-managed per-volume metadata (aliases, policies)
+-- Up Migration
+
+-- Annotations are keyed by Docker volume name rather than volumes.id so they
+-- survive a volume being removed and recreated under the same name
+CREATE TABLE IF NOT EXISTS volume_annotations (
+    volume_name VARCHAR(255) NOT NULL,
+    annotation_key VARCHAR(255) NOT NULL,
+    annotation_value TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (volume_name, annotation_key)
+);
+
+-- Lookup of all volumes sharing an annotation value (e.g. an alias group)
+CREATE INDEX IF NOT EXISTS idx_volume_annotations_key_value ON volume_annotations(annotation_key, annotation_value);
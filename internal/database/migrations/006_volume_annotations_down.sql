@@ -0,0 +1,6 @@
+-- Migration: 006_volume_annotations
+-- Description: Remove volume_annotations table
+-- Down Migration
+
+DROP INDEX IF EXISTS idx_volume_annotations_key_value;
+DROP TABLE IF EXISTS volume_annotations;
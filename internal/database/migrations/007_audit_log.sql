@@ -0,0 +1,21 @@
+-- Migration: 007_audit_log
+-- Description: Add audit_log table recording who performed which API operation
+-- Up Migration
+
+-- Request bodies are never stored; summary holds a sanitized description only
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id VARCHAR(255) NOT NULL DEFAULT '',
+    user_role VARCHAR(50) NOT NULL DEFAULT '',
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    route TEXT NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log(occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_id ON audit_log(user_id, occurred_at DESC);
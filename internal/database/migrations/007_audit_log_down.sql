@@ -0,0 +1,7 @@
+-- Migration: 007_audit_log
+-- Description: Remove audit_log table
+-- Down Migration
+
+DROP INDEX IF EXISTS idx_audit_log_user_id;
+DROP INDEX IF EXISTS idx_audit_log_occurred_at;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,39 @@
+-- Migration: 008_volume_metrics_rollups
+-- Description: Add daily and weekly rollups of volume_metrics for coarse history and growth queries
+-- Up Migration
+
+-- Rollups are maintained by a background job from the raw volume_metrics rows.
+-- bucket_start is the UTC start of the day (or ISO week, starting Monday).
+CREATE TABLE IF NOT EXISTS volume_metrics_daily (
+    volume_id VARCHAR(255) NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    min_size BIGINT NOT NULL,
+    max_size BIGINT NOT NULL,
+    avg_size DOUBLE PRECISION NOT NULL,
+    first_size BIGINT NOT NULL,
+    first_at TIMESTAMP NOT NULL,
+    last_size BIGINT NOT NULL,
+    last_at TIMESTAMP NOT NULL,
+    sample_count INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (volume_id, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS volume_metrics_weekly (
+    volume_id VARCHAR(255) NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    min_size BIGINT NOT NULL,
+    max_size BIGINT NOT NULL,
+    avg_size DOUBLE PRECISION NOT NULL,
+    first_size BIGINT NOT NULL,
+    first_at TIMESTAMP NOT NULL,
+    last_size BIGINT NOT NULL,
+    last_at TIMESTAMP NOT NULL,
+    sample_count INTEGER NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (volume_id, bucket_start)
+);
+
+-- Incremental refreshes resume from the newest bucket
+CREATE INDEX IF NOT EXISTS idx_volume_metrics_daily_bucket ON volume_metrics_daily(bucket_start);
+CREATE INDEX IF NOT EXISTS idx_volume_metrics_weekly_bucket ON volume_metrics_weekly(bucket_start);
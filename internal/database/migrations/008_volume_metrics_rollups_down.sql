@@ -0,0 +1,8 @@
+-- Migration: 008_volume_metrics_rollups
+-- Description: Remove volume_metrics rollup tables
+-- Down Migration
+
+DROP INDEX IF EXISTS idx_volume_metrics_weekly_bucket;
+DROP INDEX IF EXISTS idx_volume_metrics_daily_bucket;
+DROP TABLE IF EXISTS volume_metrics_weekly;
+DROP TABLE IF EXISTS volume_metrics_daily;
@@ -0,0 +1,9 @@
+-- Migration: 009_scan_trigger_source
+-- Description: Record what triggered each scan run
+-- Up Migration
+
+-- Runs recorded before the column existed have no known trigger
+ALTER TABLE scan_runs ADD COLUMN IF NOT EXISTS trigger_source VARCHAR(20) NOT NULL DEFAULT 'unknown';
+
+-- Recent scans filtered by trigger source
+CREATE INDEX IF NOT EXISTS idx_scan_runs_trigger_source_started_at ON scan_runs(trigger_source, started_at);
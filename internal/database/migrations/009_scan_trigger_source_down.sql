@@ -0,0 +1,6 @@
+-- Migration: 009_scan_trigger_source
+-- Description: Remove the scan run trigger source
+-- Down Migration
+
+DROP INDEX IF EXISTS idx_scan_runs_trigger_source_started_at;
+ALTER TABLE scan_runs DROP COLUMN IF EXISTS trigger_source;
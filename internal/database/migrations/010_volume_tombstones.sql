@@ -0,0 +1,11 @@
+-- Migration: 010_volume_tombstones
+-- Description: Record when volumes were removed, for change listings
+-- Up Migration
+
+-- Removed volumes are deleted from volumes; a tombstone keeps the removal time
+CREATE TABLE IF NOT EXISTS volume_tombstones (
+    volume_id VARCHAR(255) PRIMARY KEY,
+    removed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_volume_tombstones_removed_at ON volume_tombstones(removed_at);
@@ -0,0 +1,6 @@
+-- Migration: 010_volume_tombstones
+-- Description: Remove volume tombstones
+-- Down Migration
+
+DROP INDEX IF EXISTS idx_volume_tombstones_removed_at;
+DROP TABLE IF EXISTS volume_tombstones;
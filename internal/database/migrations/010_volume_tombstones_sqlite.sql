@@ -0,0 +1,11 @@
+-- Migration: 010_volume_tombstones
+-- Description: Record when volumes were removed, for change listings (SQLite)
+-- Up Migration
+
+-- Removed volumes are deleted from volumes; a tombstone keeps the removal time
+CREATE TABLE IF NOT EXISTS volume_tombstones (
+    volume_id TEXT PRIMARY KEY,
+    removed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_volume_tombstones_removed_at ON volume_tombstones(removed_at);
@@ -0,0 +1,6 @@
+-- Migration: 011_scan_accuracy
+-- Description: Record how accurate each volume scan was
+-- Up Migration
+
+-- Stats recorded before the column existed have no known accuracy
+ALTER TABLE volume_stats ADD COLUMN IF NOT EXISTS scan_accuracy VARCHAR(20) NOT NULL DEFAULT '';
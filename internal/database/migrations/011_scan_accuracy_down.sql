@@ -0,0 +1,5 @@
+-- Migration: 011_scan_accuracy
+-- Description: Remove the volume scan accuracy
+-- Down Migration
+
+ALTER TABLE volume_stats DROP COLUMN IF EXISTS scan_accuracy;
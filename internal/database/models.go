@@ -4,6 +4,7 @@ package database
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -87,6 +88,7 @@ type ScanJob struct {
 	ErrorMessage      *string        `db:"error_message" json:"error_message,omitempty"`
 	ResultID          *int           `db:"result_id" json:"result_id,omitempty"` // FK to VolumeSize
 	EstimatedDuration *time.Duration `db:"estimated_duration" json:"estimated_duration,omitempty"`
+	TriggerSource     string         `db:"trigger_source" json:"trigger_source"` // scheduled, startup, manual or event
 }
 
 // VolumeScanStats represents historical volume scan statistics (maps to volume_stats table)
@@ -96,6 +98,7 @@ type VolumeScanStats struct {
 	SizeBytes    int64         `db:"size_bytes" json:"size_bytes"`
 	FileCount    *int          `db:"file_count" json:"file_count"`       // nullable
 	ScanMethod   string        `db:"scan_method" json:"scan_method"`
+	ScanAccuracy string        `db:"scan_accuracy" json:"scan_accuracy,omitempty"` // exact, approximate or estimated; empty before it was recorded
 	DurationMs   int64         `db:"duration_ms" json:"duration_ms"`
 	Timestamp    time.Time     `db:"ts" json:"ts"`                       // using ts as column name per spec
 }
@@ -138,6 +141,32 @@ type ScanCache struct {
 	IsValid        bool      `db:"is_valid" json:"is_valid"`
 }
 
+// VolumeAnnotation is a user-managed key/value attached to a volume name
+// Keyed by name (not volumes.id) so annotations survive volume recreation
+type VolumeAnnotation struct {
+	VolumeName string    `db:"volume_name" json:"volume_name"`
+	Key        string    `db:"annotation_key" json:"key"`
+	Value      string    `db:"annotation_value" json:"value"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// AuditLogEntry records one API operation and who performed it
+// Summary is sanitized by the audit middleware; request bodies are never stored
+type AuditLogEntry struct {
+	ID         int       `db:"id" json:"id"`
+	OccurredAt time.Time `db:"occurred_at" json:"occurred_at"`
+	UserID     string    `db:"user_id" json:"user_id"`
+	UserRole   string    `db:"user_role" json:"user_role"`
+	Method     string    `db:"method" json:"method"`
+	Path       string    `db:"path" json:"path"`
+	Route      string    `db:"route" json:"route"`
+	StatusCode int       `db:"status_code" json:"status_code"`
+	Summary    string    `db:"summary" json:"summary"`
+	RequestID  string    `db:"request_id" json:"request_id"`
+	ClientIP   string    `db:"client_ip" json:"client_ip"`
+}
+
 // MigrationHistory tracks database schema changes
 // Essential for version control and rollback capabilities
 type MigrationHistory struct {
@@ -161,8 +190,11 @@ func (l Labels) Value() (driver.Value, error) {
 		return nil, nil
 	}
 
-	// Use encoding/json for proper JSON serialization
-	return fmt.Sprintf("%v", map[string]string(l)), nil
+	data, err := json.Marshal(map[string]string(l))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	return string(data), nil
 }
 
 // Scan implements the sql.Scanner interface for database retrieval
@@ -182,19 +214,16 @@ func (l *Labels) Scan(value any) error {
 	}
 }
 
-// unmarshalJSON is a simplified JSON unmarshaler for Labels
-// In production, use encoding/json for robust parsing
+// unmarshalJSON decodes stored labels
+// Rows written before labels were stored as JSON are not decodable and scan as empty
 func (l *Labels) unmarshalJSON(data []byte) error {
-	if *l == nil {
-		*l = make(Labels)
+	labels := make(Labels)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, (*map[string]string)(&labels)); err != nil {
+			labels = make(Labels)
+		}
 	}
-
-	str := string(data)
-	if str == "{}" || str == "" || str == "map[]" {
-		return nil
-	}
-
-	// Simple key-value parsing - replace with encoding/json in production
+	*l = labels
 	return nil
 }
 
@@ -208,6 +237,8 @@ var TableNames = struct {
 	VolumeMetrics    string
 	SystemHealth     string
 	ScanCache        string
+	Annotations      string
+	AuditLog         string
 	MigrationHistory string
 }{
 	Volumes:          "volumes",
@@ -218,5 +249,7 @@ var TableNames = struct {
 	VolumeMetrics:    "volume_metrics",
 	SystemHealth:     "system_health",
 	ScanCache:        "scan_cache",
+	Annotations:      "volume_annotations",
+	AuditLog:         "audit_log",
 	MigrationHistory: "migration_history",
 }
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrOptimizationInProgress is returned when an optimization is requested while one is already running
+var ErrOptimizationInProgress = errors.New("database optimization already in progress")
+
+// OptimizationBackend is the database capability used by the Optimizer
+type OptimizationBackend interface {
+	RunOptimization() (*OptimizationResult, error)
+	IsSQLite() bool
+}
+
+// OptimizerStatus reports the state of the optimizer
+type OptimizerStatus struct {
+	Running    bool                `json:"running"`
+	Interval   time.Duration       `json:"interval,omitempty"`
+	LastResult *OptimizationResult `json:"last_result,omitempty"`
+	LastError  string              `json:"last_error,omitempty"`
+}
+
+// Optimizer serializes database optimization runs triggered on demand or on a schedule
+type Optimizer struct {
+	backend  OptimizationBackend
+	interval time.Duration
+
+	mu         sync.Mutex
+	running    bool
+	lastResult *OptimizationResult
+	lastErr    error
+
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewOptimizer creates a new optimizer; an interval of zero disables scheduled runs
+func NewOptimizer(backend OptimizationBackend, interval time.Duration) *Optimizer {
+	return &Optimizer{
+		backend:  backend,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// IsSQLite reports whether the optimized database is SQLite
+func (o *Optimizer) IsSQLite() bool {
+	return o.backend.IsSQLite()
+}
+
+// Run optimizes the database and waits for completion
+func (o *Optimizer) Run() (*OptimizationResult, error) {
+	if !o.acquire() {
+		return nil, ErrOptimizationInProgress
+	}
+	return o.run()
+}
+
+// RunAsync starts an optimization in the background and returns immediately.
+// Use Status to observe the outcome.
+func (o *Optimizer) RunAsync() error {
+	if !o.acquire() {
+		return ErrOptimizationInProgress
+	}
+	go o.run()
+	return nil
+}
+
+// Status returns the current state and the outcome of the last run
+func (o *Optimizer) Status() OptimizerStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	status := OptimizerStatus{
+		Running:    o.running,
+		Interval:   o.interval,
+		LastResult: o.lastResult,
+	}
+	if o.lastErr != nil {
+		status.LastError = o.lastErr.Error()
+	}
+	return status
+}
+
+// Start begins scheduled optimization if an interval is configured
+func (o *Optimizer) Start() {
+	if o.interval <= 0 {
+		close(o.doneCh)
+		return
+	}
+
+	go func() {
+		defer close(o.doneCh)
+		ticker := time.NewTicker(o.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// Failures are logged by run
+				if _, err := o.Run(); errors.Is(err, ErrOptimizationInProgress) {
+					log.Printf("[INFO] Skipping scheduled database optimization: already running")
+				}
+			case <-o.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends scheduled optimization and waits for the scheduler goroutine to exit
+func (o *Optimizer) Stop() {
+	o.stopOnce.Do(func() { close(o.stopCh) })
+	<-o.doneCh
+}
+
+// acquire marks the optimizer as running, returning false if it already was
+func (o *Optimizer) acquire() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.running {
+		return false
+	}
+	o.running = true
+	return true
+}
+
+// run performs the optimization and records the outcome; the caller must hold the run slot
+func (o *Optimizer) run() (*OptimizationResult, error) {
+	result, err := o.backend.RunOptimization()
+	if err != nil {
+		log.Printf("[ERROR] Database optimization failed: %v", err)
+	} else if result != nil {
+		log.Printf("[INFO] Database optimization completed: backend=%s operations=%d duration=%v",
+			result.Backend, len(result.Operations), result.Duration)
+	}
+
+	o.mu.Lock()
+	o.running = false
+	o.lastResult = result
+	o.lastErr = err
+	o.mu.Unlock()
+
+	return result, err
+}
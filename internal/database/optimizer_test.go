@@ -0,0 +1,115 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOptimizationBackend records calls and can block until released
+type fakeOptimizationBackend struct {
+	mu      sync.Mutex
+	calls   int
+	sqlite  bool
+	err     error
+	release chan struct{}
+}
+
+func (f *fakeOptimizationBackend) RunOptimization() (*OptimizationResult, error) {
+	if f.release != nil {
+		<-f.release
+	}
+	f.mu.Lock()
+	f.calls++
+	f.mu.Unlock()
+	return &OptimizationResult{Backend: DatabaseTypePostgreSQL, Operations: []string{"ANALYZE"}}, f.err
+}
+
+func (f *fakeOptimizationBackend) IsSQLite() bool { return f.sqlite }
+
+func (f *fakeOptimizationBackend) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestDB_RunOptimization_SQLite(t *testing.T) {
+	db, err := NewDB(&Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "optimize.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+
+	result, err := db.RunOptimization()
+	require.NoError(t, err)
+
+	assert.Equal(t, DatabaseTypeSQLite, result.Backend)
+	assert.Equal(t, []string{"ANALYZE", "PRAGMA incremental_vacuum", "PRAGMA optimize"}, result.Operations)
+	assert.False(t, result.StartedAt.IsZero())
+}
+
+func TestOptimizer_RunRecordsStatus(t *testing.T) {
+	backend := &fakeOptimizationBackend{}
+	optimizer := NewOptimizer(backend, 0)
+
+	result, err := optimizer.Run()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ANALYZE"}, result.Operations)
+
+	status := optimizer.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, result, status.LastResult)
+	assert.Empty(t, status.LastError)
+
+	backend.err = errors.New("analyze failed")
+	_, err = optimizer.Run()
+	assert.Error(t, err)
+	assert.Equal(t, "analyze failed", optimizer.Status().LastError)
+}
+
+func TestOptimizer_RejectsOverlappingRuns(t *testing.T) {
+	backend := &fakeOptimizationBackend{release: make(chan struct{})}
+	optimizer := NewOptimizer(backend, 0)
+
+	require.NoError(t, optimizer.RunAsync())
+	assert.True(t, optimizer.Status().Running)
+
+	_, err := optimizer.Run()
+	assert.ErrorIs(t, err, ErrOptimizationInProgress)
+	assert.ErrorIs(t, optimizer.RunAsync(), ErrOptimizationInProgress)
+
+	close(backend.release)
+	assert.Eventually(t, func() bool { return !optimizer.Status().Running }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, backend.callCount())
+}
+
+func TestOptimizer_Schedule(t *testing.T) {
+	backend := &fakeOptimizationBackend{}
+	optimizer := NewOptimizer(backend, 10*time.Millisecond)
+
+	optimizer.Start()
+	assert.Eventually(t, func() bool { return backend.callCount() >= 2 }, time.Second, 5*time.Millisecond)
+	optimizer.Stop()
+
+	// No further runs once stopped
+	calls := backend.callCount()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, calls, backend.callCount())
+}
+
+func TestOptimizer_ScheduleDisabled(t *testing.T) {
+	backend := &fakeOptimizationBackend{}
+	optimizer := NewOptimizer(backend, 0)
+
+	optimizer.Start()
+	optimizer.Stop()
+	assert.Equal(t, 0, backend.callCount())
+}
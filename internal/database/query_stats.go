@@ -0,0 +1,180 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// QueryStatsExtension is the PostgreSQL extension that records per-statement
+// execution statistics for slow query reporting
+const QueryStatsExtension = "pg_stat_statements"
+
+// ErrQueryStatsPermission is returned when the database user may not create
+// the query statistics extension
+var ErrQueryStatsPermission = errors.New("insufficient privileges to create " + QueryStatsExtension)
+
+// Query statistics states, from least to most usable
+const (
+	QueryStatsUnsupported  = "unsupported"   // The database is not PostgreSQL
+	QueryStatsUnavailable  = "unavailable"   // The extension is not shipped with the server
+	QueryStatsNotInstalled = "not_installed" // Shipped, but CREATE EXTENSION has not been run
+	QueryStatsNotLoaded    = "not_loaded"    // Installed, but missing from shared_preload_libraries
+	QueryStatsEnabled      = "enabled"
+)
+
+// QueryStatsStatus reports whether slow query statistics can be read
+type QueryStatsStatus struct {
+	Extension string `json:"extension"`
+	State     string `json:"state"`
+	Version   string `json:"version,omitempty"` // Installed extension version
+}
+
+// Enabled reports whether statistics are being collected and can be read
+func (s *QueryStatsStatus) Enabled() bool {
+	return s.State == QueryStatsEnabled
+}
+
+// SlowQuery is the execution statistics of one normalized statement
+type SlowQuery struct {
+	Query       string
+	Calls       int64
+	TotalTimeMs float64
+	MeanTimeMs  float64
+	MinTimeMs   float64
+	MaxTimeMs   float64
+	StddevMs    float64
+	Rows        int64
+	HitPercent  *float64
+}
+
+// QueryStatsBackend is the database capability behind slow query reporting
+type QueryStatsBackend interface {
+	QueryStatsStatus() (*QueryStatsStatus, error)
+	EnableQueryStats() error
+	SlowQueries(limit int) ([]SlowQuery, error)
+}
+
+// QueryStatsStatus detects whether pg_stat_statements is shipped with the
+// server, installed in the database and loaded so it collects statistics
+func (db *DB) QueryStatsStatus() (*QueryStatsStatus, error) {
+	status := &QueryStatsStatus{Extension: QueryStatsExtension, State: QueryStatsUnsupported}
+	if !db.IsPostgreSQL() {
+		return status, nil
+	}
+
+	var shipped bool
+	err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = $1)`, QueryStatsExtension).Scan(&shipped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check available extensions: %w", err)
+	}
+	if !shipped {
+		status.State = QueryStatsUnavailable
+		return status, nil
+	}
+
+	err = db.QueryRow(`SELECT extversion FROM pg_extension WHERE extname = $1`, QueryStatsExtension).Scan(&status.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		status.State = QueryStatsNotInstalled
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check installed extensions: %w", err)
+	}
+
+	// The extension only collects statistics when the server preloads it
+	var preload string
+	if err := db.QueryRow(`SELECT current_setting('shared_preload_libraries')`).Scan(&preload); err != nil {
+		return nil, fmt.Errorf("failed to read shared_preload_libraries: %w", err)
+	}
+	status.State = QueryStatsNotLoaded
+	for _, library := range strings.Split(preload, ",") {
+		if strings.Trim(strings.TrimSpace(library), `"`) == QueryStatsExtension {
+			status.State = QueryStatsEnabled
+			break
+		}
+	}
+	return status, nil
+}
+
+// EnableQueryStats creates the pg_stat_statements extension in the database.
+// It returns ErrQueryStatsPermission when the user lacks the privilege; the
+// server must still preload the library for statistics to be collected.
+func (db *DB) EnableQueryStats() error {
+	if !db.IsPostgreSQL() {
+		return fmt.Errorf("%s requires PostgreSQL", QueryStatsExtension)
+	}
+
+	_, err := db.Exec(`CREATE EXTENSION IF NOT EXISTS ` + QueryStatsExtension)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42501" { // insufficient_privilege
+		return fmt.Errorf("%w: %s", ErrQueryStatsPermission, pqErr.Message)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create extension %s: %w", QueryStatsExtension, err)
+	}
+	return nil
+}
+
+// SlowQueries returns the limit statements with the most total execution time
+func (db *DB) SlowQueries(limit int) ([]SlowQuery, error) {
+	status, err := db.QueryStatsStatus()
+	if err != nil {
+		return nil, err
+	}
+	if !status.Enabled() {
+		return nil, fmt.Errorf("%s is %s", QueryStatsExtension, strings.ReplaceAll(status.State, "_", " "))
+	}
+
+	// Version 1.8 (PostgreSQL 13) split the timings into planning and execution
+	timing := "total_exec_time, mean_exec_time, min_exec_time, max_exec_time, stddev_exec_time"
+	order := "total_exec_time"
+	if versionBefore(status.Version, 1, 8) {
+		timing = "total_time, mean_time, min_time, max_time, stddev_time"
+		order = "total_time"
+	}
+
+	rows, err := db.Query(`
+		SELECT
+			query,
+			calls,
+			`+timing+`,
+			rows,
+			100.0 * shared_blks_hit / nullif(shared_blks_hit + shared_blks_read, 0) AS hit_percent
+		FROM `+QueryStatsExtension+`
+		ORDER BY `+order+` DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", QueryStatsExtension, err)
+	}
+	defer rows.Close()
+
+	queries := make([]SlowQuery, 0, limit)
+	for rows.Next() {
+		var q SlowQuery
+		var hitPercent sql.NullFloat64
+		if err := rows.Scan(&q.Query, &q.Calls, &q.TotalTimeMs, &q.MeanTimeMs, &q.MinTimeMs,
+			&q.MaxTimeMs, &q.StddevMs, &q.Rows, &hitPercent); err != nil {
+			return nil, fmt.Errorf("failed to scan slow query: %w", err)
+		}
+		if hitPercent.Valid {
+			q.HitPercent = &hitPercent.Float64
+		}
+		queries = append(queries, q)
+	}
+	return queries, rows.Err()
+}
+
+// versionBefore reports whether a major.minor extension version is older than
+// major.minor; unparseable versions count as current
+func versionBefore(version string, major, minor int) bool {
+	var gotMajor, gotMinor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &gotMajor, &gotMinor); err != nil {
+		return false
+	}
+	return gotMajor < major || (gotMajor == major && gotMinor < minor)
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  string
+		want string
+	}{
+		{"key value", "host=db port=5432 user=app password=hunter2 dbname=app", "host=db port=5432 user=app password=**** dbname=app"},
+		{"quoted", `host=db password='it\'s a secret' dbname=app`, "host=db password=**** dbname=app"},
+		{"spaced and upper case", "PASSWORD = hunter2 dbname=app", "PASSWORD = **** dbname=app"},
+		{"url", "postgres://app:hunter2@db:5432/app?sslmode=disable", "postgres://app:****@db:5432/app?sslmode=disable"},
+		{"url query", "postgres://db/app?user=app&password=hunter2&sslmode=disable", "postgres://db/app?user=app&password=****&sslmode=disable"},
+		{"other secret keys", "host=db sslpassword=keypass token=abc", "host=db sslpassword=**** token=****"},
+		{"no secrets", "host=db dbname=app", "host=db dbname=app"},
+		{"sqlite", "file:/data/volumeviz.db?cache=shared", "file:/data/volumeviz.db?cache=shared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactDSN(tt.dsn))
+		})
+	}
+}
+
+func TestAddSecretKeys(t *testing.T) {
+	t.Cleanup(func() {
+		secretKeys.Lock()
+		secretKeys.keys = nil
+		secretKeys.Unlock()
+		AddSecretKeys()
+	})
+
+	assert.Equal(t, "host=db apikey=abc", RedactDSN("host=db apikey=abc"))
+	AddSecretKeys(" APIKey ", "")
+	assert.Equal(t, "host=db apikey=****", RedactDSN("host=db apikey=abc"))
+	assert.Equal(t, "host=db password=****", RedactDSN("host=db password=abc"), "default keys stay masked")
+}
+
+// TestConfig_PasswordRedactedEverywhere checks every place the database
+// configuration surfaces: its DSN, printed configs and errors
+func TestConfig_PasswordRedactedEverywhere(t *testing.T) {
+	const password = "s3cret pass'word"
+	config := DefaultPostgreSQLConfig()
+	config.Password = password
+
+	// The real DSN holds the password, quoted so libpq parses it intact
+	require.Contains(t, config.DSN(), `password='s3cret pass\'word'`)
+	_, err := pq.NewConnector(config.DSN())
+	require.NoError(t, err)
+
+	assert.NotContains(t, config.RedactedDSN(), "s3cret")
+	assert.Contains(t, config.RedactedDSN(), "password=****")
+
+	for _, printed := range []string{
+		fmt.Sprintf("%v", config),
+		fmt.Sprintf("%+v", config),
+		fmt.Sprintf("%v", *config),
+		fmt.Sprintf("%s", config),
+	} {
+		assert.NotContains(t, printed, "s3cret")
+		assert.Contains(t, printed, "Password:****")
+	}
+
+	// Driver errors echoing the password or the DSN are masked, and still unwrap
+	driverErr := fmt.Errorf("connect %s: %w (password %s)", config.DSN(), context.DeadlineExceeded, password)
+	redacted := config.redactError(driverErr)
+	assert.NotContains(t, redacted.Error(), "s3cret")
+	assert.ErrorIs(t, redacted, context.DeadlineExceeded)
+	assert.Same(t, driverErr, errors.Unwrap(redacted))
+
+	unrelated := errors.New("connection refused")
+	assert.Same(t, unrelated, config.redactError(unrelated), "errors without secrets are returned as is")
+	assert.NoError(t, config.redactError(nil))
+
+	db := &DB{config: config}
+	assert.Equal(t, "dial failed, password ****", db.RedactError(fmt.Errorf("dial failed, password %s", password)))
+}
+
+func TestNewDB_RedactsPasswordInErrors(t *testing.T) {
+	// SQLite echoes the path in its error; a password inside it must not leak
+	config := DefaultSQLiteConfig()
+	config.Password = "hunter2-secret"
+	config.Path = filepath.Join(t.TempDir(), "missing", "hunter2-secret", "volumeviz.db")
+	config.Timeout = time.Second
+
+	_, err := NewDB(config)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "hunter2-secret")
+}
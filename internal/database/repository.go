@@ -473,11 +473,14 @@ func BuildUpdateQuery(table string, fields map[string]interface{}, whereField st
 	return query, values
 }
 
-// ListWithPagination is a generic helper for list operations with pagination
+// ListWithPagination is a generic helper for list operations with pagination.
+// Rows are finally ordered by tiebreak, columns that together are unique, so rows
+// with equal sort values keep the same order from one page to the next.
 func ListWithPagination[T any](
 	executor Executor,
 	selectFields []string,
 	tableName string,
+	tiebreak string,
 	options *FilterOptions,
 	scanRowFunc func(*sql.Rows) (*T, error),
 	countFunc func(*FilterOptions) (int, error),
@@ -496,6 +499,7 @@ func ListWithPagination[T any](
 	if options == nil || options.OrderBy == nil {
 		qb.OrderBy("created_at DESC")
 	}
+	qb.OrderBy(tiebreak)
 
 	query, args := qb.Build()
 
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// RollupBackend is the repository capability used by the RollupJob
+type RollupBackend interface {
+	RollupMetrics(ctx context.Context, granularity RollupGranularity) (int, error)
+}
+
+// RollupJob periodically refreshes the daily and weekly volume metrics rollups
+type RollupJob struct {
+	backend  RollupBackend
+	interval time.Duration
+
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRollupJob creates a new rollup job; an interval of zero disables it
+func NewRollupJob(backend RollupBackend, interval time.Duration) *RollupJob {
+	return &RollupJob{
+		backend:  backend,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Enabled reports whether the job maintains the rollups
+func (j *RollupJob) Enabled() bool {
+	return j.interval > 0
+}
+
+// Start refreshes the rollups immediately and then on every interval
+func (j *RollupJob) Start() {
+	if !j.Enabled() {
+		close(j.doneCh)
+		return
+	}
+
+	go func() {
+		defer close(j.doneCh)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.Run()
+			select {
+			case <-ticker.C:
+			case <-j.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the job and waits for a refresh in progress to finish
+func (j *RollupJob) Stop() {
+	j.stopOnce.Do(func() { close(j.stopCh) })
+	<-j.doneCh
+}
+
+// Run refreshes every rollup granularity once. Failures are logged so one
+// granularity failing does not block the others.
+func (j *RollupJob) Run() {
+	for _, granularity := range RollupGranularities {
+		start := time.Now()
+		buckets, err := j.backend.RollupMetrics(context.Background(), granularity)
+		if err != nil {
+			log.Printf("[ERROR] Volume metrics %s rollup failed: %v", granularity, err)
+			continue
+		}
+		log.Printf("[DEBUG] Volume metrics %s rollup refreshed %d buckets in %v", granularity, buckets, time.Since(start))
+	}
+}
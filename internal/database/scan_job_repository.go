@@ -136,6 +136,7 @@ func (r *ScanJobRepository) List(options *FilterOptions) (*PaginatedResult[ScanJ
 		r.getExecutor(),
 		selectFields,
 		TableNames.ScanJobs,
+		"id",
 		options,
 		r.scanScanJobRow,
 		r.getScanJobCount,
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VolumeChanges lists the volumes that changed in the database since a time
+type VolumeChanges struct {
+	Created []string          // Active volumes first stored since then
+	Updated []string          // Other active volumes whose row, mounts or scanned size changed
+	Removed []VolumeTombstone // Volumes removed or deactivated since then
+}
+
+// VolumeTombstone records when a volume was removed
+type VolumeTombstone struct {
+	VolumeID  string
+	RemovedAt time.Time
+}
+
+// recordVolumeTombstone notes that volumeID was removed at removedAt
+func (r *EventRepository) recordVolumeTombstone(volumeID string, removedAt time.Time) error {
+	query := `
+		INSERT INTO volume_tombstones (volume_id, removed_at)
+		VALUES ($1, $2)
+		ON CONFLICT (volume_id)
+		DO UPDATE SET removed_at = EXCLUDED.removed_at
+	`
+	_, err := r.getExecutor().Exec(query, volumeID, removedAt)
+	return err
+}
+
+// ListVolumeChangesSince returns the volumes created, updated and removed at
+// or after since. A volume is updated when its row changed, a container mount
+// of it was added or changed, or it was scanned. Since is truncated to the
+// second, as some timestamps are stored at that precision, so a change may be
+// reported again by the next call but is never missed. A volume removed and
+// created again is only reported as created.
+func (r *EventRepository) ListVolumeChangesSince(ctx context.Context, since time.Time) (*VolumeChanges, error) {
+	since = since.Truncate(time.Second)
+	executor := r.getExecutor()
+	changes := &VolumeChanges{}
+
+	rows, err := executor.Query(`
+		SELECT volume_id, created_at
+		FROM volumes
+		WHERE is_active = true AND (
+			created_at >= $1 OR updated_at >= $1
+			OR volume_id IN (SELECT volume_id FROM volume_mounts WHERE created_at >= $1 OR updated_at >= $1)
+			OR volume_id IN (SELECT volume_name FROM volume_stats WHERE ts >= $1)
+		)
+		ORDER BY volume_id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changed volumes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var volumeID string
+		var createdAt time.Time
+		if err := rows.Scan(&volumeID, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan changed volume: %w", err)
+		}
+		if createdAt.Before(since) {
+			changes.Updated = append(changes.Updated, volumeID)
+		} else {
+			changes.Created = append(changes.Created, volumeID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Volumes deleted outright leave a tombstone; soft-deleted ones stay
+	// behind as inactive rows
+	latest := make(map[string]time.Time)
+	for _, query := range []string{
+		`SELECT volume_id, removed_at FROM volume_tombstones
+		 WHERE removed_at >= $1 AND volume_id NOT IN (SELECT volume_id FROM volumes WHERE is_active = true)`,
+		`SELECT volume_id, updated_at FROM volumes WHERE is_active = false AND updated_at >= $1`,
+	} {
+		if err := r.collectRemovedVolumes(query, since, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	for volumeID, removedAt := range latest {
+		changes.Removed = append(changes.Removed, VolumeTombstone{VolumeID: volumeID, RemovedAt: removedAt})
+	}
+	sort.Slice(changes.Removed, func(i, j int) bool {
+		return changes.Removed[i].VolumeID < changes.Removed[j].VolumeID
+	})
+
+	return changes, nil
+}
+
+// collectRemovedVolumes records in latest the latest removal time of each
+// volume listed by query, which selects a volume ID and removal time
+func (r *EventRepository) collectRemovedVolumes(query string, since time.Time, latest map[string]time.Time) error {
+	rows, err := r.getExecutor().Query(query, since)
+	if err != nil {
+		return fmt.Errorf("failed to query removed volumes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var volumeID string
+		var removedAt time.Time
+		if err := rows.Scan(&volumeID, &removedAt); err != nil {
+			return fmt.Errorf("failed to scan removed volume: %w", err)
+		}
+		if removedAt.After(latest[volumeID]) {
+			latest[volumeID] = removedAt
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupVolumeChangesTestDB(t *testing.T) *DB {
+	db, err := NewDB(&Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "changes.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+
+	applied := 0
+	for _, m := range migrations {
+		if m.Version == "001" || m.Version == "010" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+			applied++
+		}
+	}
+	require.Equal(t, 2, applied, "migrations 001 and 010 should be embedded")
+
+	_, err = db.Exec(`
+		CREATE TABLE volume_stats (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			volume_name TEXT NOT NULL,
+			size_bytes INTEGER NOT NULL DEFAULT 0,
+			scan_method TEXT NOT NULL DEFAULT 'du',
+			ts DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestEventRepository_ListVolumeChangesSince(t *testing.T) {
+	db := setupVolumeChangesTestDB(t)
+	repo := NewEventRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	since := now.Add(-time.Hour)
+
+	upsert := func(name string, createdAt, updatedAt time.Time, active bool) {
+		t.Helper()
+		require.NoError(t, repo.UpsertVolume(ctx, &Volume{
+			VolumeID:   name,
+			Name:       name,
+			Driver:     "local",
+			Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+			Scope:      "local",
+			Status:     "active",
+			IsActive:   active,
+			BaseModel:  BaseModel{CreatedAt: createdAt, UpdatedAt: updatedAt},
+		}))
+	}
+
+	upsert("untouched", old, old, true)
+	upsert("fresh", now, now, true)
+	upsert("relabelled", old, now, true)
+	upsert("mounted", old, old, true)
+	upsert("detached", old, old, true)
+	upsert("scanned", old, old, true)
+	upsert("gone", old, old, true)
+	upsert("deactivated", old, now, false)
+	upsert("reborn", old, old, true)
+
+	require.NoError(t, repo.UpsertContainer(ctx, &Container{
+		ContainerID: "c1", Name: "app", Image: "app:latest", State: "running", IsActive: true,
+		BaseModel: BaseModel{CreatedAt: now, UpdatedAt: now},
+	}))
+	require.NoError(t, repo.UpsertVolumeMount(ctx, &VolumeMount{
+		VolumeID: "mounted", ContainerID: "c1", MountPath: "/data", AccessMode: "rw", IsActive: true,
+		BaseModel: BaseModel{CreatedAt: now, UpdatedAt: now},
+	}))
+	// A mount the reconciler found gone is kept inactive, so its detach is seen
+	require.NoError(t, repo.UpsertVolumeMount(ctx, &VolumeMount{
+		VolumeID: "detached", ContainerID: "c1", MountPath: "/old", AccessMode: "rw", IsActive: false,
+		BaseModel: BaseModel{CreatedAt: old, UpdatedAt: now},
+	}))
+	_, err := db.Exec(`INSERT INTO volume_stats (volume_name, size_bytes, scan_method, ts) VALUES ($1, $2, $3, $4)`,
+		"scanned", 1024, "du", now)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteVolume(ctx, "gone"))
+	require.NoError(t, repo.DeleteVolume(ctx, "reborn"))
+	upsert("reborn", now, now, true)
+
+	// Removed before since, so already known to the client
+	require.NoError(t, repo.recordVolumeTombstone("long-gone", old))
+
+	changes, err := repo.ListVolumeChangesSince(ctx, since)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fresh", "reborn"}, changes.Created)
+	assert.Equal(t, []string{"detached", "mounted", "relabelled", "scanned"}, changes.Updated)
+	require.Len(t, changes.Removed, 2)
+	assert.Equal(t, "deactivated", changes.Removed[0].VolumeID)
+	assert.Equal(t, "gone", changes.Removed[1].VolumeID)
+	assert.False(t, changes.Removed[1].RemovedAt.Before(since))
+
+	// Nothing changed since the present
+	changes, err = repo.ListVolumeChangesSince(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, changes.Created)
+	assert.Empty(t, changes.Updated)
+	assert.Empty(t, changes.Removed)
+}
@@ -84,6 +84,7 @@ func (r *VolumeRepository) List(options *FilterOptions) (*PaginatedResult[Volume
 		r.getExecutor(),
 		selectFields,
 		TableNames.Volumes,
+		"name, id",
 		options,
 		r.scanVolumeRow,
 		r.getVolumeCount,
@@ -97,7 +98,7 @@ func (r *VolumeRepository) GetByDriver(driver string) ([]*Volume, error) {
 		       scope, status, last_scanned, is_active, created_at, updated_at
 		FROM volumes 
 		WHERE driver = $1 AND is_active = true
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, name, id
 	`
 
 	executor := r.getExecutor()
@@ -117,7 +118,7 @@ func (r *VolumeRepository) GetByLabel(key, value string) ([]*Volume, error) {
 		       scope, status, last_scanned, is_active, created_at, updated_at
 		FROM volumes 
 		WHERE labels->$1 = $2 AND is_active = true
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, name, id
 	`
 
 	executor := r.getExecutor()
@@ -2,10 +2,12 @@ package database
 
 import (
 	"database/sql/driver"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Test data helpers
@@ -246,3 +248,61 @@ func TestLabels_DriverValuer(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, value)
 }
+
+// setupVolumeTestDB returns a SQLite database with the volumes table
+func setupVolumeTestDB(t testing.TB) *DB {
+	db, err := NewDB(&Config{
+		Type:         DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "volumes.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := NewMigrationManager(db).LoadMigrationsFromFiles()
+	require.NoError(t, err)
+	for _, m := range migrations {
+		if m.Version == "001" {
+			_, err := db.Exec(m.UpSQL)
+			require.NoError(t, err)
+		}
+	}
+	return db
+}
+
+func TestVolumeRepository_ListBreaksTiesByName(t *testing.T) {
+	db := setupVolumeTestDB(t)
+
+	// Same driver and creation time, inserted out of name order
+	names := []string{"delta", "alpha", "echo", "charlie", "bravo"}
+	for _, name := range names {
+		_, err := db.Exec(`INSERT INTO volumes (volume_id, name, driver, mountpoint, created_at, updated_at)
+			VALUES ($1, $2, 'local', '/data', '2025-01-01 00:00:00', '2025-01-01 00:00:00')`, name, name)
+		require.NoError(t, err)
+	}
+
+	repo := NewVolumeRepository(db)
+	orderBy := "driver"
+	for _, options := range []*FilterOptions{nil, {OrderBy: &orderBy}} {
+		var listed []string
+		for offset := 0; offset < len(names); offset += 2 {
+			limit, offset := 2, offset
+			page := &FilterOptions{Limit: &limit, Offset: &offset}
+			if options != nil {
+				page.OrderBy = options.OrderBy
+			}
+			result, err := repo.List(page)
+			require.NoError(t, err)
+			for _, volume := range result.Items {
+				listed = append(listed, volume.Name)
+			}
+		}
+		assert.Equal(t, []string{"alpha", "bravo", "charlie", "delta", "echo"}, listed)
+	}
+
+	byDriver, err := repo.GetByDriver("local")
+	require.NoError(t, err)
+	require.Len(t, byDriver, len(names))
+	assert.Equal(t, "alpha", byDriver[0].Name)
+}
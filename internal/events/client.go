@@ -36,6 +36,9 @@ type EventsClient struct {
 	connMutex    sync.RWMutex
 	lastEventTime *time.Time
 	streamStartTime *time.Time
+	// lastEventAt and lastConnectedAt track stream liveness, guarded by connMutex
+	lastEventAt     *time.Time
+	lastConnectedAt *time.Time
 	
 	// Backoff state
 	backoffCount int
@@ -123,6 +126,8 @@ func (c *EventsClient) IsConnected() bool {
 
 // GetLastEventTime returns the timestamp of the last processed event
 func (c *EventsClient) GetLastEventTime() *time.Time {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
 	return c.lastEventTime
 }
 
@@ -149,7 +154,11 @@ func (c *EventsClient) GetMetrics() *EventMetrics {
 		LastReconnectTime: c.metrics.LastReconnectTime,
 		Connected:        c.connected,
 		QueueSize:        queueSize,
+		LastEventAt:      c.lastEventAt,
+		LastConnectedAt:  c.lastConnectedAt,
+		ReconnectAttempts: c.metrics.ReconnectAttempts,
 	}
+	metrics.Healthy, metrics.UnhealthyReason = c.healthLocked(time.Now())
 
 	// Copy maps
 	for k, v := range c.metrics.ProcessedTotal {
@@ -161,14 +170,67 @@ func (c *EventsClient) GetMetrics() *EventMetrics {
 	for k, v := range c.metrics.ReconcileRuns {
 		metrics.ReconcileRuns[k] = v
 	}
+	if c.reconciler != nil {
+		for k, v := range c.reconciler.ReconcileRuns() {
+			metrics.ReconcileRuns[k] += v
+		}
+	}
 
 	return metrics
 }
 
-// streamEventsWithRetry handles events streaming with automatic reconnection
+// healthLocked reports whether the events stream is live at now, and why not.
+// A connected stream that has gone the health window without delivering an
+// event or reconnecting is considered stalled, since a dead stream looks just
+// like a quiet one. Callers must hold connMutex.
+func (c *EventsClient) healthLocked(now time.Time) (bool, string) {
+	if !c.connected {
+		return false, "not connected to the Docker events stream"
+	}
+	if c.config == nil || c.config.HealthWindow <= 0 {
+		return true, ""
+	}
+
+	var lastActivity time.Time
+	for _, t := range []*time.Time{c.lastEventAt, c.lastConnectedAt} {
+		if t != nil && t.After(lastActivity) {
+			lastActivity = *t
+		}
+	}
+	if lastActivity.IsZero() {
+		return true, ""
+	}
+	if idle := now.Sub(lastActivity); idle > c.config.HealthWindow {
+		return false, fmt.Sprintf("no Docker events or reconnects for %v (health window %v)",
+			idle.Truncate(time.Second), c.config.HealthWindow)
+	}
+	return true, ""
+}
+
+// recordError counts an error of the given kind
+func (c *EventsClient) recordError(kind string) {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+	c.metrics.ErrorsTotal[kind]++
+}
+
+// defaultBackoffReset is how long a stream must stay up to reset the
+// reconnect backoff when EventsConfig.BackoffResetAfter is unset
+const defaultBackoffReset = time.Minute
+
+// streamEventsWithRetry handles events streaming with automatic reconnection.
+// Each reconnect waits an exponential backoff with jitter, capped at
+// BackoffMaxDuration, which only starts over once a stream stayed up for
+// BackoffResetAfter, so a daemon that is down or drops every stream at once
+// is not retried in a tight loop.
 func (c *EventsClient) streamEventsWithRetry() {
 	defer c.wg.Done()
 
+	resetAfter := c.config.BackoffResetAfter
+	if resetAfter <= 0 {
+		resetAfter = defaultBackoffReset
+	}
+
 	for {
 		select {
 		case <-c.ctx.Done():
@@ -176,9 +238,17 @@ func (c *EventsClient) streamEventsWithRetry() {
 		default:
 		}
 
-		if err := c.streamEvents(); err != nil {
-			c.setConnected(false)
-			c.metrics.ErrorsTotal["stream"]++
+		started := time.Now()
+		err := c.streamEvents()
+		// A stream that ended, cleanly or not, is no longer connected
+		c.setConnected(false)
+		if c.ctx.Err() != nil {
+			return
+		}
+		uptime := time.Since(started)
+
+		if err != nil {
+			c.recordError("stream")
 			if c.promMetrics != nil {
 				c.promMetrics.RecordEventFailed("stream", "")
 				// Record stream duration if we had a connection
@@ -187,31 +257,57 @@ func (c *EventsClient) streamEventsWithRetry() {
 					c.promMetrics.RecordStreamDuration(duration.Seconds())
 				}
 			}
-			log.Printf("[ERROR] Docker events stream error: %v", err)
-
-			// Exponential backoff with jitter
-			backoffDuration := c.calculateBackoff()
-			log.Printf("[INFO] Reconnecting to Docker events in %v (attempt %d)", backoffDuration, c.backoffCount+1)
-
-			select {
-			case <-time.After(backoffDuration):
-				c.backoffCount++
-				c.metrics.ReconnectsTotal++
-				now := time.Now()
-				c.metrics.LastReconnectTime = &now
-				if c.promMetrics != nil {
-					c.promMetrics.RecordReconnect()
-				}
-			case <-c.ctx.Done():
-				return
-			}
-		} else {
-			// Reset backoff on successful connection
+		}
+
+		if uptime >= resetAfter && c.backoffCount > 0 {
+			log.Printf("[INFO] Docker events stream stayed up for %v after %d reconnect attempts; resetting backoff",
+				uptime.Truncate(time.Second), c.backoffCount)
 			c.backoffCount = 0
 		}
+
+		backoffDuration := c.calculateBackoff()
+		attempt := c.backoffCount + 1
+		if logReconnectAttempt(attempt) {
+			if err != nil {
+				log.Printf("[ERROR] Docker events stream error: %v", err)
+			} else {
+				log.Printf("[WARN] Docker events stream ended")
+			}
+			log.Printf("[INFO] Reconnecting to Docker events in %v (attempt %d)", backoffDuration, attempt)
+			if attempt == quietReconnectAttempts {
+				log.Printf("[INFO] Only logging Docker events reconnect attempts that are a power of two from now on")
+			}
+		}
+
+		select {
+		case <-time.After(backoffDuration):
+			c.backoffCount++
+			now := time.Now()
+			c.connMutex.Lock()
+			c.metrics.ReconnectsTotal++
+			c.metrics.ReconnectAttempts = c.backoffCount
+			c.metrics.LastReconnectTime = &now
+			c.connMutex.Unlock()
+			if c.promMetrics != nil {
+				c.promMetrics.RecordReconnect()
+				c.promMetrics.SetReconnectAttempts(c.backoffCount)
+			}
+		case <-c.ctx.Done():
+			return
+		}
 	}
 }
 
+// quietReconnectAttempts is how many reconnect attempts in a row are all logged
+const quietReconnectAttempts = 4
+
+// logReconnectAttempt reports whether reconnect attempt n is logged: each of
+// the first quietReconnectAttempts, then 8, 16, 32 and so on, so a daemon that
+// stays down for hours does not flood the logs
+func logReconnectAttempt(n int) bool {
+	return n <= quietReconnectAttempts || n&(n-1) == 0
+}
+
 // streamEvents connects to Docker events API and streams events
 func (c *EventsClient) streamEvents() error {
 	// Create filters for volume and container events
@@ -233,10 +329,14 @@ func (c *EventsClient) streamEvents() error {
 
 	for {
 		select {
-		case event := <-eventCh:
+		case event, ok := <-eventCh:
+			if !ok {
+				return fmt.Errorf("events stream closed")
+			}
+			c.recordEventReceived()
 			if err := c.handleRawEvent(event); err != nil {
 				log.Printf("[ERROR] Failed to handle event: %v", err)
-				c.metrics.ErrorsTotal["handler"]++
+				c.recordError("handler")
 			}
 		case err := <-errCh:
 			if err != nil && err != io.EOF {
@@ -249,6 +349,14 @@ func (c *EventsClient) streamEvents() error {
 	}
 }
 
+// recordEventReceived notes that the stream delivered an event, proving it live
+func (c *EventsClient) recordEventReceived() {
+	now := time.Now()
+	c.connMutex.Lock()
+	c.lastEventAt = &now
+	c.connMutex.Unlock()
+}
+
 // handleRawEvent processes a raw Docker event and converts it to our internal format
 func (c *EventsClient) handleRawEvent(rawEvent events.Message) error {
 	// Convert raw event to our internal format
@@ -268,7 +376,9 @@ func (c *EventsClient) handleRawEvent(rawEvent events.Message) error {
 		// Successfully enqueued
 	default:
 		// Queue is full, drop the event and increment counter
+		c.connMutex.Lock()
 		c.metrics.DroppedTotal++
+		c.connMutex.Unlock()
 		if c.promMetrics != nil {
 			c.promMetrics.RecordEventDropped()
 		}
@@ -325,6 +435,8 @@ func (c *EventsClient) getEventType(event events.Message) EventType {
 			return ContainerDied
 		case "destroy":
 			return ContainerDestroyed
+		case "rename":
+			return ContainerRenamed
 		}
 	}
 	return ""
@@ -344,13 +456,15 @@ func (c *EventsClient) processEvents() {
 			
 			if err := c.processEvent(event); err != nil {
 				log.Printf("[ERROR] Failed to process event %s %s: %v", event.Action, event.ID, err)
-				c.metrics.ErrorsTotal["processing"]++
+				c.recordError("processing")
 				if c.promMetrics != nil {
 					c.promMetrics.RecordEventFailed("processing", event.Type)
 				}
 			} else {
+				c.connMutex.Lock()
 				c.metrics.ProcessedTotal[event.Type]++
 				c.lastEventTime = &event.Time
+				c.connMutex.Unlock()
 				if c.promMetrics != nil {
 					c.promMetrics.RecordEventProcessed(event.Type, event.Action)
 					c.promMetrics.SetLastEventTime(float64(event.Time.Unix()))
@@ -372,24 +486,24 @@ func (c *EventsClient) processEvent(event *DockerEvent) error {
 
 // calculateBackoff calculates exponential backoff with jitter
 func (c *EventsClient) calculateBackoff() time.Duration {
-	if c.backoffCount == 0 {
-		return c.config.BackoffMinDuration
-	}
+	return backoffDelay(c.config.BackoffMinDuration, c.config.BackoffMaxDuration, c.backoffCount, rand.Float64()*2-1)
+}
 
-	// Exponential backoff: min * 2^count
-	exponential := float64(c.config.BackoffMinDuration.Nanoseconds()) * math.Pow(2, float64(c.backoffCount))
-	
-	// Cap at max backoff
-	maxNanos := float64(c.config.BackoffMaxDuration.Nanoseconds())
-	if exponential > maxNanos {
-		exponential = maxNanos
+// backoffDelay returns how long to wait before reconnect attempt attempt+1:
+// minDelay doubled attempt times, at most maxDelay, moved by jitter (-1 to 1)
+// times a quarter of it. The first attempt waits exactly minDelay and no
+// attempt waits longer than maxDelay.
+func backoffDelay(minDelay, maxDelay time.Duration, attempt int, jitter float64) time.Duration {
+	if attempt <= 0 {
+		return minDelay
 	}
 
+	// Exponential backoff: min * 2^attempt, capped before it can overflow
+	exponential := math.Min(float64(minDelay)*math.Pow(2, float64(attempt)), float64(maxDelay))
+
 	// Add jitter (±25%)
-	jitter := exponential * 0.25 * (rand.Float64()*2 - 1)
-	backoffNanos := int64(exponential + jitter)
-	
-	return time.Duration(backoffNanos)
+	delay := time.Duration(exponential + exponential*0.25*jitter)
+	return min(delay, maxDelay)
 }
 
 // runPeriodicReconciliation runs reconciliation at configured intervals
@@ -398,6 +512,16 @@ func (c *EventsClient) runPeriodicReconciliation() {
 	
 	ticker := time.NewTicker(c.config.ReconcileInterval)
 	defer ticker.Stop()
+
+	// Incremental passes run between full ones when configured; a nil
+	// channel never fires
+	var incremental <-chan time.Time
+	if interval := c.config.ReconcileIncrementalInterval; interval > 0 {
+		incrementalTicker := time.NewTicker(interval)
+		defer incrementalTicker.Stop()
+		incremental = incrementalTicker.C
+		log.Printf("[INFO] Starting incremental reconciliation every %v", interval)
+	}
 	
 	log.Printf("[INFO] Starting periodic reconciliation every %v", c.config.ReconcileInterval)
 	
@@ -412,6 +536,10 @@ func (c *EventsClient) runPeriodicReconciliation() {
 			if err := c.runReconciliation(); err != nil {
 				log.Printf("[ERROR] Periodic reconciliation failed: %v", err)
 			}
+		case <-incremental:
+			if err := c.runIncrementalReconciliation(); err != nil {
+				log.Printf("[ERROR] Incremental reconciliation failed: %v", err)
+			}
 		case <-c.ctx.Done():
 			return
 		}
@@ -426,12 +554,24 @@ func (c *EventsClient) runReconciliation() error {
 	return c.reconciler.FullReconcile(ctx)
 }
 
+// runIncrementalReconciliation executes an incremental reconciliation cycle
+func (c *EventsClient) runIncrementalReconciliation() error {
+	ctx, cancel := context.WithTimeout(c.ctx, 5*time.Minute)
+	defer cancel()
+
+	return c.reconciler.IncrementalReconcile(ctx)
+}
+
 // setConnected safely sets the connection status
 func (c *EventsClient) setConnected(connected bool) {
 	c.connMutex.Lock()
 	defer c.connMutex.Unlock()
 	c.connected = connected
 	c.metrics.Connected = connected
+	if connected {
+		now := time.Now()
+		c.lastConnectedAt = &now
+	}
 	if c.promMetrics != nil {
 		c.promMetrics.SetConnectionStatus(connected)
 	}
@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -56,6 +57,12 @@ func TestEventTypeMapping(t *testing.T) {
 			action:         "destroy",
 			expectedResult: ContainerDestroyed,
 		},
+		{
+			name:           "container rename",
+			eventType:      "container",
+			action:         "rename",
+			expectedResult: ContainerRenamed,
+		},
 		{
 			name:           "unknown event",
 			eventType:      "network",
@@ -247,6 +254,70 @@ func TestCalculateBackoff(t *testing.T) {
 	}
 }
 
+func TestBackoffDelay_GrowsExponentiallyToCap(t *testing.T) {
+	minDelay, maxDelay := time.Second, 30*time.Second
+
+	want := []time.Duration{
+		1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		16 * time.Second, 30 * time.Second, 30 * time.Second,
+	}
+	for attempt, expected := range want {
+		assert.Equal(t, expected, backoffDelay(minDelay, maxDelay, attempt, 0), "attempt %d", attempt)
+	}
+
+	// Jitter moves a delay by up to a quarter, but never past the cap
+	assert.Equal(t, 3*time.Second, backoffDelay(minDelay, maxDelay, 2, -1))
+	assert.Equal(t, 5*time.Second, backoffDelay(minDelay, maxDelay, 2, 1))
+	assert.Equal(t, maxDelay, backoffDelay(minDelay, maxDelay, 5, 1))
+	assert.Equal(t, 22500*time.Millisecond, backoffDelay(minDelay, maxDelay, 5, -1))
+	assert.Equal(t, maxDelay, backoffDelay(minDelay, maxDelay, 5000, 1), "huge attempt counts do not overflow")
+}
+
+func TestLogReconnectAttempt(t *testing.T) {
+	var logged []int
+	for n := 1; n <= 70; n++ {
+		if logReconnectAttempt(n) {
+			logged = append(logged, n)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 8, 16, 32, 64}, logged)
+}
+
+func TestEventsClient_BackoffResetsAfterStableStream(t *testing.T) {
+	// Three streams the daemon drops at once, then one that stays up
+	dockerClient := &streamDockerClient{streams: make(chan chan events.Message, 5)}
+	for range 3 {
+		dropped := make(chan events.Message)
+		close(dropped)
+		dockerClient.streams <- dropped
+	}
+	stable := make(chan events.Message)
+	dockerClient.streams <- stable
+
+	cfg := &config.EventsConfig{
+		Enabled:            true,
+		QueueSize:          10,
+		BackoffMinDuration: time.Millisecond,
+		BackoffMaxDuration: time.Second,
+		BackoffResetAfter:  50 * time.Millisecond,
+	}
+	client := NewEventsClient(dockerClient, cfg, &MockEventProcessor{}, nil, nil)
+	require.NoError(t, client.Start(context.Background()))
+	defer client.Stop(context.Background())
+
+	require.Eventually(t, func() bool { return dockerClient.openedStreams() == 4 && client.IsConnected() }, time.Second, time.Millisecond)
+	assert.Equal(t, 3, client.GetMetrics().ReconnectAttempts, "dropped streams do not reset the backoff")
+
+	// Once the stream stayed up, the next drop starts the backoff over
+	time.Sleep(2 * cfg.BackoffResetAfter)
+	dockerClient.streams <- make(chan events.Message)
+	close(stable)
+	require.Eventually(t, func() bool { return dockerClient.openedStreams() == 5 && client.IsConnected() }, time.Second, time.Millisecond)
+	metrics := client.GetMetrics()
+	assert.Equal(t, 1, metrics.ReconnectAttempts)
+	assert.Equal(t, int64(4), metrics.ReconnectsTotal)
+}
+
 func TestIsConnected(t *testing.T) {
 	client := &EventsClient{
 		metrics: &EventMetrics{
@@ -301,6 +372,76 @@ func TestGetMetrics(t *testing.T) {
 	assert.Equal(t, int64(1), metrics.ReconcileRuns["volumes"])
 }
 
+func TestGetMetrics_ConcurrentWithProcessing(t *testing.T) {
+	reconciler := NewReconcilerService(nil, nil, &config.EventsConfig{}, nil)
+	client := NewEventsClient(nil, &config.EventsConfig{QueueSize: 64}, &MockEventProcessor{}, reconciler, nil)
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	client.wg.Add(1)
+	go client.processEvents()
+
+	const eventCount = 500
+	var writers sync.WaitGroup
+	writers.Add(3)
+	go func() {
+		defer writers.Done()
+		for range eventCount {
+			client.eventQueue <- &DockerEvent{Type: VolumeCreated, Time: time.Now()}
+		}
+	}()
+	go func() {
+		defer writers.Done()
+		for range eventCount {
+			client.recordError("stream")
+		}
+	}()
+	go func() {
+		defer writers.Done()
+		for range eventCount {
+			reconciler.recordRun("incremental", false, time.Millisecond)
+		}
+	}()
+
+	// Readers walk each snapshot while the counters keep moving
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				metrics := client.GetMetrics()
+				var total int64
+				for _, counts := range []map[string]int64{metrics.ErrorsTotal, metrics.ReconcileRuns} {
+					for _, count := range counts {
+						total += count
+					}
+				}
+				total += metrics.ProcessedTotal[VolumeCreated]
+				assert.LessOrEqual(t, total, int64(3*eventCount))
+			}
+		}()
+	}
+
+	writers.Wait()
+	require.Eventually(t, func() bool {
+		return client.GetMetrics().ProcessedTotal[VolumeCreated] == eventCount
+	}, 5*time.Second, time.Millisecond)
+	close(done)
+	readers.Wait()
+	client.cancel()
+	client.wg.Wait()
+
+	metrics := client.GetMetrics()
+	assert.Equal(t, int64(eventCount), metrics.ProcessedTotal[VolumeCreated])
+	assert.Equal(t, int64(eventCount), metrics.ErrorsTotal["stream"])
+	assert.Equal(t, int64(eventCount), metrics.ReconcileRuns["incremental"])
+}
+
 func TestClientProcessEvent(t *testing.T) {
 	mockProcessor := &MockEventProcessor{}
 	
@@ -350,4 +491,88 @@ func (m *MockEventProcessor) ProcessEvent(ctx context.Context, event *DockerEven
 		return assert.AnError
 	}
 	return nil
-}
\ No newline at end of file
+}
+// streamDockerClient serves a Docker events stream the test controls
+type streamDockerClient struct {
+	MockDockerClient
+	mu      sync.Mutex
+	opened  int
+	streams chan chan events.Message
+}
+
+func (s *streamDockerClient) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	s.mu.Lock()
+	s.opened++
+	s.mu.Unlock()
+	return <-s.streams, make(chan error)
+}
+
+func (s *streamDockerClient) openedStreams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.opened
+}
+
+func TestEventsClient_StalledStreamTurnsUnhealthy(t *testing.T) {
+	stream := make(chan events.Message)
+	dockerClient := &streamDockerClient{streams: make(chan chan events.Message, 2)}
+	dockerClient.streams <- stream
+
+	cfg := &config.EventsConfig{
+		Enabled:            true,
+		QueueSize:          10,
+		BackoffMinDuration: time.Millisecond,
+		BackoffMaxDuration: time.Millisecond,
+		HealthWindow:       100 * time.Millisecond,
+	}
+	client := NewEventsClient(dockerClient, cfg, &MockEventProcessor{}, nil, nil)
+	require.NoError(t, client.Start(context.Background()))
+	defer client.Stop(context.Background())
+
+	require.Eventually(t, client.IsConnected, time.Second, 5*time.Millisecond)
+	metrics := client.GetMetrics()
+	assert.True(t, metrics.Healthy)
+	assert.NotNil(t, metrics.LastConnectedAt)
+	assert.Nil(t, metrics.LastEventAt)
+
+	// The stream stays open but delivers nothing: connected, yet stalled
+	require.Eventually(t, func() bool { return !client.GetMetrics().Healthy }, time.Second, 5*time.Millisecond)
+	metrics = client.GetMetrics()
+	assert.True(t, metrics.Connected)
+	assert.Contains(t, metrics.UnhealthyReason, "no Docker events or reconnects")
+
+	// Any event, even one the client ignores, proves the stream live again
+	stream <- events.Message{Type: "network", Action: "connect"}
+	require.Eventually(t, func() bool { return client.GetMetrics().Healthy }, time.Second, 5*time.Millisecond)
+	assert.NotNil(t, client.GetMetrics().LastEventAt)
+
+	// A stream closed by the daemon is noticed and reconnected
+	next := make(chan events.Message)
+	dockerClient.streams <- next
+	close(stream)
+	require.Eventually(t, func() bool { return dockerClient.openedStreams() == 2 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, client.IsConnected, time.Second, 5*time.Millisecond)
+	metrics = client.GetMetrics()
+	assert.Equal(t, int64(1), metrics.ErrorsTotal["stream"])
+	assert.Equal(t, int64(1), metrics.ReconnectsTotal)
+	assert.True(t, metrics.Healthy)
+}
+
+func TestEventsClient_HealthWithoutWindow(t *testing.T) {
+	stale := time.Now().Add(-24 * time.Hour)
+	client := &EventsClient{
+		config:          &config.EventsConfig{},
+		metrics:         &EventMetrics{},
+		connected:       true,
+		lastConnectedAt: &stale,
+	}
+
+	healthy, reason := client.healthLocked(time.Now())
+	assert.True(t, healthy, "a zero window disables the stall check")
+	assert.Empty(t, reason)
+
+	client.connected = false
+	healthy, reason = client.healthLocked(time.Now())
+	assert.False(t, healthy)
+	assert.Contains(t, reason, "not connected")
+}
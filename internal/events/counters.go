@@ -0,0 +1,32 @@
+package events
+
+import "sync"
+
+// counterMap is a set of counters keyed by name that is safe for concurrent
+// use. The zero value is ready to use.
+type counterMap struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// inc adds one to the named counter
+func (m *counterMap) inc(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int64)
+	}
+	m.counts[key]++
+}
+
+// snapshot copies the counters, so callers may read or encode it while
+// counting carries on
+func (m *counterMap) snapshot() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[string]int64, len(m.counts))
+	for key, count := range m.counts {
+		counts[key] = count
+	}
+	return counts
+}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// eventGuardTTL is how long the last-processed state of a resource is remembered.
+	// Replays older than this window are no longer recognised as duplicates.
+	eventGuardTTL = 15 * time.Minute
+
+	// eventGuardPruneEvery controls how many claims happen between prune passes
+	eventGuardPruneEvery = 256
+)
+
+// Reasons reported when an event is skipped by the guard
+const (
+	skipReasonDuplicate = "duplicate"
+	skipReasonStale     = "stale"
+)
+
+// guardEntry records the last event applied to a resource
+type guardEntry struct {
+	time      time.Time
+	eventType EventType
+	seenAt    time.Time
+}
+
+// eventGuard tracks the last processed event per resource so that replayed
+// (reconnect) or overlapping (reconciler) events are not applied twice and
+// older events arriving out of order do not roll state back.
+type eventGuard struct {
+	mu      sync.Mutex
+	entries map[string]guardEntry
+	ttl     time.Duration
+	claims  int
+	now     func() time.Time
+}
+
+// newEventGuard creates a new event guard
+func newEventGuard(ttl time.Duration) *eventGuard {
+	return &eventGuard{
+		entries: make(map[string]guardEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// resourceKey identifies the resource an event applies to
+func resourceKey(event *DockerEvent) string {
+	switch event.Type {
+	case VolumeCreated, VolumeRemoved:
+		return "volume:" + event.Name
+	default:
+		return "container:" + event.ID
+	}
+}
+
+// claim checks whether the event should be applied and, if so, records it as
+// the latest for its resource. Events without a timestamp are always applied.
+// The previous entry is returned so a failed event can be released again.
+func (g *eventGuard) claim(event *DockerEvent) (prev guardEntry, hadPrev bool, reason string) {
+	if event.Time.IsZero() {
+		return guardEntry{}, false, ""
+	}
+
+	key := resourceKey(event)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.claims++
+	if g.claims%eventGuardPruneEvery == 0 {
+		g.pruneLocked()
+	}
+
+	prev, hadPrev = g.entries[key]
+	if hadPrev {
+		if event.Time.Before(prev.time) {
+			return prev, hadPrev, skipReasonStale
+		}
+		if event.Time.Equal(prev.time) && event.Type == prev.eventType {
+			return prev, hadPrev, skipReasonDuplicate
+		}
+	}
+
+	g.entries[key] = guardEntry{time: event.Time, eventType: event.Type, seenAt: g.now()}
+	return prev, hadPrev, ""
+}
+
+// release restores the previous entry after the claimed event failed to apply,
+// so a retry or replay of the same event is not treated as a duplicate
+func (g *eventGuard) release(event *DockerEvent, prev guardEntry, hadPrev bool) {
+	if event.Time.IsZero() {
+		return
+	}
+
+	key := resourceKey(event)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Only roll back if nothing newer was claimed in the meantime
+	current, ok := g.entries[key]
+	if !ok || !current.time.Equal(event.Time) || current.eventType != event.Type {
+		return
+	}
+
+	if hadPrev {
+		g.entries[key] = prev
+	} else {
+		delete(g.entries, key)
+	}
+}
+
+// pruneLocked drops entries not touched within the TTL; g.mu must be held
+func (g *eventGuard) pruneLocked() {
+	cutoff := g.now().Add(-g.ttl)
+	for key, entry := range g.entries {
+		if entry.seenAt.Before(cutoff) {
+			delete(g.entries, key)
+		}
+	}
+}
+
+// size returns the number of tracked resources
+func (g *eventGuard) size() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.entries)
+}
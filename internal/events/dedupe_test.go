@@ -0,0 +1,72 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventGuardClaim(t *testing.T) {
+	guard := newEventGuard(time.Minute)
+	base := time.Now()
+
+	start := &DockerEvent{Type: ContainerStarted, ID: "c1", Time: base}
+	_, _, reason := guard.claim(start)
+	assert.Empty(t, reason)
+
+	_, _, reason = guard.claim(start)
+	assert.Equal(t, skipReasonDuplicate, reason)
+
+	// A different transition with the same timestamp is not a duplicate
+	_, _, reason = guard.claim(&DockerEvent{Type: ContainerDied, ID: "c1", Time: base})
+	assert.Empty(t, reason)
+
+	_, _, reason = guard.claim(&DockerEvent{Type: ContainerStopped, ID: "c1", Time: base.Add(-time.Millisecond)})
+	assert.Equal(t, skipReasonStale, reason)
+
+	// Other resources are tracked independently
+	_, _, reason = guard.claim(&DockerEvent{Type: ContainerStarted, ID: "c2", Time: base.Add(-time.Hour)})
+	assert.Empty(t, reason)
+
+	// Events without a timestamp are never skipped or tracked
+	untimed := &DockerEvent{Type: ContainerStarted, ID: "c3"}
+	_, _, reason = guard.claim(untimed)
+	assert.Empty(t, reason)
+	_, _, reason = guard.claim(untimed)
+	assert.Empty(t, reason)
+	assert.Equal(t, 2, guard.size())
+}
+
+func TestEventGuardRelease(t *testing.T) {
+	guard := newEventGuard(time.Minute)
+	base := time.Now()
+
+	first := &DockerEvent{Type: ContainerStarted, ID: "c1", Time: base}
+	guard.claim(first)
+
+	second := &DockerEvent{Type: ContainerStopped, ID: "c1", Time: base.Add(time.Second)}
+	prev, hadPrev, _ := guard.claim(second)
+	guard.release(second, prev, hadPrev)
+
+	// The previous entry is restored, so the failed event can be retried
+	_, _, reason := guard.claim(second)
+	assert.Empty(t, reason)
+	_, _, reason = guard.claim(first)
+	assert.Equal(t, skipReasonStale, reason)
+}
+
+func TestEventGuardPrune(t *testing.T) {
+	guard := newEventGuard(time.Minute)
+	now := time.Now()
+	guard.now = func() time.Time { return now }
+
+	guard.claim(&DockerEvent{Type: VolumeCreated, Name: "old", Time: now})
+
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < eventGuardPruneEvery; i++ {
+		guard.claim(&DockerEvent{Type: VolumeCreated, Name: "fresh", Time: now.Add(time.Duration(i))})
+	}
+
+	assert.Equal(t, 1, guard.size())
+}
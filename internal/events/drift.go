@@ -0,0 +1,195 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// DriftReport compares the database inventory with live Docker state
+type DriftReport struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	InSync      bool          `json:"in_sync"`
+	Volumes     ResourceDrift `json:"volumes"`
+	Containers  ResourceDrift `json:"containers"`
+}
+
+// ResourceDrift lists the differences found for one resource type
+type ResourceDrift struct {
+	DockerCount     int             `json:"docker_count"`
+	DatabaseCount   int             `json:"database_count"`
+	MissingInDB     []string        `json:"missing_in_database"`
+	MissingInDocker []string        `json:"missing_in_docker"`
+	Mismatches      []FieldMismatch `json:"mismatches"`
+}
+
+// FieldMismatch is a field whose database value differs from Docker
+type FieldMismatch struct {
+	ID       string `json:"id"`
+	Field    string `json:"field"`
+	Database string `json:"database"`
+	Docker   string `json:"docker"`
+}
+
+// InSync reports whether no drift was found
+func (d *ResourceDrift) InSync() bool {
+	return len(d.MissingInDB) == 0 && len(d.MissingInDocker) == 0 && len(d.Mismatches) == 0
+}
+
+// DetectDrift compares database volumes and containers against live Docker state.
+// Unlike reconciliation it only reports differences and never writes.
+func (r *ReconcilerService) DetectDrift(ctx context.Context) (*DriftReport, error) {
+	volumes, err := r.detectVolumeDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := r.detectContainerDrift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DriftReport{
+		GeneratedAt: time.Now().UTC(),
+		InSync:      volumes.InSync() && containers.InSync(),
+		Volumes:     *volumes,
+		Containers:  *containers,
+	}, nil
+}
+
+// detectVolumeDrift compares active database volumes with Docker volumes
+func (r *ReconcilerService) detectVolumeDrift(ctx context.Context) (*ResourceDrift, error) {
+	dockerVolumes, err := r.dockerClient.ListVolumes(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker volumes: %w", err)
+	}
+
+	dbVolumes, err := r.repository.ListAllVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list database volumes: %w", err)
+	}
+
+	drift := newResourceDrift(len(dockerVolumes.Volumes), len(dbVolumes))
+
+	dbVolumeMap := make(map[string]*database.Volume, len(dbVolumes))
+	for _, vol := range dbVolumes {
+		dbVolumeMap[vol.VolumeID] = vol
+	}
+
+	dockerVolumeMap := make(map[string]bool, len(dockerVolumes.Volumes))
+	for _, dockerVol := range dockerVolumes.Volumes {
+		dockerVolumeMap[dockerVol.Name] = true
+
+		dbVol, exists := dbVolumeMap[dockerVol.Name]
+		if !exists {
+			drift.MissingInDB = append(drift.MissingInDB, dockerVol.Name)
+			continue
+		}
+
+		// Same fields the reconciler would rewrite
+		drift.compare(dockerVol.Name, "driver", dbVol.Driver, dockerVol.Driver)
+		drift.compare(dockerVol.Name, "mountpoint", dbVol.Mountpoint, dockerVol.Mountpoint)
+		drift.compare(dockerVol.Name, "scope", dbVol.Scope, dockerVol.Scope)
+	}
+
+	for volumeID := range dbVolumeMap {
+		if !dockerVolumeMap[volumeID] {
+			drift.MissingInDocker = append(drift.MissingInDocker, volumeID)
+		}
+	}
+
+	drift.sort()
+	return drift, nil
+}
+
+// detectContainerDrift compares active database containers with Docker containers.
+// Only running containers are kept active in the database, so a stopped Docker
+// container without a database row is expected and not reported.
+func (r *ReconcilerService) detectContainerDrift(ctx context.Context) (*ResourceDrift, error) {
+	dockerContainers, err := r.dockerClient.ListContainers(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker containers: %w", err)
+	}
+
+	dbContainers, err := r.repository.ListAllContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list database containers: %w", err)
+	}
+
+	drift := newResourceDrift(len(dockerContainers), len(dbContainers))
+
+	dbContainerMap := make(map[string]*database.Container, len(dbContainers))
+	for _, container := range dbContainers {
+		dbContainerMap[container.ContainerID] = container
+	}
+
+	dockerContainerMap := make(map[string]bool, len(dockerContainers))
+	for _, dockerContainer := range dockerContainers {
+		dockerContainerMap[dockerContainer.ID] = true
+		state := r.mapContainerState(dockerContainer.State)
+
+		dbContainer, exists := dbContainerMap[dockerContainer.ID]
+		if !exists {
+			if state == "running" {
+				drift.MissingInDB = append(drift.MissingInDB, dockerContainer.ID)
+			}
+			continue
+		}
+
+		name := ""
+		if len(dockerContainer.Names) > 0 {
+			name = strings.TrimPrefix(dockerContainer.Names[0], "/")
+		}
+		drift.compare(dockerContainer.ID, "name", strings.TrimPrefix(dbContainer.Name, "/"), name)
+		drift.compare(dockerContainer.ID, "image", dbContainer.Image, dockerContainer.Image)
+		drift.compare(dockerContainer.ID, "state", dbContainer.State, state)
+	}
+
+	for containerID := range dbContainerMap {
+		if !dockerContainerMap[containerID] {
+			drift.MissingInDocker = append(drift.MissingInDocker, containerID)
+		}
+	}
+
+	drift.sort()
+	return drift, nil
+}
+
+// newResourceDrift creates an empty drift result so lists encode as [] rather than null
+func newResourceDrift(dockerCount, dbCount int) *ResourceDrift {
+	return &ResourceDrift{
+		DockerCount:     dockerCount,
+		DatabaseCount:   dbCount,
+		MissingInDB:     []string{},
+		MissingInDocker: []string{},
+		Mismatches:      []FieldMismatch{},
+	}
+}
+
+// compare records a mismatch when the database and Docker values differ
+func (d *ResourceDrift) compare(id, field, dbValue, dockerValue string) {
+	if dbValue != dockerValue {
+		d.Mismatches = append(d.Mismatches, FieldMismatch{
+			ID:       id,
+			Field:    field,
+			Database: dbValue,
+			Docker:   dockerValue,
+		})
+	}
+}
+
+// sort orders the results so reports are stable between calls
+func (d *ResourceDrift) sort() {
+	sort.Strings(d.MissingInDB)
+	sort.Strings(d.MissingInDocker)
+	sort.Slice(d.Mismatches, func(i, j int) bool {
+		if d.Mismatches[i].ID != d.Mismatches[j].ID {
+			return d.Mismatches[i].ID < d.Mismatches[j].ID
+		}
+		return d.Mismatches[i].Field < d.Mismatches[j].Field
+	})
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mantonx/volumeviz/internal/config"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// driftDockerClient serves fixed volume and container listings
+type driftDockerClient struct {
+	MockDockerClient
+	volumes    []*volume.Volume
+	containers []containertypes.Summary
+	listErr    error
+}
+
+func (c *driftDockerClient) ListVolumes(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
+	return volume.ListResponse{Volumes: c.volumes}, c.listErr
+}
+
+func (c *driftDockerClient) ListContainers(ctx context.Context, filterMap map[string][]string) ([]containertypes.Summary, error) {
+	return c.containers, nil
+}
+
+func TestDetectDrift(t *testing.T) {
+	dockerClient := &driftDockerClient{
+		volumes: []*volume.Volume{
+			{Name: "in-sync", Driver: "local", Mountpoint: "/var/lib/docker/volumes/in-sync/_data", Scope: "local"},
+			{Name: "changed-driver", Driver: "nfs", Mountpoint: "/mnt/changed", Scope: "local"},
+			{Name: "docker-only", Driver: "local", Scope: "local"},
+		},
+		containers: []containertypes.Summary{
+			{ID: "c-sync", Names: []string{"/web"}, Image: "nginx", State: "running"},
+			{ID: "c-stopped", Names: []string{"/db"}, Image: "postgres", State: "exited"},
+			{ID: "c-new", Names: []string{"/worker"}, Image: "worker", State: "running"},
+			{ID: "c-exited-untracked", Names: []string{"/job"}, Image: "job", State: "exited"},
+		},
+	}
+
+	mockRepo := &MockRepository{}
+	mockRepo.On("ListAllVolumes", mock.Anything).Return([]*database.Volume{
+		{VolumeID: "in-sync", Driver: "local", Mountpoint: "/var/lib/docker/volumes/in-sync/_data", Scope: "local"},
+		{VolumeID: "changed-driver", Driver: "local", Mountpoint: "/mnt/changed", Scope: "local"},
+		{VolumeID: "db-only", Driver: "local", Scope: "local"},
+	}, nil)
+	mockRepo.On("ListAllContainers", mock.Anything).Return([]*database.Container{
+		{ContainerID: "c-sync", Name: "/web", Image: "nginx", State: "running"},
+		{ContainerID: "c-stopped", Name: "/db", Image: "postgres", State: "running"},
+		{ContainerID: "c-gone", Name: "/old", Image: "old", State: "running"},
+	}, nil)
+
+	reconciler := NewReconcilerService(dockerClient, mockRepo, &config.EventsConfig{}, nil)
+
+	report, err := reconciler.DetectDrift(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, report.InSync)
+
+	assert.Equal(t, 3, report.Volumes.DockerCount)
+	assert.Equal(t, 3, report.Volumes.DatabaseCount)
+	assert.Equal(t, []string{"docker-only"}, report.Volumes.MissingInDB)
+	assert.Equal(t, []string{"db-only"}, report.Volumes.MissingInDocker)
+	assert.Equal(t, []FieldMismatch{
+		{ID: "changed-driver", Field: "driver", Database: "local", Docker: "nfs"},
+	}, report.Volumes.Mismatches)
+
+	// Stopped containers are not tracked as active, so only running ones count as missing
+	assert.Equal(t, []string{"c-new"}, report.Containers.MissingInDB)
+	assert.Equal(t, []string{"c-gone"}, report.Containers.MissingInDocker)
+	assert.Equal(t, []FieldMismatch{
+		{ID: "c-stopped", Field: "state", Database: "running", Docker: "exited"},
+	}, report.Containers.Mismatches)
+
+	// Read-only: nothing is written back
+	mockRepo.AssertNotCalled(t, "UpsertVolume", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "BulkUpsertVolumes", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "DeleteVolume", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpsertContainer", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "DeleteContainer", mock.Anything, mock.Anything)
+}
+
+func TestDetectDrift_InSync(t *testing.T) {
+	dockerClient := &driftDockerClient{
+		volumes:    []*volume.Volume{{Name: "data", Driver: "local", Scope: "local"}},
+		containers: []containertypes.Summary{{ID: "c1", Names: []string{"/app"}, Image: "app", State: "running"}},
+	}
+
+	mockRepo := &MockRepository{}
+	mockRepo.On("ListAllVolumes", mock.Anything).Return([]*database.Volume{
+		{VolumeID: "data", Driver: "local", Scope: "local"},
+	}, nil)
+	mockRepo.On("ListAllContainers", mock.Anything).Return([]*database.Container{
+		{ContainerID: "c1", Name: "/app", Image: "app", State: "running"},
+	}, nil)
+
+	reconciler := NewReconcilerService(dockerClient, mockRepo, &config.EventsConfig{}, nil)
+
+	report, err := reconciler.DetectDrift(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.InSync)
+	assert.Empty(t, report.Volumes.MissingInDB)
+	assert.Empty(t, report.Volumes.Mismatches)
+	assert.Empty(t, report.Containers.MissingInDocker)
+	assert.NotNil(t, report.Containers.Mismatches)
+}
+
+func TestDetectDrift_DockerError(t *testing.T) {
+	dockerClient := &driftDockerClient{listErr: errors.New("daemon unreachable")}
+	reconciler := NewReconcilerService(dockerClient, &MockRepository{}, &config.EventsConfig{}, nil)
+
+	_, err := reconciler.DetectDrift(context.Background())
+	assert.ErrorContains(t, err, "daemon unreachable")
+}
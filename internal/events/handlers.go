@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types"
 	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/mantonx/volumeviz/internal/interfaces"
@@ -16,6 +18,9 @@ type EventHandlerService struct {
 	dockerClient interfaces.DockerClient
 	repository   Repository
 	promMetrics  *EventMetricsCollector
+	guard        *eventGuard
+	scanTrigger  *scanTrigger // Optional, scans newly created volumes
+	removals     volumeRemovalListeners
 }
 
 // NewEventHandlerService creates a new event handler service
@@ -24,11 +29,47 @@ func NewEventHandlerService(dockerClient interfaces.DockerClient, repository Rep
 		dockerClient: dockerClient,
 		repository:   repository,
 		promMetrics:  promMetrics,
+		guard:        newEventGuard(eventGuardTTL),
 	}
 }
 
-// ProcessEvent routes events to appropriate handlers
+// SetScanOnCreate enqueues a scan of every newly created volume once it has
+// existed for delay, so new volumes get a baseline size before the next
+// scheduled pass. Call before processing events.
+func (h *EventHandlerService) SetScanOnCreate(enqueuer ScanEnqueuer, delay time.Duration) {
+	h.scanTrigger = newScanTrigger(enqueuer, delay)
+}
+
+// AddVolumeRemovalListener tells listener about every volume a destroy event
+// removes. Call before processing events.
+func (h *EventHandlerService) AddVolumeRemovalListener(listener VolumeRemovalListener) {
+	h.removals = append(h.removals, listener)
+}
+
+// ProcessEvent routes events to appropriate handlers. Events that are older than,
+// or identical to, the last event applied to the same resource are ignored so
+// replays do not re-apply state.
 func (h *EventHandlerService) ProcessEvent(ctx context.Context, event *DockerEvent) error {
+	prev, hadPrev, skipReason := h.guard.claim(event)
+	if skipReason != "" {
+		log.Printf("[DEBUG] Skipping %s event: %s %s (%s) at %s, last applied %s at %s",
+			skipReason, event.Action, event.ID, event.Name, event.Time.Format(time.RFC3339Nano),
+			prev.eventType, prev.time.Format(time.RFC3339Nano))
+		if h.promMetrics != nil {
+			h.promMetrics.RecordEventSkipped(skipReason, event.Type)
+		}
+		return nil
+	}
+
+	if err := h.routeEvent(ctx, event); err != nil {
+		h.guard.release(event, prev, hadPrev)
+		return err
+	}
+	return nil
+}
+
+// routeEvent dispatches an event to the handler for its type
+func (h *EventHandlerService) routeEvent(ctx context.Context, event *DockerEvent) error {
 	log.Printf("[DEBUG] Processing event: %s %s (%s)", event.Action, event.ID, event.Name)
 
 	switch event.Type {
@@ -44,6 +85,8 @@ func (h *EventHandlerService) ProcessEvent(ctx context.Context, event *DockerEve
 		return h.HandleContainerDie(ctx, event)
 	case ContainerDestroyed:
 		return h.HandleContainerDestroy(ctx, event)
+	case ContainerRenamed:
+		return h.HandleContainerRename(ctx, event)
 	default:
 		return fmt.Errorf("unknown event type: %s", event.Type)
 	}
@@ -53,6 +96,11 @@ func (h *EventHandlerService) ProcessEvent(ctx context.Context, event *DockerEve
 func (h *EventHandlerService) HandleVolumeCreate(ctx context.Context, event *DockerEvent) error {
 	// Get volume details from Docker API
 	volumeResp, err := h.dockerClient.InspectVolume(ctx, event.Name)
+	if err != nil && cerrdefs.IsNotFound(err) {
+		// Removed again before it could be inspected; its destroy event follows
+		log.Printf("[INFO] Volume %s removed before its create event was handled", event.Name)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to inspect volume %s: %w", event.Name, err)
 	}
@@ -91,6 +139,10 @@ func (h *EventHandlerService) HandleVolumeCreate(ctx context.Context, event *Doc
 	}
 	
 	log.Printf("[INFO] Volume created: %s (driver: %s)", event.Name, volume.Driver)
+
+	if h.scanTrigger != nil {
+		h.scanTrigger.schedule(event.Name)
+	}
 	return nil
 }
 
@@ -105,6 +157,11 @@ func (h *EventHandlerService) HandleVolumeRemove(ctx context.Context, event *Doc
 		h.promMetrics.RecordResourceRemoved("volume", "event")
 	}
 
+	if h.scanTrigger != nil {
+		h.scanTrigger.cancel(event.Name)
+	}
+	h.removals.notify(event.Name)
+
 	log.Printf("[INFO] Volume removed: %s", event.Name)
 	return nil
 }
@@ -140,10 +197,45 @@ func (h *EventHandlerService) HandleContainerDestroy(ctx context.Context, event
 	return nil
 }
 
+// HandleContainerRename handles container rename events. The container keeps
+// its ID, state and mounts, so only the stored name changes; a container not
+// stored yet gets its current name when it is next started or reconciled.
+func (h *EventHandlerService) HandleContainerRename(ctx context.Context, event *DockerEvent) error {
+	newName, ok := event.Attributes["name"]
+	if !ok || newName == "" {
+		return fmt.Errorf("rename event for container %s has no new name", event.ID)
+	}
+
+	container, err := h.repository.GetContainerByID(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load container %s: %w", event.ID, err)
+	}
+	if container == nil {
+		log.Printf("[DEBUG] Renamed container %s is not stored yet", event.ID)
+		return nil
+	}
+
+	// Stored names keep the leading slash of Docker's inspect output
+	oldName := container.Name
+	container.Name = "/" + strings.TrimPrefix(newName, "/")
+	container.UpdatedAt = event.Time
+	if err := h.repository.UpsertContainer(ctx, container); err != nil {
+		return fmt.Errorf("failed to rename container %s: %w", event.ID, err)
+	}
+
+	log.Printf("[INFO] Container renamed: %s (%s -> %s)", event.ID, oldName, container.Name)
+	return nil
+}
+
 // updateContainerAndMounts updates container state and its volume mounts
 func (h *EventHandlerService) updateContainerAndMounts(ctx context.Context, event *DockerEvent, state string) error {
 	// Get container details from Docker API
 	containerJSON, err := h.dockerClient.ContainerInspect(ctx, event.ID)
+	if err != nil && cerrdefs.IsNotFound(err) {
+		// Removed before it could be inspected; its destroy event follows
+		log.Printf("[INFO] Container %s removed before its %s event was handled", event.ID, state)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to inspect container %s: %w", event.ID, err)
 	}
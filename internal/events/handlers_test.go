@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
 	"github.com/mantonx/volumeviz/internal/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -198,6 +200,27 @@ func TestHandleVolumeCreateError(t *testing.T) {
 	mockDocker.AssertExpectations(t)
 }
 
+func TestHandleEventsForRemovedResources(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockDocker := &MockDockerClient{}
+	
+	handler := NewEventHandlerService(mockDocker, mockRepo, nil)
+	
+	ctx := context.Background()
+	removed := errdefs.NotFound(errors.New("Error response from daemon: no such object"))
+	mockDocker.On("InspectVolume", ctx, "brief-volume").Return(volume.Volume{}, removed)
+	mockDocker.On("ContainerInspect", ctx, "container_abc").Return(types.ContainerJSON{}, removed)
+	
+	// Removed again before being inspected: nothing to store, and not an
+	// error to retry, since the destroy events follow
+	assert.NoError(t, handler.HandleVolumeCreate(ctx, &DockerEvent{Type: VolumeCreated, Name: "brief-volume", Time: time.Now()}))
+	assert.NoError(t, handler.HandleContainerStart(ctx, &DockerEvent{Type: ContainerStarted, ID: "container_abc", Time: time.Now()}))
+	
+	mockDocker.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpsertVolume", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpsertContainer", mock.Anything, mock.Anything)
+}
+
 func TestHandleVolumeRemove(t *testing.T) {
 	mockRepo := &MockRepository{}
 	mockDocker := &MockDockerClient{}
@@ -214,11 +237,82 @@ func TestHandleVolumeRemove(t *testing.T) {
 	}
 	
 	mockRepo.On("DeleteVolume", ctx, "test-volume").Return(nil)
+	removals := &recordingRemovals{}
+	handler.AddVolumeRemovalListener(removals)
 	
 	err := handler.HandleVolumeRemove(ctx, event)
 	
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
+	assert.Equal(t, []string{"test-volume"}, removals.volumes)
+}
+
+// recordingRemovals records the volumes a VolumeRemovalListener is told about
+type recordingRemovals struct {
+	volumes []string
+}
+
+func (r *recordingRemovals) VolumeRemoved(volumeName string) {
+	r.volumes = append(r.volumes, volumeName)
+}
+
+// recordingEnqueuer records the volumes passed to EnqueueEventScan
+type recordingEnqueuer struct {
+	mu      sync.Mutex
+	volumes []string
+}
+
+func (e *recordingEnqueuer) EnqueueEventScan(volumeName string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.volumes = append(e.volumes, volumeName)
+	return "scan-" + volumeName, nil
+}
+
+func (e *recordingEnqueuer) enqueued() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.volumes...)
+}
+
+func TestVolumeCreateEnqueuesScan(t *testing.T) {
+	const delay = 20 * time.Millisecond
+
+	mockRepo := &MockRepository{}
+	mockDocker := &MockDockerClient{}
+	mockDocker.On("InspectVolume", mock.Anything, mock.Anything).Return(volume.Volume{Driver: "local"}, nil)
+	mockRepo.On("UpsertVolume", mock.Anything, mock.AnythingOfType("*database.Volume")).Return(nil)
+	mockRepo.On("DeleteVolume", mock.Anything, mock.Anything).Return(nil)
+
+	enqueuer := &recordingEnqueuer{}
+	handler := NewEventHandlerService(mockDocker, mockRepo, nil)
+	handler.SetScanOnCreate(enqueuer, delay)
+
+	ctx := context.Background()
+	now := time.Now()
+	created := func(name string, at time.Time) *DockerEvent {
+		return &DockerEvent{Type: VolumeCreated, ID: name, Name: name, Action: "create", Time: at}
+	}
+
+	// A create event, its replay and a second create within the delay scan once
+	assert.NoError(t, handler.ProcessEvent(ctx, created("new-volume", now)))
+	assert.NoError(t, handler.ProcessEvent(ctx, created("new-volume", now)))
+	assert.NoError(t, handler.ProcessEvent(ctx, created("new-volume", now.Add(time.Millisecond))))
+
+	// A volume removed within the delay is not scanned
+	assert.NoError(t, handler.ProcessEvent(ctx, created("churn-volume", now)))
+	assert.NoError(t, handler.ProcessEvent(ctx, &DockerEvent{
+		Type: VolumeRemoved, ID: "churn-volume", Name: "churn-volume", Action: "remove", Time: now.Add(time.Millisecond),
+	}))
+
+	assert.Eventually(t, func() bool { return len(enqueuer.enqueued()) > 0 }, time.Second, delay/4)
+	time.Sleep(3 * delay)
+	assert.Equal(t, []string{"new-volume"}, enqueuer.enqueued())
+
+	// Without the option nothing is enqueued
+	handler = NewEventHandlerService(mockDocker, mockRepo, nil)
+	assert.NoError(t, handler.HandleVolumeCreate(ctx, created("other-volume", now)))
+	assert.Nil(t, handler.scanTrigger)
 }
 
 func TestHandleContainerStart(t *testing.T) {
@@ -308,6 +402,54 @@ func TestHandleContainerDestroy(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestHandleContainerRename(t *testing.T) {
+	ctx := context.Background()
+	event := &DockerEvent{
+		Type:       ContainerRenamed,
+		ID:         "container_abc",
+		Name:       "storefront",
+		Action:     "rename",
+		Time:       time.Now(),
+		Attributes: map[string]string{"name": "storefront", "oldName": "/web"},
+	}
+
+	t.Run("updates the stored name only", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		handler := NewEventHandlerService(&MockDockerClient{}, mockRepo, nil)
+
+		mockRepo.On("GetContainerByID", ctx, "container_abc").Return(&database.Container{
+			ContainerID: "container_abc", Name: "/web", Image: "nginx", State: "running", IsActive: true,
+		}, nil)
+		mockRepo.On("UpsertContainer", ctx, mock.MatchedBy(func(c *database.Container) bool {
+			return c.ContainerID == "container_abc" &&
+				c.Name == "/storefront" &&
+				c.State == "running" &&
+				c.IsActive &&
+				c.UpdatedAt.Equal(event.Time)
+		})).Return(nil)
+
+		assert.NoError(t, handler.ProcessEvent(ctx, event))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("container not stored yet", func(t *testing.T) {
+		mockRepo := &MockRepository{}
+		handler := NewEventHandlerService(&MockDockerClient{}, mockRepo, nil)
+		mockRepo.On("GetContainerByID", ctx, "container_abc").Return((*database.Container)(nil), nil)
+
+		assert.NoError(t, handler.HandleContainerRename(ctx, event))
+		mockRepo.AssertNotCalled(t, "UpsertContainer", mock.Anything, mock.Anything)
+	})
+
+	t.Run("event without a new name", func(t *testing.T) {
+		handler := NewEventHandlerService(&MockDockerClient{}, &MockRepository{}, nil)
+		unnamed := *event
+		unnamed.Attributes = map[string]string{}
+
+		assert.Error(t, handler.HandleContainerRename(ctx, &unnamed))
+	})
+}
+
 func TestUpdateVolumeMounts(t *testing.T) {
 	mockRepo := &MockRepository{}
 	mockDocker := &MockDockerClient{}
@@ -359,6 +501,142 @@ func TestUpdateVolumeMounts(t *testing.T) {
 	mockRepo.AssertNumberOfCalls(t, "UpsertVolumeMount", 2)
 }
 
+func runningContainerJSON(id string) types.ContainerJSON {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:   id,
+			Name: "/test-container",
+			State: &types.ContainerState{
+				Status:    "running",
+				StartedAt: "2023-01-01T00:00:00Z",
+			},
+		},
+		Config: &containertypes.Config{
+			Image:  "nginx:latest",
+			Labels: map[string]string{},
+		},
+		Mounts: []types.MountPoint{
+			{Type: "volume", Name: "test-vol", Destination: "/data", RW: true},
+		},
+	}
+}
+
+func TestProcessEventIgnoresDuplicateAndStaleEvents(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockDocker := &MockDockerClient{}
+
+	handler := NewEventHandlerService(mockDocker, mockRepo, nil)
+
+	ctx := context.Background()
+	startedAt := time.Now()
+
+	// Track mount activity transitions to detect is_active flapping
+	var transitions []string
+	mockDocker.On("ContainerInspect", mock.Anything, "container_abc").Return(runningContainerJSON("container_abc"), nil)
+	mockRepo.On("UpsertContainer", mock.Anything, mock.AnythingOfType("*database.Container")).Return(nil)
+	mockRepo.On("DeactivateVolumeMounts", mock.Anything, "container_abc").
+		Run(func(mock.Arguments) { transitions = append(transitions, "deactivate") }).
+		Return(nil)
+	mockRepo.On("UpsertVolumeMount", mock.Anything, mock.AnythingOfType("*database.VolumeMount")).
+		Run(func(mock.Arguments) { transitions = append(transitions, "activate") }).
+		Return(nil)
+
+	start := &DockerEvent{
+		Type:   ContainerStarted,
+		ID:     "container_abc",
+		Name:   "test-container",
+		Action: "start",
+		Time:   startedAt,
+	}
+	assert.NoError(t, handler.ProcessEvent(ctx, start))
+
+	// Replay of the same event, e.g. after a stream reconnect
+	replay := *start
+	assert.NoError(t, handler.ProcessEvent(ctx, &replay))
+
+	// An older stop delivered out of order must not roll the container back
+	staleStop := &DockerEvent{
+		Type:   ContainerStopped,
+		ID:     "container_abc",
+		Name:   "test-container",
+		Action: "stop",
+		Time:   startedAt.Add(-time.Second),
+	}
+	assert.NoError(t, handler.ProcessEvent(ctx, staleStop))
+
+	assert.Equal(t, []string{"deactivate", "activate"}, transitions)
+	mockDocker.AssertNumberOfCalls(t, "ContainerInspect", 1)
+	mockRepo.AssertNumberOfCalls(t, "UpsertContainer", 1)
+}
+
+func TestProcessEventAppliesNewerEvents(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockDocker := &MockDockerClient{}
+
+	handler := NewEventHandlerService(mockDocker, mockRepo, nil)
+
+	ctx := context.Background()
+	createdAt := time.Now()
+
+	mockRepo.On("DeleteVolume", mock.Anything, "test-volume").Return(nil)
+
+	remove := &DockerEvent{
+		Type:   VolumeRemoved,
+		ID:     "test-volume",
+		Name:   "test-volume",
+		Action: "remove",
+		Time:   createdAt.Add(time.Second),
+	}
+	assert.NoError(t, handler.ProcessEvent(ctx, remove))
+
+	// A replayed create from before the removal must not resurrect the volume
+	staleCreate := &DockerEvent{
+		Type:   VolumeCreated,
+		ID:     "test-volume",
+		Name:   "test-volume",
+		Action: "create",
+		Time:   createdAt,
+	}
+	assert.NoError(t, handler.ProcessEvent(ctx, staleCreate))
+	mockDocker.AssertNotCalled(t, "InspectVolume", mock.Anything, mock.Anything)
+
+	// A genuinely newer create is applied
+	mockDocker.On("InspectVolume", mock.Anything, "test-volume").Return(volume.Volume{Name: "test-volume", Driver: "local"}, nil)
+	mockRepo.On("UpsertVolume", mock.Anything, mock.AnythingOfType("*database.Volume")).Return(nil)
+
+	recreate := *staleCreate
+	recreate.Time = createdAt.Add(2 * time.Second)
+	assert.NoError(t, handler.ProcessEvent(ctx, &recreate))
+
+	mockRepo.AssertExpectations(t)
+	mockDocker.AssertExpectations(t)
+}
+
+func TestProcessEventRetriesFailedEvent(t *testing.T) {
+	mockRepo := &MockRepository{}
+	mockDocker := &MockDockerClient{}
+
+	handler := NewEventHandlerService(mockDocker, mockRepo, nil)
+
+	ctx := context.Background()
+	event := &DockerEvent{
+		Type:   VolumeRemoved,
+		ID:     "test-volume",
+		Name:   "test-volume",
+		Action: "remove",
+		Time:   time.Now(),
+	}
+
+	mockRepo.On("DeleteVolume", mock.Anything, "test-volume").Return(errors.New("database locked")).Once()
+	mockRepo.On("DeleteVolume", mock.Anything, "test-volume").Return(nil).Once()
+
+	assert.Error(t, handler.ProcessEvent(ctx, event))
+
+	// The failed attempt must not mark the event as applied
+	assert.NoError(t, handler.ProcessEvent(ctx, event))
+	mockRepo.AssertNumberOfCalls(t, "DeleteVolume", 2)
+}
+
 // Mock implementations
 type MockRepository struct {
 	mock.Mock
@@ -419,6 +697,14 @@ func (m *MockRepository) DeactivateVolumeMounts(ctx context.Context, containerID
 	return args.Error(0)
 }
 
+func (m *MockRepository) BulkUpsertVolumes(ctx context.Context, volumes []*database.Volume) (*database.BulkUpsertResult, error) {
+	args := m.Called(ctx, volumes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*database.BulkUpsertResult), args.Error(1)
+}
+
 func (m *MockRepository) ListAllVolumes(ctx context.Context) ([]*database.Volume, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*database.Volume), args.Error(1)
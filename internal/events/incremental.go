@@ -0,0 +1,198 @@
+package events
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// reconcileSnapshot records the Docker listings a reconciliation left the
+// database matching. Each resource keeps a fingerprint of its listed fields,
+// and the generation hashes every fingerprint so an unchanged daemon is
+// recognised without walking either map.
+type reconcileSnapshot struct {
+	generation string
+	volumes    map[string]string // volume name -> fingerprint
+	containers map[string]string // container ID -> fingerprint
+}
+
+// newReconcileSnapshot fingerprints the listed volumes and containers
+func newReconcileSnapshot(volumes []*volume.Volume, containers []types.Container) *reconcileSnapshot {
+	snapshot := &reconcileSnapshot{
+		volumes:    make(map[string]string, len(volumes)),
+		containers: make(map[string]string, len(containers)),
+	}
+	for _, vol := range volumes {
+		snapshot.volumes[vol.Name] = volumeFingerprint(vol)
+	}
+	for _, container := range containers {
+		snapshot.containers[container.ID] = containerFingerprint(container)
+	}
+
+	hash := sha256.New()
+	for _, entries := range []map[string]string{snapshot.volumes, snapshot.containers} {
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(hash, "%s=%s\n", key, entries[key])
+		}
+		hash.Write([]byte{0})
+	}
+	snapshot.generation = hex.EncodeToString(hash.Sum(nil))
+	return snapshot
+}
+
+// volumeFingerprint covers the fields reconciliation stores for a volume
+func volumeFingerprint(vol *volume.Volume) string {
+	return strings.Join([]string{vol.Driver, vol.Mountpoint, vol.Scope}, "|")
+}
+
+// containerFingerprint covers a container's state, image and volume mounts.
+// Status is left out: its uptime text changes on every listing.
+func containerFingerprint(container types.Container) string {
+	mounts := make([]string, 0, len(container.Mounts))
+	for _, mount := range container.Mounts {
+		if mount.Type == "volume" {
+			mounts = append(mounts, fmt.Sprintf("%s:%s:%t", mount.Name, mount.Destination, mount.RW))
+		}
+	}
+	sort.Strings(mounts)
+	return strings.Join([]string{container.State, container.Image, strings.Join(mounts, ",")}, "|")
+}
+
+// setSnapshot replaces the incremental baseline; nil drops it
+func (r *ReconcilerService) setSnapshot(snapshot *reconcileSnapshot) {
+	r.snapshotMu.Lock()
+	defer r.snapshotMu.Unlock()
+	r.snapshot = snapshot
+}
+
+// IncrementalReconcile lists Docker volumes and containers and only revisits
+// those added, removed or changed since the last clean reconciliation. When
+// the listings hash to the stored generation nothing else is read or
+// written. Without a baseline, after a pass that left changes unapplied, or
+// with ReconcileDryRun set it runs a full reconciliation instead.
+func (r *ReconcilerService) IncrementalReconcile(ctx context.Context) error {
+	r.snapshotMu.Lock()
+	previous := r.snapshot
+	r.snapshotMu.Unlock()
+
+	if previous == nil || r.config.ReconcileDryRun {
+		log.Printf("[INFO] No incremental reconciliation baseline, running full reconciliation")
+		return r.FullReconcile(ctx)
+	}
+
+	start := time.Now()
+	defer func() {
+		r.recordRun("incremental", false, time.Since(start))
+	}()
+
+	dockerVolumes, err := r.dockerClient.ListVolumes(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list Docker volumes: %w", err)
+	}
+	dockerContainers, err := r.dockerClient.ListContainers(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list Docker containers: %w", err)
+	}
+
+	current := newReconcileSnapshot(dockerVolumes.Volumes, dockerContainers)
+	if current.generation == previous.generation {
+		log.Printf("[DEBUG] Incremental reconciliation: Docker state unchanged (generation %.12s)", current.generation)
+		return nil
+	}
+
+	report := newReconcileReport(false)
+	r.reconcileChangedVolumes(ctx, dockerVolumes.Volumes, previous, current, report)
+	r.reconcileChangedContainers(ctx, dockerContainers, previous, current, report)
+
+	if report.failures == 0 {
+		if len(report.vanished) > 0 {
+			current = newReconcileSnapshot(dockerVolumes.Volumes, report.listedContainers(dockerContainers))
+		}
+		r.setSnapshot(current)
+	} else {
+		// Leave the next pass to a full reconciliation rather than trusting
+		// a baseline the database does not match
+		r.setSnapshot(nil)
+	}
+
+	log.Printf("[INFO] Incremental reconciliation completed in %v: volumes %s, containers %s, mounts %s",
+		time.Since(start), report.Volumes.summary(), report.Containers.summary(), report.Mounts.summary())
+	return nil
+}
+
+// reconcileChangedVolumes writes the volumes whose fingerprint differs from
+// the previous snapshot and removes those no longer listed
+func (r *ReconcilerService) reconcileChangedVolumes(ctx context.Context, dockerVolumes []*volume.Volume, previous, current *reconcileSnapshot, report *ReconcileReport) {
+	var pending []*database.Volume
+	newVolumes := make(map[string]bool)
+	for _, dockerVol := range dockerVolumes {
+		if fingerprint, seen := previous.volumes[dockerVol.Name]; seen && fingerprint == current.volumes[dockerVol.Name] {
+			continue
+		}
+
+		dbVol, err := r.repository.GetVolumeByName(ctx, dockerVol.Name)
+		if err != nil {
+			log.Printf("[WARN] Failed to load volume %s during reconciliation: %v", dockerVol.Name, err)
+			report.failures++
+			continue
+		}
+
+		vol := r.convertDockerVolumeToModel(dockerVol, time.Now())
+		if dbVol != nil {
+			if !r.shouldUpdateVolume(dbVol, dockerVol) {
+				continue
+			}
+			vol.ID = dbVol.ID               // Preserve database ID
+			vol.CreatedAt = dbVol.CreatedAt // Preserve original created time
+		} else {
+			newVolumes[dockerVol.Name] = true
+		}
+		pending = append(pending, vol)
+	}
+	r.applyVolumeUpserts(ctx, report, pending, newVolumes)
+
+	for volumeID := range previous.volumes {
+		if _, exists := current.volumes[volumeID]; !exists {
+			r.removeVolume(ctx, volumeID, report)
+		}
+	}
+}
+
+// reconcileChangedContainers inspects and syncs the containers whose
+// fingerprint differs from the previous snapshot and removes those no longer
+// listed
+func (r *ReconcilerService) reconcileChangedContainers(ctx context.Context, dockerContainers []types.Container, previous, current *reconcileSnapshot, report *ReconcileReport) {
+	for _, dockerContainer := range dockerContainers {
+		if fingerprint, seen := previous.containers[dockerContainer.ID]; seen && fingerprint == current.containers[dockerContainer.ID] {
+			continue
+		}
+
+		dbContainer, err := r.repository.GetContainerByID(ctx, dockerContainer.ID)
+		if err != nil {
+			log.Printf("[WARN] Failed to load container %s during reconciliation: %v", dockerContainer.ID, err)
+			report.failures++
+			continue
+		}
+		r.syncContainer(ctx, dockerContainer, dbContainer, report)
+	}
+
+	for containerID := range previous.containers {
+		if _, exists := current.containers[containerID]; !exists {
+			r.removeContainer(ctx, containerID, report)
+		}
+	}
+}
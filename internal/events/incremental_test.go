@@ -0,0 +1,186 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/mantonx/volumeviz/internal/config"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// baselineReconciler runs the first incremental pass, which has no baseline
+// and so reconciles reconcileFixture in full
+func baselineReconciler(t *testing.T) *ReconcilerService {
+	t.Helper()
+	dockerClient, mockRepo, _ := reconcileFixture()
+	mockRepo.On("BulkUpsertVolumes", mock.Anything, mock.Anything).Return(&database.BulkUpsertResult{}, nil)
+	mockRepo.On("DeleteVolume", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpsertContainer", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpsertVolumeMount", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("DeactivateVolumeMounts", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("DeleteContainer", mock.Anything, mock.Anything).Return(nil)
+
+	reconciler := NewReconcilerService(dockerClient, mockRepo, &config.EventsConfig{}, nil)
+
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	assert.Equal(t, int64(1), reconciler.ReconcileRuns()["full"])
+	assert.Zero(t, reconciler.ReconcileRuns()["incremental"])
+	return reconciler
+}
+
+func TestIncrementalReconcile_UnchangedDockerDoesNoWork(t *testing.T) {
+	reconciler := baselineReconciler(t)
+
+	// Fresh mocks without expectations fail on any inspect or repository call
+	baseline := reconciler.dockerClient.(*driftDockerClient)
+	reconciler.dockerClient = &driftDockerClient{volumes: baseline.volumes, containers: baseline.containers}
+	reconciler.repository = &MockRepository{}
+
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+
+	assert.Equal(t, int64(2), reconciler.ReconcileRuns()["incremental"])
+	assert.Equal(t, int64(1), reconciler.ReconcileRuns()["full"])
+}
+
+func TestIncrementalReconcile_SyncsOnlyChangedResources(t *testing.T) {
+	reconciler := baselineReconciler(t)
+
+	// "changed" moves, "new-vol" goes and "late-vol" appears; c-new stops and
+	// c-sync is removed. in-sync is untouched and must not be read.
+	dockerClient := &driftDockerClient{
+		volumes: []*volume.Volume{
+			{Name: "in-sync", Driver: "local", Scope: "local"},
+			{Name: "changed", Driver: "nfs", Mountpoint: "/mnt/changed", Scope: "local"},
+			{Name: "late-vol", Driver: "local", Scope: "local"},
+		},
+		containers: []containertypes.Summary{
+			{ID: "c-new", State: "exited", Status: "Exited (0)"},
+		},
+	}
+	stopped := runningContainerJSON("c-new")
+	stopped.State.Status = "exited"
+	dockerClient.On("ContainerInspect", mock.Anything, "c-new").Return(stopped, nil)
+	reconciler.dockerClient = dockerClient
+
+	mockRepo := &MockRepository{}
+	mockRepo.On("GetVolumeByName", mock.Anything, "changed").Return(&database.Volume{VolumeID: "changed", Driver: "nfs", Scope: "local", IsActive: true}, nil)
+	mockRepo.On("GetVolumeByName", mock.Anything, "late-vol").Return((*database.Volume)(nil), nil)
+	mockRepo.On("BulkUpsertVolumes", mock.Anything, mock.Anything).Return(&database.BulkUpsertResult{}, nil)
+	mockRepo.On("DeleteVolume", mock.Anything, "new-vol").Return(nil)
+	mockRepo.On("GetContainerByID", mock.Anything, "c-new").Return(&database.Container{ContainerID: "c-new", State: "running", Status: "running", IsActive: true}, nil)
+	mockRepo.On("UpsertContainer", mock.Anything, mock.MatchedBy(func(c *database.Container) bool {
+		return c.ContainerID == "c-new" && c.State == "exited" && !c.IsActive
+	})).Return(nil)
+	mockRepo.On("GetVolumeMountsByContainer", mock.Anything, "c-new").Return([]*database.VolumeMount{
+		{VolumeID: "test-vol", ContainerID: "c-new", MountPath: "/data", AccessMode: "rw", IsActive: true},
+	}, nil)
+	mockRepo.On("DeactivateVolumeMounts", mock.Anything, "c-sync").Return(nil)
+	mockRepo.On("DeleteContainer", mock.Anything, "c-sync").Return(nil)
+	reconciler.repository = mockRepo
+
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+
+	mockRepo.AssertExpectations(t)
+	dockerClient.AssertNumberOfCalls(t, "ContainerInspect", 1)
+	mockRepo.AssertNumberOfCalls(t, "BulkUpsertVolumes", 1)
+	var upserted []*database.Volume
+	for _, call := range mockRepo.Calls {
+		if call.Method == "BulkUpsertVolumes" {
+			upserted = call.Arguments.Get(1).([]*database.Volume)
+		}
+	}
+	require.Len(t, upserted, 2)
+	assert.Equal(t, "changed", upserted[0].VolumeID)
+	assert.Equal(t, "/mnt/changed", upserted[0].Mountpoint)
+	assert.Equal(t, "late-vol", upserted[1].VolumeID)
+	mockRepo.AssertNotCalled(t, "ListAllVolumes", mock.Anything)
+	mockRepo.AssertNotCalled(t, "ListAllContainers", mock.Anything)
+
+	// The applied changes become the new baseline
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	dockerClient.AssertNumberOfCalls(t, "ContainerInspect", 1)
+	mockRepo.AssertNumberOfCalls(t, "BulkUpsertVolumes", 1)
+	assert.Equal(t, int64(2), reconciler.ReconcileRuns()["incremental"])
+	assert.Equal(t, int64(1), reconciler.ReconcileRuns()["full"])
+}
+
+func TestIncrementalReconcile_FailedWriteForcesFullPass(t *testing.T) {
+	reconciler := baselineReconciler(t)
+
+	dockerClient := &driftDockerClient{
+		volumes:    []*volume.Volume{{Name: "in-sync", Driver: "local", Scope: "local"}, {Name: "late-vol", Driver: "local"}},
+		containers: reconciler.dockerClient.(*driftDockerClient).containers,
+	}
+	reconciler.dockerClient = dockerClient
+
+	mockRepo := &MockRepository{}
+	mockRepo.On("GetVolumeByName", mock.Anything, "late-vol").Return((*database.Volume)(nil), nil)
+	mockRepo.On("BulkUpsertVolumes", mock.Anything, mock.Anything).Return(&database.BulkUpsertResult{
+		Failed: []database.VolumeUpsertFailure{{VolumeID: "late-vol", Err: assert.AnError}},
+	}, nil)
+	mockRepo.On("DeleteVolume", mock.Anything, mock.Anything).Return(nil)
+	reconciler.repository = mockRepo
+
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	assert.Equal(t, int64(1), reconciler.ReconcileRuns()["incremental"])
+
+	// Without a trusted baseline the next pass reconciles everything
+	mockRepo.On("ListAllVolumes", mock.Anything).Return([]*database.Volume{}, nil)
+	mockRepo.On("ListAllContainers", mock.Anything).Return([]*database.Container{}, nil)
+	dockerClient.On("ContainerInspect", mock.Anything, mock.Anything).Return(runningContainerJSON("c"), nil)
+	mockRepo.On("UpsertContainer", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("GetVolumeMountsByContainer", mock.Anything, mock.Anything).Return([]*database.VolumeMount{}, nil)
+	mockRepo.On("UpsertVolumeMount", mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	assert.Equal(t, int64(2), reconciler.ReconcileRuns()["full"])
+}
+
+func TestIncrementalReconcile_ContainerRemovedBeforeInspect(t *testing.T) {
+	reconciler := baselineReconciler(t)
+	volumes := reconciler.dockerClient.(*driftDockerClient).volumes
+
+	// c-sync stops and c-brief starts, but both are removed before they are
+	// inspected
+	dockerClient := &driftDockerClient{
+		volumes: volumes,
+		containers: []containertypes.Summary{
+			{ID: "c-new", State: "running", Status: "Up"},
+			{ID: "c-sync", State: "exited", Status: "Exited (0)"},
+			{ID: "c-brief", State: "running", Status: "Up"},
+		},
+	}
+	removed := errdefs.NotFound(errors.New("Error response from daemon: No such container"))
+	dockerClient.On("ContainerInspect", mock.Anything, "c-sync").Return(types.ContainerJSON{}, removed)
+	dockerClient.On("ContainerInspect", mock.Anything, "c-brief").Return(types.ContainerJSON{}, removed)
+	reconciler.dockerClient = dockerClient
+
+	mockRepo := &MockRepository{}
+	mockRepo.On("GetContainerByID", mock.Anything, "c-sync").Return(&database.Container{ContainerID: "c-sync", State: "running", IsActive: true}, nil)
+	mockRepo.On("GetContainerByID", mock.Anything, "c-brief").Return((*database.Container)(nil), nil)
+	mockRepo.On("DeactivateVolumeMounts", mock.Anything, "c-sync").Return(nil)
+	mockRepo.On("DeleteContainer", mock.Anything, "c-sync").Return(nil)
+	reconciler.repository = mockRepo
+
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpsertContainer", mock.Anything, mock.Anything)
+
+	// Not failures, so the baseline is kept, without the removed containers:
+	// once Docker stops listing them there is nothing left to do
+	dockerClient.containers = dockerClient.containers[:1]
+	require.NoError(t, reconciler.IncrementalReconcile(context.Background()))
+	dockerClient.AssertNumberOfCalls(t, "ContainerInspect", 2)
+	mockRepo.AssertNumberOfCalls(t, "DeleteContainer", 1)
+	assert.Equal(t, int64(2), reconciler.ReconcileRuns()["incremental"])
+	assert.Equal(t, int64(1), reconciler.ReconcileRuns()["full"])
+}
@@ -66,11 +66,7 @@ func (suite *EventsIntegrationTestSuite) SetupSuite() {
 	handler := NewEventHandlerService(dockerWrapper, suite.repo, nil)
 
 	// Create reconciler
-	reconciler := NewReconcilerService(dockerWrapper, suite.repo, cfg, &EventMetrics{
-		ProcessedTotal: make(map[EventType]int64),
-		ErrorsTotal:    make(map[string]int64),
-		ReconcileRuns:  make(map[string]int64),
-	}, nil)
+	reconciler := NewReconcilerService(dockerWrapper, suite.repo, cfg, nil)
 
 	// Create events client
 	suite.eventsClient = NewEventsClient(dockerWrapper, cfg, handler, reconciler, nil)
@@ -474,6 +470,13 @@ func (r *TestRepository) DeactivateVolumeMounts(ctx context.Context, containerID
 	return nil
 }
 
+func (r *TestRepository) BulkUpsertVolumes(ctx context.Context, volumes []*database.Volume) (*database.BulkUpsertResult, error) {
+	for _, volume := range volumes {
+		r.volumes[volume.VolumeID] = volume
+	}
+	return &database.BulkUpsertResult{Upserted: len(volumes)}, nil
+}
+
 func (r *TestRepository) ListAllVolumes(ctx context.Context) ([]*database.Volume, error) {
 	var result []*database.Volume
 	for _, vol := range r.volumes {
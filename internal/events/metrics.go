@@ -11,11 +11,13 @@ type EventMetricsCollector struct {
 	eventsProcessedTotal prometheus.CounterVec
 	eventsFailedTotal    prometheus.CounterVec
 	eventsDroppedTotal   prometheus.Counter
+	eventsSkippedTotal   prometheus.CounterVec
 	eventQueueSize       prometheus.Gauge
 	
 	// Connection and streaming metrics
 	eventsConnectionStatus   prometheus.Gauge
 	eventsReconnectsTotal    prometheus.Counter
+	eventsReconnectAttempts  prometheus.Gauge
 	eventsStreamDuration     prometheus.Histogram
 	eventsLastEventTime      prometheus.Gauge
 	eventsLastReconnectTime  prometheus.Gauge
@@ -61,6 +63,14 @@ func NewEventMetricsCollector(namespace, subsystem string, labels prometheus.Lab
 			ConstLabels: labels,
 		}),
 
+		eventsSkippedTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "docker_events_skipped_total",
+			Help:        "Total number of duplicate or stale Docker events ignored",
+			ConstLabels: labels,
+		}, []string{"reason", "event_type"}),
+
 		eventQueueSize: promauto.NewGauge(prometheus.GaugeOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
@@ -86,6 +96,14 @@ func NewEventMetricsCollector(namespace, subsystem string, labels prometheus.Lab
 			ConstLabels: labels,
 		}),
 
+		eventsReconnectAttempts: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   subsystem,
+			Name:        "docker_events_reconnect_attempts",
+			Help:        "Docker events reconnection attempts since the stream last stayed up for the backoff reset period",
+			ConstLabels: labels,
+		}),
+
 		eventsStreamDuration: promauto.NewHistogram(prometheus.HistogramOpts{
 			Namespace:   namespace,
 			Subsystem:   subsystem,
@@ -194,6 +212,10 @@ func (m *EventMetricsCollector) RecordEventDropped() {
 	m.eventsDroppedTotal.Inc()
 }
 
+func (m *EventMetricsCollector) RecordEventSkipped(reason string, eventType EventType) {
+	m.eventsSkippedTotal.WithLabelValues(reason, string(eventType)).Inc()
+}
+
 func (m *EventMetricsCollector) SetEventQueueSize(size int) {
 	m.eventQueueSize.Set(float64(size))
 }
@@ -217,6 +239,10 @@ func (m *EventMetricsCollector) RecordReconnect() {
 	m.eventsLastReconnectTime.SetToCurrentTime()
 }
 
+func (m *EventMetricsCollector) SetReconnectAttempts(attempts int) {
+	m.eventsReconnectAttempts.Set(float64(attempts))
+}
+
 func (m *EventMetricsCollector) RecordStreamDuration(durationSeconds float64) {
 	m.eventsStreamDuration.Observe(durationSeconds)
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
+	"sync"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/mantonx/volumeviz/internal/config"
@@ -13,49 +16,137 @@ import (
 	"github.com/mantonx/volumeviz/internal/interfaces"
 )
 
+// ReconcileReport lists the changes a reconciliation applied or, in a dry run,
+// would apply. Volumes and containers are listed by ID, mounts as
+// container:volume. Changes that failed to apply are left out.
+type ReconcileReport struct {
+	DryRun     bool             `json:"dry_run"`
+	Volumes    ReconcileChanges `json:"volumes"`
+	Containers ReconcileChanges `json:"containers"`
+	Mounts     ReconcileChanges `json:"mounts"`
+
+	// failures counts changes that could not be applied, leaving the database
+	// behind Docker
+	failures int
+
+	// vanished lists the containers removed between being listed and
+	// inspected, which snapshots leave out
+	vanished map[string]bool
+}
+
+// ReconcileChanges lists the rows added, updated and removed for one resource type
+type ReconcileChanges struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Removed []string `json:"removed"`
+}
+
+// newReconcileReport creates an empty report so lists encode as [] rather than null
+func newReconcileReport(dryRun bool) *ReconcileReport {
+	return &ReconcileReport{
+		DryRun:     dryRun,
+		Volumes:    newReconcileChanges(),
+		Containers: newReconcileChanges(),
+		Mounts:     newReconcileChanges(),
+	}
+}
+
+func newReconcileChanges() ReconcileChanges {
+	return ReconcileChanges{Added: []string{}, Updated: []string{}, Removed: []string{}}
+}
+
+// sort orders the changes so reports are stable between runs
+func (c *ReconcileChanges) sort() {
+	sort.Strings(c.Added)
+	sort.Strings(c.Updated)
+	sort.Strings(c.Removed)
+}
+
+// summary describes the changes for the reconciliation log
+func (c *ReconcileChanges) summary() string {
+	return fmt.Sprintf("%d added, %d updated, %d removed", len(c.Added), len(c.Updated), len(c.Removed))
+}
+
 // ReconcilerService implements the Reconciler interface
 type ReconcilerService struct {
 	dockerClient interfaces.DockerClient
 	repository   Repository
 	config       *config.EventsConfig
-	metrics      *EventMetrics
 	promMetrics  *EventMetricsCollector
+
+	// runs counts finished reconciliations by kind; API and periodic runs
+	// can finish at the same time
+	runs counterMap
+
+	// snapshot is the Docker state the last clean reconciliation matched;
+	// incremental passes only revisit what changed since
+	snapshotMu sync.Mutex
+	snapshot   *reconcileSnapshot
+
+	removals volumeRemovalListeners
 }
 
 // NewReconcilerService creates a new reconciliation service
-func NewReconcilerService(dockerClient interfaces.DockerClient, repository Repository, config *config.EventsConfig, metrics *EventMetrics, promMetrics *EventMetricsCollector) *ReconcilerService {
+func NewReconcilerService(dockerClient interfaces.DockerClient, repository Repository, config *config.EventsConfig, promMetrics *EventMetricsCollector) *ReconcilerService {
 	return &ReconcilerService{
 		dockerClient: dockerClient,
 		repository:   repository,
 		config:       config,
-		metrics:      metrics,
 		promMetrics:  promMetrics,
 	}
 }
 
+// AddVolumeRemovalListener tells listener about every volume reconciliation
+// removes. Call before reconciling.
+func (r *ReconcilerService) AddVolumeRemovalListener(listener VolumeRemovalListener) {
+	r.removals = append(r.removals, listener)
+}
+
+// recordRun counts a finished reconciliation run. Dry runs change nothing and
+// are not counted.
+func (r *ReconcilerService) recordRun(kind string, dryRun bool, duration time.Duration) {
+	if dryRun {
+		return
+	}
+	r.runs.inc(kind)
+	if r.promMetrics != nil {
+		r.promMetrics.RecordReconciliationRun(kind, duration.Seconds())
+	}
+}
+
+// ReconcileRuns returns the number of finished reconciliation runs by kind
+func (r *ReconcilerService) ReconcileRuns() map[string]int64 {
+	return r.runs.snapshot()
+}
+
 // ReconcileVolumes syncs database volumes with Docker daemon state
 func (r *ReconcilerService) ReconcileVolumes(ctx context.Context) error {
-	log.Printf("[INFO] Starting volume reconciliation...")
+	_, err := r.reconcileVolumes(ctx, newReconcileReport(r.config.ReconcileDryRun))
+	return err
+}
+
+// reconcileVolumes records the volume changes needed to match Docker in
+// report, applying them unless report is a dry run. Returns the volumes
+// Docker listed.
+func (r *ReconcilerService) reconcileVolumes(ctx context.Context, report *ReconcileReport) ([]*volume.Volume, error) {
+	log.Printf("[INFO] Starting volume reconciliation (dry run: %t)...", report.DryRun)
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
-		r.metrics.ReconcileRuns["volumes"]++
-		if r.promMetrics != nil {
-			r.promMetrics.RecordReconciliationRun("volumes", duration.Seconds())
-		}
+		r.recordRun("volumes", report.DryRun, duration)
 		log.Printf("[INFO] Volume reconciliation completed in %v", duration)
 	}()
 
 	// Get current volumes from Docker
 	dockerVolumes, err := r.dockerClient.ListVolumes(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to list Docker volumes: %w", err)
+		return nil, fmt.Errorf("failed to list Docker volumes: %w", err)
 	}
 
 	// Get current volumes from database
 	dbVolumes, err := r.repository.ListAllVolumes(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list database volumes: %w", err)
+		return nil, fmt.Errorf("failed to list database volumes: %w", err)
 	}
 
 	// Create maps for efficient lookup
@@ -69,73 +160,162 @@ func (r *ReconcilerService) ReconcileVolumes(ctx context.Context) error {
 		dbVolumeMap[vol.VolumeID] = vol
 	}
 
-	// Sync volumes from Docker to database
+	// Collect volumes that need to be written, then upsert them in batches
+	var pending []*database.Volume
+	newVolumes := make(map[string]bool)
 	for _, dockerVol := range dockerVolumes.Volumes {
 		if dbVol, exists := dbVolumeMap[dockerVol.Name]; exists {
 			// Volume exists in both - check if update needed
 			if r.shouldUpdateVolume(dbVol, dockerVol) {
 				updatedVol := r.convertDockerVolumeToModel(dockerVol, time.Now())
-				updatedVol.ID = dbVol.ID // Preserve database ID
+				updatedVol.ID = dbVol.ID               // Preserve database ID
 				updatedVol.CreatedAt = dbVol.CreatedAt // Preserve original created time
-				
-				if err := r.repository.UpsertVolume(ctx, updatedVol); err != nil {
-					log.Printf("[WARN] Failed to update volume %s during reconciliation: %v", dockerVol.Name, err)
-				}
+				pending = append(pending, updatedVol)
 			}
 		} else {
 			// Volume exists in Docker but not in database - add it
-			newVol := r.convertDockerVolumeToModel(dockerVol, time.Now())
-			if err := r.repository.UpsertVolume(ctx, newVol); err != nil {
-				log.Printf("[WARN] Failed to add volume %s during reconciliation: %v", dockerVol.Name, err)
-			} else if r.promMetrics != nil {
-				r.promMetrics.RecordVolumeSync("create", "reconciliation")
-			}
+			pending = append(pending, r.convertDockerVolumeToModel(dockerVol, time.Now()))
+			newVolumes[dockerVol.Name] = true
 		}
 	}
 
+	r.applyVolumeUpserts(ctx, report, pending, newVolumes)
+
 	// Remove volumes that exist in database but not in Docker
-	removedCount := 0
 	for volumeID, dbVol := range dbVolumeMap {
 		if !dockerVolumeMap[volumeID] && dbVol.IsActive {
-			if err := r.repository.DeleteVolume(ctx, volumeID); err != nil {
-				log.Printf("[WARN] Failed to remove volume %s during reconciliation: %v", volumeID, err)
-			} else {
-				removedCount++
-				if r.promMetrics != nil {
-					r.promMetrics.RecordResourceRemoved("volume", "reconciliation")
-				}
+			r.removeVolume(ctx, volumeID, report)
+		}
+	}
+
+	log.Printf("[INFO] Volume reconciliation: %d Docker volumes, %d DB volumes, %s",
+		len(dockerVolumes.Volumes), len(dbVolumes), report.Volumes.summary())
+	return dockerVolumes.Volumes, nil
+}
+
+// applyVolumeUpserts writes pending volumes unless report is a dry run and
+// records the ones written; newVolumes marks those not yet in the database
+func (r *ReconcilerService) applyVolumeUpserts(ctx context.Context, report *ReconcileReport, pending []*database.Volume, newVolumes map[string]bool) {
+	failed := map[string]bool{}
+	if !report.DryRun {
+		failed = r.upsertVolumesInBatches(ctx, pending)
+	}
+	for _, vol := range pending {
+		if failed[vol.VolumeID] {
+			report.failures++
+			continue
+		}
+		if newVolumes[vol.VolumeID] {
+			report.Volumes.Added = append(report.Volumes.Added, vol.VolumeID)
+			if r.promMetrics != nil && !report.DryRun {
+				r.promMetrics.RecordVolumeSync("create", "reconciliation")
 			}
+		} else {
+			report.Volumes.Updated = append(report.Volumes.Updated, vol.VolumeID)
 		}
 	}
+}
 
-	log.Printf("[INFO] Volume reconciliation: %d Docker volumes, %d DB volumes, %d removed", 
-		len(dockerVolumes.Volumes), len(dbVolumes), removedCount)
-	return nil
+// removeVolume deletes a volume Docker no longer has unless report is a dry run
+func (r *ReconcilerService) removeVolume(ctx context.Context, volumeID string, report *ReconcileReport) {
+	if !report.DryRun {
+		if err := r.repository.DeleteVolume(ctx, volumeID); err != nil {
+			log.Printf("[WARN] Failed to remove volume %s during reconciliation: %v", volumeID, err)
+			report.failures++
+			return
+		}
+		if r.promMetrics != nil {
+			r.promMetrics.RecordResourceRemoved("volume", "reconciliation")
+		}
+		r.removals.notify(volumeID)
+	}
+	report.Volumes.Removed = append(report.Volumes.Removed, volumeID)
+}
+
+// upsertVolumesInBatches writes volumes in chunks of ReconcileBatchSize, running up to
+// ReconcileConcurrency chunks at once. Returns the set of volume names that failed.
+func (r *ReconcilerService) upsertVolumesInBatches(ctx context.Context, volumes []*database.Volume) map[string]bool {
+	failed := make(map[string]bool)
+	if len(volumes) == 0 {
+		return failed
+	}
+
+	batchSize := r.config.ReconcileBatchSize
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	concurrency := r.config.ReconcileConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for start := 0; start < len(volumes); start += batchSize {
+		end := start + batchSize
+		if end > len(volumes) {
+			end = len(volumes)
+		}
+		batch := volumes[start:end]
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(batch []*database.Volume) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := r.repository.BulkUpsertVolumes(ctx, batch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("[WARN] Failed to upsert batch of %d volumes during reconciliation: %v", len(batch), err)
+				for _, vol := range batch {
+					failed[vol.VolumeID] = true
+				}
+				return
+			}
+			for _, failure := range result.Failed {
+				log.Printf("[WARN] Failed to upsert volume %s during reconciliation: %v", failure.VolumeID, failure.Err)
+				failed[failure.VolumeID] = true
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	return failed
 }
 
 // ReconcileContainers syncs database containers with Docker daemon state
 func (r *ReconcilerService) ReconcileContainers(ctx context.Context) error {
-	log.Printf("[INFO] Starting container reconciliation...")
+	_, err := r.reconcileContainers(ctx, newReconcileReport(r.config.ReconcileDryRun))
+	return err
+}
+
+// reconcileContainers records the container and mount changes needed to
+// match Docker in report, applying them unless report is a dry run. Returns
+// the containers Docker listed.
+func (r *ReconcilerService) reconcileContainers(ctx context.Context, report *ReconcileReport) ([]types.Container, error) {
+	log.Printf("[INFO] Starting container reconciliation (dry run: %t)...", report.DryRun)
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
-		r.metrics.ReconcileRuns["containers"]++
-		if r.promMetrics != nil {
-			r.promMetrics.RecordReconciliationRun("containers", duration.Seconds())
-		}
+		r.recordRun("containers", report.DryRun, duration)
 		log.Printf("[INFO] Container reconciliation completed in %v", duration)
 	}()
 
 	// Get current containers from Docker (including stopped ones)
 	dockerContainers, err := r.dockerClient.ListContainers(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to list Docker containers: %w", err)
+		return nil, fmt.Errorf("failed to list Docker containers: %w", err)
 	}
 
 	// Get current containers from database
 	dbContainers, err := r.repository.ListAllContainers(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list database containers: %w", err)
+		return nil, fmt.Errorf("failed to list database containers: %w", err)
 	}
 
 	// Create maps for efficient lookup
@@ -151,87 +331,172 @@ func (r *ReconcilerService) ReconcileContainers(ctx context.Context) error {
 
 	// Sync containers and their mounts
 	for _, dockerContainer := range dockerContainers {
-		// Get detailed container information for mounts
-		containerJSON, err := r.dockerClient.ContainerInspect(ctx, dockerContainer.ID)
-		if err != nil {
-			log.Printf("[WARN] Failed to inspect container %s during reconciliation: %v", dockerContainer.ID, err)
-			continue
+		r.syncContainer(ctx, dockerContainer, dbContainerMap[dockerContainer.ID], report)
+	}
+
+	// Deactivate containers that exist in database but not in Docker
+	for containerID, dbContainer := range dbContainerMap {
+		if _, exists := dockerContainerMap[containerID]; !exists && dbContainer.IsActive {
+			r.removeContainer(ctx, containerID, report)
 		}
+	}
 
-		state := r.mapContainerState(dockerContainer.State)
-		
-		if dbContainer, exists := dbContainerMap[dockerContainer.ID]; exists {
-			// Container exists in both - check if update needed
-			if r.shouldUpdateContainer(dbContainer, dockerContainer, state) {
-				updatedContainer := r.convertDockerContainerToModel(containerJSON, state, time.Now())
-				updatedContainer.ID = dbContainer.ID // Preserve database ID
-				updatedContainer.CreatedAt = dbContainer.CreatedAt // Preserve original created time
-				
-				if err := r.repository.UpsertContainer(ctx, updatedContainer); err != nil {
-					log.Printf("[WARN] Failed to update container %s during reconciliation: %v", dockerContainer.ID, err)
-				}
+	log.Printf("[INFO] Container reconciliation: %d Docker containers, %d DB containers, %s",
+		len(dockerContainers), len(dbContainers), report.Containers.summary())
+	return dockerContainers, nil
+}
+
+// syncContainer inspects a listed container and records the container and
+// mount changes needed to match it in report. dbContainer is its database
+// row, or nil when it is not stored yet.
+func (r *ReconcilerService) syncContainer(ctx context.Context, dockerContainer types.Container, dbContainer *database.Container, report *ReconcileReport) {
+	// Get detailed container information for mounts
+	containerJSON, err := r.dockerClient.ContainerInspect(ctx, dockerContainer.ID)
+	if err != nil && cerrdefs.IsNotFound(err) {
+		r.containerVanished(ctx, dockerContainer.ID, dbContainer, report)
+		return
+	}
+	if err != nil {
+		log.Printf("[WARN] Failed to inspect container %s during reconciliation: %v", dockerContainer.ID, err)
+		report.failures++
+		return
+	}
+
+	state := r.mapContainerState(dockerContainer.State)
+
+	if dbContainer != nil {
+		// Container exists in both - check if update needed
+		if r.shouldUpdateContainer(dbContainer, dockerContainer, state) {
+			updatedContainer := r.convertDockerContainerToModel(containerJSON, state, time.Now())
+			updatedContainer.ID = dbContainer.ID               // Preserve database ID
+			updatedContainer.CreatedAt = dbContainer.CreatedAt // Preserve original created time
+
+			if err := r.upsertContainer(ctx, report.DryRun, updatedContainer); err != nil {
+				log.Printf("[WARN] Failed to update container %s during reconciliation: %v", dockerContainer.ID, err)
+				report.failures++
+			} else {
+				report.Containers.Updated = append(report.Containers.Updated, dockerContainer.ID)
 			}
+		}
+	} else {
+		// Container exists in Docker but not in database - add it
+		newContainer := r.convertDockerContainerToModel(containerJSON, state, time.Now())
+		if err := r.upsertContainer(ctx, report.DryRun, newContainer); err != nil {
+			log.Printf("[WARN] Failed to add container %s during reconciliation: %v", dockerContainer.ID, err)
+			report.failures++
 		} else {
-			// Container exists in Docker but not in database - add it
-			newContainer := r.convertDockerContainerToModel(containerJSON, state, time.Now())
-			if err := r.repository.UpsertContainer(ctx, newContainer); err != nil {
-				log.Printf("[WARN] Failed to add container %s during reconciliation: %v", dockerContainer.ID, err)
-			}
+			report.Containers.Added = append(report.Containers.Added, dockerContainer.ID)
 		}
+	}
 
-		// Reconcile volume mounts for this container
-		if err := r.reconcileContainerMounts(ctx, dockerContainer.ID, containerJSON.Mounts); err != nil {
-			log.Printf("[WARN] Failed to reconcile mounts for container %s: %v", dockerContainer.ID, err)
+	// Reconcile volume mounts for this container
+	if err := r.reconcileContainerMounts(ctx, dockerContainer.ID, containerJSON.Mounts, report); err != nil {
+		log.Printf("[WARN] Failed to reconcile mounts for container %s: %v", dockerContainer.ID, err)
+		report.failures++
+	}
+}
+
+// containerVanished handles a listed container removed before it could be
+// inspected: a stored row is removed as for an unlisted container, and the
+// removal is not a failure
+func (r *ReconcilerService) containerVanished(ctx context.Context, containerID string, dbContainer *database.Container, report *ReconcileReport) {
+	log.Printf("[INFO] Container %s removed during reconciliation", containerID)
+	if report.vanished == nil {
+		report.vanished = make(map[string]bool)
+	}
+	report.vanished[containerID] = true
+	if dbContainer != nil && dbContainer.IsActive {
+		r.removeContainer(ctx, containerID, report)
+	}
+}
+
+// listedContainers returns containers without those report found vanished
+func (report *ReconcileReport) listedContainers(containers []types.Container) []types.Container {
+	if len(report.vanished) == 0 {
+		return containers
+	}
+	listed := make([]types.Container, 0, len(containers))
+	for _, container := range containers {
+		if !report.vanished[container.ID] {
+			listed = append(listed, container)
 		}
 	}
+	return listed
+}
 
-	// Deactivate containers that exist in database but not in Docker
-	deactivatedCount := 0
-	for containerID, dbContainer := range dbContainerMap {
-		if _, exists := dockerContainerMap[containerID]; !exists && dbContainer.IsActive {
-			if err := r.repository.DeactivateVolumeMounts(ctx, containerID); err != nil {
-				log.Printf("[WARN] Failed to deactivate mounts for container %s: %v", containerID, err)
-			}
-			
-			if err := r.repository.DeleteContainer(ctx, containerID); err != nil {
-				log.Printf("[WARN] Failed to remove container %s during reconciliation: %v", containerID, err)
-			} else {
-				deactivatedCount++
-			}
+// removeContainer deletes a container Docker no longer has, deactivating its
+// mounts first, unless report is a dry run
+func (r *ReconcilerService) removeContainer(ctx context.Context, containerID string, report *ReconcileReport) {
+	if !report.DryRun {
+		if err := r.repository.DeactivateVolumeMounts(ctx, containerID); err != nil {
+			log.Printf("[WARN] Failed to deactivate mounts for container %s: %v", containerID, err)
+		}
+
+		if err := r.repository.DeleteContainer(ctx, containerID); err != nil {
+			log.Printf("[WARN] Failed to remove container %s during reconciliation: %v", containerID, err)
+			report.failures++
+			return
 		}
 	}
+	report.Containers.Removed = append(report.Containers.Removed, containerID)
+}
 
-	log.Printf("[INFO] Container reconciliation: %d Docker containers, %d DB containers, %d removed", 
-		len(dockerContainers), len(dbContainers), deactivatedCount)
-	return nil
+// upsertContainer writes a container unless this is a dry run
+func (r *ReconcilerService) upsertContainer(ctx context.Context, dryRun bool, container *database.Container) error {
+	if dryRun {
+		return nil
+	}
+	return r.repository.UpsertContainer(ctx, container)
 }
 
-// FullReconcile performs complete reconciliation of all resources
+// FullReconcile performs complete reconciliation of all resources. With
+// ReconcileDryRun set it only logs the changes it would make.
 func (r *ReconcilerService) FullReconcile(ctx context.Context) error {
-	log.Printf("[INFO] Starting full reconciliation...")
+	_, err := r.Reconcile(ctx, r.config.ReconcileDryRun)
+	return err
+}
+
+// Reconcile reconciles all resources and reports the changes made. A dry run
+// computes the same changes without writing any of them.
+func (r *ReconcilerService) Reconcile(ctx context.Context, dryRun bool) (*ReconcileReport, error) {
+	log.Printf("[INFO] Starting full reconciliation (dry run: %t)...", dryRun)
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
-		r.metrics.ReconcileRuns["full"]++
-		if r.promMetrics != nil {
-			r.promMetrics.RecordReconciliationRun("full", duration.Seconds())
-		}
+		r.recordRun("full", dryRun, duration)
 		log.Printf("[INFO] Full reconciliation completed in %v", duration)
 	}()
 
-	if err := r.ReconcileVolumes(ctx); err != nil {
-		return fmt.Errorf("volume reconciliation failed: %w", err)
+	report := newReconcileReport(dryRun)
+	volumes, err := r.reconcileVolumes(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("volume reconciliation failed: %w", err)
 	}
 
-	if err := r.ReconcileContainers(ctx); err != nil {
-		return fmt.Errorf("container reconciliation failed: %w", err)
+	containers, err := r.reconcileContainers(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("container reconciliation failed: %w", err)
 	}
 
-	return nil
+	// A clean pass becomes the baseline for incremental passes; one that
+	// left changes unapplied forces the next of them to run in full
+	if !dryRun {
+		if report.failures == 0 {
+			r.setSnapshot(newReconcileSnapshot(volumes, report.listedContainers(containers)))
+		} else {
+			r.setSnapshot(nil)
+		}
+	}
+
+	report.Volumes.sort()
+	report.Containers.sort()
+	report.Mounts.sort()
+	return report, nil
 }
 
-// reconcileContainerMounts syncs volume mounts for a specific container
-func (r *ReconcilerService) reconcileContainerMounts(ctx context.Context, containerID string, dockerMounts []types.MountPoint) error {
+// reconcileContainerMounts records the volume mount changes for a specific
+// container in report, applying them unless report is a dry run
+func (r *ReconcilerService) reconcileContainerMounts(ctx context.Context, containerID string, dockerMounts []types.MountPoint, report *ReconcileReport) error {
 	// Get current mounts from database
 	dbMounts, err := r.repository.GetVolumeMountsByContainer(ctx, containerID)
 	if err != nil {
@@ -251,23 +516,36 @@ func (r *ReconcilerService) reconcileContainerMounts(ctx context.Context, contai
 		dbMountMap[mount.VolumeID] = mount
 	}
 
+	upsert := func(mount *database.VolumeMount) error {
+		if report.DryRun {
+			return nil
+		}
+		return r.repository.UpsertVolumeMount(ctx, mount)
+	}
+
 	// Add/update mounts that exist in Docker
 	for volumeName, dockerMount := range dockerMountMap {
 		accessMode := "rw"
 		if !dockerMount.RW {
 			accessMode = "ro"
 		}
+		mountID := containerID + ":" + volumeName
 
 		if dbMount, exists := dbMountMap[volumeName]; exists {
 			// Mount exists in both - check if update needed
 			if dbMount.MountPath != dockerMount.Destination || dbMount.AccessMode != accessMode || !dbMount.IsActive {
-				dbMount.MountPath = dockerMount.Destination
-				dbMount.AccessMode = accessMode
-				dbMount.IsActive = true
-				dbMount.UpdatedAt = time.Now()
-				
-				if err := r.repository.UpsertVolumeMount(ctx, dbMount); err != nil {
+				// Update a copy so a dry run leaves the loaded row as it was
+				updated := *dbMount
+				updated.MountPath = dockerMount.Destination
+				updated.AccessMode = accessMode
+				updated.IsActive = true
+				updated.UpdatedAt = time.Now()
+
+				if err := upsert(&updated); err != nil {
 					log.Printf("[WARN] Failed to update mount %s->%s: %v", volumeName, containerID, err)
+					report.failures++
+				} else {
+					report.Mounts.Updated = append(report.Mounts.Updated, mountID)
 				}
 			}
 		} else {
@@ -283,18 +561,28 @@ func (r *ReconcilerService) reconcileContainerMounts(ctx context.Context, contai
 					UpdatedAt: time.Now(),
 				},
 			}
-			if err := r.repository.UpsertVolumeMount(ctx, newMount); err != nil {
+			if err := upsert(newMount); err != nil {
 				log.Printf("[WARN] Failed to add mount %s->%s: %v", volumeName, containerID, err)
+				report.failures++
+			} else {
+				report.Mounts.Added = append(report.Mounts.Added, mountID)
 			}
 		}
 	}
 
-	// Deactivate mounts that exist in database but not in Docker
+	// Deactivate mounts that exist in database but not in Docker; they are
+	// kept inactive rather than deleted so the volume changes feed sees the detach
 	for volumeID, dbMount := range dbMountMap {
 		if _, exists := dockerMountMap[volumeID]; !exists && dbMount.IsActive {
-			if err := r.repository.DeleteVolumeMount(ctx, volumeID, containerID); err != nil {
+			deactivated := *dbMount
+			deactivated.IsActive = false
+			deactivated.UpdatedAt = time.Now()
+			if err := upsert(&deactivated); err != nil {
 				log.Printf("[WARN] Failed to deactivate mount %s->%s: %v", volumeID, containerID, err)
+				report.failures++
+				continue
 			}
+			report.Mounts.Removed = append(report.Mounts.Removed, containerID+":"+volumeID)
 		}
 	}
 
@@ -0,0 +1,174 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mantonx/volumeviz/internal/config"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func makeReconcileVolumes(count int) []*database.Volume {
+	volumes := make([]*database.Volume, count)
+	for i := range volumes {
+		volumes[i] = &database.Volume{VolumeID: fmt.Sprintf("vol-%d", i), Name: fmt.Sprintf("vol-%d", i)}
+	}
+	return volumes
+}
+
+func TestUpsertVolumesInBatches(t *testing.T) {
+	mockRepo := &MockRepository{}
+	cfg := &config.EventsConfig{ReconcileBatchSize: 4, ReconcileConcurrency: 2}
+	reconciler := NewReconcilerService(nil, mockRepo, cfg, nil)
+
+	volumes := makeReconcileVolumes(10)
+
+	batchSizes := make(chan int, 3)
+	mockRepo.On("BulkUpsertVolumes", mock.Anything, mock.AnythingOfType("[]*database.Volume")).
+		Run(func(args mock.Arguments) {
+			batchSizes <- len(args.Get(1).([]*database.Volume))
+		}).
+		Return(&database.BulkUpsertResult{
+			Failed: []database.VolumeUpsertFailure{{VolumeID: "vol-5", Err: errors.New("bad row")}},
+		}, nil)
+
+	failed := reconciler.upsertVolumesInBatches(context.Background(), volumes)
+	close(batchSizes)
+
+	total := 0
+	var sizes []int
+	for size := range batchSizes {
+		sizes = append(sizes, size)
+		total += size
+	}
+	assert.Len(t, sizes, 3)
+	assert.ElementsMatch(t, []int{4, 4, 2}, sizes)
+	assert.Equal(t, 10, total)
+	assert.Equal(t, map[string]bool{"vol-5": true}, failed)
+}
+
+func TestUpsertVolumesInBatches_BatchError(t *testing.T) {
+	mockRepo := &MockRepository{}
+	reconciler := NewReconcilerService(nil, mockRepo, &config.EventsConfig{}, nil)
+
+	mockRepo.On("BulkUpsertVolumes", mock.Anything, mock.Anything).Return(nil, errors.New("connection lost"))
+
+	failed := reconciler.upsertVolumesInBatches(context.Background(), makeReconcileVolumes(3))
+
+	// Default batch size fits everything in one call, and every row is marked failed
+	mockRepo.AssertNumberOfCalls(t, "BulkUpsertVolumes", 1)
+	assert.Len(t, failed, 3)
+}
+
+// reconcileFixture sets up Docker and database state needing one of each
+// reconciliation change
+func reconcileFixture() (*driftDockerClient, *MockRepository, *database.VolumeMount) {
+	dockerClient := &driftDockerClient{
+		volumes: []*volume.Volume{
+			{Name: "in-sync", Driver: "local", Scope: "local"},
+			{Name: "changed", Driver: "nfs", Scope: "local"},
+			{Name: "new-vol", Driver: "local", Scope: "local"},
+		},
+		containers: []containertypes.Summary{
+			{ID: "c-new", State: "running", Status: "Up"},
+			{ID: "c-sync", State: "running", Status: "Up"},
+		},
+	}
+	dockerClient.On("ContainerInspect", mock.Anything, "c-new").Return(runningContainerJSON("c-new"), nil)
+	dockerClient.On("ContainerInspect", mock.Anything, "c-sync").Return(runningContainerJSON("c-sync"), nil)
+
+	movedMount := &database.VolumeMount{VolumeID: "test-vol", ContainerID: "c-sync", MountPath: "/old", AccessMode: "rw", IsActive: true}
+
+	mockRepo := &MockRepository{}
+	mockRepo.On("ListAllVolumes", mock.Anything).Return([]*database.Volume{
+		{VolumeID: "in-sync", Driver: "local", Scope: "local", IsActive: true},
+		{VolumeID: "changed", Driver: "local", Scope: "local", IsActive: true},
+		{VolumeID: "gone", Driver: "local", Scope: "local", IsActive: true},
+	}, nil)
+	mockRepo.On("ListAllContainers", mock.Anything).Return([]*database.Container{
+		{ContainerID: "c-sync", State: "running", Status: "Up", IsActive: true},
+		{ContainerID: "c-gone", State: "running", Status: "Up", IsActive: true},
+	}, nil)
+	mockRepo.On("GetVolumeMountsByContainer", mock.Anything, "c-new").Return([]*database.VolumeMount{}, nil)
+	mockRepo.On("GetVolumeMountsByContainer", mock.Anything, "c-sync").Return([]*database.VolumeMount{
+		movedMount,
+		{VolumeID: "stale", ContainerID: "c-sync", MountPath: "/stale", AccessMode: "rw", IsActive: true},
+	}, nil)
+
+	return dockerClient, mockRepo, movedMount
+}
+
+// expectedReconcileChanges are the changes reconcileFixture calls for
+func expectedReconcileChanges(t *testing.T, report *ReconcileReport) {
+	t.Helper()
+	assert.Equal(t, ReconcileChanges{Added: []string{"new-vol"}, Updated: []string{"changed"}, Removed: []string{"gone"}}, report.Volumes)
+	assert.Equal(t, ReconcileChanges{Added: []string{"c-new"}, Updated: []string{}, Removed: []string{"c-gone"}}, report.Containers)
+	assert.Equal(t, ReconcileChanges{Added: []string{"c-new:test-vol"}, Updated: []string{"c-sync:test-vol"}, Removed: []string{"c-sync:stale"}}, report.Mounts)
+}
+
+func TestReconcile_DryRunReportsWithoutWriting(t *testing.T) {
+	dockerClient, mockRepo, movedMount := reconcileFixture()
+	reconciler := NewReconcilerService(dockerClient, mockRepo, &config.EventsConfig{}, nil)
+	removals := &recordingRemovals{}
+	reconciler.AddVolumeRemovalListener(removals)
+
+	report, err := reconciler.Reconcile(context.Background(), true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	expectedReconcileChanges(t, report)
+
+	// Nothing was written, and the loaded rows were left as they were
+	for _, write := range []string{"BulkUpsertVolumes", "UpsertVolume", "DeleteVolume", "UpsertContainer",
+		"DeleteContainer", "UpsertVolumeMount", "DeleteVolumeMount", "DeactivateVolumeMounts"} {
+		mockRepo.AssertNumberOfCalls(t, write, 0)
+	}
+	assert.Equal(t, "/old", movedMount.MountPath)
+	assert.Empty(t, reconciler.ReconcileRuns())
+	assert.Empty(t, removals.volumes)
+}
+
+func TestReconcile_AppliesChanges(t *testing.T) {
+	dockerClient, mockRepo, _ := reconcileFixture()
+	mockRepo.On("BulkUpsertVolumes", mock.Anything, mock.Anything).Return(&database.BulkUpsertResult{}, nil)
+	mockRepo.On("DeleteVolume", mock.Anything, "gone").Return(nil)
+	mockRepo.On("UpsertContainer", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpsertVolumeMount", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("DeactivateVolumeMounts", mock.Anything, "c-gone").Return(nil)
+	mockRepo.On("DeleteContainer", mock.Anything, "c-gone").Return(nil)
+
+	reconciler := NewReconcilerService(dockerClient, mockRepo, &config.EventsConfig{}, nil)
+	removals := &recordingRemovals{}
+	reconciler.AddVolumeRemovalListener(removals)
+
+	report, err := reconciler.Reconcile(context.Background(), false)
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	expectedReconcileChanges(t, report)
+	assert.Equal(t, []string{"gone"}, removals.volumes)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpsertContainer", 1)
+	mockRepo.AssertNumberOfCalls(t, "UpsertVolumeMount", 3)
+	mockRepo.AssertCalled(t, "UpsertVolumeMount", mock.Anything, mock.MatchedBy(func(mount *database.VolumeMount) bool {
+		return mount.VolumeID == "stale" && !mount.IsActive
+	}))
+	mockRepo.AssertNotCalled(t, "DeleteVolumeMount", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, int64(1), reconciler.ReconcileRuns()["full"])
+}
+
+func TestFullReconcile_ConfiguredDryRun(t *testing.T) {
+	dockerClient, mockRepo, _ := reconcileFixture()
+	reconciler := NewReconcilerService(dockerClient, mockRepo, &config.EventsConfig{ReconcileDryRun: true}, nil)
+
+	require.NoError(t, reconciler.FullReconcile(context.Background()))
+	mockRepo.AssertNumberOfCalls(t, "BulkUpsertVolumes", 0)
+	mockRepo.AssertNumberOfCalls(t, "DeleteVolume", 0)
+	mockRepo.AssertNumberOfCalls(t, "DeleteContainer", 0)
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// ScanEnqueuer queues a volume for a size scan triggered by an event. It is
+// satisfied by the scan scheduler, which applies its skip patterns and
+// per-volume rate limit.
+type ScanEnqueuer interface {
+	EnqueueEventScan(volumeName string) (string, error)
+}
+
+// scanTrigger enqueues a scan for newly created volumes once they have existed
+// for the debounce delay. Creating the volume again restarts the delay and
+// removing it cancels the pending scan, so create/remove churn scans nothing.
+type scanTrigger struct {
+	enqueuer ScanEnqueuer
+	delay    time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// newScanTrigger creates a new scan trigger
+func newScanTrigger(enqueuer ScanEnqueuer, delay time.Duration) *scanTrigger {
+	return &scanTrigger{
+		enqueuer: enqueuer,
+		delay:    delay,
+		pending:  make(map[string]*time.Timer),
+	}
+}
+
+// schedule queues a scan of the volume after the debounce delay, replacing
+// any scan already pending for it
+func (t *scanTrigger) schedule(volumeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if timer, ok := t.pending[volumeName]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(t.delay, func() {
+		t.mu.Lock()
+		if t.pending[volumeName] != timer {
+			// Superseded or cancelled after the timer fired
+			t.mu.Unlock()
+			return
+		}
+		delete(t.pending, volumeName)
+		t.mu.Unlock()
+
+		t.enqueue(volumeName)
+	})
+	t.pending[volumeName] = timer
+}
+
+// cancel drops the pending scan of the volume, if any
+func (t *scanTrigger) cancel(volumeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if timer, ok := t.pending[volumeName]; ok {
+		timer.Stop()
+		delete(t.pending, volumeName)
+		log.Printf("[DEBUG] Cancelled scan of removed volume %s", volumeName)
+	}
+}
+
+// enqueue hands the volume to the scheduler. Rejections (skip patterns, rate
+// limits, a paused scheduler) are expected and only logged.
+func (t *scanTrigger) enqueue(volumeName string) {
+	scanID, err := t.enqueuer.EnqueueEventScan(volumeName)
+	if err != nil {
+		log.Printf("[INFO] Not scanning new volume %s: %v", volumeName, err)
+		return
+	}
+	log.Printf("[INFO] Enqueued scan of new volume %s (scan_id: %s)", volumeName, scanID)
+}
@@ -21,6 +21,7 @@ const (
 	ContainerStopped   EventType = "container.stop"
 	ContainerDied      EventType = "container.die"
 	ContainerDestroyed EventType = "container.destroy"
+	ContainerRenamed   EventType = "container.rename"
 )
 
 // DockerEvent represents a processed Docker event
@@ -28,7 +29,7 @@ type DockerEvent struct {
 	Type        EventType         `json:"type"`
 	ID          string            `json:"id"`           // Volume ID or Container ID
 	Name        string            `json:"name"`         // Volume name or Container name
-	Action      string            `json:"action"`       // create, remove, start, stop, die, destroy
+	Action      string            `json:"action"`       // create, remove, start, stop, die, destroy, rename
 	Time        time.Time         `json:"time"`
 	Attributes  map[string]string `json:"attributes"`
 	RawEvent    events.Message    `json:"raw_event"`
@@ -47,6 +48,7 @@ type EventHandler interface {
 	HandleContainerStop(ctx context.Context, event *DockerEvent) error
 	HandleContainerDie(ctx context.Context, event *DockerEvent) error
 	HandleContainerDestroy(ctx context.Context, event *DockerEvent) error
+	HandleContainerRename(ctx context.Context, event *DockerEvent) error
 }
 
 // Reconciler defines the interface for periodic reconciliation
@@ -54,6 +56,21 @@ type Reconciler interface {
 	ReconcileVolumes(ctx context.Context) error
 	ReconcileContainers(ctx context.Context) error
 	FullReconcile(ctx context.Context) error
+	// IncrementalReconcile only revisits resources changed since the last
+	// reconciliation, falling back to a full one without a baseline
+	IncrementalReconcile(ctx context.Context) error
+	// ReconcileRuns returns the number of finished runs by kind
+	ReconcileRuns() map[string]int64
+}
+
+// ReconcileRunner runs a full reconciliation on demand, optionally as a dry run
+type ReconcileRunner interface {
+	Reconcile(ctx context.Context, dryRun bool) (*ReconcileReport, error)
+}
+
+// DriftDetector defines the interface for read-only comparison of database and Docker state
+type DriftDetector interface {
+	DetectDrift(ctx context.Context) (*DriftReport, error)
 }
 
 // EventService defines the main events service interface
@@ -76,6 +93,18 @@ type EventMetrics struct {
 	LastReconnectTime *time.Time         `json:"last_reconnect_time"`
 	Connected        bool                `json:"connected"`
 	QueueSize        int                 `json:"queue_size"`
+
+	// LastEventAt is when the stream last delivered an event of any type,
+	// LastConnectedAt when it last connected
+	LastEventAt     *time.Time `json:"last_event_at,omitempty"`
+	LastConnectedAt *time.Time `json:"last_connected_at,omitempty"`
+	// ReconnectAttempts counts the reconnects since a stream last stayed up
+	// for the backoff reset period; it drives the reconnect backoff
+	ReconnectAttempts int `json:"reconnect_attempts"`
+	// Healthy is false while disconnected, or once the stream has gone the
+	// health window without an event or a reconnect
+	Healthy         bool   `json:"healthy"`
+	UnhealthyReason string `json:"unhealthy_reason,omitempty"`
 }
 
 // Repository defines database operations for events
@@ -98,6 +127,7 @@ type Repository interface {
 	DeactivateVolumeMounts(ctx context.Context, containerID string) error
 
 	// Bulk operations for reconciliation
+	BulkUpsertVolumes(ctx context.Context, volumes []*database.Volume) (*database.BulkUpsertResult, error)
 	ListAllVolumes(ctx context.Context) ([]*database.Volume, error)
 	ListAllContainers(ctx context.Context) ([]*database.Container, error)
 	ListAllVolumeMounts(ctx context.Context) ([]*database.VolumeMount, error)
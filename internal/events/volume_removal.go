@@ -0,0 +1,18 @@
+package events
+
+// VolumeRemovalListener is told about volumes removed from Docker, so services
+// keeping per-volume state in memory can drop it. It is satisfied by the scan
+// scheduler and the scanner's metrics collector.
+type VolumeRemovalListener interface {
+	VolumeRemoved(volumeName string)
+}
+
+// volumeRemovalListeners fans a volume removal out to every listener
+type volumeRemovalListeners []VolumeRemovalListener
+
+// notify tells every listener that volumeName was removed
+func (l volumeRemovalListeners) notify(volumeName string) {
+	for _, listener := range l {
+		listener.VolumeRemoved(volumeName)
+	}
+}
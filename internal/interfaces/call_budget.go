@@ -0,0 +1,53 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrDockerCallBudgetExhausted is returned for Docker API calls a request
+// makes after spending its budget
+var ErrDockerCallBudgetExhausted = errors.New("docker API call budget exhausted")
+
+// dockerCallBudgetKey is the context key for WithDockerCallBudget
+type dockerCallBudgetKey struct{}
+
+// dockerCallBudget counts down the Docker API calls left to a request
+type dockerCallBudget struct {
+	remaining atomic.Int64
+	exhausted atomic.Bool
+}
+
+// WithDockerCallBudget returns a context allowing at most calls Docker API
+// calls through a DockerService, so a single expensive request cannot flood
+// the daemon. A budget of zero or less is unlimited.
+func WithDockerCallBudget(ctx context.Context, calls int) context.Context {
+	if calls <= 0 {
+		return ctx
+	}
+	budget := &dockerCallBudget{}
+	budget.remaining.Store(int64(calls))
+	return context.WithValue(ctx, dockerCallBudgetKey{}, budget)
+}
+
+// SpendDockerCall takes one call from the budget of ctx, returning
+// ErrDockerCallBudgetExhausted when none is left
+func SpendDockerCall(ctx context.Context) error {
+	budget, ok := ctx.Value(dockerCallBudgetKey{}).(*dockerCallBudget)
+	if !ok {
+		return nil
+	}
+	if budget.remaining.Add(-1) < 0 {
+		budget.exhausted.Store(true)
+		return ErrDockerCallBudgetExhausted
+	}
+	return nil
+}
+
+// DockerCallBudgetExhausted reports whether a Docker API call was refused
+// for want of budget under ctx
+func DockerCallBudgetExhausted(ctx context.Context) bool {
+	budget, ok := ctx.Value(dockerCallBudgetKey{}).(*dockerCallBudget)
+	return ok && budget.exhausted.Load()
+}
@@ -23,4 +23,5 @@ type DockerService interface {
 	GetVolumeContainers(ctx context.Context, volumeName string) ([]models.VolumeContainer, error)
 	GetVolumesByDriver(ctx context.Context, driver string) ([]models.Volume, error)
 	GetVolumesByLabel(ctx context.Context, labelKey, labelValue string) ([]models.Volume, error)
+	RemoveVolume(ctx context.Context, volumeName string) error
 }
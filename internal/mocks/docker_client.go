@@ -18,7 +18,8 @@ type MockDockerClient struct {
 	IsConnectedFunc func(ctx context.Context) bool
 
 	// Version
-	VersionFunc func(ctx context.Context) (types.Version, error)
+	VersionFunc       func(ctx context.Context) (types.Version, error)
+	ClientVersionFunc func() string
 
 	// Volumes
 	ListVolumesFunc   func(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error)
@@ -87,6 +88,14 @@ func (m *MockDockerClient) Version(ctx context.Context) (types.Version, error) {
 	}, nil
 }
 
+// ClientVersion mocks the negotiated Docker API version
+func (m *MockDockerClient) ClientVersion() string {
+	if m.ClientVersionFunc != nil {
+		return m.ClientVersionFunc()
+	}
+	return "1.41"
+}
+
 // ListVolumes mocks the ListVolumes method
 func (m *MockDockerClient) ListVolumes(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
 	m.ListVolumesCalls++
@@ -64,4 +64,9 @@ func (m *DockerService) GetVolumeContainers(ctx context.Context, volumeID string
 	return args.Get(0).([]models.VolumeContainer), args.Error(1)
 }
 
+func (m *DockerService) RemoveVolume(ctx context.Context, volumeName string) error {
+	args := m.Called(ctx, volumeName)
+	return args.Error(0)
+}
+
 // Other methods can be added as needed based on the interface
\ No newline at end of file
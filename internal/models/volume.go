@@ -4,6 +4,9 @@ import (
 	"time"
 )
 
+// LocalNodeName is the node reported for volumes on hosts that are not part of a Swarm
+const LocalNodeName = "local"
+
 // Volume represents a Docker volume with metadata
 type Volume struct {
 	ID         string            `json:"id"`
@@ -16,6 +19,7 @@ type Volume struct {
 	Scope      string            `json:"scope"`
 	Status     map[string]string `json:"status,omitempty"`
 	UsageData  *VolumeUsage      `json:"usage_data,omitempty"`
+	Node       string            `json:"node,omitempty"` // Swarm node the volume lives on, or "local"
 }
 
 // VolumeUsage contains volume usage statistics
@@ -33,7 +37,8 @@ type VolumeContainer struct {
 	MountPath   string `json:"mount_path"`
 	MountType   string `json:"mount_type"`
 	AccessMode  string `json:"access_mode"`
-	Propagation string `json:"propagation,omitempty"`
+	Propagation string `json:"propagation,omitempty"` // rprivate, shared, slave, ... (Linux only)
+	Consistency string `json:"consistency,omitempty"` // consistent, cached, delegated (Mac/Windows only)
 }
 
 // DockerHealth represents Docker daemon health status
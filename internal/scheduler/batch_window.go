@@ -0,0 +1,39 @@
+package scheduler
+
+// batchWindow returns how many scans a batch enqueue may leave queued at
+// once: the configured window, at most the queue size. 0 disables pacing.
+func (s *Scheduler) batchWindow() int {
+	if s.config.BatchWindow <= 0 {
+		return 0
+	}
+	return min(s.config.BatchWindow, cap(s.taskQueue))
+}
+
+// waitForBatchSlot blocks until fewer than window scans are queued, so the
+// next volume of a batch is only enqueued once workers have taken earlier
+// ones. It returns false if the scheduler stops or is paused meanwhile, in
+// which case the rest of the batch is not enqueued.
+func (s *Scheduler) waitForBatchSlot(window int) bool {
+	for len(s.taskQueue) >= window {
+		select {
+		case <-s.dequeued:
+		case <-s.ctx.Done():
+			return false
+		}
+		// Workers keep draining a paused scheduler, so a pause is noticed on
+		// the next dequeue
+		if s.IsPaused() {
+			return false
+		}
+	}
+	return true
+}
+
+// signalDequeued wakes a batch enqueue waiting for a queue slot without
+// blocking; signals coalesce as the waiter rechecks the queue length
+func (s *Scheduler) signalDequeued() {
+	select {
+	case s.dequeued <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// batchWindowScheduler returns a running test scheduler with a small queue
+// and count volumes to enqueue
+func batchWindowScheduler(queueSize, window, count int) *Scheduler {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx, scheduler.cancel = context.WithCancel(context.Background())
+	scheduler.taskQueue = make(chan *ScanTask, queueSize)
+	scheduler.config.BatchWindow = window
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+
+	volumes := make([]*database.Volume, count)
+	for i := range volumes {
+		volumes[i] = localVolume(fmt.Sprintf("volume-%02d", i))
+	}
+	mockProvider.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	return scheduler
+}
+
+func TestBatchWindowClampedToQueueSize(t *testing.T) {
+	scheduler := batchWindowScheduler(4, 0, 0)
+	assert.Equal(t, 0, scheduler.batchWindow())
+
+	scheduler.config.BatchWindow = 2
+	assert.Equal(t, 2, scheduler.batchWindow())
+
+	scheduler.config.BatchWindow = 50
+	assert.Equal(t, 4, scheduler.batchWindow())
+}
+
+func TestEnqueueAllVolumesOverflowsSmallQueueWithoutWindow(t *testing.T) {
+	scheduler := batchWindowScheduler(3, 0, 20)
+
+	_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, 0)
+	require.NoError(t, err)
+
+	// The batch stops at the first full enqueue
+	assert.Len(t, scheduler.taskQueue, 3)
+}
+
+func TestEnqueueAllVolumesPacesToBatchWindow(t *testing.T) {
+	const window = 2
+	scheduler := batchWindowScheduler(3, window, 20)
+
+	// A slow worker: every volume must still arrive, without the queue ever
+	// holding more than the window
+	received := make(chan []string)
+	go func() {
+		var names []string
+		for len(names) < 20 {
+			select {
+			case task := <-scheduler.taskQueue:
+				scheduler.signalDequeued()
+				names = append(names, task.VolumeName)
+				time.Sleep(time.Millisecond)
+			case <-scheduler.ctx.Done():
+				received <- names
+				return
+			}
+			assert.LessOrEqual(t, len(scheduler.taskQueue), window)
+		}
+		received <- names
+	}()
+
+	_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, 0)
+	require.NoError(t, err)
+
+	select {
+	case names := <-received:
+		require.Len(t, names, 20)
+		assert.Equal(t, "volume-00", names[0])
+		assert.Equal(t, "volume-19", names[19])
+	case <-time.After(5 * time.Second):
+		scheduler.cancel()
+		t.Fatal("every volume of the batch should reach the worker")
+	}
+}
+
+func TestEnqueueAllVolumesManualPacedBatchRunsInBackground(t *testing.T) {
+	scheduler := batchWindowScheduler(10, 3, 20)
+
+	// Nothing takes tasks, so the batch waits for a slot after the window
+	batchID, err := scheduler.EnqueueAllVolumes()
+	require.NoError(t, err)
+	assert.NotEmpty(t, batchID)
+
+	assert.Eventually(t, func() bool {
+		return len(scheduler.taskQueue) == 3
+	}, time.Second, time.Millisecond)
+
+	// Taking one scan lets exactly one more in
+	<-scheduler.taskQueue
+	scheduler.signalDequeued()
+	assert.Eventually(t, func() bool {
+		return len(scheduler.taskQueue) == 3
+	}, time.Second, time.Millisecond)
+
+	scheduler.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.True(t, waitGroupsDone(ctx, &scheduler.schedulerWG),
+		"stopping the scheduler should end the batch's wait")
+	assert.Len(t, scheduler.taskQueue, 3)
+}
+
+func TestEnqueueAllVolumesPacedBatchStopsWhenPaused(t *testing.T) {
+	scheduler := batchWindowScheduler(10, 1, 5)
+	scheduler.metricsCollector.(*MockMetricsCollector).On("SetSchedulerPausedStatus", true)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, 0)
+		assert.NoError(t, err)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(scheduler.taskQueue) == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, scheduler.Pause(false))
+
+	// The paused scheduler's workers still drain, which ends the wait
+	<-scheduler.taskQueue
+	scheduler.signalDequeued()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("pausing the scheduler should end the batch")
+	}
+	assert.Empty(t, scheduler.taskQueue)
+}
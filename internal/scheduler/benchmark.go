@@ -0,0 +1,248 @@
+package scheduler
+
+import (
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+)
+
+// benchmarkSizeTolerance is how far (as a fraction) a method's measured size may
+// stray from the median of all methods before it is considered inaccurate
+const benchmarkSizeTolerance = 0.01
+
+// defaultBenchmarkInterval is used when auto-benchmarking is enabled without an interval
+const defaultBenchmarkInterval = 24 * time.Hour
+
+// filesystemBenchmark is the latest benchmark for one filesystem type
+type filesystemBenchmark struct {
+	durations    map[string]time.Duration // Accurate methods only
+	fastest      string
+	sampleVolume string
+	measuredAt   time.Time
+}
+
+// methodBenchmarks stores measured scan method durations per filesystem type
+type methodBenchmarks struct {
+	mu                sync.RWMutex
+	byFilesystem      map[string]*filesystemBenchmark
+	volumeFilesystems map[string]string // Filesystem type learned from scan results
+}
+
+// newMethodBenchmarks creates an empty benchmark store
+func newMethodBenchmarks() *methodBenchmarks {
+	return &methodBenchmarks{
+		byFilesystem:      make(map[string]*filesystemBenchmark),
+		volumeFilesystems: make(map[string]string),
+	}
+}
+
+// record stores a benchmark and returns the fastest accurate method it found.
+// Benchmarks where no method succeeded leave the previous result in place.
+func (b *methodBenchmarks) record(benchmark *interfaces.MethodBenchmark) (string, bool) {
+	accurate := accurateTimings(benchmark.Timings)
+	if len(accurate) == 0 {
+		return "", false
+	}
+
+	entry := &filesystemBenchmark{
+		durations:    make(map[string]time.Duration, len(accurate)),
+		sampleVolume: benchmark.VolumeID,
+		measuredAt:   benchmark.MeasuredAt,
+	}
+	for _, timing := range accurate {
+		entry.durations[timing.Method] = timing.Duration
+		if entry.fastest == "" || timing.Duration < entry.durations[entry.fastest] {
+			entry.fastest = timing.Method
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.byFilesystem[benchmark.FilesystemType] = entry
+	b.volumeFilesystems[benchmark.VolumeID] = benchmark.FilesystemType
+
+	return entry.fastest, true
+}
+
+// setVolumeFilesystem remembers the filesystem type a volume was scanned on
+func (b *methodBenchmarks) setVolumeFilesystem(volumeName, filesystemType string) {
+	if filesystemType == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.volumeFilesystems[volumeName] = filesystemType
+}
+
+// fastestFor returns the fastest accurate method measured on the volume's filesystem
+func (b *methodBenchmarks) fastestFor(volumeName string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	filesystemType, ok := b.volumeFilesystems[volumeName]
+	if !ok {
+		return "", false
+	}
+	entry, ok := b.byFilesystem[filesystemType]
+	if !ok {
+		return "", false
+	}
+	return entry.fastest, true
+}
+
+// sampleVolumes returns one known volume per filesystem type, sorted by filesystem
+func (b *methodBenchmarks) sampleVolumes() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	samples := make(map[string]string)
+	for volumeName, filesystemType := range b.volumeFilesystems {
+		if current, ok := samples[filesystemType]; !ok || volumeName < current {
+			samples[filesystemType] = volumeName
+		}
+	}
+
+	filesystems := make([]string, 0, len(samples))
+	for filesystemType := range samples {
+		filesystems = append(filesystems, filesystemType)
+	}
+	sort.Strings(filesystems)
+
+	volumes := make([]string, len(filesystems))
+	for i, filesystemType := range filesystems {
+		volumes[i] = samples[filesystemType]
+	}
+	return volumes
+}
+
+// snapshot returns measured durations in seconds by filesystem type and method
+func (b *methodBenchmarks) snapshot() map[string]map[string]float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	result := make(map[string]map[string]float64, len(b.byFilesystem))
+	for filesystemType, entry := range b.byFilesystem {
+		durations := make(map[string]float64, len(entry.durations))
+		for method, duration := range entry.durations {
+			durations[method] = duration.Seconds()
+		}
+		result[filesystemType] = durations
+	}
+	return result
+}
+
+// accurateTimings returns the successful timings whose size agrees with the median
+func accurateTimings(timings []interfaces.MethodTiming) []interfaces.MethodTiming {
+	var successful []interfaces.MethodTiming
+	for _, timing := range timings {
+		if timing.Error == "" {
+			successful = append(successful, timing)
+		}
+	}
+	if len(successful) == 0 {
+		return nil
+	}
+
+	sizes := make([]int64, len(successful))
+	for i, timing := range successful {
+		sizes[i] = timing.SizeBytes
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	median := float64(sizes[len(sizes)/2])
+
+	var accurate []interfaces.MethodTiming
+	for _, timing := range successful {
+		if math.Abs(float64(timing.SizeBytes)-median) <= median*benchmarkSizeTolerance {
+			accurate = append(accurate, timing)
+		}
+	}
+	return accurate
+}
+
+// runBenchmarkLoop benchmarks scan methods on start and then every BenchmarkInterval
+func (s *Scheduler) runBenchmarkLoop(benchmarker interfaces.MethodBenchmarker) {
+	defer s.schedulerWG.Done()
+
+	interval := s.config.BenchmarkInterval
+	if interval <= 0 {
+		interval = defaultBenchmarkInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("[INFO] Scan method auto-benchmarking started (interval: %v)", interval)
+
+	s.runBenchmarks(benchmarker)
+	for {
+		select {
+		case <-ticker.C:
+			s.runBenchmarks(benchmarker)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// runBenchmarks benchmarks one sample volume per known filesystem type
+func (s *Scheduler) runBenchmarks(benchmarker interfaces.MethodBenchmarker) {
+	for _, volumeName := range s.benchmarkSampleVolumes() {
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		benchmark, err := benchmarker.BenchmarkMethods(s.ctx, volumeName)
+		if err != nil {
+			log.Printf("[WARN] Failed to benchmark scan methods on volume %s: %v", volumeName, err)
+			continue
+		}
+
+		if fastest, ok := s.benchmarks.record(benchmark); ok {
+			log.Printf("[INFO] Fastest scan method for filesystem %s is %s (sample volume: %s)",
+				benchmark.FilesystemType, fastest, volumeName)
+		} else {
+			log.Printf("[WARN] No scan method succeeded while benchmarking volume %s", volumeName)
+		}
+	}
+}
+
+// benchmarkSampleVolumes picks the volumes to benchmark. Until any filesystem type
+// is known from scan results, the first scannable volume is used.
+func (s *Scheduler) benchmarkSampleVolumes() []string {
+	// Volumes switched off since they were scanned are not benchmarked either
+	scanDisabled := s.scanDisabledVolumes()
+	var samples []string
+	for _, name := range s.benchmarks.sampleVolumes() {
+		if !scanDisabled[name] {
+			samples = append(samples, name)
+		}
+	}
+	if len(samples) > 0 {
+		return samples
+	}
+
+	volumes, err := s.volumeProvider.ListVolumes(s.ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed to list volumes for benchmarking: %v", err)
+		return nil
+	}
+
+	for _, volume := range volumes {
+		if s.shouldSkipVolume(volume.Name) || !s.sizePolicy.SizeSupported(volume.Driver) || scanDisabled[volume.Name] {
+			continue
+		}
+		if scannable, _ := s.sizePolicy.Scannable(volume.Mountpoint, volume.Options); !scannable {
+			continue
+		}
+		if s.isBindMount(volume.Name) && !s.isBindMountAllowed(volume.Name) {
+			continue
+		}
+		return []string{volume.Name}
+	}
+	return nil
+}
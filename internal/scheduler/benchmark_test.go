@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubBenchmarker returns fixed method timings per volume
+type stubBenchmarker struct {
+	MockVolumeScanner
+	benchmarks map[string]*interfaces.MethodBenchmark
+}
+
+func (s *stubBenchmarker) BenchmarkMethods(ctx context.Context, volumeID string) (*interfaces.MethodBenchmark, error) {
+	return s.benchmarks[volumeID], nil
+}
+
+func TestRunBenchmarksChoosesFastestPerFilesystem(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+	scheduler.config.AutoBenchmark = true
+	scheduler.config.MethodsOrder = []string{"native"}
+	scheduler.ctx = context.Background()
+
+	// Filesystems learned from earlier scans
+	scheduler.benchmarks.setVolumeFilesystem("ext4-volume", "ext4")
+	scheduler.benchmarks.setVolumeFilesystem("nfs-volume", "nfs")
+	scheduler.benchmarks.setVolumeFilesystem("other-ext4-volume", "ext4")
+
+	benchmarker := &stubBenchmarker{benchmarks: map[string]*interfaces.MethodBenchmark{
+		"ext4-volume": {
+			VolumeID:       "ext4-volume",
+			FilesystemType: "ext4",
+			Timings: []interfaces.MethodTiming{
+				{Method: "diskus", Duration: 100 * time.Millisecond, SizeBytes: 1 << 30},
+				{Method: "du", Duration: 400 * time.Millisecond, SizeBytes: 1 << 30},
+				{Method: "native", Duration: 2 * time.Second, SizeBytes: 1 << 30},
+			},
+		},
+		"nfs-volume": {
+			VolumeID:       "nfs-volume",
+			FilesystemType: "nfs",
+			Timings: []interfaces.MethodTiming{
+				{Method: "diskus", Duration: 9 * time.Second, SizeBytes: 1 << 30},
+				{Method: "du", Duration: 3 * time.Second, SizeBytes: 1 << 30},
+				// Fastest but wrong size: not accurate, never preferred
+				{Method: "native", Duration: time.Second, SizeBytes: 1 << 20},
+			},
+		},
+	}}
+
+	scheduler.runBenchmarks(benchmarker)
+
+	assert.Equal(t, "diskus", scheduler.selectScanMethod("ext4-volume", nil))
+	assert.Equal(t, "diskus", scheduler.selectScanMethod("other-ext4-volume", nil))
+	assert.Equal(t, "du", scheduler.selectScanMethod("nfs-volume", nil))
+
+	// No benchmark for the volume's filesystem: configured order
+	assert.Equal(t, "native", scheduler.selectScanMethod("unknown-volume", nil))
+
+	metrics := scheduler.GetMetrics()
+	assert.Equal(t, map[string]float64{"diskus": 0.1, "du": 0.4, "native": 2}, metrics.MethodBenchmarks["ext4"])
+	assert.NotContains(t, metrics.MethodBenchmarks["nfs"], "native")
+
+	// Opt-in: measurements are ignored when auto-benchmarking is off
+	scheduler.config.AutoBenchmark = false
+	assert.Equal(t, "native", scheduler.selectScanMethod("nfs-volume", nil))
+	assert.Nil(t, scheduler.GetMetrics().MethodBenchmarks)
+}
+
+func TestRunBenchmarksKeepsPreviousResultWhenAllMethodsFail(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+	scheduler.config.AutoBenchmark = true
+	scheduler.ctx = context.Background()
+	scheduler.benchmarks.setVolumeFilesystem("nfs-volume", "nfs")
+
+	scheduler.benchmarks.record(&interfaces.MethodBenchmark{
+		VolumeID:       "nfs-volume",
+		FilesystemType: "nfs",
+		Timings:        []interfaces.MethodTiming{{Method: "du", Duration: time.Second, SizeBytes: 10}},
+	})
+
+	benchmarker := &stubBenchmarker{benchmarks: map[string]*interfaces.MethodBenchmark{
+		"nfs-volume": {
+			VolumeID:       "nfs-volume",
+			FilesystemType: "nfs",
+			Timings: []interfaces.MethodTiming{
+				{Method: "diskus", Error: "timeout"},
+				{Method: "du", Error: "timeout"},
+			},
+		},
+	}}
+	scheduler.runBenchmarks(benchmarker)
+
+	assert.Equal(t, "du", scheduler.selectScanMethod("nfs-volume", nil))
+}
+
+func TestBenchmarkSampleVolumes(t *testing.T) {
+	scheduler, _, _, mockProvider, _ := createTestScheduler()
+	scheduler.ctx = context.Background()
+
+	// Nothing scanned yet: first scannable volume from the provider
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("test_skipped"), // matches skip pattern
+		localVolume("data"),
+		localVolume("logs"),
+	}, nil).Once()
+	assert.Equal(t, []string{"data"}, scheduler.benchmarkSampleVolumes())
+
+	// One sample per known filesystem
+	scheduler.benchmarks.setVolumeFilesystem("b-ext4", "ext4")
+	scheduler.benchmarks.setVolumeFilesystem("a-ext4", "ext4")
+	scheduler.benchmarks.setVolumeFilesystem("nfs-volume", "nfs")
+	assert.Equal(t, []string{"a-ext4", "nfs-volume"}, scheduler.benchmarkSampleVolumes())
+}
+
+func TestAccurateTimings(t *testing.T) {
+	timings := []interfaces.MethodTiming{
+		{Method: "diskus", SizeBytes: 1000},
+		{Method: "du", SizeBytes: 1005},
+		{Method: "native", SizeBytes: 500},
+		{Method: "broken", Error: "exec: not found"},
+	}
+
+	accurate := accurateTimings(timings)
+
+	var methods []string
+	for _, timing := range accurate {
+		methods = append(methods, timing.Method)
+	}
+	assert.Equal(t, []string{"diskus", "du"}, methods)
+	assert.Empty(t, accurateTimings([]interfaces.MethodTiming{{Method: "du", Error: "failed"}}))
+}
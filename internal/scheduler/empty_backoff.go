@@ -0,0 +1,113 @@
+package scheduler
+
+import "sync"
+
+// emptyVolumes tracks volumes whose scans keep finding them (nearly) empty,
+// so scheduled passes can scan them less often and spend the scan budget on
+// volumes that hold data
+type emptyVolumes struct {
+	mu      sync.Mutex
+	volumes map[string]*emptyStreak
+}
+
+// emptyStreak is a run of consecutive empty scans of a volume
+type emptyStreak struct {
+	scans   int // Consecutive scans that found the volume empty
+	skipped int // Scheduled passes left out since the last of them
+}
+
+// newEmptyVolumes creates an empty store of empty volumes
+func newEmptyVolumes() *emptyVolumes {
+	return &emptyVolumes{volumes: make(map[string]*emptyStreak)}
+}
+
+// record counts a successful scan of volumeName. A scan above threshold bytes
+// ends the volume's streak of empty scans.
+func (e *emptyVolumes) record(volumeName string, size, threshold int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if size > threshold {
+		delete(e.volumes, volumeName)
+		return
+	}
+	streak, ok := e.volumes[volumeName]
+	if !ok {
+		streak = &emptyStreak{}
+		e.volumes[volumeName] = streak
+	}
+	streak.scans++
+	streak.skipped = 0
+}
+
+// forget drops the streak of a removed volume
+func (e *emptyVolumes) forget(volumeName string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.volumes, volumeName)
+}
+
+// skipPass reports whether a scheduled pass leaves out volumeName, counting
+// the pass as skipped if so. Once after consecutive scans found the volume
+// empty it is scanned every second pass, and every further empty scan
+// doubles that, up to every maxPasses passes.
+func (e *emptyVolumes) skipPass(volumeName string, after, maxPasses int) bool {
+	if after <= 0 || maxPasses <= 1 {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	streak, ok := e.volumes[volumeName]
+	if !ok || streak.scans < after {
+		return false
+	}
+	if streak.skipped+1 >= emptyScanEvery(streak.scans-after, maxPasses) {
+		return false
+	}
+	streak.skipped++
+	return true
+}
+
+// emptyScanEvery returns every how many passes a volume is scanned after
+// extra empty scans beyond the backoff threshold
+func emptyScanEvery(extra, maxPasses int) int {
+	every := 2
+	for range extra {
+		if every >= maxPasses {
+			break
+		}
+		every *= 2
+	}
+	return min(every, maxPasses)
+}
+
+// emptyBackoffPasses returns the most scheduled passes an empty volume is
+// scanned once in, from EmptyBackoffMax
+func (s *Scheduler) emptyBackoffPasses() int {
+	if s.config.Interval <= 0 {
+		return 0
+	}
+	return int(s.config.EmptyBackoffMax / s.config.Interval)
+}
+
+// emptyBackedOff reports whether a batch with triggerSource leaves out
+// volumeName because its recent scans found it empty. Only scheduled passes
+// back off; batches asked for through the API scan every volume.
+func (s *Scheduler) emptyBackedOff(volumeName, triggerSource string) bool {
+	if triggerSource != TriggerSourceScheduled {
+		return false
+	}
+	return s.empty.skipPass(volumeName, s.config.EmptyScansBeforeBackoff, s.emptyBackoffPasses())
+}
+
+// VolumeRemoved drops the per-volume state kept for a volume removed from
+// Docker, so a volume created again under its name starts afresh
+func (s *Scheduler) VolumeRemoved(volumeName string) {
+	s.empty.forget(volumeName)
+	s.costs.forget(volumeName)
+}
+
+// recordScanSize notes the size a scan of volumeName found for the empty
+// volume backoff
+func (s *Scheduler) recordScanSize(volumeName string, size int64) {
+	s.empty.record(volumeName, size, s.config.EmptyThresholdBytes)
+}
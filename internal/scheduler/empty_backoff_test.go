@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyScanEvery(t *testing.T) {
+	assert.Equal(t, 2, emptyScanEvery(0, 16))
+	assert.Equal(t, 4, emptyScanEvery(1, 16))
+	assert.Equal(t, 16, emptyScanEvery(3, 16))
+	assert.Equal(t, 16, emptyScanEvery(10, 16))
+	assert.Equal(t, 3, emptyScanEvery(5, 3), "capped at the maximum")
+}
+
+func TestEmptyVolumeBackoff(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+	scheduler.config.EmptyScansBeforeBackoff = 2
+	scheduler.config.EmptyThresholdBytes = 4096
+	scheduler.config.EmptyBackoffMax = 4 * scheduler.config.Interval
+
+	sizes := map[string]int64{"cache": 0, "postgres-data": 1 << 30}
+	volumes := []*database.Volume{localVolume("cache"), localVolume("postgres-data")}
+
+	// pass enqueues a batch, scans what it enqueued at sizes and reports
+	// whether the empty volume was among them
+	pass := func(triggerSource string) bool {
+		scheduler.enqueueBatch("batch", volumes, triggerSource, 0)
+		scanned := false
+		for len(scheduler.taskQueue) > 0 {
+			task := <-scheduler.taskQueue
+			scheduler.queued.taken(task)
+			scheduler.recordScanSize(task.VolumeName, sizes[task.VolumeName])
+			if task.VolumeName == "cache" {
+				scanned = true
+			} else {
+				assert.Equal(t, "postgres-data", task.VolumeName)
+			}
+		}
+		return scanned
+	}
+	passes := func(count int) []bool {
+		scanned := make([]bool, count)
+		for i := range scanned {
+			scanned[i] = pass(TriggerSourceScheduled)
+		}
+		return scanned
+	}
+
+	// Two empty scans back it off to every second pass, then every fourth,
+	// which the maximum of four intervals keeps it at
+	assert.Equal(t, []bool{true, true, false, true}, passes(4))
+	assert.Equal(t, []bool{false, false, false, true}, passes(4))
+	assert.Equal(t, []bool{false, false, false, true}, passes(4))
+
+	// Scans asked for through the API are never backed off
+	assert.True(t, pass(TriggerSourceManual))
+	assert.False(t, pass(TriggerSourceScheduled))
+
+	// Growing ends the backoff
+	sizes["cache"] = 10 << 20
+	assert.Equal(t, []bool{false, false, true, true, true, true}, passes(6))
+
+	// and emptying out again starts it over
+	sizes["cache"] = 0
+	assert.Equal(t, []bool{true, true, false, true}, passes(4))
+
+	// Removing the volume forgets its streak
+	scheduler.VolumeRemoved("cache")
+	assert.NotContains(t, scheduler.empty.volumes, "cache")
+	assert.True(t, pass(TriggerSourceScheduled))
+}
+
+func TestEmptyVolumeBackoff_Disabled(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+	scheduler.config.EmptyBackoffMax = time.Hour
+
+	for range 5 {
+		scheduler.recordScanSize("cache", 0)
+		assert.False(t, scheduler.emptyBackedOff("cache", TriggerSourceScheduled))
+	}
+
+	// A maximum under two intervals leaves no pass to skip
+	scheduler.config.EmptyScansBeforeBackoff = 1
+	scheduler.config.EmptyBackoffMax = scheduler.config.Interval
+	assert.False(t, scheduler.emptyBackedOff("cache", TriggerSourceScheduled))
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// Orders in which a batch enqueues its volumes
+const (
+	BatchOrderFair   = "fair"   // Quickest to scan first, from the previous scans
+	BatchOrderListed = "listed" // As the volume provider lists them
+)
+
+// scanCosts remembers how long the last scan of each volume took, so batches
+// can put quick volumes ahead of slow ones
+type scanCosts struct {
+	mu   sync.RWMutex
+	last map[string]time.Duration
+}
+
+// newScanCosts creates an empty scan cost store
+func newScanCosts() *scanCosts {
+	return &scanCosts{last: make(map[string]time.Duration)}
+}
+
+// record stores how long a scan of volumeName took, failed scans included
+// since a scan that timed out is as slow as it gets
+func (c *scanCosts) record(volumeName string, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last[volumeName] = duration
+}
+
+// forget drops the cost recorded for volumeName
+func (c *scanCosts) forget(volumeName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, volumeName)
+}
+
+// order returns volumes quickest to scan first. Volumes not scanned since
+// the scheduler started have no known cost and go first, as do ties, in
+// their listed order; a slow volume thus only holds up the volumes slower
+// than itself. The given slice is not modified.
+func (c *scanCosts) order(volumes []*database.Volume) []*database.Volume {
+	ordered := make([]*database.Volume, len(volumes))
+	copy(ordered, volumes)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return c.last[ordered[i].Name] < c.last[ordered[j].Name]
+	})
+	return ordered
+}
+
+// batchOrder returns the volumes of a batch in the order they are enqueued
+func (s *Scheduler) batchOrder(volumes []*database.Volume) []*database.Volume {
+	if s.config.BatchOrder == BatchOrderListed {
+		return volumes
+	}
+	return s.costs.order(volumes)
+}
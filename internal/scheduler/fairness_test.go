@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanCostsOrder(t *testing.T) {
+	costs := newScanCosts()
+	costs.record("slow", time.Hour)
+	costs.record("quick", time.Second)
+	costs.record("also-quick", time.Second)
+
+	volumes := []*database.Volume{
+		localVolume("slow"), localVolume("quick"), localVolume("new"), localVolume("also-quick"),
+	}
+	var names []string
+	for _, volume := range costs.order(volumes) {
+		names = append(names, volume.Name)
+	}
+
+	// Unknown costs first, ties in listed order
+	assert.Equal(t, []string{"new", "quick", "also-quick", "slow"}, names)
+	assert.Equal(t, "slow", volumes[0].Name, "the listed volumes are left as they were")
+}
+
+func TestVolumeRemovedForgetsScanCost(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+	scheduler.costs.record("slow", time.Hour)
+	scheduler.costs.record("quick", time.Second)
+
+	scheduler.VolumeRemoved("slow")
+	assert.NotContains(t, scheduler.costs.last, "slow")
+	assert.Contains(t, scheduler.costs.last, "quick")
+}
+
+// fairnessScheduler returns a running scheduler with one worker, scanning
+// "giant" in slowScan and every other volume at once. Completed scans are
+// sent to done in the order they finish.
+func fairnessScheduler(t *testing.T, order string, slowScan time.Duration) (*Scheduler, <-chan string) {
+	scheduler, mockScanner, mockRepo, mockProvider, _ := createTestScheduler()
+	scheduler.metricsCollector = nil
+	scheduler.config.Concurrency = 1
+	scheduler.config.BatchOrder = order
+	scheduler.running = true
+	scheduler.ctx, scheduler.cancel = context.WithCancel(context.Background())
+	t.Cleanup(scheduler.cancel)
+
+	volumes := []*database.Volume{localVolume("giant")}
+	for i := range 5 {
+		volumes = append(volumes, localVolume(fmt.Sprintf("fast-%d", i)))
+	}
+	mockProvider.On("ListVolumes", mock.Anything).Return(volumes, nil)
+	mockRepo.On("InsertScanRun", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpdateScanRun", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("InsertVolumeStats", mock.Anything, mock.Anything).Return(nil)
+
+	done := make(chan string, len(volumes))
+	mockScanner.On("ScanVolume", mock.Anything, mock.Anything).Return(&interfaces.ScanResult{TotalSize: 1}, nil).Run(func(args mock.Arguments) {
+		name := args.String(1)
+		if name == "giant" {
+			time.Sleep(slowScan)
+		}
+		done <- name
+	})
+
+	// The previous pass measured every volume
+	scheduler.costs.record("giant", slowScan)
+	for _, volume := range volumes[1:] {
+		scheduler.costs.record(volume.Name, time.Millisecond)
+	}
+
+	w := &worker{id: 0, scheduler: scheduler, ctx: scheduler.ctx, draining: make(chan struct{})}
+	scheduler.workerWG.Add(1)
+	go w.run()
+	return scheduler, done
+}
+
+// completionOrder waits for count scans to finish and returns their volumes
+func completionOrder(t *testing.T, done <-chan string, count int) []string {
+	t.Helper()
+	var names []string
+	for range count {
+		select {
+		case name := <-done:
+			names = append(names, name)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %v finished", names)
+		}
+	}
+	return names
+}
+
+func TestFairBatchScansFastVolumesBeforeSlowOne(t *testing.T) {
+	const slowScan = 300 * time.Millisecond
+	scheduler, done := fairnessScheduler(t, BatchOrderFair, slowScan)
+
+	start := time.Now()
+	_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, 0)
+	require.NoError(t, err)
+
+	names := completionOrder(t, done, 5)
+	assert.NotContains(t, names, "giant")
+	assert.Less(t, time.Since(start), slowScan, "the fast volumes must not wait behind the slow one")
+	assert.Equal(t, "giant", completionOrder(t, done, 1)[0])
+}
+
+func TestListedBatchOrderKeepsSlowVolumeFirst(t *testing.T) {
+	const slowScan = 100 * time.Millisecond
+	scheduler, done := fairnessScheduler(t, BatchOrderListed, slowScan)
+
+	_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "giant", completionOrder(t, done, 6)[0])
+}
+
+func TestWorkerRecordsScanCost(t *testing.T) {
+	scheduler, mockScanner, mockRepo, _, _ := createTestScheduler()
+	scheduler.metricsCollector = nil
+	mockRepo.On("InsertScanRun", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("UpdateScanRun", mock.Anything, mock.Anything).Return(nil)
+	mockScanner.On("ScanVolume", mock.Anything, "flaky").Return(nil, fmt.Errorf("boom")).Run(func(mock.Arguments) {
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	w := &worker{id: 0, scheduler: scheduler, ctx: context.Background()}
+	w.processTask(&ScanTask{ScanID: "scan-1", VolumeName: "flaky", Method: "du", Timeout: time.Minute})
+
+	scheduler.costs.mu.RLock()
+	defer scheduler.costs.mu.RUnlock()
+	assert.GreaterOrEqual(t, scheduler.costs.last["flaky"], 10*time.Millisecond, "failed scans count too")
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// maxJitter bounds the jitter fraction so consecutive scheduled cycles are
+// always at least half an interval apart
+const maxJitter = 0.5
+
+// jitterFraction returns the configured jitter clamped to [0, maxJitter]
+func (s *Scheduler) jitterFraction() float64 {
+	return min(max(s.config.Jitter, 0), maxJitter)
+}
+
+// nextCycleDelay returns the wait before the next scheduled cycle: the
+// interval moved by a uniform random offset of up to the jitter fraction of
+// it either way, so cycles average one interval apart
+func (s *Scheduler) nextCycleDelay() time.Duration {
+	return jitteredInterval(s.config.Interval, s.jitterFraction(), rand.Float64())
+}
+
+// jitteredInterval offsets interval by fraction*interval scaled to r in [0, 1),
+// from -fraction*interval at r=0 towards +fraction*interval
+func jitteredInterval(interval time.Duration, fraction, r float64) time.Duration {
+	if fraction <= 0 {
+		return interval
+	}
+	return interval + time.Duration((2*r-1)*fraction*float64(interval))
+}
+
+// enqueueSpread returns how long a scheduled cycle may take to enqueue its
+// volumes: the jitter fraction of the interval, so a cycle has finished
+// enqueueing before the earliest the next one can start
+func (s *Scheduler) enqueueSpread() time.Duration {
+	return time.Duration(s.jitterFraction() * float64(s.config.Interval))
+}
+
+// enqueueDelay returns a random wait before enqueueing one of count volumes,
+// below spread/count so the waits of a whole cycle add up to less than spread
+func enqueueDelay(spread time.Duration, count int) time.Duration {
+	if spread <= 0 || count <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Float64() * float64(spread) / float64(count))
+}
+
+// waitToEnqueue sleeps for delay before the next volume of a batch is
+// enqueued. It returns false if the scheduler stops or is paused meanwhile,
+// in which case the rest of the batch is not enqueued.
+func (s *Scheduler) waitToEnqueue(delay time.Duration) bool {
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+	return !s.IsPaused()
+}
@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitteredInterval(t *testing.T) {
+	interval := 10 * time.Minute
+
+	assert.Equal(t, interval, jitteredInterval(interval, 0, 0.9))
+	assert.Equal(t, 8*time.Minute, jitteredInterval(interval, 0.2, 0))
+	assert.Equal(t, interval, jitteredInterval(interval, 0.2, 0.5))
+	assert.Equal(t, 11*time.Minute, jitteredInterval(interval, 0.2, 0.75))
+}
+
+func TestNextCycleDelayWithinJitterBound(t *testing.T) {
+	tests := []struct {
+		name     string
+		jitter   float64
+		fraction float64
+	}{
+		{name: "disabled", jitter: 0, fraction: 0},
+		{name: "configured", jitter: 0.2, fraction: 0.2},
+		{name: "clamped", jitter: 0.9, fraction: maxJitter},
+		{name: "negative", jitter: -0.3, fraction: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, _, _, _, _ := createTestScheduler()
+			scheduler.config.Jitter = tt.jitter
+			interval := scheduler.config.Interval
+			bound := time.Duration(tt.fraction * float64(interval))
+
+			delays := make(map[time.Duration]bool)
+			for i := 0; i < 1000; i++ {
+				delay := scheduler.nextCycleDelay()
+				require.GreaterOrEqual(t, delay, interval-bound)
+				require.LessOrEqual(t, delay, interval+bound)
+				delays[delay] = true
+			}
+			if tt.fraction == 0 {
+				assert.Len(t, delays, 1, "without jitter every cycle waits exactly one interval")
+			} else {
+				assert.Greater(t, len(delays), 1, "cycles should be perturbed")
+			}
+		})
+	}
+}
+
+func TestPeriodicCyclesAreJittered(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.config.Interval = 40 * time.Millisecond
+	scheduler.config.Jitter = 0.5
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{}, nil).Maybe()
+
+	stop := runPeriodicLoop(scheduler)
+	defer stop()
+
+	// Record the start of each cycle and when it scheduled the next one
+	var starts, nexts []time.Time
+	require.Eventually(t, func() bool {
+		status := scheduler.GetStatus()
+		if status.LastRunAt != nil && (len(starts) == 0 || !status.LastRunAt.Equal(starts[len(starts)-1])) {
+			starts = append(starts, *status.LastRunAt)
+			nexts = append(nexts, *status.NextRunAt)
+		}
+		return len(starts) == 6
+	}, 5*time.Second, time.Millisecond)
+
+	minDelay, maxDelay := 20*time.Millisecond, 60*time.Millisecond
+	offsets := make(map[time.Duration]bool)
+	for i := range starts {
+		// The next run was timed just before the cycle started
+		delay := nexts[i].Sub(starts[i])
+		assert.GreaterOrEqual(t, delay, minDelay-time.Millisecond)
+		assert.LessOrEqual(t, delay, maxDelay)
+		offsets[delay.Round(time.Millisecond)] = true
+
+		// Timers never fire early, so no cycle starts before its jittered time
+		if i > 0 {
+			assert.GreaterOrEqual(t, starts[i].Sub(starts[i-1]), minDelay-time.Millisecond)
+		}
+	}
+	assert.Greater(t, len(offsets), 1, "cycle times should be perturbed")
+}
+
+func TestEnqueueAllVolumesSpreadsBatch(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("volume-a"),
+		localVolume("volume-b"),
+		localVolume("volume-c"),
+		localVolume("volume-d"),
+	}, nil)
+
+	const spread = 80 * time.Millisecond
+	start := time.Now()
+	_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, spread)
+	require.NoError(t, err)
+	elapsed := time.Since(start)
+
+	// The per-volume waits add up to less than the spread
+	assert.Len(t, scheduler.taskQueue, 4)
+	assert.Less(t, elapsed, spread+50*time.Millisecond)
+
+	var last time.Time
+	for range 4 {
+		task := <-scheduler.taskQueue
+		assert.False(t, task.CreatedAt.Before(last), "volumes are enqueued in order")
+		assert.Less(t, task.CreatedAt.Sub(start), spread)
+		last = task.CreatedAt
+	}
+}
+
+func TestEnqueueAllVolumesSpreadStopsWithScheduler(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx, scheduler.cancel = context.WithCancel(context.Background())
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("volume-a"),
+		localVolume("volume-b"),
+	}, nil)
+
+	// Each volume may wait up to 5s, far longer than the test
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := scheduler.enqueueAllVolumes(TriggerSourceScheduled, 10*time.Second)
+		assert.NoError(t, err)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	scheduler.cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stopping the scheduler should end the batch's wait")
+	}
+	assert.Empty(t, scheduler.taskQueue)
+}
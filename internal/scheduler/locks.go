@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	coremodels "github.com/mantonx/volumeviz/internal/core/models"
+	"github.com/mantonx/volumeviz/internal/database"
+)
+
+// SetScanLocks registers the source of per-volume scan locks. Locked volumes
+// are left out of batch scans, rejected for single scans and not scanned if
+// they were locked while queued, until the lock expires or is released.
+// Call before Start.
+func (s *Scheduler) SetScanLocks(locks ScanLocks) {
+	s.scanLocks = locks
+}
+
+// scanLock returns the lock held on a volume against scanning, or nil. A
+// failed lookup is logged and locks nothing, like a missing lock source.
+func (s *Scheduler) scanLock(volumeName string) *database.ScanLock {
+	if s.scanLocks == nil {
+		return nil
+	}
+	lock, err := s.scanLocks.ActiveScanLock(s.ctx, volumeName, time.Now())
+	if err != nil {
+		log.Printf("[WARN] Could not check whether volume %s is locked against scanning: %v", volumeName, err)
+		return nil
+	}
+	return lock
+}
+
+// scanLockedVolumes returns the volumes locked against scanning now
+func (s *Scheduler) scanLockedVolumes() map[string]database.ScanLock {
+	if s.scanLocks == nil {
+		return nil
+	}
+	locked, err := s.scanLocks.ScanLockedVolumes(s.ctx, time.Now())
+	if err != nil {
+		log.Printf("[WARN] Could not list volumes locked against scanning: %v", err)
+		return nil
+	}
+	return locked
+}
+
+// cancelLockedTask records the run of a task whose volume was locked after it
+// was queued as canceled, without scanning
+func (w *worker) cancelLockedTask(scanRun *database.ScanJob, locked *LockedError) {
+	log.Printf("[INFO] Worker %d not scanning volume %s: %v", w.id, scanRun.VolumeID, locked)
+
+	scanRun.Status = coremodels.ScanStatusCanceled
+	scanRun.CompletedAt = scanRun.StartedAt
+	errorMsg := locked.Error()
+	scanRun.ErrorMessage = &errorMsg
+
+	persistCtx, cancelPersist := w.persistContext()
+	defer cancelPersist()
+	if err := w.scheduler.repository.InsertScanRun(persistCtx, scanRun); err != nil {
+		log.Printf("[ERROR] Worker %d failed to insert scan run: %v", w.id, err)
+	}
+	scansBySourceTotal.WithLabelValues(scanRun.TriggerSource, scanRun.Status).Inc()
+}
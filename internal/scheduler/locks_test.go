@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScanLocks holds scan locks the test takes and releases
+type fakeScanLocks struct {
+	mu    sync.Mutex
+	locks map[string]database.ScanLock
+}
+
+func (f *fakeScanLocks) lock(volumeName string, lock database.ScanLock) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.locks == nil {
+		f.locks = make(map[string]database.ScanLock)
+	}
+	f.locks[volumeName] = lock
+}
+
+func (f *fakeScanLocks) unlock(volumeName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.locks, volumeName)
+}
+
+func (f *fakeScanLocks) ActiveScanLock(ctx context.Context, volumeName string, now time.Time) (*database.ScanLock, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lock, ok := f.locks[volumeName]
+	if !ok || !lock.Active(now) {
+		return nil, nil
+	}
+	return &lock, nil
+}
+
+func (f *fakeScanLocks) ScanLockedVolumes(ctx context.Context, now time.Time) (map[string]database.ScanLock, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	locked := make(map[string]database.ScanLock)
+	for name, lock := range f.locks {
+		if lock.Active(now) {
+			locked[name] = lock
+		}
+	}
+	return locked, nil
+}
+
+func TestEnqueueSkipsLockedVolumes(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	locks := &fakeScanLocks{}
+	scheduler.SetScanLocks(locks)
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("app-data"),
+		localVolume("postgres-data"),
+	}, nil)
+	mockProvider.On("GetVolume", mock.Anything, "postgres-data").Return(localVolume("postgres-data"), nil)
+
+	backup := database.ScanLock{Until: time.Now().Add(time.Hour), Holder: "pg-backup"}
+	locks.lock("postgres-data", backup)
+
+	_, err := scheduler.EnqueueAllVolumes()
+	require.NoError(t, err)
+	assert.Len(t, scheduler.taskQueue, 1)
+	assert.Equal(t, "app-data", (<-scheduler.taskQueue).VolumeName)
+
+	// Forcing a scan does not break someone else's lock
+	scanID, err := scheduler.EnqueueVolumeWithOptions("postgres-data", EnqueueOptions{Force: true, OverrideScanDisabled: true})
+	var locked *LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, "pg-backup", locked.Lock.Holder)
+	assert.Contains(t, err.Error(), "locked against scanning by pg-backup until")
+	assert.Empty(t, scanID)
+	assert.Empty(t, scheduler.taskQueue)
+
+	// Released, the volume is scanned again
+	locks.unlock("postgres-data")
+	scanID, err = scheduler.EnqueueVolume("postgres-data")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+	assert.Equal(t, "postgres-data", (<-scheduler.taskQueue).VolumeName)
+}
+
+func TestEnqueueScansVolumesOnceLockExpires(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	locks := &fakeScanLocks{}
+	scheduler.SetScanLocks(locks)
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	mockProvider.On("GetVolume", mock.Anything, "postgres-data").Return(localVolume("postgres-data"), nil)
+
+	locks.lock("postgres-data", database.ScanLock{Until: time.Now().Add(50 * time.Millisecond)})
+	_, err := scheduler.EnqueueVolume("postgres-data")
+	var locked *LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Empty(t, scheduler.taskQueue)
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = scheduler.EnqueueVolume("postgres-data")
+	require.NoError(t, err)
+	assert.Len(t, scheduler.taskQueue, 1)
+}
+
+func TestWorkerCancelsTaskLockedWhileQueued(t *testing.T) {
+	scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+	locks := &fakeScanLocks{}
+	scheduler.SetScanLocks(locks)
+	scheduler.ctx = context.Background()
+	worker := &worker{id: 0, scheduler: scheduler, ctx: context.Background()}
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	var recorded *database.ScanJob
+	mockRepo.On("InsertScanRun", mock.Anything, mock.AnythingOfType("*database.ScanJob")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(*database.ScanJob) }).
+		Return(nil).Once()
+
+	locks.lock("postgres-data", database.ScanLock{Until: time.Now().Add(time.Hour), Holder: "pg-backup"})
+	worker.processTask(&ScanTask{
+		ScanID:        "scan-1",
+		VolumeName:    "postgres-data",
+		Method:        "du",
+		Timeout:       time.Minute,
+		TriggerSource: TriggerSourceScheduled,
+	})
+
+	// The mock scanner would fail the test if it were asked to scan
+	mockScanner.AssertNotCalled(t, "ScanVolume", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+	require.NotNil(t, recorded)
+	assert.Equal(t, "canceled", recorded.Status)
+	require.NotNil(t, recorded.ErrorMessage)
+	assert.Contains(t, *recorded.ErrorMessage, "locked against scanning by pg-backup")
+	assert.NotNil(t, recorded.CompletedAt)
+}
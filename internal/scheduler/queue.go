@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// QueuedTask is a read-only view of a task waiting in the scan queue
+type QueuedTask struct {
+	Position      int       `json:"position"` // 1 is the next task a worker takes
+	ScanID        string    `json:"scan_id"`
+	VolumeName    string    `json:"volume_name"`
+	Method        string    `json:"method"`
+	Priority      int       `json:"priority"`
+	EnqueuedAt    time.Time `json:"enqueued_at"`
+	TriggerSource string    `json:"trigger_source"`
+}
+
+// queueIndex mirrors the tasks waiting in the scan queue, which as a channel
+// cannot be looked into, in the order workers take them
+type queueIndex struct {
+	mu    sync.Mutex
+	tasks []*ScanTask
+}
+
+// offer sends task to queue without blocking, recording it on success. The
+// send happens under the lock so the index keeps the order of the channel.
+func (q *queueIndex) offer(queue chan<- *ScanTask, task *ScanTask) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	select {
+	case queue <- task:
+		q.tasks = append(q.tasks, task)
+		return true
+	default:
+		return false
+	}
+}
+
+// taken drops a task a worker took off the queue
+func (q *queueIndex) taken(task *ScanTask) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, queued := range q.tasks {
+		if queued == task {
+			q.tasks = append(q.tasks[:i], q.tasks[i+1:]...)
+			return
+		}
+	}
+}
+
+// snapshot returns up to limit queued tasks in the order workers take them,
+// and how many tasks are queued in all
+func (q *queueIndex) snapshot(limit int) ([]QueuedTask, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(q.tasks)
+	if limit >= 0 {
+		n = min(n, limit)
+	}
+	tasks := make([]QueuedTask, n)
+	for i, task := range q.tasks[:n] {
+		tasks[i] = QueuedTask{
+			Position:      i + 1,
+			ScanID:        task.ScanID,
+			VolumeName:    task.VolumeName,
+			Method:        task.Method,
+			Priority:      task.Priority,
+			EnqueuedAt:    task.CreatedAt,
+			TriggerSource: task.TriggerSource,
+		}
+	}
+	return tasks, len(q.tasks)
+}
+
+// QueuedTasks returns up to limit of the tasks waiting in the scan queue, in
+// the order workers take them, and how many are queued in all. The queue is
+// first-in first-out: priority is reported but does not reorder it. A
+// negative limit returns every task.
+func (s *Scheduler) QueuedTasks(limit int) ([]QueuedTask, int) {
+	return s.queued.snapshot(limit)
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueuedTasksSnapshot(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("app-data"),
+		localVolume("logs"),
+	}, nil)
+	mockProvider.On("GetVolume", mock.Anything, "postgres-data").Return(localVolume("postgres-data"), nil)
+	mockProvider.On("GetVolume", mock.Anything, "uploads").Return(localVolume("uploads"), nil)
+
+	tasks, total := scheduler.QueuedTasks(-1)
+	assert.Empty(t, tasks)
+	assert.Zero(t, total)
+
+	manualID, err := scheduler.EnqueueVolume("postgres-data")
+	require.NoError(t, err)
+	_, err = scheduler.EnqueueAllVolumes()
+	require.NoError(t, err)
+	eventID, err := scheduler.EnqueueEventScan("uploads")
+	require.NoError(t, err)
+
+	tasks, total = scheduler.QueuedTasks(-1)
+	require.Equal(t, 4, total)
+	require.Len(t, tasks, 4)
+	volumes := make([]string, len(tasks))
+	for i, task := range tasks {
+		volumes[i] = task.VolumeName
+		assert.Equal(t, i+1, task.Position)
+		assert.NotEmpty(t, task.Method)
+		assert.False(t, task.EnqueuedAt.IsZero())
+	}
+	// First in, first out: the later manual-priority event scan waits behind the batch
+	assert.Equal(t, []string{"postgres-data", "app-data", "logs", "uploads"}, volumes)
+	assert.Equal(t, QueuedTask{
+		Position:      1,
+		ScanID:        manualID,
+		VolumeName:    "postgres-data",
+		Method:        tasks[0].Method,
+		Priority:      1,
+		EnqueuedAt:    tasks[0].EnqueuedAt,
+		TriggerSource: TriggerSourceManual,
+	}, tasks[0])
+	assert.Equal(t, 0, tasks[1].Priority)
+	assert.Equal(t, TriggerSourceManual, tasks[1].TriggerSource)
+	assert.Equal(t, eventID, tasks[3].ScanID)
+	assert.Equal(t, TriggerSourceEvent, tasks[3].TriggerSource)
+
+	// A capped snapshot still reports the full depth
+	tasks, total = scheduler.QueuedTasks(2)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, 4, total)
+
+	// Tasks a worker takes drop out and the rest move up
+	scheduler.queued.taken(<-scheduler.taskQueue)
+	tasks, total = scheduler.QueuedTasks(-1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "app-data", tasks[0].VolumeName)
+	assert.Equal(t, 1, tasks[0].Position)
+}
@@ -26,8 +26,8 @@ func NewRepository(db *database.DB) *Repository {
 // InsertVolumeStats inserts a new volume statistics record
 func (r *Repository) InsertVolumeStats(ctx context.Context, stats *database.VolumeScanStats) error {
 	query := `
-		INSERT INTO volume_stats (volume_name, size_bytes, file_count, scan_method, duration_ms, ts, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+		INSERT INTO volume_stats (volume_name, size_bytes, file_count, scan_method, scan_accuracy, duration_ms, ts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 	
 	now := time.Now()
 	_, err := r.db.ExecContext(ctx, query,
@@ -35,6 +35,7 @@ func (r *Repository) InsertVolumeStats(ctx context.Context, stats *database.Volu
 		stats.SizeBytes,
 		stats.FileCount,
 		stats.ScanMethod,
+		stats.ScanAccuracy,
 		stats.DurationMs,
 		stats.Timestamp,
 		now,
@@ -48,10 +49,54 @@ func (r *Repository) InsertVolumeStats(ctx context.Context, stats *database.Volu
 	return nil
 }
 
+// InsertVolumeStatsBatch inserts volume statistics records in one transaction;
+// either all of them are stored or none
+func (r *Repository) InsertVolumeStatsBatch(ctx context.Context, batch []*database.VolumeScanStats) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO volume_stats (volume_name, size_bytes, file_count, scan_method, scan_accuracy, duration_ms, ts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare volume stats insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, stats := range batch {
+		if _, err := stmt.ExecContext(ctx,
+			stats.VolumeName,
+			stats.SizeBytes,
+			stats.FileCount,
+			stats.ScanMethod,
+			stats.ScanAccuracy,
+			stats.DurationMs,
+			stats.Timestamp,
+			now,
+			now,
+		); err != nil {
+			return fmt.Errorf("failed to insert volume stats for %s: %w", stats.VolumeName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit volume stats: %w", err)
+	}
+	return nil
+}
+
 // GetVolumeStatsByName retrieves volume statistics for a specific volume
 func (r *Repository) GetVolumeStatsByName(ctx context.Context, volumeName string, limit int) ([]*database.VolumeScanStats, error) {
 	query := `
-		SELECT id, volume_name, size_bytes, file_count, scan_method, duration_ms, ts, created_at, updated_at
+		SELECT id, volume_name, size_bytes, file_count, scan_method, scan_accuracy, duration_ms, ts, created_at, updated_at
 		FROM volume_stats 
 		WHERE volume_name = $1 
 		ORDER BY ts DESC 
@@ -72,6 +117,7 @@ func (r *Repository) GetVolumeStatsByName(ctx context.Context, volumeName string
 			&stat.SizeBytes,
 			&stat.FileCount,
 			&stat.ScanMethod,
+			&stat.ScanAccuracy,
 			&stat.DurationMs,
 			&stat.Timestamp,
 			&stat.CreatedAt,
@@ -86,6 +132,56 @@ func (r *Repository) GetVolumeStatsByName(ctx context.Context, volumeName string
 	return stats, rows.Err()
 }
 
+// WalkVolumeStats calls fn for each statistics record of a volume scanned in
+// [since, until), oldest first. Rows are read one at a time, so a volume's full
+// history never has to fit in memory. A zero since or until leaves that end open.
+func (r *Repository) WalkVolumeStats(ctx context.Context, volumeName string, since, until time.Time, fn func(*database.VolumeScanStats) error) error {
+	query := `
+		SELECT id, volume_name, size_bytes, file_count, scan_method, scan_accuracy, duration_ms, ts, created_at, updated_at
+		FROM volume_stats
+		WHERE volume_name = $1`
+	args := []interface{}{volumeName}
+	if !since.IsZero() {
+		args = append(args, since)
+		query += fmt.Sprintf(" AND ts >= $%d", len(args))
+	}
+	if !until.IsZero() {
+		args = append(args, until)
+		query += fmt.Sprintf(" AND ts < $%d", len(args))
+	}
+	query += " ORDER BY ts ASC, id ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query volume stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stat := &database.VolumeScanStats{}
+		err := rows.Scan(
+			&stat.ID,
+			&stat.VolumeName,
+			&stat.SizeBytes,
+			&stat.FileCount,
+			&stat.ScanMethod,
+			&stat.ScanAccuracy,
+			&stat.DurationMs,
+			&stat.Timestamp,
+			&stat.CreatedAt,
+			&stat.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan volume stats row: %w", err)
+		}
+		if err := fn(stat); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // GetLatestVolumeStats retrieves the latest volume statistics for a specific volume
 func (r *Repository) GetLatestVolumeStats(ctx context.Context, volumeName string) (*database.VolumeScanStats, error) {
 	stats, err := r.GetVolumeStatsByName(ctx, volumeName, 1)
@@ -100,13 +196,72 @@ func (r *Repository) GetLatestVolumeStats(ctx context.Context, volumeName string
 	return stats[0], nil
 }
 
+// RecentSizes returns the sizes of the latest scans of a volume, newest first
+func (r *Repository) RecentSizes(ctx context.Context, volumeName string, limit int) ([]int64, error) {
+	stats, err := r.GetVolumeStatsByName(ctx, volumeName, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make([]int64, len(stats))
+	for i, stat := range stats {
+		sizes[i] = stat.SizeBytes
+	}
+	return sizes, nil
+}
+
+// GetLatestVolumeStatsByVolume retrieves the latest statistics of every scanned
+// volume in one query, keyed by volume name
+func (r *Repository) GetLatestVolumeStatsByVolume(ctx context.Context) (map[string]*database.VolumeScanStats, error) {
+	query := `
+		SELECT s.id, s.volume_name, s.size_bytes, s.file_count, s.scan_method, s.scan_accuracy, s.duration_ms, s.ts, s.created_at, s.updated_at
+		FROM volume_stats s
+		JOIN (
+			SELECT volume_name, MAX(ts) AS ts
+			FROM volume_stats
+			GROUP BY volume_name
+		) latest ON latest.volume_name = s.volume_name AND latest.ts = s.ts`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest volume stats: %w", err)
+	}
+	defer rows.Close()
+
+	latest := make(map[string]*database.VolumeScanStats)
+	for rows.Next() {
+		stat := &database.VolumeScanStats{}
+		err := rows.Scan(
+			&stat.ID,
+			&stat.VolumeName,
+			&stat.SizeBytes,
+			&stat.FileCount,
+			&stat.ScanMethod,
+			&stat.ScanAccuracy,
+			&stat.DurationMs,
+			&stat.Timestamp,
+			&stat.CreatedAt,
+			&stat.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan volume stats row: %w", err)
+		}
+		// Scans recorded at the same instant resolve to the last inserted
+		if current, ok := latest[stat.VolumeName]; !ok || stat.ID > current.ID {
+			latest[stat.VolumeName] = stat
+		}
+	}
+
+	return latest, rows.Err()
+}
+
 // Scan runs operations
 
 // InsertScanRun inserts a new scan run record
 func (r *Repository) InsertScanRun(ctx context.Context, run *database.ScanJob) error {
 	query := `
-		INSERT INTO scan_runs (scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+		INSERT INTO scan_runs (scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, trigger_source, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 	
 	now := time.Now()
 	_, err := r.db.ExecContext(ctx, query,
@@ -120,6 +275,7 @@ func (r *Repository) InsertScanRun(ctx context.Context, run *database.ScanJob) e
 		run.ErrorMessage,
 		run.ResultID,
 		run.EstimatedDuration,
+		run.TriggerSource,
 		now,
 		now,
 	)
@@ -168,7 +324,7 @@ func (r *Repository) UpdateScanRun(ctx context.Context, run *database.ScanJob) e
 // GetScanRunByID retrieves a scan run by its ID
 func (r *Repository) GetScanRunByID(ctx context.Context, scanID string) (*database.ScanJob, error) {
 	query := `
-		SELECT id, scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, created_at, updated_at
+		SELECT id, scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, trigger_source, created_at, updated_at
 		FROM scan_runs 
 		WHERE scan_id = $1`
 	
@@ -187,6 +343,7 @@ func (r *Repository) GetScanRunByID(ctx context.Context, scanID string) (*databa
 		&run.ErrorMessage,
 		&run.ResultID,
 		&run.EstimatedDuration,
+		&run.TriggerSource,
 		&run.CreatedAt,
 		&run.UpdatedAt,
 	)
@@ -204,7 +361,7 @@ func (r *Repository) GetScanRunByID(ctx context.Context, scanID string) (*databa
 // GetActiveScanRuns retrieves all currently active scan runs
 func (r *Repository) GetActiveScanRuns(ctx context.Context) ([]*database.ScanJob, error) {
 	query := `
-		SELECT id, scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, created_at, updated_at
+		SELECT id, scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, trigger_source, created_at, updated_at
 		FROM scan_runs 
 		WHERE status IN ('queued', 'running')
 		ORDER BY created_at DESC`
@@ -230,6 +387,51 @@ func (r *Repository) GetActiveScanRuns(ctx context.Context) ([]*database.ScanJob
 			&run.ErrorMessage,
 			&run.ResultID,
 			&run.EstimatedDuration,
+			&run.TriggerSource,
+			&run.CreatedAt,
+			&run.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scan run row: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	
+	return runs, rows.Err()
+}
+
+// ListRecentScanRuns retrieves the most recently started scan runs, newest
+// first. An empty triggerSource lists runs of every source.
+func (r *Repository) ListRecentScanRuns(ctx context.Context, limit int, triggerSource string) ([]*database.ScanJob, error) {
+	query := `
+		SELECT id, scan_id, volume_id, status, progress, method, started_at, completed_at, error_message, result_id, estimated_duration, trigger_source, created_at, updated_at
+		FROM scan_runs 
+		WHERE ($1 = '' OR trigger_source = $1)
+		ORDER BY started_at DESC
+		LIMIT $2`
+	
+	rows, err := r.db.QueryContext(ctx, query, triggerSource, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent scan runs: %w", err)
+	}
+	defer rows.Close()
+	
+	var runs []*database.ScanJob
+	for rows.Next() {
+		run := &database.ScanJob{}
+		err := rows.Scan(
+			&run.ID,
+			&run.ScanID,
+			&run.VolumeID,
+			&run.Status,
+			&run.Progress,
+			&run.Method,
+			&run.StartedAt,
+			&run.CompletedAt,
+			&run.ErrorMessage,
+			&run.ResultID,
+			&run.EstimatedDuration,
+			&run.TriggerSource,
 			&run.CreatedAt,
 			&run.UpdatedAt,
 		)
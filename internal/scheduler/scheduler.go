@@ -4,17 +4,27 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"regexp"
+	"slices"
 	"sync"
 	"time"
-	"math/rand"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	coremodels "github.com/mantonx/volumeviz/internal/core/models"
 	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// scansBySourceTotal counts finished scheduler scans by trigger source and status
+var scansBySourceTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "volumeviz_scheduler_scans_total",
+	Help: "Total number of scheduler scans finished, by trigger source (scheduled, startup, manual, event) and status (completed, failed)",
+}, []string{"source", "status"})
+
 // Scheduler implements the ScanScheduler interface
 type Scheduler struct {
 	config         *SchedulerConfig
@@ -25,11 +35,16 @@ type Scheduler struct {
 	
 	// Worker pool and queue
 	taskQueue      chan *ScanTask
+	queued         queueIndex // What taskQueue holds, for QueuedTasks
 	workers        []*worker
 	workerWG       sync.WaitGroup
 	
 	// Scheduler state
 	running        bool
+	paused         bool
+	pausedAt       *time.Time
+	allowManual    bool          // Single-volume scans accepted while paused
+	pauseChanged   chan struct{} // Wakes the periodic scheduler on pause/resume
 	ctx            context.Context
 	cancel         context.CancelFunc
 	schedulerWG    sync.WaitGroup
@@ -38,12 +53,47 @@ type Scheduler struct {
 	metrics        *SchedulerMetrics
 	status         *SchedulerStatus
 	statusMutex    sync.RWMutex
+	// Scan durations per method, from which metrics.ScanDurations is averaged
+	durationStats  map[string]*durationStat
 	
 	// Skip pattern regex
-	skipPattern    *regexp.Regexp
+	skipPattern    *utils.Pattern
+	
+	// Drivers whose volumes cannot be sized
+	sizePolicy     *config.SizePolicy
+	
+	// Measured scan method speed per filesystem (auto-benchmarking)
+	benchmarks     *methodBenchmarks
+	
+	// Optional receiver of scanned sizes
+	sizeReporter   SizeReporter
+	
+	// Optional per-volume scan switches
+	scanToggles    ScanToggles
+	methodDenials  MethodDenials
+	scanLocks      ScanLocks
+	
+	// Batches volume stats inserts; nil inserts each scan's stats directly
+	statsWriter    *statsWriter
+	
+	// Closed by Stop so workers take no new tasks while running scans finish
+	draining       chan struct{}
+	
+	// Guards config.MethodsOrder, which can be changed at runtime
+	methodsMutex   sync.RWMutex
+	
+	// Signalled by workers as they take a task, for paced batch enqueues
+	dequeued       chan struct{}
+	
+	// Last scan duration per volume, for fair batch ordering
+	costs          *scanCosts
+	
+	// Volumes whose scans keep finding them empty, scanned less often
+	empty          *emptyVolumes
 	
 	// Rate limiting
 	lastEnqueueAll time.Time
+	lastVolumeScan map[string]time.Time // Last enqueue time per volume
 	rateLimitMutex sync.Mutex
 }
 
@@ -52,6 +102,7 @@ type worker struct {
 	id        int
 	scheduler *Scheduler
 	ctx       context.Context
+	draining  <-chan struct{} // Closed when the scheduler stops taking new tasks
 }
 
 // NewScheduler creates a new scan scheduler
@@ -63,9 +114,9 @@ func NewScheduler(
 	metricsCollector interfaces.MetricsCollector,
 ) (*Scheduler, error) {
 	// Compile skip pattern if provided
-	var skipPattern *regexp.Regexp
+	var skipPattern *utils.Pattern
 	if config.SkipPattern != "" {
-		compiled, err := regexp.Compile(config.SkipPattern)
+		compiled, err := utils.CompilePattern(config.SkipPattern)
 		if err != nil {
 			return nil, fmt.Errorf("invalid skip pattern %q: %w", config.SkipPattern, err)
 		}
@@ -80,9 +131,16 @@ func NewScheduler(
 		metricsCollector: metricsCollector,
 		taskQueue:        make(chan *ScanTask, config.QueueSize),
 		skipPattern:      skipPattern,
+		sizePolicy:       config.SizePolicy(),
+		lastVolumeScan:   make(map[string]time.Time),
+		benchmarks:       newMethodBenchmarks(),
+		pauseChanged:     make(chan struct{}, 1),
+		dequeued:         make(chan struct{}, 1),
+		costs:            newScanCosts(),
+		empty:            newEmptyVolumes(),
+		durationStats:    make(map[string]*durationStat),
 		metrics: &SchedulerMetrics{
 			CompletedScans: make(map[string]int64),
-			ScanDurations:  make(map[string]float64),
 			ErrorCounts:    make(map[string]int64),
 		},
 		status: &SchedulerStatus{
@@ -90,9 +148,37 @@ func NewScheduler(
 		},
 	}
 	
+	if config.StatsBatchSize > 1 {
+		if batcher, ok := repository.(StatsBatchInserter); ok {
+			scheduler.statsWriter = newStatsWriter(repository, batcher, config.StatsBatchSize, config.StatsFlushInterval)
+		} else {
+			log.Printf("[WARN] Scan stats batching enabled but the repository does not support batch inserts; inserting stats one by one")
+		}
+	}
+	
 	return scheduler, nil
 }
 
+// SetSizeReporter registers a receiver for the size of each completed scan.
+// Call before Start.
+func (s *Scheduler) SetSizeReporter(reporter SizeReporter) {
+	s.sizeReporter = reporter
+}
+
+// SetScanToggles registers the source of per-volume scan switches. Volumes
+// switched off are left out of batch scans and rejected for single scans
+// unless overridden. Call before Start.
+func (s *Scheduler) SetScanToggles(toggles ScanToggles) {
+	s.scanToggles = toggles
+}
+
+// SetMethodDenials registers the source of per-volume scan method denials.
+// Denied methods are never chosen for, or fallen back to on, that volume.
+// Call before Start.
+func (s *Scheduler) SetMethodDenials(denials MethodDenials) {
+	s.methodDenials = denials
+}
+
 // Start starts the scan scheduler
 func (s *Scheduler) Start(ctx context.Context) error {
 	if !s.config.Enabled {
@@ -112,6 +198,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	s.statusMutex.Unlock()
 	
 	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.draining = make(chan struct{})
 	
 	log.Printf("[INFO] Starting scan scheduler (interval: %v, concurrency: %d, queue size: %d)",
 		s.config.Interval, s.config.Concurrency, s.config.QueueSize)
@@ -130,6 +217,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 			id:        i,
 			scheduler: s,
 			ctx:       s.ctx,
+			draining:  s.draining,
 		}
 		s.workerWG.Add(1)
 		go s.workers[i].run()
@@ -139,10 +227,31 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	s.schedulerWG.Add(1)
 	go s.runPeriodicScheduler()
 	
+	// Flush batched volume stats on their interval
+	if s.statsWriter != nil {
+		s.schedulerWG.Add(1)
+		go func() {
+			defer s.schedulerWG.Done()
+			s.statsWriter.run(s.ctx)
+		}()
+	}
+	
+	// Start method benchmarking if enabled and supported by the scanner
+	if s.config.AutoBenchmark {
+		if benchmarker, ok := s.scanner.(interfaces.MethodBenchmarker); ok {
+			s.schedulerWG.Add(1)
+			go s.runBenchmarkLoop(benchmarker)
+		} else {
+			log.Printf("[WARN] Scan method auto-benchmarking enabled but scanner does not support it; using configured order")
+		}
+	}
+	
 	return nil
 }
 
-// Stop stops the scan scheduler
+// Stop stops the scan scheduler. New scans are refused and queued ones are
+// not started, while scans already running get until ctx expires to finish;
+// only then are they cancelled. Buffered stats are committed last.
 func (s *Scheduler) Stop(ctx context.Context) error {
 	s.statusMutex.Lock()
 	if !s.running {
@@ -150,6 +259,9 @@ func (s *Scheduler) Stop(ctx context.Context) error {
 		return nil
 	}
 	s.running = false
+	s.paused = false
+	s.pausedAt = nil
+	s.allowManual = false
 	s.statusMutex.Unlock()
 	
 	log.Printf("[INFO] Stopping scan scheduler...")
@@ -161,27 +273,56 @@ func (s *Scheduler) Stop(ctx context.Context) error {
 		s.metricsCollector.UpdateSchedulerWorkerUtilization(0.0)
 	}
 	
-	// Cancel context to stop all workers
+	// Let workers finish the scans they are running before cancelling them
+	if s.draining != nil {
+		close(s.draining)
+		s.draining = nil
+	}
+	if !waitGroupsDone(ctx, &s.workerWG) {
+		log.Printf("[WARN] Scans still running at the stop deadline are cancelled")
+	}
+	if queued := len(s.taskQueue); queued > 0 {
+		log.Printf("[INFO] %d queued scans were not started", queued)
+	}
+	
+	// Cancel context to stop the periodic scheduler and any scan still running
 	if s.cancel != nil {
 		s.cancel()
 	}
 	
-	// Wait for scheduler and workers to finish
+	if waitGroupsDone(ctx, &s.schedulerWG, &s.workerWG) {
+		log.Printf("[INFO] Scan scheduler stopped")
+	} else {
+		log.Printf("[WARN] Scan scheduler stop timeout")
+	}
+	
+	// Commit the stats of scans completed since the last flush
+	if s.statsWriter != nil {
+		if pending := s.statsWriter.buffered(); pending > 0 {
+			log.Printf("[INFO] Flushing %d buffered volume stats", pending)
+			s.statsWriter.flush()
+		}
+	}
+	
+	return nil
+}
+
+// waitGroupsDone waits for every group, returning false if ctx expires first
+func waitGroupsDone(ctx context.Context, groups ...*sync.WaitGroup) bool {
 	done := make(chan struct{})
 	go func() {
-		s.schedulerWG.Wait()
-		s.workerWG.Wait()
+		for _, group := range groups {
+			group.Wait()
+		}
 		close(done)
 	}()
 	
 	select {
 	case <-done:
-		log.Printf("[INFO] Scan scheduler stopped")
+		return true
 	case <-ctx.Done():
-		log.Printf("[WARN] Scan scheduler stop timeout")
+		return false
 	}
-	
-	return nil
 }
 
 // IsRunning returns whether the scheduler is currently running
@@ -200,10 +341,88 @@ func (s *Scheduler) GetStatus() *SchedulerStatus {
 	status := *s.status
 	status.QueueDepth = len(s.taskQueue)
 	status.Running = s.running
+	status.Paused = s.paused
+	status.PausedAt = s.pausedAt
+	status.AllowManualScans = s.paused && s.allowManual
+	if s.paused {
+		status.NextRunAt = nil
+	}
 	
 	return &status
 }
 
+// Pause halts periodic and batch scanning until Resume. Workers, queued tasks and
+// the rest of the service keep running; allowManualScans keeps single-volume
+// scans available while paused.
+func (s *Scheduler) Pause(allowManualScans bool) error {
+	s.statusMutex.Lock()
+	if !s.running {
+		s.statusMutex.Unlock()
+		return fmt.Errorf("scheduler not running")
+	}
+	if !s.paused {
+		now := time.Now()
+		s.pausedAt = &now
+	}
+	s.paused = true
+	s.allowManual = allowManualScans
+	s.statusMutex.Unlock()
+	
+	log.Printf("[INFO] Scan scheduler paused (manual scans allowed: %v)", allowManualScans)
+	
+	if s.metricsCollector != nil {
+		s.metricsCollector.SetSchedulerPausedStatus(true)
+	}
+	s.notifyPauseChanged()
+	return nil
+}
+
+// Resume restarts periodic and batch scanning after Pause
+func (s *Scheduler) Resume() error {
+	s.statusMutex.Lock()
+	if !s.running {
+		s.statusMutex.Unlock()
+		return fmt.Errorf("scheduler not running")
+	}
+	wasPaused := s.paused
+	s.paused = false
+	s.pausedAt = nil
+	s.allowManual = false
+	if wasPaused {
+		// The ticker restarts from now
+		next := time.Now().Add(s.config.Interval)
+		s.status.NextRunAt = &next
+	}
+	s.statusMutex.Unlock()
+	
+	if !wasPaused {
+		return nil
+	}
+	
+	log.Printf("[INFO] Scan scheduler resumed")
+	
+	if s.metricsCollector != nil {
+		s.metricsCollector.SetSchedulerPausedStatus(false)
+	}
+	s.notifyPauseChanged()
+	return nil
+}
+
+// IsPaused returns whether periodic and batch scanning is paused
+func (s *Scheduler) IsPaused() bool {
+	s.statusMutex.RLock()
+	defer s.statusMutex.RUnlock()
+	return s.paused
+}
+
+// notifyPauseChanged wakes the periodic scheduler without blocking
+func (s *Scheduler) notifyPauseChanged() {
+	select {
+	case s.pauseChanged <- struct{}{}:
+	default:
+	}
+}
+
 // GetMetrics returns current scheduler metrics
 func (s *Scheduler) GetMetrics() *SchedulerMetrics {
 	s.statusMutex.RLock()
@@ -217,69 +436,152 @@ func (s *Scheduler) GetMetrics() *SchedulerMetrics {
 		ScanDurations:     make(map[string]float64),
 		ErrorCounts:       make(map[string]int64),
 		WorkerUtilization: s.calculateWorkerUtilization(),
+		Paused:            s.paused,
 	}
 	
 	// Copy maps
 	for k, v := range s.metrics.CompletedScans {
 		metrics.CompletedScans[k] = v
 	}
-	for k, v := range s.metrics.ScanDurations {
-		metrics.ScanDurations[k] = v
+	for method, stat := range s.durationStats {
+		metrics.ScanDurations[method] = stat.mean()
 	}
 	for k, v := range s.metrics.ErrorCounts {
 		metrics.ErrorCounts[k] = v
 	}
+	if s.config.AutoBenchmark {
+		metrics.MethodBenchmarks = s.benchmarks.snapshot()
+	}
 	
 	return metrics
 }
 
 // EnqueueVolume enqueues a single volume for scanning
 func (s *Scheduler) EnqueueVolume(volumeName string) (string, error) {
+	return s.EnqueueVolumeWithOptions(volumeName, EnqueueOptions{})
+}
+
+// EnqueueEventScan enqueues a single volume for a scan triggered by a Docker event
+func (s *Scheduler) EnqueueEventScan(volumeName string) (string, error) {
+	return s.EnqueueVolumeWithOptions(volumeName, EnqueueOptions{TriggerSource: TriggerSourceEvent})
+}
+
+// EnqueueVolumeWithOptions enqueues a single volume for scanning. A volume enqueued
+// within the minimum interval is rejected with a *ThrottledError unless forced.
+func (s *Scheduler) EnqueueVolumeWithOptions(volumeName string, opts EnqueueOptions) (string, error) {
 	if !s.IsRunning() {
 		return "", fmt.Errorf("scheduler not running")
 	}
 	
+	s.statusMutex.RLock()
+	rejectPaused := s.paused && !s.allowManual
+	s.statusMutex.RUnlock()
+	if rejectPaused {
+		return "", ErrSchedulerPaused
+	}
+	
 	// Check if volume should be skipped
 	if s.shouldSkipVolume(volumeName) {
 		return "", fmt.Errorf("volume %s matches skip pattern", volumeName)
 	}
 	
+	// Check if scanning of the volume was switched off
+	if s.scanToggles != nil && !opts.OverrideScanDisabled {
+		enabled, err := s.scanToggles.IsScanEnabled(s.ctx, volumeName)
+		if err != nil {
+			log.Printf("[WARN] Could not check whether scanning of volume %s is enabled: %v", volumeName, err)
+		} else if !enabled {
+			return "", fmt.Errorf("volume %s: %w", volumeName, ErrScanDisabled)
+		}
+	}
+	
+	// Check if a backup or the like holds the volume
+	if lock := s.scanLock(volumeName); lock != nil {
+		return "", &LockedError{VolumeName: volumeName, Lock: *lock}
+	}
+	
 	// Check if volume allows bind mount scanning if it's a bind mount
 	if s.isBindMount(volumeName) && !s.isBindMountAllowed(volumeName) {
 		return "", fmt.Errorf("bind mount %s not in allow list", volumeName)
 	}
 	
+	// Look up the volume: its driver, mountpoint and labels affect the scan
+	var labels database.Labels
+	volume, err := s.volumeProvider.GetVolume(s.ctx, volumeName)
+	if err != nil {
+		log.Printf("[WARN] Could not look up volume %s: %v", volumeName, err)
+	} else if volume != nil {
+		// Check if the volume driver can report a size at all
+		if !s.sizePolicy.SizeSupported(volume.Driver) {
+			return "", fmt.Errorf("volume %s uses driver %s which does not support size scanning", volumeName, volume.Driver)
+		}
+		if scannable, reason := s.sizePolicy.Scannable(volume.Mountpoint, volume.Options); !scannable {
+			return "", fmt.Errorf("volume %s is not scannable: %s", volumeName, reason)
+		}
+		labels = volume.Labels
+	}
+	
+	// Coalesce rapid re-requests for the same volume
+	prev, hadPrev, retryAfter := s.claimVolumeScan(volumeName, opts.Force)
+	if retryAfter > 0 {
+		return "", &ThrottledError{VolumeName: volumeName, RetryAfter: retryAfter}
+	}
+	
+	triggerSource := opts.TriggerSource
+	if triggerSource == "" {
+		triggerSource = TriggerSourceManual
+	}
+	
 	scanID := uuid.New().String()
+	denied := s.deniedMethods(volumeName)
 	task := &ScanTask{
-		ScanID:     scanID,
-		VolumeName: volumeName,
-		Method:     s.selectScanMethod(),
-		Priority:   1, // Normal priority for manual scans
-		CreatedAt:  time.Now(),
-		Timeout:    s.config.TimeoutPerVolume,
-		MaxRetries: 1,
+		ScanID:        scanID,
+		VolumeName:    volumeName,
+		Method:        s.methodForVolume(volumeName, labels, denied),
+		Priority:      1, // Normal priority for manual scans
+		CreatedAt:     time.Now(),
+		Timeout:       s.config.TimeoutPerVolume,
+		MaxRetries:    1,
+		TriggerSource: triggerSource,
+		DeniedMethods: denied,
 	}
 	
-	select {
-	case s.taskQueue <- task:
+	if s.queued.offer(s.taskQueue, task) {
 		log.Printf("[INFO] Enqueued volume %s for scanning (scan_id: %s)", volumeName, scanID)
 		// Update queue depth metrics
 		if s.metricsCollector != nil {
+			s.statusMutex.RLock()
+			utilization := s.calculateWorkerUtilization()
+			s.statusMutex.RUnlock()
 			s.metricsCollector.UpdateSchedulerQueueDepth(len(s.taskQueue))
-			s.metricsCollector.UpdateSchedulerWorkerUtilization(s.calculateWorkerUtilization())
+			s.metricsCollector.UpdateSchedulerWorkerUtilization(utilization)
 		}
 		return scanID, nil
-	default:
-		return "", fmt.Errorf("scan queue full")
 	}
+	s.releaseVolumeScan(volumeName, prev, hadPrev)
+	return "", fmt.Errorf("scan queue full")
 }
 
 // EnqueueAllVolumes enqueues all volumes for scanning with rate limiting
 func (s *Scheduler) EnqueueAllVolumes() (string, error) {
+	return s.enqueueAllVolumes(TriggerSourceManual, 0)
+}
+
+// enqueueAllVolumes enqueues every scannable volume as a low-priority batch.
+// With a spread, each volume waits a random part of it before being enqueued
+// so the batch trickles into the queue instead of arriving at once. With a
+// batch window, volumes are only enqueued while fewer than that many scans
+// are queued, so the batch keeps pace with the workers instead of filling the
+// queue.
+func (s *Scheduler) enqueueAllVolumes(triggerSource string, spread time.Duration) (string, error) {
 	if !s.IsRunning() {
 		return "", fmt.Errorf("scheduler not running")
 	}
 	
+	if s.IsPaused() {
+		return "", ErrSchedulerPaused
+	}
+	
 	// Rate limiting: only allow one EnqueueAllVolumes call per minute
 	s.rateLimitMutex.Lock()
 	if time.Since(s.lastEnqueueAll) < time.Minute {
@@ -296,42 +598,138 @@ func (s *Scheduler) EnqueueAllVolumes() (string, error) {
 	}
 	
 	batchID := uuid.New().String()
+	
+	// A paced batch lasts as long as the workers take to drain it, so a
+	// manual one carries on in the background instead of holding the request
+	if triggerSource == TriggerSourceManual && s.batchWindow() > 0 {
+		s.schedulerWG.Add(1)
+		go func() {
+			defer s.schedulerWG.Done()
+			s.enqueueBatch(batchID, volumes, triggerSource, spread)
+		}()
+		return batchID, nil
+	}
+	
+	s.enqueueBatch(batchID, volumes, triggerSource, spread)
+	return batchID, nil
+}
+
+// enqueueBatch enqueues the scannable volumes of batch batchID in batch
+// order, waiting out the spread and the batch window before each
+func (s *Scheduler) enqueueBatch(batchID string, volumes []*database.Volume, triggerSource string, spread time.Duration) {
+	window := s.batchWindow()
+	volumes = s.batchOrder(volumes)
 	enqueuedCount := 0
+	sizeUnsupportedCount := 0
+	unscannableCount := 0
+	throttledCount := 0
+	scanDisabledCount := 0
+	scanDisabled := s.scanDisabledVolumes()
+	lockedCount := 0
+	locked := s.scanLockedVolumes()
+	emptyCount := 0
 	
 	for _, volume := range volumes {
+		if spread > 0 && !s.waitToEnqueue(enqueueDelay(spread, len(volumes))) {
+			log.Printf("[INFO] Scheduler stopped or paused, not enqueueing the rest of batch %s", batchID)
+			goto done
+		}
+		
 		// Check if volume should be skipped
 		if s.shouldSkipVolume(volume.Name) {
 			continue
 		}
 		
+		// Volumes switched off one by one are never scanned in batches
+		if scanDisabled[volume.Name] {
+			scanDisabledCount++
+			continue
+		}
+		
+		// Locked volumes are left for the next pass
+		if _, ok := locked[volume.Name]; ok {
+			lockedCount++
+			continue
+		}
+		
+		// Volumes on size-unsupported drivers would only fail every scan
+		if !s.sizePolicy.SizeSupported(volume.Driver) {
+			sizeUnsupportedCount++
+			continue
+		}
+		
+		// Volumes without a local path would fail every scan too
+		if scannable, _ := s.sizePolicy.Scannable(volume.Mountpoint, volume.Options); !scannable {
+			unscannableCount++
+			continue
+		}
+		
 		// Check bind mount policy
 		if s.isBindMount(volume.Name) && !s.isBindMountAllowed(volume.Name) {
 			continue
 		}
 		
+		// Volumes that keep scanning empty sit out some scheduled passes
+		if s.emptyBackedOff(volume.Name, triggerSource) {
+			emptyCount++
+			continue
+		}
+		
+		// Only add to the batch as workers take queued scans
+		if window > 0 && !s.waitForBatchSlot(window) {
+			log.Printf("[INFO] Scheduler stopped or paused, not enqueueing the rest of batch %s", batchID)
+			goto done
+		}
+		
+		// Volumes scanned within the minimum interval are already fresh
+		prev, hadPrev, retryAfter := s.claimVolumeScan(volume.Name, false)
+		if retryAfter > 0 {
+			throttledCount++
+			continue
+		}
+		
 		scanID := uuid.New().String()
+		denied := s.deniedMethods(volume.Name)
 		task := &ScanTask{
-			ScanID:     scanID,
-			VolumeName: volume.Name,
-			Method:     s.selectScanMethod(),
-			Priority:   0, // Lower priority for batch scans
-			CreatedAt:  time.Now(),
-			Timeout:    s.config.TimeoutPerVolume,
-			MaxRetries: 1,
+			ScanID:        scanID,
+			VolumeName:    volume.Name,
+			Method:        s.methodForVolume(volume.Name, volume.Labels, denied),
+			Priority:      0, // Lower priority for batch scans
+			CreatedAt:     time.Now(),
+			Timeout:       s.config.TimeoutPerVolume,
+			MaxRetries:    1,
+			TriggerSource: triggerSource,
+			DeniedMethods: denied,
 		}
 		
-		select {
-		case s.taskQueue <- task:
-			enqueuedCount++
-		default:
+		if !s.queued.offer(s.taskQueue, task) {
+			s.releaseVolumeScan(volume.Name, prev, hadPrev)
 			log.Printf("[WARN] Scan queue full, could not enqueue volume %s", volume.Name)
 			goto done
 		}
+		enqueuedCount++
 	}
 	
 done:
+	if sizeUnsupportedCount > 0 {
+		log.Printf("[INFO] Skipped %d volumes on size-unsupported drivers", sizeUnsupportedCount)
+	}
+	if unscannableCount > 0 {
+		log.Printf("[INFO] Skipped %d volumes without a local mountpoint", unscannableCount)
+	}
+	if throttledCount > 0 {
+		log.Printf("[INFO] Skipped %d volumes scanned within the last %v", throttledCount, s.config.MinVolumeInterval)
+	}
+	if scanDisabledCount > 0 {
+		log.Printf("[INFO] Skipped %d volumes with scanning disabled", scanDisabledCount)
+	}
+	if lockedCount > 0 {
+		log.Printf("[INFO] Skipped %d volumes locked against scanning", lockedCount)
+	}
+	if emptyCount > 0 {
+		log.Printf("[INFO] Skipped %d volumes backed off after repeatedly scanning empty", emptyCount)
+	}
 	log.Printf("[INFO] Enqueued %d volumes for scanning (batch_id: %s)", enqueuedCount, batchID)
-	return batchID, nil
 }
 
 // GetScanStatus returns the status of a specific scan
@@ -342,17 +740,38 @@ func (s *Scheduler) GetScanStatus(scanID string) (*ScanStatus, error) {
 	}
 	
 	if scanRun == nil {
-		return nil, fmt.Errorf("scan not found")
+		return nil, ErrScanNotFound
+	}
+	
+	return scanStatusFromRun(scanRun), nil
+}
+
+// ListRecentScans returns the most recently started scans, newest first,
+// optionally only those with the given trigger source
+func (s *Scheduler) ListRecentScans(limit int, triggerSource string) ([]*ScanStatus, error) {
+	runs, err := s.repository.ListRecentScanRuns(s.ctx, limit, triggerSource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent scans: %w", err)
 	}
 	
+	scans := make([]*ScanStatus, len(runs))
+	for i, run := range runs {
+		scans[i] = scanStatusFromRun(run)
+	}
+	return scans, nil
+}
+
+// scanStatusFromRun converts a recorded scan run to its status
+func scanStatusFromRun(scanRun *database.ScanJob) *ScanStatus {
 	status := &ScanStatus{
-		ScanID:     scanRun.ScanID,
-		VolumeName: scanRun.VolumeID, // Note: VolumeID in ScanJob corresponds to volume name
-		Status:     scanRun.Status,
-		Method:     scanRun.Method,
-		Progress:   scanRun.Progress,
-		StartedAt:  scanRun.StartedAt,
-		CompletedAt: scanRun.CompletedAt,
+		ScanID:        scanRun.ScanID,
+		VolumeName:    scanRun.VolumeID, // Note: VolumeID in ScanJob corresponds to volume name
+		Status:        scanRun.Status,
+		Method:        scanRun.Method,
+		TriggerSource: scanRun.TriggerSource,
+		Progress:      scanRun.Progress,
+		StartedAt:     scanRun.StartedAt,
+		CompletedAt:   scanRun.CompletedAt,
 	}
 	
 	if scanRun.StartedAt != nil && scanRun.CompletedAt != nil {
@@ -364,49 +783,74 @@ func (s *Scheduler) GetScanStatus(scanID string) (*ScanStatus, error) {
 		status.Error = *scanRun.ErrorMessage
 	}
 	
-	return status, nil
+	return status
 }
 
 // runPeriodicScheduler runs the periodic scheduling loop
 func (s *Scheduler) runPeriodicScheduler() {
 	defer s.schedulerWG.Done()
 	
-	ticker := time.NewTicker(s.config.Interval)
-	defer ticker.Stop()
+	// A timer rather than a ticker, so every cycle waits a freshly jittered interval
+	delay := s.nextCycleDelay()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	nextRun := time.Now().Add(delay)
 	
-	log.Printf("[INFO] Periodic scheduler started (interval: %v)", s.config.Interval)
+	log.Printf("[INFO] Periodic scheduler started (interval: %v, jitter: %.0f%%)", s.config.Interval, s.jitterFraction()*100)
 	
-	// Run initial scan after a short delay
-	initialDelay := time.Duration(rand.Intn(30)) * time.Second
-	select {
-	case <-time.After(initialDelay):
-		s.runScheduledScan()
-	case <-s.ctx.Done():
-		return
+	// The first pass waits a full interval unless a startup pass is enabled;
+	// it goes through the same rate-limited, low-priority batch enqueue
+	if s.config.OnStartup {
+		select {
+		case <-time.After(s.config.StartupDelay):
+			log.Printf("[INFO] Running startup scan")
+			s.runScheduledScan(TriggerSourceStartup, nextRun)
+		case <-s.ctx.Done():
+			return
+		}
 	}
 	
 	for {
 		select {
-		case <-ticker.C:
-			s.runScheduledScan()
+		case <-timer.C:
+			// The next cycle is timed from the start of this one
+			delay := s.nextCycleDelay()
+			timer.Reset(delay)
+			nextRun = time.Now().Add(delay)
+			s.runScheduledScan(TriggerSourceScheduled, nextRun)
+		case <-s.pauseChanged:
+			// Stop the timer while paused; resuming restarts a full (jittered) interval
+			if s.IsPaused() {
+				timer.Stop()
+			} else {
+				delay := s.nextCycleDelay()
+				timer.Reset(delay)
+				nextRun = time.Now().Add(delay)
+				s.setNextRunAt(nextRun)
+			}
 		case <-s.ctx.Done():
 			return
 		}
 	}
 }
 
-// runScheduledScan performs a scheduled scan of all volumes
-func (s *Scheduler) runScheduledScan() {
+// runScheduledScan performs a scheduled scan of all volumes, recording
+// triggerSource on each scan and nextRun as the time of the next cycle
+func (s *Scheduler) runScheduledScan(triggerSource string, nextRun time.Time) {
 	s.statusMutex.Lock()
+	if s.paused {
+		s.statusMutex.Unlock()
+		log.Printf("[INFO] Skipping scheduled scan: scheduler paused")
+		return
+	}
 	now := time.Now()
 	s.status.LastRunAt = &now
-	next := now.Add(s.config.Interval)
-	s.status.NextRunAt = &next
+	s.status.NextRunAt = &nextRun
 	s.statusMutex.Unlock()
 	
 	log.Printf("[INFO] Starting scheduled scan")
 	
-	_, err := s.EnqueueAllVolumes()
+	_, err := s.enqueueAllVolumes(triggerSource, s.enqueueSpread())
 	if err != nil {
 		log.Printf("[ERROR] Failed to enqueue volumes for scheduled scan: %v", err)
 		s.statusMutex.Lock()
@@ -415,6 +859,13 @@ func (s *Scheduler) runScheduledScan() {
 	}
 }
 
+// setNextRunAt records when the next scheduled cycle runs
+func (s *Scheduler) setNextRunAt(next time.Time) {
+	s.statusMutex.Lock()
+	s.status.NextRunAt = &next
+	s.statusMutex.Unlock()
+}
+
 // Helper methods
 
 func (s *Scheduler) shouldSkipVolume(volumeName string) bool {
@@ -424,6 +875,51 @@ func (s *Scheduler) shouldSkipVolume(volumeName string) bool {
 	return s.skipPattern.MatchString(volumeName)
 }
 
+// scanDisabledVolumes returns the volumes whose scanning is switched off. A
+// failed lookup is logged and disables nothing, like a missing toggle source.
+func (s *Scheduler) scanDisabledVolumes() map[string]bool {
+	if s.scanToggles == nil {
+		return nil
+	}
+	disabled, err := s.scanToggles.ScanDisabledVolumes(s.ctx)
+	if err != nil {
+		log.Printf("[WARN] Could not list volumes with scanning disabled: %v", err)
+		return nil
+	}
+	return disabled
+}
+
+// claimVolumeScan records volumeName as enqueued now, unless it was enqueued within
+// the minimum interval, in which case the time until it is eligible is returned.
+// The previous entry is returned so a failed enqueue can be released again.
+func (s *Scheduler) claimVolumeScan(volumeName string, force bool) (prev time.Time, hadPrev bool, retryAfter time.Duration) {
+	s.rateLimitMutex.Lock()
+	defer s.rateLimitMutex.Unlock()
+	
+	now := time.Now()
+	prev, hadPrev = s.lastVolumeScan[volumeName]
+	if hadPrev && !force && s.config.MinVolumeInterval > 0 {
+		if elapsed := now.Sub(prev); elapsed < s.config.MinVolumeInterval {
+			return prev, hadPrev, s.config.MinVolumeInterval - elapsed
+		}
+	}
+	
+	s.lastVolumeScan[volumeName] = now
+	return prev, hadPrev, 0
+}
+
+// releaseVolumeScan restores the previous enqueue time after a failed enqueue
+func (s *Scheduler) releaseVolumeScan(volumeName string, prev time.Time, hadPrev bool) {
+	s.rateLimitMutex.Lock()
+	defer s.rateLimitMutex.Unlock()
+	
+	if hadPrev {
+		s.lastVolumeScan[volumeName] = prev
+	} else {
+		delete(s.lastVolumeScan, volumeName)
+	}
+}
+
 func (s *Scheduler) isBindMount(volumeName string) bool {
 	// Simple heuristic: bind mounts typically contain path separators
 	// More sophisticated detection would require Docker API integration
@@ -444,13 +940,181 @@ func (s *Scheduler) isBindMountAllowed(volumeName string) bool {
 	return false
 }
 
-func (s *Scheduler) selectScanMethod() string {
-	if len(s.config.MethodsOrder) > 0 {
-		return s.config.MethodsOrder[0]
+// selectScanMethod returns the method to try first for a volume: the fastest accurate
+// method benchmarked on its filesystem when auto-benchmarking, else the configured order.
+// Denied methods are passed over; with every listed method denied it returns "",
+// leaving the choice to the scanner's fallback chain.
+func (s *Scheduler) selectScanMethod(volumeName string, denied []string) string {
+	if s.config.AutoBenchmark {
+		if method, ok := s.benchmarks.fastestFor(volumeName); ok && !slices.Contains(denied, method) {
+			return method
+		}
+	}
+	s.methodsMutex.RLock()
+	defer s.methodsMutex.RUnlock()
+	for _, method := range s.config.MethodsOrder {
+		if !slices.Contains(denied, method) {
+			return method
+		}
+	}
+	if len(s.config.MethodsOrder) == 0 && !slices.Contains(denied, "du") {
+		return "du" // fallback
 	}
-	return "du" // fallback
+	return ""
 }
 
+// GetMethodsOrder returns the current scan method preference order
+func (s *Scheduler) GetMethodsOrder() []string {
+	s.methodsMutex.RLock()
+	defer s.methodsMutex.RUnlock()
+	return append([]string(nil), s.config.MethodsOrder...)
+}
+
+// SetMethodsOrder replaces the scan method preference order at runtime. Every
+// entry must be a full-scan method known to the scanner, listed once.
+func (s *Scheduler) SetMethodsOrder(order []string) error {
+	if len(order) == 0 {
+		return fmt.Errorf("%w: at least one method is required", ErrInvalidMethodsOrder)
+	}
+	
+	known := make(map[string]bool)
+	for _, info := range s.scanner.GetAvailableMethods() {
+		if !isEstimateOnly(info) {
+			known[info.Name] = true
+		}
+	}
+	
+	normalized := make([]string, 0, len(order))
+	seen := make(map[string]bool, len(order))
+	for _, method := range order {
+		method = strings.ToLower(strings.TrimSpace(method))
+		if !known[method] {
+			return fmt.Errorf("%w: unknown scan method %q", ErrInvalidMethodsOrder, method)
+		}
+		if seen[method] {
+			return fmt.Errorf("%w: scan method %q listed more than once", ErrInvalidMethodsOrder, method)
+		}
+		seen[method] = true
+		normalized = append(normalized, method)
+	}
+	
+	s.methodsMutex.Lock()
+	s.config.MethodsOrder = normalized
+	s.methodsMutex.Unlock()
+	
+	log.Printf("[INFO] Scan methods order set to %v", normalized)
+	return nil
+}
+
+// methodForVolume returns the method to try first for a volume, preferring the
+// method named by the configured scan method label when the scanner supports it
+// and it is not denied for the volume
+func (s *Scheduler) methodForVolume(volumeName string, labels map[string]string, denied []string) string {
+	if s.config.MethodLabel != "" {
+		if value, ok := labels[s.config.MethodLabel]; ok {
+			method := strings.ToLower(strings.TrimSpace(value))
+			switch {
+			case slices.Contains(denied, method):
+				log.Printf("[WARN] Ignoring label %s=%q on volume %s: the scan method is denied for the volume",
+					s.config.MethodLabel, value, volumeName)
+			case s.isMethodAvailable(method):
+				return method
+			default:
+				log.Printf("[WARN] Ignoring label %s=%q on volume %s: not an available scan method",
+					s.config.MethodLabel, value, volumeName)
+			}
+		}
+	}
+	return s.selectScanMethod(volumeName, denied)
+}
+
+// MethodForVolume returns the method scans of a volume try first, taking its
+// labels, benchmarks and denied methods into account; "" leaves the choice to
+// the scanner
+func (s *Scheduler) MethodForVolume(volumeName string) string {
+	var labels database.Labels
+	if volume, err := s.volumeProvider.GetVolume(s.ctx, volumeName); err == nil && volume != nil {
+		labels = volume.Labels
+	}
+	return s.methodForVolume(volumeName, labels, s.deniedMethods(volumeName))
+}
+
+// deniedMethods returns the scan methods denied for a volume. A failed lookup
+// is logged and denies nothing, like a failed scan toggle lookup.
+func (s *Scheduler) deniedMethods(volumeName string) []string {
+	if s.methodDenials == nil {
+		return nil
+	}
+	denied, err := s.methodDenials.DeniedScanMethods(s.ctx, volumeName)
+	if err != nil {
+		log.Printf("[WARN] Could not look up denied scan methods of volume %s: %v", volumeName, err)
+		return nil
+	}
+	return denied
+}
+
+// isMethodAvailable reports whether the scanner can use the named method for full scans
+func (s *Scheduler) isMethodAvailable(method string) bool {
+	for _, info := range s.scanner.GetAvailableMethods() {
+		if info.Name == method && info.Available && !isEstimateOnly(info) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEstimateOnly reports whether a method only produces sampled estimates and
+// so cannot be chosen for full scans
+func isEstimateOnly(info interfaces.MethodInfo) bool {
+	for _, feature := range info.Features {
+		if feature == "estimate_only" {
+			return true
+		}
+	}
+	return false
+}
+
+// durationStat accumulates the scan durations of one method
+type durationStat struct {
+	totalSeconds float64
+	count        int64
+}
+
+// mean returns the average duration in seconds
+func (d *durationStat) mean() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	return d.totalSeconds / float64(d.count)
+}
+
+// recordScanFailure counts a failed scan under its error code
+func (s *Scheduler) recordScanFailure(errorCode string) {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	s.status.TotalFailed++
+	s.metrics.CompletedScans["failed"]++
+	s.metrics.ErrorCounts[errorCode]++
+}
+
+// recordScanSuccess counts a completed scan and adds its duration to the
+// method's average
+func (s *Scheduler) recordScanSuccess(method string, duration time.Duration) {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	s.status.TotalCompleted++
+	s.metrics.CompletedScans["completed"]++
+	stat, ok := s.durationStats[method]
+	if !ok {
+		stat = &durationStat{}
+		s.durationStats[method] = stat
+	}
+	stat.totalSeconds += duration.Seconds()
+	stat.count++
+}
+
+// calculateWorkerUtilization returns the share of workers scanning. Callers
+// must hold statusMutex.
 func (s *Scheduler) calculateWorkerUtilization() float64 {
 	if s.config.Concurrency == 0 {
 		return 0.0
@@ -465,8 +1129,21 @@ func (w *worker) run() {
 	log.Printf("[INFO] Worker %d started", w.id)
 	
 	for {
+		// A stopping scheduler starts no new tasks, even with some still queued
+		select {
+		case <-w.draining:
+			log.Printf("[INFO] Worker %d stopped", w.id)
+			return
+		default:
+		}
+		
 		select {
+		case <-w.draining:
+			log.Printf("[INFO] Worker %d stopped", w.id)
+			return
 		case task := <-w.scheduler.taskQueue:
+			w.scheduler.queued.taken(task)
+			w.scheduler.signalDequeued()
 			// Update queue depth metrics after dequeue
 			if w.scheduler.metricsCollector != nil {
 				w.scheduler.metricsCollector.UpdateSchedulerQueueDepth(len(w.scheduler.taskQueue))
@@ -488,17 +1165,28 @@ func (w *worker) processTask(task *ScanTask) {
 	
 	// Create scan run record
 	scanRun := &database.ScanJob{
-		ScanID:   task.ScanID,
-		VolumeID: task.VolumeName,
-		Status:   "running",
-		Method:   task.Method,
-		Progress: 0,
+		ScanID:        task.ScanID,
+		VolumeID:      task.VolumeName,
+		Status:        "running",
+		Method:        task.Method,
+		Progress:      0,
+		TriggerSource: task.TriggerSource,
 	}
 	now := time.Now()
 	scanRun.StartedAt = &now
 	
+	// A volume locked while its scan waited in the queue is not scanned;
+	// the run is recorded canceled so whoever enqueued it learns why
+	if lock := w.scheduler.scanLock(task.VolumeName); lock != nil {
+		w.cancelLockedTask(scanRun, &LockedError{VolumeName: task.VolumeName, Lock: *lock})
+		return
+	}
+	
 	// Insert initial scan run
-	if err := w.scheduler.repository.InsertScanRun(w.ctx, scanRun); err != nil {
+	persistCtx, cancelPersist := w.persistContext()
+	err := w.scheduler.repository.InsertScanRun(persistCtx, scanRun)
+	cancelPersist()
+	if err != nil {
 		log.Printf("[ERROR] Worker %d failed to insert scan run: %v", w.id, err)
 		return
 	}
@@ -508,35 +1196,51 @@ func (w *worker) processTask(task *ScanTask) {
 		w.scheduler.metricsCollector.ScanStarted(task.Method)
 	}
 	
-	// Create timeout context
-	ctx, cancel := context.WithTimeout(w.ctx, task.Timeout)
+	// Create timeout context that asks the scanner to try the selected method first.
+	// The timeout starts once the scan run is recorded, so database writes never
+	// use up scan time.
+	scanStart := time.Now()
+	// Methods denied for the volume are left out of the scanner's fallback chain.
+	ctx, cancel := context.WithTimeout(interfaces.WithDeniedMethods(interfaces.WithPreferredMethod(w.ctx, task.Method), task.DeniedMethods), task.Timeout)
 	defer cancel()
 	
 	// Perform the scan
 	result, err := w.scheduler.scanner.ScanVolume(ctx, task.VolumeName)
 	completedAt := time.Now()
-	duration := completedAt.Sub(now)
+	duration := completedAt.Sub(scanStart)
+	w.scheduler.costs.record(task.VolumeName, duration)
 	
 	// Update scan run with results
 	scanRun.CompletedAt = &completedAt
 	scanRun.Progress = 100
 	
-	if err != nil {
+	if err != nil && coremodels.VolumeRemoved(err) {
+		// A volume removed from Docker after it was queued is not a failure:
+		// the run is recorded canceled with why, and no failure is counted
+		scanRun.Status = coremodels.ScanStatusCanceled
+		errorMsg := "volume removed during scan"
+		scanRun.ErrorMessage = &errorMsg
+		
+		log.Printf("[INFO] Worker %d not scanning volume %s: removed during scan", w.id, task.VolumeName)
+		w.scheduler.VolumeRemoved(task.VolumeName)
+	} else if err != nil {
 		// Handle failure
 		scanRun.Status = "failed"
 		errorMsg := err.Error()
 		scanRun.ErrorMessage = &errorMsg
 		
-		log.Printf("[ERROR] Worker %d scan failed for volume %s: %v", w.id, task.VolumeName, err)
+		// Classify once so logs and metrics agree on the failure category
+		errorCode := coremodels.ClassifyScanError(err)
+		if w.scheduler.config.FailureLogDetail == "code" {
+			log.Printf("[ERROR] Worker %d scan failed for volume %s: code=%s", w.id, task.VolumeName, errorCode)
+		} else {
+			log.Printf("[ERROR] Worker %d scan failed for volume %s: code=%s error=%v", w.id, task.VolumeName, errorCode, err)
+		}
 		
-		w.scheduler.statusMutex.Lock()
-		w.scheduler.status.TotalFailed++
-		w.scheduler.metrics.CompletedScans["failed"]++
-		w.scheduler.metrics.ErrorCounts["scan_error"]++
-		w.scheduler.statusMutex.Unlock()
+		w.scheduler.recordScanFailure(errorCode)
 		
 		if w.scheduler.metricsCollector != nil {
-			w.scheduler.metricsCollector.RecordScanFailure(task.Method, "scan_error")
+			w.scheduler.metricsCollector.RecordScanFailure(task.Method, errorCode)
 		}
 	} else {
 		// Handle success
@@ -547,28 +1251,37 @@ func (w *worker) processTask(task *ScanTask) {
 		
 		// Insert volume stats
 		stats := &database.VolumeScanStats{
-			VolumeName: task.VolumeName,
-			SizeBytes:  result.TotalSize,
-			ScanMethod: result.Method,
-			DurationMs: duration.Milliseconds(),
-			Timestamp:  completedAt,
+			VolumeName:   task.VolumeName,
+			SizeBytes:    result.TotalSize,
+			ScanMethod:   result.Method,
+			ScanAccuracy: result.Accuracy,
+			DurationMs:   duration.Milliseconds(),
+			Timestamp:    completedAt,
 		}
 		
 		if result.FileCount > 0 {
 			stats.FileCount = &result.FileCount
 		}
 		
-		if err := w.scheduler.repository.InsertVolumeStats(w.ctx, stats); err != nil {
-			log.Printf("[ERROR] Worker %d failed to insert volume stats: %v", w.id, err)
+		// Lets benchmarks for this filesystem apply to the volume
+		w.scheduler.benchmarks.setVolumeFilesystem(task.VolumeName, result.FilesystemType)
+		w.scheduler.recordScanSize(task.VolumeName, result.TotalSize)
+		
+		if w.scheduler.statsWriter != nil {
+			w.scheduler.statsWriter.add(stats)
+		} else {
+			persistCtx, cancelPersist := w.persistContext()
+			if err := w.scheduler.repository.InsertVolumeStats(persistCtx, stats); err != nil {
+				log.Printf("[ERROR] Worker %d failed to insert volume stats: %v", w.id, err)
+			}
+			cancelPersist()
+		}
+		
+		if w.scheduler.sizeReporter != nil {
+			w.scheduler.sizeReporter.ReportVolumeSize(task.VolumeName, result.TotalSize)
 		}
 		
-		w.scheduler.statusMutex.Lock()
-		w.scheduler.status.TotalCompleted++
-		w.scheduler.metrics.CompletedScans["completed"]++
-		// Update average duration for this method
-		currentAvg := w.scheduler.metrics.ScanDurations[task.Method]
-		w.scheduler.metrics.ScanDurations[task.Method] = (currentAvg + duration.Seconds()) / 2
-		w.scheduler.statusMutex.Unlock()
+		w.scheduler.recordScanSuccess(task.Method, duration)
 		
 		if w.scheduler.metricsCollector != nil {
 			w.scheduler.metricsCollector.ScanCompleted(task.VolumeName, task.Method, duration, result.TotalSize)
@@ -576,16 +1289,29 @@ func (w *worker) processTask(task *ScanTask) {
 	}
 	
 	// Update scan run in database
-	if err := w.scheduler.repository.UpdateScanRun(w.ctx, scanRun); err != nil {
+	persistCtx, cancelPersist = w.persistContext()
+	defer cancelPersist()
+	if err := w.scheduler.repository.UpdateScanRun(persistCtx, scanRun); err != nil {
 		log.Printf("[ERROR] Worker %d failed to update scan run: %v", w.id, err)
 	}
 	
 	// Update metrics
+	scansBySourceTotal.WithLabelValues(task.TriggerSource, scanRun.Status).Inc()
 	if w.scheduler.metricsCollector != nil {
 		w.scheduler.metricsCollector.ScanFinished(task.Method)
 	}
 }
 
+// persistContext bounds one scan bookkeeping write by the persistence timeout,
+// apart from the scan's own deadline, so a slow database fails the write
+// instead of stalling the worker
+func (w *worker) persistContext() (context.Context, context.CancelFunc) {
+	if w.scheduler.config.PersistTimeout <= 0 {
+		return w.ctx, func() {}
+	}
+	return context.WithTimeout(w.ctx, w.scheduler.config.PersistTimeout)
+}
+
 func (w *worker) updateActiveScans(delta int) {
 	w.scheduler.statusMutex.Lock()
 	w.scheduler.status.ActiveScans += delta
@@ -2,14 +2,24 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/mantonx/volumeviz/internal/config"
 	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	coremodels "github.com/mantonx/volumeviz/internal/core/models"
 	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/mantonx/volumeviz/internal/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockVolumeScanner implements interfaces.VolumeScanner for testing
@@ -48,6 +58,14 @@ func (m *MockVolumeScanner) ClearCache(volumeID string) error {
 	return args.Error(0)
 }
 
+func (m *MockVolumeScanner) EstimateVolumeSize(ctx context.Context, volumeID string) (*interfaces.ScanResult, error) {
+	args := m.Called(ctx, volumeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.ScanResult), args.Error(1)
+}
+
 // MockScanRepository implements ScanRepository for testing
 type MockScanRepository struct {
 	mock.Mock
@@ -94,6 +112,11 @@ func (m *MockScanRepository) GetActiveScanRuns(ctx context.Context) ([]*database
 	return args.Get(0).([]*database.ScanJob), args.Error(1)
 }
 
+func (m *MockScanRepository) ListRecentScanRuns(ctx context.Context, limit int, triggerSource string) ([]*database.ScanJob, error) {
+	args := m.Called(ctx, limit, triggerSource)
+	return args.Get(0).([]*database.ScanJob), args.Error(1)
+}
+
 func (m *MockScanRepository) ListVolumes(ctx context.Context) ([]*database.Volume, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]*database.Volume), args.Error(1)
@@ -122,6 +145,11 @@ func (m *MockVolumeProvider) GetVolume(ctx context.Context, volumeName string) (
 	return args.Get(0).(*database.Volume), args.Error(1)
 }
 
+// localVolume returns a volume on the local driver with a Docker-managed mountpoint
+func localVolume(name string) *database.Volume {
+	return &database.Volume{Name: name, Driver: "local", Mountpoint: "/var/lib/docker/volumes/" + name + "/_data"}
+}
+
 // MockMetricsCollector implements interfaces.MetricsCollector for testing
 type MockMetricsCollector struct {
 	mock.Mock
@@ -151,8 +179,8 @@ func (m *MockMetricsCollector) RecordScanFailure(method, errorCode string) {
 	m.Called(method, errorCode)
 }
 
-func (m *MockMetricsCollector) UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, size int64, fileCount int, scanMethod string) {
-	m.Called(volumeID, volumeName, driver, filesystemType, size, fileCount, scanMethod)
+func (m *MockMetricsCollector) UpdateVolumeMetrics(volumeID, volumeName, driver, filesystemType string, volumeLabels map[string]string, size int64, fileCount int, scanMethod string) {
+	m.Called(volumeID, volumeName, driver, filesystemType, volumeLabels, size, fileCount, scanMethod)
 }
 
 func (m *MockMetricsCollector) SetDockerConnectionStatus(connected bool) {
@@ -167,6 +195,10 @@ func (m *MockMetricsCollector) SetActiveScanners(count int) {
 	m.Called(count)
 }
 
+func (m *MockMetricsCollector) SetMethodAvailable(method string, available bool) {
+	m.Called(method, available)
+}
+
 func (m *MockMetricsCollector) ScanStarted(method string) {
 	m.Called(method)
 }
@@ -179,6 +211,10 @@ func (m *MockMetricsCollector) SetSchedulerRunningStatus(running bool) {
 	m.Called(running)
 }
 
+func (m *MockMetricsCollector) SetSchedulerPausedStatus(paused bool) {
+	m.Called(paused)
+}
+
 func (m *MockMetricsCollector) UpdateSchedulerQueueDepth(depth int) {
 	m.Called(depth)
 }
@@ -271,6 +307,25 @@ func TestNewSchedulerWithInvalidSkipPattern(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid skip pattern")
 }
 
+func TestNewSchedulerRejectsOverlyComplexSkipPattern(t *testing.T) {
+	schedulerConfig := &SchedulerConfig{
+		ScanConfig: &config.ScanConfig{
+			Enabled:          true,
+			Interval:         5 * time.Minute,
+			Concurrency:      2,
+			TimeoutPerVolume: 30 * time.Second,
+			SkipPattern:      "^(a{0,30}){0,30}$",
+		},
+		QueueSize: 10,
+	}
+
+	scheduler, err := NewScheduler(schedulerConfig, &MockVolumeScanner{}, &MockScanRepository{}, &MockVolumeProvider{}, &MockMetricsCollector{})
+
+	assert.Nil(t, scheduler)
+	assert.ErrorIs(t, err, utils.ErrPatternTooComplex)
+	assert.Contains(t, err.Error(), "invalid skip pattern")
+}
+
 func TestSchedulerStart(t *testing.T) {
 	scheduler, _, _, _, mockMetrics := createTestScheduler()
 	ctx := context.Background()
@@ -369,6 +424,98 @@ func TestSchedulerStopWhenNotRunning(t *testing.T) {
 	assert.False(t, scheduler.IsRunning())
 }
 
+// startDrainTestScheduler starts a single-worker scheduler whose scan of
+// "busy" blocks until release is closed or its context is cancelled. finished
+// receives the scan context's error as the scan returns.
+func startDrainTestScheduler(t *testing.T, release <-chan struct{}) (scheduler *Scheduler, mockScanner *MockVolumeScanner, started chan struct{}, finished chan error) {
+	scheduler, mockScanner, mockRepo, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.config.Concurrency = 1
+
+	mockRepo.On("InsertScanRun", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("UpdateScanRun", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("InsertVolumeStats", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.Anything).Return(localVolume("busy"), nil).Maybe()
+	for _, method := range []string{"SetSchedulerRunningStatus", "UpdateSchedulerQueueDepth", "UpdateSchedulerWorkerUtilization", "ScanStarted", "ScanFinished"} {
+		mockMetrics.On(method, mock.Anything).Maybe()
+	}
+	mockMetrics.On("ScanCompleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockMetrics.On("RecordScanAttempt", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockMetrics.On("RecordScanFailure", mock.Anything, mock.Anything).Maybe()
+
+	started = make(chan struct{})
+	finished = make(chan error, 1)
+	mockScanner.On("ScanVolume", mock.Anything, "busy").Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		close(started)
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		finished <- ctx.Err()
+	}).Return(&interfaces.ScanResult{TotalSize: 1024, Method: "du"}, nil)
+
+	require.NoError(t, scheduler.Start(context.Background()))
+	_, err := scheduler.EnqueueVolume("busy")
+	require.NoError(t, err)
+	_, err = scheduler.EnqueueVolume("queued")
+	require.NoError(t, err)
+	return scheduler, mockScanner, started, finished
+}
+
+func TestSchedulerStopDrainsRunningScans(t *testing.T) {
+	release := make(chan struct{})
+	scheduler, mockScanner, started, finished := startDrainTestScheduler(t, release)
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("scan did not start")
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- scheduler.Stop(stopCtx)
+	}()
+
+	// The running scan holds up Stop, and new scans are refused meanwhile
+	assert.Eventually(t, func() bool { return !scheduler.IsRunning() }, time.Second, time.Millisecond)
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the running scan finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	_, err := scheduler.EnqueueVolume("late")
+	assert.Error(t, err)
+
+	close(release)
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return after the scan finished")
+	}
+
+	assert.NoError(t, <-finished, "the running scan finished without being cancelled")
+	mockScanner.AssertNotCalled(t, "ScanVolume", mock.Anything, "queued")
+}
+
+func TestSchedulerStopCancelsScansAtDeadline(t *testing.T) {
+	scheduler, _, started, finished := startDrainTestScheduler(t, make(chan struct{}))
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("scan did not start")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, scheduler.Stop(stopCtx))
+	assert.ErrorIs(t, <-finished, context.Canceled)
+}
+
 func TestEnqueueVolume(t *testing.T) {
 	scheduler, mockScanner, mockRepo, mockProvider, mockMetrics := createTestScheduler()
 	ctx := context.Background()
@@ -386,9 +533,7 @@ func TestEnqueueVolume(t *testing.T) {
 	}, nil).Maybe()
 
 	// Mock provider methods that workers might call
-	mockProvider.On("GetVolume", mock.Anything, "test-volume").Return(&database.Volume{
-		Name: "test-volume",
-	}, nil).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, "test-volume").Return(localVolume("test-volume"), nil).Maybe()
 
 	// Start scheduler
 	mockMetrics.On("SetSchedulerRunningStatus", true).Once()
@@ -463,8 +608,8 @@ func TestEnqueueAllVolumes(t *testing.T) {
 
 	// Mock volumes
 	volumes := []*database.Volume{
-		{Name: "volume1"},
-		{Name: "volume2"},
+		localVolume("volume1"),
+		localVolume("volume2"),
 	}
 	mockProvider.On("ListVolumes", mock.AnythingOfType("*context.cancelCtx")).Return(volumes, nil)
 
@@ -481,9 +626,7 @@ func TestEnqueueAllVolumes(t *testing.T) {
 	}, nil).Maybe()
 
 	// Mock provider methods that workers might call
-	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(&database.Volume{
-		Name: "test",
-	}, nil).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("test"), nil).Maybe()
 
 	// Start scheduler
 	mockMetrics.On("SetSchedulerRunningStatus", true).Once()
@@ -521,7 +664,7 @@ func TestEnqueueAllVolumesRateLimit(t *testing.T) {
 
 	// Mock volumes
 	volumes := []*database.Volume{
-		{Name: "volume1"},
+		localVolume("volume1"),
 	}
 	mockProvider.On("ListVolumes", mock.AnythingOfType("*context.cancelCtx")).Return(volumes, nil).Once()
 
@@ -538,9 +681,7 @@ func TestEnqueueAllVolumesRateLimit(t *testing.T) {
 	}, nil).Maybe()
 
 	// Mock provider methods that workers might call
-	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(&database.Volume{
-		Name: "test",
-	}, nil).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("test"), nil).Maybe()
 
 	// Start scheduler
 	mockMetrics.On("SetSchedulerRunningStatus", true).Once()
@@ -644,12 +785,12 @@ func TestIsBindMountAllowed(t *testing.T) {
 func TestSelectScanMethod(t *testing.T) {
 	scheduler, _, _, _, _ := createTestScheduler()
 
-	method := scheduler.selectScanMethod()
+	method := scheduler.selectScanMethod("test-volume", nil)
 	assert.Equal(t, "diskus", method) // First in MethodsOrder
 
 	// Test fallback when no methods configured
 	scheduler.config.MethodsOrder = []string{}
-	method = scheduler.selectScanMethod()
+	method = scheduler.selectScanMethod("test-volume", nil)
 	assert.Equal(t, "du", method) // Fallback
 }
 
@@ -669,4 +810,708 @@ func TestCalculateWorkerUtilization(t *testing.T) {
 	scheduler.metrics.ActiveScans = 2
 	utilization = scheduler.calculateWorkerUtilization()
 	assert.Equal(t, 1.0, utilization)
-}
\ No newline at end of file
+}
+func TestScanMetricsUnderConcurrentCompletions(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+
+	// Durations 1s..200s per method; the old pairwise average would have
+	// weighted the last scans far above the first
+	const scans = 200
+	var writers sync.WaitGroup
+	for _, method := range []string{"du", "diskus"} {
+		for i := 1; i <= scans; i++ {
+			writers.Add(1)
+			go func(method string, duration time.Duration) {
+				defer writers.Done()
+				scheduler.recordScanSuccess(method, duration)
+				if duration%(10*time.Second) == 0 {
+					scheduler.recordScanFailure("TIMEOUT")
+				}
+			}(method, time.Duration(i)*time.Second)
+		}
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				metrics := scheduler.GetMetrics()
+				for method, mean := range metrics.ScanDurations {
+					assert.LessOrEqual(t, mean, float64(scans), method)
+				}
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(done)
+	readers.Wait()
+
+	metrics := scheduler.GetMetrics()
+	assert.Equal(t, int64(2*scans), metrics.CompletedScans["completed"])
+	assert.Equal(t, int64(2*scans/10), metrics.CompletedScans["failed"])
+	assert.Equal(t, int64(2*scans/10), metrics.ErrorCounts["TIMEOUT"])
+	assert.InDelta(t, 100.5, metrics.ScanDurations["du"], 1e-9)
+	assert.InDelta(t, 100.5, metrics.ScanDurations["diskus"], 1e-9)
+	assert.Equal(t, int64(2*scans), scheduler.GetStatus().TotalCompleted)
+}
+
+func TestProcessTaskRecordsFailureErrorCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode string
+	}{
+		{
+			name: "permission denied",
+			err: &coremodels.ScanError{
+				Code:    coremodels.ErrorCodeAllMethodsFailed,
+				Message: "all scan methods failed",
+				Err: &coremodels.ScanError{
+					Code:    coremodels.ErrorCodeMethodUnavailable,
+					Message: "du scan failed",
+					Err:     &fs.PathError{Op: "open", Path: "/data", Err: syscall.EACCES},
+				},
+			},
+			expectedCode: coremodels.ErrorCodePermissionDenied,
+		},
+		{
+			name: "timeout",
+			err: &coremodels.ScanError{
+				Code:    coremodels.ErrorCodeAllMethodsFailed,
+				Message: "all scan methods failed",
+				Err:     fmt.Errorf("native walk: %w", context.DeadlineExceeded),
+			},
+			expectedCode: coremodels.ErrorCodeScanTimeout,
+		},
+		{
+			name: "volume not found",
+			err: &coremodels.ScanError{
+				Code:    coremodels.ErrorCodeVolumePathError,
+				Message: "failed to resolve volume path",
+				Err:     errors.New("failed to get volume info: Error response from daemon: get gone: no such volume"),
+			},
+			expectedCode: coremodels.ErrorCodeVolumeNotFound,
+		},
+		{
+			name: "path missing",
+			err: &coremodels.ScanError{
+				Code:    coremodels.ErrorCodeAllMethodsFailed,
+				Message: "all scan methods failed",
+				Err: &coremodels.ScanError{
+					Code:    coremodels.ErrorCodePathNotFound,
+					Message: "failed to read volume root",
+				},
+			},
+			expectedCode: coremodels.ErrorCodePathNotFound,
+		},
+		{
+			name:         "unclassified",
+			err:          errors.New("diskus exited with status 2"),
+			expectedCode: coremodels.ErrorCodeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+
+			mockRepo.On("InsertScanRun", mock.Anything, mock.AnythingOfType("*database.ScanJob")).Return(nil)
+			mockRepo.On("UpdateScanRun", mock.Anything, mock.MatchedBy(func(run *database.ScanJob) bool {
+				return run.Status == "failed" && run.ErrorMessage != nil
+			})).Return(nil)
+			mockScanner.On("ScanVolume", mock.Anything, "broken-volume").Return(nil, tt.err)
+			mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+			mockMetrics.On("ScanStarted", "du")
+			mockMetrics.On("ScanFinished", "du")
+			mockMetrics.On("RecordScanFailure", "du", tt.expectedCode).Once()
+
+			w := &worker{id: 1, scheduler: scheduler, ctx: context.Background()}
+			w.processTask(&ScanTask{
+				ScanID:     "scan-1",
+				VolumeName: "broken-volume",
+				Method:     "du",
+				Timeout:    time.Second,
+			})
+
+			metrics := scheduler.GetMetrics()
+			assert.Equal(t, map[string]int64{tt.expectedCode: 1}, metrics.ErrorCounts)
+			assert.Equal(t, int64(1), metrics.CompletedScans["failed"])
+			mockMetrics.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessTaskVolumeRemoved(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "removed before the scan looked it up",
+			err: &coremodels.ScanError{
+				Code:    coremodels.ErrorCodeVolumePathError,
+				Message: "failed to resolve volume path",
+				Err:     fmt.Errorf("failed to get volume info: %w", errdefs.NotFound(errors.New("Error response from daemon: get gone"))),
+			},
+		},
+		{
+			name: "removed while it was scanned",
+			err: &coremodels.ScanError{
+				Code:    coremodels.ErrorCodeVolumeRemoved,
+				Message: "volume removed during scan",
+				Err:     fmt.Errorf("failed to get volume gone: %w", errdefs.NotFound(errors.New("Error response from daemon: get gone"))),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+
+			mockRepo.On("InsertScanRun", mock.Anything, mock.AnythingOfType("*database.ScanJob")).Return(nil)
+			mockRepo.On("UpdateScanRun", mock.Anything, mock.MatchedBy(func(run *database.ScanJob) bool {
+				return run.Status == coremodels.ScanStatusCanceled && run.ErrorMessage != nil &&
+					*run.ErrorMessage == "volume removed during scan"
+			})).Return(nil)
+			mockScanner.On("ScanVolume", mock.Anything, "gone").Return(nil, tt.err)
+			mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+			mockMetrics.On("ScanStarted", "du")
+			mockMetrics.On("ScanFinished", "du")
+
+			w := &worker{id: 1, scheduler: scheduler, ctx: context.Background()}
+			w.processTask(&ScanTask{
+				ScanID:     "scan-1",
+				VolumeName: "gone",
+				Method:     "du",
+				Timeout:    time.Second,
+			})
+
+			// Not a failure: no error counted, no failure metric recorded
+			metrics := scheduler.GetMetrics()
+			assert.Empty(t, metrics.ErrorCounts)
+			assert.Zero(t, metrics.CompletedScans["failed"])
+			mockMetrics.AssertNotCalled(t, "RecordScanFailure", mock.Anything, mock.Anything)
+			mockMetrics.AssertExpectations(t)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnqueueSkipsSizeUnsupportedDrivers(t *testing.T) {
+	scheduler, _, _, mockProvider, _ := createTestScheduler()
+	scheduler.sizePolicy = config.NewSizePolicy([]string{"csi-nfs"})
+
+	// Mark running without starting workers so queued tasks stay inspectable
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("local-volume"),
+		{Name: "nfs-volume", Driver: "csi-nfs"},
+	}, nil)
+	mockProvider.On("GetVolume", mock.Anything, "nfs-volume").Return(&database.Volume{Name: "nfs-volume", Driver: "csi-nfs"}, nil)
+
+	_, err := scheduler.EnqueueAllVolumes()
+	assert.NoError(t, err)
+
+	assert.Len(t, scheduler.taskQueue, 1)
+	task := <-scheduler.taskQueue
+	assert.Equal(t, "local-volume", task.VolumeName)
+
+	scanID, err := scheduler.EnqueueVolume("nfs-volume")
+	assert.Error(t, err)
+	assert.Empty(t, scanID)
+	assert.Contains(t, err.Error(), "does not support size scanning")
+	assert.Empty(t, scheduler.taskQueue)
+}
+
+func TestEnqueueSkipsUnscannableVolumes(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	emptyMountpoint := &database.Volume{Name: "empty-mountpoint", Driver: "rexray"}
+	remoteMountpoint := &database.Volume{Name: "remote-mountpoint", Driver: "netshare", Mountpoint: "nfs://storage/export"}
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("local-volume"),
+		emptyMountpoint,
+		remoteMountpoint,
+		// A local device path is scanned even without a usable mountpoint
+		{Name: "device-volume", Driver: "local", Options: database.Labels{"device": "/mnt/data"}},
+	}, nil)
+	mockProvider.On("GetVolume", mock.Anything, "empty-mountpoint").Return(emptyMountpoint, nil)
+	mockProvider.On("GetVolume", mock.Anything, "remote-mountpoint").Return(remoteMountpoint, nil)
+
+	_, err := scheduler.EnqueueAllVolumes()
+	assert.NoError(t, err)
+
+	assert.Len(t, scheduler.taskQueue, 2)
+	assert.Equal(t, "local-volume", (<-scheduler.taskQueue).VolumeName)
+	assert.Equal(t, "device-volume", (<-scheduler.taskQueue).VolumeName)
+
+	_, err = scheduler.EnqueueVolume("empty-mountpoint")
+	assert.ErrorContains(t, err, "driver reported no mountpoint")
+	_, err = scheduler.EnqueueVolume("remote-mountpoint")
+	assert.ErrorContains(t, err, `mountpoint "nfs://storage/export" is not a local path`)
+	assert.Empty(t, scheduler.taskQueue)
+
+	// With an external size command configured they are scanned like any other volume
+	scheduler.sizePolicy = (&config.ScanConfig{ExternalSizeCommand: "/usr/local/bin/volume-size"}).SizePolicy()
+	scanID, err := scheduler.EnqueueVolume("remote-mountpoint")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+}
+
+// fakeScanToggles disables scanning of the volumes in its map
+type fakeScanToggles map[string]bool
+
+func (f fakeScanToggles) IsScanEnabled(ctx context.Context, volumeName string) (bool, error) {
+	return !f[volumeName], nil
+}
+
+func (f fakeScanToggles) ScanDisabledVolumes(ctx context.Context) (map[string]bool, error) {
+	return f, nil
+}
+
+func TestEnqueueSkipsScanDisabledVolumes(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.SetScanToggles(fakeScanToggles{"postgres-data": true})
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("app-data"),
+		localVolume("postgres-data"),
+	}, nil)
+	mockProvider.On("GetVolume", mock.Anything, "postgres-data").Return(localVolume("postgres-data"), nil)
+
+	_, err := scheduler.EnqueueAllVolumes()
+	assert.NoError(t, err)
+	assert.Len(t, scheduler.taskQueue, 1)
+	assert.Equal(t, "app-data", (<-scheduler.taskQueue).VolumeName)
+
+	scanID, err := scheduler.EnqueueVolume("postgres-data")
+	assert.ErrorIs(t, err, ErrScanDisabled)
+	assert.Empty(t, scanID)
+	assert.Empty(t, scheduler.taskQueue)
+
+	// An explicit override scans it anyway
+	scanID, err = scheduler.EnqueueVolumeWithOptions("postgres-data", EnqueueOptions{OverrideScanDisabled: true})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+	assert.Equal(t, "postgres-data", (<-scheduler.taskQueue).VolumeName)
+}
+
+// fakeMethodDenials denies the listed scan methods of the volumes in its map
+type fakeMethodDenials map[string][]string
+
+func (f fakeMethodDenials) DeniedScanMethods(ctx context.Context, volumeName string) ([]string, error) {
+	return f[volumeName], nil
+}
+
+func TestEnqueueSkipsDeniedMethods(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.SetMethodDenials(fakeMethodDenials{"fuse-data": {"diskus"}, "odd-data": {"diskus", "du"}})
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("app-data"),
+		localVolume("fuse-data"),
+		localVolume("odd-data"),
+	}, nil)
+	for _, name := range []string{"app-data", "fuse-data", "odd-data"} {
+		mockProvider.On("GetVolume", mock.Anything, name).Return(localVolume(name), nil)
+	}
+
+	_, err := scheduler.EnqueueAllVolumes()
+	require.NoError(t, err)
+	require.Len(t, scheduler.taskQueue, 3)
+	tasks := make(map[string]*ScanTask)
+	for range 3 {
+		task := <-scheduler.taskQueue
+		tasks[task.VolumeName] = task
+	}
+
+	// The denied method is passed over only for the volume it is denied for
+	assert.Equal(t, "diskus", tasks["app-data"].Method)
+	assert.Empty(t, tasks["app-data"].DeniedMethods)
+	assert.Equal(t, "du", tasks["fuse-data"].Method)
+	assert.Equal(t, []string{"diskus"}, tasks["fuse-data"].DeniedMethods)
+
+	// With every configured method denied the scanner's fallback chain decides
+	assert.Equal(t, "", tasks["odd-data"].Method)
+	assert.Equal(t, []string{"diskus", "du"}, tasks["odd-data"].DeniedMethods)
+
+	assert.Equal(t, "du", scheduler.MethodForVolume("fuse-data"))
+	assert.Equal(t, "diskus", scheduler.MethodForVolume("app-data"))
+
+	// Single scans honor the denial too
+	_, err = scheduler.EnqueueVolume("fuse-data")
+	require.NoError(t, err)
+	task := <-scheduler.taskQueue
+	assert.Equal(t, "du", task.Method)
+	assert.Equal(t, []string{"diskus"}, task.DeniedMethods)
+}
+
+func TestMethodForVolumeIgnoresDeniedLabel(t *testing.T) {
+	scheduler, mockScanner, _, _, _ := createTestScheduler()
+	scheduler.config.MethodLabel = "volumeviz.scan-method"
+	mockScanner.On("GetAvailableMethods").Return([]interfaces.MethodInfo{
+		{Name: "diskus", Available: true},
+		{Name: "du", Available: true},
+		{Name: "native", Available: true},
+	})
+	labels := map[string]string{"volumeviz.scan-method": "native"}
+
+	assert.Equal(t, "native", scheduler.methodForVolume("nfs-data", labels, nil))
+	assert.Equal(t, "diskus", scheduler.methodForVolume("nfs-data", labels, []string{"native"}))
+}
+
+func TestEnqueueVolumeMinInterval(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.config.MinVolumeInterval = time.Minute
+
+	// Mark running without starting workers so queued tasks stay inspectable
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("volume-a"), nil)
+
+	scanID, err := scheduler.EnqueueVolume("volume-a")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+
+	// A rapid second scan of the same volume is throttled
+	scanID, err = scheduler.EnqueueVolume("volume-a")
+	assert.Empty(t, scanID)
+	var throttled *ThrottledError
+	if assert.ErrorAs(t, err, &throttled) {
+		assert.Equal(t, "volume-a", throttled.VolumeName)
+		assert.Greater(t, throttled.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, throttled.RetryAfter, time.Minute)
+	}
+
+	// A different volume proceeds
+	scanID, err = scheduler.EnqueueVolume("volume-b")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+
+	// Force bypasses the interval
+	scanID, err = scheduler.EnqueueVolumeWithOptions("volume-a", EnqueueOptions{Force: true})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+
+	assert.Len(t, scheduler.taskQueue, 3)
+}
+
+func TestEnqueueVolumeMinIntervalElapsed(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.config.MinVolumeInterval = time.Minute
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("volume-a"), nil)
+
+	scheduler.lastVolumeScan["volume-a"] = time.Now().Add(-2 * time.Minute)
+
+	scanID, err := scheduler.EnqueueVolume("volume-a")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+}
+
+func TestEnqueueAllVolumesSkipsRecentlyScanned(t *testing.T) {
+	scheduler, _, _, mockProvider, _ := createTestScheduler()
+	scheduler.config.MinVolumeInterval = time.Minute
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+
+	scheduler.lastVolumeScan["volume-a"] = time.Now()
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("volume-a"),
+		localVolume("volume-b"),
+	}, nil)
+
+	_, err := scheduler.EnqueueAllVolumes()
+	assert.NoError(t, err)
+
+	assert.Len(t, scheduler.taskQueue, 1)
+	task := <-scheduler.taskQueue
+	assert.Equal(t, "volume-b", task.VolumeName)
+}
+
+func TestEnqueueVolumeQueueFullReleasesInterval(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.config.MinVolumeInterval = time.Minute
+	scheduler.taskQueue = make(chan *ScanTask) // Unbuffered with no workers: always full
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("volume-a"), nil)
+
+	_, err := scheduler.EnqueueVolume("volume-a")
+	assert.ErrorContains(t, err, "scan queue full")
+
+	// The failed attempt must not count towards the interval
+	_, hadPrev := scheduler.lastVolumeScan["volume-a"]
+	assert.False(t, hadPrev)
+}
+
+func TestPauseStopsScheduledEnqueues(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("SetSchedulerPausedStatus", true).Once()
+	mockMetrics.On("SetSchedulerPausedStatus", false).Once()
+
+	assert.NoError(t, scheduler.Pause(false))
+
+	// Scheduled and batch scans do nothing while paused
+	scheduler.runScheduledScan(TriggerSourceScheduled, time.Now().Add(scheduler.config.Interval))
+	assert.Empty(t, scheduler.taskQueue)
+	mockProvider.AssertNotCalled(t, "ListVolumes", mock.Anything)
+
+	_, err := scheduler.EnqueueAllVolumes()
+	assert.ErrorIs(t, err, ErrSchedulerPaused)
+	_, err = scheduler.EnqueueVolume("volume-a")
+	assert.ErrorIs(t, err, ErrSchedulerPaused)
+
+	status := scheduler.GetStatus()
+	assert.True(t, status.Paused)
+	assert.NotNil(t, status.PausedAt)
+	assert.Nil(t, status.NextRunAt)
+	assert.Nil(t, status.LastRunAt)
+	assert.True(t, scheduler.GetMetrics().Paused)
+
+	// The periodic loop was told to stop its ticker
+	assert.Len(t, scheduler.pauseChanged, 1)
+	<-scheduler.pauseChanged
+
+	// Resuming restarts scheduled enqueues
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("volume-a"),
+	}, nil)
+	assert.NoError(t, scheduler.Resume())
+	assert.Len(t, scheduler.pauseChanged, 1)
+
+	scheduler.runScheduledScan(TriggerSourceScheduled, time.Now().Add(scheduler.config.Interval))
+	assert.Len(t, scheduler.taskQueue, 1)
+
+	status = scheduler.GetStatus()
+	assert.False(t, status.Paused)
+	assert.Nil(t, status.PausedAt)
+	assert.NotNil(t, status.LastRunAt)
+	assert.False(t, scheduler.GetMetrics().Paused)
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestPauseAllowingManualScans(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("SetSchedulerPausedStatus", true)
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("volume-a"), nil)
+
+	assert.NoError(t, scheduler.Pause(true))
+	assert.True(t, scheduler.GetStatus().AllowManualScans)
+
+	// Single-volume scans are accepted, batch scans are not
+	scanID, err := scheduler.EnqueueVolume("volume-a")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scanID)
+
+	_, err = scheduler.EnqueueAllVolumes()
+	assert.ErrorIs(t, err, ErrSchedulerPaused)
+}
+
+// runPeriodicLoop starts the periodic scheduler loop without workers, so
+// enqueued tasks stay in the queue; the returned function stops it
+func runPeriodicLoop(scheduler *Scheduler) func() {
+	scheduler.running = true
+	scheduler.ctx, scheduler.cancel = context.WithCancel(context.Background())
+	scheduler.schedulerWG.Add(1)
+	go scheduler.runPeriodicScheduler()
+	return func() {
+		scheduler.cancel()
+		scheduler.schedulerWG.Wait()
+	}
+}
+
+func TestStartupScanEnqueuesBatch(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.config.OnStartup = true
+	scheduler.config.StartupDelay = 10 * time.Millisecond
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{
+		localVolume("volume-a"),
+		localVolume("volume-b"),
+		localVolume("test_skipped"),
+	}, nil).Once()
+
+	stop := runPeriodicLoop(scheduler)
+	defer stop()
+
+	// Skip patterns still apply to the startup pass
+	assert.Eventually(t, func() bool { return len(scheduler.taskQueue) == 2 }, time.Second, 5*time.Millisecond)
+	for _, name := range []string{"volume-a", "volume-b"} {
+		task := <-scheduler.taskQueue
+		assert.Equal(t, name, task.VolumeName)
+		assert.Equal(t, 0, task.Priority, "startup scans are low priority")
+		assert.Equal(t, TriggerSourceStartup, task.TriggerSource)
+	}
+	assert.NotNil(t, scheduler.GetStatus().LastRunAt)
+}
+
+func TestStartupScanDisabledWaitsForInterval(t *testing.T) {
+	scheduler, _, _, mockProvider, _ := createTestScheduler()
+	scheduler.config.StartupDelay = time.Millisecond
+
+	stop := runPeriodicLoop(scheduler)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	assert.Empty(t, scheduler.taskQueue)
+	assert.Nil(t, scheduler.GetStatus().LastRunAt)
+	mockProvider.AssertNotCalled(t, "ListVolumes", mock.Anything)
+}
+
+func TestPauseRequiresRunningScheduler(t *testing.T) {
+	scheduler, _, _, _, _ := createTestScheduler()
+
+	assert.ErrorContains(t, scheduler.Pause(false), "scheduler not running")
+	assert.ErrorContains(t, scheduler.Resume(), "scheduler not running")
+}
+
+func TestEnqueueVolumeMethodLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   database.Labels
+		expected string
+	}{
+		{name: "valid override", labels: database.Labels{"volumeviz.scan.method": "native"}, expected: "native"},
+		{name: "invalid override falls back", labels: database.Labels{"volumeviz.scan.method": "rsync"}, expected: "diskus"},
+		{name: "unavailable method falls back", labels: database.Labels{"volumeviz.scan.method": "du"}, expected: "diskus"},
+		{name: "estimate-only method falls back", labels: database.Labels{"volumeviz.scan.method": "sample"}, expected: "diskus"},
+		{name: "no label", labels: database.Labels{"com.docker.compose.project": "shop"}, expected: "diskus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler, mockScanner, _, mockProvider, mockMetrics := createTestScheduler()
+			scheduler.config.MethodLabel = "volumeviz.scan.method"
+			scheduler.running = true
+			scheduler.ctx = context.Background()
+
+			mockProvider.On("GetVolume", mock.Anything, "data").Return(&database.Volume{
+				Name: "data", Driver: "local", Mountpoint: "/var/lib/docker/volumes/data/_data", Labels: tt.labels,
+			}, nil)
+			mockScanner.On("GetAvailableMethods").Return([]interfaces.MethodInfo{
+				{Name: "diskus", Available: true},
+				{Name: "du", Available: false},
+				{Name: "native", Available: true},
+				{Name: "sample", Available: true, Features: []string{"estimate_only"}},
+			}).Maybe()
+			mockMetrics.On("UpdateSchedulerQueueDepth", mock.Anything).Maybe()
+			mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.Anything).Maybe()
+
+			_, err := scheduler.EnqueueVolume("data")
+			assert.NoError(t, err)
+
+			task := <-scheduler.taskQueue
+			assert.Equal(t, tt.expected, task.Method)
+		})
+	}
+}
+
+func TestEnqueueRecordsTriggerSource(t *testing.T) {
+	scheduler, _, _, mockProvider, mockMetrics := createTestScheduler()
+	scheduler.running = true
+	scheduler.ctx = context.Background()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.AnythingOfType("int")).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Maybe()
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("volume-a"), nil)
+	mockProvider.On("ListVolumes", mock.Anything).Return([]*database.Volume{localVolume("volume-a")}, nil)
+
+	nextSource := func() string {
+		t.Helper()
+		require.Len(t, scheduler.taskQueue, 1)
+		return (<-scheduler.taskQueue).TriggerSource
+	}
+	resetThrottles := func() {
+		scheduler.lastVolumeScan = make(map[string]time.Time)
+		scheduler.lastEnqueueAll = time.Time{}
+	}
+
+	_, err := scheduler.EnqueueVolume("volume-a")
+	require.NoError(t, err)
+	assert.Equal(t, TriggerSourceManual, nextSource())
+
+	resetThrottles()
+	_, err = scheduler.EnqueueEventScan("volume-a")
+	require.NoError(t, err)
+	assert.Equal(t, TriggerSourceEvent, nextSource())
+
+	resetThrottles()
+	_, err = scheduler.EnqueueVolumeWithOptions("volume-a", EnqueueOptions{Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, TriggerSourceManual, nextSource(), "an unset source is a manual scan")
+
+	resetThrottles()
+	_, err = scheduler.EnqueueAllVolumes()
+	require.NoError(t, err)
+	assert.Equal(t, TriggerSourceManual, nextSource())
+
+	resetThrottles()
+	scheduler.runScheduledScan(TriggerSourceScheduled, time.Now().Add(scheduler.config.Interval))
+	assert.Equal(t, TriggerSourceScheduled, nextSource())
+}
+
+func TestWorkerPersistsTriggerSource(t *testing.T) {
+	scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+	worker := &worker{id: 0, scheduler: scheduler, ctx: context.Background()}
+
+	mockScanner.On("ScanVolume", mock.Anything, "volume-a").Return(&interfaces.ScanResult{VolumeID: "volume-a", TotalSize: 1, Method: "du"}, nil)
+	mockRepo.On("InsertScanRun", mock.Anything, mock.MatchedBy(func(run *database.ScanJob) bool {
+		return run.TriggerSource == TriggerSourceEvent
+	})).Return(nil).Once()
+	mockRepo.On("UpdateScanRun", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("InsertVolumeStats", mock.Anything, mock.Anything).Return(nil)
+	mockMetrics.On("ScanStarted", "du")
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.Anything)
+	mockMetrics.On("ScanCompleted", "volume-a", "du", mock.Anything, int64(1))
+	mockMetrics.On("ScanFinished", "du")
+
+	finished := testutil.ToFloat64(scansBySourceTotal.WithLabelValues(TriggerSourceEvent, "completed"))
+	worker.processTask(&ScanTask{
+		ScanID:        "scan-event",
+		VolumeName:    "volume-a",
+		Method:        "du",
+		CreatedAt:     time.Now(),
+		Timeout:       time.Second,
+		TriggerSource: TriggerSourceEvent,
+	})
+
+	mockRepo.AssertExpectations(t)
+	assert.Equal(t, finished+1, testutil.ToFloat64(scansBySourceTotal.WithLabelValues(TriggerSourceEvent, "completed")))
+}
@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// statsFlushTimeout bounds one flush of buffered volume stats. Flushes run
+// detached from the scheduler context so a shutdown cannot cut one short.
+const statsFlushTimeout = 30 * time.Second
+
+var (
+	// statsBufferedRows is the number of volume stats rows waiting to be committed
+	statsBufferedRows = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "volumeviz_scan_stats_buffered_rows",
+		Help: "Number of completed scan stats rows buffered for a batch commit",
+	})
+
+	// statsFlushedRowsTotal counts buffered volume stats rows by flush outcome
+	statsFlushedRowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "volumeviz_scan_stats_flushed_rows_total",
+		Help: "Total number of buffered scan stats rows flushed, by result (committed, failed)",
+	}, []string{"result"})
+
+	// statsBatchCommitsTotal counts transactions that committed a batch of volume stats
+	statsBatchCommitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "volumeviz_scan_stats_batch_commits_total",
+		Help: "Total number of transactions that committed a batch of buffered scan stats",
+	})
+)
+
+// statsWriter buffers the volume stats of completed scans and commits them in
+// batches, one transaction per batch, instead of one insert per scan. A batch
+// is committed once it holds batchSize rows, every interval, and on shutdown.
+type statsWriter struct {
+	repository ScanRepository
+	batcher    StatsBatchInserter
+	batchSize  int
+	interval   time.Duration // Zero flushes only full batches and on shutdown
+
+	mu      sync.Mutex
+	pending []*database.VolumeScanStats
+
+	// flushMu serializes flushes so batches are committed in order
+	flushMu sync.Mutex
+}
+
+// newStatsWriter creates a stats writer committing through batcher
+func newStatsWriter(repository ScanRepository, batcher StatsBatchInserter, batchSize int, interval time.Duration) *statsWriter {
+	return &statsWriter{
+		repository: repository,
+		batcher:    batcher,
+		batchSize:  batchSize,
+		interval:   interval,
+	}
+}
+
+// add buffers the stats of one scan, committing the batch once it is full
+func (w *statsWriter) add(stats *database.VolumeScanStats) {
+	w.mu.Lock()
+	w.pending = append(w.pending, stats)
+	full := len(w.pending) >= w.batchSize
+	w.mu.Unlock()
+	statsBufferedRows.Inc()
+
+	if full {
+		w.flush()
+	}
+}
+
+// buffered returns the number of rows waiting to be committed
+func (w *statsWriter) buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending)
+}
+
+// flush commits every buffered row in one transaction. When the transaction
+// fails the rows are inserted one by one instead, so a single bad row does not
+// lose the rest of the batch.
+func (w *statsWriter) flush() {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+	statsBufferedRows.Sub(float64(len(batch)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), statsFlushTimeout)
+	defer cancel()
+
+	err := w.batcher.InsertVolumeStatsBatch(ctx, batch)
+	if err == nil {
+		statsBatchCommitsTotal.Inc()
+		statsFlushedRowsTotal.WithLabelValues("committed").Add(float64(len(batch)))
+		return
+	}
+	log.Printf("[WARN] Failed to commit batch of %d volume stats, inserting them one by one: %v", len(batch), err)
+
+	failed := 0
+	for _, stats := range batch {
+		if err := w.repository.InsertVolumeStats(ctx, stats); err != nil {
+			log.Printf("[ERROR] Failed to insert volume stats for %s: %v", stats.VolumeName, err)
+			failed++
+		}
+	}
+	statsFlushedRowsTotal.WithLabelValues("committed").Add(float64(len(batch) - failed))
+	statsFlushedRowsTotal.WithLabelValues("failed").Add(float64(failed))
+}
+
+// run flushes buffered rows every interval until ctx is done. Rows still
+// buffered afterwards are left for a final flush.
+func (w *statsWriter) run(ctx context.Context) {
+	if w.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
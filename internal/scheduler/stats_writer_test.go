@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mantonx/volumeviz/internal/config"
+	"github.com/mantonx/volumeviz/internal/core/interfaces"
+	"github.com/mantonx/volumeviz/internal/database"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// batchingScanRepository records batch inserts on top of MockScanRepository
+type batchingScanRepository struct {
+	*MockScanRepository
+	mu       sync.Mutex
+	batches  [][]*database.VolumeScanStats
+	batchErr error
+}
+
+func (r *batchingScanRepository) InsertVolumeStatsBatch(ctx context.Context, batch []*database.VolumeScanStats) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.batchErr != nil {
+		return r.batchErr
+	}
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+// batchSizes returns the number of rows in each committed batch
+func (r *batchingScanRepository) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sizes := make([]int, len(r.batches))
+	for i, batch := range r.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestCompletedScansCommitStatsInBatches(t *testing.T) {
+	mockScanner := &MockVolumeScanner{}
+	repo := &batchingScanRepository{MockScanRepository: &MockScanRepository{}}
+	mockMetrics := &MockMetricsCollector{}
+	schedulerConfig := &SchedulerConfig{
+		ScanConfig: &config.ScanConfig{
+			Enabled:          true,
+			Interval:         time.Hour,
+			Concurrency:      1,
+			TimeoutPerVolume: 30 * time.Second,
+			MethodsOrder:     []string{"du"},
+			StatsBatchSize:   3,
+		},
+		QueueSize: 10,
+	}
+	scheduler, err := NewScheduler(schedulerConfig, mockScanner, repo, &MockVolumeProvider{}, mockMetrics)
+	require.NoError(t, err)
+	require.NotNil(t, scheduler.statsWriter)
+
+	mockScanner.On("ScanVolume", mock.Anything, mock.Anything).Return(&interfaces.ScanResult{TotalSize: 2048, Method: "du"}, nil)
+	repo.On("InsertScanRun", mock.Anything, mock.Anything).Return(nil)
+	repo.On("UpdateScanRun", mock.Anything, mock.Anything).Return(nil)
+	mockMetrics.On("SetSchedulerRunningStatus", mock.Anything).Maybe()
+	mockMetrics.On("UpdateSchedulerQueueDepth", mock.Anything).Maybe()
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.Anything).Maybe()
+	mockMetrics.On("ScanStarted", "du").Maybe()
+	mockMetrics.On("ScanCompleted", mock.Anything, "du", mock.Anything, int64(2048)).Maybe()
+	mockMetrics.On("ScanFinished", "du").Maybe()
+
+	require.NoError(t, scheduler.Start(context.Background()))
+	committedBefore := testutil.ToFloat64(statsFlushedRowsTotal.WithLabelValues("committed"))
+
+	worker := &worker{id: 0, scheduler: scheduler, ctx: context.Background()}
+	for i := 0; i < 7; i++ {
+		worker.processTask(&ScanTask{
+			ScanID:     fmt.Sprintf("scan-%d", i),
+			VolumeName: fmt.Sprintf("volume-%d", i),
+			Method:     "du",
+			Timeout:    30 * time.Second,
+		})
+	}
+
+	// Seven scans make two full batches; the seventh row waits for a flush
+	assert.Equal(t, []int{3, 3}, repo.batchSizes())
+	assert.Equal(t, 1, scheduler.statsWriter.buffered())
+
+	// Shutdown commits what is still buffered
+	stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, scheduler.Stop(stopCtx))
+
+	assert.Equal(t, []int{3, 3, 1}, repo.batchSizes())
+	assert.Equal(t, 0, scheduler.statsWriter.buffered())
+	assert.Equal(t, float64(7), testutil.ToFloat64(statsFlushedRowsTotal.WithLabelValues("committed"))-committedBefore)
+	repo.AssertNotCalled(t, "InsertVolumeStats", mock.Anything, mock.Anything)
+}
+
+func TestStatsWriterFlushesOnInterval(t *testing.T) {
+	repo := &batchingScanRepository{MockScanRepository: &MockScanRepository{}}
+	writer := newStatsWriter(repo, repo, 100, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go writer.run(ctx)
+
+	writer.add(&database.VolumeScanStats{VolumeName: "data"})
+	assert.Eventually(t, func() bool {
+		sizes := repo.batchSizes()
+		return len(sizes) == 1 && sizes[0] == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 0, writer.buffered())
+}
+
+func TestStatsWriterFallsBackToSingleInserts(t *testing.T) {
+	repo := &batchingScanRepository{MockScanRepository: &MockScanRepository{}, batchErr: errors.New("database is locked")}
+	writer := newStatsWriter(repo, repo, 2, 0)
+
+	good := &database.VolumeScanStats{VolumeName: "good"}
+	bad := &database.VolumeScanStats{VolumeName: "bad"}
+	repo.On("InsertVolumeStats", mock.Anything, good).Return(nil).Once()
+	repo.On("InsertVolumeStats", mock.Anything, bad).Return(errors.New("constraint failed")).Once()
+
+	failedBefore := testutil.ToFloat64(statsFlushedRowsTotal.WithLabelValues("failed"))
+	writer.add(good)
+	writer.add(bad)
+
+	repo.AssertExpectations(t)
+	assert.Empty(t, repo.batchSizes())
+	assert.Equal(t, float64(1), testutil.ToFloat64(statsFlushedRowsTotal.WithLabelValues("failed"))-failedBefore)
+}
+
+func TestRepositoryInsertVolumeStatsBatch(t *testing.T) {
+	db, err := database.NewDB(&database.Config{
+		Type:         database.DatabaseTypeSQLite,
+		Path:         filepath.Join(t.TempDir(), "stats.db"),
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+	})
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`
+		CREATE TABLE volume_stats (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			volume_name TEXT NOT NULL,
+			size_bytes INTEGER NOT NULL DEFAULT 0,
+			file_count INTEGER DEFAULT 0,
+			scan_method TEXT NOT NULL DEFAULT 'du',
+			scan_accuracy TEXT NOT NULL DEFAULT '',
+			duration_ms INTEGER DEFAULT 0,
+			ts DATETIME DEFAULT CURRENT_TIMESTAMP,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+
+	repo := NewRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.InsertVolumeStatsBatch(ctx, []*database.VolumeScanStats{
+		{VolumeName: "data", SizeBytes: 100, ScanMethod: "du", Timestamp: now.Add(-time.Minute)},
+		{VolumeName: "data", SizeBytes: 200, ScanMethod: "sample", ScanAccuracy: "estimated", Timestamp: now},
+	}))
+
+	stats, err := repo.GetVolumeStatsByName(ctx, "data", 10)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(200), stats[0].SizeBytes)
+	assert.Equal(t, "estimated", stats[0].ScanAccuracy)
+	assert.NoError(t, repo.InsertVolumeStatsBatch(ctx, nil))
+}
@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/mantonx/volumeviz/internal/config"
@@ -16,8 +18,71 @@ type ScanScheduler interface {
 	GetStatus() *SchedulerStatus
 	GetMetrics() *SchedulerMetrics
 	EnqueueVolume(volumeName string) (string, error)
+	EnqueueVolumeWithOptions(volumeName string, opts EnqueueOptions) (string, error)
+	EnqueueEventScan(volumeName string) (string, error)
 	EnqueueAllVolumes() (string, error)
 	GetScanStatus(scanID string) (*ScanStatus, error)
+	ListRecentScans(limit int, triggerSource string) ([]*ScanStatus, error)
+	Pause(allowManualScans bool) error
+	Resume() error
+	GetMethodsOrder() []string
+	SetMethodsOrder(order []string) error
+	MethodForVolume(volumeName string) string
+	QueuedTasks(limit int) ([]QueuedTask, int)
+}
+
+// ErrSchedulerPaused is returned for enqueues rejected while the scheduler is paused
+var ErrSchedulerPaused = errors.New("scheduler paused")
+
+// ErrInvalidMethodsOrder is returned when a scan method preference order is rejected
+var ErrInvalidMethodsOrder = errors.New("invalid scan methods order")
+
+// ErrScanDisabled is returned for enqueues of a volume whose scanning is switched off
+var ErrScanDisabled = errors.New("scanning disabled for volume")
+
+// ErrScanNotFound is returned for status lookups of a scan with no record
+var ErrScanNotFound = errors.New("scan not found")
+
+// Trigger sources record what enqueued a scan
+const (
+	TriggerSourceScheduled = "scheduled" // Periodic scan of all volumes
+	TriggerSourceStartup   = "startup"   // Scan of all volumes shortly after startup
+	TriggerSourceManual    = "manual"    // Requested through the API
+	TriggerSourceEvent     = "event"     // Docker event, e.g. a volume being created
+)
+
+// EnqueueOptions controls how a single volume scan is enqueued
+type EnqueueOptions struct {
+	// TriggerSource records what requested the scan; empty means TriggerSourceManual
+	TriggerSource string
+	// Force bypasses the per-volume minimum scan interval
+	Force bool
+	// OverrideScanDisabled scans the volume even when its scanning is switched off
+	OverrideScanDisabled bool
+}
+
+// ThrottledError is returned when a volume was scanned within the minimum interval
+type ThrottledError struct {
+	VolumeName string
+	RetryAfter time.Duration // Time until the volume is eligible again
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("volume %s scanned recently: try again in %v", e.VolumeName, e.RetryAfter)
+}
+
+// LockedError is returned for enqueues of a volume locked against scanning
+type LockedError struct {
+	VolumeName string
+	Lock       database.ScanLock
+}
+
+func (e *LockedError) Error() string {
+	holder := ""
+	if e.Lock.Holder != "" {
+		holder = " by " + e.Lock.Holder
+	}
+	return fmt.Sprintf("volume %s locked against scanning%s until %s", e.VolumeName, holder, e.Lock.Until.UTC().Format(time.RFC3339))
 }
 
 // ScanRepository defines database operations for scan persistence
@@ -32,18 +97,51 @@ type ScanRepository interface {
 	UpdateScanRun(ctx context.Context, run *database.ScanJob) error
 	GetScanRunByID(ctx context.Context, scanID string) (*database.ScanJob, error)
 	GetActiveScanRuns(ctx context.Context) ([]*database.ScanJob, error)
+	ListRecentScanRuns(ctx context.Context, limit int, triggerSource string) ([]*database.ScanJob, error)
 	
 	// Volume operations
 	ListVolumes(ctx context.Context) ([]*database.Volume, error)
 	UpsertVolume(ctx context.Context, volume *database.Volume) error
 }
 
+// StatsBatchInserter is implemented by repositories that can store many volume
+// stats rows in one transaction, which enables batched stats writes
+type StatsBatchInserter interface {
+	InsertVolumeStatsBatch(ctx context.Context, batch []*database.VolumeScanStats) error
+}
+
 // VolumeProvider defines interface for getting volume information
 type VolumeProvider interface {
 	ListVolumes(ctx context.Context) ([]*database.Volume, error)
 	GetVolume(ctx context.Context, volumeName string) (*database.Volume, error)
 }
 
+// SizeReporter receives the size of every successfully scanned volume, e.g. to
+// notify WebSocket clients of significant changes
+type SizeReporter interface {
+	ReportVolumeSize(volumeName string, sizeBytes int64)
+}
+
+// ScanToggles reports volumes whose scanning has been switched off one by one,
+// independently of the skip pattern
+type ScanToggles interface {
+	IsScanEnabled(ctx context.Context, volumeName string) (bool, error)
+	ScanDisabledVolumes(ctx context.Context) (map[string]bool, error)
+}
+
+// MethodDenials reports scan methods that must not be used for single
+// volumes, independently of the configured methods order
+type MethodDenials interface {
+	DeniedScanMethods(ctx context.Context, volumeName string) ([]string, error)
+}
+
+// ScanLocks reports volumes that external processes, such as backups writing
+// them, have locked against scanning for a while
+type ScanLocks interface {
+	ActiveScanLock(ctx context.Context, volumeName string, now time.Time) (*database.ScanLock, error)
+	ScanLockedVolumes(ctx context.Context, now time.Time) (map[string]database.ScanLock, error)
+}
+
 // ScanTask represents a scan task in the queue
 type ScanTask struct {
 	ScanID     string
@@ -54,6 +152,10 @@ type ScanTask struct {
 	Timeout    time.Duration
 	Retries    int
 	MaxRetries int
+	// TriggerSource records what enqueued the task, one of the TriggerSource constants
+	TriggerSource string
+	// DeniedMethods are never used to scan the volume, see MethodDenials
+	DeniedMethods []string
 }
 
 // ScanResult represents the result of a completed scan
@@ -79,6 +181,11 @@ type SchedulerStatus struct {
 	WorkerCount     int       `json:"worker_count"`
 	TotalCompleted  int64     `json:"total_completed"`
 	TotalFailed     int64     `json:"total_failed"`
+	
+	// Pause state; workers keep draining the queue while paused
+	Paused           bool       `json:"paused"`
+	PausedAt         *time.Time `json:"paused_at,omitempty"`
+	AllowManualScans bool       `json:"allow_manual_scans,omitempty"` // Single-volume scans accepted while paused
 }
 
 // SchedulerMetrics represents metrics for Prometheus
@@ -86,24 +193,27 @@ type SchedulerMetrics struct {
 	QueueDepth        int                    `json:"queue_depth"`
 	ActiveScans       int                    `json:"active_scans"`
 	CompletedScans    map[string]int64       `json:"completed_scans"`    // by status
-	ScanDurations     map[string]float64     `json:"scan_durations"`     // by method (avg seconds)
-	ErrorCounts       map[string]int64       `json:"error_counts"`       // by reason
+	ScanDurations     map[string]float64     `json:"scan_durations"`     // by method (mean seconds)
+	ErrorCounts       map[string]int64       `json:"error_counts"`       // by error code (e.g. PERMISSION_DENIED) or "enqueue"
 	WorkerUtilization float64                `json:"worker_utilization"` // percentage
+	Paused            bool                   `json:"paused"`
+	MethodBenchmarks  map[string]map[string]float64 `json:"method_benchmarks,omitempty"` // latest seconds by filesystem and method
 }
 
 // ScanStatus represents the status of a specific scan
 type ScanStatus struct {
-	ScanID      string     `json:"scan_id"`
-	VolumeName  string     `json:"volume_name"`
-	Status      string     `json:"status"` // queued, running, completed, failed, timeout
-	Method      string     `json:"method"`
-	Progress    int        `json:"progress"`
-	StartedAt   *time.Time `json:"started_at,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	Duration    *time.Duration `json:"duration,omitempty"`
-	SizeBytes   *int64     `json:"size_bytes,omitempty"`
-	FileCount   *int       `json:"file_count,omitempty"`
-	Error       string     `json:"error,omitempty"`
+	ScanID        string         `json:"scan_id"`
+	VolumeName    string         `json:"volume_name"`
+	Status        string         `json:"status"` // queued, running, completed, failed, timeout
+	Method        string         `json:"method"`
+	TriggerSource string         `json:"trigger_source"` // What enqueued the scan, e.g. scheduled or manual
+	Progress      int            `json:"progress"`
+	StartedAt     *time.Time     `json:"started_at,omitempty"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+	Duration      *time.Duration `json:"duration,omitempty"`
+	SizeBytes     *int64         `json:"size_bytes,omitempty"`
+	FileCount     *int           `json:"file_count,omitempty"`
+	Error         string         `json:"error,omitempty"`
 }
 
 // SchedulerConfig wraps the config.ScanConfig with additional runtime settings
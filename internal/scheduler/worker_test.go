@@ -95,7 +95,7 @@ func TestWorkerProcessTaskFailure(t *testing.T) {
 	// Expect metrics calls for failure
 	mockMetrics.On("ScanStarted", "diskus").Once()
 	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Times(2) // Start and end
-	mockMetrics.On("RecordScanFailure", "diskus", "scan_error").Once()
+	mockMetrics.On("RecordScanFailure", "diskus", "PERMISSION_DENIED").Once()
 	mockMetrics.On("ScanFinished", "diskus").Once()
 
 	// Process the task
@@ -109,7 +109,7 @@ func TestWorkerProcessTaskFailure(t *testing.T) {
 	// Verify failure metrics were updated
 	assert.Equal(t, int64(1), scheduler.status.TotalFailed)
 	assert.Equal(t, int64(1), scheduler.metrics.CompletedScans["failed"])
-	assert.Equal(t, int64(1), scheduler.metrics.ErrorCounts["scan_error"])
+	assert.Equal(t, int64(1), scheduler.metrics.ErrorCounts["PERMISSION_DENIED"])
 }
 
 func TestWorkerProcessTaskTimeout(t *testing.T) {
@@ -142,7 +142,7 @@ func TestWorkerProcessTaskTimeout(t *testing.T) {
 	// Expect metrics calls for failure
 	mockMetrics.On("ScanStarted", "diskus").Once()
 	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.AnythingOfType("float64")).Times(2) // Start and end
-	mockMetrics.On("RecordScanFailure", "diskus", "scan_error").Once()
+	mockMetrics.On("RecordScanFailure", "diskus", "SCAN_TIMEOUT").Once()
 	mockMetrics.On("ScanFinished", "diskus").Once()
 
 	// Process the task
@@ -274,7 +274,7 @@ func TestWorkerUpdateActiveScans(t *testing.T) {
 }
 
 func TestWorkerConcurrency(t *testing.T) {
-	scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+	scheduler, mockScanner, mockRepo, mockProvider, mockMetrics := createTestScheduler()
 	
 	// Start scheduler with multiple workers
 	ctx := context.Background()
@@ -309,6 +309,8 @@ func TestWorkerConcurrency(t *testing.T) {
 	mockRepo.On("UpdateScanRun", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*database.ScanJob")).Return(nil)
 	mockRepo.On("InsertVolumeStats", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*database.VolumeScanStats")).Return(nil)
 
+	mockProvider.On("GetVolume", mock.Anything, mock.AnythingOfType("string")).Return(localVolume("test-volume"), nil)
+
 	// Enqueue multiple volumes
 	scanIDs := make([]string, numTasks)
 	for i := 0; i < numTasks; i++ {
@@ -359,4 +361,101 @@ func TestWorkerShutdown(t *testing.T) {
 	err := scheduler.Stop(stopCtx)
 	assert.NoError(t, err)
 	assert.False(t, scheduler.IsRunning())
-}
\ No newline at end of file
+}
+// recordingSizeReporter captures reported sizes
+type recordingSizeReporter struct {
+	sizes map[string]int64
+}
+
+func (r *recordingSizeReporter) ReportVolumeSize(volumeName string, sizeBytes int64) {
+	r.sizes[volumeName] = sizeBytes
+}
+
+func TestWorkerProcessTaskReportsSize(t *testing.T) {
+	scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+	ctx := context.Background()
+
+	reporter := &recordingSizeReporter{sizes: make(map[string]int64)}
+	scheduler.SetSizeReporter(reporter)
+
+	worker := &worker{id: 0, scheduler: scheduler, ctx: ctx}
+	task := &ScanTask{
+		ScanID:     "test-scan-456",
+		VolumeName: "test-volume",
+		Method:     "du",
+		CreatedAt:  time.Now(),
+		Timeout:    30 * time.Second,
+	}
+
+	mockScanner.On("ScanVolume", mock.Anything, "test-volume").Return(&interfaces.ScanResult{
+		VolumeID:  "test-volume",
+		TotalSize: 2048,
+		Method:    "du",
+	}, nil)
+	mockRepo.On("InsertScanRun", ctx, mock.Anything).Return(nil)
+	mockRepo.On("UpdateScanRun", ctx, mock.Anything).Return(nil)
+	mockRepo.On("InsertVolumeStats", ctx, mock.Anything).Return(nil)
+	mockMetrics.On("ScanStarted", "du")
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.Anything)
+	mockMetrics.On("ScanCompleted", "test-volume", "du", mock.Anything, int64(2048))
+	mockMetrics.On("ScanFinished", "du")
+
+	worker.processTask(task)
+
+	assert.Equal(t, map[string]int64{"test-volume": 2048}, reporter.sizes)
+}
+
+func TestWorkerProcessTaskSlowDatabaseKeepsScanDeadline(t *testing.T) {
+	scheduler, mockScanner, mockRepo, _, mockMetrics := createTestScheduler()
+	scheduler.config.PersistTimeout = 50 * time.Millisecond
+
+	worker := &worker{id: 0, scheduler: scheduler, ctx: context.Background()}
+	task := &ScanTask{
+		ScanID:     "test-scan-slow-db",
+		VolumeName: "test-volume",
+		Method:     "du",
+		CreatedAt:  time.Now(),
+		Timeout:    time.Second,
+	}
+
+	// Recording the scan run is slow, but finishes within the persistence timeout
+	mockRepo.On("InsertScanRun", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		time.Sleep(30 * time.Millisecond)
+	}).Return(nil)
+
+	var scanTimeLeft time.Duration
+	mockScanner.On("ScanVolume", mock.Anything, "test-volume").Run(func(args mock.Arguments) {
+		deadline, ok := args.Get(0).(context.Context).Deadline()
+		assert.True(t, ok)
+		scanTimeLeft = time.Until(deadline)
+	}).Return(&interfaces.ScanResult{VolumeID: "test-volume", TotalSize: 2048, Method: "du"}, nil)
+
+	// Writing the results hangs until the persistence timeout cuts it off
+	var statsErr, updateErr error
+	mockRepo.On("InsertVolumeStats", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		<-ctx.Done()
+		statsErr = ctx.Err()
+	}).Return(context.DeadlineExceeded)
+	mockRepo.On("UpdateScanRun", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		<-ctx.Done()
+		updateErr = ctx.Err()
+	}).Return(context.DeadlineExceeded)
+
+	mockMetrics.On("ScanStarted", "du")
+	mockMetrics.On("UpdateSchedulerWorkerUtilization", mock.Anything)
+	mockMetrics.On("ScanCompleted", "test-volume", "du", mock.Anything, int64(2048))
+	mockMetrics.On("ScanFinished", "du")
+
+	start := time.Now()
+	worker.processTask(task)
+
+	// The slow write did not shorten the scan deadline
+	assert.Greater(t, scanTimeLeft, 900*time.Millisecond)
+	// Hanging writes gave up at the persistence timeout rather than the scan timeout
+	assert.ErrorIs(t, statsErr, context.DeadlineExceeded)
+	assert.ErrorIs(t, updateErr, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/mantonx/volumeviz/internal/config"
+)
+
+// NewHTTPServer creates the API server with the configured timeouts and
+// protocols. ReadHeaderTimeout keeps slow clients trickling headers
+// (slowloris) from holding connections open.
+func NewHTTPServer(handler http.Handler, cfg *config.ServerConfig) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		Protocols:         serverProtocols(cfg.HTTP2),
+	}
+}
+
+// serverProtocols returns the protocols the server accepts. HTTP/1.1 is always
+// served since WebSocket upgrades need it; HTTP/2 is negotiated over TLS and
+// spoken unencrypted (h2c) by clients that use it with prior knowledge.
+func serverProtocols(http2 bool) *http.Protocols {
+	protocols := &http.Protocols{}
+	protocols.SetHTTP1(true)
+	if http2 {
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	return protocols
+}
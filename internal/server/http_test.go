@@ -0,0 +1,155 @@
+package server
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
+	apiutils "github.com/mantonx/volumeviz/internal/api/utils"
+	"github.com/mantonx/volumeviz/internal/config"
+	"github.com/mantonx/volumeviz/internal/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveHTTP serves handler with cfg on a local port and returns its base URL
+func serveHTTP(t *testing.T, handler http.Handler, cfg *config.ServerConfig) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := NewHTTPServer(handler, cfg)
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+	return "http://" + listener.Addr().String()
+}
+
+// h2cClient speaks unencrypted HTTP/2 with prior knowledge
+func h2cClient() *http.Client {
+	protocols := &http.Protocols{}
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{Transport: &http.Transport{Protocols: protocols}, Timeout: time.Second}
+}
+
+func TestNewHTTPServer_AppliesTimeouts(t *testing.T) {
+	cfg := &config.ServerConfig{
+		ReadHeaderTimeout: 2 * time.Second,
+		ReadTimeout:       3 * time.Second,
+		WriteTimeout:      4 * time.Second,
+		IdleTimeout:       5 * time.Second,
+		HTTP2:             true,
+	}
+	srv := NewHTTPServer(http.NotFoundHandler(), cfg)
+
+	assert.Equal(t, 2*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 4*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 5*time.Second, srv.IdleTimeout)
+	assert.True(t, srv.Protocols.HTTP1())
+	assert.True(t, srv.Protocols.HTTP2())
+	assert.True(t, srv.Protocols.UnencryptedHTTP2())
+
+	srv = NewHTTPServer(http.NotFoundHandler(), &config.ServerConfig{})
+	assert.True(t, srv.Protocols.HTTP1())
+	assert.False(t, srv.Protocols.HTTP2())
+	assert.False(t, srv.Protocols.UnencryptedHTTP2())
+}
+
+func TestNewHTTPServer_ReadHeaderTimeoutDropsSlowClients(t *testing.T) {
+	url := serveHTTP(t, http.NotFoundHandler(), &config.ServerConfig{ReadHeaderTimeout: 50 * time.Millisecond})
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Headers that never finish are cut off instead of holding the connection
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, err = io.ReadAll(conn)
+	require.NoError(t, err, "the server should close the connection")
+}
+
+func TestNewHTTPServer_ServesH2C(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	})
+
+	url := serveHTTP(t, handler, &config.ServerConfig{HTTP2: true})
+	resp, err := h2cClient().Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", string(body))
+
+	// HTTP/1.1 clients are still served
+	resp, err = http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, resp.ProtoMajor)
+
+	url = serveHTTP(t, handler, &config.ServerConfig{HTTP2: false})
+	_, err = h2cClient().Get(url)
+	assert.Error(t, err)
+}
+
+func TestNewHTTPServer_WriteTimeoutExemptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const writeTimeout = 50 * time.Millisecond
+
+	slowResponse := func(disable bool) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if disable {
+				apiutils.DisableWriteTimeout(c)
+			}
+			time.Sleep(3 * writeTimeout)
+			c.String(http.StatusOK, "done")
+		}
+	}
+	router := gin.New()
+	router.GET("/bounded", slowResponse(false))
+	router.GET("/stream", slowResponse(true))
+
+	hub := websocket.NewHub()
+	go hub.Run()
+	router.GET("/ws", func(c *gin.Context) {
+		websocket.ServeWS(hub, c.Writer, c.Request)
+	})
+
+	url := serveHTTP(t, router, &config.ServerConfig{WriteTimeout: writeTimeout})
+
+	// An ordinary response slower than the write timeout is cut off
+	resp, err := http.Get(url + "/bounded")
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	assert.Error(t, err)
+
+	// A streaming response is not
+	resp, err = http.Get(url + "/stream")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+
+	// WebSocket clients keep receiving messages long after the write timeout
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http")+"/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Eventually(t, func() bool { return hub.GetClientCount() > 0 }, time.Second, time.Millisecond)
+
+	time.Sleep(3 * writeTimeout)
+	hub.BroadcastVolumeUpdate([]websocket.VolumeData{{ID: "data", Name: "data"}})
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "volume_update")
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// unixScheme prefixes a host that is a unix socket path rather than an IP or hostname
+const unixScheme = "unix://"
+
+// ListenAddress returns the network and address to listen on for a host and port.
+// A host of the form unix:///path/to.sock selects a unix socket and ignores the
+// port. IPv6 literals are bracketed, with or without brackets in the host.
+func ListenAddress(host, port string) (network, address string, err error) {
+	if path, ok := strings.CutPrefix(host, unixScheme); ok {
+		if path == "" {
+			return "", "", fmt.Errorf("unix listen address %q has no socket path", host)
+		}
+		return "unix", path, nil
+	}
+
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if port == "" {
+		return "", "", fmt.Errorf("no port to listen on for host %q", host)
+	}
+	return "tcp", net.JoinHostPort(host, port), nil
+}
+
+// Listen opens the listener for a host and port. A unix socket is created with
+// socketMode permissions, replacing a stale socket left by an earlier run; a
+// socket another process is still serving on is never removed.
+func Listen(host, port string, socketMode os.FileMode) (net.Listener, error) {
+	network, address, err := ListenAddress(host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, socketMode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set permissions of socket %s: %w", address, err)
+	}
+	return listener, nil
+}
+
+// removeStaleSocket removes a socket file nothing is listening on anymore
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("socket %s is in use by another process", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("failed to check socket %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
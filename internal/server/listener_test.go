@@ -0,0 +1,127 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		port        string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{name: "ipv4", host: "0.0.0.0", port: "8080", wantNetwork: "tcp", wantAddress: "0.0.0.0:8080"},
+		{name: "hostname", host: "localhost", port: "8080", wantNetwork: "tcp", wantAddress: "localhost:8080"},
+		{name: "all interfaces", host: "", port: "8080", wantNetwork: "tcp", wantAddress: ":8080"},
+		{name: "ipv6", host: "::1", port: "8080", wantNetwork: "tcp", wantAddress: "[::1]:8080"},
+		{name: "bracketed ipv6", host: "[::]", port: "8080", wantNetwork: "tcp", wantAddress: "[::]:8080"},
+		{name: "unix socket", host: "unix:///run/volumeviz.sock", port: "8080", wantNetwork: "unix", wantAddress: "/run/volumeviz.sock"},
+		{name: "unix socket without path", host: "unix://", port: "8080", wantErr: true},
+		{name: "missing port", host: "0.0.0.0", port: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, address, err := ListenAddress(tt.host, tt.port)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNetwork, network)
+			assert.Equal(t, tt.wantAddress, address)
+		})
+	}
+}
+
+// serveOnce serves the listener, makes one request and returns its status code
+func serveOnce(t *testing.T, listener net.Listener, client *http.Client, url string) int {
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestListen_IPv4(t *testing.T) {
+	listener, err := Listen("127.0.0.1", "0", 0660)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNoContent, serveOnce(t, listener, http.DefaultClient, "http://"+listener.Addr().String()))
+}
+
+func TestListen_IPv6(t *testing.T) {
+	listener, err := Listen("::1", "0", 0660)
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+
+	assert.Equal(t, http.StatusNoContent, serveOnce(t, listener, http.DefaultClient, "http://"+listener.Addr().String()))
+}
+
+func TestListen_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumeviz.sock")
+
+	listener, err := Listen("unix://"+path, "8080", 0600)
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	client := &http.Client{Transport: &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) { return net.Dial("unix", path) },
+	}}
+	assert.Equal(t, http.StatusNoContent, serveOnce(t, listener, client, "http://volumeviz/"))
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "socket should be removed when the listener closes")
+}
+
+func TestListen_UnixSocketReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumeviz.sock")
+
+	// Leave a socket file behind with nothing listening on it
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := Listen("unix://"+path, "", 0660)
+	require.NoError(t, err)
+	listener.Close()
+}
+
+func TestListen_UnixSocketInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumeviz.sock")
+
+	first, err := Listen("unix://"+path, "", 0660)
+	require.NoError(t, err)
+	defer first.Close()
+
+	_, err = Listen("unix://"+path, "", 0660)
+	assert.ErrorContains(t, err, "in use")
+}
+
+func TestListen_UnixSocketPathIsNotSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "volumeviz.sock")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+
+	_, err := Listen("unix://"+path, "", 0660)
+	assert.ErrorContains(t, err, "not a socket")
+}
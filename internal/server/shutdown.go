@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// lateStepTimeout is how long a step started after the shutdown deadline may
+// take, so releasing connections still gets a chance after a slow drain
+const lateStepTimeout = time.Second
+
+// ShutdownPhase orders the subsystems stopped by a graceful shutdown. Every
+// step of a phase finishes before the next phase starts.
+type ShutdownPhase int
+
+const (
+	// PhaseIntake stops accepting new work, e.g. the HTTP server
+	PhaseIntake ShutdownPhase = iota
+	// PhaseDrain finishes work already accepted: in-flight scans, their
+	// buffered stats and Docker events being processed
+	PhaseDrain
+	// PhaseBackground stops periodic background jobs
+	PhaseBackground
+	// PhaseRelease closes the connections everything above relied on
+	PhaseRelease
+)
+
+// shutdownStep is one subsystem stopped during shutdown
+type shutdownStep struct {
+	phase ShutdownPhase
+	name  string
+	stop  func(ctx context.Context) error
+}
+
+// Shutdown stops subsystems in phase order, and within a phase in the order
+// they were added, all within one deadline
+type Shutdown struct {
+	steps []shutdownStep
+}
+
+// NewShutdown creates an empty shutdown sequence
+func NewShutdown() *Shutdown {
+	return &Shutdown{}
+}
+
+// Add registers the stop function of a subsystem in a phase
+func (s *Shutdown) Add(phase ShutdownPhase, name string, stop func(ctx context.Context) error) {
+	s.steps = append(s.steps, shutdownStep{phase: phase, name: name, stop: stop})
+}
+
+// AddFunc registers a stop function that takes no context and cannot fail
+func (s *Shutdown) AddFunc(phase ShutdownPhase, name string, stop func()) {
+	s.Add(phase, name, func(context.Context) error {
+		stop()
+		return nil
+	})
+}
+
+// Run stops every registered subsystem. A step that fails, or is still
+// running when ctx expires, is logged and the remaining steps still run so
+// connections are released even after a slow drain. Run returns the errors of
+// all failed steps.
+func (s *Shutdown) Run(ctx context.Context) error {
+	steps := append([]shutdownStep(nil), s.steps...)
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].phase < steps[j].phase
+	})
+
+	var errs []error
+	for _, step := range steps {
+		if err := runShutdownStep(ctx, step); err != nil {
+			log.Printf("[ERROR] Failed to stop %s: %v", step.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+			continue
+		}
+		log.Printf("[INFO] Stopped %s", step.name)
+	}
+	return errors.Join(errs...)
+}
+
+// runShutdownStep runs one step, giving up on it when ctx expires first. A
+// step started after the deadline is waited for up to lateStepTimeout.
+func runShutdownStep(ctx context.Context, step shutdownStep) error {
+	wait := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		wait, cancel = context.WithTimeout(context.WithoutCancel(ctx), lateStepTimeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- step.stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-wait.Done():
+		// A step that ignores the deadline is left behind rather than holding up the rest
+		return fmt.Errorf("did not stop before the shutdown deadline: %w", context.DeadlineExceeded)
+	}
+}
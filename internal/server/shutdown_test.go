@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stopRecorder records the order subsystems are stopped in
+type stopRecorder struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (r *stopRecorder) stop(name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.order = append(r.order, name)
+		return nil
+	}
+}
+
+func (r *stopRecorder) stopped() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.order...)
+}
+
+func TestShutdown_StopsSubsystemsInOrder(t *testing.T) {
+	recorder := &stopRecorder{}
+	shutdown := NewShutdown()
+
+	// Registered in startup order, as subsystems come up, rather than stop order
+	shutdown.Add(PhaseRelease, "database", recorder.stop("database"))
+	shutdown.AddFunc(PhaseBackground, "retention service", func() { recorder.stop("retention service")(nil) })
+	shutdown.Add(PhaseRelease, "Docker client", recorder.stop("Docker client"))
+	shutdown.Add(PhaseDrain, "scan scheduler", recorder.stop("scan scheduler"))
+	shutdown.Add(PhaseDrain, "events service", recorder.stop("events service"))
+	shutdown.Add(PhaseBackground, "database optimizer", recorder.stop("database optimizer"))
+	shutdown.Add(PhaseIntake, "HTTP server", recorder.stop("HTTP server"))
+
+	require.NoError(t, shutdown.Run(context.Background()))
+	assert.Equal(t, []string{
+		"HTTP server",
+		"scan scheduler",
+		"events service",
+		"retention service",
+		"database optimizer",
+		"database",
+		"Docker client",
+	}, recorder.stopped())
+}
+
+func TestShutdown_FailedStepDoesNotStopTheRest(t *testing.T) {
+	recorder := &stopRecorder{}
+	shutdown := NewShutdown()
+	shutdown.Add(PhaseDrain, "scan scheduler", func(ctx context.Context) error {
+		return errors.New("flush failed")
+	})
+	shutdown.Add(PhaseRelease, "database", recorder.stop("database"))
+
+	err := shutdown.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scan scheduler: flush failed")
+	assert.Equal(t, []string{"database"}, recorder.stopped())
+}
+
+func TestShutdown_DeadlineSkipsStuckStep(t *testing.T) {
+	recorder := &stopRecorder{}
+	shutdown := NewShutdown()
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	shutdown.AddFunc(PhaseDrain, "events service", func() { <-stuck })
+	shutdown.Add(PhaseRelease, "database", recorder.stop("database"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := shutdown.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Connections are still released after the deadline
+	assert.Equal(t, []string{"database"}, recorder.stopped())
+}
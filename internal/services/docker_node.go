@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/mantonx/volumeviz/internal/models"
+)
+
+// infoClient is implemented by Docker clients that can describe the daemon they talk to
+type infoClient interface {
+	Info(ctx context.Context) (system.Info, error)
+}
+
+// dockerNode identifies the node the Docker daemon runs on
+type dockerNode struct {
+	id   string // Swarm node ID; empty outside a Swarm
+	name string
+}
+
+// localNode returns the Swarm node of the daemon, or the "local" node on hosts
+// that are not in a Swarm or whose client cannot report daemon info. A Swarm
+// node is looked up once; failed lookups are retried on the next call.
+func (s *DockerService) localNode(ctx context.Context) dockerNode {
+	s.nodeMu.Lock()
+	defer s.nodeMu.Unlock()
+
+	if s.node != nil {
+		return *s.node
+	}
+
+	node := dockerNode{name: models.LocalNodeName}
+	client, ok := s.client.(infoClient)
+	if !ok {
+		return node
+	}
+
+	info, err := client.Info(ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed to get Docker info, reporting volumes on node %q: %v", models.LocalNodeName, err)
+		return node
+	}
+
+	if info.Swarm.LocalNodeState == swarm.LocalNodeStateActive && info.Swarm.NodeID != "" {
+		node = dockerNode{id: info.Swarm.NodeID, name: info.Name}
+		if node.name == "" {
+			node.name = info.Swarm.NodeID
+		}
+	}
+	s.node = &node
+	return node
+}
+
+// volumeNode returns the node a volume lives on. Swarm cluster volumes report
+// the node they are published to; every other volume is local to the daemon.
+func volumeNode(vol volume.Volume, local dockerNode) string {
+	if vol.ClusterVolume != nil {
+		for _, status := range vol.ClusterVolume.PublishStatus {
+			if status == nil || status.State != volume.StatePublished || status.NodeID == "" {
+				continue
+			}
+			if status.NodeID == local.id {
+				return local.name
+			}
+			return status.NodeID
+		}
+	}
+	return local.name
+}
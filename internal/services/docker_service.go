@@ -5,11 +5,14 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
+	mounttypes "github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/mantonx/volumeviz/internal/interfaces"
 	"github.com/mantonx/volumeviz/internal/models"
@@ -21,6 +24,9 @@ import (
 // Wraps the Docker client with business logic and error handling
 type DockerService struct {
 	client interfaces.DockerClient
+
+	nodeMu sync.Mutex
+	node   *dockerNode // Cached once the daemon's Swarm node is known
 }
 
 // NewDockerService creates a new Docker service instance
@@ -68,6 +74,22 @@ func (s *DockerService) GetVersion(ctx context.Context) (types.Version, error) {
 	return s.client.Version(ctx)
 }
 
+// apiVersionClient is implemented by Docker clients that negotiate the API
+// version they speak with the daemon
+type apiVersionClient interface {
+	ClientVersion() string
+}
+
+// NegotiatedAPIVersion returns the Docker API version requests are made
+// with, or "" when the client does not report it
+func (s *DockerService) NegotiatedAPIVersion() string {
+	client, ok := s.client.(apiVersionClient)
+	if !ok {
+		return ""
+	}
+	return client.ClientVersion()
+}
+
 // Version is an alias for GetVersion to satisfy the DockerClient interface
 func (s *DockerService) Version(ctx context.Context) (types.Version, error) {
 	return s.client.Version(ctx)
@@ -76,14 +98,19 @@ func (s *DockerService) Version(ctx context.Context) (types.Version, error) {
 // ListVolumes returns all Docker volumes with metadata
 func (s *DockerService) ListVolumes(ctx context.Context) ([]models.Volume, error) {
 	// List all volumes
+	if err := interfaces.SpendDockerCall(ctx); err != nil {
+		return nil, err
+	}
 	volumeResp, err := s.client.ListVolumes(ctx, nil)
 	if err != nil {
 		return nil, utils.WrapError(err, "failed to list volumes")
 	}
 
+	node := s.localNode(ctx)
 	volumes := make([]models.Volume, 0, len(volumeResp.Volumes))
 	for _, vol := range volumeResp.Volumes {
 		volume := s.convertToVolumeModel(*vol)
+		volume.Node = volumeNode(*vol, node)
 		volumes = append(volumes, volume)
 	}
 
@@ -93,6 +120,9 @@ func (s *DockerService) ListVolumes(ctx context.Context) ([]models.Volume, error
 // GetVolume returns detailed information about a specific volume
 func (s *DockerService) GetVolume(ctx context.Context, volumeID string) (*models.Volume, error) {
 	// Inspect the volume
+	if err := interfaces.SpendDockerCall(ctx); err != nil {
+		return nil, err
+	}
 	vol, err := s.client.InspectVolume(ctx, volumeID)
 	if err != nil {
 		return nil, utils.WrapErrorf(err, "failed to get volume %s", volumeID)
@@ -100,12 +130,16 @@ func (s *DockerService) GetVolume(ctx context.Context, volumeID string) (*models
 
 	// Convert to our model
 	volume := s.convertToVolumeModel(vol)
+	volume.Node = volumeNode(vol, s.localNode(ctx))
 	return &volume, nil
 }
 
 // GetVolumeContainers returns all containers using a specific volume
 func (s *DockerService) GetVolumeContainers(ctx context.Context, volumeName string) ([]models.VolumeContainer, error) {
 	// List all containers
+	if err := interfaces.SpendDockerCall(ctx); err != nil {
+		return nil, err
+	}
 	containers, err := s.client.ListContainers(ctx, nil)
 	if err != nil {
 		return nil, utils.WrapError(err, "failed to list containers")
@@ -115,7 +149,11 @@ func (s *DockerService) GetVolumeContainers(ctx context.Context, volumeName stri
 
 	// Check each container for the volume mount
 	for _, container := range containers {
-		// Inspect container to get detailed mount information
+		// Inspect container to get detailed mount information; a volume whose
+		// containers were not all inspected has no reliable attachments
+		if err := interfaces.SpendDockerCall(ctx); err != nil {
+			return nil, err
+		}
 		containerInfo, err := s.client.InspectContainer(ctx, container.ID)
 		if err != nil {
 			// Skip this container if we can't inspect it
@@ -141,6 +179,11 @@ func (s *DockerService) GetVolumeContainers(ctx context.Context, volumeName stri
 					volumeContainer.AccessMode = "ro"
 				}
 
+				// Propagation is Linux-only and consistency only applies on Mac/Windows;
+				// both stay empty when Docker does not report them
+				volumeContainer.Propagation = string(mount.Propagation)
+				volumeContainer.Consistency = mountConsistency(containerInfo, mount)
+
 				volumeContainers = append(volumeContainers, volumeContainer)
 				break // Found the volume, no need to check other mounts
 			}
@@ -203,14 +246,19 @@ func (s *DockerService) GetVolumesByDriver(ctx context.Context, driver string) (
 		"driver": {driver},
 	}
 
+	if err := interfaces.SpendDockerCall(ctx); err != nil {
+		return nil, err
+	}
 	volumeResp, err := s.client.ListVolumes(ctx, filterMap)
 	if err != nil {
 		return nil, utils.WrapErrorf(err, "failed to list volumes by driver %s", driver)
 	}
 
+	node := s.localNode(ctx)
 	volumes := make([]models.Volume, 0, len(volumeResp.Volumes))
 	for _, vol := range volumeResp.Volumes {
 		volume := s.convertToVolumeModel(*vol)
+		volume.Node = volumeNode(*vol, node)
 		volumes = append(volumes, volume)
 	}
 
@@ -228,14 +276,19 @@ func (s *DockerService) GetVolumesByLabel(ctx context.Context, labelKey, labelVa
 		"label": {labelFilter},
 	}
 
+	if err := interfaces.SpendDockerCall(ctx); err != nil {
+		return nil, err
+	}
 	volumeResp, err := s.client.ListVolumes(ctx, filterMap)
 	if err != nil {
 		return nil, utils.WrapErrorf(err, "failed to list volumes by label %s", labelKey)
 	}
 
+	node := s.localNode(ctx)
 	volumes := make([]models.Volume, 0, len(volumeResp.Volumes))
 	for _, vol := range volumeResp.Volumes {
 		volume := s.convertToVolumeModel(*vol)
+		volume.Node = volumeNode(*vol, node)
 		volumes = append(volumes, volume)
 	}
 
@@ -266,6 +319,41 @@ func (s *DockerService) InspectVolume(ctx context.Context, volumeID string) (vol
 	return s.client.InspectVolume(ctx, volumeID)
 }
 
+// volumeRemoverClient is implemented by Docker clients that can remove volumes
+type volumeRemoverClient interface {
+	RemoveVolume(ctx context.Context, volumeID string, force bool) error
+}
+
+// RemoveVolume removes a volume that is not in use by any container
+func (s *DockerService) RemoveVolume(ctx context.Context, volumeName string) error {
+	client, ok := s.client.(volumeRemoverClient)
+	if !ok {
+		return fmt.Errorf("docker client cannot remove volumes")
+	}
+	return client.RemoveVolume(ctx, volumeName, false)
+}
+
+// mountConsistency returns the consistency option of a mount point. Mounts created
+// with --mount carry it in HostConfig.Mounts; -v mounts list it among the mode options.
+func mountConsistency(info containertypes.InspectResponse, mountPoint containertypes.MountPoint) string {
+	if info.ContainerJSONBase != nil && info.HostConfig != nil {
+		for _, m := range info.HostConfig.Mounts {
+			if m.Target == mountPoint.Destination && m.Consistency != "" {
+				return string(m.Consistency)
+			}
+		}
+	}
+
+	for _, option := range strings.Split(mountPoint.Mode, ",") {
+		switch consistency := mounttypes.Consistency(strings.TrimSpace(option)); consistency {
+		case mounttypes.ConsistencyFull, mounttypes.ConsistencyCached, mounttypes.ConsistencyDelegated:
+			return string(consistency)
+		}
+	}
+
+	return ""
+}
+
 // InspectContainer is an alias for GetVolumeContainers' internal implementation
 // Returns detailed container information in the Docker API format
 func (s *DockerService) InspectContainer(ctx context.Context, containerID string) (containertypes.InspectResponse, error) {
@@ -9,7 +9,10 @@ import (
 	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/mantonx/volumeviz/internal/interfaces"
 	"github.com/mantonx/volumeviz/internal/mocks"
 	"github.com/mantonx/volumeviz/internal/models"
 )
@@ -396,6 +399,120 @@ func TestDockerService_GetVolumeContainers(t *testing.T) {
 	}
 }
 
+func TestDockerService_GetVolumeContainers_MountOptions(t *testing.T) {
+	volumeName := "test-volume"
+
+	containerJSONs := map[string]containertypes.InspectResponse{
+		// --mount with explicit propagation and consistency
+		"container1": {
+			ContainerJSONBase: &containertypes.ContainerJSONBase{
+				ID:   "container1",
+				Name: "/mounted",
+				HostConfig: &containertypes.HostConfig{
+					Mounts: []mount.Mount{
+						{Type: mount.TypeVolume, Source: volumeName, Target: "/data", Consistency: mount.ConsistencyCached},
+					},
+				},
+			},
+			Mounts: []containertypes.MountPoint{
+				{Type: mount.TypeVolume, Name: volumeName, Destination: "/data", RW: true, Propagation: mount.PropagationRShared},
+			},
+		},
+		// -v with consistency in the mode options
+		"container2": {
+			ContainerJSONBase: &containertypes.ContainerJSONBase{
+				ID:   "container2",
+				Name: "/legacy",
+			},
+			Mounts: []containertypes.MountPoint{
+				{Type: mount.TypeVolume, Name: volumeName, Destination: "/backup", Mode: "ro,delegated", Propagation: mount.PropagationSlave},
+			},
+		},
+		// Nothing reported: fields stay empty
+		"container3": {
+			ContainerJSONBase: &containertypes.ContainerJSONBase{
+				ID:   "container3",
+				Name: "/plain",
+			},
+			Mounts: []containertypes.MountPoint{
+				{Type: mount.TypeVolume, Name: volumeName, Destination: "/plain", Mode: "z", RW: true},
+			},
+		},
+	}
+
+	mockClient := &mocks.MockDockerClient{
+		ListContainersFunc: func(ctx context.Context, filterMap map[string][]string) ([]containertypes.Summary, error) {
+			return []containertypes.Summary{{ID: "container1"}, {ID: "container2"}, {ID: "container3"}}, nil
+		},
+		InspectContainerFunc: func(ctx context.Context, containerID string) (containertypes.InspectResponse, error) {
+			return containerJSONs[containerID], nil
+		},
+	}
+
+	service := NewDockerServiceWithClient(mockClient)
+	containers, err := service.GetVolumeContainers(context.Background(), volumeName)
+	if err != nil {
+		t.Fatalf("GetVolumeContainers() error = %v", err)
+	}
+	if len(containers) != 3 {
+		t.Fatalf("GetVolumeContainers() count = %v, want 3", len(containers))
+	}
+
+	want := []struct {
+		propagation string
+		consistency string
+	}{
+		{"rshared", "cached"},
+		{"slave", "delegated"},
+		{"", ""},
+	}
+	for i, w := range want {
+		if containers[i].Propagation != w.propagation {
+			t.Errorf("Container[%d] Propagation = %q, want %q", i, containers[i].Propagation, w.propagation)
+		}
+		if containers[i].Consistency != w.consistency {
+			t.Errorf("Container[%d] Consistency = %q, want %q", i, containers[i].Consistency, w.consistency)
+		}
+	}
+}
+
+func TestDockerService_DockerCallBudget(t *testing.T) {
+	mockClient := &mocks.MockDockerClient{
+		ListContainersFunc: func(ctx context.Context, filterMap map[string][]string) ([]containertypes.Summary, error) {
+			return []containertypes.Summary{{ID: "container1"}, {ID: "container2"}}, nil
+		},
+		InspectContainerFunc: func(ctx context.Context, containerID string) (containertypes.InspectResponse, error) {
+			return containertypes.InspectResponse{ContainerJSONBase: &containertypes.ContainerJSONBase{ID: containerID}}, nil
+		},
+	}
+	service := NewDockerServiceWithClient(mockClient)
+
+	// Listing the containers of a volume takes a call plus one per container
+	ctx := interfaces.WithDockerCallBudget(context.Background(), 3)
+	if _, err := service.GetVolumeContainers(ctx, "test-volume"); err != nil {
+		t.Fatalf("GetVolumeContainers() within budget error = %v", err)
+	}
+	if interfaces.DockerCallBudgetExhausted(ctx) {
+		t.Error("DockerCallBudgetExhausted() = true after spending exactly the budget")
+	}
+
+	_, err := service.GetVolumeContainers(ctx, "test-volume")
+	if !errors.Is(err, interfaces.ErrDockerCallBudgetExhausted) {
+		t.Errorf("GetVolumeContainers() over budget error = %v, want %v", err, interfaces.ErrDockerCallBudgetExhausted)
+	}
+	if !interfaces.DockerCallBudgetExhausted(ctx) {
+		t.Error("DockerCallBudgetExhausted() = false after a refused call")
+	}
+	if mockClient.ListContainersCalls != 1 || mockClient.InspectContainerCalls != 2 {
+		t.Errorf("Docker calls = %d lists and %d inspects, want 1 and 2", mockClient.ListContainersCalls, mockClient.InspectContainerCalls)
+	}
+
+	// A context without a budget is unlimited
+	if _, err := service.GetVolumeContainers(context.Background(), "test-volume"); err != nil {
+		t.Errorf("GetVolumeContainers() without budget error = %v", err)
+	}
+}
+
 func TestDockerService_IsDockerAvailable(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -721,3 +838,112 @@ func TestDockerService_GetVolumesByLabel(t *testing.T) {
 		})
 	}
 }
+
+// swarmDockerClient adds daemon info to the mock client
+type swarmDockerClient struct {
+	*mocks.MockDockerClient
+	info      system.Info
+	infoErr   error
+	infoCalls int
+}
+
+func (c *swarmDockerClient) Info(ctx context.Context) (system.Info, error) {
+	c.infoCalls++
+	return c.info, c.infoErr
+}
+
+func TestDockerService_VolumeNodes(t *testing.T) {
+	volumes := volume.ListResponse{
+		Volumes: []*volume.Volume{
+			{Name: "local-data", Driver: "local"},
+			{Name: "cluster-remote", Driver: "csi", ClusterVolume: &volume.ClusterVolume{
+				PublishStatus: []*volume.PublishStatus{
+					{NodeID: "node-b", State: volume.StatePending},
+					{NodeID: "node-c", State: volume.StatePublished},
+				},
+			}},
+			{Name: "cluster-here", Driver: "csi", ClusterVolume: &volume.ClusterVolume{
+				PublishStatus: []*volume.PublishStatus{{NodeID: "node-a", State: volume.StatePublished}},
+			}},
+		},
+	}
+	listVolumes := func(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
+		return volumes, nil
+	}
+
+	tests := []struct {
+		name    string
+		client  func() interfaces.DockerClient
+		wantErr bool
+		want    map[string]string
+	}{
+		{
+			name: "client without daemon info",
+			client: func() interfaces.DockerClient {
+				return &mocks.MockDockerClient{ListVolumesFunc: listVolumes}
+			},
+			want: map[string]string{"local-data": "local", "cluster-remote": "node-c", "cluster-here": "node-a"},
+		},
+		{
+			name: "host outside a swarm",
+			client: func() interfaces.DockerClient {
+				return &swarmDockerClient{
+					MockDockerClient: &mocks.MockDockerClient{ListVolumesFunc: listVolumes},
+					info:             system.Info{Name: "host-a", Swarm: swarm.Info{LocalNodeState: swarm.LocalNodeStateInactive}},
+				}
+			},
+			want: map[string]string{"local-data": "local", "cluster-remote": "node-c", "cluster-here": "node-a"},
+		},
+		{
+			name: "swarm node",
+			client: func() interfaces.DockerClient {
+				return &swarmDockerClient{
+					MockDockerClient: &mocks.MockDockerClient{ListVolumesFunc: listVolumes},
+					info:             system.Info{Name: "host-a", Swarm: swarm.Info{NodeID: "node-a", LocalNodeState: swarm.LocalNodeStateActive}},
+				}
+			},
+			want: map[string]string{"local-data": "host-a", "cluster-remote": "node-c", "cluster-here": "host-a"},
+		},
+		{
+			name: "daemon info error",
+			client: func() interfaces.DockerClient {
+				return &swarmDockerClient{
+					MockDockerClient: &mocks.MockDockerClient{ListVolumesFunc: listVolumes},
+					infoErr:          errors.New("connection refused"),
+				}
+			},
+			want: map[string]string{"local-data": "local", "cluster-remote": "node-c", "cluster-here": "node-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewDockerServiceWithClient(tt.client())
+
+			result, err := service.ListVolumes(context.Background())
+			if err != nil {
+				t.Fatalf("ListVolumes() error = %v", err)
+			}
+			for _, vol := range result {
+				if vol.Node != tt.want[vol.Name] {
+					t.Errorf("ListVolumes() volume %s Node = %q, want %q", vol.Name, vol.Node, tt.want[vol.Name])
+				}
+			}
+		})
+	}
+
+	// A known swarm node is looked up once
+	client := &swarmDockerClient{
+		MockDockerClient: &mocks.MockDockerClient{ListVolumesFunc: listVolumes},
+		info:             system.Info{Name: "host-a", Swarm: swarm.Info{NodeID: "node-a", LocalNodeState: swarm.LocalNodeStateActive}},
+	}
+	service := NewDockerServiceWithClient(client)
+	for i := 0; i < 3; i++ {
+		if _, err := service.ListVolumes(context.Background()); err != nil {
+			t.Fatalf("ListVolumes() error = %v", err)
+		}
+	}
+	if client.infoCalls != 1 {
+		t.Errorf("Info() called %d times, want 1", client.infoCalls)
+	}
+}
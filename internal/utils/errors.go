@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+
+	cerrdefs "github.com/containerd/errdefs"
 )
 
 // WrapError wraps an error with additional context
@@ -24,14 +27,49 @@ func WrapErrorf(err error, format string, args ...interface{}) error {
 }
 
 // IsNotFound checks if error indicates a not found condition
-// Checks for common not found error patterns
+// Typed errors decide first: the Docker client's not-found errors, ErrNotFound
+// and sql.ErrNoRows. Message patterns are only a last resort for untyped
+// errors, so an error Docker classified otherwise is never taken for not found.
 func IsNotFound(err error) bool {
 	if err == nil {
 		return false
 	}
-	errStr := err.Error()
-	return errors.Is(err, ErrNotFound) ||
-		ContainsAny(errStr, "not found", "no rows", "does not exist")
+	if cerrdefs.IsNotFound(err) || errors.Is(err, ErrNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return true
+	}
+	if isClassified(err) {
+		return false
+	}
+	return ContainsAny(err.Error(), "not found", "no such", "no rows", "does not exist", "doesn't exist")
+}
+
+// isClassified reports whether err carries an errdefs class other than not
+// found, as the Docker client attaches to every error response
+func isClassified(err error) bool {
+	for _, is := range []func(error) bool{
+		cerrdefs.IsUnknown,
+		cerrdefs.IsInvalidArgument,
+		cerrdefs.IsAlreadyExists,
+		cerrdefs.IsPermissionDenied,
+		cerrdefs.IsResourceExhausted,
+		cerrdefs.IsFailedPrecondition,
+		cerrdefs.IsConflict,
+		cerrdefs.IsNotModified,
+		cerrdefs.IsAborted,
+		cerrdefs.IsOutOfRange,
+		cerrdefs.IsNotImplemented,
+		cerrdefs.IsInternal,
+		cerrdefs.IsUnavailable,
+		cerrdefs.IsDataLoss,
+		cerrdefs.IsUnauthorized,
+		cerrdefs.IsCanceled,
+		cerrdefs.IsDeadlineExceeded,
+	} {
+		if is(err) {
+			return true
+		}
+	}
+	return false
 }
 
 // Common error types for consistent error handling
@@ -1,8 +1,13 @@
 package utils
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
+
+	"github.com/docker/docker/errdefs"
 )
 
 func TestWrapError(t *testing.T) {
@@ -60,6 +65,13 @@ func TestIsNotFound(t *testing.T) {
 		{"contains no rows", errors.New("sql: no rows in result set"), true},
 		{"contains does not exist", errors.New("table does not exist"), true},
 		{"other error", errors.New("connection timeout"), false},
+		{"Docker not found", errdefs.NotFound(errors.New("get gone: volume is missing")), true},
+		{"wrapped Docker not found", fmt.Errorf("failed to get volume gone: %w", errdefs.NotFound(errors.New("Error response from daemon"))), true},
+		{"wrapped sql.ErrNoRows", fmt.Errorf("failed to get volume: %w", sql.ErrNoRows), true},
+		// A class other than not found wins over the message
+		{"Docker system error", errdefs.System(errors.New("plugin not found in cache")), false},
+		{"Docker unavailable", fmt.Errorf("failed to get volume: %w", errdefs.Unavailable(errors.New("no such host"))), false},
+		{"canceled", fmt.Errorf("lookup not found before: %w", context.Canceled), false},
 	}
 
 	for _, tt := range tests {
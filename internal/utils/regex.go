@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+)
+
+// Limits for user-supplied regular expressions. Go's RE2 engine matches in
+// time linear in the input, so bounding the pattern, its compiled program and
+// the input bounds the work done by every match.
+const (
+	// MaxPatternLength is the longest pattern CompilePattern accepts
+	MaxPatternLength = 512
+
+	// MaxPatternProgramSize bounds the compiled program, which nested counted
+	// repetitions such as (a{0,30}){0,30} can blow up far beyond the pattern length
+	MaxPatternProgramSize = 1000
+
+	// MaxPatternInputLength is the longest input a Pattern matches against;
+	// longer inputs never match
+	MaxPatternInputLength = 4096
+)
+
+var (
+	// ErrPatternTooLong is returned for patterns over MaxPatternLength
+	ErrPatternTooLong = errors.New("pattern too long")
+
+	// ErrPatternTooComplex is returned for patterns whose compiled program exceeds MaxPatternProgramSize
+	ErrPatternTooComplex = errors.New("pattern too complex")
+)
+
+// Pattern is a regular expression that passed the CompilePattern limits
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+// CompilePattern compiles a user-supplied regular expression, rejecting
+// patterns that are too long or compile to an oversized program.
+// All regexes taken from configuration or requests go through this helper.
+func CompilePattern(pattern string) (*Pattern, error) {
+	if len(pattern) > MaxPatternLength {
+		return nil, fmt.Errorf("%w: %d characters (max %d)", ErrPatternTooLong, len(pattern), MaxPatternLength)
+	}
+
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, err
+	}
+	if len(prog.Inst) > MaxPatternProgramSize {
+		return nil, fmt.Errorf("%w: compiles to %d instructions (max %d)", ErrPatternTooComplex, len(prog.Inst), MaxPatternProgramSize)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{re: re}, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics on error.
+// Only use it for patterns fixed in the source.
+func MustCompilePattern(pattern string) *Pattern {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("utils: CompilePattern(%q): %v", pattern, err))
+	}
+	return p
+}
+
+// MatchString reports whether s contains a match of the pattern.
+// Inputs longer than MaxPatternInputLength never match.
+func (p *Pattern) MatchString(s string) bool {
+	if len(s) > MaxPatternInputLength {
+		return false
+	}
+	return p.re.MatchString(s)
+}
+
+// String returns the source text of the pattern
+func (p *Pattern) String() string {
+	return p.re.String()
+}
+
+// ReplaceAllString replaces matches of the pattern in s with repl, expanding
+// $1-style references. Inputs longer than MaxPatternInputLength are returned unchanged.
+func (p *Pattern) ReplaceAllString(s, repl string) string {
+	if len(s) > MaxPatternInputLength {
+		return s
+	}
+	return p.re.ReplaceAllString(s, repl)
+}
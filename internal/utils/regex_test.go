@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		wantErr error
+	}{
+		{"default skip pattern", "^docker_|^builder_|^containerd", nil},
+		{"nested quantifiers", "^(a+)+$", nil},
+		{"too long", strings.Repeat("a", MaxPatternLength+1), ErrPatternTooLong},
+		{"nested counted repetition", "^(a{0,30}){0,30}$", ErrPatternTooComplex},
+		{"large character class repetition", "[a-z0-9]{1000}", ErrPatternTooComplex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompilePattern(tt.pattern)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CompilePattern(%q) returned error %v", tt.pattern, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompilePattern(%q) error = %v, want %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := CompilePattern("[invalid"); err == nil {
+		t.Error("CompilePattern accepted an invalid pattern")
+	}
+}
+
+func TestPatternMatchString(t *testing.T) {
+	p := MustCompilePattern("^docker_")
+
+	if !p.MatchString("docker_cache") {
+		t.Error("expected docker_cache to match")
+	}
+	if p.MatchString("app_data") {
+		t.Error("expected app_data not to match")
+	}
+	if p.MatchString("docker_" + strings.Repeat("x", MaxPatternInputLength)) {
+		t.Error("expected input over MaxPatternInputLength not to match")
+	}
+	if p.String() != "^docker_" {
+		t.Errorf("String() = %q", p.String())
+	}
+}
+
+// The classic catastrophic backtracking case must stay linear
+func TestPatternMatchString_PathologicalInputIsBounded(t *testing.T) {
+	p := MustCompilePattern("^(a+)+$")
+	input := strings.Repeat("a", MaxPatternInputLength-1) + "!"
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if p.MatchString(input) {
+			t.Fatal("expected no match")
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("10 matches of a pathological input took %v", elapsed)
+	}
+}
+
+func TestMustCompilePatternPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected MustCompilePattern to panic")
+		}
+	}()
+	MustCompilePattern(strings.Repeat("a", MaxPatternLength+1))
+}
@@ -23,11 +23,16 @@ const (
 	maxMessageSize = 512
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow connections from any origin
-		return true
-	},
+// newUpgrader returns the upgrader for a connection, negotiating
+// permessage-deflate when compression is enabled
+func newUpgrader(compression bool) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			// Allow connections from any origin
+			return true
+		},
+		EnableCompression: compression,
+	}
 }
 
 // Client is a middleman between the websocket connection and the hub.
@@ -164,11 +169,18 @@ func (c *Client) sendMessage(message Message) {
 
 // ServeWS handles websocket requests from the peer.
 func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+	compression, level := hub.compressionSettings()
+	conn, err := newUpgrader(compression).Upgrade(w, r, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
+	if compression {
+		// Only applies when the client negotiated compression
+		if err := conn.SetCompressionLevel(level); err != nil {
+			log.Printf("error setting compression level: %v", err)
+		}
+	}
 	client := &Client{hub: hub, conn: conn, send: make(chan []byte, 256)}
 	client.hub.register <- client
 
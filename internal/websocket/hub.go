@@ -1,7 +1,9 @@
 package websocket
 
 import (
+	"compress/flate"
 	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -27,6 +29,16 @@ type Hub struct {
 	// Message queue for offline clients (optional)
 	messageQueue []Message
 	maxQueueSize int
+
+	// Last scanned size per volume, for size_changed notifications
+	sizeMu              sync.Mutex
+	lastSizes           map[string]int64
+	sizeChangeThreshold int64 // Smallest absolute change in bytes that is broadcast
+
+	// permessage-deflate settings for new connections
+	compressionMu    sync.RWMutex
+	compression      bool
+	compressionLevel int
 }
 
 // NewHub creates a new WebSocket hub
@@ -38,6 +50,8 @@ func NewHub() *Hub {
 		clients:      make(map[*Client]bool),
 		messageQueue: make([]Message, 0),
 		maxQueueSize: 100,
+
+		compressionLevel: flate.BestSpeed,
 	}
 }
 
@@ -152,6 +166,81 @@ func (h *Hub) BroadcastScanError(volumeID string, errorMsg string, errorCode str
 	h.BroadcastMessage(message)
 }
 
+// SetSizeChangeThreshold sets the smallest absolute size change, in bytes, that
+// ReportVolumeSize broadcasts. Smaller changes are treated as noise.
+func (h *Hub) SetSizeChangeThreshold(threshold int64) {
+	h.sizeMu.Lock()
+	defer h.sizeMu.Unlock()
+	h.sizeChangeThreshold = threshold
+}
+
+// SetCompression enables permessage-deflate (RFC 7692) for new connections at
+// the given flate level. Compression is only used with clients that offer it
+// during the upgrade; other clients get uncompressed messages as before.
+func (h *Hub) SetCompression(enabled bool, level int) error {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		return fmt.Errorf("invalid compression level %d: must be between %d and %d", level, flate.HuffmanOnly, flate.BestCompression)
+	}
+
+	h.compressionMu.Lock()
+	defer h.compressionMu.Unlock()
+	h.compression = enabled
+	h.compressionLevel = level
+	return nil
+}
+
+// compressionSettings returns whether new connections negotiate compression and at which level
+func (h *Hub) compressionSettings() (bool, int) {
+	h.compressionMu.RLock()
+	defer h.compressionMu.RUnlock()
+	return h.compression, h.compressionLevel
+}
+
+// ReportVolumeSize records the size from a completed scan and broadcasts a
+// size_changed message when it differs from the previous scan by at least the
+// threshold. The first size seen for a volume only establishes the baseline.
+func (h *Hub) ReportVolumeSize(volumeID string, sizeBytes int64) {
+	h.sizeMu.Lock()
+	if h.lastSizes == nil {
+		h.lastSizes = make(map[string]int64)
+	}
+	oldSize, known := h.lastSizes[volumeID]
+	h.lastSizes[volumeID] = sizeBytes
+	threshold := h.sizeChangeThreshold
+	h.sizeMu.Unlock()
+
+	delta := sizeBytes - oldSize
+	if !known || delta == 0 || (delta < threshold && -delta < threshold) {
+		return
+	}
+
+	h.BroadcastSizeChanged(volumeID, oldSize, sizeBytes)
+}
+
+// VolumeRemoved forgets the last scanned size of a volume removed from
+// Docker, so a volume created again under its name starts a new baseline
+func (h *Hub) VolumeRemoved(volumeName string) {
+	h.sizeMu.Lock()
+	defer h.sizeMu.Unlock()
+	delete(h.lastSizes, volumeName)
+}
+
+// BroadcastSizeChanged broadcasts a change in a volume's scanned size
+func (h *Hub) BroadcastSizeChanged(volumeID string, oldSize, newSize int64) {
+	message := Message{
+		Type:     MessageTypeSizeChanged,
+		VolumeID: volumeID,
+		Data: SizeChangedData{
+			Volume:  volumeID,
+			OldSize: oldSize,
+			NewSize: newSize,
+			Delta:   newSize - oldSize,
+		},
+		Timestamp: time.Now(),
+	}
+	h.BroadcastMessage(message)
+}
+
 // GetClientCount returns the number of connected clients
 func (h *Hub) GetClientCount() int {
 	h.mu.RLock()
@@ -0,0 +1,210 @@
+package websocket
+
+import (
+	"compress/flate"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drainBroadcasts returns the messages queued for broadcast without running the hub
+func drainBroadcasts(t *testing.T, h *Hub) []Message {
+	var messages []Message
+	for {
+		select {
+		case data := <-h.broadcast:
+			var message Message
+			require.NoError(t, json.Unmarshal(data, &message))
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestReportVolumeSize_BroadcastsSignificantChanges(t *testing.T) {
+	hub := NewHub()
+	hub.SetSizeChangeThreshold(1024 * 1024)
+
+	// First scan only establishes the baseline
+	hub.ReportVolumeSize("data", 10*1024*1024)
+	assert.Empty(t, drainBroadcasts(t, hub))
+
+	// Trivial change below the threshold is suppressed
+	hub.ReportVolumeSize("data", 10*1024*1024+4096)
+	assert.Empty(t, drainBroadcasts(t, hub))
+
+	// Significant growth is broadcast against the last reported size
+	hub.ReportVolumeSize("data", 15*1024*1024)
+	messages := drainBroadcasts(t, hub)
+	require.Len(t, messages, 1)
+	assert.Equal(t, MessageTypeSizeChanged, messages[0].Type)
+	assert.Equal(t, "data", messages[0].VolumeID)
+	assert.Equal(t, map[string]any{
+		"volume":   "data",
+		"old_size": float64(10*1024*1024 + 4096),
+		"new_size": float64(15 * 1024 * 1024),
+		"delta":    float64(5*1024*1024 - 4096),
+	}, messages[0].Data)
+
+	// Shrinking counts too
+	hub.ReportVolumeSize("data", 1024)
+	messages = drainBroadcasts(t, hub)
+	require.Len(t, messages, 1)
+	assert.Equal(t, float64(1024-15*1024*1024), messages[0].Data.(map[string]any)["delta"])
+
+	// Sizes are tracked per volume
+	hub.ReportVolumeSize("logs", 50*1024*1024)
+	assert.Empty(t, drainBroadcasts(t, hub))
+
+	// A removed volume's size is forgotten; recreated, it starts a new baseline
+	hub.VolumeRemoved("data")
+	assert.NotContains(t, hub.lastSizes, "data")
+	hub.ReportVolumeSize("data", 20*1024*1024)
+	assert.Empty(t, drainBroadcasts(t, hub))
+}
+
+// countingConn counts the bytes read from the underlying connection
+type countingConn struct {
+	net.Conn
+	read *atomic.Int64
+}
+
+func (c countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// dialHub connects a client to a hub served over HTTP. It returns the
+// negotiated extensions and a counter of the bytes the client has read.
+func dialHub(t *testing.T, hub *Hub, offerCompression bool) (*websocket.Conn, string, *atomic.Int64) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWS(hub, w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	read := &atomic.Int64{}
+	dialer := websocket.Dialer{
+		EnableCompression: offerCompression,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return countingConn{Conn: conn, read: read}, nil
+		},
+	}
+
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	// Wait until the hub has registered the client before broadcasting
+	require.Eventually(t, func() bool { return hub.GetClientCount() > 0 }, time.Second, time.Millisecond)
+
+	return conn, resp.Header.Get("Sec-WebSocket-Extensions"), read
+}
+
+// sampleVolumes returns a volume_update payload typical of a busy host,
+// numbering volumes from first so successive payloads differ
+func sampleVolumes(first, count int) []VolumeData {
+	volumes := make([]VolumeData, count)
+	for i := range volumes {
+		name := fmt.Sprintf("project_%d_data", first+i)
+		volumes[i] = VolumeData{
+			ID:         name,
+			Name:       name,
+			Driver:     "local",
+			Mountpoint: "/var/lib/docker/volumes/" + name + "/_data",
+			CreatedAt:  time.Date(2025, 7, 1, 12, 0, first+i, 0, time.UTC),
+		}
+	}
+	return volumes
+}
+
+func TestServeWS_NegotiatesCompression(t *testing.T) {
+	tests := []struct {
+		name             string
+		hubCompression   bool
+		offerCompression bool
+		wantCompression  bool
+	}{
+		{name: "enabled and offered", hubCompression: true, offerCompression: true, wantCompression: true},
+		{name: "enabled, client without compression", hubCompression: true, offerCompression: false},
+		{name: "disabled", hubCompression: false, offerCompression: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub()
+			require.NoError(t, hub.SetCompression(tt.hubCompression, flate.BestSpeed))
+			go hub.Run()
+
+			conn, extensions, _ := dialHub(t, hub, tt.offerCompression)
+			assert.Equal(t, tt.wantCompression, strings.Contains(extensions, "permessage-deflate"), extensions)
+
+			// Messages arrive intact either way
+			hub.BroadcastVolumeUpdate(sampleVolumes(0, 3))
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+			_, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+
+			var message Message
+			require.NoError(t, json.Unmarshal(data, &message))
+			assert.Equal(t, MessageTypeVolumeUpdate, message.Type)
+		})
+	}
+}
+
+func TestServeWS_CompressionReducesBandwidth(t *testing.T) {
+	received := func(offerCompression bool) int64 {
+		hub := NewHub()
+		require.NoError(t, hub.SetCompression(true, flate.BestSpeed))
+		go hub.Run()
+
+		conn, _, read := dialHub(t, hub, offerCompression)
+		before := read.Load()
+
+		const messages = 20
+		for i := 0; i < messages; i++ {
+			hub.BroadcastVolumeUpdate(sampleVolumes(i*50, 50))
+		}
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		for count := 0; count < messages; {
+			_, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+			// Queued messages may be coalesced into one frame, one per line
+			count += strings.Count(string(data), "\n") + 1
+		}
+		return read.Load() - before
+	}
+
+	plain := received(false)
+	compressed := received(true)
+	t.Logf("20 volume_update messages of 50 volumes: %d bytes uncompressed, %d bytes compressed (%.0f%% smaller)",
+		plain, compressed, 100*(1-float64(compressed)/float64(plain)))
+	assert.Less(t, compressed, plain/2)
+}
+
+func TestSetCompression_RejectsInvalidLevel(t *testing.T) {
+	hub := NewHub()
+	assert.Error(t, hub.SetCompression(true, 10))
+	assert.Error(t, hub.SetCompression(true, -3))
+
+	// The previous settings are kept
+	enabled, level := hub.compressionSettings()
+	assert.False(t, enabled)
+	assert.Equal(t, flate.BestSpeed, level)
+}
@@ -17,6 +17,7 @@ const (
 	MessageTypeScanProgress MessageType = "scan_progress"
 	MessageTypeScanComplete MessageType = "scan_complete"
 	MessageTypeScanError    MessageType = "scan_error"
+	MessageTypeSizeChanged  MessageType = "size_changed"
 )
 
 // Message represents a WebSocket message
@@ -64,3 +65,11 @@ type ScanErrorData struct {
 	Error string `json:"error"`
 	Code  string `json:"code"`
 }
+
+// SizeChangedData represents a significant change in a volume's scanned size
+type SizeChangedData struct {
+	Volume  string `json:"volume"`
+	OldSize int64  `json:"old_size"`
+	NewSize int64  `json:"new_size"`
+	Delta   int64  `json:"delta"`
+}
@@ -9,6 +9,7 @@ import (
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
 )
@@ -75,6 +76,24 @@ func (c *Client) Version(ctx context.Context) (types.Version, error) {
 	return version, nil
 }
 
+// ClientVersion returns the Docker API version the client speaks, as
+// negotiated with the daemon on the client's first request
+func (c *Client) ClientVersion() string {
+	return c.cli.ClientVersion()
+}
+
+// Info returns Docker daemon information, including its Swarm node
+func (c *Client) Info(ctx context.Context) (system.Info, error) {
+	ctx, cancel := c.contextWithTimeout(ctx)
+	defer cancel()
+
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		return system.Info{}, fmt.Errorf("failed to get Docker info: %w", err)
+	}
+	return info, nil
+}
+
 // ListVolumes lists all Docker volumes
 func (c *Client) ListVolumes(ctx context.Context, filterMap map[string][]string) (volume.ListResponse, error) {
 	ctx, cancel := c.contextWithTimeout(ctx)
@@ -110,6 +129,18 @@ func (c *Client) InspectVolume(ctx context.Context, volumeID string) (volume.Vol
 	return vol, nil
 }
 
+// RemoveVolume removes a volume. Docker refuses to remove volumes that are in
+// use unless force is set.
+func (c *Client) RemoveVolume(ctx context.Context, volumeID string, force bool) error {
+	ctx, cancel := c.contextWithTimeout(ctx)
+	defer cancel()
+
+	if err := c.cli.VolumeRemove(ctx, volumeID, force); err != nil {
+		return fmt.Errorf("failed to remove volume %s: %w", volumeID, err)
+	}
+	return nil
+}
+
 // ListContainers lists all containers with optional filters
 func (c *Client) ListContainers(ctx context.Context, filterMap map[string][]string) ([]containertypes.Summary, error) {
 	ctx, cancel := c.contextWithTimeout(ctx)
@@ -308,12 +308,16 @@ func testPerformanceEndpoints(t *testing.T, router *gin.Engine) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var slowQueries []database.SlowQueryInfo
+		var slowQueries database.SlowQueriesResponse
 		err := json.Unmarshal(w.Body.Bytes(), &slowQueries)
 		require.NoError(t, err)
 
-		// pg_stat_statements might not be available, so empty result is acceptable
-		for _, query := range slowQueries {
+		// pg_stat_statements might not be available; then the response says why
+		require.NotNil(t, slowQueries.QueryStats)
+		if !slowQueries.QueryStats.Enabled() {
+			assert.NotEmpty(t, slowQueries.Message)
+		}
+		for _, query := range slowQueries.Queries {
 			assert.NotEmpty(t, query.Query)
 			assert.GreaterOrEqual(t, query.Calls, int64(0))
 			assert.GreaterOrEqual(t, query.TotalTime, 0.0)
@@ -328,12 +332,12 @@ func testPerformanceEndpoints(t *testing.T, router *gin.Engine) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var slowQueries []database.SlowQueryInfo
+		var slowQueries database.SlowQueriesResponse
 		err := json.Unmarshal(w.Body.Bytes(), &slowQueries)
 		require.NoError(t, err)
 
 		// Should respect limit parameter
-		assert.LessOrEqual(t, len(slowQueries), 5)
+		assert.LessOrEqual(t, len(slowQueries.Queries), 5)
 	})
 }
 
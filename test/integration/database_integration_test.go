@@ -458,6 +458,43 @@ func testDatabasePerformance(t *testing.T, db *database.DB) {
 		// Performance assertions (adjust based on acceptable performance)
 		assert.Less(t, duration, 30*time.Second, "Bulk insert should complete within 30 seconds")
 		assert.Greater(t, ratePerSecond, 10.0, "Should insert at least 10 records per second")
+
+		// The same volumes once more, written with multi-row statements in
+		// batches of the reconciler's default EVENTS_RECONCILE_BATCH_SIZE
+		const batchSize = 500
+		now := time.Now()
+		volumes := make([]*database.Volume, numRecords)
+		for i := range volumes {
+			volumes[i] = &database.Volume{
+				VolumeID:   fmt.Sprintf("perf_batch_vol_%04d", i),
+				Name:       fmt.Sprintf("performance-batch-volume-%d", i),
+				Driver:     "local",
+				Mountpoint: fmt.Sprintf("/test/perf/batch/%d", i),
+				Labels:     database.Labels{"test": "performance", "batch": "bulk_upsert"},
+				Options:    database.Labels{},
+				Scope:      "local",
+				Status:     "active",
+				IsActive:   true,
+				BaseModel:  database.BaseModel{CreatedAt: now, UpdatedAt: now},
+			}
+		}
+
+		eventRepo := database.NewEventRepository(db)
+		upserted := 0
+		start = time.Now()
+		for from := 0; from < len(volumes); from += batchSize {
+			result, err := eventRepo.BulkUpsertVolumes(context.Background(), volumes[from:min(from+batchSize, len(volumes))])
+			require.NoError(t, err)
+			require.Empty(t, result.Failed)
+			upserted += result.Upserted
+		}
+		batchDuration := time.Since(start)
+		batchRatePerSecond := float64(numRecords) / batchDuration.Seconds()
+
+		t.Logf("Batch inserted %d records in %v (%.2f records/sec, %.1fx serial)",
+			numRecords, batchDuration, batchRatePerSecond, batchRatePerSecond/ratePerSecond)
+		assert.Equal(t, numRecords, upserted)
+		assert.Less(t, batchDuration, duration, "Batch insert should be faster than inserting one volume at a time")
 	})
 
 	// Test query performance